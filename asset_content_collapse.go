@@ -48,7 +48,9 @@ type voxelShadowSettings struct {
 }
 
 func trySpawnCollapsedAuthoredAsset(cmd *Commands, assets *AssetServer, def *content.AssetDef, rootTransform TransformComponent, opts AuthoredAssetSpawnOptions, result *AuthoredAssetSpawnResult) (bool, error) {
-	enabled := def != nil && def.Runtime != nil && def.Runtime.CollapseVoxelParts
+	// Skeletal assets keep their bone parts unless collapse is forced, which
+	// bakes the bind pose.
+	enabled := def != nil && def.Runtime != nil && def.Runtime.CollapseVoxelParts && def.Skeleton == nil
 	switch opts.CollapseVoxelParts {
 	case VoxelPartCollapseDisable:
 		return false, nil
@@ -135,6 +135,13 @@ func SpawnAuthoredAssetWithOptions(cmd *Commands, assets *AssetServer, def *cont
 			return result, err
 		}
 	}
+	if skeleton := voxelSkeletonFromAssetDef(def, result.EntitiesByAssetID); skeleton != nil {
+		player := &AnimationPlayerComponent{}
+		if len(skeleton.Clips) > 0 {
+			player.Clip = skeleton.Clips[0].Name
+		}
+		cmd.AddComponents(result.RootEntity, skeleton, player)
+	}
 	cmd.app.FlushCommands()
 
 	TransformHierarchySystem(cmd)
@@ -276,6 +283,10 @@ func modelAndPaletteFromSource(assets *AssetServer, def *content.AssetDef, part
 			model = assets.CreateCapsuleModel(params["radius"], params["height"], part.ModelScale)
 		case "ramp":
 			model = assets.CreateRampModel(params["sx"], params["sy"], params["sz"], part.ModelScale)
+		case "torus":
+			model = assets.CreateTorusModel(params["major_radius"], params["minor_radius"], part.ModelScale)
+		case "rounded_box":
+			model = assets.CreateRoundedBoxModel(params["sx"], params["sy"], params["sz"], params["radius"], part.ModelScale)
 		default:
 			return AssetId{}, AssetId{}, fmt.Errorf("unsupported procedural primitive %q", part.Source.Primitive)
 		}
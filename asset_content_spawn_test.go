@@ -1591,3 +1591,29 @@ func writeInt32ForVoxFixture(t *testing.T, buf *bytes.Buffer, value int32) {
 		t.Fatalf("binary.Write failed: %v", err)
 	}
 }
+
+func TestCreateSDFPrimitiveModelsProduceExpectedShapes(t *testing.T) {
+	assets := newSpawnTestAssetServer()
+
+	torus, ok := assets.GetVoxelGeometry(assets.CreateTorusModel(6, 2, 1))
+	if !ok || torus.XBrickMap == nil {
+		t.Fatal("expected torus geometry")
+	}
+	if found, _ := torus.XBrickMap.GetVoxel(8, 1, 8); found {
+		t.Fatal("expected torus hole at the center to be empty")
+	}
+	if found, _ := torus.XBrickMap.GetVoxel(14, 1, 8); !found {
+		t.Fatal("expected torus ring voxel at major radius")
+	}
+
+	box, ok := assets.GetVoxelGeometry(assets.CreateRoundedBoxModel(10, 10, 10, 3, 1))
+	if !ok || box.XBrickMap == nil {
+		t.Fatal("expected rounded box geometry")
+	}
+	if found, _ := box.XBrickMap.GetVoxel(0, 0, 0); found {
+		t.Fatal("expected rounded box corner to be trimmed")
+	}
+	if found, _ := box.XBrickMap.GetVoxel(0, 5, 5); !found {
+		t.Fatal("expected rounded box face center to be filled")
+	}
+}
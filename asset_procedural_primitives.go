@@ -1,6 +1,11 @@
 package gekko
 
-import "math"
+import (
+	"math"
+
+	"github.com/gekko3d/gekko/voxelrt/rt/volume"
+	"github.com/go-gl/mathgl/mgl32"
+)
 
 func (server *AssetServer) CreateSphereModel(radius float32, resolution float32) AssetId {
 	scaledRadius := radius * resolution
@@ -286,3 +291,55 @@ func (server *AssetServer) CreateRampModel(sizeX, sizeY, sizeZ float32, resoluti
 		Voxels: voxels,
 	}, 1.0)
 }
+
+// CreateTorusModel creates a torus lying in the local XZ plane.
+func (server *AssetServer) CreateTorusModel(majorRadius, minorRadius float32, resolution float32) AssetId {
+	major := majorRadius * resolution
+	minor := minorRadius * resolution
+	outer := int(math.Ceil(float64(major + minor)))
+	thickness := int(math.Ceil(float64(minor)))
+	return server.CreateVoxelGeometry(sdfVoxModel(volume.SDFTorus{
+		Center:      mgl32.Vec3{float32(outer), float32(thickness), float32(outer)},
+		MajorRadius: major,
+		MinorRadius: minor,
+	}, [3]int{outer * 2, thickness * 2, outer * 2}), 1.0)
+}
+
+// CreateRoundedBoxModel creates a box of the given size whose edges are
+// rounded by radius.
+func (server *AssetServer) CreateRoundedBoxModel(sizeX, sizeY, sizeZ, radius float32, resolution float32) AssetId {
+	sx, sy, sz := int(sizeX*resolution), int(sizeY*resolution), int(sizeZ*resolution)
+	if sx <= 0 || sy <= 0 || sz <= 0 {
+		return server.CreateVoxelGeometry(VoxModel{}, 1.0)
+	}
+	half := mgl32.Vec3{float32(sx) * 0.5, float32(sy) * 0.5, float32(sz) * 0.5}
+	r := radius * resolution
+	r = min(r, half.X(), half.Y(), half.Z())
+	return server.CreateVoxelGeometry(sdfVoxModel(volume.SDFRoundedBox{
+		Center:      half,
+		HalfExtents: half,
+		Radius:      r,
+	}, [3]int{sx, sy, sz}), 1.0)
+}
+
+// sdfVoxModel samples shape at voxel centers inside [0,size).
+func sdfVoxModel(shape volume.SDF, size [3]int) VoxModel {
+	voxels := []Voxel{}
+	for x := 0; x < size[0]; x++ {
+		for y := 0; y < size[1]; y++ {
+			for z := 0; z < size[2]; z++ {
+				p := mgl32.Vec3{float32(x) + 0.5, float32(y) + 0.5, float32(z) + 0.5}
+				if shape.Distance(p) <= 0 {
+					voxels = append(voxels, Voxel{
+						X: uint32(x), Y: uint32(y), Z: uint32(z),
+						ColorIndex: 1,
+					})
+				}
+			}
+		}
+	}
+	return VoxModel{
+		SizeX: uint32(size[0]), SizeY: uint32(size[1]), SizeZ: uint32(size[2]),
+		Voxels: voxels,
+	}
+}
@@ -62,6 +62,40 @@ func (server *AssetServer) CreateTextureFromTexels(texels []uint8, texWidth uint
 	return id
 }
 
+// CreateRenderTargetTexture creates a texture that a RenderViewComponent
+// draws into. It has no CPU texels; sprites can display it.
+func (server *AssetServer) CreateRenderTargetTexture(width, height uint32) AssetId {
+	id := makeAssetId()
+
+	server.mu.Lock()
+	server.textures[id] = TextureAsset{
+		Version:      0,
+		Width:        max(width, 1),
+		Height:       max(height, 1),
+		Depth:        1,
+		Dimension:    TextureDimension2D,
+		Format:       TextureFormatRGBA8UnormSrgb,
+		RenderTarget: true,
+	}
+	server.mu.Unlock()
+
+	return id
+}
+
+// TextureSize returns the pixel size of a texture asset.
+func (server *AssetServer) TextureSize(id AssetId) (uint32, uint32, bool) {
+	if server == nil {
+		return 0, 0, false
+	}
+	server.mu.RLock()
+	defer server.mu.RUnlock()
+	texture, ok := server.textures[id]
+	if !ok || texture.Width == 0 || texture.Height == 0 {
+		return 0, 0, false
+	}
+	return texture.Width, texture.Height, true
+}
+
 func (server *AssetServer) CreateTexture(filename string) AssetId {
 	id := makeAssetId()
 
@@ -2,6 +2,7 @@ package gekko
 
 import (
 	"encoding/json"
+	"fmt"
 	"math"
 
 	"github.com/gekko3d/gekko/voxelrt/rt/volume"
@@ -202,6 +203,26 @@ func (server *AssetServer) CloneVoxelGeometry(id AssetId) (AssetId, bool) {
 	return server.RegisterSharedVoxelGeometry(asset.XBrickMap, asset.SourcePath), true
 }
 
+// SaveVoxelGeometryBinary writes the geometry's XBrickMap in the binary
+// XBrickMap format so runtime-owned geometry can be persisted compactly.
+func (server *AssetServer) SaveVoxelGeometryBinary(id AssetId, path string) error {
+	asset, ok := server.GetVoxelGeometry(id)
+	if !ok || asset.XBrickMap == nil {
+		return fmt.Errorf("voxel geometry %v not found", id)
+	}
+	return SaveVoxelObjectSnapshotXBrickMap(path, asset.XBrickMap)
+}
+
+// LoadVoxelGeometryBinary registers runtime-owned geometry from a binary
+// XBrickMap file, falling back to legacy JSON voxel object snapshots.
+func (server *AssetServer) LoadVoxelGeometryBinary(path string) (AssetId, error) {
+	xbm, err := LoadVoxelObjectSnapshotXBrickMap(path)
+	if err != nil {
+		return AssetId{}, err
+	}
+	return server.RegisterSharedVoxelGeometry(xbm, path), nil
+}
+
 func (server *AssetServer) DeleteVoxelGeometry(id AssetId) bool {
 	if server == nil || id == (AssetId{}) {
 		return false
@@ -143,7 +143,7 @@ func TestEntityLODImpostorTextureCachesAndProducesOpaquePixels(t *testing.T) {
 		t.Fatal("expected source geometry")
 	}
 
-	textureID, ok := server.entityLODImpostorTexture(geometryID, paletteID, &source)
+	textureID, ok := awaitEntityLODImpostorTexture(t, server, geometryID, paletteID, &source)
 	if !ok || textureID == (AssetId{}) {
 		t.Fatal("expected impostor texture")
 	}
@@ -154,8 +154,9 @@ func TestEntityLODImpostorTextureCachesAndProducesOpaquePixels(t *testing.T) {
 	if tex.Format != TextureFormatRGBA8UnormSrgb {
 		t.Fatalf("expected impostor texture format %v, got %v", TextureFormatRGBA8UnormSrgb, tex.Format)
 	}
-	if tex.Width != entityLODImpostorTextureSize || tex.Height != entityLODImpostorTextureSize {
-		t.Fatalf("expected %dx%d impostor texture, got %dx%d", entityLODImpostorTextureSize, entityLODImpostorTextureSize, tex.Width, tex.Height)
+	wantWidth := uint32(entityLODImpostorViewGrid * entityLODImpostorCellSize)
+	if tex.Width != wantWidth || tex.Height != 2*wantWidth {
+		t.Fatalf("expected %dx%d octahedral impostor atlas, got %dx%d", wantWidth, 2*wantWidth, tex.Width, tex.Height)
 	}
 	opaquePixels := 0
 	for i := 3; i < len(tex.Texels); i += 4 {
@@ -167,7 +168,7 @@ func TestEntityLODImpostorTextureCachesAndProducesOpaquePixels(t *testing.T) {
 		t.Fatal("expected impostor texture to contain visible pixels")
 	}
 
-	textureID2, ok := server.entityLODImpostorTexture(geometryID, paletteID, &source)
+	textureID2, ok := awaitEntityLODImpostorTexture(t, server, geometryID, paletteID, &source)
 	if !ok {
 		t.Fatal("expected impostor cache lookup")
 	}
@@ -205,7 +206,7 @@ func TestEntityLODImpostorTextureSkipsFullyTransparentFrontVoxels(t *testing.T)
 		t.Fatal("expected source geometry")
 	}
 
-	textureID, ok := server.entityLODImpostorTexture(modelID, paletteID, &source)
+	textureID, ok := awaitEntityLODImpostorTexture(t, server, modelID, paletteID, &source)
 	if !ok || textureID == (AssetId{}) {
 		t.Fatal("expected impostor texture to be generated from opaque voxel behind transparent front voxel")
 	}
@@ -215,7 +216,8 @@ func TestEntityLODImpostorTextureSkipsFullyTransparentFrontVoxels(t *testing.T)
 	}
 
 	opaquePixels := 0
-	for i := 0; i < len(tex.Texels); i += 4 {
+	colorTexels := tex.Texels[:len(tex.Texels)/2]
+	for i := 0; i < len(colorTexels); i += 4 {
 		if tex.Texels[i+3] == 0 {
 			continue
 		}
@@ -256,7 +258,7 @@ func TestEntityLODImpostorTextureUsesOpaqueReadableCoverage(t *testing.T) {
 		t.Fatal("expected source geometry")
 	}
 
-	textureID, ok := server.entityLODImpostorTexture(modelID, paletteID, &source)
+	textureID, ok := awaitEntityLODImpostorTexture(t, server, modelID, paletteID, &source)
 	if !ok || textureID == (AssetId{}) {
 		t.Fatal("expected impostor texture")
 	}
@@ -266,7 +268,8 @@ func TestEntityLODImpostorTextureUsesOpaqueReadableCoverage(t *testing.T) {
 	}
 
 	filled := 0
-	for i := 0; i < len(tex.Texels); i += 4 {
+	colorTexels := tex.Texels[:len(tex.Texels)/2]
+	for i := 0; i < len(colorTexels); i += 4 {
 		if tex.Texels[i+3] == 0 {
 			continue
 		}
@@ -23,6 +23,9 @@ type TextureAsset struct {
 	Depth     uint32
 	Dimension TextureDimension
 	Format    TextureFormat
+	// RenderTarget textures have no texels; a render view draws into them on
+	// the GPU.
+	RenderTarget bool
 }
 
 type SamplerAsset struct {
@@ -0,0 +1,207 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/ebitengine/oto/v3"
+)
+
+// Backend receives mixed interleaved stereo frames. The mixer pushes one
+// block per frame; device backends buffer internally and drain on their own
+// audio thread.
+type Backend interface {
+	Write(samples []float32) error
+	Close() error
+}
+
+// NullBackend discards audio and counts the frames it was given.
+type NullBackend struct {
+	Frames int
+}
+
+func (b *NullBackend) Write(samples []float32) error {
+	b.Frames += len(samples) / 2
+	return nil
+}
+
+func (b *NullBackend) Close() error { return nil }
+
+const (
+	// deviceFrameBytes is one interleaved stereo float32 frame.
+	deviceFrameBytes = 8
+	// deviceMaxLatencyMs caps the queued backlog; older frames are dropped
+	// when the device falls behind.
+	deviceMaxLatencyMs = 200
+	// devicePlayerBufferMs is how far ahead the device thread reads.
+	devicePlayerBufferMs = 40
+)
+
+var (
+	deviceContextOnce sync.Once
+	deviceContext     *oto.Context
+	deviceContextRate int
+	deviceContextErr  error
+)
+
+// DeviceBackend plays the mix on the default output device. Write queues
+// frames and the device thread drains them, playing silence when the queue
+// runs dry.
+type DeviceBackend struct {
+	player   *oto.Player
+	maxBytes int
+
+	mu      sync.Mutex
+	pending []byte
+	closed  bool
+}
+
+// NewDeviceBackend opens the default output device at sampleRate. A process
+// has a single device context, so later backends must use the first one's
+// sample rate.
+func NewDeviceBackend(sampleRate int) (*DeviceBackend, error) {
+	if sampleRate <= 0 {
+		sampleRate = DefaultSampleRate
+	}
+	ctx, err := openDeviceContext(sampleRate)
+	if err != nil {
+		return nil, err
+	}
+	b := newDeviceQueue(sampleRate)
+	b.player = ctx.NewPlayer(b)
+	b.player.SetBufferSize(sampleRate * devicePlayerBufferMs / 1000 * deviceFrameBytes)
+	b.player.Play()
+	return b, nil
+}
+
+func newDeviceQueue(sampleRate int) *DeviceBackend {
+	return &DeviceBackend{maxBytes: sampleRate * deviceMaxLatencyMs / 1000 * deviceFrameBytes}
+}
+
+func openDeviceContext(sampleRate int) (*oto.Context, error) {
+	deviceContextOnce.Do(func() {
+		ctx, ready, err := oto.NewContext(&oto.NewContextOptions{
+			SampleRate:   sampleRate,
+			ChannelCount: 2,
+			Format:       oto.FormatFloat32LE,
+		})
+		if err != nil {
+			deviceContextErr = err
+			return
+		}
+		<-ready
+		if err := ctx.Err(); err != nil {
+			deviceContextErr = err
+			return
+		}
+		deviceContext, deviceContextRate = ctx, sampleRate
+	})
+	if deviceContextErr != nil {
+		return nil, deviceContextErr
+	}
+	if deviceContextRate != sampleRate {
+		return nil, fmt.Errorf("audio device is open at %d Hz, not %d Hz", deviceContextRate, sampleRate)
+	}
+	return deviceContext, nil
+}
+
+func (b *DeviceBackend) Write(samples []float32) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return io.ErrClosedPipe
+	}
+	for _, sample := range samples {
+		b.pending = binary.LittleEndian.AppendUint32(b.pending, math.Float32bits(sample))
+	}
+	if over := len(b.pending) - b.maxBytes; over > 0 {
+		over = (over + deviceFrameBytes - 1) / deviceFrameBytes * deviceFrameBytes
+		b.pending = b.pending[:copy(b.pending, b.pending[over:])]
+	}
+	return nil
+}
+
+// Read feeds the device thread whole frames from the queue and pads the rest
+// of p with silence.
+func (b *DeviceBackend) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return 0, io.EOF
+	}
+	n := min(len(p), len(b.pending)) / deviceFrameBytes * deviceFrameBytes
+	copy(p, b.pending[:n])
+	b.pending = b.pending[:copy(b.pending, b.pending[n:])]
+	clear(p[n:])
+	return len(p), nil
+}
+
+func (b *DeviceBackend) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.pending = nil
+	b.mu.Unlock()
+	if b.player == nil {
+		return nil
+	}
+	return b.player.Close()
+}
+
+// WAVWriterBackend records everything written so headless runs and tests can
+// inspect the mix or save it as a WAV file.
+type WAVWriterBackend struct {
+	SampleRate int
+
+	mu      sync.Mutex
+	samples []float32
+}
+
+func NewWAVWriterBackend(sampleRate int) *WAVWriterBackend {
+	return &WAVWriterBackend{SampleRate: sampleRate}
+}
+
+func (b *WAVWriterBackend) Write(samples []float32) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.samples = append(b.samples, samples...)
+	return nil
+}
+
+func (b *WAVWriterBackend) Close() error { return nil }
+
+// Samples returns a copy of the recorded interleaved stereo samples.
+func (b *WAVWriterBackend) Samples() []float32 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]float32(nil), b.samples...)
+}
+
+// WriteTo encodes the recording as a 16-bit stereo WAV.
+func (b *WAVWriterBackend) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	if err := EncodeWAV(&buf, b.SampleRate, 2, b.Samples()); err != nil {
+		return 0, err
+	}
+	return buf.WriteTo(w)
+}
+
+func (b *WAVWriterBackend) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := b.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
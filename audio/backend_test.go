@@ -0,0 +1,61 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestDeviceBackendQueuesFramesAndPadsWithSilence(t *testing.T) {
+	b := newDeviceQueue(1000)
+	if err := b.Write([]float32{0.25, -0.5}); err != nil {
+		t.Fatal(err)
+	}
+	p := make([]byte, 4*deviceFrameBytes)
+	for i := range p {
+		p[i] = 0xff
+	}
+	if n, err := b.Read(p); n != len(p) || err != nil {
+		t.Fatalf("Read = %d, %v; want a full buffer", n, err)
+	}
+	sample := func(i int) float32 { return math.Float32frombits(binary.LittleEndian.Uint32(p[i*4:])) }
+	if sample(0) != 0.25 || sample(1) != -0.5 {
+		t.Fatalf("expected the queued frame first, got %v %v", sample(0), sample(1))
+	}
+	for i := 2; i < 8; i++ {
+		if sample(i) != 0 {
+			t.Fatalf("expected silence after the queue ran dry, sample %d = %v", i, sample(i))
+		}
+	}
+}
+
+func TestDeviceBackendDropsOldestFramesPastMaxLatency(t *testing.T) {
+	b := newDeviceQueue(1000)
+	maxFrames := b.maxBytes / deviceFrameBytes
+	samples := make([]float32, 2*(maxFrames+10))
+	for i := range samples {
+		samples[i] = float32(i / 2)
+	}
+	if err := b.Write(samples); err != nil {
+		t.Fatal(err)
+	}
+	if len(b.pending) != b.maxBytes {
+		t.Fatalf("queued %d bytes, want the %d byte cap", len(b.pending), b.maxBytes)
+	}
+	p := make([]byte, deviceFrameBytes)
+	b.Read(p)
+	if got := math.Float32frombits(binary.LittleEndian.Uint32(p)); got != 10 {
+		t.Fatalf("expected the oldest 10 frames to be dropped, first frame is %v", got)
+	}
+
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Read(p); err != io.EOF {
+		t.Fatalf("Read after Close = %v, want EOF", err)
+	}
+	if err := b.Write(samples[:2]); err == nil {
+		t.Fatal("expected Write after Close to fail")
+	}
+}
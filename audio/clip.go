@@ -0,0 +1,230 @@
+package audio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfreymuth/oggvorbis"
+)
+
+// Clip is decoded audio held in memory as interleaved float samples in
+// -1..1. The mixer plays mono and stereo clips.
+type Clip struct {
+	SampleRate int
+	Channels   int
+	Samples    []float32
+}
+
+// Frames is the number of sample frames (samples per channel).
+func (c *Clip) Frames() int {
+	if c == nil || c.Channels <= 0 {
+		return 0
+	}
+	return len(c.Samples) / c.Channels
+}
+
+func (c *Clip) Duration() float64 {
+	if c == nil || c.SampleRate <= 0 {
+		return 0
+	}
+	return float64(c.Frames()) / float64(c.SampleRate)
+}
+
+// LoadClip decodes a WAV or OGG Vorbis file, choosing by extension and
+// falling back to the file signature.
+func LoadClip(path string) (*Clip, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".wav":
+		return DecodeWAV(r)
+	case ".ogg", ".oga":
+		return DecodeOGG(r)
+	}
+	magic, _ := r.Peek(4)
+	switch string(magic) {
+	case "RIFF":
+		return DecodeWAV(r)
+	case "OggS":
+		return DecodeOGG(r)
+	}
+	return nil, fmt.Errorf("%s: unrecognized audio format", path)
+}
+
+func DecodeOGG(r io.Reader) (*Clip, error) {
+	samples, format, err := oggvorbis.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("ogg: %w", err)
+	}
+	clip := &Clip{SampleRate: format.SampleRate, Channels: format.Channels, Samples: samples}
+	if err := clip.validate(); err != nil {
+		return nil, fmt.Errorf("ogg: %w", err)
+	}
+	return clip, nil
+}
+
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xFFFE
+)
+
+// DecodeWAV reads RIFF WAVE files with 8/16/24/32-bit integer PCM or 32-bit
+// float samples. Chunks other than fmt and data are skipped.
+func DecodeWAV(r io.Reader) (*Clip, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, fmt.Errorf("wav: %w", err)
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("wav: missing RIFF/WAVE header")
+	}
+
+	var format, channels, bits int
+	var sampleRate int
+	haveFormat := false
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil, fmt.Errorf("wav: no data chunk")
+			}
+			return nil, fmt.Errorf("wav: %w", err)
+		}
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, fmt.Errorf("wav: fmt chunk too small")
+			}
+			data := make([]byte, size)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, fmt.Errorf("wav: %w", err)
+			}
+			format = int(binary.LittleEndian.Uint16(data[0:2]))
+			channels = int(binary.LittleEndian.Uint16(data[2:4]))
+			sampleRate = int(binary.LittleEndian.Uint32(data[4:8]))
+			bits = int(binary.LittleEndian.Uint16(data[14:16]))
+			if format == wavFormatExtensible && size >= 26 {
+				format = int(binary.LittleEndian.Uint16(data[24:26]))
+			}
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return nil, fmt.Errorf("wav: data chunk before fmt chunk")
+			}
+			data, err := io.ReadAll(io.LimitReader(r, size))
+			if err != nil {
+				return nil, fmt.Errorf("wav: %w", err)
+			}
+			samples, err := decodeWAVSamples(data, format, bits)
+			if err != nil {
+				return nil, err
+			}
+			clip := &Clip{SampleRate: sampleRate, Channels: channels, Samples: samples}
+			if err := clip.validate(); err != nil {
+				return nil, fmt.Errorf("wav: %w", err)
+			}
+			// Drop a trailing partial frame from truncated files.
+			clip.Samples = clip.Samples[:clip.Frames()*clip.Channels]
+			return clip, nil
+		default:
+			size += size & 1
+			if _, err := io.CopyN(io.Discard, r, size); err != nil {
+				return nil, fmt.Errorf("wav: %w", err)
+			}
+			continue
+		}
+		if size&1 == 1 {
+			if _, err := io.CopyN(io.Discard, r, 1); err != nil {
+				return nil, fmt.Errorf("wav: %w", err)
+			}
+		}
+	}
+}
+
+func decodeWAVSamples(data []byte, format, bits int) ([]float32, error) {
+	switch {
+	case format == wavFormatPCM && bits == 8:
+		out := make([]float32, len(data))
+		for i, b := range data {
+			out[i] = (float32(b) - 128) / 128
+		}
+		return out, nil
+	case format == wavFormatPCM && bits == 16:
+		out := make([]float32, len(data)/2)
+		for i := range out {
+			out[i] = float32(int16(binary.LittleEndian.Uint16(data[i*2:]))) / 32768
+		}
+		return out, nil
+	case format == wavFormatPCM && bits == 24:
+		out := make([]float32, len(data)/3)
+		for i := range out {
+			b := data[i*3:]
+			v := int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24) >> 8
+			out[i] = float32(v) / 8388608
+		}
+		return out, nil
+	case format == wavFormatPCM && bits == 32:
+		out := make([]float32, len(data)/4)
+		for i := range out {
+			out[i] = float32(int32(binary.LittleEndian.Uint32(data[i*4:]))) / 2147483648
+		}
+		return out, nil
+	case format == wavFormatFloat && bits == 32:
+		out := make([]float32, len(data)/4)
+		for i := range out {
+			out[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("wav: unsupported format %d with %d bits", format, bits)
+}
+
+func (c *Clip) validate() error {
+	if c.Channels < 1 || c.Channels > 2 {
+		return fmt.Errorf("unsupported channel count %d", c.Channels)
+	}
+	if c.SampleRate <= 0 {
+		return fmt.Errorf("invalid sample rate %d", c.SampleRate)
+	}
+	return nil
+}
+
+// EncodeWAV writes interleaved samples as 16-bit PCM, clamping to -1..1.
+func EncodeWAV(w io.Writer, sampleRate, channels int, samples []float32) error {
+	var buf bytes.Buffer
+	dataSize := len(samples) * 2
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(wavFormatPCM))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*channels*2))
+	binary.Write(&buf, binary.LittleEndian, uint16(channels*2))
+	binary.Write(&buf, binary.LittleEndian, uint16(16))
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(dataSize))
+	pcm := make([]byte, dataSize)
+	for i, s := range samples {
+		s = max(-1, min(1, s))
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(int16(math.Round(float64(s)*32767))))
+	}
+	buf.Write(pcm)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
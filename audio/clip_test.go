@@ -0,0 +1,74 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestEncodeWAVRoundTripsThroughDecodeWAV(t *testing.T) {
+	samples := []float32{0, 0.5, -0.5, 1, -1, 0.25}
+	var buf bytes.Buffer
+	if err := EncodeWAV(&buf, 22050, 2, samples); err != nil {
+		t.Fatalf("EncodeWAV failed: %v", err)
+	}
+	clip, err := DecodeWAV(&buf)
+	if err != nil {
+		t.Fatalf("DecodeWAV failed: %v", err)
+	}
+	if clip.SampleRate != 22050 || clip.Channels != 2 || clip.Frames() != 3 {
+		t.Fatalf("unexpected clip header %d Hz, %d channels, %d frames", clip.SampleRate, clip.Channels, clip.Frames())
+	}
+	for i, want := range samples {
+		if math.Abs(float64(clip.Samples[i]-want)) > 1e-3 {
+			t.Fatalf("sample %d: expected %v, got %v", i, want, clip.Samples[i])
+		}
+	}
+	if math.Abs(clip.Duration()-3.0/22050) > 1e-9 {
+		t.Fatalf("unexpected duration %v", clip.Duration())
+	}
+}
+
+func TestDecodeWAVSkipsUnknownChunksAndReads8Bit(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(0))
+	buf.WriteString("WAVE")
+	// An odd-sized LIST chunk exercises the pad byte.
+	buf.WriteString("LIST")
+	binary.Write(&buf, binary.LittleEndian, uint32(3))
+	buf.Write([]byte{1, 2, 3, 0})
+	buf.WriteString("fmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))
+	binary.Write(&buf, binary.LittleEndian, uint16(wavFormatPCM))
+	binary.Write(&buf, binary.LittleEndian, uint16(1))
+	binary.Write(&buf, binary.LittleEndian, uint32(11025))
+	binary.Write(&buf, binary.LittleEndian, uint32(11025))
+	binary.Write(&buf, binary.LittleEndian, uint16(1))
+	binary.Write(&buf, binary.LittleEndian, uint16(8))
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(3))
+	buf.Write([]byte{128, 255, 0})
+
+	clip, err := DecodeWAV(&buf)
+	if err != nil {
+		t.Fatalf("DecodeWAV failed: %v", err)
+	}
+	if clip.Channels != 1 || clip.SampleRate != 11025 || len(clip.Samples) != 3 {
+		t.Fatalf("unexpected clip %+v", clip)
+	}
+	if clip.Samples[0] != 0 || clip.Samples[1] < 0.99 || clip.Samples[2] != -1 {
+		t.Fatalf("unexpected 8-bit samples %v", clip.Samples)
+	}
+}
+
+func TestDecodeWAVRejectsUnsupportedChannelCounts(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeWAV(&buf, 44100, 4, make([]float32, 8)); err != nil {
+		t.Fatalf("EncodeWAV failed: %v", err)
+	}
+	if _, err := DecodeWAV(&buf); err == nil {
+		t.Fatal("expected a four-channel clip to be rejected")
+	}
+}
@@ -0,0 +1,125 @@
+package audio
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ProbeDirections returns n unit directions spread evenly over the sphere on
+// a Fibonacci lattice. The set depends only on n, so room estimates are
+// reproducible.
+func ProbeDirections(n int) []mgl32.Vec3 {
+	if n <= 0 {
+		return nil
+	}
+	golden := math.Pi * (3 - math.Sqrt(5))
+	dirs := make([]mgl32.Vec3, n)
+	for i := range dirs {
+		y := 1 - (float64(i)+0.5)*2/float64(n)
+		radius := math.Sqrt(1 - y*y)
+		theta := golden * float64(i)
+		dirs[i] = mgl32.Vec3{float32(math.Cos(theta) * radius), float32(y), float32(math.Sin(theta) * radius)}
+	}
+	return dirs
+}
+
+// Room summarizes probe rays cast from the listener.
+type Room struct {
+	// Size is the mean distance to geometry along the rays that hit.
+	Size float32
+	// Openness is the fraction of rays that escaped without a hit.
+	Openness float32
+}
+
+// EstimateRoom builds a Room from probe hit distances. Negative distances
+// are misses; a probe set with no hits is fully open.
+func EstimateRoom(distances []float32) Room {
+	if len(distances) == 0 {
+		return Room{Openness: 1}
+	}
+	var sum float32
+	hits := 0
+	for _, d := range distances {
+		if d < 0 {
+			continue
+		}
+		sum += d
+		hits++
+	}
+	room := Room{Openness: float32(len(distances)-hits) / float32(len(distances))}
+	if hits > 0 {
+		room.Size = sum / float32(hits)
+	}
+	return room
+}
+
+// Reverb maps the room to reverb settings. Enclosed rooms are wetter, and
+// larger rooms decay longer; maxDistance is the probe length the room was
+// measured with. A fully open room has no reverb.
+func (r Room) Reverb(maxDistance float32) ReverbParams {
+	enclosure := 1 - clamp01(r.Openness)
+	if enclosure <= 0 {
+		return ReverbParams{}
+	}
+	size := float32(0)
+	if maxDistance > 0 {
+		size = clamp01(r.Size / maxDistance)
+	}
+	return ReverbParams{
+		// A square root keeps small rooms from sounding dry.
+		RoomSize: float32(math.Sqrt(float64(size))),
+		Damping:  1 - size,
+		Wet:      enclosure,
+	}
+}
+
+// Lerp blends two rooms, for smoothing estimates over time.
+func (r Room) Lerp(to Room, t float32) Room {
+	return Room{
+		Size:     r.Size + (to.Size-r.Size)*t,
+		Openness: r.Openness + (to.Openness-r.Openness)*t,
+	}
+}
+
+// OcclusionResponse maps how much of a source is blocked to its gain and
+// low-pass cutoff.
+type OcclusionResponse struct {
+	// OccludedGain is the gain of a fully blocked source.
+	OccludedGain float32
+	// OccludedCutoff is the low-pass cutoff in Hz of a fully blocked source.
+	OccludedCutoff float32
+}
+
+// openCutoff is where the occlusion low-pass starts; it is effectively
+// transparent.
+const openCutoff = 20000
+
+// Apply returns the gain and low-pass cutoff for occlusion in 0..1. The
+// cutoff moves geometrically so each step sounds similar; an unblocked
+// source returns zero, leaving the voice unfiltered.
+func (o OcclusionResponse) Apply(occlusion float32) (gain, cutoff float32) {
+	occlusion = clamp01(occlusion)
+	if occlusion <= 0 {
+		return 1, 0
+	}
+	gain = 1 + (clamp01(o.OccludedGain)-1)*occlusion
+	target := o.OccludedCutoff
+	if target <= 0 || target >= openCutoff {
+		return gain, 0
+	}
+	cutoff = float32(openCutoff * math.Pow(float64(target)/openCutoff, float64(occlusion)))
+	return gain, cutoff
+}
+
+// CombineLowPass returns the stricter of two cutoffs, treating zero as
+// unfiltered.
+func CombineLowPass(a, b float32) float32 {
+	if a <= 0 {
+		return b
+	}
+	if b <= 0 {
+		return a
+	}
+	return min(a, b)
+}
@@ -0,0 +1,68 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProbeDirectionsAreUnitAndDeterministic(t *testing.T) {
+	a, b := ProbeDirections(12), ProbeDirections(12)
+	var up, down int
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("direction %d differs between calls", i)
+		}
+		if math.Abs(float64(a[i].Len()-1)) > 1e-5 {
+			t.Fatalf("direction %d is not unit length: %v", i, a[i])
+		}
+		if a[i].Y() > 0 {
+			up++
+		} else {
+			down++
+		}
+	}
+	if up != down {
+		t.Fatalf("expected directions split evenly between hemispheres, got %d up %d down", up, down)
+	}
+}
+
+func TestEstimateRoomMapsEnclosureToReverb(t *testing.T) {
+	closed := EstimateRoom([]float32{4, 4, 4, 4})
+	if closed.Openness != 0 || closed.Size != 4 {
+		t.Fatalf("unexpected closed room %+v", closed)
+	}
+	half := EstimateRoom([]float32{8, -1, 8, -1})
+	if half.Openness != 0.5 || half.Size != 8 {
+		t.Fatalf("unexpected half-open room %+v", half)
+	}
+	if open := EstimateRoom([]float32{-1, -1}); open.Reverb(32) != (ReverbParams{}) {
+		t.Fatalf("expected an open room to have no reverb, got %+v", open.Reverb(32))
+	}
+
+	small, large := closed.Reverb(32), EstimateRoom([]float32{24, 24}).Reverb(32)
+	if small.Wet != 1 || half.Reverb(32).Wet != 0.5 {
+		t.Fatalf("expected wet to follow enclosure, got %v and %v", small.Wet, half.Reverb(32).Wet)
+	}
+	if large.RoomSize <= small.RoomSize || large.Damping >= small.Damping {
+		t.Fatalf("expected a larger room to ring longer, small %+v large %+v", small, large)
+	}
+}
+
+func TestOcclusionResponseLowersGainAndCutoff(t *testing.T) {
+	response := OcclusionResponse{OccludedGain: 0.4, OccludedCutoff: 800}
+	if gain, cutoff := response.Apply(0); gain != 1 || cutoff != 0 {
+		t.Fatalf("expected an open source unfiltered, got gain %v cutoff %v", gain, cutoff)
+	}
+	halfGain, halfCutoff := response.Apply(0.5)
+	fullGain, fullCutoff := response.Apply(1)
+	if math.Abs(float64(fullGain-0.4)) > 1e-6 || math.Abs(float64(fullCutoff-800)) > 0.5 {
+		t.Fatalf("expected the occluded response at full occlusion, got gain %v cutoff %v", fullGain, fullCutoff)
+	}
+	// Geometric interpolation puts half occlusion at the mean of the octaves.
+	if math.Abs(float64(halfCutoff)-math.Sqrt(20000*800)) > 1 || halfGain <= fullGain {
+		t.Fatalf("unexpected half occlusion gain %v cutoff %v", halfGain, halfCutoff)
+	}
+	if CombineLowPass(0, 500) != 500 || CombineLowPass(900, 500) != 500 || CombineLowPass(900, 0) != 900 {
+		t.Fatal("expected CombineLowPass to keep the stricter cutoff")
+	}
+}
@@ -0,0 +1,364 @@
+package audio
+
+import "math"
+
+// MasterBus is the root every other bus feeds.
+const MasterBus = "master"
+
+// Bus is a named volume stage. Voices play through a bus, and each bus feeds
+// its parent up to MasterBus.
+type Bus struct {
+	Name   string
+	Parent string // empty feeds MasterBus
+	Volume float32
+	Muted  bool
+}
+
+// DefaultBuses returns master plus sfx, music, ambient, voice and ui at full
+// volume.
+func DefaultBuses() []Bus {
+	return []Bus{
+		{Name: MasterBus, Volume: 1},
+		{Name: "sfx", Volume: 1},
+		{Name: "music", Volume: 1},
+		{Name: "ambient", Volume: 1},
+		{Name: "voice", Volume: 1},
+		{Name: "ui", Volume: 1},
+	}
+}
+
+type VoiceID uint64
+
+// VoiceParams are a voice's per-frame mix settings. Spatial callers fold
+// distance attenuation into Gain and Doppler into Pitch.
+type VoiceParams struct {
+	Gain  float32
+	Pan   float32 // -1 left .. 1 right
+	Pitch float32 // playback-rate multiplier; zero means 1
+	Bus   string  // empty plays through MasterBus
+	Loop  bool
+	// Priority decides which voice is stolen when the mixer is full; lower
+	// priorities go first, then quieter voices.
+	Priority int
+	// LowPass is a one-pole low-pass cutoff in Hz; zero leaves the voice
+	// unfiltered. Occlusion and underwater muffling drive it.
+	LowPass float32
+	// ReverbSend is the share of the voice's post-gain signal fed to the
+	// mixer's reverb.
+	ReverbSend float32
+}
+
+type voice struct {
+	id       VoiceID
+	clip     *Clip
+	params   VoiceParams
+	position float64
+	gainL    float32
+	gainR    float32
+	ramped   bool
+	done     bool
+	lowL     float32
+	lowR     float32
+}
+
+// Mixer sums voices into interleaved stereo float frames at SampleRate.
+// Gain and pan changes are ramped across each mixed block to avoid clicks.
+type Mixer struct {
+	SampleRate int
+	MaxVoices  int
+
+	buses  map[string]*Bus
+	voices []*voice
+	nextID VoiceID
+	reverb *reverb
+	send   []float32
+}
+
+const (
+	DefaultSampleRate = 48000
+	DefaultMaxVoices  = 32
+)
+
+func NewMixer(sampleRate, maxVoices int, buses ...Bus) *Mixer {
+	if sampleRate <= 0 {
+		sampleRate = DefaultSampleRate
+	}
+	if maxVoices <= 0 {
+		maxVoices = DefaultMaxVoices
+	}
+	if len(buses) == 0 {
+		buses = DefaultBuses()
+	}
+	m := &Mixer{SampleRate: sampleRate, MaxVoices: maxVoices, buses: map[string]*Bus{}}
+	m.buses[MasterBus] = &Bus{Name: MasterBus, Volume: 1}
+	for _, bus := range buses {
+		bus := bus
+		m.buses[bus.Name] = &bus
+	}
+	return m
+}
+
+// SetReverb changes the shared reverb. A zero Wet bypasses it.
+func (m *Mixer) SetReverb(params ReverbParams) {
+	if m.reverb == nil {
+		if params.Wet <= 0 {
+			return
+		}
+		m.reverb = newReverb(m.SampleRate)
+	}
+	m.reverb.params = params
+}
+
+// Reverb returns the current reverb settings.
+func (m *Mixer) Reverb() ReverbParams {
+	if m.reverb == nil {
+		return ReverbParams{}
+	}
+	return m.reverb.params
+}
+
+// Bus returns the named bus for volume and mute changes, or nil.
+func (m *Mixer) Bus(name string) *Bus {
+	return m.buses[name]
+}
+
+// SetBusVolume adds the bus when it does not exist yet.
+func (m *Mixer) SetBusVolume(name string, volume float32) {
+	if bus := m.buses[name]; bus != nil {
+		bus.Volume = volume
+		return
+	}
+	m.buses[name] = &Bus{Name: name, Volume: volume}
+}
+
+// busGain multiplies volumes from name up to MasterBus. Unknown buses play
+// through master; parent cycles are cut off.
+func (m *Mixer) busGain(name string) float32 {
+	if name == "" {
+		name = MasterBus
+	}
+	gain := float32(1)
+	for depth := 0; depth < 16; depth++ {
+		bus := m.buses[name]
+		if bus == nil {
+			bus = m.buses[MasterBus]
+			name = MasterBus
+		}
+		if bus.Muted {
+			return 0
+		}
+		gain *= bus.Volume
+		if name == MasterBus {
+			break
+		}
+		name = bus.Parent
+		if name == "" {
+			name = MasterBus
+		}
+	}
+	return gain
+}
+
+// Play starts clip and returns its voice, or zero when the mixer is full of
+// higher-priority voices.
+func (m *Mixer) Play(clip *Clip, params VoiceParams) VoiceID {
+	if clip == nil || clip.Frames() == 0 {
+		return 0
+	}
+	if m.ActiveVoices() >= m.MaxVoices {
+		victim := m.stealCandidate()
+		if victim == nil || !outranks(params, m.audibility(params), victim.params, m.audibility(victim.params)) {
+			return 0
+		}
+		victim.done = true
+		m.compact()
+	}
+	m.nextID++
+	m.voices = append(m.voices, &voice{id: m.nextID, clip: clip, params: params})
+	return m.nextID
+}
+
+func (m *Mixer) audibility(params VoiceParams) float32 {
+	return params.Gain * m.busGain(params.Bus)
+}
+
+func (m *Mixer) stealCandidate() *voice {
+	var victim *voice
+	for _, v := range m.voices {
+		if v.done {
+			continue
+		}
+		if victim == nil || outranks(victim.params, m.audibility(victim.params), v.params, m.audibility(v.params)) {
+			victim = v
+		}
+	}
+	return victim
+}
+
+// outranks reports whether a should keep playing over b.
+func outranks(a VoiceParams, aAudibility float32, b VoiceParams, bAudibility float32) bool {
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	return aAudibility > bAudibility
+}
+
+// Update changes a playing voice's parameters. It returns false when the
+// voice has finished or was stolen.
+func (m *Mixer) Update(id VoiceID, params VoiceParams) bool {
+	if v := m.voice(id); v != nil {
+		v.params = params
+		return true
+	}
+	return false
+}
+
+func (m *Mixer) Stop(id VoiceID) {
+	if v := m.voice(id); v != nil {
+		v.done = true
+		m.compact()
+	}
+}
+
+func (m *Mixer) Playing(id VoiceID) bool {
+	return m.voice(id) != nil
+}
+
+func (m *Mixer) ActiveVoices() int {
+	count := 0
+	for _, v := range m.voices {
+		if !v.done {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *Mixer) voice(id VoiceID) *voice {
+	if id == 0 {
+		return nil
+	}
+	for _, v := range m.voices {
+		if v.id == id && !v.done {
+			return v
+		}
+	}
+	return nil
+}
+
+func (m *Mixer) compact() {
+	kept := m.voices[:0]
+	for _, v := range m.voices {
+		if !v.done {
+			kept = append(kept, v)
+		}
+	}
+	for i := len(kept); i < len(m.voices); i++ {
+		m.voices[i] = nil
+	}
+	m.voices = kept
+}
+
+// Mix overwrites out with len(out)/2 stereo frames.
+func (m *Mixer) Mix(out []float32) {
+	clear(out)
+	frames := len(out) / 2
+	if frames == 0 {
+		return
+	}
+	var send []float32
+	if m.reverb != nil && m.reverb.params.Wet > 0 {
+		if cap(m.send) < frames {
+			m.send = make([]float32, frames)
+		}
+		send = m.send[:frames]
+		clear(send)
+	}
+	for _, v := range m.voices {
+		if !v.done {
+			m.mixVoice(v, out, send, frames)
+		}
+	}
+	if send != nil {
+		m.reverb.process(send, out)
+	}
+	m.compact()
+}
+
+// lowPassAlpha returns the one-pole smoothing factor for cutoff, or 1 when
+// the filter is off or above Nyquist.
+func lowPassAlpha(cutoff float32, sampleRate int) float32 {
+	if cutoff <= 0 || float64(cutoff) >= float64(sampleRate)/2 {
+		return 1
+	}
+	return float32(1 - math.Exp(-2*math.Pi*float64(cutoff)/float64(sampleRate)))
+}
+
+func (m *Mixer) mixVoice(v *voice, out, send []float32, frames int) {
+	clip := v.clip
+	gain := v.params.Gain * m.busGain(v.params.Bus)
+	panL, panR := PanGains(v.params.Pan, clip.Channels)
+	targetL, targetR := gain*panL, gain*panR
+	if !v.ramped {
+		v.gainL, v.gainR = targetL, targetR
+		v.ramped = true
+	}
+	pitch := v.params.Pitch
+	if pitch <= 0 {
+		pitch = 1
+	}
+	step := float64(clip.SampleRate) / float64(m.SampleRate) * float64(pitch)
+	clipFrames := clip.Frames()
+	channels := clip.Channels
+	alpha := lowPassAlpha(v.params.LowPass, m.SampleRate)
+	reverbSend := max(0, v.params.ReverbSend)
+
+	for f := 0; f < frames; f++ {
+		index := int(v.position)
+		if index >= clipFrames {
+			if !v.params.Loop {
+				v.done = true
+				break
+			}
+			v.position -= float64(clipFrames) * float64(index/clipFrames)
+			index = int(v.position)
+		}
+		next := index + 1
+		if next >= clipFrames {
+			if v.params.Loop {
+				next = 0
+			} else {
+				next = index
+			}
+		}
+		frac := float32(v.position - float64(index))
+		var left, right float32
+		if channels == 1 {
+			s := clip.Samples[index] + (clip.Samples[next]-clip.Samples[index])*frac
+			left, right = s, s
+		} else {
+			left = clip.Samples[index*2] + (clip.Samples[next*2]-clip.Samples[index*2])*frac
+			right = clip.Samples[index*2+1] + (clip.Samples[next*2+1]-clip.Samples[index*2+1])*frac
+		}
+		if alpha < 1 {
+			v.lowL += (left - v.lowL) * alpha
+			v.lowR += (right - v.lowR) * alpha
+			left, right = v.lowL, v.lowR
+		} else {
+			v.lowL, v.lowR = left, right
+		}
+		t := float32(f+1) / float32(frames)
+		left *= v.gainL + (targetL-v.gainL)*t
+		right *= v.gainR + (targetR-v.gainR)*t
+		out[f*2] += left
+		out[f*2+1] += right
+		if send != nil && reverbSend > 0 {
+			send[f] += (left + right) * 0.5 * reverbSend
+		}
+		v.position += step
+	}
+	if !v.params.Loop && int(v.position) >= clipFrames {
+		v.done = true
+	}
+	v.gainL, v.gainR = targetL, targetR
+}
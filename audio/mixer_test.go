@@ -0,0 +1,125 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+func constantClip(rate, frames int, value float32) *Clip {
+	samples := make([]float32, frames)
+	for i := range samples {
+		samples[i] = value
+	}
+	return &Clip{SampleRate: rate, Channels: 1, Samples: samples}
+}
+
+func TestMixerResamplesAndFinishesOneShots(t *testing.T) {
+	m := NewMixer(48000, 4)
+	clip := &Clip{SampleRate: 24000, Channels: 1, Samples: []float32{0, 1, 0, 1}}
+	id := m.Play(clip, VoiceParams{Gain: 1})
+	out := make([]float32, 16)
+	m.Mix(out)
+	// Half the clip rate plays each source frame twice, interpolating between.
+	if math.Abs(float64(out[2]-0.5)) > 1e-5 || math.Abs(float64(out[4]-1)) > 1e-5 {
+		t.Fatalf("expected linear interpolation at half rate, got %v", out)
+	}
+	if m.Playing(id) || m.ActiveVoices() != 0 {
+		t.Fatal("expected the one-shot to finish inside the block")
+	}
+}
+
+func TestMixerLoopsAndAppliesBusVolumes(t *testing.T) {
+	m := NewMixer(1000, 4)
+	m.SetBusVolume(MasterBus, 0.5)
+	m.SetBusVolume("sfx", 0.5)
+	id := m.Play(constantClip(1000, 3, 1), VoiceParams{Gain: 1, Bus: "sfx", Loop: true})
+	out := make([]float32, 20)
+	m.Mix(out)
+	if !m.Playing(id) {
+		t.Fatal("expected a looping voice to keep playing")
+	}
+	for i, s := range out {
+		if math.Abs(float64(s-0.25)) > 1e-5 {
+			t.Fatalf("sample %d: expected master*sfx gain 0.25, got %v", i, s)
+		}
+	}
+
+	m.Bus("sfx").Muted = true
+	m.Mix(out)
+	if out[len(out)-1] != 0 {
+		t.Fatalf("expected a muted bus to ramp to silence, got %v", out[len(out)-1])
+	}
+}
+
+func TestMixerStealsLowestPriorityVoice(t *testing.T) {
+	m := NewMixer(1000, 2)
+	clip := constantClip(1000, 100, 1)
+	low := m.Play(clip, VoiceParams{Gain: 1, Priority: 0})
+	high := m.Play(clip, VoiceParams{Gain: 0.1, Priority: 5})
+	if rejected := m.Play(clip, VoiceParams{Gain: 1, Priority: -1}); rejected != 0 {
+		t.Fatal("expected a lower-priority voice to be rejected when full")
+	}
+	stealer := m.Play(clip, VoiceParams{Gain: 0.5, Priority: 1})
+	if stealer == 0 || m.Playing(low) || !m.Playing(high) || m.ActiveVoices() != 2 {
+		t.Fatalf("expected the priority 0 voice to be stolen, active %d", m.ActiveVoices())
+	}
+	if m.Update(low, VoiceParams{}) {
+		t.Fatal("expected Update on a stolen voice to report false")
+	}
+}
+
+func TestMixerPansMonoVoices(t *testing.T) {
+	m := NewMixer(1000, 2)
+	m.Play(constantClip(1000, 10, 1), VoiceParams{Gain: 1, Pan: 1, Loop: true})
+	out := make([]float32, 8)
+	m.Mix(out)
+	if out[0] > 1e-6 || out[1] < 1.4 {
+		t.Fatalf("expected a hard-right voice on the right channel, got %v", out[:2])
+	}
+}
+
+func TestMixerLowPassMufflesHighFrequencies(t *testing.T) {
+	alternating := &Clip{SampleRate: 1000, Channels: 1, Samples: make([]float32, 200)}
+	for i := range alternating.Samples {
+		alternating.Samples[i] = float32(1 - 2*(i%2))
+	}
+	peak := func(params VoiceParams) float32 {
+		m := NewMixer(1000, 2)
+		m.Play(alternating, params)
+		out := make([]float32, 200)
+		m.Mix(out)
+		var p float32
+		for _, s := range out[100:] {
+			p = max(p, float32(math.Abs(float64(s))))
+		}
+		return p
+	}
+	dry := peak(VoiceParams{Gain: 1})
+	muffled := peak(VoiceParams{Gain: 1, LowPass: 50})
+	if muffled > dry*0.5 {
+		t.Fatalf("expected a 50Hz low-pass to cut a Nyquist signal, dry %v muffled %v", dry, muffled)
+	}
+}
+
+func TestMixerReverbLeavesATail(t *testing.T) {
+	m := NewMixer(8000, 2)
+	m.SetReverb(ReverbParams{RoomSize: 0.8, Wet: 1})
+	m.Play(constantClip(8000, 80, 1), VoiceParams{Gain: 1, ReverbSend: 1})
+	out := make([]float32, 8000)
+	m.Mix(out)
+	if m.ActiveVoices() != 0 {
+		t.Fatal("expected the dry voice to finish")
+	}
+	var tail float32
+	for _, s := range out[1000:] {
+		tail = max(tail, float32(math.Abs(float64(s))))
+	}
+	if tail == 0 {
+		t.Fatal("expected the reverb to keep ringing after the voice ended")
+	}
+
+	m.SetReverb(ReverbParams{})
+	if m.Reverb() != (ReverbParams{}) {
+		t.Fatal("expected the reverb to be bypassed")
+	}
+}
@@ -0,0 +1,100 @@
+package audio
+
+// ReverbParams configure the mixer's shared reverb. Voices feed it through
+// VoiceParams.ReverbSend.
+type ReverbParams struct {
+	// RoomSize in 0..1 sets the comb feedback and so the decay time.
+	RoomSize float32
+	// Damping in 0..1 absorbs high frequencies in the tail.
+	Damping float32
+	// Wet scales the reverb output; zero disables the reverb.
+	Wet float32
+}
+
+// Comb and allpass lengths from Freeverb, tuned at 44.1kHz and rescaled to
+// the mixer rate. The right channel is offset to decorrelate the tail.
+var (
+	reverbCombTunings    = [...]int{1116, 1188, 1277, 1356}
+	reverbAllpassTunings = [...]int{556, 441}
+)
+
+const (
+	reverbTuningRate   = 44100
+	reverbStereoSpread = 23
+	reverbInputGain    = 0.015
+	reverbAllpassGain  = 0.5
+)
+
+type reverbComb struct {
+	buffer []float32
+	index  int
+	store  float32
+}
+
+func (c *reverbComb) process(in, feedback, damping float32) float32 {
+	out := c.buffer[c.index]
+	c.store = out*(1-damping) + c.store*damping
+	c.buffer[c.index] = in + c.store*feedback
+	c.index = (c.index + 1) % len(c.buffer)
+	return out
+}
+
+type reverbAllpass struct {
+	buffer []float32
+	index  int
+}
+
+func (a *reverbAllpass) process(in float32) float32 {
+	delayed := a.buffer[a.index]
+	a.buffer[a.index] = in + delayed*reverbAllpassGain
+	a.index = (a.index + 1) % len(a.buffer)
+	return delayed - in
+}
+
+// reverb is a Schroeder-style mono-in, stereo-out reverberator.
+type reverb struct {
+	params    ReverbParams
+	combs     [2][len(reverbCombTunings)]reverbComb
+	allpasses [2][len(reverbAllpassTunings)]reverbAllpass
+}
+
+func newReverb(sampleRate int) *reverb {
+	r := &reverb{}
+	scale := func(samples int) int {
+		return max(1, samples*sampleRate/reverbTuningRate)
+	}
+	for ch := 0; ch < 2; ch++ {
+		spread := ch * reverbStereoSpread
+		for i, tuning := range reverbCombTunings {
+			r.combs[ch][i].buffer = make([]float32, scale(tuning+spread))
+		}
+		for i, tuning := range reverbAllpassTunings {
+			r.allpasses[ch][i].buffer = make([]float32, scale(tuning+spread))
+		}
+	}
+	return r
+}
+
+// process adds the reverb of the mono send buffer to the stereo out buffer.
+func (r *reverb) process(send, out []float32) {
+	feedback := 0.7 + 0.28*clamp01(r.params.RoomSize)
+	damping := 0.4 * clamp01(r.params.Damping)
+	wet := max(0, r.params.Wet)
+	for f, in := range send {
+		in *= reverbInputGain
+		for ch := 0; ch < 2; ch++ {
+			var acc float32
+			for i := range r.combs[ch] {
+				acc += r.combs[ch][i].process(in, feedback, damping)
+			}
+			for i := range r.allpasses[ch] {
+				acc = r.allpasses[ch][i].process(acc)
+			}
+			out[f*2+ch] += acc * wet
+		}
+	}
+}
+
+func clamp01(v float32) float32 {
+	return max(0, min(1, v))
+}
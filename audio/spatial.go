@@ -0,0 +1,105 @@
+package audio
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SpeedOfSound is in meters per second, matching the engine's world units.
+const SpeedOfSound = 343.0
+
+// Attenuation describes how a source fades with distance.
+type Attenuation struct {
+	// MinDistance is the radius of full volume.
+	MinDistance float32
+	// MaxDistance is where the source becomes silent.
+	MaxDistance float32
+	// Rolloff scales the inverse-distance falloff; 1 halves the gain at
+	// twice MinDistance. Zero means 1.
+	Rolloff float32
+}
+
+// fadeBand is the fraction of MaxDistance over which the inverse-distance
+// curve is faded to zero, so sources reach silence instead of a floor.
+const fadeBand = 0.1
+
+// Gain returns the distance gain in 0..1 using the clamped inverse-distance
+// model.
+func (a Attenuation) Gain(distance float32) float32 {
+	minDistance := a.MinDistance
+	if minDistance <= 0 {
+		minDistance = 1
+	}
+	if distance <= minDistance {
+		return 1
+	}
+	if a.MaxDistance > 0 && distance >= a.MaxDistance {
+		return 0
+	}
+	rolloff := a.Rolloff
+	if rolloff <= 0 {
+		rolloff = 1
+	}
+	gain := minDistance / (minDistance + rolloff*(distance-minDistance))
+	if a.MaxDistance > minDistance {
+		fadeStart := a.MaxDistance * (1 - fadeBand)
+		if distance > fadeStart && fadeStart > minDistance {
+			gain *= (a.MaxDistance - distance) / (a.MaxDistance - fadeStart)
+		}
+	}
+	return gain
+}
+
+// Pan returns -1 (left) .. 1 (right) for a source direction relative to a
+// listener whose right vector is right.
+func Pan(listenerPos, listenerRight, sourcePos mgl32.Vec3) float32 {
+	offset := sourcePos.Sub(listenerPos)
+	if offset.Len() < 1e-5 {
+		return 0
+	}
+	return max(-1, min(1, offset.Normalize().Dot(listenerRight)))
+}
+
+// PanGains converts a pan into left and right channel gains. Mono sources use
+// an equal-power law normalized so a centered source plays at unity on both
+// channels; stereo sources use balance, attenuating only the far side.
+func PanGains(pan float32, channels int) (float32, float32) {
+	pan = max(-1, min(1, pan))
+	if channels == 2 {
+		if pan < 0 {
+			return 1, 1 + pan
+		}
+		return 1 - pan, 1
+	}
+	angle := float64(pan+1) * math.Pi / 4
+	return float32(math.Cos(angle) * math.Sqrt2), float32(math.Sin(angle) * math.Sqrt2)
+}
+
+// maxDopplerSpeed keeps velocities clear of the speed of sound, where the
+// Doppler formula diverges.
+const maxDopplerSpeed = SpeedOfSound * 0.5
+
+// DopplerPitch returns the playback-rate multiplier for a source and listener
+// moving with the given velocities. factor scales the effect; zero disables
+// it. The result is clamped to 0.5..2.
+func DopplerPitch(listenerPos, listenerVel, sourcePos, sourceVel mgl32.Vec3, factor float32) float32 {
+	if factor <= 0 {
+		return 1
+	}
+	offset := sourcePos.Sub(listenerPos)
+	if offset.Len() < 1e-5 {
+		return 1
+	}
+	dir := offset.Normalize()
+	// Positive when the listener moves toward the source / the source moves
+	// away from the listener.
+	vl := clampSpeed(listenerVel.Dot(dir) * factor)
+	vs := clampSpeed(sourceVel.Dot(dir) * factor)
+	pitch := float32((SpeedOfSound + float64(vl)) / (SpeedOfSound + float64(vs)))
+	return max(0.5, min(2, pitch))
+}
+
+func clampSpeed(v float32) float32 {
+	return max(-maxDopplerSpeed, min(maxDopplerSpeed, v))
+}
@@ -0,0 +1,62 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestAttenuationGainFallsOffAndReachesSilence(t *testing.T) {
+	a := Attenuation{MinDistance: 2, MaxDistance: 40}
+	if g := a.Gain(1); g != 1 {
+		t.Fatalf("expected full gain inside MinDistance, got %v", g)
+	}
+	if g := a.Gain(4); math.Abs(float64(g)-0.5) > 1e-6 {
+		t.Fatalf("expected half gain at twice MinDistance, got %v", g)
+	}
+	if near, far := a.Gain(10), a.Gain(20); far >= near {
+		t.Fatalf("expected gain to fall with distance, got %v then %v", near, far)
+	}
+	if g := a.Gain(39.9); g <= 0 || g > 0.01 {
+		t.Fatalf("expected the fade band to approach silence, got %v", g)
+	}
+	if g := a.Gain(40); g != 0 {
+		t.Fatalf("expected silence at MaxDistance, got %v", g)
+	}
+}
+
+func TestPanAndPanGains(t *testing.T) {
+	right := mgl32.Vec3{1, 0, 0}
+	if p := Pan(mgl32.Vec3{}, right, mgl32.Vec3{5, 0, 0}); p != 1 {
+		t.Fatalf("expected a source to the right to pan fully right, got %v", p)
+	}
+	if p := Pan(mgl32.Vec3{}, right, mgl32.Vec3{0, 0, -5}); math.Abs(float64(p)) > 1e-6 {
+		t.Fatalf("expected a source ahead to be centered, got %v", p)
+	}
+	l, r := PanGains(0, 1)
+	if math.Abs(float64(l-1)) > 1e-6 || math.Abs(float64(r-1)) > 1e-6 {
+		t.Fatalf("expected a centered mono source at unity, got %v %v", l, r)
+	}
+	if l, r = PanGains(1, 1); l > 1e-6 || r < 1.4 {
+		t.Fatalf("expected a hard-right mono source on the right only, got %v %v", l, r)
+	}
+	if l, r = PanGains(-0.5, 2); l != 1 || r != 0.5 {
+		t.Fatalf("expected stereo balance to attenuate only the far side, got %v %v", l, r)
+	}
+}
+
+func TestDopplerPitchRisesWhenApproaching(t *testing.T) {
+	source := mgl32.Vec3{0, 0, -50}
+	approaching := DopplerPitch(mgl32.Vec3{}, mgl32.Vec3{}, source, mgl32.Vec3{0, 0, 30}, 1)
+	receding := DopplerPitch(mgl32.Vec3{}, mgl32.Vec3{}, source, mgl32.Vec3{0, 0, -30}, 1)
+	if approaching <= 1 || receding >= 1 {
+		t.Fatalf("expected approach to raise and recession to lower pitch, got %v %v", approaching, receding)
+	}
+	if p := DopplerPitch(mgl32.Vec3{}, mgl32.Vec3{}, source, mgl32.Vec3{0, 0, 30}, 0); p != 1 {
+		t.Fatalf("expected a zero factor to disable Doppler, got %v", p)
+	}
+	if p := DopplerPitch(mgl32.Vec3{}, mgl32.Vec3{}, source, mgl32.Vec3{0, 0, 10000}, 1); p > 2 {
+		t.Fatalf("expected the pitch to stay clamped, got %v", p)
+	}
+}
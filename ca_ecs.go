@@ -471,12 +471,9 @@ func bridgeCellsToParticles(cmd *Commands, instances *[]core.ParticleInstance, m
 	added := 0
 	// Capture camera position once for adaptive LOD
 	camPos := mgl32.Vec3{}
-	MakeQuery1[CameraComponent](cmd).Map(func(eid EntityId, cam *CameraComponent) bool {
-		if cam != nil {
-			camPos = cam.Position
-		}
-		return false
-	})
+	if cam, ok := primaryRenderCamera(cmd); ok {
+		camPos = cam.Position
+	}
 	MakeQuery2[TransformComponent, CellularVolumeComponent](cmd).Map(func(eid EntityId, tr *TransformComponent, cv *CellularVolumeComponent) bool {
 		if cv == nil {
 			return true
@@ -0,0 +1,92 @@
+// Command loccheck compares every string table in a directory against the
+// reference locale and reports missing keys, missing plural forms and
+// placeholder mismatches. Keys a fallback table provides are reported as
+// warnings. It exits non-zero when any table has errors.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gekko3d/gekko/content"
+)
+
+func main() {
+	var dir string
+	var reference string
+	var locales string
+	var asJSON bool
+	flag.StringVar(&dir, "dir", "", "directory of .json and .po string tables")
+	flag.StringVar(&reference, "reference", "en", "locale whose keys every other table must have")
+	flag.StringVar(&locales, "locales", "", "comma-separated locales to check; defaults to every table")
+	flag.BoolVar(&asJSON, "json", false, "print issues as JSON")
+	flag.Parse()
+
+	if dir == "" {
+		fatalf("-dir is required")
+	}
+	tables, err := content.LoadStringTableDir(dir)
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	var referenceTable *content.StringTableDef
+	for _, table := range tables {
+		if strings.EqualFold(table.Locale, reference) {
+			referenceTable = table
+		}
+	}
+	if referenceTable == nil {
+		fatalf("no string table for reference locale %q in %s", reference, dir)
+	}
+
+	selected := map[string]bool{}
+	for _, locale := range strings.Split(locales, ",") {
+		if locale = strings.TrimSpace(locale); locale != "" {
+			selected[strings.ToLower(locale)] = true
+		}
+	}
+
+	var issues []content.StringTableValidationIssue
+	checked := 0
+	errorCount := 0
+	for _, table := range tables {
+		if len(selected) > 0 && !selected[strings.ToLower(table.Locale)] && table != referenceTable {
+			continue
+		}
+		checked++
+		results := []content.StringTableValidationResult{content.ValidateStringTable(table)}
+		if table != referenceTable {
+			fallbacks := content.StringTableFallbacks(tables, table, referenceTable)
+			results = append(results, content.CompareStringTables(referenceTable, table, fallbacks...))
+		}
+		for _, result := range results {
+			issues = append(issues, result.Issues...)
+			errorCount += result.HardErrorCount
+		}
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(issues, "", "  ")
+		if err != nil {
+			fatalf("%v", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, issue := range issues {
+			fmt.Printf("%s: %s: %s\n", issue.Locale, issue.Code, issue.Message)
+		}
+		fmt.Printf("checked %d table(s) against %s: %d issue(s), %d error(s)\n", checked, referenceTable.Locale, len(issues), errorCount)
+	}
+	if errorCount > 0 {
+		os.Exit(1)
+	}
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "loccheck: "+format+"\n", args...)
+	os.Exit(2)
+}
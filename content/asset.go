@@ -78,6 +78,7 @@ type AssetDef struct {
 	Lights             []AssetLightDef             `json:"lights,omitempty"`
 	Emitters           []AssetEmitterDef           `json:"emitters,omitempty"`
 	Markers            []AssetMarkerDef            `json:"markers,omitempty"`
+	Skeleton           *AssetSkeletonDef           `json:"skeleton,omitempty"`
 }
 
 type AssetMaterialDef struct {
@@ -164,6 +165,48 @@ type AssetMarkerDef struct {
 	Tags      []string          `json:"tags,omitempty"`
 }
 
+// AssetSkeletonDef animates parts as rigid bones. Each bone drives one part,
+// usually a group holding the bone's voxel chunk, and that part's authored
+// transform is the bone's bind pose. Animation poses replace the bone part's
+// transform, so they are relative to the parent bone like the bind pose.
+type AssetSkeletonDef struct {
+	Bones      []AssetBoneDef      `json:"bones"`
+	Animations []AssetAnimationDef `json:"animations,omitempty"`
+}
+
+type AssetBoneDef struct {
+	Name   string `json:"name"`
+	PartID string `json:"part_id"`
+	// Parent indexes an earlier bone; -1 marks a root bone.
+	Parent int `json:"parent"`
+}
+
+type AssetAnimationDef struct {
+	Name string  `json:"name"`
+	FPS  float32 `json:"fps,omitempty"`
+	Loop bool    `json:"loop,omitempty"`
+	// Frames hold one pose per bone, in bone order.
+	Frames []AssetAnimationFrameDef `json:"frames,omitempty"`
+	Events []AssetAnimationEventDef `json:"events,omitempty"`
+}
+
+type AssetAnimationFrameDef struct {
+	Bones []AssetBonePoseDef `json:"bones"`
+}
+
+type AssetBonePoseDef struct {
+	Position Vec3 `json:"position"`
+	Rotation Quat `json:"rotation"`
+}
+
+// AssetAnimationEventDef fires when playback reaches Frame. Event and Options
+// are game-defined, such as footstep sounds or muzzle flashes.
+type AssetAnimationEventDef struct {
+	Frame   int    `json:"frame"`
+	Event   int    `json:"event"`
+	Options string `json:"options,omitempty"`
+}
+
 // AssetTransformDef is authored relative to the asset root for root items and
 // relative to the parent item for child items. Pivot is stored in that same
 // authored space and must round-trip without recomputing from world state.
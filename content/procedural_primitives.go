@@ -34,6 +34,14 @@ var proceduralPrimitiveSpecs = map[string]ProceduralPrimitiveSpec{
 		Primitive: "ramp",
 		Params:    []string{"sx", "sy", "sz"},
 	},
+	"torus": {
+		Primitive: "torus",
+		Params:    []string{"major_radius", "minor_radius"},
+	},
+	"rounded_box": {
+		Primitive: "rounded_box",
+		Params:    []string{"sx", "sy", "sz", "radius"},
+	},
 }
 
 func ProceduralPrimitiveSpecFor(primitive string) (ProceduralPrimitiveSpec, bool) {
@@ -0,0 +1,221 @@
+package content
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const CurrentStringTableSchemaVersion = 1
+
+// PluralCategory names a CLDR plural form.
+type PluralCategory string
+
+const (
+	PluralZero  PluralCategory = "zero"
+	PluralOne   PluralCategory = "one"
+	PluralTwo   PluralCategory = "two"
+	PluralFew   PluralCategory = "few"
+	PluralMany  PluralCategory = "many"
+	PluralOther PluralCategory = "other"
+)
+
+// StringTableDef holds the translated strings of one locale. Fallback names
+// the locale consulted for keys this table does not have.
+type StringTableDef struct {
+	SchemaVersion int                       `json:"schema_version"`
+	Locale        string                    `json:"locale"`
+	Fallback      string                    `json:"fallback,omitempty"`
+	Strings       map[string]StringEntryDef `json:"strings"`
+}
+
+// StringEntryDef is either a plain string or a set of plural forms. In JSON
+// a plain entry is a string and a plural entry is an object keyed by plural
+// category. Text may contain {name} placeholders.
+type StringEntryDef struct {
+	Text   string
+	Plural map[PluralCategory]string
+}
+
+func (e StringEntryDef) MarshalJSON() ([]byte, error) {
+	if e.Plural != nil {
+		return json.Marshal(e.Plural)
+	}
+	return json.Marshal(e.Text)
+}
+
+func (e *StringEntryDef) UnmarshalJSON(data []byte) error {
+	*e = StringEntryDef{}
+	if err := json.Unmarshal(data, &e.Text); err == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, &e.Plural); err != nil {
+		return fmt.Errorf("string entry must be a string or an object of plural forms")
+	}
+	return nil
+}
+
+// Form returns the text for a plural category, falling back to the other
+// form. Plain entries ignore the category.
+func (e StringEntryDef) Form(category PluralCategory) string {
+	if e.Plural == nil {
+		return e.Text
+	}
+	if text, ok := e.Plural[category]; ok {
+		return text
+	}
+	return e.Plural[PluralOther]
+}
+
+// Texts returns every form of the entry.
+func (e StringEntryDef) Texts() []string {
+	if e.Plural == nil {
+		return []string{e.Text}
+	}
+	texts := make([]string, 0, len(e.Plural))
+	for _, category := range pluralCategoryOrder {
+		if text, ok := e.Plural[category]; ok {
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+var pluralCategoryOrder = []PluralCategory{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther}
+
+// LocaleLanguage returns the language part of a locale tag, so "pt-BR" and
+// "pt_BR" both give "pt".
+func LocaleLanguage(locale string) string {
+	language, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	return strings.ToLower(language)
+}
+
+// PluralCategories lists the plural forms a locale uses for whole numbers,
+// in gettext msgstr[n] order.
+func PluralCategories(locale string) []PluralCategory {
+	switch LocaleLanguage(locale) {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms", "lo", "my", "km":
+		return []PluralCategory{PluralOther}
+	case "ru", "uk", "be", "pl":
+		return []PluralCategory{PluralOne, PluralFew, PluralMany}
+	case "cs", "sk":
+		return []PluralCategory{PluralOne, PluralFew, PluralOther}
+	case "ar":
+		return []PluralCategory{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther}
+	default:
+		return []PluralCategory{PluralOne, PluralOther}
+	}
+}
+
+// PluralCategoryFor picks the plural form of n in a locale. Languages
+// without a rule of their own use the English one/other rule.
+func PluralCategoryFor(locale string, n int) PluralCategory {
+	if n < 0 {
+		n = -n
+	}
+	mod10, mod100 := n%10, n%100
+	switch LocaleLanguage(locale) {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms", "lo", "my", "km":
+		return PluralOther
+	case "fr", "pt":
+		if n <= 1 {
+			return PluralOne
+		}
+		return PluralOther
+	case "ru", "uk", "be":
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return PluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PluralFew
+		default:
+			return PluralMany
+		}
+	case "pl":
+		switch {
+		case n == 1:
+			return PluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PluralFew
+		default:
+			return PluralMany
+		}
+	case "cs", "sk":
+		switch {
+		case n == 1:
+			return PluralOne
+		case n >= 2 && n <= 4:
+			return PluralFew
+		default:
+			return PluralOther
+		}
+	case "ar":
+		switch {
+		case n == 0:
+			return PluralZero
+		case n == 1:
+			return PluralOne
+		case n == 2:
+			return PluralTwo
+		case mod100 >= 3 && mod100 <= 10:
+			return PluralFew
+		case mod100 >= 11:
+			return PluralMany
+		default:
+			return PluralOther
+		}
+	default:
+		if n == 1 {
+			return PluralOne
+		}
+		return PluralOther
+	}
+}
+
+// StringPlaceholders returns the {name} placeholders in text in order of
+// first use. "{{" and "}}" are literal braces.
+func StringPlaceholders(text string) ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '{':
+			if i+1 < len(text) && text[i+1] == '{' {
+				i++
+				continue
+			}
+			end := strings.IndexByte(text[i:], '}')
+			if end < 0 {
+				return names, fmt.Errorf("unclosed placeholder in %q", text)
+			}
+			name := text[i+1 : i+end]
+			if !isStringPlaceholderName(name) {
+				return names, fmt.Errorf("invalid placeholder {%s} in %q", name, text)
+			}
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+			i += end
+		case '}':
+			if i+1 < len(text) && text[i+1] == '}' {
+				i++
+				continue
+			}
+			return names, fmt.Errorf("unmatched } in %q", text)
+		}
+	}
+	return names, nil
+}
+
+func isStringPlaceholderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r != '_' && (r < '0' || r > '9') && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,237 @@
+package content
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func SaveStringTable(path string, def *StringTableDef) error {
+	if def == nil {
+		return fmt.Errorf("string table is nil")
+	}
+	if def.SchemaVersion != CurrentStringTableSchemaVersion {
+		return fmt.Errorf("unsupported schema version %d", def.SchemaVersion)
+	}
+
+	data, err := json.MarshalIndent(def, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadStringTable reads a JSON string table, or a gettext PO file when the
+// path ends in .po.
+func LoadStringTable(path string) (*StringTableDef, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".po") {
+		def, err := ParseStringTablePO(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return def, nil
+	}
+
+	var def StringTableDef
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, err
+	}
+
+	if def.SchemaVersion != CurrentStringTableSchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d", def.SchemaVersion)
+	}
+
+	return &def, nil
+}
+
+// LoadStringTableDir loads every .json and .po string table in dir, sorted by
+// file name.
+func LoadStringTableDir(dir string) ([]*StringTableDef, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if !entry.IsDir() && (ext == ".json" || ext == ".po") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	tables := make([]*StringTableDef, 0, len(names))
+	for _, name := range names {
+		def, err := LoadStringTable(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, def)
+	}
+	return tables, nil
+}
+
+// ParseStringTablePO reads a gettext PO file. An entry's msgctxt is its key
+// when present, otherwise its msgid. The locale comes from the header's
+// Language field and the fallback from X-Fallback-Language. msgstr[n] forms
+// map to the locale's plural categories in order. Untranslated and fuzzy
+// entries are skipped so they show up as missing keys.
+func ParseStringTablePO(data []byte) (*StringTableDef, error) {
+	def := &StringTableDef{
+		SchemaVersion: CurrentStringTableSchemaVersion,
+		Strings:       make(map[string]StringEntryDef),
+	}
+
+	var entries []poEntry
+	current := poEntry{}
+	var target *string
+	started := false
+	flush := func() {
+		if started {
+			entries = append(entries, current)
+		}
+		current = poEntry{}
+		target = nil
+		started = false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			flush()
+			continue
+		case strings.HasPrefix(line, "#,"):
+			if started && current.msgid != nil {
+				flush()
+			}
+			current.fuzzy = current.fuzzy || strings.Contains(line, "fuzzy")
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, `"`):
+			if target == nil {
+				return nil, fmt.Errorf("line %d: string without a keyword", lineNumber)
+			}
+			text, err := strconv.Unquote(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			*target += text
+			continue
+		}
+
+		keyword, quoted, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected a keyword and a string", lineNumber)
+		}
+		text, err := strconv.Unquote(strings.TrimSpace(quoted))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if (keyword == "msgctxt" || keyword == "msgid") && current.msgid != nil {
+			flush()
+		}
+		started = true
+		switch {
+		case keyword == "msgctxt":
+			current.msgctxt = &text
+			target = current.msgctxt
+		case keyword == "msgid":
+			current.msgid = &text
+			target = current.msgid
+		case keyword == "msgid_plural":
+			current.plural = true
+			target = new(string)
+		case keyword == "msgstr":
+			current.msgstr = append(current.msgstr, text)
+			target = &current.msgstr[len(current.msgstr)-1]
+		case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+			index, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+			if err != nil || index != len(current.msgstr) {
+				return nil, fmt.Errorf("line %d: msgstr index out of order", lineNumber)
+			}
+			current.msgstr = append(current.msgstr, text)
+			target = &current.msgstr[index]
+		default:
+			return nil, fmt.Errorf("line %d: unknown keyword %q", lineNumber, keyword)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	for _, entry := range entries {
+		if entry.msgid != nil && *entry.msgid == "" && entry.msgctxt == nil && len(entry.msgstr) > 0 {
+			def.Locale, def.Fallback = parsePOHeader(entry.msgstr[0])
+		}
+	}
+	categories := PluralCategories(def.Locale)
+	for _, entry := range entries {
+		if entry.msgid == nil || entry.fuzzy {
+			continue
+		}
+		key := *entry.msgid
+		if entry.msgctxt != nil {
+			key = *entry.msgctxt
+		}
+		if key == "" {
+			continue
+		}
+		if !entry.plural {
+			if len(entry.msgstr) > 0 && entry.msgstr[0] != "" {
+				def.Strings[key] = StringEntryDef{Text: entry.msgstr[0]}
+			}
+			continue
+		}
+		forms := make(map[PluralCategory]string)
+		for i, text := range entry.msgstr {
+			if i < len(categories) && text != "" {
+				forms[categories[i]] = text
+			}
+		}
+		if len(forms) > 0 {
+			def.Strings[key] = StringEntryDef{Plural: forms}
+		}
+	}
+	return def, nil
+}
+
+type poEntry struct {
+	msgctxt *string
+	msgid   *string
+	plural  bool
+	msgstr  []string
+	fuzzy   bool
+}
+
+func parsePOHeader(header string) (string, string) {
+	var locale, fallback string
+	for _, line := range strings.Split(header, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(name) {
+		case "Language":
+			locale = strings.TrimSpace(value)
+		case "X-Fallback-Language":
+			fallback = strings.TrimSpace(value)
+		}
+	}
+	return locale, fallback
+}
@@ -0,0 +1,173 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func assertHasStringTableIssue(t *testing.T, result StringTableValidationResult, code string, key string) {
+	t.Helper()
+	for _, issue := range result.Issues {
+		if issue.Code == code && issue.Key == key {
+			return
+		}
+	}
+	t.Fatalf("expected %s issue for %q, got %+v", code, key, result.Issues)
+}
+
+func TestStringTableRoundTripsPlainAndPluralEntries(t *testing.T) {
+	def := &StringTableDef{
+		SchemaVersion: CurrentStringTableSchemaVersion,
+		Locale:        "en",
+		Strings: map[string]StringEntryDef{
+			"menu.play":   {Text: "Play"},
+			"items.count": {Plural: map[PluralCategory]string{PluralOne: "{count} item", PluralOther: "{count} items"}},
+		},
+	}
+	dir := t.TempDir()
+	if err := SaveStringTable(filepath.Join(dir, "en.json"), def); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	tables, err := LoadStringTableDir(dir)
+	if err != nil || len(tables) != 1 {
+		t.Fatalf("load: %v %d", err, len(tables))
+	}
+	loaded := tables[0]
+	if result := ValidateStringTable(loaded); result.HasErrors() {
+		t.Fatalf("expected a valid table, got %v", result.Issues)
+	}
+	if got := loaded.Strings["menu.play"].Form(PluralOther); got != "Play" {
+		t.Fatalf("expected plain text, got %q", got)
+	}
+	if got := loaded.Strings["items.count"].Form(PluralOne); got != "{count} item" {
+		t.Fatalf("expected the one form, got %q", got)
+	}
+	if got := loaded.Strings["items.count"].Form(PluralFew); got != "{count} items" {
+		t.Fatalf("expected a missing form to use other, got %q", got)
+	}
+}
+
+func TestParseStringTablePOMapsPluralFormsAndSkipsUntranslated(t *testing.T) {
+	po := `# Russian strings
+msgid ""
+msgstr ""
+"Language: ru\n"
+"X-Fallback-Language: en\n"
+
+msgctxt "menu.play"
+msgid "Play"
+msgstr "Играть"
+
+msgctxt "items.count"
+msgid "{count} item"
+msgid_plural "{count} items"
+msgstr[0] "{count} предмет"
+msgstr[1] "{count} предмета"
+msgstr[2] "{count} "
+"предметов"
+
+#, fuzzy
+msgctxt "menu.quit"
+msgid "Quit"
+msgstr "Выйти"
+
+msgid "Options"
+msgstr ""
+`
+	path := filepath.Join(t.TempDir(), "ru.po")
+	if err := os.WriteFile(path, []byte(po), 0644); err != nil {
+		t.Fatal(err)
+	}
+	def, err := LoadStringTable(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if def.Locale != "ru" || def.Fallback != "en" {
+		t.Fatalf("expected the header locale and fallback, got %q %q", def.Locale, def.Fallback)
+	}
+	if got := def.Strings["menu.play"].Text; got != "Играть" {
+		t.Fatalf("expected msgctxt to be the key, got %q", got)
+	}
+	entry := def.Strings["items.count"]
+	if entry.Plural[PluralMany] != "{count} предметов" || entry.Plural[PluralFew] != "{count} предмета" {
+		t.Fatalf("expected msgstr[n] to map to ru categories, got %+v", entry.Plural)
+	}
+	if _, ok := def.Strings["menu.quit"]; ok {
+		t.Fatalf("expected fuzzy entries to be skipped")
+	}
+	if _, ok := def.Strings["Options"]; ok {
+		t.Fatalf("expected untranslated entries to be skipped")
+	}
+}
+
+func TestPluralCategoryFor(t *testing.T) {
+	cases := []struct {
+		locale string
+		n      int
+		want   PluralCategory
+	}{
+		{"en", 1, PluralOne}, {"en-US", 0, PluralOther}, {"fr", 0, PluralOne}, {"ja", 1, PluralOther},
+		{"ru", 21, PluralOne}, {"ru", 3, PluralFew}, {"ru", 12, PluralMany}, {"pl", 22, PluralFew}, {"pl", 21, PluralMany},
+		{"cs", 4, PluralFew}, {"ar", 2, PluralTwo}, {"ar", 105, PluralFew}, {"ar", 111, PluralMany}, {"ar", 100, PluralOther},
+	}
+	for _, tc := range cases {
+		if got := PluralCategoryFor(tc.locale, tc.n); got != tc.want {
+			t.Fatalf("%s %d: expected %s, got %s", tc.locale, tc.n, tc.want, got)
+		}
+	}
+}
+
+func TestCompareStringTablesReportsMissingKeysFormsAndPlaceholders(t *testing.T) {
+	reference := &StringTableDef{Locale: "en", Strings: map[string]StringEntryDef{
+		"menu.play":   {Text: "Play"},
+		"hud.ammo":    {Text: "Ammo: {current}/{max}"},
+		"items.count": {Plural: map[PluralCategory]string{PluralOne: "{count} item", PluralOther: "{count} items"}},
+		"hud.score":   {Text: "Score {score}"},
+	}}
+	table := &StringTableDef{Locale: "pl", Strings: map[string]StringEntryDef{
+		"hud.ammo":    {Text: "Amunicja: {current}"},
+		"items.count": {Plural: map[PluralCategory]string{PluralOne: "{count} przedmiot", PluralFew: "{count} przedmioty"}},
+		"hud.score":   {Text: "Wynik {points}"},
+	}}
+
+	result := CompareStringTables(reference, table)
+	assertHasStringTableIssue(t, result, "missing_key", "menu.play")
+	assertHasStringTableIssue(t, result, "missing_placeholder", "hud.ammo")
+	assertHasStringTableIssue(t, result, "missing_plural_form", "items.count")
+	assertHasStringTableIssue(t, result, "unknown_placeholder", "hud.score")
+	if got := result.Error(); got != `pl "hud.ammo" drops placeholder {max}` {
+		t.Fatalf("expected the result to describe its first error, got %q", got)
+	}
+
+	invalid := ValidateStringTable(&StringTableDef{Strings: map[string]StringEntryDef{"a": {Text: "{broken"}}})
+	assertHasStringTableIssue(t, invalid, "empty_locale", "")
+	assertHasStringTableIssue(t, invalid, "invalid_placeholder", "a")
+}
+
+func TestCompareStringTablesWarnsForKeysInheritedThroughFallbacks(t *testing.T) {
+	reference := &StringTableDef{Locale: "en", Strings: map[string]StringEntryDef{
+		"menu.play": {Text: "Play"},
+		"menu.quit": {Text: "Quit"},
+		"menu.back": {Text: "Back"},
+	}}
+	pt := &StringTableDef{Locale: "pt", Strings: map[string]StringEntryDef{
+		"menu.play": {Text: "Jogar"},
+		"menu.quit": {Text: "Sair"},
+	}}
+	ptBR := &StringTableDef{Locale: "pt-BR", Fallback: "pt", Strings: map[string]StringEntryDef{
+		"menu.play": {Text: "Jogar"},
+	}}
+	tables := []*StringTableDef{reference, pt, ptBR}
+
+	fallbacks := StringTableFallbacks(tables, ptBR, reference)
+	if len(fallbacks) != 1 || fallbacks[0] != pt {
+		t.Fatalf("expected pt-BR to fall back to pt only, got %v", fallbacks)
+	}
+	result := CompareStringTables(reference, ptBR, fallbacks...)
+	assertHasStringTableIssue(t, result, "inherited_key", "menu.quit")
+	assertHasStringTableIssue(t, result, "missing_key", "menu.back")
+	if result.HardErrorCount != 1 {
+		t.Fatalf("expected only the key no table provides to be an error, got %+v", result.Issues)
+	}
+}
@@ -0,0 +1,230 @@
+package content
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type StringTableValidationSeverity string
+
+const (
+	StringTableValidationSeverityError   StringTableValidationSeverity = "error"
+	StringTableValidationSeverityWarning StringTableValidationSeverity = "warning"
+)
+
+type StringTableValidationIssue struct {
+	Severity StringTableValidationSeverity `json:"severity"`
+	Code     string                        `json:"code"`
+	Message  string                        `json:"message"`
+	Locale   string                        `json:"locale,omitempty"`
+	Key      string                        `json:"key,omitempty"`
+}
+
+type StringTableValidationResult struct {
+	Issues         []StringTableValidationIssue `json:"issues,omitempty"`
+	HardErrorCount int                          `json:"hard_error_count"`
+}
+
+func (r StringTableValidationResult) HasErrors() bool {
+	return r.HardErrorCount > 0
+}
+
+func (r StringTableValidationResult) FirstError() (StringTableValidationIssue, bool) {
+	for _, issue := range r.Issues {
+		if issue.Severity == StringTableValidationSeverityError {
+			return issue, true
+		}
+	}
+	return StringTableValidationIssue{}, false
+}
+
+func (r StringTableValidationResult) Error() string {
+	if issue, ok := r.FirstError(); ok {
+		return issue.Message
+	}
+	return ""
+}
+
+func (r *StringTableValidationResult) addError(code string, message string, locale string, key string) {
+	r.Issues = append(r.Issues, StringTableValidationIssue{
+		Severity: StringTableValidationSeverityError,
+		Code:     code,
+		Message:  message,
+		Locale:   locale,
+		Key:      key,
+	})
+	r.HardErrorCount++
+}
+
+func (r *StringTableValidationResult) addWarning(code string, message string, locale string, key string) {
+	r.Issues = append(r.Issues, StringTableValidationIssue{
+		Severity: StringTableValidationSeverityWarning,
+		Code:     code,
+		Message:  message,
+		Locale:   locale,
+		Key:      key,
+	})
+}
+
+// ValidateStringTable checks one table on its own: locale, keys, plural
+// categories and placeholder syntax.
+func ValidateStringTable(def *StringTableDef) StringTableValidationResult {
+	result := StringTableValidationResult{}
+	if def == nil {
+		result.addError("nil_table", "string table is nil", "", "")
+		return result
+	}
+	if strings.TrimSpace(def.Locale) == "" {
+		result.addError("empty_locale", "string table locale is required", "", "")
+	}
+	if def.Fallback != "" && def.Fallback == def.Locale {
+		result.addError("self_fallback", fmt.Sprintf("locale %q falls back to itself", def.Locale), def.Locale, "")
+	}
+	for _, key := range sortedStringKeys(def.Strings) {
+		entry := def.Strings[key]
+		if strings.TrimSpace(key) == "" {
+			result.addError("empty_key", "string key is required", def.Locale, key)
+			continue
+		}
+		if entry.Plural != nil {
+			for category := range entry.Plural {
+				if !slices.Contains(pluralCategoryOrder, category) {
+					result.addError("unknown_plural_category", fmt.Sprintf("%q has unknown plural category %q", key, category), def.Locale, key)
+				}
+			}
+			if _, ok := entry.Plural[PluralOther]; !ok && !pluralFormsCovered(def.Locale, entry) {
+				result.addError("missing_plural_other", fmt.Sprintf("%q needs an %q form", key, PluralOther), def.Locale, key)
+			}
+		}
+		for _, text := range entry.Texts() {
+			if _, err := StringPlaceholders(text); err != nil {
+				result.addError("invalid_placeholder", fmt.Sprintf("%q: %v", key, err), def.Locale, key)
+			}
+		}
+	}
+	return result
+}
+
+// CompareStringTables reports keys of reference that table is missing, plural
+// forms the table's locale needs but does not define, and placeholders the
+// translation drops or invents. A missing key that one of fallbacks provides
+// is only a warning, inherited_key; see StringTableFallbacks.
+func CompareStringTables(reference *StringTableDef, table *StringTableDef, fallbacks ...*StringTableDef) StringTableValidationResult {
+	result := StringTableValidationResult{}
+	if reference == nil || table == nil {
+		result.addError("nil_table", "string table is nil", "", "")
+		return result
+	}
+	for _, key := range sortedStringKeys(reference.Strings) {
+		want := reference.Strings[key]
+		got, ok := table.Strings[key]
+		if !ok {
+			if from := stringTableProviding(fallbacks, key); from != nil {
+				result.addWarning("inherited_key", fmt.Sprintf("%s inherits %q from %s", table.Locale, key, from.Locale), table.Locale, key)
+				continue
+			}
+			result.addError("missing_key", fmt.Sprintf("%s is missing %q", table.Locale, key), table.Locale, key)
+			continue
+		}
+		if want.Plural != nil || got.Plural != nil {
+			var missing []string
+			for _, category := range PluralCategories(table.Locale) {
+				if _, ok := got.Plural[category]; !ok {
+					missing = append(missing, string(category))
+				}
+			}
+			if len(missing) > 0 {
+				result.addError("missing_plural_form", fmt.Sprintf("%s %q is missing plural forms %s", table.Locale, key, strings.Join(missing, ", ")), table.Locale, key)
+			}
+		}
+		wantNames := entryPlaceholders(want)
+		gotNames := entryPlaceholders(got)
+		for _, name := range wantNames {
+			if !slices.Contains(gotNames, name) && name != "count" {
+				result.addError("missing_placeholder", fmt.Sprintf("%s %q drops placeholder {%s}", table.Locale, key, name), table.Locale, key)
+			}
+		}
+		for _, name := range gotNames {
+			if !slices.Contains(wantNames, name) && name != "count" {
+				result.addError("unknown_placeholder", fmt.Sprintf("%s %q uses placeholder {%s} the reference does not", table.Locale, key, name), table.Locale, key)
+			}
+		}
+	}
+	return result
+}
+
+// StringTableFallbacks returns the tables consulted for keys table lacks, in
+// the order Localization tries them: the Fallback links, then the locale's
+// language. The reference table ends every chain at runtime and is left out.
+func StringTableFallbacks(tables []*StringTableDef, table *StringTableDef, reference *StringTableDef) []*StringTableDef {
+	var chain []*StringTableDef
+	seen := map[*StringTableDef]bool{table: true, reference: true}
+	var add func(locale string)
+	add = func(locale string) {
+		for _, candidate := range tables {
+			if candidate == nil || seen[candidate] || !sameLocale(candidate.Locale, locale) {
+				continue
+			}
+			seen[candidate] = true
+			chain = append(chain, candidate)
+			add(candidate.Fallback)
+			return
+		}
+	}
+	if table != nil {
+		add(table.Fallback)
+		add(LocaleLanguage(table.Locale))
+	}
+	return chain
+}
+
+func stringTableProviding(tables []*StringTableDef, key string) *StringTableDef {
+	for _, table := range tables {
+		if _, ok := table.Strings[key]; ok {
+			return table
+		}
+	}
+	return nil
+}
+
+func sameLocale(a, b string) bool {
+	normalize := func(locale string) string {
+		return strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	}
+	return b != "" && strings.EqualFold(normalize(a), normalize(b))
+}
+
+// pluralFormsCovered reports whether a plural entry defines every category
+// its locale uses, in which case it does not need an other form.
+func pluralFormsCovered(locale string, entry StringEntryDef) bool {
+	for _, category := range PluralCategories(locale) {
+		if _, ok := entry.Plural[category]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func entryPlaceholders(entry StringEntryDef) []string {
+	var names []string
+	for _, text := range entry.Texts() {
+		found, _ := StringPlaceholders(text)
+		for _, name := range found {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+func sortedStringKeys(entries map[string]StringEntryDef) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,189 @@
+package content
+
+const CurrentUiDocumentSchemaVersion = 1
+
+type UiElementType string
+
+const (
+	UiElementPanel       UiElementType = "panel"
+	UiElementColumn      UiElementType = "column"
+	UiElementRow         UiElementType = "row"
+	UiElementZStack      UiElementType = "zstack"
+	UiElementSpacer      UiElementType = "spacer"
+	UiElementLabel       UiElementType = "label"
+	UiElementButton      UiElementType = "button"
+	UiElementCheckbox    UiElementType = "checkbox"
+	UiElementSlider      UiElementType = "slider"
+	UiElementProgress    UiElementType = "progress"
+	UiElementTextField   UiElementType = "text_field"
+	UiElementNumberField UiElementType = "number_field"
+	UiElementSelect      UiElementType = "select"
+	UiElementDropdown    UiElementType = "dropdown"
+	UiElementList        UiElementType = "list"
+	UiElementTabs        UiElementType = "tabs"
+	UiElementTab         UiElementType = "tab"
+	UiElementTooltip     UiElementType = "tooltip"
+	UiElementSlot        UiElementType = "slot"
+)
+
+// Bindable element properties. Value, checked and selected bindings are
+// two-way: widget changes are written back to the bound field.
+const (
+	UiBindText     = "text"
+	UiBindValue    = "value"
+	UiBindChecked  = "checked"
+	UiBindSelected = "selected"
+	UiBindOptions  = "options"
+	UiBindMin      = "min"
+	UiBindMax      = "max"
+	UiBindColor    = "color"
+	UiBindVisible  = "visible"
+)
+
+// Binding path prefixes. "resource:PlayerStats.Health" reads a field of the
+// ECS resource whose type is PlayerStats; "component:Health.Current" reads a
+// component on the entity that owns the document.
+const (
+	UiBindingResourcePrefix  = "resource:"
+	UiBindingComponentPrefix = "component:"
+)
+
+// UiDocumentDef is a declarative UI tree. The runtime compiles it to a
+// retained UiPanel every frame.
+type UiDocumentDef struct {
+	SchemaVersion int                   `json:"schema_version"`
+	Name          string                `json:"name"`
+	Theme         string                `json:"theme,omitempty"`
+	Styles        map[string]UiStyleDef `json:"styles,omitempty"`
+	Themes        map[string]UiThemeDef `json:"themes,omitempty"`
+	Root          UiElementDef          `json:"root"`
+}
+
+// UiThemeDef replaces style properties while the theme is active. Properties
+// the theme leaves unset keep their document value.
+type UiThemeDef struct {
+	Styles map[string]UiStyleDef `json:"styles,omitempty"`
+}
+
+// UiStyleDef holds presentation properties. Nil fields are unset, so styles,
+// themes and element overrides can be layered.
+type UiStyleDef struct {
+	Width      *float32    `json:"width,omitempty"`
+	Scale      *float32    `json:"scale,omitempty"`
+	Padding    *float32    `json:"padding,omitempty"`
+	Spacing    *float32    `json:"spacing,omitempty"`
+	Color      *[4]float32 `json:"color,omitempty"`
+	BgColor    *[4]float32 `json:"bg_color,omitempty"`
+	TextColor  *[4]float32 `json:"text_color,omitempty"`
+	Align      string      `json:"align,omitempty"`
+	Borderless *bool       `json:"borderless,omitempty"`
+}
+
+// UiElementDef is one node of a UI document. Its inline style properties
+// override the named Style.
+type UiElementDef struct {
+	Type  UiElementType `json:"type"`
+	Key   string        `json:"key,omitempty"`
+	Style string        `json:"style,omitempty"`
+	UiStyleDef
+	Text        string            `json:"text,omitempty"`
+	Placeholder string            `json:"placeholder,omitempty"`
+	Options     []string          `json:"options,omitempty"`
+	Value       float32           `json:"value,omitempty"`
+	Min         float32           `json:"min,omitempty"`
+	Max         float32           `json:"max,omitempty"`
+	Step        float32           `json:"step,omitempty"`
+	Precision   int               `json:"precision,omitempty"`
+	Checked     bool              `json:"checked,omitempty"`
+	Rows        int               `json:"rows,omitempty"`
+	Height      float32           `json:"height,omitempty"`
+	Anchor      string            `json:"anchor,omitempty"`
+	Position    [2]float32        `json:"position,omitempty"`
+	MaxHeight   float32           `json:"max_height,omitempty"`
+	Modal       bool              `json:"modal,omitempty"`
+	Navigable   bool              `json:"navigable,omitempty"`
+	Slot        string            `json:"slot,omitempty"`
+	Action      string            `json:"action,omitempty"`
+	Bind        map[string]string `json:"bind,omitempty"`
+	Children    []UiElementDef    `json:"children,omitempty"`
+}
+
+// ResolveStyle layers the element's named style, the theme's override of
+// that style and the element's inline properties.
+func (def *UiDocumentDef) ResolveStyle(element *UiElementDef, theme string) UiStyleDef {
+	var style UiStyleDef
+	if def == nil || element == nil {
+		return style
+	}
+	if element.Style != "" {
+		style = mergeUiStyle(style, def.Styles[element.Style])
+		if themeDef, ok := def.Themes[theme]; ok {
+			style = mergeUiStyle(style, themeDef.Styles[element.Style])
+		}
+	}
+	return mergeUiStyle(style, element.UiStyleDef)
+}
+
+func mergeUiStyle(base, over UiStyleDef) UiStyleDef {
+	if over.Width != nil {
+		base.Width = over.Width
+	}
+	if over.Scale != nil {
+		base.Scale = over.Scale
+	}
+	if over.Padding != nil {
+		base.Padding = over.Padding
+	}
+	if over.Spacing != nil {
+		base.Spacing = over.Spacing
+	}
+	if over.Color != nil {
+		base.Color = over.Color
+	}
+	if over.BgColor != nil {
+		base.BgColor = over.BgColor
+	}
+	if over.TextColor != nil {
+		base.TextColor = over.TextColor
+	}
+	if over.Align != "" {
+		base.Align = over.Align
+	}
+	if over.Borderless != nil {
+		base.Borderless = over.Borderless
+	}
+	return base
+}
+
+func isUiContainerElement(elementType UiElementType) bool {
+	switch elementType {
+	case UiElementPanel, UiElementColumn, UiElementRow, UiElementZStack, UiElementTabs, UiElementTab, UiElementTooltip:
+		return true
+	default:
+		return false
+	}
+}
+
+// uiElementBindings lists the properties each element type can bind besides
+// visible.
+var uiElementBindings = map[UiElementType][]string{
+	UiElementPanel:       {UiBindText},
+	UiElementColumn:      nil,
+	UiElementRow:         nil,
+	UiElementZStack:      nil,
+	UiElementSpacer:      nil,
+	UiElementLabel:       {UiBindText, UiBindColor},
+	UiElementButton:      {UiBindText},
+	UiElementCheckbox:    {UiBindText, UiBindChecked},
+	UiElementSlider:      {UiBindValue, UiBindMin, UiBindMax},
+	UiElementProgress:    {UiBindText, UiBindValue, UiBindMin, UiBindMax},
+	UiElementTextField:   {UiBindValue},
+	UiElementNumberField: {UiBindValue},
+	UiElementSelect:      {UiBindSelected, UiBindOptions},
+	UiElementDropdown:    {UiBindSelected, UiBindOptions},
+	UiElementList:        {UiBindSelected, UiBindOptions},
+	UiElementTabs:        {UiBindSelected},
+	UiElementTab:         {UiBindText},
+	UiElementTooltip:     {UiBindText},
+	UiElementSlot:        nil,
+}
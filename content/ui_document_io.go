@@ -0,0 +1,40 @@
+package content
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+func SaveUiDocument(path string, def *UiDocumentDef) error {
+	if def == nil {
+		return fmt.Errorf("ui document is nil")
+	}
+	if def.SchemaVersion != CurrentUiDocumentSchemaVersion {
+		return fmt.Errorf("unsupported schema version %d", def.SchemaVersion)
+	}
+
+	data, err := json.MarshalIndent(def, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func LoadUiDocument(path string) (*UiDocumentDef, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var def UiDocumentDef
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, err
+	}
+
+	if def.SchemaVersion != CurrentUiDocumentSchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d", def.SchemaVersion)
+	}
+
+	return &def, nil
+}
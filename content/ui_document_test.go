@@ -0,0 +1,126 @@
+package content
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func assertHasUiDocumentIssue(t *testing.T, result UiDocumentValidationResult, code string, elementPath string) {
+	t.Helper()
+	for _, issue := range result.Issues {
+		if issue.Code == code && issue.ElementPath == elementPath {
+			return
+		}
+	}
+	t.Fatalf("expected %s issue at %q, got %+v", code, elementPath, result.Issues)
+}
+
+func TestUiDocumentRoundTripsAndResolvesThemedStyles(t *testing.T) {
+	small, large := float32(0.8), float32(1.4)
+	yellow := [4]float32{1, 1, 0, 1}
+	def := &UiDocumentDef{
+		SchemaVersion: CurrentUiDocumentSchemaVersion,
+		Name:          "hud",
+		Styles:        map[string]UiStyleDef{"caption": {Scale: &small, Color: &yellow}},
+		Themes:        map[string]UiThemeDef{"large": {Styles: map[string]UiStyleDef{"caption": {Scale: &large}}}},
+		Root: UiElementDef{Type: UiElementPanel, Children: []UiElementDef{
+			{Type: UiElementLabel, Key: "hp", Style: "caption", Bind: map[string]string{UiBindText: "resource:PlayerStats.Health"}},
+		}},
+	}
+	path := filepath.Join(t.TempDir(), "hud.ui.json")
+	if err := SaveUiDocument(path, def); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, err := LoadUiDocument(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if result := ValidateUiDocument(loaded); result.HasErrors() {
+		t.Fatalf("expected a valid document, got %v", result.Issues)
+	}
+
+	label := &loaded.Root.Children[0]
+	if style := loaded.ResolveStyle(label, ""); *style.Scale != small || *style.Color != yellow {
+		t.Fatalf("unexpected base style %+v", style)
+	}
+	if style := loaded.ResolveStyle(label, "large"); *style.Scale != large || *style.Color != yellow {
+		t.Fatalf("expected the theme to override only scale, got %+v", style)
+	}
+	label.Scale = &small
+	if style := loaded.ResolveStyle(label, "large"); *style.Scale != small {
+		t.Fatalf("expected inline properties to win over the theme, got %v", *style.Scale)
+	}
+}
+
+func TestValidateUiDocumentReportsElementIssues(t *testing.T) {
+	def := &UiDocumentDef{
+		SchemaVersion: CurrentUiDocumentSchemaVersion,
+		Name:          "menu",
+		Theme:         "missing",
+		Root: UiElementDef{Type: UiElementPanel, Children: []UiElementDef{
+			{Type: "banner"},
+			{Type: UiElementLabel, Key: "title", Style: "heading", Children: []UiElementDef{{Type: UiElementLabel}}},
+			{Type: UiElementButton, Key: "title", Bind: map[string]string{UiBindChecked: "resource:Menu.Open"}},
+			{Type: UiElementSlider, Key: "volume", Min: 1, Max: 1, Bind: map[string]string{UiBindValue: "Settings.Volume"}},
+			{Type: UiElementSlot, Slot: "extra"},
+			{Type: UiElementColumn, Key: "more", Children: []UiElementDef{{Type: UiElementSlot, Slot: "extra"}, {Type: UiElementPanel}}},
+			{Type: UiElementTabs, Key: "pages", Children: []UiElementDef{{Type: UiElementLabel}}},
+		}},
+	}
+
+	result := ValidateUiDocument(def)
+	assertHasUiDocumentIssue(t, result, "unknown_theme", "")
+	assertHasUiDocumentIssue(t, result, "unknown_element_type", "root/0")
+	assertHasUiDocumentIssue(t, result, "unknown_style", "root/title")
+	assertHasUiDocumentIssue(t, result, "unexpected_children", "root/title")
+	assertHasUiDocumentIssue(t, result, "duplicate_key", "root/title")
+	assertHasUiDocumentIssue(t, result, "invalid_bind_property", "root/title")
+	assertHasUiDocumentIssue(t, result, "invalid_range", "root/volume")
+	assertHasUiDocumentIssue(t, result, "invalid_binding", "root/volume")
+	assertHasUiDocumentIssue(t, result, "duplicate_slot", "root/more/0")
+	assertHasUiDocumentIssue(t, result, "nested_panel", "root/more/1")
+	assertHasUiDocumentIssue(t, result, "invalid_tab", "root/pages/0")
+	if got := result.Error(); got != `default theme "missing" is not defined` {
+		t.Fatalf("expected the result to describe its first error, got %q", got)
+	}
+}
+
+func TestValidateUiDocumentReportsMapIssuesInKeyOrder(t *testing.T) {
+	def := &UiDocumentDef{
+		SchemaVersion: CurrentUiDocumentSchemaVersion,
+		Name:          "menu",
+		Styles: map[string]UiStyleDef{
+			"c": {Align: "top"}, "a": {Align: "top"}, "b": {Align: "top"},
+		},
+		Themes: map[string]UiThemeDef{
+			"light": {Styles: map[string]UiStyleDef{"z": {}, "y": {}}},
+			"dark":  {Styles: map[string]UiStyleDef{"x": {}}},
+		},
+		Root: UiElementDef{Type: UiElementPanel, Children: []UiElementDef{
+			{Type: UiElementLabel, Bind: map[string]string{"value": "bad", UiBindVisible: "bad", "checked": "bad"}},
+		}},
+	}
+	want := ValidateUiDocument(def).Issues
+	if len(want) == 0 || want[0].Style != "a" {
+		t.Fatalf("expected the first issue to be for style a, got %+v", want)
+	}
+	for run := 0; run < 20; run++ {
+		if got := ValidateUiDocument(def).Issues; !slices.Equal(got, want) {
+			t.Fatalf("run %d reported issues in a different order:\n%+v\nwant\n%+v", run, got, want)
+		}
+	}
+}
+
+func TestValidateUiBindingPath(t *testing.T) {
+	for _, binding := range []string{"resource:PlayerStats.Health", "component:Inventory.Slots.0.Count"} {
+		if err := ValidateUiBindingPath(binding); err != nil {
+			t.Fatalf("expected %q to be valid, got %v", binding, err)
+		}
+	}
+	for _, binding := range []string{"PlayerStats.Health", "resource:PlayerStats", "component:Health..Current", "resource:Stats.Health-Max"} {
+		if err := ValidateUiBindingPath(binding); err == nil {
+			t.Fatalf("expected %q to be rejected", binding)
+		}
+	}
+}
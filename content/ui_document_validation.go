@@ -0,0 +1,257 @@
+package content
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+type UiDocumentValidationSeverity string
+
+const (
+	UiDocumentValidationSeverityError UiDocumentValidationSeverity = "error"
+)
+
+type UiDocumentValidationIssue struct {
+	Severity    UiDocumentValidationSeverity `json:"severity"`
+	Code        string                       `json:"code"`
+	Message     string                       `json:"message"`
+	ElementPath string                       `json:"element_path,omitempty"`
+	Style       string                       `json:"style,omitempty"`
+	Binding     string                       `json:"binding,omitempty"`
+}
+
+type UiDocumentValidationResult struct {
+	Issues         []UiDocumentValidationIssue `json:"issues,omitempty"`
+	HardErrorCount int                         `json:"hard_error_count"`
+}
+
+func (r UiDocumentValidationResult) HasErrors() bool {
+	return r.HardErrorCount > 0
+}
+
+func (r UiDocumentValidationResult) FirstError() (UiDocumentValidationIssue, bool) {
+	for _, issue := range r.Issues {
+		if issue.Severity == UiDocumentValidationSeverityError {
+			return issue, true
+		}
+	}
+	return UiDocumentValidationIssue{}, false
+}
+
+func (r UiDocumentValidationResult) Error() string {
+	if issue, ok := r.FirstError(); ok {
+		if issue.ElementPath != "" {
+			return issue.ElementPath + ": " + issue.Message
+		}
+		return issue.Message
+	}
+	return ""
+}
+
+func (r *UiDocumentValidationResult) addError(code string, message string, elementPath string, style string, binding string) {
+	r.Issues = append(r.Issues, UiDocumentValidationIssue{
+		Severity:    UiDocumentValidationSeverityError,
+		Code:        code,
+		Message:     message,
+		ElementPath: elementPath,
+		Style:       style,
+		Binding:     binding,
+	})
+	r.HardErrorCount++
+}
+
+// ValidateUiDocument checks element types, nesting, style and theme
+// references, slots and binding syntax. Binding targets are resolved against
+// live ECS types at runtime, so only their shape is checked here.
+func ValidateUiDocument(def *UiDocumentDef) UiDocumentValidationResult {
+	result := UiDocumentValidationResult{}
+	if def == nil {
+		result.addError("nil_document", "ui document is nil", "", "", "")
+		return result
+	}
+	if strings.TrimSpace(def.Name) == "" {
+		result.addError("empty_name", "ui document name is required", "", "", "")
+	}
+	if def.Theme != "" {
+		if _, ok := def.Themes[def.Theme]; !ok {
+			result.addError("unknown_theme", fmt.Sprintf("default theme %q is not defined", def.Theme), "", "", "")
+		}
+	}
+	for _, name := range sortedUiKeys(def.Styles) {
+		validateUiStyle(&result, def.Styles[name], "", name)
+	}
+	for _, themeName := range sortedUiKeys(def.Themes) {
+		theme := def.Themes[themeName]
+		for _, name := range sortedUiKeys(theme.Styles) {
+			if _, ok := def.Styles[name]; !ok {
+				result.addError("unknown_style", fmt.Sprintf("theme %q overrides undefined style %q", themeName, name), "", name, "")
+			}
+			validateUiStyle(&result, theme.Styles[name], "", name)
+		}
+	}
+
+	if def.Root.Type != UiElementPanel {
+		result.addError("invalid_root", "ui document root must be a panel", "root", "", "")
+	}
+	slots := map[string]struct{}{}
+	validateUiElement(&result, def, &def.Root, "root", slots)
+	return result
+}
+
+func validateUiElement(result *UiDocumentValidationResult, def *UiDocumentDef, element *UiElementDef, path string, slots map[string]struct{}) {
+	bindable, known := uiElementBindings[element.Type]
+	if !known {
+		result.addError("unknown_element_type", fmt.Sprintf("unsupported element type %q", element.Type), path, "", "")
+		return
+	}
+	if element.Style != "" {
+		if _, ok := def.Styles[element.Style]; !ok {
+			result.addError("unknown_style", fmt.Sprintf("style %q is not defined", element.Style), path, element.Style, "")
+		}
+	}
+	validateUiStyle(result, element.UiStyleDef, path, "")
+	if element.Anchor != "" && !isValidUiAnchor(element.Anchor) {
+		result.addError("invalid_anchor", fmt.Sprintf("unsupported anchor %q", element.Anchor), path, "", "")
+	}
+	if element.Type == UiElementSlider && element.Max <= element.Min && element.Bind[UiBindMax] == "" {
+		result.addError("invalid_range", "slider max must be greater than min", path, "", "")
+	}
+
+	if element.Type == UiElementSlot {
+		if strings.TrimSpace(element.Slot) == "" {
+			result.addError("empty_slot_name", "slot name is required", path, "", "")
+		} else if _, dup := slots[element.Slot]; dup {
+			result.addError("duplicate_slot", fmt.Sprintf("slot %q is declared more than once", element.Slot), path, "", "")
+		}
+		slots[element.Slot] = struct{}{}
+	} else if element.Slot != "" {
+		result.addError("unexpected_slot_name", "only slot elements take a slot name", path, "", "")
+	}
+	if element.Action != "" && !isUiActionElement(element.Type) {
+		result.addError("unexpected_action", fmt.Sprintf("%s elements do not run actions", element.Type), path, "", "")
+	}
+
+	for _, property := range sortedUiKeys(element.Bind) {
+		binding := element.Bind[property]
+		if property != UiBindVisible && !slices.Contains(bindable, property) {
+			result.addError("invalid_bind_property", fmt.Sprintf("%s elements cannot bind %q", element.Type, property), path, "", binding)
+			continue
+		}
+		if err := ValidateUiBindingPath(binding); err != nil {
+			result.addError("invalid_binding", err.Error(), path, "", binding)
+		}
+	}
+
+	if len(element.Children) > 0 && !isUiContainerElement(element.Type) {
+		result.addError("unexpected_children", fmt.Sprintf("%s elements cannot have children", element.Type), path, "", "")
+	}
+	if element.Type == UiElementTooltip && len(element.Children) != 1 {
+		result.addError("invalid_tooltip_child", "tooltip needs exactly one child", path, "", "")
+	}
+	keys := map[string]struct{}{}
+	for i := range element.Children {
+		child := &element.Children[i]
+		childPath := fmt.Sprintf("%s/%d", path, i)
+		if child.Key != "" {
+			childPath = path + "/" + child.Key
+			if _, dup := keys[child.Key]; dup {
+				result.addError("duplicate_key", fmt.Sprintf("key %q is used by more than one sibling", child.Key), childPath, "", "")
+			}
+			keys[child.Key] = struct{}{}
+		}
+		switch {
+		case child.Type == UiElementPanel:
+			result.addError("nested_panel", "panels can only be the document root", childPath, "", "")
+			continue
+		case element.Type == UiElementTabs && child.Type != UiElementTab:
+			result.addError("invalid_tab", "tabs children must be tab elements", childPath, "", "")
+			continue
+		case element.Type != UiElementTabs && child.Type == UiElementTab:
+			result.addError("invalid_tab", "tab elements must be inside tabs", childPath, "", "")
+			continue
+		}
+		validateUiElement(result, def, child, childPath, slots)
+	}
+}
+
+// sortedUiKeys returns the keys of m in order so issues are reported
+// deterministically.
+func sortedUiKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func validateUiStyle(result *UiDocumentValidationResult, style UiStyleDef, path string, name string) {
+	switch style.Align {
+	case "", "left", "center", "right":
+	default:
+		result.addError("invalid_align", fmt.Sprintf("unsupported align %q", style.Align), path, name, "")
+	}
+	if style.Scale != nil && *style.Scale <= 0 {
+		result.addError("invalid_scale", "scale must be positive", path, name, "")
+	}
+	if style.Width != nil && *style.Width < 0 {
+		result.addError("invalid_width", "width must not be negative", path, name, "")
+	}
+}
+
+// ValidateUiBindingPath checks that a binding names a resource or component
+// type followed by a dotted field path.
+func ValidateUiBindingPath(binding string) error {
+	var rest string
+	switch {
+	case strings.HasPrefix(binding, UiBindingResourcePrefix):
+		rest = strings.TrimPrefix(binding, UiBindingResourcePrefix)
+	case strings.HasPrefix(binding, UiBindingComponentPrefix):
+		rest = strings.TrimPrefix(binding, UiBindingComponentPrefix)
+	default:
+		return fmt.Errorf("binding %q must start with %q or %q", binding, UiBindingResourcePrefix, UiBindingComponentPrefix)
+	}
+	segments := strings.Split(rest, ".")
+	if len(segments) < 2 {
+		return fmt.Errorf("binding %q needs a type and a field path", binding)
+	}
+	for _, segment := range segments {
+		if !isUiBindingSegment(segment) {
+			return fmt.Errorf("binding %q has an invalid path segment %q", binding, segment)
+		}
+	}
+	return nil
+}
+
+func isUiBindingSegment(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	for _, r := range segment {
+		if r != '_' && (r < '0' || r > '9') && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isUiActionElement(elementType UiElementType) bool {
+	switch elementType {
+	case UiElementButton, UiElementCheckbox, UiElementSlider, UiElementTextField, UiElementNumberField,
+		UiElementSelect, UiElementDropdown, UiElementList, UiElementTabs:
+		return true
+	default:
+		return false
+	}
+}
+
+func isValidUiAnchor(anchor string) bool {
+	switch anchor {
+	case "top_left", "top_right", "bottom_left", "bottom_right", "top_center", "bottom_center", "center":
+		return true
+	default:
+		return false
+	}
+}
@@ -150,6 +150,10 @@ func ValidateAsset(def *AssetDef, opts AssetValidationOptions) AssetValidationRe
 		validatePartParent(&result, partIDs, allItemIDs, marker.ParentID, marker.ID, marker.Name, "marker")
 	}
 
+	if def.Skeleton != nil {
+		validateAssetSkeleton(&result, *def.Skeleton, partIDs)
+	}
+
 	visiting := make(map[string]bool, len(partParentByID))
 	visited := make(map[string]bool, len(partParentByID))
 	var visit func(string) bool
@@ -179,6 +183,33 @@ func ValidateAsset(def *AssetDef, opts AssetValidationOptions) AssetValidationRe
 	return result
 }
 
+func validateAssetSkeleton(result *AssetValidationResult, skeleton AssetSkeletonDef, partIDs map[string]struct{}) {
+	boneParts := make(map[string]struct{}, len(skeleton.Bones))
+	for i, bone := range skeleton.Bones {
+		if _, ok := partIDs[bone.PartID]; !ok {
+			result.addError("broken_bone_part", fmt.Sprintf("bone %s references missing part %s", bone.Name, bone.PartID), bone.PartID, bone.Name, "bone")
+		}
+		if _, dup := boneParts[bone.PartID]; dup {
+			result.addError("duplicate_bone_part", fmt.Sprintf("bone %s shares part %s with another bone", bone.Name, bone.PartID), bone.PartID, bone.Name, "bone")
+		}
+		boneParts[bone.PartID] = struct{}{}
+		if bone.Parent < -1 || bone.Parent >= i {
+			result.addError("invalid_bone_parent", fmt.Sprintf("bone %s parent %d must be -1 or an earlier bone", bone.Name, bone.Parent), bone.PartID, bone.Name, "bone")
+		}
+	}
+	for _, animation := range skeleton.Animations {
+		if animation.FPS < 0 {
+			result.addError("invalid_animation_fps", fmt.Sprintf("animation %s fps must be >= 0", animation.Name), "", animation.Name, "animation")
+		}
+		for frameIndex, frame := range animation.Frames {
+			if len(frame.Bones) != len(skeleton.Bones) {
+				result.addError("invalid_animation_frame", fmt.Sprintf("animation %s frame %d has %d bone poses, want %d", animation.Name, frameIndex, len(frame.Bones), len(skeleton.Bones)), "", animation.Name, "animation")
+				break
+			}
+		}
+	}
+}
+
 func (r *AssetValidationResult) addError(code string, message string, itemID string, itemName string, itemKind string) {
 	r.Issues = append(r.Issues, AssetValidationIssue{
 		Severity: AssetValidationSeverityError,
@@ -170,6 +170,40 @@ func TestValidateAssetAcceptsGroupPartSource(t *testing.T) {
 	}
 }
 
+func TestValidateAssetValidatesSkeleton(t *testing.T) {
+	def := NewAssetDef("skeleton")
+	group := AssetSourceDef{Kind: AssetSourceKindGroup}
+	def.Parts = []AssetPartDef{
+		{ID: "root", Name: "root", Source: group, Transform: identityTransform()},
+		{ID: "arm", Name: "arm", ParentID: "root", Source: group, Transform: identityTransform()},
+	}
+	def.Skeleton = &AssetSkeletonDef{
+		Bones: []AssetBoneDef{
+			{Name: "root", PartID: "root", Parent: 1},
+			{Name: "arm", PartID: "missing", Parent: 0},
+			{Name: "again", PartID: "root", Parent: 0},
+		},
+		Animations: []AssetAnimationDef{{
+			Name:   "idle",
+			FPS:    -1,
+			Frames: []AssetAnimationFrameDef{{Bones: make([]AssetBonePoseDef, 2)}},
+		}},
+	}
+
+	result := ValidateAsset(def, AssetValidationOptions{})
+	for _, code := range []string{"invalid_bone_parent", "broken_bone_part", "duplicate_bone_part", "invalid_animation_fps", "invalid_animation_frame"} {
+		assertHasValidationCode(t, result, code)
+	}
+
+	def.Skeleton.Bones = def.Skeleton.Bones[:2]
+	def.Skeleton.Bones[0].Parent = -1
+	def.Skeleton.Bones[1].PartID = "arm"
+	def.Skeleton.Animations[0].FPS = 10
+	if result := ValidateAsset(def, AssetValidationOptions{}); result.HasErrors() {
+		t.Fatalf("expected valid skeleton, got %+v", result.Issues)
+	}
+}
+
 func TestValidateAssetValidatesProceduralPrimitivePayload(t *testing.T) {
 	def := NewAssetDef("procedural")
 	def.Parts = []AssetPartDef{
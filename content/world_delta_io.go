@@ -71,7 +71,11 @@ func LoadVoxelObjectSnapshot(path string) (*VoxelObjectSnapshotDef, error) {
 	if err != nil {
 		return nil, err
 	}
+	return ParseVoxelObjectSnapshot(data)
+}
 
+// ParseVoxelObjectSnapshot decodes a JSON voxel object snapshot.
+func ParseVoxelObjectSnapshot(data []byte) (*VoxelObjectSnapshotDef, error) {
 	var def VoxelObjectSnapshotDef
 	if err := json.Unmarshal(data, &def); err != nil {
 		return nil, err
@@ -0,0 +1,409 @@
+package gekko
+
+import (
+	"math"
+	"reflect"
+
+	app_rt "github.com/gekko3d/gekko/voxelrt/rt/app"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	// DefaultDecalPoolBudget is the number of spawned decals kept when
+	// DecalPool.Budget is zero.
+	DefaultDecalPoolBudget = 128
+	defaultDecalMaxAngle   = 75
+)
+
+// DecalComponent projects a region of the decal atlas onto opaque voxel
+// surfaces inside a box. The entity's TransformComponent places the box:
+// local X and Y span the image, and it projects along local -Z onto surfaces
+// facing +Z. Decals are applied to the G-buffer albedo during deferred
+// lighting, so they never clip through geometry.
+type DecalComponent struct {
+	Disabled bool
+
+	// Size is the projector box, scaled by the transform. Z is the projection
+	// depth; keep it shallow so the decal does not reach through thin walls.
+	Size mgl32.Vec3
+	// Region is the atlas UV rectangle (u0, v0, u1, v1). Zero uses the whole
+	// atlas; see DecalAtlasCell for grid atlases.
+	Region [4]float32
+	// Color tints the image and its alpha scales opacity. Zero is opaque
+	// white.
+	Color [4]float32
+
+	// Lifetime removes the decal after this many seconds; zero keeps it.
+	// FadeOut fades it over the last seconds of its lifetime.
+	Lifetime float32
+	FadeOut  float32
+	Age      float32
+
+	// Priority draws higher decals over lower ones and keeps them first when
+	// the renderer's decal budget is exceeded.
+	Priority int32
+	// MaxAngle is the largest angle in degrees between a surface and the
+	// projector that still receives the decal. Zero uses 75.
+	MaxAngle float32
+	// VoxelSnap projects at voxel centers so the image steps with the voxel
+	// grid instead of being smooth across a voxel face.
+	VoxelSnap bool
+}
+
+// ImpactDecalComponent stamps Decal where its entity hits something, such as
+// a projectile leaving bullet holes. Feed collision events to
+// DecalPool.SpawnImpacts to use it.
+type ImpactDecalComponent struct {
+	Disabled bool
+	Decal    DecalComponent
+	// MinImpulse and MinSpeed ignore light touches.
+	MinImpulse float32
+	MinSpeed   float32
+	// RandomSpin rotates each stamp around the surface normal.
+	RandomSpin bool
+}
+
+// DecalAtlasCell returns the Region of cell index in a cols x rows grid
+// atlas, counted row by row from the top left.
+func DecalAtlasCell(index, cols, rows uint32) [4]float32 {
+	if cols == 0 {
+		cols = 1
+	}
+	if rows == 0 {
+		rows = 1
+	}
+	index %= cols * rows
+	cw := 1 / float32(cols)
+	ch := 1 / float32(rows)
+	x := float32(index%cols) * cw
+	y := float32(index/cols) * ch
+	return [4]float32{x, y, x + cw, y + ch}
+}
+
+// DecalRotationForNormal orients a decal so it projects onto a surface with
+// the given normal, rotated by spin radians around it.
+func DecalRotationForNormal(normal mgl32.Vec3, spin float32) mgl32.Quat {
+	if normal.Len() < 1e-6 {
+		normal = mgl32.Vec3{0, 1, 0}
+	}
+	normal = normal.Normalize()
+	rot := mgl32.QuatBetweenVectors(mgl32.Vec3{0, 0, 1}, normal)
+	if normal.Z() < -0.9999 {
+		// QuatBetweenVectors has no unique axis for opposite vectors.
+		rot = mgl32.QuatRotate(math.Pi, mgl32.Vec3{0, 1, 0})
+	}
+	if spin != 0 {
+		rot = rot.Mul(mgl32.QuatRotate(spin, mgl32.Vec3{0, 0, 1}))
+	}
+	return rot
+}
+
+// Opacity returns the lifetime fade, from 1 down to 0 over FadeOut.
+func (d *DecalComponent) Opacity() float32 {
+	if d.Lifetime <= 0 || d.FadeOut <= 0 {
+		return 1
+	}
+	return clampf((d.Lifetime-d.Age)/d.FadeOut, 0, 1)
+}
+
+func (d *DecalComponent) expired() bool {
+	return d.Lifetime > 0 && d.Age >= d.Lifetime
+}
+
+func (d *DecalComponent) renderInput(position mgl32.Vec3, rotation mgl32.Quat, scale mgl32.Vec3, order uint64) (app_rt.DecalInput, bool) {
+	if d.Disabled || d.expired() {
+		return app_rt.DecalInput{}, false
+	}
+	color := d.Color
+	if color == ([4]float32{}) {
+		color = [4]float32{1, 1, 1, 1}
+	}
+	color[3] *= d.Opacity()
+	region := d.Region
+	if region == ([4]float32{}) {
+		region = [4]float32{0, 0, 1, 1}
+	}
+	maxAngle := d.MaxAngle
+	if maxAngle <= 0 {
+		maxAngle = defaultDecalMaxAngle
+	}
+	input := app_rt.DecalInput{
+		Position:  position,
+		Rotation:  rotation,
+		Size:      mgl32.Vec3{d.Size.X() * scale.X(), d.Size.Y() * scale.Y(), d.Size.Z() * scale.Z()},
+		Region:    region,
+		Color:     color,
+		Priority:  d.Priority,
+		Order:     order,
+		MinFacing: float32(math.Cos(float64(mgl32.DegToRad(minf(maxAngle, 180))))),
+		VoxelSnap: d.VoxelSnap,
+	}
+	return input, input.Color[3] > 0
+}
+
+// PooledDecal is a decal spawned into a DecalPool.
+type PooledDecal struct {
+	Position mgl32.Vec3
+	Rotation mgl32.Quat
+	Decal    DecalComponent
+
+	serial uint64
+}
+
+// DecalPool holds decals spawned at runtime without entities, such as impacts
+// and scorch marks. It keeps at most Budget decals, recycling the oldest when
+// a spawn would exceed it.
+type DecalPool struct {
+	// Atlas is the texture every decal Region refers to.
+	Atlas AssetId
+	// Budget caps pooled decals; zero uses DefaultDecalPoolBudget.
+	Budget int
+	// MaxVisible caps the decals, pooled and entity, the renderer projects
+	// per frame after culling; zero uses the renderer default.
+	MaxVisible int
+
+	decals []PooledDecal
+	serial uint64
+}
+
+func (p *DecalPool) budget() int {
+	if p.Budget > 0 {
+		return p.Budget
+	}
+	return DefaultDecalPoolBudget
+}
+
+// Spawn adds a decal at a world position and rotation. It reports whether
+// the oldest decal was recycled to make room.
+func (p *DecalPool) Spawn(position mgl32.Vec3, rotation mgl32.Quat, decal DecalComponent) bool {
+	if p == nil || decal.Disabled {
+		return false
+	}
+	recycled := false
+	if over := len(p.decals) + 1 - p.budget(); over > 0 {
+		p.decals = append(p.decals[:0], p.decals[over:]...)
+		recycled = true
+	}
+	p.serial++
+	p.decals = append(p.decals, PooledDecal{Position: position, Rotation: rotation, Decal: decal, serial: p.serial})
+	return recycled
+}
+
+// SpawnOnSurface stamps a decal onto the surface at point with the given
+// normal. The box is centered on the point so it reaches into the surface.
+func (p *DecalPool) SpawnOnSurface(point, normal mgl32.Vec3, decal DecalComponent, spin float32) bool {
+	return p.Spawn(point, DecalRotationForNormal(normal, spin), decal)
+}
+
+// Advance ages pooled decals and drops expired ones.
+func (p *DecalPool) Advance(dt float32) {
+	if p == nil || dt <= 0 {
+		return
+	}
+	kept := p.decals[:0]
+	for _, pooled := range p.decals {
+		pooled.Decal.Age += dt
+		if !pooled.Decal.expired() {
+			kept = append(kept, pooled)
+		}
+	}
+	for i := len(kept); i < len(p.decals); i++ {
+		p.decals[i] = PooledDecal{}
+	}
+	p.decals = kept
+}
+
+// Decals returns a copy of the pooled decals, oldest first.
+func (p *DecalPool) Decals() []PooledDecal {
+	if p == nil || len(p.decals) == 0 {
+		return nil
+	}
+	return append([]PooledDecal(nil), p.decals...)
+}
+
+func (p *DecalPool) Len() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.decals)
+}
+
+func (p *DecalPool) Clear() {
+	if p == nil {
+		return
+	}
+	p.decals = p.decals[:0]
+}
+
+// SpawnImpacts stamps decals for collision events whose entities carry an
+// ImpactDecalComponent. Pass the events drained from PhysicsProxy each frame.
+// It returns the number of decals spawned.
+func (p *DecalPool) SpawnImpacts(cmd *Commands, events []PhysicsCollisionEvent) int {
+	if p == nil || cmd == nil {
+		return 0
+	}
+	spawned := 0
+	for _, event := range events {
+		if event.Type != CollisionEventEnter || event.IsTrigger {
+			continue
+		}
+		for _, eid := range [2]EntityId{event.A, event.B} {
+			impact, position, ok := impactDecalForEntity(cmd, eid)
+			if !ok || impact.Disabled || event.NormalImpulse < impact.MinImpulse || event.RelativeSpeed < impact.MinSpeed {
+				continue
+			}
+			// Face the side the impacting entity came from.
+			normal := event.Normal
+			if normal.Dot(position.Sub(event.Point)) < 0 {
+				normal = normal.Mul(-1)
+			}
+			spin := float32(0)
+			if impact.RandomSpin {
+				spin = impactDecalSpin(event.Tick, eid)
+			}
+			p.SpawnOnSurface(event.Point, normal, impact.Decal, spin)
+			spawned++
+		}
+	}
+	return spawned
+}
+
+func impactDecalForEntity(cmd *Commands, eid EntityId) (ImpactDecalComponent, mgl32.Vec3, bool) {
+	var impact ImpactDecalComponent
+	var position mgl32.Vec3
+	found := false
+	for _, comp := range cmd.GetAllComponents(eid) {
+		switch c := comp.(type) {
+		case *ImpactDecalComponent:
+			impact, found = *c, true
+		case ImpactDecalComponent:
+			impact, found = c, true
+		case *TransformComponent:
+			position = c.Position
+		case TransformComponent:
+			position = c.Position
+		}
+	}
+	return impact, position, found
+}
+
+// impactDecalSpin is a deterministic per-impact angle so replays stamp the
+// same decals.
+func impactDecalSpin(tick uint64, eid EntityId) float32 {
+	h := (tick*0x9E3779B97F4A7C15 ^ uint64(eid)*0xBF58476D1CE4E5B9) >> 40
+	return float32(h%3600) / 3600 * 2 * math.Pi
+}
+
+// spawnDestructionDecal stamps event.Decal over the destroyed area. A zero
+// Size covers the carve radius.
+func spawnDestructionDecal(pool *DecalPool, event DestructionEvent) {
+	if pool == nil || event.Decal == nil {
+		return
+	}
+	center, radius, ok := destructionEventArea(event)
+	if !ok {
+		return
+	}
+	decal := *event.Decal
+	if decal.Size == (mgl32.Vec3{}) {
+		side := radius * 2.5
+		decal.Size = mgl32.Vec3{side, side, side}
+	}
+	pool.SpawnOnSurface(center, event.DecalNormal, decal, 0)
+}
+
+// destructionEventArea returns the center and radius of the carved area: the
+// brush bounds when a Brush is set, otherwise Center and Radius.
+func destructionEventArea(event DestructionEvent) (mgl32.Vec3, float32, bool) {
+	if event.Brush == nil {
+		return event.Center, event.Radius, event.Radius > 0
+	}
+	lo, hi := event.Brush.Bounds()
+	half := hi.Sub(lo).Mul(0.5)
+	radius := max(half.X(), half.Y(), half.Z())
+	if !(radius > 0) || math.IsInf(float64(radius), 0) {
+		return mgl32.Vec3{}, 0, false
+	}
+	return lo.Add(half), radius, true
+}
+
+type DecalModule struct {
+	// Atlas, PoolBudget and MaxVisible seed the DecalPool resource.
+	Atlas      AssetId
+	PoolBudget int
+	MaxVisible int
+}
+
+func (mod DecalModule) Install(app *App, cmd *Commands) {
+	cmd.AddResources(&DecalPool{Atlas: mod.Atlas, Budget: mod.PoolBudget, MaxVisible: mod.MaxVisible})
+	app.UseSystem(
+		System(decalLifetimeSystem).
+			InStage(Update).
+			RunAlways(),
+	)
+}
+
+// decalLifetimeSystem ages entity and pooled decals, removing expired ones.
+func decalLifetimeSystem(time *Time, pool *DecalPool, cmd *Commands) {
+	dt := float32(time.Dt)
+	if dt <= 0 {
+		return
+	}
+	pool.Advance(dt)
+	MakeQuery1[DecalComponent](cmd).Map(func(eid EntityId, decal *DecalComponent) bool {
+		if decal.Lifetime <= 0 {
+			return true
+		}
+		decal.Age += dt
+		if decal.expired() {
+			cmd.RemoveEntity(eid)
+		}
+		return true
+	})
+}
+
+func decalPoolFromApp(app *App) *DecalPool {
+	if app == nil {
+		return nil
+	}
+	if resource, ok := app.resources[reflect.TypeOf(DecalPool{})]; ok {
+		return resource.(*DecalPool)
+	}
+	return nil
+}
+
+// buildDecalInputs collects entity decals and pooled decals for the renderer.
+// Pooled decals order after entity decals of the same priority, so impacts
+// draw over authored signage.
+func buildDecalInputs(cmd *Commands, pool *DecalPool) []app_rt.DecalInput {
+	var inputs []app_rt.DecalInput
+	MakeQuery2[TransformComponent, DecalComponent](cmd).Map(func(eid EntityId, tr *TransformComponent, decal *DecalComponent) bool {
+		if input, ok := decal.renderInput(tr.Position, tr.Rotation, tr.Scale, uint64(eid)); ok {
+			inputs = append(inputs, input)
+		}
+		return true
+	})
+	if pool != nil {
+		for i := range pool.decals {
+			pooled := &pool.decals[i]
+			if input, ok := pooled.Decal.renderInput(pooled.Position, pooled.Rotation, mgl32.Vec3{1, 1, 1}, 1<<63|pooled.serial); ok {
+				inputs = append(inputs, input)
+			}
+		}
+	}
+	return inputs
+}
+
+func syncVoxelRtDecals(state *VoxelRtState, server *AssetServer, cmd *Commands) {
+	if state == nil || state.RtApp == nil || cmd == nil {
+		return
+	}
+	pool := decalPoolFromApp(cmd.app)
+	if pool != nil {
+		state.RtApp.MaxDecals = pool.MaxVisible
+		if texAsset, ok := spriteAtlasTexture(server, spriteAtlasKey(pool.Atlas)); ok {
+			state.RtApp.SetDecalAtlas(texAsset.Texels, texAsset.Width, texAsset.Height, texAsset.Version, assetTextureFormatToWGPU(texAsset.Format))
+		}
+	}
+	state.RtApp.ApplyDecalInput(buildDecalInputs(cmd, pool))
+}
@@ -0,0 +1,211 @@
+package gekko
+
+import (
+	"math"
+	"testing"
+
+	app_rt "github.com/gekko3d/gekko/voxelrt/rt/app"
+	"github.com/gekko3d/gekko/voxelrt/rt/core"
+	"github.com/gekko3d/gekko/voxelrt/rt/volume"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestDecalPoolRecyclesOldestPastBudget(t *testing.T) {
+	pool := &DecalPool{Budget: 3}
+	decal := DecalComponent{Size: mgl32.Vec3{1, 1, 1}}
+	for i := 0; i < 3; i++ {
+		if pool.Spawn(mgl32.Vec3{float32(i), 0, 0}, mgl32.QuatIdent(), decal) {
+			t.Fatalf("spawn %d recycled below budget", i)
+		}
+	}
+	if !pool.Spawn(mgl32.Vec3{3, 0, 0}, mgl32.QuatIdent(), decal) {
+		t.Fatal("expected the spawn past budget to recycle")
+	}
+	decals := pool.Decals()
+	if len(decals) != 3 || decals[0].Position.X() != 1 || decals[2].Position.X() != 3 {
+		t.Fatalf("expected the oldest decal to be recycled, got %+v", decals)
+	}
+	if pool.Spawn(mgl32.Vec3{}, mgl32.QuatIdent(), DecalComponent{Disabled: true}) || pool.Len() != 3 {
+		t.Fatal("expected disabled decals to be ignored")
+	}
+	pool.Clear()
+	if pool.Len() != 0 {
+		t.Fatal("expected Clear to empty the pool")
+	}
+}
+
+func TestDecalPoolAdvanceFadesAndExpires(t *testing.T) {
+	pool := &DecalPool{}
+	pool.Spawn(mgl32.Vec3{}, mgl32.QuatIdent(), DecalComponent{Size: mgl32.Vec3{1, 1, 1}, Lifetime: 2, FadeOut: 1})
+	pool.Spawn(mgl32.Vec3{}, mgl32.QuatIdent(), DecalComponent{Size: mgl32.Vec3{1, 1, 1}})
+
+	pool.Advance(1.5)
+	decals := pool.Decals()
+	if len(decals) != 2 {
+		t.Fatalf("expected both decals alive, got %d", len(decals))
+	}
+	if got := decals[0].Decal.Opacity(); math.Abs(float64(got-0.5)) > 1e-5 {
+		t.Fatalf("opacity = %v, want 0.5", got)
+	}
+	input, ok := decals[0].Decal.renderInput(mgl32.Vec3{}, mgl32.QuatIdent(), mgl32.Vec3{1, 1, 1}, 0)
+	if !ok || math.Abs(float64(input.Color[3]-0.5)) > 1e-5 {
+		t.Fatalf("expected the fade to reach the render alpha, got %+v", input.Color)
+	}
+
+	pool.Advance(0.5)
+	if pool.Len() != 1 {
+		t.Fatalf("expected the timed decal to expire, got %d decals", pool.Len())
+	}
+}
+
+func TestDecalRenderInputDefaults(t *testing.T) {
+	decal := DecalComponent{Size: mgl32.Vec3{2, 2, 0.5}}
+	input, ok := decal.renderInput(mgl32.Vec3{1, 2, 3}, mgl32.QuatIdent(), mgl32.Vec3{2, 1, 1}, 7)
+	if !ok {
+		t.Fatal("expected a drawable decal")
+	}
+	if input.Size != (mgl32.Vec3{4, 2, 0.5}) {
+		t.Fatalf("expected the transform scale to apply, got %v", input.Size)
+	}
+	if input.Region != [4]float32{0, 0, 1, 1} || input.Color != [4]float32{1, 1, 1, 1} {
+		t.Fatalf("unexpected defaults region=%v color=%v", input.Region, input.Color)
+	}
+	if want := float32(math.Cos(75 * math.Pi / 180)); math.Abs(float64(input.MinFacing-want)) > 1e-5 {
+		t.Fatalf("MinFacing = %v, want %v", input.MinFacing, want)
+	}
+	if cell := DecalAtlasCell(5, 4, 2); cell != [4]float32{0.25, 0.5, 0.5, 1} {
+		t.Fatalf("DecalAtlasCell = %v", cell)
+	}
+}
+
+func TestDecalRotationForNormalProjectsAlongNormal(t *testing.T) {
+	for _, normal := range []mgl32.Vec3{{0, 1, 0}, {1, 0, 0}, {0, 0, 1}, {0, 0, -1}} {
+		got := DecalRotationForNormal(normal, 0.7).Rotate(mgl32.Vec3{0, 0, 1})
+		if got.Sub(normal).Len() > 1e-4 {
+			t.Fatalf("normal %v: decal axis %v", normal, got)
+		}
+	}
+}
+
+func TestDecalPoolSpawnImpactsStampsFacingTheImpactor(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	bullet := cmd.AddEntity(
+		&TransformComponent{Position: mgl32.Vec3{0, 2, 0}, Scale: mgl32.Vec3{1, 1, 1}},
+		&ImpactDecalComponent{Decal: DecalComponent{Size: mgl32.Vec3{0.5, 0.5, 0.2}}, MinImpulse: 1},
+	)
+	wall := cmd.AddEntity(&TransformComponent{Scale: mgl32.Vec3{1, 1, 1}})
+	app.FlushCommands()
+
+	pool := &DecalPool{}
+	spawned := pool.SpawnImpacts(cmd, []PhysicsCollisionEvent{
+		// Normal points away from the bullet; the stamp must still face it.
+		{Type: CollisionEventEnter, A: wall, B: bullet, Point: mgl32.Vec3{0, 1, 0}, Normal: mgl32.Vec3{0, -1, 0}, NormalImpulse: 5, Tick: 3},
+		{Type: CollisionEventEnter, A: wall, B: bullet, Point: mgl32.Vec3{0, 1, 0}, Normal: mgl32.Vec3{0, 1, 0}, NormalImpulse: 0.5},
+		{Type: CollisionEventStay, A: wall, B: bullet, Point: mgl32.Vec3{0, 1, 0}, Normal: mgl32.Vec3{0, 1, 0}, NormalImpulse: 5},
+		{Type: CollisionEventEnter, IsTrigger: true, A: wall, B: bullet, NormalImpulse: 5},
+	})
+	if spawned != 1 || pool.Len() != 1 {
+		t.Fatalf("expected one impact decal, spawned %d", spawned)
+	}
+	stamp := pool.Decals()[0]
+	if stamp.Position != (mgl32.Vec3{0, 1, 0}) {
+		t.Fatalf("expected the stamp at the contact point, got %v", stamp.Position)
+	}
+	if axis := stamp.Rotation.Rotate(mgl32.Vec3{0, 0, 1}); axis.Sub(mgl32.Vec3{0, 1, 0}).Len() > 1e-4 {
+		t.Fatalf("expected the stamp to face the bullet, got axis %v", axis)
+	}
+}
+
+func TestBuildDecalInputsOrdersPooledAfterEntityDecals(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	cmd.AddEntity(
+		&TransformComponent{Rotation: mgl32.QuatIdent(), Scale: mgl32.Vec3{1, 1, 1}},
+		&DecalComponent{Size: mgl32.Vec3{1, 1, 1}},
+	)
+	cmd.AddEntity(
+		&TransformComponent{Scale: mgl32.Vec3{1, 1, 1}},
+		&DecalComponent{Size: mgl32.Vec3{1, 1, 1}, Disabled: true},
+	)
+	app.FlushCommands()
+
+	pool := &DecalPool{}
+	pool.Spawn(mgl32.Vec3{5, 0, 0}, mgl32.QuatIdent(), DecalComponent{Size: mgl32.Vec3{1, 1, 1}})
+	inputs := buildDecalInputs(cmd, pool)
+	if len(inputs) != 2 {
+		t.Fatalf("expected the entity and pooled decal, got %d inputs", len(inputs))
+	}
+	if inputs[1].Position.X() != 5 || inputs[1].Order <= inputs[0].Order {
+		t.Fatalf("expected the pooled decal to order after the entity decal, got %+v", inputs)
+	}
+}
+
+func TestDestructionEventStampsDecalIntoPool(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	cmd.AddResources(&DecalPool{Budget: 4})
+
+	state := &VoxelRtState{
+		instanceMap:    make(map[EntityId]*core.VoxelObject),
+		objectToEntity: make(map[*core.VoxelObject]EntityId),
+		loadedModels:   make(map[AssetId]*core.VoxelObject),
+		RtApp: &app_rt.App{
+			Scene:    core.NewScene(),
+			Profiler: core.NewProfiler(),
+		},
+	}
+
+	xbm := volume.NewXBrickMap()
+	for z := 0; z < 4; z++ {
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				xbm.SetVoxel(x, y, z, 1)
+			}
+		}
+	}
+	server := &AssetServer{voxModels: make(map[AssetId]VoxelGeometryAsset)}
+	ent := cmd.AddEntity(
+		&TransformComponent{Scale: mgl32.Vec3{1, 1, 1}},
+		&VoxelModelComponent{OverrideGeometry: server.RegisterSharedVoxelGeometry(xbm, "")},
+	)
+	app.FlushCommands()
+	obj := core.NewVoxelObject()
+	obj.XBrickMap = xbm
+	state.instanceMap[ent] = obj
+
+	queue := &DestructionQueue{Events: []DestructionEvent{{
+		Entity:      ent,
+		Center:      mgl32.Vec3{2, 4, 2},
+		Radius:      1,
+		Decal:       &DecalComponent{Color: [4]float32{0.1, 0.1, 0.1, 1}},
+		DecalNormal: mgl32.Vec3{0, 1, 0},
+	}}}
+	destructionSystem(state, queue, cmd, server)
+
+	pool := decalPoolFromApp(app)
+	if pool == nil || pool.Len() != 1 {
+		t.Fatalf("expected one scorch decal in the pool, got %d", pool.Len())
+	}
+	scorch := pool.Decals()[0]
+	if scorch.Position != (mgl32.Vec3{2, 4, 2}) || scorch.Decal.Size != (mgl32.Vec3{2.5, 2.5, 2.5}) {
+		t.Fatalf("unexpected scorch decal %+v", scorch)
+	}
+}
+
+func TestDestructionDecalFollowsBrushBounds(t *testing.T) {
+	pool := &DecalPool{}
+	spawnDestructionDecal(pool, DestructionEvent{
+		Center: mgl32.Vec3{100, 100, 100},
+		Radius: 50,
+		Brush:  volume.SDFSphere{Center: mgl32.Vec3{4, 2, -6}, Radius: 2},
+		Decal:  &DecalComponent{},
+	})
+	if pool.Len() != 1 {
+		t.Fatalf("expected one brush scorch decal, got %d", pool.Len())
+	}
+	scorch := pool.Decals()[0]
+	if scorch.Position != (mgl32.Vec3{4, 2, -6}) || scorch.Decal.Size != (mgl32.Vec3{5, 5, 5}) {
+		t.Fatalf("expected the decal on the brush bounds, got %+v", scorch)
+	}
+}
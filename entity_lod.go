@@ -34,43 +34,116 @@ func (r EntityLODRepresentation) String() string {
 type EntityLODDistanceMetric uint32
 
 const (
+	// EntityLODDistanceMetricOrigin measures from the camera to the entity
+	// transform position.
 	EntityLODDistanceMetricOrigin EntityLODDistanceMetric = iota
+	// EntityLODDistanceMetricBoundsNearest measures from the camera to the
+	// nearest point of the entity's world bounds, so large entities keep
+	// detail while the camera is close to any part of them.
+	EntityLODDistanceMetricBoundsNearest
+	// EntityLODDistanceMetricScreenSize selects bands by projected bounds
+	// diameter in pixels using EntityLODBand.MinScreenSize.
+	EntityLODDistanceMetricScreenSize
 )
 
+// defaultEntityLODViewportHeight is used for screen-size selection when the
+// renderer has no surface yet.
+const defaultEntityLODViewportHeight = 1080
+
 func (m EntityLODDistanceMetric) String() string {
 	switch m {
 	case EntityLODDistanceMetricOrigin:
 		return "origin"
+	case EntityLODDistanceMetricBoundsNearest:
+		return "bounds_nearest"
+	case EntityLODDistanceMetricScreenSize:
+		return "screen_size"
 	default:
 		return "unknown"
 	}
 }
 
 type EntityLODBand struct {
-	MaxDistance    float32
+	MaxDistance float32
+	// MinScreenSize is the smallest projected diameter, in pixels, that keeps
+	// this band selected. Only used by EntityLODDistanceMetricScreenSize.
+	MinScreenSize float32
+	// Hysteresis widens this band's outer edge in metric units (world units
+	// or pixels). An entity must cross the edge by this margin to leave the
+	// band, and come back inside it by the same margin to return.
+	Hysteresis     float32
 	Representation EntityLODRepresentation
 }
 
 type EntityLODSelection struct {
 	Distance       float32
+	ScreenSize     float32
 	BandIndex      int
 	MaxDistance    float32
 	Representation EntityLODRepresentation
+
+	// PreviousBandIndex is the band being faded out, or -1 when no
+	// transition is running. TransitionProgress runs from 0 to 1.
+	PreviousBandIndex      int
+	PreviousRepresentation EntityLODRepresentation
+	TransitionProgress     float32
+}
+
+// Transitioning reports whether the renderer should blend the previous and
+// active representations.
+func (s EntityLODSelection) Transitioning() bool {
+	return s.PreviousBandIndex >= 0 && s.TransitionProgress < 1
+}
+
+// TransitionAlpha returns the opacity of the active and previous
+// representations: the active one fades in while the previous fades out.
+func (s EntityLODSelection) TransitionAlpha() (active, previous float32) {
+	if !s.Transitioning() {
+		return 1, 0
+	}
+	progress := clampF(s.TransitionProgress, 0, 1)
+	return progress, 1 - progress
+}
+
+// EntityLODView is the camera state used to measure LOD metrics.
+type EntityLODView struct {
+	Position mgl32.Vec3
+	// FovY is the vertical field of view in radians.
+	FovY float32
+	// ViewportHeight is the render target height in pixels.
+	ViewportHeight float32
+}
+
+// EntityLODBounds is a world-space AABB. A zero-size box at the transform
+// position is used when the entity has no resolvable geometry.
+type EntityLODBounds struct {
+	Min mgl32.Vec3
+	Max mgl32.Vec3
 }
 
 // EntityLODComponent defines the engine-side LOD contract for an entity.
-// Bands must be sorted by ascending MaxDistance, and the final band must be
-// unbounded by setting MaxDistance to 0.
+// Bands are ordered from most to least detailed. With distance metrics they
+// must be sorted by ascending MaxDistance and the final band must be
+// unbounded by setting MaxDistance to 0. With the screen-size metric they must
+// be sorted by descending MinScreenSize and the final band must set
+// MinScreenSize to 0.
 type EntityLODComponent struct {
 	Disabled       bool
 	DistanceMetric EntityLODDistanceMetric
 	Bands          []EntityLODBand
+	// TransitionDuration is the cross-fade window in seconds reported to the
+	// renderer after a band change. Zero switches instantly.
+	TransitionDuration float32
 
-	SelectionValid       bool
-	ActiveDistance       float32
-	ActiveBandIndex      int
-	ActiveMaxDistance    float32
-	ActiveRepresentation EntityLODRepresentation
+	SelectionValid         bool
+	ActiveDistance         float32
+	ActiveScreenSize       float32
+	ActiveBandIndex        int
+	ActiveMaxDistance      float32
+	ActiveRepresentation   EntityLODRepresentation
+	PreviousBandIndex      int
+	PreviousRepresentation EntityLODRepresentation
+	TransitionProgress     float32
 }
 
 func (c *EntityLODComponent) Enabled() bool {
@@ -82,7 +155,9 @@ func (c *EntityLODComponent) NormalizedDistanceMetric() EntityLODDistanceMetric
 		return EntityLODDistanceMetricOrigin
 	}
 	switch c.DistanceMetric {
-	case EntityLODDistanceMetricOrigin:
+	case EntityLODDistanceMetricOrigin,
+		EntityLODDistanceMetricBoundsNearest,
+		EntityLODDistanceMetricScreenSize:
 		return c.DistanceMetric
 	default:
 		return EntityLODDistanceMetricOrigin
@@ -95,22 +170,73 @@ func (c *EntityLODComponent) ClearRuntimeSelection() {
 	}
 	c.SelectionValid = false
 	c.ActiveDistance = 0
+	c.ActiveScreenSize = 0
 	c.ActiveBandIndex = -1
 	c.ActiveMaxDistance = 0
 	c.ActiveRepresentation = EntityLODRepresentationFullVoxel
+	c.PreviousBandIndex = -1
+	c.PreviousRepresentation = EntityLODRepresentationFullVoxel
+	c.TransitionProgress = 1
 }
 
+// ApplySelection makes selection active. A band change on an entity that
+// already had a selection starts a transition when TransitionDuration is set.
 func (c *EntityLODComponent) ApplySelection(selection EntityLODSelection) {
 	if c == nil {
 		return
 	}
+	changed := c.SelectionValid && selection.BandIndex != c.ActiveBandIndex
+	switch {
+	case changed && c.TransitionDuration > 0:
+		c.PreviousBandIndex = c.ActiveBandIndex
+		c.PreviousRepresentation = c.ActiveRepresentation
+		c.TransitionProgress = 0
+	case changed || !c.SelectionValid:
+		c.PreviousBandIndex = -1
+		c.PreviousRepresentation = selection.Representation
+		c.TransitionProgress = 1
+	}
 	c.SelectionValid = true
 	c.ActiveDistance = selection.Distance
+	c.ActiveScreenSize = selection.ScreenSize
 	c.ActiveBandIndex = selection.BandIndex
 	c.ActiveMaxDistance = selection.MaxDistance
 	c.ActiveRepresentation = selection.Representation
 }
 
+// AdvanceTransition moves a running transition forward by dt seconds.
+func (c *EntityLODComponent) AdvanceTransition(dt float32) {
+	if c == nil || c.PreviousBandIndex < 0 {
+		return
+	}
+	if c.TransitionDuration > 0 && dt > 0 {
+		c.TransitionProgress += dt / c.TransitionDuration
+	} else if c.TransitionDuration <= 0 {
+		c.TransitionProgress = 1
+	}
+	if c.TransitionProgress >= 1 {
+		c.TransitionProgress = 1
+		c.PreviousBandIndex = -1
+	}
+}
+
+// ActiveSelection returns the runtime selection, including transition state.
+func (c *EntityLODComponent) ActiveSelection() (EntityLODSelection, bool) {
+	if c == nil || !c.SelectionValid {
+		return EntityLODSelection{}, false
+	}
+	return EntityLODSelection{
+		Distance:               c.ActiveDistance,
+		ScreenSize:             c.ActiveScreenSize,
+		BandIndex:              c.ActiveBandIndex,
+		MaxDistance:            c.ActiveMaxDistance,
+		Representation:         c.ActiveRepresentation,
+		PreviousBandIndex:      c.PreviousBandIndex,
+		PreviousRepresentation: c.PreviousRepresentation,
+		TransitionProgress:     c.TransitionProgress,
+	}, true
+}
+
 func (c *EntityLODComponent) Validate() error {
 	if c == nil {
 		return fmt.Errorf("entity LOD component is nil")
@@ -119,7 +245,9 @@ func (c *EntityLODComponent) Validate() error {
 		return fmt.Errorf("entity LOD requires at least one distance band")
 	}
 
+	screenSize := c.NormalizedDistanceMetric() == EntityLODDistanceMetricScreenSize
 	prevMax := float32(-1)
+	prevMinSize := float32(math.Inf(1))
 	for i, band := range c.Bands {
 		switch band.Representation {
 		case EntityLODRepresentationFullVoxel,
@@ -129,8 +257,24 @@ func (c *EntityLODComponent) Validate() error {
 		default:
 			return fmt.Errorf("entity LOD band %d has unsupported representation %d", i, band.Representation)
 		}
+		if band.Hysteresis < 0 {
+			return fmt.Errorf("entity LOD band %d hysteresis must be >= 0", i)
+		}
 
 		last := i == len(c.Bands)-1
+		if screenSize {
+			if !last && band.MinScreenSize <= 0 {
+				return fmt.Errorf("entity LOD band %d must have a positive min screen size", i)
+			}
+			if band.MinScreenSize < 0 {
+				return fmt.Errorf("entity LOD band %d min screen size must be >= 0", i)
+			}
+			if !last && band.MinScreenSize >= prevMinSize {
+				return fmt.Errorf("entity LOD screen size bands must be strictly decreasing")
+			}
+			prevMinSize = band.MinScreenSize
+			continue
+		}
 		if !last && band.MaxDistance <= 0 {
 			return fmt.Errorf("entity LOD band %d must have a positive max distance", i)
 		}
@@ -146,12 +290,20 @@ func (c *EntityLODComponent) Validate() error {
 		prevMax = band.MaxDistance
 	}
 
+	if screenSize {
+		if c.Bands[len(c.Bands)-1].MinScreenSize > 0 {
+			return fmt.Errorf("final entity LOD band must be unbounded (MinScreenSize == 0)")
+		}
+		return nil
+	}
 	if c.Bands[len(c.Bands)-1].MaxDistance > 0 {
 		return fmt.Errorf("final entity LOD band must be unbounded (MaxDistance == 0)")
 	}
 	return nil
 }
 
+// SelectEntityLODByDistance picks a band for distance. Band edges honor
+// Hysteresis relative to the component's active band.
 func SelectEntityLODByDistance(component *EntityLODComponent, distance float32) (EntityLODSelection, error) {
 	if err := component.Validate(); err != nil {
 		return EntityLODSelection{}, err
@@ -159,42 +311,137 @@ func SelectEntityLODByDistance(component *EntityLODComponent, distance float32)
 	if distance < 0 {
 		distance = 0
 	}
+	return entityLODSelectionForBand(component, selectEntityLODBand(component, distance, false), distance, 0), nil
+}
 
-	lastIndex := len(component.Bands) - 1
-	for i, band := range component.Bands {
-		maxDistance := band.MaxDistance
-		if i == lastIndex && maxDistance == 0 {
-			maxDistance = float32(math.Inf(1))
-		}
-		if distance <= maxDistance {
-			return EntityLODSelection{
-				Distance:       distance,
-				BandIndex:      i,
-				MaxDistance:    band.MaxDistance,
-				Representation: band.Representation,
-			}, nil
-		}
+// SelectEntityLODByScreenSize picks a band for a projected diameter in
+// pixels using each band's MinScreenSize.
+func SelectEntityLODByScreenSize(component *EntityLODComponent, distance, screenSize float32) (EntityLODSelection, error) {
+	if err := component.Validate(); err != nil {
+		return EntityLODSelection{}, err
 	}
-
-	last := component.Bands[lastIndex]
-	return EntityLODSelection{
-		Distance:       distance,
-		BandIndex:      lastIndex,
-		MaxDistance:    last.MaxDistance,
-		Representation: last.Representation,
-	}, nil
+	if screenSize < 0 {
+		screenSize = 0
+	}
+	return entityLODSelectionForBand(component, selectEntityLODBand(component, screenSize, true), distance, screenSize), nil
 }
 
+// SelectEntityLOD selects by distance from cameraPosition to the transform
+// position. Use SelectEntityLODForView for the bounds and screen-size
+// metrics.
 func SelectEntityLOD(cameraPosition mgl32.Vec3, transform *TransformComponent, component *EntityLODComponent) (EntityLODSelection, error) {
 	if transform == nil {
 		return EntityLODSelection{}, fmt.Errorf("entity LOD requires a transform")
 	}
+	bounds := EntityLODBounds{Min: transform.Position, Max: transform.Position}
+	return SelectEntityLODForView(EntityLODView{Position: cameraPosition}, bounds, transform, component)
+}
+
+func SelectEntityLODForView(view EntityLODView, bounds EntityLODBounds, transform *TransformComponent, component *EntityLODComponent) (EntityLODSelection, error) {
+	if transform == nil {
+		return EntityLODSelection{}, fmt.Errorf("entity LOD requires a transform")
+	}
+	screenSize := entityLODScreenSize(view, bounds)
 	switch component.NormalizedDistanceMetric() {
-	case EntityLODDistanceMetricOrigin:
-		return SelectEntityLODByDistance(component, transform.Position.Sub(cameraPosition).Len())
+	case EntityLODDistanceMetricBoundsNearest:
+		selection, err := SelectEntityLODByDistance(component, entityLODNearestBoundsDistance(view.Position, bounds))
+		selection.ScreenSize = screenSize
+		return selection, err
+	case EntityLODDistanceMetricScreenSize:
+		return SelectEntityLODByScreenSize(component, transform.Position.Sub(view.Position).Len(), screenSize)
 	default:
-		return SelectEntityLODByDistance(component, transform.Position.Sub(cameraPosition).Len())
+		selection, err := SelectEntityLODByDistance(component, transform.Position.Sub(view.Position).Len())
+		selection.ScreenSize = screenSize
+		return selection, err
+	}
+}
+
+// selectEntityLODBand maps value onto a band. For distances a band holds
+// while value <= MaxDistance; for screen sizes while value >= MinScreenSize.
+// When the component has an active band the walk starts there, so an entity
+// only moves once it is past an edge by that edge's hysteresis.
+func selectEntityLODBand(component *EntityLODComponent, value float32, screenSize bool) int {
+	lastIndex := len(component.Bands) - 1
+	inside := func(index int, margin float32) bool {
+		if index >= lastIndex {
+			return true
+		}
+		band := component.Bands[index]
+		if screenSize {
+			return value >= band.MinScreenSize+margin
+		}
+		return value <= band.MaxDistance-margin
+	}
+	beyond := func(index int) bool {
+		if index >= lastIndex {
+			return false
+		}
+		band := component.Bands[index]
+		if screenSize {
+			return value < band.MinScreenSize-band.Hysteresis
+		}
+		return value > band.MaxDistance+band.Hysteresis
+	}
+
+	if !component.SelectionValid || component.ActiveBandIndex < 0 || component.ActiveBandIndex > lastIndex {
+		for i := range component.Bands {
+			if inside(i, 0) {
+				return i
+			}
+		}
+		return lastIndex
+	}
+	current := component.ActiveBandIndex
+	for beyond(current) {
+		current++
+	}
+	for current > 0 && inside(current-1, component.Bands[current-1].Hysteresis) {
+		current--
+	}
+	return current
+}
+
+func entityLODSelectionForBand(component *EntityLODComponent, index int, distance, screenSize float32) EntityLODSelection {
+	band := component.Bands[index]
+	return EntityLODSelection{
+		Distance:           distance,
+		ScreenSize:         screenSize,
+		BandIndex:          index,
+		MaxDistance:        band.MaxDistance,
+		Representation:     band.Representation,
+		PreviousBandIndex:  -1,
+		TransitionProgress: 1,
+	}
+}
+
+func entityLODNearestBoundsDistance(point mgl32.Vec3, bounds EntityLODBounds) float32 {
+	var nearest mgl32.Vec3
+	for axis := 0; axis < 3; axis++ {
+		nearest[axis] = min(max(point[axis], bounds.Min[axis]), bounds.Max[axis])
+	}
+	return nearest.Sub(point).Len()
+}
+
+// entityLODScreenSize returns the projected diameter of the bounds' sphere in
+// pixels. The camera inside the sphere counts as filling the screen.
+func entityLODScreenSize(view EntityLODView, bounds EntityLODBounds) float32 {
+	radius := bounds.Max.Sub(bounds.Min).Len() * 0.5
+	if radius <= 0 {
+		return 0
+	}
+	fovY := view.FovY
+	if fovY <= 0 {
+		fovY = mgl32.DegToRad(60)
+	}
+	height := view.ViewportHeight
+	if height <= 0 {
+		height = defaultEntityLODViewportHeight
+	}
+	distance := bounds.Min.Add(bounds.Max).Mul(0.5).Sub(view.Position).Len()
+	if distance <= radius {
+		return float32(math.Inf(1))
 	}
+	return radius * height / (distance * float32(math.Tan(float64(fovY)*0.5)))
 }
 
 func entityLODComponentForEntity(cmd *Commands, eid EntityId) (EntityLODComponent, bool) {
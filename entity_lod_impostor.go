@@ -0,0 +1,491 @@
+package gekko
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/gekko3d/gekko/voxelrt/rt/volume"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Octahedral impostors bake entityLODImpostorViewGrid² orthographic views of
+// a geometry, one per octahedral-map cell. The atlas is Grid cells wide and
+// 2*Grid cells tall: the top half holds straight-alpha color, the bottom half
+// holds the view-space normal in RGB and depth in A. Normals are expressed in
+// each view's card basis (x right, y up, z toward the viewer) so the sprite
+// shader can rebuild them from the billboard axes.
+const (
+	entityLODImpostorViewGrid = 8
+	entityLODImpostorCellSize = 32
+)
+
+type entityLODImpostorAtlas struct {
+	Grid     int
+	CellSize int
+	Texels   []uint8
+}
+
+func (a entityLODImpostorAtlas) Width() int {
+	return a.Grid * a.CellSize
+}
+
+func (a entityLODImpostorAtlas) Height() int {
+	return 2 * a.Grid * a.CellSize
+}
+
+// entityLODImpostorView is the sprite-side view selection: Index takes the
+// remaining weight after BlendWeights.
+type entityLODImpostorView struct {
+	Index        uint32
+	BlendIndices [2]uint32
+	BlendWeights [2]float32
+}
+
+// entityLODImpostorSphere returns the local-space bounding sphere every view
+// is framed on. All views share it so the card size does not change as the
+// selected view changes.
+func entityLODImpostorSphere(xbm *volume.XBrickMap) (mgl32.Vec3, float32, bool) {
+	if xbm == nil || xbm.GetVoxelCount() == 0 {
+		return mgl32.Vec3{}, 0, false
+	}
+	minB, maxB := xbm.ComputeAABB()
+	radius := maxB.Sub(minB).Len() * 0.5
+	if radius <= 0 {
+		return mgl32.Vec3{}, 0, false
+	}
+	return minB.Add(maxB).Mul(0.5), radius, true
+}
+
+// octahedralEncode maps a unit direction to [0,1]² with +Y at the center and
+// -Y folded onto the corners.
+func octahedralEncode(dir mgl32.Vec3) [2]float32 {
+	sum := absf(dir.X()) + absf(dir.Y()) + absf(dir.Z())
+	if sum <= 0 {
+		return [2]float32{0.5, 0.5}
+	}
+	px, pz := dir.X()/sum, dir.Z()/sum
+	if dir.Y() < 0 {
+		px, pz = (1-absf(pz))*signNonZero(px), (1-absf(px))*signNonZero(pz)
+	}
+	return [2]float32{px*0.5 + 0.5, pz*0.5 + 0.5}
+}
+
+func octahedralDecode(uv [2]float32) mgl32.Vec3 {
+	px, pz := uv[0]*2-1, uv[1]*2-1
+	y := 1 - absf(px) - absf(pz)
+	if y < 0 {
+		px, pz = (1-absf(pz))*signNonZero(px), (1-absf(px))*signNonZero(pz)
+	}
+	return mgl32.Vec3{px, y, pz}.Normalize()
+}
+
+func signNonZero(v float32) float32 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
+
+// entityLODImpostorViewDirection returns the baked direction toward the
+// viewer for a cell.
+func entityLODImpostorViewDirection(grid, cellX, cellY int) mgl32.Vec3 {
+	return octahedralDecode([2]float32{
+		(float32(cellX) + 0.5) / float32(grid),
+		(float32(cellY) + 0.5) / float32(grid),
+	})
+}
+
+// entityLODImpostorViewBasis matches CameraState.GetRight/GetUp for a camera
+// looking along -dir with +Y up.
+func entityLODImpostorViewBasis(dir mgl32.Vec3) (right, up mgl32.Vec3) {
+	forward := dir.Mul(-1)
+	upHint := mgl32.Vec3{0, 1, 0}
+	if absf(dir.Y()) > 0.999 {
+		upHint = mgl32.Vec3{0, 0, -1}
+	}
+	right = forward.Cross(upHint).Normalize()
+	up = right.Cross(forward).Normalize()
+	return right, up
+}
+
+// selectEntityLODImpostorView picks the three baked views around localDir,
+// the object-space direction from the entity toward the camera, and their
+// barycentric blend weights.
+func selectEntityLODImpostorView(localDir mgl32.Vec3, grid int) entityLODImpostorView {
+	if grid <= 0 {
+		return entityLODImpostorView{}
+	}
+	if localDir.LenSqr() <= 1e-12 {
+		localDir = mgl32.Vec3{0, 0, 1}
+	}
+	uv := octahedralEncode(localDir.Normalize())
+	gx := uv[0]*float32(grid) - 0.5
+	gy := uv[1]*float32(grid) - 0.5
+	baseX, baseY := float32(math.Floor(float64(gx))), float32(math.Floor(float64(gy)))
+	fx, fy := gx-baseX, gy-baseY
+	cell := func(dx, dy int) uint32 {
+		x := min(max(int(baseX)+dx, 0), grid-1)
+		y := min(max(int(baseY)+dy, 0), grid-1)
+		return uint32(y*grid + x)
+	}
+	if fx+fy <= 1 {
+		return entityLODImpostorView{
+			Index:        cell(0, 0),
+			BlendIndices: [2]uint32{cell(1, 0), cell(0, 1)},
+			BlendWeights: [2]float32{fx, fy},
+		}
+	}
+	return entityLODImpostorView{
+		Index:        cell(1, 1),
+		BlendIndices: [2]uint32{cell(0, 1), cell(1, 0)},
+		BlendWeights: [2]float32{1 - fx, 1 - fy},
+	}
+}
+
+// entityLODImpostorNearestCell returns the baked view closest to localDir.
+func entityLODImpostorNearestCell(localDir mgl32.Vec3, grid int) (int, int) {
+	if localDir.LenSqr() <= 1e-12 {
+		localDir = mgl32.Vec3{0, 0, 1}
+	}
+	uv := octahedralEncode(localDir.Normalize())
+	x := min(max(int(uv[0]*float32(grid)), 0), grid-1)
+	y := min(max(int(uv[1]*float32(grid)), 0), grid-1)
+	return x, y
+}
+
+// bakeEntityLODImpostorAtlas ray-marches every view of xbm. Fully transparent
+// palette entries are skipped so they neither stamp nor occlude.
+func bakeEntityLODImpostorAtlas(xbm *volume.XBrickMap, palette *VoxPalette, grid, cellSize int) (entityLODImpostorAtlas, bool) {
+	center, radius, ok := entityLODImpostorSphere(xbm)
+	if !ok || palette == nil || grid <= 0 || cellSize <= 0 {
+		return entityLODImpostorAtlas{}, false
+	}
+	atlas := entityLODImpostorAtlas{Grid: grid, CellSize: cellSize}
+	width := atlas.Width()
+	atlas.Texels = make([]uint8, width*atlas.Height()*4)
+	normalRowOffset := grid * cellSize
+
+	occupied := false
+	for cellY := 0; cellY < grid; cellY++ {
+		for cellX := 0; cellX < grid; cellX++ {
+			dir := entityLODImpostorViewDirection(grid, cellX, cellY)
+			right, up := entityLODImpostorViewBasis(dir)
+			forward := dir.Mul(-1)
+			for py := 0; py < cellSize; py++ {
+				for px := 0; px < cellSize; px++ {
+					x := ((float32(px)+0.5)/float32(cellSize)*2 - 1) * radius
+					y := (1 - (float32(py)+0.5)/float32(cellSize)*2) * radius
+					origin := center.Add(right.Mul(x)).Add(up.Mul(y)).Add(dir.Mul(radius + 1))
+					hit, t, normal, color := entityLODImpostorTrace(xbm, palette, origin, forward, 2*radius+2)
+					if !hit {
+						continue
+					}
+					occupied = true
+					ax, ay := cellX*cellSize+px, cellY*cellSize+py
+					colorIdx := (ay*width + ax) * 4
+					atlas.Texels[colorIdx+0] = color[0]
+					atlas.Texels[colorIdx+1] = color[1]
+					atlas.Texels[colorIdx+2] = color[2]
+					// Opaque texels keep distant cards readable; sparse per-voxel
+					// alpha composites as a hazy overlay.
+					atlas.Texels[colorIdx+3] = 255
+
+					position := origin.Add(forward.Mul(t))
+					depth := clampF(position.Sub(center).Dot(dir)/radius*0.5+0.5, 0, 1)
+					normalIdx := ((ay+normalRowOffset)*width + ax) * 4
+					atlas.Texels[normalIdx+0] = entityLODEncodeUnitSRGB(normal.Dot(right))
+					atlas.Texels[normalIdx+1] = entityLODEncodeUnitSRGB(normal.Dot(up))
+					atlas.Texels[normalIdx+2] = entityLODEncodeUnitSRGB(normal.Dot(dir))
+					atlas.Texels[normalIdx+3] = uint8(max(1, math.Round(float64(depth)*255)))
+				}
+			}
+		}
+	}
+	if !occupied {
+		return entityLODImpostorAtlas{}, false
+	}
+	for cellY := 0; cellY < 2*grid; cellY++ {
+		for cellX := 0; cellX < grid; cellX++ {
+			entityLODDilateAtlasCell(atlas.Texels, width, cellX*cellSize, cellY*cellSize, cellSize)
+		}
+	}
+	return atlas, true
+}
+
+func entityLODImpostorTrace(xbm *volume.XBrickMap, palette *VoxPalette, origin, dir mgl32.Vec3, tMax float32) (bool, float32, mgl32.Vec3, [4]uint8) {
+	tMin := float32(0)
+	for tMin < tMax {
+		hit, t, coord, normal := xbm.RayMarch(origin, dir, tMin, tMax)
+		if !hit {
+			return false, 0, mgl32.Vec3{}, [4]uint8{}
+		}
+		_, value := xbm.GetVoxel(coord[0], coord[1], coord[2])
+		if color := palette[value]; color[3] != 0 {
+			return true, t, normal, color
+		}
+		tMin = entityLODVoxelExit(origin, dir, coord) + 1e-3
+	}
+	return false, 0, mgl32.Vec3{}, [4]uint8{}
+}
+
+func entityLODVoxelExit(origin, dir mgl32.Vec3, coord [3]int) float32 {
+	exit := float32(math.Inf(1))
+	for axis := 0; axis < 3; axis++ {
+		if dir[axis] == 0 {
+			continue
+		}
+		boundary := float32(coord[axis])
+		if dir[axis] > 0 {
+			boundary++
+		}
+		exit = min(exit, (boundary-origin[axis])/dir[axis])
+	}
+	return exit
+}
+
+// entityLODEncodeUnitSRGB stores v in [-1,1] so that sampling the sRGB atlas
+// returns v*0.5+0.5 in linear space.
+func entityLODEncodeUnitSRGB(v float32) uint8 {
+	linear := float64(clampF(v*0.5+0.5, 0, 1))
+	var encoded float64
+	if linear <= 0.0031308 {
+		encoded = linear * 12.92
+	} else {
+		encoded = 1.055*math.Pow(linear, 1/2.4) - 0.055
+	}
+	return uint8(math.Round(encoded * 255))
+}
+
+func entityLODDilateAtlasCell(texels []uint8, atlasWidth, x0, y0, size int) {
+	cell := make([]uint8, size*size*4)
+	for y := 0; y < size; y++ {
+		row := ((y0+y)*atlasWidth + x0) * 4
+		copy(cell[y*size*4:(y+1)*size*4], texels[row:row+size*4])
+	}
+	entityLODDilateTransparentRGB(cell, size, size)
+	for y := 0; y < size; y++ {
+		row := ((y0+y)*atlasWidth + x0) * 4
+		copy(texels[row:row+size*4], cell[y*size*4:(y+1)*size*4])
+	}
+}
+
+// entityLODImpostorContentHash keys the disk cache on the voxel content, the
+// palette, and the bake layout, so renamed or reloaded assets reuse bakes.
+func entityLODImpostorContentHash(xbm *volume.XBrickMap, palette *VoxPalette, grid, cellSize int) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s:impostor:%d:%d:", entityLODGeneratedAssetVersion, grid, cellSize)
+	for _, color := range palette {
+		hash.Write(color[:])
+	}
+	if err := volume.WriteXBrickMap(hash, xbm, volume.XBrickMapWriteOptions{DisableCompression: true}); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func entityLODImpostorCachePath(dir, hash string) string {
+	return filepath.Join(dir, hash+".png")
+}
+
+func loadEntityLODImpostorAtlas(path string, grid, cellSize int) (entityLODImpostorAtlas, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return entityLODImpostorAtlas{}, false
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		return entityLODImpostorAtlas{}, false
+	}
+	atlas := entityLODImpostorAtlas{Grid: grid, CellSize: cellSize}
+	bounds := img.Bounds()
+	if bounds.Dx() != atlas.Width() || bounds.Dy() != atlas.Height() {
+		return entityLODImpostorAtlas{}, false
+	}
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok {
+		converted := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		for y := 0; y < bounds.Dy(); y++ {
+			for x := 0; x < bounds.Dx(); x++ {
+				converted.Set(x, y, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+			}
+		}
+		nrgba = converted
+	}
+	atlas.Texels = make([]uint8, atlas.Width()*atlas.Height()*4)
+	for y := 0; y < atlas.Height(); y++ {
+		copy(atlas.Texels[y*atlas.Width()*4:(y+1)*atlas.Width()*4], nrgba.Pix[y*nrgba.Stride:y*nrgba.Stride+atlas.Width()*4])
+	}
+	return atlas, true
+}
+
+// saveEntityLODImpostorAtlas writes through a temporary file so concurrent
+// processes never observe a partial PNG.
+func saveEntityLODImpostorAtlas(path string, atlas entityLODImpostorAtlas) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	img := &image.NRGBA{
+		Pix:    atlas.Texels,
+		Stride: atlas.Width() * 4,
+		Rect:   image.Rect(0, 0, atlas.Width(), atlas.Height()),
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(tmp, img); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// defaultEntityLODImpostorCacheDir is used by AssetServerModule when no cache
+// directory is configured. An empty result disables the disk cache.
+func defaultEntityLODImpostorCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil || dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "gekko", "impostors")
+}
+
+// SetEntityLODImpostorCacheDir sets where baked impostor atlases are cached
+// on disk. An empty dir keeps bakes in memory only.
+func (server *AssetServer) SetEntityLODImpostorCacheDir(dir string) {
+	if server == nil {
+		return
+	}
+	server.mu.Lock()
+	server.impostorCacheDir = dir
+	server.mu.Unlock()
+}
+
+// entityLODImpostorState is the progress of an impostor atlas bake.
+type entityLODImpostorState uint8
+
+const (
+	// entityLODImpostorFailed means the model cannot be baked.
+	entityLODImpostorFailed entityLODImpostorState = iota
+	// entityLODImpostorBaking means a worker is still baking the atlas.
+	entityLODImpostorBaking
+	// entityLODImpostorReady means the atlas texture is published.
+	entityLODImpostorReady
+)
+
+// entityLODImpostorBake is an atlas being baked on a worker goroutine. The
+// worker only fills atlas and ok before closing done; the texture is
+// published by the caller that finds it done.
+type entityLODImpostorBake struct {
+	done  chan struct{}
+	atlas entityLODImpostorAtlas
+	ok    bool
+}
+
+// entityLODImpostorTexture returns the octahedral impostor atlas for a
+// geometry/palette pair. The first call starts a bake on a worker and reports
+// entityLODImpostorBaking until a later call finds it finished and publishes
+// the texture. Bakes are shared in memory by cache key and on disk by content
+// hash.
+func (server *AssetServer) entityLODImpostorTexture(geometryID, paletteID AssetId, source *VoxelGeometryAsset) (AssetId, entityLODImpostorState) {
+	if server == nil || source == nil || source.XBrickMap == nil {
+		return AssetId{}, entityLODImpostorFailed
+	}
+	cacheKey := entityLODCacheKey("impostor", geometryID, paletteID)
+	if cached, ok := server.entityLODTextureByCacheKey(cacheKey); ok && cached.Width > 0 && cached.Height > 0 {
+		server.mu.RLock()
+		id := server.textureKeys[cacheKey]
+		server.mu.RUnlock()
+		if id != (AssetId{}) {
+			return id, entityLODImpostorReady
+		}
+	}
+
+	server.mu.RLock()
+	bake := server.impostorBakes[cacheKey]
+	server.mu.RUnlock()
+	if bake == nil {
+		paletteAsset, ok := server.GetVoxelPalette(paletteID)
+		if !ok {
+			return AssetId{}, entityLODImpostorFailed
+		}
+		bake = server.startEntityLODImpostorBake(cacheKey, source.XBrickMap.Copy(), paletteAsset.VoxPalette)
+	}
+	select {
+	case <-bake.done:
+	default:
+		return AssetId{}, entityLODImpostorBaking
+	}
+	if !bake.ok {
+		// Keep the failed bake so the model is not baked again every frame.
+		return AssetId{}, entityLODImpostorFailed
+	}
+	server.mu.Lock()
+	delete(server.impostorBakes, cacheKey)
+	server.mu.Unlock()
+	atlas := bake.atlas
+	return server.createTextureFromTexelsWithCacheKey(cacheKey, atlas.Texels, uint32(atlas.Width()), uint32(atlas.Height()), 1, TextureDimension2D, TextureFormatRGBA8UnormSrgb), entityLODImpostorReady
+}
+
+// startEntityLODImpostorBake loads or bakes xbm's atlas on a worker. xbm must
+// be a copy the caller no longer touches.
+func (server *AssetServer) startEntityLODImpostorBake(cacheKey string, xbm *volume.XBrickMap, palette VoxPalette) *entityLODImpostorBake {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if bake, ok := server.impostorBakes[cacheKey]; ok {
+		return bake
+	}
+	if server.impostorBakes == nil {
+		server.impostorBakes = make(map[string]*entityLODImpostorBake)
+	}
+	bake := &entityLODImpostorBake{done: make(chan struct{})}
+	server.impostorBakes[cacheKey] = bake
+	cacheDir := server.impostorCacheDir
+
+	go func() {
+		defer close(bake.done)
+		const grid, cellSize = entityLODImpostorViewGrid, entityLODImpostorCellSize
+		cachePath := ""
+		if cacheDir != "" {
+			if hash, err := entityLODImpostorContentHash(xbm, &palette, grid, cellSize); err == nil {
+				cachePath = entityLODImpostorCachePath(cacheDir, hash)
+			}
+		}
+		if cachePath != "" {
+			if bake.atlas, bake.ok = loadEntityLODImpostorAtlas(cachePath, grid, cellSize); bake.ok {
+				return
+			}
+		}
+		if bake.atlas, bake.ok = bakeEntityLODImpostorAtlas(xbm, &palette, grid, cellSize); !bake.ok {
+			return
+		}
+		if cachePath != "" {
+			if err := saveEntityLODImpostorAtlas(cachePath, bake.atlas); err != nil {
+				fmt.Printf("WARNING: failed to cache impostor atlas %s: %v\n", cachePath, err)
+			}
+		}
+	}()
+	return bake
+}
+
+// entityLODImpostorViewForCamera converts the world eye position into the
+// entity's object space and selects the blended views.
+func entityLODImpostorViewForCamera(eye, worldCenter mgl32.Vec3, rotation mgl32.Quat) entityLODImpostorView {
+	toCamera := eye.Sub(worldCenter)
+	if rotation.Len() > 0 {
+		toCamera = rotation.Inverse().Rotate(toCamera)
+	}
+	return selectEntityLODImpostorView(toCamera, entityLODImpostorViewGrid)
+}
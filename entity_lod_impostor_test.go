@@ -0,0 +1,200 @@
+package gekko
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// awaitEntityLODImpostorTexture polls the impostor texture like the frame
+// loop does until its background bake finishes.
+func awaitEntityLODImpostorTexture(t *testing.T, server *AssetServer, geometryID, paletteID AssetId, source *VoxelGeometryAsset) (AssetId, bool) {
+	t.Helper()
+	awaitEntityLODImpostorBakes(t, server)
+	id, bake := server.entityLODImpostorTexture(geometryID, paletteID, source)
+	if bake == entityLODImpostorBaking {
+		awaitEntityLODImpostorBakes(t, server)
+		id, bake = server.entityLODImpostorTexture(geometryID, paletteID, source)
+	}
+	return id, bake == entityLODImpostorReady
+}
+
+// awaitEntityLODImpostorBakes waits for every started impostor bake.
+func awaitEntityLODImpostorBakes(t *testing.T, server *AssetServer) {
+	t.Helper()
+	server.mu.RLock()
+	bakes := make([]*entityLODImpostorBake, 0, len(server.impostorBakes))
+	for _, bake := range server.impostorBakes {
+		bakes = append(bakes, bake)
+	}
+	server.mu.RUnlock()
+	for _, bake := range bakes {
+		select {
+		case <-bake.done:
+		case <-time.After(30 * time.Second):
+			t.Fatal("impostor bake did not finish")
+		}
+	}
+}
+
+func TestEntityLODImpostorTextureBakesInBackground(t *testing.T) {
+	server := newSpawnTestAssetServer()
+	modelID := server.CreateFrameModel(10, 10, 10, 2, 1.0)
+	paletteID := server.CreateSimplePalette([4]uint8{90, 160, 220, 255})
+	source, ok := server.GetVoxelGeometry(modelID)
+	if !ok {
+		t.Fatal("expected frame geometry")
+	}
+	if _, bake := server.entityLODImpostorTexture(modelID, paletteID, &source); bake != entityLODImpostorBaking {
+		t.Fatalf("expected the first request to start a background bake, got state %d", bake)
+	}
+	awaitEntityLODImpostorBakes(t, server)
+	textureID, bake := server.entityLODImpostorTexture(modelID, paletteID, &source)
+	if bake != entityLODImpostorReady || textureID == (AssetId{}) {
+		t.Fatalf("expected the finished bake to be published, got state %d", bake)
+	}
+	if len(server.impostorBakes) != 0 {
+		t.Fatal("expected the published bake to be dropped")
+	}
+
+	empty := server.CreateVoxelModel(VoxModel{}, 1.0)
+	emptySource, _ := server.GetVoxelGeometry(empty)
+	server.entityLODImpostorTexture(empty, paletteID, &emptySource)
+	awaitEntityLODImpostorBakes(t, server)
+	for i := 0; i < 2; i++ {
+		if _, bake := server.entityLODImpostorTexture(empty, paletteID, &emptySource); bake != entityLODImpostorFailed {
+			t.Fatalf("expected an empty model to fail without rebaking, got state %d", bake)
+		}
+	}
+}
+
+func TestOctahedralEncodeDecodeRoundTrips(t *testing.T) {
+	for _, dir := range []mgl32.Vec3{
+		{0, 1, 0},
+		{0, -1, 0},
+		{1, 0, 0},
+		{0, 0, -1},
+		mgl32.Vec3{1, -2, 3}.Normalize(),
+		mgl32.Vec3{-0.3, 0.2, -0.9}.Normalize(),
+	} {
+		got := octahedralDecode(octahedralEncode(dir))
+		if got.Dot(dir) < 0.9999 {
+			t.Fatalf("octahedral round trip of %v returned %v", dir, got)
+		}
+	}
+}
+
+func TestSelectEntityLODImpostorViewBlendsNeighbouringCells(t *testing.T) {
+	const grid = 8
+	baked := entityLODImpostorViewDirection(grid, 5, 2)
+	view := selectEntityLODImpostorView(baked, grid)
+	if view.Index != 2*grid+5 {
+		t.Fatalf("expected baked direction to select its own cell, got %+v", view)
+	}
+	if view.BlendWeights[0] > 1e-4 || view.BlendWeights[1] > 1e-4 {
+		t.Fatalf("expected no blending at a cell center, got %+v", view)
+	}
+
+	between := entityLODImpostorViewDirection(grid, 5, 2).Add(entityLODImpostorViewDirection(grid, 6, 2)).Normalize()
+	view = selectEntityLODImpostorView(between, grid)
+	if view.BlendWeights[0] < 0.3 || view.BlendWeights[0] > 0.7 {
+		t.Fatalf("expected an even blend between neighbouring cells, got %+v", view)
+	}
+	if view.Index != 2*grid+5 || view.BlendIndices[0] != 2*grid+6 {
+		t.Fatalf("expected cells (5,2) and (6,2) to blend, got %+v", view)
+	}
+	if sum := view.BlendWeights[0] + view.BlendWeights[1]; sum > 1 {
+		t.Fatalf("expected blend weights to leave the primary view a share, got %v", sum)
+	}
+}
+
+func TestBakeEntityLODImpostorAtlasStoresViewSpaceNormalsAndDepth(t *testing.T) {
+	server := newSpawnTestAssetServer()
+	modelID := server.CreateCubeModel(8, 8, 8, 1.0)
+	source, ok := server.GetVoxelGeometry(modelID)
+	if !ok {
+		t.Fatal("expected cube geometry")
+	}
+	var palette VoxPalette
+	for i := 1; i < len(palette); i++ {
+		palette[i] = [4]uint8{200, 100, 50, 255}
+	}
+
+	const grid, cellSize = 4, 16
+	atlas, ok := bakeEntityLODImpostorAtlas(source.XBrickMap, &palette, grid, cellSize)
+	if !ok {
+		t.Fatal("expected impostor bake")
+	}
+	if atlas.Width() != grid*cellSize || atlas.Height() != 2*grid*cellSize || len(atlas.Texels) != atlas.Width()*atlas.Height()*4 {
+		t.Fatalf("unexpected atlas layout %dx%d with %d bytes", atlas.Width(), atlas.Height(), len(atlas.Texels))
+	}
+
+	// Every view of a cube sees it at the card center, facing the viewer.
+	for cellY := 0; cellY < grid; cellY++ {
+		for cellX := 0; cellX < grid; cellX++ {
+			x, y := cellX*cellSize+cellSize/2, cellY*cellSize+cellSize/2
+			color := atlas.Texels[(y*atlas.Width()+x)*4:][:4]
+			if color[0] != 200 || color[3] != 255 {
+				t.Fatalf("cell (%d,%d): expected opaque palette color at the center, got %v", cellX, cellY, color)
+			}
+			normal := atlas.Texels[((y+grid*cellSize)*atlas.Width()+x)*4:][:4]
+			if normal[2] < 200 {
+				t.Fatalf("cell (%d,%d): expected view-space normal to face the viewer, got %v", cellX, cellY, normal)
+			}
+			if normal[3] <= 128 {
+				t.Fatalf("cell (%d,%d): expected the visible surface in front of the card plane, got depth %d", cellX, cellY, normal[3])
+			}
+		}
+	}
+	if corner := atlas.Texels[3]; corner != 0 {
+		t.Fatalf("expected the corner of the first view to stay transparent, got alpha %d", corner)
+	}
+}
+
+func TestEntityLODImpostorTextureReusesDiskCacheByContentHash(t *testing.T) {
+	cacheDir := t.TempDir()
+	bake := func() (*AssetServer, []byte) {
+		server := newSpawnTestAssetServer()
+		server.SetEntityLODImpostorCacheDir(cacheDir)
+		modelID := server.CreateFrameModel(10, 10, 10, 2, 1.0)
+		paletteID := server.CreateSimplePalette([4]uint8{90, 160, 220, 255})
+		source, ok := server.GetVoxelGeometry(modelID)
+		if !ok {
+			t.Fatal("expected frame geometry")
+		}
+		textureID, ok := awaitEntityLODImpostorTexture(t, server, modelID, paletteID, &source)
+		if !ok {
+			t.Fatal("expected impostor texture")
+		}
+		return server, server.textures[textureID].Texels
+	}
+
+	_, first := bake()
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one cached atlas, got %v (err=%v)", entries, err)
+	}
+	cached := cacheDir + "/" + entries[0].Name()
+	info, err := os.Stat(cached)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, second := bake()
+	if !bytes.Equal(first, second) {
+		t.Fatal("expected a new asset server to load identical texels from the disk cache")
+	}
+	after, err := os.Stat(cached)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.ModTime().Equal(info.ModTime()) {
+		t.Fatal("expected cached atlas to be reused rather than rewritten")
+	}
+	if entries, _ := os.ReadDir(cacheDir); len(entries) != 1 {
+		t.Fatalf("expected identical content to share one cache entry, got %d", len(entries))
+	}
+}
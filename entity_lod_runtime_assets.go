@@ -6,9 +6,8 @@ import (
 )
 
 const (
-	entityLODGeneratedAssetVersion = "e009-v3"
+	entityLODGeneratedAssetVersion = "e009-v4"
 	entityLODSimplifiedScale       = 0.5
-	entityLODImpostorTextureSize   = 64
 	entityLODDotTextureSize        = 8
 )
 
@@ -65,23 +64,6 @@ func (server *AssetServer) createTextureFromTexelsWithCacheKey(cacheKey string,
 	return id
 }
 
-func entityLODGeometryBounds(asset *VoxelGeometryAsset) (minX, minY, minZ, maxX, maxY, maxZ int, ok bool) {
-	if asset == nil || asset.XBrickMap == nil {
-		return 0, 0, 0, 0, 0, 0, false
-	}
-	minB, maxB := asset.XBrickMap.ComputeAABB()
-	if maxB.Sub(minB).LenSqr() <= 0 {
-		return 0, 0, 0, 0, 0, 0, false
-	}
-	return int(math.Floor(float64(minB.X()))),
-		int(math.Floor(float64(minB.Y()))),
-		int(math.Floor(float64(minB.Z()))),
-		int(math.Ceil(float64(maxB.X()))),
-		int(math.Ceil(float64(maxB.Y()))),
-		int(math.Ceil(float64(maxB.Z()))),
-		true
-}
-
 func entityLODGeometryExtents(asset *VoxelGeometryAsset) (extentX, extentY, extentZ float32) {
 	if asset == nil {
 		return 0, 0, 0
@@ -138,108 +120,6 @@ func (server *AssetServer) entityLODSimplifiedGeometry(geometryID, paletteID Ass
 	return id, &asset, true
 }
 
-func (server *AssetServer) entityLODImpostorTexture(geometryID, paletteID AssetId, source *VoxelGeometryAsset) (AssetId, bool) {
-	if server == nil || source == nil || source.XBrickMap == nil {
-		return AssetId{}, false
-	}
-	cacheKey := entityLODCacheKey("impostor", geometryID, paletteID)
-	if cached, ok := server.entityLODTextureByCacheKey(cacheKey); ok && cached.Width > 0 && cached.Height > 0 {
-		server.mu.RLock()
-		id := server.textureKeys[cacheKey]
-		server.mu.RUnlock()
-		if id != (AssetId{}) {
-			return id, true
-		}
-	}
-	paletteAsset, ok := server.GetVoxelPalette(paletteID)
-	if !ok {
-		return AssetId{}, false
-	}
-	minX, minY, minZ, maxX, maxY, maxZ, ok := entityLODGeometryBounds(source)
-	if !ok || maxX <= minX || maxY <= minY || maxZ <= minZ {
-		return AssetId{}, false
-	}
-
-	const size = entityLODImpostorTextureSize
-	texels := make([]uint8, size*size*4)
-	zBuffer := make([]float32, size*size)
-	for i := range zBuffer {
-		zBuffer[i] = float32(math.Inf(-1))
-	}
-
-	spanX := float32(maxX - minX)
-	spanY := float32(maxY - minY)
-	if spanX <= 0 || spanY <= 0 {
-		return AssetId{}, false
-	}
-
-	occupied := false
-	for gx := minX; gx < maxX; gx++ {
-		for gy := minY; gy < maxY; gy++ {
-			for gz := minZ; gz < maxZ; gz++ {
-				found, value := source.XBrickMap.GetVoxel(gx, gy, gz)
-				if !found {
-					continue
-				}
-				color := paletteAsset.VoxPalette[value]
-				alpha := color[3]
-				if alpha == 0 {
-					// Fully transparent voxels should not stamp an impostor pixel or
-					// occlude visible voxels behind them.
-					continue
-				}
-				// Stamp the full projected footprint instead of a single texel so
-				// thin voxel models do not alias into sparse, semi-transparent cards.
-				x0 := int(math.Floor(float64((float32(gx)-float32(minX))/spanX * float32(size))))
-				x1 := int(math.Ceil(float64((float32(gx+1)-float32(minX))/spanX * float32(size))))
-				y0 := int(math.Floor(float64((float32(gy)-float32(minY))/spanY * float32(size))))
-				y1 := int(math.Ceil(float64((float32(gy+1)-float32(minY))/spanY * float32(size))))
-				if x1 <= x0 {
-					x1 = x0 + 1
-				}
-				if y1 <= y0 {
-					y1 = y0 + 1
-				}
-				if x0 < 0 {
-					x0 = 0
-				}
-				if y0 < 0 {
-					y0 = 0
-				}
-				if x1 > size {
-					x1 = size
-				}
-				if y1 > size {
-					y1 = size
-				}
-				depth := float32(gz) + 0.5
-				for px := x0; px < x1; px++ {
-					for pyTex := y0; pyTex < y1; pyTex++ {
-						py := size - 1 - pyTex
-						idx := py*size + px
-						if depth < zBuffer[idx] {
-							continue
-						}
-						zBuffer[idx] = depth
-						texels[idx*4+0] = color[0]
-						texels[idx*4+1] = color[1]
-						texels[idx*4+2] = color[2]
-						// Use fully opaque impostor texels for readability. Sparse
-						// per-voxel alpha makes the card composite as a hazy overlay.
-						texels[idx*4+3] = 255
-						occupied = true
-					}
-				}
-			}
-		}
-	}
-	if !occupied {
-		return AssetId{}, false
-	}
-	entityLODDilateTransparentRGB(texels, size, size)
-	return server.createTextureFromTexelsWithCacheKey(cacheKey, texels, size, size, 1, TextureDimension2D, TextureFormatRGBA8UnormSrgb), true
-}
-
 func entityLODDilateTransparentRGB(texels []uint8, width, height int) {
 	if width <= 0 || height <= 0 || len(texels) != width*height*4 {
 		return
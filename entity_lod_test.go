@@ -99,3 +99,135 @@ func TestSelectEntityLODUsesTransformPosition(t *testing.T) {
 		t.Fatalf("expected dot representation, got %v", got.Representation)
 	}
 }
+
+func TestSelectEntityLODByDistanceAppliesHysteresisAroundActiveBand(t *testing.T) {
+	component := &EntityLODComponent{
+		Bands: []EntityLODBand{
+			{MaxDistance: 50, Hysteresis: 5, Representation: EntityLODRepresentationFullVoxel},
+			{MaxDistance: 0, Representation: EntityLODRepresentationDot},
+		},
+	}
+	component.ClearRuntimeSelection()
+
+	steps := []struct {
+		distance float32
+		wantBand int
+	}{
+		{distance: 49, wantBand: 0},
+		{distance: 54, wantBand: 0},
+		{distance: 56, wantBand: 1},
+		{distance: 47, wantBand: 1},
+		{distance: 44, wantBand: 0},
+	}
+	for _, step := range steps {
+		got, err := SelectEntityLODByDistance(component, step.distance)
+		if err != nil {
+			t.Fatalf("SelectEntityLODByDistance(%v) returned error: %v", step.distance, err)
+		}
+		if got.BandIndex != step.wantBand {
+			t.Fatalf("distance %v: expected band %d, got %d", step.distance, step.wantBand, got.BandIndex)
+		}
+		component.ApplySelection(got)
+	}
+}
+
+func TestSelectEntityLODForViewUsesScreenSizeBands(t *testing.T) {
+	component := &EntityLODComponent{
+		DistanceMetric: EntityLODDistanceMetricScreenSize,
+		Bands: []EntityLODBand{
+			{MinScreenSize: 200, Representation: EntityLODRepresentationFullVoxel},
+			{MinScreenSize: 20, Representation: EntityLODRepresentationImpostor},
+			{Representation: EntityLODRepresentationDot},
+		},
+	}
+	if err := component.Validate(); err != nil {
+		t.Fatalf("expected screen-size bands to validate: %v", err)
+	}
+	view := EntityLODView{FovY: mgl32.DegToRad(90), ViewportHeight: 1000}
+	transform := &TransformComponent{Position: mgl32.Vec3{0, 0, -100}, Rotation: mgl32.QuatIdent(), Scale: mgl32.Vec3{1, 1, 1}}
+
+	small := EntityLODBounds{Min: mgl32.Vec3{-1, -1, -101}, Max: mgl32.Vec3{1, 1, -99}}
+	got, err := SelectEntityLODForView(view, small, transform, component)
+	if err != nil {
+		t.Fatalf("SelectEntityLODForView returned error: %v", err)
+	}
+	// radius sqrt(3) at distance 100 with tan(45deg)=1 covers ~17px.
+	if got.ScreenSize < 17 || got.ScreenSize > 18 || got.Representation != EntityLODRepresentationDot {
+		t.Fatalf("expected small bounds to select dot at ~17px, got %+v", got)
+	}
+
+	large := EntityLODBounds{Min: mgl32.Vec3{-20, -20, -120}, Max: mgl32.Vec3{20, 20, -80}}
+	got, err = SelectEntityLODForView(view, large, transform, component)
+	if err != nil {
+		t.Fatalf("SelectEntityLODForView returned error: %v", err)
+	}
+	if got.BandIndex != 0 || got.Representation != EntityLODRepresentationFullVoxel {
+		t.Fatalf("expected large bounds at the same distance to keep full detail, got %+v", got)
+	}
+	if got.Distance < 99.9 || got.Distance > 100.1 {
+		t.Fatalf("expected distance to still report origin distance, got %v", got.Distance)
+	}
+
+	invalid := *component
+	invalid.Bands = []EntityLODBand{
+		{MinScreenSize: 20, Representation: EntityLODRepresentationFullVoxel},
+		{MinScreenSize: 200, Representation: EntityLODRepresentationImpostor},
+		{Representation: EntityLODRepresentationDot},
+	}
+	if err := invalid.Validate(); err == nil {
+		t.Fatal("expected increasing screen sizes to fail validation")
+	}
+}
+
+func TestSelectEntityLODForViewUsesNearestBoundsPoint(t *testing.T) {
+	component := &EntityLODComponent{
+		DistanceMetric: EntityLODDistanceMetricBoundsNearest,
+		Bands: []EntityLODBand{
+			{MaxDistance: 10, Representation: EntityLODRepresentationFullVoxel},
+			{MaxDistance: 0, Representation: EntityLODRepresentationDot},
+		},
+	}
+	transform := &TransformComponent{Position: mgl32.Vec3{0, 0, -30}, Rotation: mgl32.QuatIdent(), Scale: mgl32.Vec3{1, 1, 1}}
+	bounds := EntityLODBounds{Min: mgl32.Vec3{-25, -5, -55}, Max: mgl32.Vec3{25, 5, -5}}
+
+	got, err := SelectEntityLODForView(EntityLODView{}, bounds, transform, component)
+	if err != nil {
+		t.Fatalf("SelectEntityLODForView returned error: %v", err)
+	}
+	if got.Distance != 5 || got.BandIndex != 0 {
+		t.Fatalf("expected nearest bounds distance 5 in band 0, got %+v", got)
+	}
+}
+
+func TestEntityLODComponentReportsTransitionWindow(t *testing.T) {
+	component := &EntityLODComponent{
+		TransitionDuration: 0.5,
+		Bands: []EntityLODBand{
+			{MaxDistance: 10, Representation: EntityLODRepresentationFullVoxel},
+			{MaxDistance: 0, Representation: EntityLODRepresentationImpostor},
+		},
+	}
+	component.ClearRuntimeSelection()
+
+	near, _ := SelectEntityLODByDistance(component, 5)
+	component.ApplySelection(near)
+	if selection, _ := component.ActiveSelection(); selection.Transitioning() {
+		t.Fatalf("expected first selection to be instant, got %+v", selection)
+	}
+
+	far, _ := SelectEntityLODByDistance(component, 20)
+	component.ApplySelection(far)
+	selection, ok := component.ActiveSelection()
+	if !ok || !selection.Transitioning() || selection.PreviousBandIndex != 0 || selection.PreviousRepresentation != EntityLODRepresentationFullVoxel {
+		t.Fatalf("expected transition from full voxel, got %+v", selection)
+	}
+
+	component.AdvanceTransition(0.25)
+	if selection, _ := component.ActiveSelection(); selection.TransitionProgress < 0.49 || selection.TransitionProgress > 0.51 {
+		t.Fatalf("expected half-way progress, got %v", selection.TransitionProgress)
+	}
+	component.AdvanceTransition(0.5)
+	if selection, _ := component.ActiveSelection(); selection.Transitioning() || selection.PreviousBandIndex != -1 || selection.TransitionProgress != 1 {
+		t.Fatalf("expected completed transition, got %+v", selection)
+	}
+}
@@ -0,0 +1,261 @@
+package mesh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+const (
+	glbMagic          = 0x46546C67 // "glTF"
+	glbVersion        = 2
+	glbChunkJSON      = 0x4E4F534A
+	glbChunkBIN       = 0x004E4942
+	gltfFloat         = 5126
+	gltfUnsignedInt   = 5125
+	gltfArrayBuffer   = 34962
+	gltfElementBuffer = 34963
+)
+
+type gltfDocument struct {
+	Asset       gltfAsset        `json:"asset"`
+	Scene       int              `json:"scene"`
+	Scenes      []gltfScene      `json:"scenes"`
+	Nodes       []gltfNode       `json:"nodes"`
+	Meshes      []gltfMesh       `json:"meshes"`
+	Materials   []gltfMaterial   `json:"materials,omitempty"`
+	Accessors   []gltfAccessor   `json:"accessors,omitempty"`
+	BufferViews []gltfBufferView `json:"bufferViews,omitempty"`
+	Buffers     []gltfBuffer     `json:"buffers,omitempty"`
+}
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator,omitempty"`
+}
+
+type gltfScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+type gltfNode struct {
+	Name string `json:"name,omitempty"`
+	Mesh int    `json:"mesh"`
+}
+
+type gltfMesh struct {
+	Name       string          `json:"name,omitempty"`
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    int            `json:"indices"`
+	Material   int            `json:"material"`
+}
+
+type gltfMaterial struct {
+	Name                 string                  `json:"name,omitempty"`
+	PBRMetallicRoughness gltfPBRMetallicRoughess `json:"pbrMetallicRoughness"`
+	EmissiveFactor       *[3]float32             `json:"emissiveFactor,omitempty"`
+	AlphaMode            string                  `json:"alphaMode,omitempty"`
+}
+
+type gltfPBRMetallicRoughess struct {
+	BaseColorFactor [4]float32 `json:"baseColorFactor"`
+	MetallicFactor  float32    `json:"metallicFactor"`
+	RoughnessFactor float32    `json:"roughnessFactor"`
+}
+
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float32 `json:"min,omitempty"`
+	Max           []float32 `json:"max,omitempty"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target,omitempty"`
+}
+
+type gltfBuffer struct {
+	ByteLength int `json:"byteLength"`
+}
+
+// WriteGLB writes m as a binary glTF 2.0 file with one node, one mesh and one
+// primitive per material group. glTF meshes need at least one primitive, so
+// an empty mesh is an error.
+func WriteGLB(w io.Writer, m *Mesh) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	if len(m.Primitives) == 0 {
+		return fmt.Errorf("mesh %q has no primitives", m.Name)
+	}
+	doc := gltfDocument{
+		Asset:  gltfAsset{Version: "2.0", Generator: "gekko"},
+		Scenes: []gltfScene{{Nodes: []int{0}}},
+		Nodes:  []gltfNode{{Name: m.Name, Mesh: 0}},
+		Meshes: []gltfMesh{{Name: m.Name, Primitives: []gltfPrimitive{}}},
+	}
+	for _, material := range m.Materials {
+		out := gltfMaterial{
+			Name: material.Name,
+			PBRMetallicRoughness: gltfPBRMetallicRoughess{
+				BaseColorFactor: linearBaseColor(material.BaseColor),
+				MetallicFactor:  material.Metallic,
+				RoughnessFactor: material.Roughness,
+			},
+		}
+		if material.Emissive != ([3]float32{}) {
+			emissive := material.Emissive
+			out.EmissiveFactor = &emissive
+		}
+		if material.BaseColor[3] < 1 {
+			out.AlphaMode = "BLEND"
+		}
+		doc.Materials = append(doc.Materials, out)
+	}
+
+	var bin bytes.Buffer
+	addView := func(data []byte, target int) int {
+		for bin.Len()%4 != 0 {
+			bin.WriteByte(0)
+		}
+		doc.BufferViews = append(doc.BufferViews, gltfBufferView{
+			ByteOffset: bin.Len(),
+			ByteLength: len(data),
+			Target:     target,
+		})
+		bin.Write(data)
+		return len(doc.BufferViews) - 1
+	}
+	for _, primitive := range m.Primitives {
+		if len(primitive.Indices) == 0 {
+			continue
+		}
+		minB, maxB := positionBounds(primitive.Positions)
+		positionView := addView(float3Bytes(primitive.Positions), gltfArrayBuffer)
+		doc.Accessors = append(doc.Accessors, gltfAccessor{
+			BufferView:    positionView,
+			ComponentType: gltfFloat,
+			Count:         len(primitive.Positions),
+			Type:          "VEC3",
+			Min:           minB[:],
+			Max:           maxB[:],
+		})
+		positionAccessor := len(doc.Accessors) - 1
+		normalView := addView(float3Bytes(primitive.Normals), gltfArrayBuffer)
+		doc.Accessors = append(doc.Accessors, gltfAccessor{
+			BufferView:    normalView,
+			ComponentType: gltfFloat,
+			Count:         len(primitive.Normals),
+			Type:          "VEC3",
+		})
+		normalAccessor := len(doc.Accessors) - 1
+		indexData := make([]byte, len(primitive.Indices)*4)
+		for i, index := range primitive.Indices {
+			binary.LittleEndian.PutUint32(indexData[i*4:], index)
+		}
+		indexView := addView(indexData, gltfElementBuffer)
+		doc.Accessors = append(doc.Accessors, gltfAccessor{
+			BufferView:    indexView,
+			ComponentType: gltfUnsignedInt,
+			Count:         len(primitive.Indices),
+			Type:          "SCALAR",
+		})
+		doc.Meshes[0].Primitives = append(doc.Meshes[0].Primitives, gltfPrimitive{
+			Attributes: map[string]int{"POSITION": positionAccessor, "NORMAL": normalAccessor},
+			Indices:    len(doc.Accessors) - 1,
+			Material:   primitive.Material,
+		})
+	}
+	for bin.Len()%4 != 0 {
+		bin.WriteByte(0)
+	}
+	if bin.Len() > 0 {
+		doc.Buffers = []gltfBuffer{{ByteLength: bin.Len()}}
+	}
+
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	for len(jsonData)%4 != 0 {
+		jsonData = append(jsonData, ' ')
+	}
+	total := 12 + 8 + len(jsonData)
+	if bin.Len() > 0 {
+		total += 8 + bin.Len()
+	}
+	var header [12]byte
+	binary.LittleEndian.PutUint32(header[0:4], glbMagic)
+	binary.LittleEndian.PutUint32(header[4:8], glbVersion)
+	binary.LittleEndian.PutUint32(header[8:12], uint32(total))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	if err := writeGLBChunk(w, glbChunkJSON, jsonData); err != nil {
+		return err
+	}
+	if bin.Len() > 0 {
+		return writeGLBChunk(w, glbChunkBIN, bin.Bytes())
+	}
+	return nil
+}
+
+// SaveGLB writes m to path, creating parent directories as needed.
+func SaveGLB(path string, m *Mesh) error {
+	var out bytes.Buffer
+	if err := WriteGLB(&out, m); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, out.Bytes(), 0644)
+}
+
+func writeGLBChunk(w io.Writer, chunkType uint32, data []byte) error {
+	var header [8]byte
+	binary.LittleEndian.PutUint32(header[0:4], uint32(len(data)))
+	binary.LittleEndian.PutUint32(header[4:8], chunkType)
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+func float3Bytes(values [][3]float32) []byte {
+	out := make([]byte, len(values)*12)
+	for i, value := range values {
+		for axis := 0; axis < 3; axis++ {
+			binary.LittleEndian.PutUint32(out[i*12+axis*4:], math.Float32bits(value[axis]))
+		}
+	}
+	return out
+}
+
+func linearBaseColor(color [4]float32) [4]float32 {
+	out := color
+	for i := 0; i < 3; i++ {
+		c := float64(color[i])
+		if c <= 0.04045 {
+			out[i] = float32(c / 12.92)
+		} else {
+			out[i] = float32(math.Pow((c+0.055)/1.055, 2.4))
+		}
+	}
+	return out
+}
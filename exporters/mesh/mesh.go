@@ -0,0 +1,77 @@
+// Package mesh writes triangle meshes produced from voxel content to
+// interchange formats (binary glTF 2.0 and Wavefront OBJ/MTL).
+package mesh
+
+import (
+	"fmt"
+	"math"
+)
+
+// Material holds the surface parameters shared by every triangle of a
+// primitive. BaseColor RGB is sRGB encoded; the glTF writer linearizes it.
+type Material struct {
+	Name      string
+	BaseColor [4]float32
+	Metallic  float32
+	Roughness float32
+	Emissive  [3]float32
+}
+
+// Primitive is an indexed triangle list drawn with one material.
+type Primitive struct {
+	Material  int
+	Positions [][3]float32
+	Normals   [][3]float32
+	Indices   []uint32
+}
+
+type Mesh struct {
+	Name       string
+	Materials  []Material
+	Primitives []Primitive
+}
+
+func (m *Mesh) TriangleCount() int {
+	count := 0
+	for _, primitive := range m.Primitives {
+		count += len(primitive.Indices) / 3
+	}
+	return count
+}
+
+// Validate reports structural problems that would make either writer emit a
+// broken file.
+func (m *Mesh) Validate() error {
+	if m == nil {
+		return fmt.Errorf("mesh is nil")
+	}
+	for i, primitive := range m.Primitives {
+		if primitive.Material < 0 || primitive.Material >= len(m.Materials) {
+			return fmt.Errorf("primitive %d references missing material %d", i, primitive.Material)
+		}
+		if len(primitive.Normals) != len(primitive.Positions) {
+			return fmt.Errorf("primitive %d has %d normals for %d positions", i, len(primitive.Normals), len(primitive.Positions))
+		}
+		if len(primitive.Indices)%3 != 0 {
+			return fmt.Errorf("primitive %d index count %d is not a multiple of 3", i, len(primitive.Indices))
+		}
+		for _, index := range primitive.Indices {
+			if int(index) >= len(primitive.Positions) {
+				return fmt.Errorf("primitive %d index %d is out of range", i, index)
+			}
+		}
+	}
+	return nil
+}
+
+func positionBounds(positions [][3]float32) ([3]float32, [3]float32) {
+	minB := [3]float32{math.MaxFloat32, math.MaxFloat32, math.MaxFloat32}
+	maxB := [3]float32{-math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32}
+	for _, p := range positions {
+		for axis := 0; axis < 3; axis++ {
+			minB[axis] = min(minB[axis], p[axis])
+			maxB[axis] = max(maxB[axis], p[axis])
+		}
+	}
+	return minB, maxB
+}
@@ -0,0 +1,117 @@
+package mesh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func buildTestMesh() *Mesh {
+	return &Mesh{
+		Name: "quad test",
+		Materials: []Material{
+			{Name: "red", BaseColor: [4]float32{1, 0, 0, 1}, Roughness: 0.5},
+			{Name: "glass", BaseColor: [4]float32{0.5, 0.5, 1, 0.25}, Emissive: [3]float32{0.2, 0, 0}},
+		},
+		Primitives: []Primitive{
+			{
+				Material:  0,
+				Positions: [][3]float32{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}},
+				Normals:   [][3]float32{{0, 0, 1}, {0, 0, 1}, {0, 0, 1}, {0, 0, 1}},
+				Indices:   []uint32{0, 1, 2, 0, 2, 3},
+			},
+			{
+				Material:  1,
+				Positions: [][3]float32{{0, 0, 1}, {1, 0, 1}, {1, 1, 1}},
+				Normals:   [][3]float32{{0, 0, 1}, {0, 0, 1}, {0, 0, 1}},
+				Indices:   []uint32{0, 1, 2},
+			},
+		},
+	}
+}
+
+func TestWriteGLBProducesValidContainer(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGLB(&buf, buildTestMesh()); err != nil {
+		t.Fatalf("WriteGLB failed: %v", err)
+	}
+	data := buf.Bytes()
+	if binary.LittleEndian.Uint32(data[0:4]) != glbMagic || binary.LittleEndian.Uint32(data[4:8]) != glbVersion {
+		t.Fatal("expected glTF binary magic and version 2")
+	}
+	if int(binary.LittleEndian.Uint32(data[8:12])) != len(data) {
+		t.Fatalf("header length %d does not match file length %d", binary.LittleEndian.Uint32(data[8:12]), len(data))
+	}
+	jsonLength := int(binary.LittleEndian.Uint32(data[12:16]))
+	if binary.LittleEndian.Uint32(data[16:20]) != glbChunkJSON || jsonLength%4 != 0 {
+		t.Fatal("expected a 4-byte aligned JSON chunk first")
+	}
+	var doc gltfDocument
+	if err := json.Unmarshal(data[20:20+jsonLength], &doc); err != nil {
+		t.Fatalf("invalid glTF JSON: %v", err)
+	}
+	binOffset := 20 + jsonLength
+	binLength := int(binary.LittleEndian.Uint32(data[binOffset : binOffset+4]))
+	if binary.LittleEndian.Uint32(data[binOffset+4:binOffset+8]) != glbChunkBIN || binLength != doc.Buffers[0].ByteLength {
+		t.Fatal("expected BIN chunk matching the declared buffer length")
+	}
+	if len(doc.Meshes) != 1 || len(doc.Meshes[0].Primitives) != 2 || len(doc.Materials) != 2 {
+		t.Fatalf("expected one mesh with two primitives and two materials, got %+v", doc.Meshes)
+	}
+	if doc.Materials[1].AlphaMode != "BLEND" || doc.Materials[1].EmissiveFactor == nil {
+		t.Fatalf("expected translucent emissive material, got %+v", doc.Materials[1])
+	}
+	indices := doc.Accessors[doc.Meshes[0].Primitives[0].Indices]
+	if indices.Count != 6 || indices.ComponentType != gltfUnsignedInt {
+		t.Fatalf("unexpected index accessor %+v", indices)
+	}
+	position := doc.Accessors[doc.Meshes[0].Primitives[0].Attributes["POSITION"]]
+	if len(position.Min) != 3 || position.Max[0] != 1 || position.Min[0] != 0 {
+		t.Fatalf("expected position bounds on accessor, got %+v", position)
+	}
+}
+
+func TestWriteOBJReferencesMaterials(t *testing.T) {
+	var obj, mtl bytes.Buffer
+	if err := WriteOBJ(&obj, &mtl, buildTestMesh(), "quad.mtl"); err != nil {
+		t.Fatalf("WriteOBJ failed: %v", err)
+	}
+	text := obj.String()
+	if !strings.Contains(text, "mtllib quad.mtl") || !strings.Contains(text, "o quad_test") {
+		t.Fatalf("expected material library and object name, got:\n%s", text)
+	}
+	if got := strings.Count(text, "\nv "); got != 7 {
+		t.Fatalf("expected 7 vertices, got %d", got)
+	}
+	if got := strings.Count(text, "\nf "); got != 3 {
+		t.Fatalf("expected 3 faces, got %d", got)
+	}
+	// The second primitive's indices are offset past the first's vertices.
+	if !strings.Contains(text, "f 5//5 6//6 7//7") {
+		t.Fatalf("expected second primitive faces to use global indices, got:\n%s", text)
+	}
+	if !strings.Contains(mtl.String(), "newmtl glass") || !strings.Contains(mtl.String(), "d 0.25") {
+		t.Fatalf("unexpected MTL output:\n%s", mtl.String())
+	}
+}
+
+func TestValidateRejectsOutOfRangeIndex(t *testing.T) {
+	m := buildTestMesh()
+	m.Primitives[1].Indices = []uint32{0, 1, 9}
+	if err := WriteGLB(&bytes.Buffer{}, m); err == nil {
+		t.Fatal("expected out-of-range index to fail")
+	}
+}
+
+func TestWriteGLBRejectsMeshWithoutPrimitives(t *testing.T) {
+	m := buildTestMesh()
+	m.Primitives = nil
+	if err := WriteGLB(&bytes.Buffer{}, m); err == nil {
+		t.Fatal("expected a mesh without primitives to fail")
+	}
+	if err := WriteOBJ(&bytes.Buffer{}, &bytes.Buffer{}, m, "empty.mtl"); err != nil {
+		t.Fatalf("expected OBJ to accept an empty mesh, got %v", err)
+	}
+}
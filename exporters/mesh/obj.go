@@ -0,0 +1,97 @@
+package mesh
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteOBJ writes m as Wavefront OBJ geometry to objW and its materials to
+// mtlW. mtlName is the material library file name referenced by the OBJ.
+func WriteOBJ(objW io.Writer, mtlW io.Writer, m *Mesh, mtlName string) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	obj := bufio.NewWriter(objW)
+	fmt.Fprintf(obj, "# gekko voxel mesh export\n")
+	if mtlName != "" {
+		fmt.Fprintf(obj, "mtllib %s\n", mtlName)
+	}
+	if m.Name != "" {
+		fmt.Fprintf(obj, "o %s\n", objName(m.Name))
+	}
+	base := 1
+	for _, primitive := range m.Primitives {
+		if len(primitive.Indices) == 0 {
+			continue
+		}
+		for _, p := range primitive.Positions {
+			fmt.Fprintf(obj, "v %g %g %g\n", p[0], p[1], p[2])
+		}
+		for _, n := range primitive.Normals {
+			fmt.Fprintf(obj, "vn %g %g %g\n", n[0], n[1], n[2])
+		}
+		fmt.Fprintf(obj, "usemtl %s\n", objName(m.Materials[primitive.Material].Name))
+		for i := 0; i+2 < len(primitive.Indices); i += 3 {
+			a := base + int(primitive.Indices[i])
+			b := base + int(primitive.Indices[i+1])
+			c := base + int(primitive.Indices[i+2])
+			fmt.Fprintf(obj, "f %d//%d %d//%d %d//%d\n", a, a, b, b, c, c)
+		}
+		base += len(primitive.Positions)
+	}
+	if err := obj.Flush(); err != nil {
+		return err
+	}
+	if mtlW == nil {
+		return nil
+	}
+	mtl := bufio.NewWriter(mtlW)
+	for _, material := range m.Materials {
+		fmt.Fprintf(mtl, "newmtl %s\n", objName(material.Name))
+		fmt.Fprintf(mtl, "Kd %g %g %g\n", material.BaseColor[0], material.BaseColor[1], material.BaseColor[2])
+		fmt.Fprintf(mtl, "d %g\n", material.BaseColor[3])
+		if material.Emissive != ([3]float32{}) {
+			fmt.Fprintf(mtl, "Ke %g %g %g\n", material.Emissive[0], material.Emissive[1], material.Emissive[2])
+		}
+		fmt.Fprintf(mtl, "Pr %g\n", material.Roughness)
+		fmt.Fprintf(mtl, "Pm %g\n\n", material.Metallic)
+	}
+	return mtl.Flush()
+}
+
+// SaveOBJ writes m to path and a sibling .mtl file with the same base name.
+func SaveOBJ(path string, m *Mesh) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	mtlPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".mtl"
+	objFile, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer objFile.Close()
+	mtlFile, err := os.Create(mtlPath)
+	if err != nil {
+		return err
+	}
+	defer mtlFile.Close()
+	if err := WriteOBJ(objFile, mtlFile, m, filepath.Base(mtlPath)); err != nil {
+		return err
+	}
+	if err := mtlFile.Close(); err != nil {
+		return err
+	}
+	return objFile.Close()
+}
+
+func objName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "default"
+	}
+	return strings.Join(strings.Fields(name), "_")
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	importcommon "github.com/gekko3d/gekko/importers/common"
+	"github.com/go-gl/mathgl/mgl32"
 )
 
 const (
@@ -55,6 +56,7 @@ type MDLGeometry struct {
 	Info      MDLInfo
 	Textures  []MDLTexturePixels
 	Triangles []MDLTriangle
+	Skeleton  MDLSkeleton
 }
 
 type MDLTriangle struct {
@@ -63,7 +65,10 @@ type MDLTriangle struct {
 }
 
 type MDLTriangleVertex struct {
+	// Position is in model space. When the model has bones it is placed by
+	// the bind pose of Bone; otherwise Bone is -1 and Position is raw.
 	Position    importcommon.Vec3
+	Bone        int
 	NormalIndex int
 	Texel       [2]int
 	UV          [2]float32
@@ -198,10 +203,12 @@ func ParseMDLGeometryWithExternalTextures(data []byte, externalTextures []MDLTex
 	geometry := MDLGeometry{
 		Info:     info,
 		Textures: textures,
+		Skeleton: parseMDLSkeleton(data, info),
 	}
+	bind := geometry.Skeleton.ModelSpace(geometry.Skeleton.BindPose())
 	for _, part := range decodeMDLBodyParts(data, int(readInt32(data, 208)), info.BodyPartCount) {
 		for _, model := range part.models {
-			geometry.Triangles = append(geometry.Triangles, decodeMDLModelTriangles(data, model, info, geometry.Textures)...)
+			geometry.Triangles = append(geometry.Triangles, decodeMDLModelTriangles(data, model, info, geometry.Textures, bind)...)
 		}
 	}
 	geometry.Info.DecodedTriangleCount = len(geometry.Triangles)
@@ -302,6 +309,7 @@ type decodedMDLModel struct {
 	info        MDLModelInfo
 	vertexIndex int
 	vertices    []importcommon.Vec3
+	vertexBones []int
 	meshes      []decodedMDLMesh
 }
 
@@ -357,6 +365,7 @@ func decodeMDLModels(data []byte, offset int, count int) []decodedMDLModel {
 			},
 			vertexIndex: vertexIndex,
 			vertices:    parseMDLVertices(data, vertexIndex, vertexCount),
+			vertexBones: parseMDLVertexBones(data, int(readInt32(data, base+84)), vertexCount),
 			meshes:      decodeMDLMeshes(data, meshIndex, meshCount),
 		}
 		out = append(out, model)
@@ -381,6 +390,18 @@ func parseMDLVertices(data []byte, offset int, count int) []importcommon.Vec3 {
 	return out
 }
 
+// parseMDLVertexBones reads the per-vertex bone index bytes.
+func parseMDLVertexBones(data []byte, offset int, count int) []int {
+	if count <= 0 || offset < mdlHeaderSize || offset > len(data) || count > len(data)-offset {
+		return nil
+	}
+	out := make([]int, count)
+	for i := range out {
+		out[i] = int(data[offset+i])
+	}
+	return out
+}
+
 func decodeMDLMeshes(data []byte, offset int, count int) []decodedMDLMesh {
 	const meshSize = 20
 	if count <= 0 || offset < 0 || offset > len(data) || count > (len(data)-offset)/meshSize {
@@ -397,16 +418,29 @@ func decodeMDLMeshes(data []byte, offset int, count int) []decodedMDLMesh {
 	return out
 }
 
-func decodeMDLModelTriangles(data []byte, model decodedMDLModel, info MDLInfo, textures []MDLTexturePixels) []MDLTriangle {
+func decodeMDLModelTriangles(data []byte, model decodedMDLModel, info MDLInfo, textures []MDLTexturePixels, bind []mgl32.Mat4) []MDLTriangle {
+	vertices := mdlModelVertices{positions: model.vertices}
+	if len(bind) > 0 && len(model.vertexBones) == len(model.vertices) {
+		vertices.bones = model.vertexBones
+		vertices.bind = bind
+	}
 	out := make([]MDLTriangle, 0)
 	for _, mesh := range model.meshes {
 		textureIndex := mdlTextureIndexForSkinRef(data, info, mesh.skinRef)
-		out = append(out, decodeMDLTriangleCommands(data, mesh.triangleCommandIndex, textureIndex, model.vertices, textures)...)
+		out = append(out, decodeMDLTriangleCommands(data, mesh.triangleCommandIndex, textureIndex, vertices, textures)...)
 	}
 	return out
 }
 
-func decodeMDLTriangleCommands(data []byte, offset int, textureIndex int, vertices []importcommon.Vec3, textures []MDLTexturePixels) []MDLTriangle {
+// mdlModelVertices holds bone-local vertex positions and, when the model is
+// skinned, the bone of each vertex and the bind pose that places it.
+type mdlModelVertices struct {
+	positions []importcommon.Vec3
+	bones     []int
+	bind      []mgl32.Mat4
+}
+
+func decodeMDLTriangleCommands(data []byte, offset int, textureIndex int, vertices mdlModelVertices, textures []MDLTexturePixels) []MDLTriangle {
 	if offset < 0 || offset+2 > len(data) {
 		return nil
 	}
@@ -451,13 +485,20 @@ func decodeMDLTriangleCommands(data []byte, offset int, textureIndex int, vertic
 	return out
 }
 
-func mdlTriangleVertex(vertexIndex int, normalIndex int, s int, t int, textureIndex int, vertices []importcommon.Vec3, textures []MDLTexturePixels) MDLTriangleVertex {
+func mdlTriangleVertex(vertexIndex int, normalIndex int, s int, t int, textureIndex int, vertices mdlModelVertices, textures []MDLTexturePixels) MDLTriangleVertex {
 	out := MDLTriangleVertex{
+		Bone:        -1,
 		NormalIndex: normalIndex,
 		Texel:       [2]int{s, t},
 	}
-	if vertexIndex >= 0 && vertexIndex < len(vertices) {
-		out.Position = vertices[vertexIndex]
+	if vertexIndex >= 0 && vertexIndex < len(vertices.positions) {
+		out.Position = vertices.positions[vertexIndex]
+		if vertexIndex < len(vertices.bones) {
+			if bone := vertices.bones[vertexIndex]; bone < len(vertices.bind) {
+				out.Position = transformMDLPoint(vertices.bind[bone], out.Position)
+				out.Bone = bone
+			}
+		}
 	}
 	if textureIndex >= 0 && textureIndex < len(textures) {
 		texture := textures[textureIndex].Info
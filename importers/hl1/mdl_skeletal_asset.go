@@ -0,0 +1,187 @@
+package hl1
+
+import (
+	"fmt"
+
+	"github.com/gekko3d/gekko/content"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// mdlHasAnimation reports whether the model has skinned vertices and at least
+// one decoded sequence, which is what a skeletal asset needs.
+func mdlHasAnimation(geometry MDLGeometry) bool {
+	if len(geometry.Skeleton.Bones) == 0 {
+		return false
+	}
+	skinned := false
+	for _, tri := range geometry.Triangles {
+		if tri.Vertices[0].Bone >= 0 {
+			skinned = true
+			break
+		}
+	}
+	if !skinned {
+		return false
+	}
+	for _, seq := range geometry.Skeleton.Sequences {
+		if len(seq.Frames) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// buildMDLSkeletalVoxelAsset skins the model as rigid per-bone voxel chunks.
+// Each bone becomes a group part posed at its bind transform relative to its
+// parent bone, holding a voxel part voxelized in the bone's own space. The
+// asset's skeleton drives the group parts from the decoded sequences.
+func buildMDLSkeletalVoxelAsset(geometry MDLGeometry, opts MDLVoxelAssetOptions, resolution float32) (*content.AssetDef, int, error) {
+	skeleton := geometry.Skeleton
+	bindPose := skeleton.BindPose()
+	bindModel := skeleton.ModelSpace(bindPose)
+	inverseBind := make([]mgl32.Mat4, len(bindModel))
+	for i, m := range bindModel {
+		inverseBind[i] = m.Inv()
+	}
+
+	boneTriangles := make([][]MDLTriangle, len(skeleton.Bones))
+	for _, tri := range geometry.Triangles {
+		bone := mdlTriangleBone(tri)
+		if bone < 0 || bone >= len(skeleton.Bones) {
+			bone = 0
+		}
+		local := tri
+		for i := range local.Vertices {
+			local.Vertices[i].Position = transformMDLPoint(inverseBind[bone], tri.Vertices[i].Position)
+		}
+		boneTriangles[bone] = append(boneTriangles[bone], local)
+	}
+
+	boneVoxels := make([]map[[3]int]mdlVoxelSample, len(skeleton.Bones))
+	counts := map[[4]uint8]int{}
+	for i, triangles := range boneTriangles {
+		if len(triangles) == 0 {
+			continue
+		}
+		boneVoxels[i] = voxelizeMDLGeometry(MDLGeometry{Textures: geometry.Textures, Triangles: triangles}, resolution)
+		for _, sample := range boneVoxels[i] {
+			counts[sample.Color]++
+		}
+	}
+	if len(counts) == 0 {
+		return nil, 0, fmt.Errorf("mdl voxelization produced no voxels")
+	}
+	palette := newMDLColorPaletteFromCounts(counts)
+	materials, shapePalette := mdlPaletteMaterials(palette)
+
+	tags := []string{"source:hl1", "source_asset:mdl", "generated:mdl_voxel_skeletal"}
+	asset := content.NewAssetDef(mdlAssetName(geometry, opts))
+	asset.Tags = append([]string(nil), tags...)
+	if opts.SourceRef != "" {
+		asset.Tags = append(asset.Tags, "source_ref:"+opts.SourceRef)
+	}
+	asset.Materials = materials
+	asset.Skeleton = &content.AssetSkeletonDef{}
+
+	voxelCount := 0
+	for i, bone := range skeleton.Bones {
+		boneID := mdlBonePartID(i)
+		parentID := ""
+		if bone.Parent >= 0 {
+			parentID = mdlBonePartID(bone.Parent)
+		}
+		asset.Parts = append(asset.Parts, content.AssetPartDef{
+			ID:        boneID,
+			Name:      mdlBoneName(bone, i),
+			ParentID:  parentID,
+			Transform: mdlBoneTransformDef(bindPose[i]),
+			Source:    content.AssetSourceDef{Kind: content.AssetSourceKindGroup},
+			Tags:      append([]string(nil), tags...),
+		})
+		asset.Skeleton.Bones = append(asset.Skeleton.Bones, content.AssetBoneDef{
+			Name:   mdlBoneName(bone, i),
+			PartID: boneID,
+			Parent: bone.Parent,
+		})
+		if len(boneVoxels[i]) == 0 {
+			continue
+		}
+		localVoxels, origin := localizeMDLVoxelsWithPalette(boneVoxels[i], resolution, palette)
+		voxelCount += len(localVoxels)
+		asset.Parts = append(asset.Parts, content.AssetPartDef{
+			ID:              boneID + "_voxels",
+			Name:            mdlBoneName(bone, i) + "_voxels",
+			ParentID:        boneID,
+			VoxelResolution: resolution,
+			Transform: content.AssetTransformDef{
+				Position: content.Vec3{origin.X, origin.Y, origin.Z},
+				Rotation: content.Quat{0, 0, 0, 1},
+				Scale:    content.Vec3{1, 1, 1},
+			},
+			Source: content.AssetSourceDef{
+				Kind: content.AssetSourceKindVoxelShape,
+				VoxelShape: &content.AssetVoxelShapeDef{
+					Palette: shapePalette,
+					Voxels:  localVoxels,
+				},
+			},
+			Tags: append([]string(nil), tags...),
+		})
+	}
+
+	for _, seq := range skeleton.Sequences {
+		if len(seq.Frames) == 0 {
+			continue
+		}
+		animation := content.AssetAnimationDef{
+			Name:   seq.Name,
+			FPS:    seq.FPS,
+			Loop:   seq.Looping(),
+			Frames: make([]content.AssetAnimationFrameDef, 0, len(seq.Frames)),
+		}
+		for _, frame := range seq.Frames {
+			poses := make([]content.AssetBonePoseDef, len(frame))
+			for b, pose := range frame {
+				transform := mdlBoneTransformDef(pose)
+				poses[b] = content.AssetBonePoseDef{Position: transform.Position, Rotation: transform.Rotation}
+			}
+			animation.Frames = append(animation.Frames, content.AssetAnimationFrameDef{Bones: poses})
+		}
+		for _, event := range seq.Events {
+			animation.Events = append(animation.Events, content.AssetAnimationEventDef{Frame: event.Frame, Event: event.Event, Options: event.Options})
+		}
+		asset.Skeleton.Animations = append(asset.Skeleton.Animations, animation)
+	}
+	return asset, voxelCount, nil
+}
+
+// mdlTriangleBone picks the bone most of the triangle's vertices follow,
+// preferring the first vertex on ties.
+func mdlTriangleBone(tri MDLTriangle) int {
+	a, b, c := tri.Vertices[0].Bone, tri.Vertices[1].Bone, tri.Vertices[2].Bone
+	if b == c && b != a {
+		return b
+	}
+	return a
+}
+
+func mdlBonePartID(index int) string {
+	return fmt.Sprintf("bone_%d", index)
+}
+
+func mdlBoneName(bone MDLBone, index int) string {
+	if bone.Name != "" {
+		return bone.Name
+	}
+	return mdlBonePartID(index)
+}
+
+func mdlBoneTransformDef(pose MDLBonePose) content.AssetTransformDef {
+	position := HammerToGekko(pose.Position)
+	rotation := HammerQuatToGekko(pose.Rotation).Normalize()
+	return content.AssetTransformDef{
+		Position: content.Vec3{position.X, position.Y, position.Z},
+		Rotation: content.Quat{rotation.V.X(), rotation.V.Y(), rotation.V.Z(), rotation.W},
+		Scale:    content.Vec3{1, 1, 1},
+	}
+}
@@ -0,0 +1,290 @@
+package hl1
+
+import (
+	"math"
+
+	importcommon "github.com/gekko3d/gekko/importers/common"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	mdlBoneSize          = 112
+	mdlSequenceSize      = 176
+	mdlSequenceGroupSize = 104
+	mdlEventSize         = 76
+	mdlMaxBones          = 128
+	mdlMaxSequenceFrames = 4096
+	mdlSequenceLooping   = 0x0001
+)
+
+// MDLBone is a studio bone in Hammer units. Position and Rotation (Euler
+// radians) are the reference pose; animation values are added to them after
+// scaling by PositionScale and RotationScale.
+type MDLBone struct {
+	Name          string            `json:"name"`
+	Parent        int               `json:"parent"`
+	Position      importcommon.Vec3 `json:"position"`
+	Rotation      importcommon.Vec3 `json:"rotation"`
+	PositionScale importcommon.Vec3 `json:"position_scale"`
+	RotationScale importcommon.Vec3 `json:"rotation_scale"`
+}
+
+// MDLBonePose is a bone transform relative to its parent bone, in Hammer
+// space.
+type MDLBonePose struct {
+	Position importcommon.Vec3
+	Rotation mgl32.Quat
+}
+
+type MDLAnimationEvent struct {
+	Frame   int    `json:"frame"`
+	Event   int    `json:"event"`
+	Options string `json:"options,omitempty"`
+}
+
+type MDLSequence struct {
+	Name       string              `json:"name"`
+	FPS        float32             `json:"fps"`
+	Flags      int                 `json:"flags,omitempty"`
+	Activity   int                 `json:"activity,omitempty"`
+	FrameCount int                 `json:"frame_count"`
+	BlendCount int                 `json:"blend_count,omitempty"`
+	Events     []MDLAnimationEvent `json:"events,omitempty"`
+	// External sequences live in a separate sequence-group file and are not
+	// decoded.
+	External bool `json:"external,omitempty"`
+	// Frames hold the first blend's poses, Frames[frame][bone].
+	Frames [][]MDLBonePose `json:"-"`
+}
+
+func (s MDLSequence) Looping() bool {
+	return s.Flags&mdlSequenceLooping != 0
+}
+
+type MDLSkeleton struct {
+	Bones     []MDLBone     `json:"bones,omitempty"`
+	Sequences []MDLSequence `json:"sequences,omitempty"`
+}
+
+func ParseMDLSkeleton(data []byte) (MDLSkeleton, error) {
+	info, err := ParseMDLInfo(data)
+	if err != nil {
+		return MDLSkeleton{}, err
+	}
+	return parseMDLSkeleton(data, info), nil
+}
+
+func parseMDLSkeleton(data []byte, info MDLInfo) MDLSkeleton {
+	bones := parseMDLBones(data, int(readInt32(data, 144)), info.BoneCount)
+	if len(bones) == 0 {
+		return MDLSkeleton{}
+	}
+	return MDLSkeleton{
+		Bones:     bones,
+		Sequences: parseMDLSequences(data, info, bones),
+	}
+}
+
+// BindPose returns the reference pose every vertex is stored against.
+func (s MDLSkeleton) BindPose() []MDLBonePose {
+	out := make([]MDLBonePose, len(s.Bones))
+	for i, bone := range s.Bones {
+		out[i] = MDLBonePose{Position: bone.Position, Rotation: mdlAngleQuaternion(bone.Rotation)}
+	}
+	return out
+}
+
+// ModelSpace chains parent-relative poses into model-space matrices.
+func (s MDLSkeleton) ModelSpace(poses []MDLBonePose) []mgl32.Mat4 {
+	out := make([]mgl32.Mat4, len(s.Bones))
+	for i, bone := range s.Bones {
+		local := mgl32.Ident4()
+		if i < len(poses) {
+			p := poses[i].Position
+			local = mgl32.Translate3D(p.X, p.Y, p.Z).Mul4(poses[i].Rotation.Mat4())
+		}
+		if bone.Parent >= 0 {
+			out[i] = out[bone.Parent].Mul4(local)
+		} else {
+			out[i] = local
+		}
+	}
+	return out
+}
+
+func parseMDLBones(data []byte, offset int, count int) []MDLBone {
+	if count <= 0 || count > mdlMaxBones || offset < mdlHeaderSize || offset > len(data) || count > (len(data)-offset)/mdlBoneSize {
+		return nil
+	}
+	out := make([]MDLBone, 0, count)
+	for i := 0; i < count; i++ {
+		base := offset + i*mdlBoneSize
+		bone := MDLBone{
+			Name:          cString(data[base : base+32]),
+			Parent:        int(readInt32(data, base+32)),
+			Position:      readMDLVec3(data, base+64),
+			Rotation:      readMDLVec3(data, base+76),
+			PositionScale: readMDLVec3(data, base+88),
+			RotationScale: readMDLVec3(data, base+100),
+		}
+		// studiomdl always writes parents before children.
+		if bone.Parent < -1 || bone.Parent >= i {
+			return nil
+		}
+		out = append(out, bone)
+	}
+	return out
+}
+
+func parseMDLSequences(data []byte, info MDLInfo, bones []MDLBone) []MDLSequence {
+	offset := int(readInt32(data, 168))
+	count := info.SequenceCount
+	if count <= 0 || offset < mdlHeaderSize || offset > len(data) || count > (len(data)-offset)/mdlSequenceSize {
+		return nil
+	}
+	// Animation offsets are relative to the sequence group's data, which is
+	// zero for the group stored in this file.
+	groupData := 0
+	groupOffset := int(readInt32(data, 176))
+	if int(readInt32(data, 172)) > 0 && groupOffset >= mdlHeaderSize && groupOffset+mdlSequenceGroupSize <= len(data) {
+		groupData = int(readInt32(data, groupOffset+100))
+	}
+	out := make([]MDLSequence, 0, count)
+	for i := 0; i < count; i++ {
+		base := offset + i*mdlSequenceSize
+		seq := MDLSequence{
+			Name:       cString(data[base : base+32]),
+			FPS:        readFloat32(data, base+32),
+			Flags:      int(readInt32(data, base+36)),
+			Activity:   int(readInt32(data, base+40)),
+			FrameCount: int(readInt32(data, base+56)),
+			BlendCount: int(readInt32(data, base+120)),
+			Events:     parseMDLEvents(data, int(readInt32(data, base+52)), int(readInt32(data, base+48))),
+			External:   readInt32(data, base+156) != 0,
+		}
+		if !seq.External {
+			seq.Frames = decodeMDLSequenceFrames(data, groupData+int(readInt32(data, base+124)), seq.FrameCount, bones)
+		}
+		out = append(out, seq)
+	}
+	return out
+}
+
+func parseMDLEvents(data []byte, offset int, count int) []MDLAnimationEvent {
+	if count <= 0 || offset < mdlHeaderSize || offset > len(data) || count > (len(data)-offset)/mdlEventSize {
+		return nil
+	}
+	out := make([]MDLAnimationEvent, 0, count)
+	for i := 0; i < count; i++ {
+		base := offset + i*mdlEventSize
+		out = append(out, MDLAnimationEvent{
+			Frame:   int(readInt32(data, base)),
+			Event:   int(readInt32(data, base+4)),
+			Options: cString(data[base+12 : base+76]),
+		})
+	}
+	return out
+}
+
+// decodeMDLSequenceFrames decodes the RLE animation channels of one blend.
+// Each bone has six uint16 offsets (position xyz, rotation xyz) relative to
+// its mstudioanim_t; zero means the channel holds the reference value.
+func decodeMDLSequenceFrames(data []byte, offset int, frameCount int, bones []MDLBone) [][]MDLBonePose {
+	const animSize = 12
+	if frameCount <= 0 || frameCount > mdlMaxSequenceFrames || offset < mdlHeaderSize || offset > len(data) || len(bones) > (len(data)-offset)/animSize {
+		return nil
+	}
+	frames := make([][]MDLBonePose, frameCount)
+	for frame := range frames {
+		poses := make([]MDLBonePose, len(bones))
+		for b, bone := range bones {
+			anim := offset + b*animSize
+			var channels [6]float32
+			for k := 0; k < 6; k++ {
+				channelOffset := int(readUint16(data, anim+k*2))
+				if channelOffset == 0 {
+					continue
+				}
+				if value, ok := mdlAnimValue(data, anim+channelOffset, frame); ok {
+					channels[k] = value
+				}
+			}
+			poses[b] = MDLBonePose{
+				Position: importcommon.Vec3{
+					X: bone.Position.X + channels[0]*bone.PositionScale.X,
+					Y: bone.Position.Y + channels[1]*bone.PositionScale.Y,
+					Z: bone.Position.Z + channels[2]*bone.PositionScale.Z,
+				},
+				Rotation: mdlAngleQuaternion(importcommon.Vec3{
+					X: bone.Rotation.X + channels[3]*bone.RotationScale.X,
+					Y: bone.Rotation.Y + channels[4]*bone.RotationScale.Y,
+					Z: bone.Rotation.Z + channels[5]*bone.RotationScale.Z,
+				}),
+			}
+		}
+		frames[frame] = poses
+	}
+	return frames
+}
+
+// mdlAnimValue reads frame from an mstudioanimvalue_t run list. Each run
+// header holds valid and total counts followed by valid int16 values; frames
+// past valid repeat the last value.
+func mdlAnimValue(data []byte, offset int, frame int) (float32, bool) {
+	k := frame
+	for {
+		if offset < 0 || offset+2 > len(data) {
+			return 0, false
+		}
+		valid := int(data[offset])
+		total := int(data[offset+1])
+		if total == 0 {
+			return 0, false
+		}
+		if total > k {
+			index := valid
+			if valid > k {
+				index = k + 1
+			}
+			at := offset + index*2
+			if at+2 > len(data) {
+				return 0, false
+			}
+			return float32(readInt16(data, at)), true
+		}
+		k -= total
+		offset += (valid + 1) * 2
+	}
+}
+
+// mdlAngleQuaternion matches the SDK's AngleQuaternion: roll about X, then
+// pitch about Y, then yaw about Z.
+func mdlAngleQuaternion(angles importcommon.Vec3) mgl32.Quat {
+	sy, cy := math.Sincos(float64(angles.Z) * 0.5)
+	sp, cp := math.Sincos(float64(angles.Y) * 0.5)
+	sr, cr := math.Sincos(float64(angles.X) * 0.5)
+	return mgl32.Quat{
+		W: float32(cr*cp*cy + sr*sp*sy),
+		V: mgl32.Vec3{
+			float32(sr*cp*cy - cr*sp*sy),
+			float32(cr*sp*cy + sr*cp*sy),
+			float32(cr*cp*sy - sr*sp*cy),
+		},
+	}
+}
+
+// HammerQuatToGekko converts a rotation with the same basis change as
+// HammerToGekko. The basis change is a proper rotation, so only the vector
+// part is remapped.
+func HammerQuatToGekko(q mgl32.Quat) mgl32.Quat {
+	return mgl32.Quat{W: q.W, V: mgl32.Vec3{q.V.X(), q.V.Z(), -q.V.Y()}}
+}
+
+func readMDLVec3(data []byte, offset int) importcommon.Vec3 {
+	return importcommon.Vec3{X: readFloat32(data, offset), Y: readFloat32(data, offset+4), Z: readFloat32(data, offset+8)}
+}
+
+func transformMDLPoint(m mgl32.Mat4, v importcommon.Vec3) importcommon.Vec3 {
+	p := m.Mul4x1(mgl32.Vec4{v.X, v.Y, v.Z, 1})
+	return importcommon.Vec3{X: p.X(), Y: p.Y(), Z: p.Z()}
+}
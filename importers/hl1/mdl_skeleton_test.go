@@ -0,0 +1,169 @@
+package hl1
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gekko3d/gekko/content"
+	importcommon "github.com/gekko3d/gekko/importers/common"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// syntheticSkinnedMDL extends syntheticMDL with a two-bone skeleton, vertex
+// bone assignments, and one looping sequence whose second bone yaws 1 radian
+// from frame 1 on.
+func syntheticSkinnedMDL() []byte {
+	data := syntheticMDL()
+	const modelOffset = mdlHeaderSize + 80 + 76
+	boneOffset := len(data)
+	vertInfoOffset := boneOffset + 2*mdlBoneSize
+	seqOffset := vertInfoOffset + 8
+	eventOffset := seqOffset + mdlSequenceSize
+	animOffset := eventOffset + mdlEventSize
+	data = append(data, make([]byte, animOffset+2*12+6-len(data))...)
+
+	writeTestInt32(data, 140, 2)
+	writeTestInt32(data, 144, boneOffset)
+	writeTestInt32(data, 164, 1)
+	writeTestInt32(data, 168, seqOffset)
+	writeTestInt32(data, modelOffset+84, vertInfoOffset)
+
+	writeTestCString(data[boneOffset:boneOffset+32], "root")
+	writeTestInt32(data, boneOffset+32, -1)
+	writeTestVec3(data, boneOffset+64, 0, 0, 10)
+	writeTestVec3(data, boneOffset+88, 1, 1, 1)
+	arm := boneOffset + mdlBoneSize
+	writeTestCString(data[arm:arm+32], "arm")
+	writeTestInt32(data, arm+32, 0)
+	writeTestVec3(data, arm+64, 5, 0, 0)
+	writeTestVec3(data, arm+88, 1, 1, 1)
+	writeTestVec3(data, arm+100, 0, 0, 0.01)
+
+	copy(data[vertInfoOffset:], []byte{0, 0, 1})
+
+	writeTestCString(data[seqOffset:seqOffset+32], "idle")
+	writeTestFloat32(data, seqOffset+32, 10)
+	writeTestInt32(data, seqOffset+36, mdlSequenceLooping)
+	writeTestInt32(data, seqOffset+48, 1)
+	writeTestInt32(data, seqOffset+52, eventOffset)
+	writeTestInt32(data, seqOffset+56, 3)
+	writeTestInt32(data, seqOffset+120, 1)
+	writeTestInt32(data, seqOffset+124, animOffset)
+
+	writeTestInt32(data, eventOffset, 2)
+	writeTestInt32(data, eventOffset+4, 5004)
+	writeTestCString(data[eventOffset+12:eventOffset+76], "fire")
+
+	// Bone 1's yaw channel points 12 bytes past its anim struct at one run:
+	// two values over three frames.
+	armAnim := animOffset + 12
+	writeTestInt16(data, armAnim+5*2, 12)
+	data[armAnim+12] = 2
+	data[armAnim+13] = 3
+	writeTestInt16(data, armAnim+14, 0)
+	writeTestInt16(data, armAnim+16, 100)
+	return data
+}
+
+func TestParseMDLSkeletonDecodesBonesSequencesAndEvents(t *testing.T) {
+	skeleton, err := ParseMDLSkeleton(syntheticSkinnedMDL())
+	if err != nil {
+		t.Fatalf("ParseMDLSkeleton failed: %v", err)
+	}
+	if len(skeleton.Bones) != 2 || skeleton.Bones[1].Name != "arm" || skeleton.Bones[1].Parent != 0 {
+		t.Fatalf("unexpected bones: %+v", skeleton.Bones)
+	}
+	if len(skeleton.Sequences) != 1 {
+		t.Fatalf("expected one sequence, got %d", len(skeleton.Sequences))
+	}
+	seq := skeleton.Sequences[0]
+	if seq.Name != "idle" || seq.FPS != 10 || !seq.Looping() || len(seq.Frames) != 3 {
+		t.Fatalf("unexpected sequence: %+v", seq)
+	}
+	if len(seq.Events) != 1 || seq.Events[0] != (MDLAnimationEvent{Frame: 2, Event: 5004, Options: "fire"}) {
+		t.Fatalf("unexpected events: %+v", seq.Events)
+	}
+	want := mgl32.QuatRotate(1, mgl32.Vec3{0, 0, 1})
+	for frame, wantRot := range []mgl32.Quat{mgl32.QuatIdent(), want, want} {
+		got := seq.Frames[frame][1]
+		if !got.Rotation.ApproxEqualThreshold(wantRot, 1e-5) || got.Position != (importcommon.Vec3{X: 5}) {
+			t.Fatalf("frame %d arm pose = %+v, want rotation %v", frame, got, wantRot)
+		}
+	}
+}
+
+func TestMDLAnimValueWalksRuns(t *testing.T) {
+	data := make([]byte, mdlHeaderSize+12)
+	base := mdlHeaderSize
+	data[base], data[base+1] = 1, 2
+	writeTestInt16(data, base+2, 7)
+	data[base+4], data[base+5] = 1, 1
+	writeTestInt16(data, base+6, 9)
+	for frame, want := range []float32{7, 7, 9} {
+		if got, ok := mdlAnimValue(data, base, frame); !ok || got != want {
+			t.Fatalf("frame %d = %v ok=%v, want %v", frame, got, ok, want)
+		}
+	}
+	if _, ok := mdlAnimValue(data, base, 5); ok {
+		t.Fatal("expected reading past the runs to fail")
+	}
+}
+
+func TestParseMDLGeometryPlacesSkinnedVerticesByBindPose(t *testing.T) {
+	geometry, err := ParseMDLGeometry(syntheticSkinnedMDL())
+	if err != nil {
+		t.Fatalf("ParseMDLGeometry failed: %v", err)
+	}
+	verts := geometry.Triangles[0].Vertices
+	if verts[0].Bone != 0 || verts[2].Bone != 1 {
+		t.Fatalf("unexpected vertex bones: %+v", verts)
+	}
+	if verts[1].Position != (importcommon.Vec3{X: 1, Z: 10}) || verts[2].Position != (importcommon.Vec3{X: 5, Z: 11}) {
+		t.Fatalf("expected bind-pose model positions, got %+v", verts)
+	}
+}
+
+func TestBuildMDLVoxelAssetBuildsRigidBoneChunksAndAnimations(t *testing.T) {
+	geometry, err := ParseMDLGeometry(syntheticSkinnedMDL())
+	if err != nil {
+		t.Fatalf("ParseMDLGeometry failed: %v", err)
+	}
+	asset, voxelCount, err := BuildMDLVoxelAsset(geometry, MDLVoxelAssetOptions{Name: "npc", VoxelResolution: 0.005})
+	if err != nil {
+		t.Fatalf("BuildMDLVoxelAsset failed: %v", err)
+	}
+	content.NormalizeAssetDef(asset)
+	if validation := content.ValidateAsset(asset, content.AssetValidationOptions{}); validation.HasErrors() {
+		t.Fatalf("skeletal asset failed validation: %s", validation.Error())
+	}
+	if asset.Runtime != nil && asset.Runtime.CollapseVoxelParts {
+		t.Fatal("skeletal assets must not collapse their bone parts")
+	}
+	if asset.Skeleton == nil || len(asset.Skeleton.Bones) != 2 || asset.Skeleton.Bones[1].Parent != 0 {
+		t.Fatalf("unexpected skeleton: %+v", asset.Skeleton)
+	}
+	if len(asset.Parts) != 3 || asset.Parts[1].ID != "bone_0_voxels" || asset.Parts[2].ParentID != "bone_0" {
+		t.Fatalf("expected two bone groups and one voxel chunk, got %+v", asset.Parts)
+	}
+	if voxelCount == 0 || len(asset.Parts[1].Source.VoxelShape.Voxels) != voxelCount {
+		t.Fatalf("expected the chunk to hold every voxel, got %d", voxelCount)
+	}
+	// The arm sits 5 Hammer units along X from the root, which sits 10 up.
+	arm := asset.Parts[2].Transform.Position
+	if math.Abs(float64(arm[0]-5*HammerUnitMeters)) > 1e-6 || asset.Parts[0].Transform.Position[1] != 10*HammerUnitMeters {
+		t.Fatalf("unexpected bone bind transforms: root=%v arm=%v", asset.Parts[0].Transform.Position, arm)
+	}
+	if len(asset.Skeleton.Animations) != 1 || len(asset.Skeleton.Animations[0].Frames) != 3 || !asset.Skeleton.Animations[0].Loop {
+		t.Fatalf("unexpected animations: %+v", asset.Skeleton.Animations)
+	}
+	// A yaw about Hammer +Z is a rotation about Gekko +Y.
+	rot := asset.Skeleton.Animations[0].Frames[1].Bones[1].Rotation
+	if math.Abs(float64(rot[1]-float32(math.Sin(0.5)))) > 1e-5 || math.Abs(float64(rot[2])) > 1e-6 {
+		t.Fatalf("unexpected converted rotation %v", rot)
+	}
+
+	static, _, err := BuildMDLVoxelAsset(geometry, MDLVoxelAssetOptions{Name: "npc", VoxelResolution: 0.005, Static: true})
+	if err != nil || static.Skeleton != nil || len(static.Parts) != 1 {
+		t.Fatalf("expected Static to bake a single part, got %+v err=%v", static, err)
+	}
+}
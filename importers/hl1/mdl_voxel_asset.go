@@ -13,6 +13,9 @@ type MDLVoxelAssetOptions struct {
 	Name            string
 	SourceRef       string
 	VoxelResolution float32
+	// Static bakes one collapsed bind-pose part even when the model has
+	// animation sequences.
+	Static bool
 }
 
 func BuildMDLVoxelAsset(geometry MDLGeometry, opts MDLVoxelAssetOptions) (*content.AssetDef, int, error) {
@@ -23,20 +26,16 @@ func BuildMDLVoxelAsset(geometry MDLGeometry, opts MDLVoxelAssetOptions) (*conte
 	if len(geometry.Triangles) == 0 {
 		return nil, 0, fmt.Errorf("mdl contains no decoded triangles")
 	}
+	if !opts.Static && mdlHasAnimation(geometry) {
+		return buildMDLSkeletalVoxelAsset(geometry, opts, resolution)
+	}
 	voxels := voxelizeMDLGeometry(geometry, resolution)
 	if len(voxels) == 0 {
 		return nil, 0, fmt.Errorf("mdl voxelization produced no voxels")
 	}
 	localVoxels, origin := localizeMDLVoxels(voxels, resolution)
 	materials, palette := mdlAssetMaterialsAndPalette(voxels)
-	name := opts.Name
-	if name == "" {
-		name = geometry.Info.Name
-	}
-	if name == "" {
-		name = "hl1_model"
-	}
-	asset := content.NewAssetDef(name)
+	asset := content.NewAssetDef(mdlAssetName(geometry, opts))
 	asset.Tags = []string{"source:hl1", "source_asset:mdl", "generated:mdl_voxel_surface"}
 	if opts.SourceRef != "" {
 		asset.Tags = append(asset.Tags, "source_ref:"+opts.SourceRef)
@@ -64,6 +63,16 @@ func BuildMDLVoxelAsset(geometry MDLGeometry, opts MDLVoxelAssetOptions) (*conte
 	return asset, len(localVoxels), nil
 }
 
+func mdlAssetName(geometry MDLGeometry, opts MDLVoxelAssetOptions) string {
+	if opts.Name != "" {
+		return opts.Name
+	}
+	if geometry.Info.Name != "" {
+		return geometry.Info.Name
+	}
+	return "hl1_model"
+}
+
 func voxelizeMDLGeometry(geometry MDLGeometry, resolution float32) map[[3]int]mdlVoxelSample {
 	out := map[[3]int]mdlVoxelSample{}
 	half := importcommon.Vec3{X: resolution * 0.5, Y: resolution * 0.5, Z: resolution * 0.5}
@@ -158,6 +167,10 @@ func sampleMDLTexture(texture MDLTexturePixels, u float32, v float32) ([4]uint8,
 }
 
 func localizeMDLVoxels(voxels map[[3]int]mdlVoxelSample, resolution float32) ([]content.VoxelObjectVoxelDef, importcommon.Vec3) {
+	return localizeMDLVoxelsWithPalette(voxels, resolution, newMDLColorPalette(voxels))
+}
+
+func localizeMDLVoxelsWithPalette(voxels map[[3]int]mdlVoxelSample, resolution float32, palette mdlColorPalette) ([]content.VoxelObjectVoxelDef, importcommon.Vec3) {
 	keys := make([][3]int, 0, len(voxels))
 	first := true
 	var minK [3]int
@@ -181,7 +194,6 @@ func localizeMDLVoxels(voxels map[[3]int]mdlVoxelSample, resolution float32) ([]
 		}
 		return keys[i][2] < keys[j][2]
 	})
-	palette := newMDLColorPalette(voxels)
 	out := make([]content.VoxelObjectVoxelDef, 0, len(keys))
 	for _, key := range keys {
 		out = append(out, content.VoxelObjectVoxelDef{
@@ -195,7 +207,10 @@ func localizeMDLVoxels(voxels map[[3]int]mdlVoxelSample, resolution float32) ([]
 }
 
 func mdlAssetMaterialsAndPalette(voxels map[[3]int]mdlVoxelSample) ([]content.AssetMaterialDef, []content.AssetVoxelPaletteEntryDef) {
-	pal := newMDLColorPalette(voxels)
+	return mdlPaletteMaterials(newMDLColorPalette(voxels))
+}
+
+func mdlPaletteMaterials(pal mdlColorPalette) ([]content.AssetMaterialDef, []content.AssetVoxelPaletteEntryDef) {
 	materials := make([]content.AssetMaterialDef, 0, len(pal.colors))
 	shapePalette := make([]content.AssetVoxelPaletteEntryDef, 0, len(pal.colors))
 	for _, entry := range pal.colors {
@@ -227,6 +242,10 @@ func newMDLColorPalette(voxels map[[3]int]mdlVoxelSample) mdlColorPalette {
 	for _, sample := range voxels {
 		counts[sample.Color]++
 	}
+	return newMDLColorPaletteFromCounts(counts)
+}
+
+func newMDLColorPaletteFromCounts(counts map[[4]uint8]int) mdlColorPalette {
 	type counted struct {
 		color [4]uint8
 		count int
@@ -99,7 +99,16 @@ func BuildImportSummary(opts ImportOptions) (ImportSummary, error) {
 	}
 	wads, wadDiagnostics := LoadResolvedWADs(source.WADPaths)
 	mapImport.Diagnostics = append(mapImport.Diagnostics, wadDiagnostics...)
-	mapImport.Materials = materialsFromBSPTextures(bsp.Textures, wads)
+	textureTypes, err := LoadTextureTypes(opts.GameDir)
+	if err != nil {
+		mapImport.Diagnostics = append(mapImport.Diagnostics, importcommon.Diagnostic{
+			Severity: importcommon.SeverityWarning,
+			Code:     "hl1.texture_types_unreadable",
+			Subject:  "sound/materials.txt",
+			Message:  err.Error(),
+		})
+	}
+	mapImport.Materials = materialsFromBSPTextures(bsp.Textures, wads, textureTypes)
 	mapImport.Diagnostics = append(mapImport.Diagnostics, missingTextureDiagnostics(bsp.Textures, wads)...)
 	mapImport.Entities, mapImport.Lights, mapImport.Triggers = extractEntities(bsp)
 	worldFaces, faceErr := bsp.WorldFaces()
@@ -307,7 +316,10 @@ func lightFromEntity(raw RawEntity, position importcommon.Vec3) importcommon.Lig
 	return light
 }
 
-func materialsFromBSPTextures(textures []Texture, wads []*WAD) []importcommon.Material {
+// materialsFromBSPTextures builds one material per BSP texture. Textures
+// listed in materials.txt are tagged "surface:<name>" for footstep and impact
+// sounds.
+func materialsFromBSPTextures(textures []Texture, wads []*WAD, textureTypes TextureTypes) []importcommon.Material {
 	out := make([]importcommon.Material, 0, len(textures))
 	for i, texture := range textures {
 		baseColor := texture.BaseColor
@@ -322,6 +334,11 @@ func materialsFromBSPTextures(textures []Texture, wads []*WAD) []importcommon.Ma
 			}
 		}
 		semantics := materialSemantics(texture.Name)
+		if textureType, ok := textureTypes.Lookup(texture.Name); ok {
+			if surface := TextureTypeSurface(textureType); surface != "" {
+				semantics.Tags = append(semantics.Tags, "surface:"+surface)
+			}
+		}
 		out = append(out, importcommon.Material{
 			ID:                i + 1,
 			PaletteIndex:      uint8(min(i+1, 255)),
@@ -0,0 +1,114 @@
+package hl1
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hl1TextureNameMax matches CBTEXTURENAMEMAX; materials.txt names and the
+// lookup compare at most this many characters minus the terminator.
+const hl1TextureNameMax = 13
+
+// TextureTypes is the texture-type table from sound/materials.txt, keyed by
+// upper-cased texture name prefix.
+type TextureTypes map[string]byte
+
+// hl1TextureTypeSurfaces names the materials.txt type characters.
+var hl1TextureTypeSurfaces = map[byte]string{
+	'C': "concrete",
+	'M': "metal",
+	'D': "dirt",
+	'V': "vent",
+	'G': "grate",
+	'T': "tile",
+	'S': "water",
+	'W': "wood",
+	'P': "computer",
+	'Y': "glass",
+	'F': "flesh",
+	'N': "snow",
+}
+
+// TextureTypeSurface returns the surface name for a materials.txt type
+// character, or "" when it is unknown.
+func TextureTypeSurface(textureType byte) string {
+	return hl1TextureTypeSurfaces[textureType]
+}
+
+// ParseTextureTypes reads materials.txt lines of the form "M METALFLOOR".
+// Comments and malformed lines are skipped, and the first entry for a name
+// wins, as in the game.
+func ParseTextureTypes(data []byte) TextureTypes {
+	types := TextureTypes{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || len(fields[0]) != 1 {
+			continue
+		}
+		name := textureTypeKey(fields[1])
+		if name == "" {
+			continue
+		}
+		if _, exists := types[name]; !exists {
+			types[name] = strings.ToUpper(fields[0])[0]
+		}
+	}
+	return types
+}
+
+// LoadTextureTypes reads sound/materials.txt from the game directory, falling
+// back to the valve directory for mods. A missing file is not an error.
+func LoadTextureTypes(gameDir string) (TextureTypes, error) {
+	if strings.TrimSpace(gameDir) == "" {
+		return nil, nil
+	}
+	candidates := uniqueCleanPaths([]string{
+		filepath.Join(gameDir, "sound", "materials.txt"),
+		filepath.Join(gameDir, "valve", "sound", "materials.txt"),
+		filepath.Join(filepath.Dir(gameDir), "valve", "sound", "materials.txt"),
+	})
+	for _, path := range candidates {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return ParseTextureTypes(data), nil
+	}
+	return nil, nil
+}
+
+// Lookup returns the type character for a BSP texture name, skipping the
+// random-tiling and animation prefixes the game skips.
+func (t TextureTypes) Lookup(textureName string) (byte, bool) {
+	if len(t) == 0 {
+		return 0, false
+	}
+	name := textureName
+	if len(name) >= 2 && (name[0] == '-' || name[0] == '+') {
+		name = name[2:]
+	}
+	if len(name) > 0 && (name[0] == '{' || name[0] == '!' || name[0] == '~' || name[0] == ' ') {
+		name = name[1:]
+	}
+	textureType, ok := t[textureTypeKey(name)]
+	return textureType, ok
+}
+
+func textureTypeKey(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if len(name) > hl1TextureNameMax-1 {
+		name = name[:hl1TextureNameMax-1]
+	}
+	return name
+}
@@ -0,0 +1,65 @@
+package hl1
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTextureTypesMatchesGamePrefixRules(t *testing.T) {
+	types := ParseTextureTypes([]byte(`// materials.txt
+M METALFLOOR1
+G grate1a
+C CRETESTEP01  // trailing comment
+M metalfloor1
+X
+Y GLASS_BRIGHT_LONGNAME
+`))
+	if len(types) != 4 {
+		t.Fatalf("expected 4 entries, got %+v", types)
+	}
+	cases := []struct {
+		name string
+		want byte
+		ok   bool
+	}{
+		{"metalfloor1", 'M', true},
+		{"-0GRATE1A", 'G', true},
+		{"{grate1a", 'G', true},
+		{"+0~CRETESTEP01", 'C', true},
+		{"GLASS_BRIGHT_LONG", 'Y', true},
+		{"metalfloor", 0, false},
+	}
+	for _, tc := range cases {
+		got, ok := types.Lookup(tc.name)
+		if got != tc.want || ok != tc.ok {
+			t.Fatalf("Lookup(%q) = %q,%v, want %q,%v", tc.name, got, ok, tc.want, tc.ok)
+		}
+	}
+	if TextureTypeSurface('S') != "water" || TextureTypeSurface('?') != "" {
+		t.Fatal("unexpected texture type surface names")
+	}
+}
+
+func TestMaterialsFromBSPTexturesTagsTextureTypeSurfaces(t *testing.T) {
+	dir := t.TempDir()
+	if err := mkdirAll(filepath.Join(dir, "valve", "sound")); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFile(filepath.Join(dir, "valve", "sound", "materials.txt"), []byte("G GRATE1A\n")); err != nil {
+		t.Fatal(err)
+	}
+	// A mod directory without its own materials.txt uses valve's.
+	types, err := LoadTextureTypes(filepath.Join(dir, "mymod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	materials := materialsFromBSPTextures([]Texture{{Name: "grate1a"}, {Name: "crete1"}}, nil, types)
+	if !hasTag(materials[0].Tags, "surface:grate") {
+		t.Fatalf("expected the grate texture tagged from materials.txt, got %+v", materials[0].Tags)
+	}
+	for _, tag := range materials[1].Tags {
+		if len(tag) > 8 && tag[:8] == "surface:" {
+			t.Fatalf("expected no surface tag for an unlisted texture, got %+v", materials[1].Tags)
+		}
+	}
+}
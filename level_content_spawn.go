@@ -1280,6 +1280,10 @@ func levelBrushModelAsset(assets *AssetServer, brush content.LevelBrushDef) (Ass
 		return assets.CreateCapsuleModel(params["radius"], params["height"], 1), nil
 	case "ramp":
 		return assets.CreateRampModel(params["sx"], params["sy"], params["sz"], 1), nil
+	case "torus":
+		return assets.CreateTorusModel(params["major_radius"], params["minor_radius"], 1), nil
+	case "rounded_box":
+		return assets.CreateRoundedBoxModel(params["sx"], params["sy"], params["sz"], params["radius"], 1), nil
 	default:
 		return AssetId{}, fmt.Errorf("unsupported level brush primitive %q", brush.Primitive)
 	}
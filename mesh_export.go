@@ -0,0 +1,161 @@
+package gekko
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/gekko3d/gekko/content"
+	"github.com/gekko3d/gekko/exporters/mesh"
+	"github.com/gekko3d/gekko/voxelrt/rt/core"
+	"github.com/gekko3d/gekko/voxelrt/rt/volume"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// VoxelMeshFromXBrickMap greedy-meshes xbm into a triangle mesh with one
+// primitive per palette index. Vertices are scaled by voxelSize and shifted
+// by offset; palette supplies colors and PBR parameters.
+func VoxelMeshFromXBrickMap(name string, xbm *volume.XBrickMap, palette *VoxelPaletteAsset, voxelSize float32, offset mgl32.Vec3) *mesh.Mesh {
+	out := &mesh.Mesh{Name: name}
+	if voxelSize <= 0 {
+		voxelSize = 1
+	}
+	if palette == nil {
+		palette = &VoxelPaletteAsset{VoxPalette: defaultPalette()}
+	}
+	materials := materialTableFromPalette(palette)
+	for _, group := range volume.GreedyMesh(xbm).Groups {
+		primitive := mesh.Primitive{
+			Material:  len(out.Materials),
+			Positions: make([][3]float32, 0, len(group.Quads)*4),
+			Normals:   make([][3]float32, 0, len(group.Quads)*4),
+			Indices:   make([]uint32, 0, len(group.Quads)*6),
+		}
+		for _, quad := range group.Quads {
+			base := uint32(len(primitive.Positions))
+			for _, corner := range quad.Corners {
+				p := corner.Mul(voxelSize).Add(offset)
+				primitive.Positions = append(primitive.Positions, [3]float32{p.X(), p.Y(), p.Z()})
+				primitive.Normals = append(primitive.Normals, [3]float32{quad.Normal.X(), quad.Normal.Y(), quad.Normal.Z()})
+			}
+			primitive.Indices = append(primitive.Indices, base, base+1, base+2, base, base+2, base+3)
+		}
+		out.Materials = append(out.Materials, exportMeshMaterial(group.PaletteIndex, materials[group.PaletteIndex]))
+		out.Primitives = append(out.Primitives, primitive)
+	}
+	return out
+}
+
+// SaveVoxelMesh writes m to path, choosing glTF binary for .glb and
+// OBJ/MTL for .obj.
+func SaveVoxelMesh(path string, m *mesh.Mesh) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".glb":
+		return mesh.SaveGLB(path, m)
+	case ".obj":
+		return mesh.SaveOBJ(path, m)
+	default:
+		return fmt.Errorf("unsupported mesh export format %q", filepath.Ext(path))
+	}
+}
+
+// BuildAuthoredAssetMesh bakes every voxel-backed part of def into one
+// composite, the same way voxel collapse does, and meshes the result in
+// asset space.
+func BuildAuthoredAssetMesh(assets *AssetServer, def *content.AssetDef, documentPath string) (*mesh.Mesh, error) {
+	if assets == nil {
+		return nil, fmt.Errorf("mesh export requires asset server")
+	}
+	if def == nil {
+		return nil, fmt.Errorf("asset definition is nil")
+	}
+	parts, err := resolveAuthoredCollapseParts(assets, def, documentPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("asset %s has no voxel-backed parts", def.ID)
+	}
+	voxelResolution := parts[0].voxelResolution
+	paletteID, err := collapsePaletteID(assets, parts)
+	if err != nil {
+		return nil, err
+	}
+	palette, ok := assets.GetVoxelPalette(paletteID)
+	if !ok {
+		return nil, fmt.Errorf("missing palette for asset %s", def.ID)
+	}
+	combined := volume.NewXBrickMap()
+	for _, part := range parts {
+		if absf(part.voxelResolution-voxelResolution) > 1e-5 {
+			return nil, fmt.Errorf("mesh export requires matching voxel_resolution")
+		}
+		if voxelGeometryIsEmpty(part.geometry) {
+			continue
+		}
+		if err := bakeResolvedPartIntoComposite(combined, part, voxelResolution); err != nil {
+			return nil, fmt.Errorf("mesh export bake failed for part %s: %w", part.def.ID, err)
+		}
+	}
+	return VoxelMeshFromXBrickMap(def.Name, combined, &palette, voxelResolution, mgl32.Vec3{}), nil
+}
+
+// ExportAuthoredAssetMesh writes the collapsed geometry of def to path.
+func ExportAuthoredAssetMesh(assets *AssetServer, def *content.AssetDef, documentPath string, path string) error {
+	m, err := BuildAuthoredAssetMesh(assets, def, documentPath)
+	if err != nil {
+		return err
+	}
+	return SaveVoxelMesh(path, m)
+}
+
+// BuildImportedWorldChunkMesh meshes one imported world chunk in world space
+// using the world's palette and materials.
+func BuildImportedWorldChunkMesh(assets *AssetServer, world *content.ImportedWorldDef, chunk *content.ImportedWorldChunkDef) (*mesh.Mesh, error) {
+	if chunk == nil {
+		return nil, fmt.Errorf("imported world chunk is nil")
+	}
+	var palette *VoxelPaletteAsset
+	if paletteID := ImportedWorldPaletteAsset(assets, world); paletteID != (AssetId{}) {
+		if asset, ok := assets.GetVoxelPalette(paletteID); ok {
+			palette = &asset
+		}
+	}
+	name := fmt.Sprintf("chunk_%d_%d_%d", chunk.Coord.X, chunk.Coord.Y, chunk.Coord.Z)
+	if world != nil && world.WorldID != "" {
+		name = world.WorldID + "_" + name
+	}
+	return VoxelMeshFromXBrickMap(name, ImportedWorldChunkToXBrickMap(chunk), palette, chunk.VoxelResolution, importedWorldChunkPosition(chunk)), nil
+}
+
+// ExportImportedWorldChunkMesh writes one imported world chunk to path.
+func ExportImportedWorldChunkMesh(assets *AssetServer, world *content.ImportedWorldDef, chunk *content.ImportedWorldChunkDef, path string) error {
+	m, err := BuildImportedWorldChunkMesh(assets, world, chunk)
+	if err != nil {
+		return err
+	}
+	return SaveVoxelMesh(path, m)
+}
+
+func exportMeshMaterial(paletteIndex uint8, mat core.Material) mesh.Material {
+	emissive := [3]float32{}
+	if mat.Emission > 0 {
+		strength := float32(math.Min(float64(mat.Emission), 1))
+		for i := 0; i < 3; i++ {
+			emissive[i] = float32(mat.Emissive[i]) / 255 * strength
+		}
+	}
+	return mesh.Material{
+		Name: fmt.Sprintf("palette_%d", paletteIndex),
+		BaseColor: [4]float32{
+			float32(mat.BaseColor[0]) / 255,
+			float32(mat.BaseColor[1]) / 255,
+			float32(mat.BaseColor[2]) / 255,
+			1 - mat.Transparency,
+		},
+		Metallic:  mat.Metalness,
+		Roughness: mat.Roughness,
+		Emissive:  emissive,
+	}
+}
@@ -0,0 +1,114 @@
+package gekko
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gekko3d/gekko/content"
+	"github.com/gekko3d/gekko/voxelrt/rt/volume"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestVoxelMeshFromXBrickMapUsesPaletteMaterials(t *testing.T) {
+	xbm := volume.NewXBrickMap()
+	volume.Cube(xbm, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{3, 3, 3}, 1)
+	xbm.SetVoxel(0, 6, 0, 2)
+	var palette VoxPalette
+	palette[1] = [4]uint8{255, 0, 0, 255}
+	palette[2] = [4]uint8{0, 0, 255, 255}
+
+	m := VoxelMeshFromXBrickMap("test", xbm, &VoxelPaletteAsset{VoxPalette: palette}, 0.5, mgl32.Vec3{10, 0, 0})
+	if len(m.Materials) != 2 || len(m.Primitives) != 2 {
+		t.Fatalf("expected one material group per palette index, got %d materials", len(m.Materials))
+	}
+	if m.Materials[0].BaseColor != ([4]float32{1, 0, 0, 1}) || m.Materials[1].BaseColor != ([4]float32{0, 0, 1, 1}) {
+		t.Fatalf("unexpected material colors %+v", m.Materials)
+	}
+	// A greedy-meshed cube is six quads, regardless of its voxel extent.
+	if got := len(m.Primitives[0].Indices) / 6; got != 6 {
+		t.Fatalf("expected six merged quads for the cube, got %d", got)
+	}
+	for _, p := range m.Primitives[0].Positions {
+		if p[0] < 10 || p[0] > 12 {
+			t.Fatalf("expected positions scaled by voxel size and offset, got %v", p)
+		}
+	}
+}
+
+func TestExportAuthoredAssetMeshWritesGLBAndOBJ(t *testing.T) {
+	assets := newSpawnTestAssetServer()
+	def := content.NewAssetDef("export-mesh")
+	def.Parts = []content.AssetPartDef{{
+		ID:   "body",
+		Name: "body",
+		Source: content.AssetSourceDef{
+			Kind:      content.AssetSourceKindProceduralPrimitive,
+			Primitive: "cube",
+			Params:    map[string]float32{"sx": 4, "sy": 2, "sz": 2},
+		},
+		Transform: content.AssetTransformDef{
+			Rotation: content.Quat{0, 0, 0, 1},
+			Scale:    content.Vec3{1, 1, 1},
+		},
+		ModelScale: 1,
+	}}
+
+	dir := t.TempDir()
+	glbPath := filepath.Join(dir, "asset.glb")
+	if err := ExportAuthoredAssetMesh(assets, def, "", glbPath); err != nil {
+		t.Fatalf("ExportAuthoredAssetMesh glb failed: %v", err)
+	}
+	data, err := os.ReadFile(glbPath)
+	if err != nil || len(data) < 12 || string(data[:4]) != "glTF" {
+		t.Fatalf("expected glTF binary output, err=%v", err)
+	}
+
+	objPath := filepath.Join(dir, "asset.obj")
+	if err := ExportAuthoredAssetMesh(assets, def, "", objPath); err != nil {
+		t.Fatalf("ExportAuthoredAssetMesh obj failed: %v", err)
+	}
+	obj, err := os.ReadFile(objPath)
+	if err != nil || !strings.Contains(string(obj), "mtllib asset.mtl") {
+		t.Fatalf("expected OBJ referencing its material library, err=%v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "asset.mtl")); err != nil {
+		t.Fatalf("expected MTL beside OBJ: %v", err)
+	}
+
+	if err := ExportAuthoredAssetMesh(assets, def, "", filepath.Join(dir, "asset.fbx")); err == nil {
+		t.Fatal("expected unsupported extension to fail")
+	}
+}
+
+func TestBuildImportedWorldChunkMeshPlacesChunkInWorldSpace(t *testing.T) {
+	assets := newSpawnTestAssetServer()
+	world := &content.ImportedWorldDef{
+		WorldID: "world",
+		Palette: []content.ImportedWorldPaletteColor{{0, 0, 0, 0}, {200, 100, 50, 255}},
+	}
+	chunk := &content.ImportedWorldChunkDef{
+		WorldID:         "world",
+		Coord:           content.TerrainChunkCoordDef{X: 2, Y: 0, Z: 0},
+		ChunkSize:       32,
+		VoxelResolution: 0.25,
+		Voxels:          []content.ImportedWorldVoxelDef{{X: 0, Y: 0, Z: 0, Value: 1}},
+	}
+
+	m, err := BuildImportedWorldChunkMesh(assets, world, chunk)
+	if err != nil {
+		t.Fatalf("BuildImportedWorldChunkMesh failed: %v", err)
+	}
+	if m.Name != "world_chunk_2_0_0" || len(m.Primitives) != 1 {
+		t.Fatalf("unexpected chunk mesh %q with %d primitives", m.Name, len(m.Primitives))
+	}
+	if m.Materials[0].BaseColor[0] != float32(200)/255 {
+		t.Fatalf("expected world palette color, got %+v", m.Materials[0])
+	}
+	for _, p := range m.Primitives[0].Positions {
+		if p[0] < 16 || p[0] > 16.25 {
+			t.Fatalf("expected chunk offset of 16 world units, got %v", p)
+		}
+	}
+}
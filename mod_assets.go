@@ -65,11 +65,18 @@ type AssetServer struct {
 	voxPalettes    map[AssetId]VoxelPaletteAsset
 	voxPaletteKeys map[string]AssetId
 	voxFiles       map[AssetId]*VoxFile
+
+	impostorCacheDir string
+	impostorBakes    map[string]*entityLODImpostorBake
 }
 
-type AssetServerModule struct{}
+type AssetServerModule struct {
+	// ImpostorCacheDir is where baked entity-LOD impostor atlases are cached.
+	// Empty uses the user cache directory.
+	ImpostorCacheDir string
+}
 
-func (AssetServerModule) Install(app *App, cmd *Commands) {
+func (mod AssetServerModule) Install(app *App, cmd *Commands) {
 	server := &AssetServer{
 		meshes:         make(map[AssetId]MeshAsset),
 		materials:      make(map[AssetId]MaterialAsset),
@@ -82,6 +89,10 @@ func (AssetServerModule) Install(app *App, cmd *Commands) {
 		voxPaletteKeys: make(map[string]AssetId),
 		voxFiles:       make(map[AssetId]*VoxFile),
 	}
+	server.impostorCacheDir = mod.ImpostorCacheDir
+	if server.impostorCacheDir == "" {
+		server.impostorCacheDir = defaultEntityLODImpostorCacheDir()
+	}
 	cmd.AddResources(server)
 }
 
@@ -0,0 +1,318 @@
+package gekko
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	rootaudio "github.com/gekko3d/gekko/audio"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type AudioClip = rootaudio.Clip
+type AudioBus = rootaudio.Bus
+type AudioBackend = rootaudio.Backend
+type AudioAttenuation = rootaudio.Attenuation
+
+// LoadAudioClip decodes a WAV or OGG Vorbis file.
+func LoadAudioClip(path string) (*AudioClip, error) {
+	return rootaudio.LoadClip(path)
+}
+
+// AudioSourceComponent plays a clip from its entity. Spatial sources are
+// positioned by the entity's TransformComponent relative to the listener.
+type AudioSourceComponent struct {
+	Clip *AudioClip
+	// Path is loaded through the AudioState clip cache when Clip is nil.
+	Path   string
+	Bus    string  // empty plays through the "sfx" bus
+	Volume float32 // zero means 1
+	Pitch  float32 // zero means 1
+	Loop   bool
+	// Playing starts the source; it is cleared when a one-shot finishes or
+	// cannot get a voice.
+	Playing bool
+	// Spatial applies attenuation, panning and Doppler. Non-spatial sources
+	// play centered, for UI and music.
+	Spatial     bool
+	Attenuation AudioAttenuation
+	// Doppler scales the pitch shift from relative motion; zero disables it.
+	Doppler  float32
+	Priority int
+
+	lastPosition mgl32.Vec3
+	hasLast      bool
+	environment  audioSourceEnvironment
+}
+
+// AudioListenerComponent marks the entity whose TransformComponent hears
+// spatial sources. Without one, the first CameraComponent listens.
+type AudioListenerComponent struct {
+	lastPosition mgl32.Vec3
+	hasLast      bool
+}
+
+// AudioState owns the mixer and output backend.
+type AudioState struct {
+	Mixer   *rootaudio.Mixer
+	Backend AudioBackend
+
+	clips     map[string]*AudioClip
+	voices    map[EntityId]rootaudio.VoiceID
+	remainder float64
+	buffer    []float32
+	// listener is the environment's listener-wide effect on spatial sources,
+	// such as underwater muffling.
+	listener audioSourceEnvironment
+	// lastListener is where the previous frame heard from, for one-shots.
+	lastListener audioListener
+}
+
+func NewAudioState(backend AudioBackend, mixer *rootaudio.Mixer) *AudioState {
+	if backend == nil {
+		backend = &rootaudio.NullBackend{}
+	}
+	if mixer == nil {
+		mixer = rootaudio.NewMixer(0, 0)
+	}
+	return &AudioState{
+		Mixer:   mixer,
+		Backend: backend,
+		clips:   map[string]*AudioClip{},
+		voices:  map[EntityId]rootaudio.VoiceID{},
+
+		lastListener: audioListener{right: mgl32.Vec3{1, 0, 0}},
+	}
+}
+
+// LoadClip decodes path once and caches the result, including failures.
+func (s *AudioState) LoadClip(path string) (*AudioClip, error) {
+	if clip, ok := s.clips[path]; ok {
+		if clip == nil {
+			return nil, fmt.Errorf("audio clip %s failed to load", path)
+		}
+		return clip, nil
+	}
+	clip, err := rootaudio.LoadClip(path)
+	s.clips[path] = clip
+	return clip, err
+}
+
+// PlayAt starts a fire-and-forget spatial one-shot from path on the "sfx"
+// bus, heard from the previous frame's listener. It returns zero when the clip
+// fails to load, is out of range, or loses voice allocation.
+func (s *AudioState) PlayAt(path string, position mgl32.Vec3, volume, pitch float32, attenuation AudioAttenuation) rootaudio.VoiceID {
+	clip, err := s.LoadClip(path)
+	if err != nil {
+		fmt.Printf("WARNING: audio one-shot: %v\n", err)
+		return 0
+	}
+	params := rootaudio.VoiceParams{
+		Gain:  defaulted(volume, 1) * attenuation.Gain(position.Sub(s.lastListener.position).Len()),
+		Pitch: defaulted(pitch, 1),
+		Pan:   rootaudio.Pan(s.lastListener.position, s.lastListener.right, position),
+		Bus:   "sfx",
+	}
+	s.listener.apply(&params)
+	if params.Gain <= 0 {
+		return 0
+	}
+	return s.Mixer.Play(clip, params)
+}
+
+type AudioModule struct {
+	// Backend defaults to the default output device, or a NullBackend when
+	// no device opens.
+	Backend    AudioBackend
+	SampleRate int
+	MaxVoices  int
+	// Buses default to rootaudio.DefaultBuses.
+	Buses []AudioBus
+}
+
+func (mod AudioModule) Install(app *App, cmd *Commands) {
+	mixer := rootaudio.NewMixer(mod.SampleRate, mod.MaxVoices, mod.Buses...)
+	backend := mod.Backend
+	if backend == nil {
+		device, err := rootaudio.NewDeviceBackend(mixer.SampleRate)
+		if err != nil {
+			fmt.Printf("WARNING: audio device unavailable, audio is muted: %v\n", err)
+		} else {
+			backend = device
+		}
+	}
+	cmd.AddResources(NewAudioState(backend, mixer))
+	app.UseSystem(
+		System(audioSystem).
+			InStage(PostUpdate).
+			RunAlways(),
+	)
+}
+
+func audioStateFromApp(app *App) *AudioState {
+	if app == nil {
+		return nil
+	}
+	if resource, ok := app.resources[reflect.TypeOf(AudioState{})]; ok {
+		if state, ok := resource.(*AudioState); ok {
+			return state
+		}
+	}
+	return nil
+}
+
+type audioListener struct {
+	position mgl32.Vec3
+	right    mgl32.Vec3
+	velocity mgl32.Vec3
+}
+
+// audioSystem updates voices from sources and pushes this frame's share of
+// mixed audio to the backend.
+func audioSystem(time *Time, state *AudioState, cmd *Commands) {
+	dt := float32(time.Dt)
+	listener := findAudioListener(cmd, dt)
+	state.lastListener = listener
+
+	positions := map[EntityId]mgl32.Vec3{}
+	MakeQuery2[AudioSourceComponent, TransformComponent](cmd).Map(func(eid EntityId, _ *AudioSourceComponent, tr *TransformComponent) bool {
+		positions[eid] = tr.Position
+		return true
+	})
+
+	seen := map[EntityId]bool{}
+	MakeQuery1[AudioSourceComponent](cmd).Map(func(eid EntityId, src *AudioSourceComponent) bool {
+		seen[eid] = true
+		position, hasPosition := positions[eid]
+		updateAudioSource(state, eid, src, listener, position, hasPosition, dt)
+		return true
+	})
+	for eid, voice := range state.voices {
+		if !seen[eid] {
+			state.Mixer.Stop(voice)
+			delete(state.voices, eid)
+		}
+	}
+
+	mixAudioFrame(state, float64(time.Dt))
+}
+
+func updateAudioSource(state *AudioState, eid EntityId, src *AudioSourceComponent, listener audioListener, position mgl32.Vec3, hasPosition bool, dt float32) {
+	voice := state.voices[eid]
+	if !src.Playing {
+		if voice != 0 {
+			state.Mixer.Stop(voice)
+			delete(state.voices, eid)
+		}
+		src.hasLast = false
+		return
+	}
+	clip := src.Clip
+	if clip == nil && src.Path != "" {
+		var err error
+		if clip, err = state.LoadClip(src.Path); err != nil {
+			fmt.Printf("WARNING: audio source %v: %v\n", eid, err)
+			src.Playing = false
+			return
+		}
+	}
+	if clip == nil {
+		src.Playing = false
+		return
+	}
+
+	params := rootaudio.VoiceParams{
+		Gain:     defaulted(src.Volume, 1),
+		Pitch:    defaulted(src.Pitch, 1),
+		Bus:      src.Bus,
+		Loop:     src.Loop,
+		Priority: src.Priority,
+	}
+	if params.Bus == "" {
+		params.Bus = "sfx"
+	}
+	if src.Spatial && hasPosition {
+		params.Gain *= src.Attenuation.Gain(position.Sub(listener.position).Len())
+		params.Pan = rootaudio.Pan(listener.position, listener.right, position)
+		var velocity mgl32.Vec3
+		if src.hasLast && dt > 0 {
+			velocity = position.Sub(src.lastPosition).Mul(1 / dt)
+		}
+		params.Pitch *= rootaudio.DopplerPitch(listener.position, listener.velocity, position, velocity, src.Doppler)
+		src.lastPosition, src.hasLast = position, true
+		src.environment.apply(&params)
+		state.listener.apply(&params)
+	}
+
+	if voice != 0 && state.Mixer.Update(voice, params) {
+		return
+	}
+	// A finished one-shot stops here; a stolen loop asks for a new voice.
+	if voice != 0 && !src.Loop {
+		delete(state.voices, eid)
+		src.Playing = false
+		return
+	}
+	if voice = state.Mixer.Play(clip, params); voice == 0 {
+		delete(state.voices, eid)
+		if !src.Loop {
+			src.Playing = false
+		}
+		return
+	}
+	state.voices[eid] = voice
+}
+
+func findAudioListener(cmd *Commands, dt float32) audioListener {
+	listener := audioListener{right: mgl32.Vec3{1, 0, 0}}
+	found := false
+	MakeQuery2[AudioListenerComponent, TransformComponent](cmd).Map(func(_ EntityId, l *AudioListenerComponent, tr *TransformComponent) bool {
+		listener.position = tr.Position
+		rotation := tr.Rotation
+		if rotation.Len() < 1e-6 {
+			rotation = mgl32.QuatIdent()
+		}
+		listener.right = rotation.Rotate(mgl32.Vec3{1, 0, 0})
+		if l.hasLast && dt > 0 {
+			listener.velocity = tr.Position.Sub(l.lastPosition).Mul(1 / dt)
+		}
+		l.lastPosition, l.hasLast = tr.Position, true
+		found = true
+		return false
+	})
+	if found {
+		return listener
+	}
+	MakeQuery1[CameraComponent](cmd).Map(func(_ EntityId, cam *CameraComponent) bool {
+		listener.position = cam.Position
+		forward := cam.LookAt.Sub(cam.Position)
+		if right := forward.Cross(cam.Up); right.Len() > 1e-6 {
+			listener.right = right.Normalize()
+		}
+		return false
+	})
+	return listener
+}
+
+// mixAudioFrame renders dt seconds of audio, carrying the fractional frame
+// into the next call so the output rate stays exact.
+func mixAudioFrame(state *AudioState, dt float64) {
+	if dt <= 0 {
+		return
+	}
+	exact := dt*float64(state.Mixer.SampleRate) + state.remainder
+	// The epsilon keeps accumulated rounding from dropping a whole frame.
+	frames := int(math.Floor(exact + 1e-6))
+	state.remainder = max(0, exact-float64(frames))
+	if frames == 0 {
+		return
+	}
+	if cap(state.buffer) < frames*2 {
+		state.buffer = make([]float32, frames*2)
+	}
+	state.buffer = state.buffer[:frames*2]
+	state.Mixer.Mix(state.buffer)
+	if err := state.Backend.Write(state.buffer); err != nil {
+		fmt.Printf("ERROR: audio backend write failed: %v\n", err)
+	}
+}
@@ -0,0 +1,345 @@
+package gekko
+
+import (
+	"math"
+
+	rootaudio "github.com/gekko3d/gekko/audio"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type AudioRoom = rootaudio.Room
+type AudioOcclusionResponse = rootaudio.OcclusionResponse
+
+// AudioRaycaster is the voxel query the environment samples. VoxelRtState
+// implements it over XBrickMap objects, imported-world chunks included.
+type AudioRaycaster interface {
+	Raycast(origin, dir mgl32.Vec3, tMax float32) RaycastHit
+}
+
+type AudioEnvironmentConfig struct {
+	// OcclusionRays are cast from the listener to points spread over a
+	// SourceRadius disc around each spatial source.
+	OcclusionRays int
+	SourceRadius  float32
+	Occlusion     AudioOcclusionResponse
+	// ProbeRays are cast from the listener over the sphere, up to
+	// ProbeDistance, to estimate room size and openness for the reverb.
+	ProbeRays     int
+	ProbeDistance float32
+	// ReverbSend is an unblocked source's send; fully occluded sources send
+	// twice as much, since they are mostly heard through reflections.
+	ReverbSend float32
+	// UnderwaterCutoff and UnderwaterGain muffle spatial sources while the
+	// listener is inside a water body.
+	UnderwaterCutoff float32
+	UnderwaterGain   float32
+	// SampleInterval is the time between raycast passes in seconds.
+	SampleInterval float32
+	// Smoothing is the time constant in seconds that occlusion and the room
+	// ease toward each new sample with.
+	Smoothing float32
+}
+
+func DefaultAudioEnvironmentConfig() AudioEnvironmentConfig {
+	return AudioEnvironmentConfig{
+		OcclusionRays:    5,
+		SourceRadius:     0.5,
+		Occlusion:        AudioOcclusionResponse{OccludedGain: 0.35, OccludedCutoff: 900},
+		ProbeRays:        16,
+		ProbeDistance:    32,
+		ReverbSend:       0.35,
+		UnderwaterCutoff: 500,
+		UnderwaterGain:   0.6,
+		SampleInterval:   0.1,
+		Smoothing:        0.15,
+	}
+}
+
+func effectiveAudioEnvironmentConfig(cfg AudioEnvironmentConfig) AudioEnvironmentConfig {
+	defaults := DefaultAudioEnvironmentConfig()
+	if cfg.OcclusionRays != 0 {
+		defaults.OcclusionRays = cfg.OcclusionRays
+	}
+	if cfg.SourceRadius != 0 {
+		defaults.SourceRadius = cfg.SourceRadius
+	}
+	if cfg.Occlusion.OccludedGain != 0 {
+		defaults.Occlusion.OccludedGain = cfg.Occlusion.OccludedGain
+	}
+	if cfg.Occlusion.OccludedCutoff != 0 {
+		defaults.Occlusion.OccludedCutoff = cfg.Occlusion.OccludedCutoff
+	}
+	if cfg.ProbeRays != 0 {
+		defaults.ProbeRays = cfg.ProbeRays
+	}
+	if cfg.ProbeDistance != 0 {
+		defaults.ProbeDistance = cfg.ProbeDistance
+	}
+	if cfg.ReverbSend != 0 {
+		defaults.ReverbSend = cfg.ReverbSend
+	}
+	if cfg.UnderwaterCutoff != 0 {
+		defaults.UnderwaterCutoff = cfg.UnderwaterCutoff
+	}
+	if cfg.UnderwaterGain != 0 {
+		defaults.UnderwaterGain = cfg.UnderwaterGain
+	}
+	if cfg.SampleInterval != 0 {
+		defaults.SampleInterval = cfg.SampleInterval
+	}
+	if cfg.Smoothing != 0 {
+		defaults.Smoothing = cfg.Smoothing
+	}
+	return defaults
+}
+
+// audioSourceEnvironment is the environment's effect on one voice.
+type audioSourceEnvironment struct {
+	active     bool
+	gain       float32
+	lowPass    float32
+	reverbSend float32
+}
+
+func (e audioSourceEnvironment) apply(params *rootaudio.VoiceParams) {
+	if !e.active {
+		return
+	}
+	params.Gain *= e.gain
+	params.LowPass = rootaudio.CombineLowPass(params.LowPass, e.lowPass)
+	params.ReverbSend += e.reverbSend
+}
+
+type audioOcclusion struct {
+	target  float32
+	current float32
+}
+
+// AudioEnvironmentState holds the sampled room and per-source occlusion.
+type AudioEnvironmentState struct {
+	Config AudioEnvironmentConfig
+	// Room is the smoothed estimate around the listener.
+	Room       AudioRoom
+	Underwater bool
+
+	targetRoom  AudioRoom
+	occlusion   map[EntityId]*audioOcclusion
+	sinceSample float32
+	sampled     bool
+}
+
+func NewAudioEnvironmentState(cfg AudioEnvironmentConfig) *AudioEnvironmentState {
+	return &AudioEnvironmentState{
+		Config:    effectiveAudioEnvironmentConfig(cfg),
+		Room:      AudioRoom{Openness: 1},
+		occlusion: map[EntityId]*audioOcclusion{},
+	}
+}
+
+// Occlusion returns the smoothed occlusion of a source in 0..1.
+func (s *AudioEnvironmentState) Occlusion(eid EntityId) float32 {
+	if o := s.occlusion[eid]; o != nil {
+		return o.current
+	}
+	return 0
+}
+
+type AudioEnvironmentModule struct {
+	Config AudioEnvironmentConfig
+}
+
+func (mod AudioEnvironmentModule) Install(app *App, cmd *Commands) {
+	cmd.AddResources(NewAudioEnvironmentState(mod.Config))
+	app.UseSystem(
+		System(audioEnvironmentSystem).
+			InStage(Update).
+			RunAlways(),
+	)
+}
+
+func audioEnvironmentSystem(time *Time, cmd *Commands, audio *AudioState, env *AudioEnvironmentState, voxRt *VoxelRtState) {
+	env.Update(cmd, audio, voxRt, float32(time.Dt))
+}
+
+// Update samples the environment every SampleInterval, eases toward the
+// samples, and hands the result to the mixer. A nil raycaster sees no
+// geometry.
+func (s *AudioEnvironmentState) Update(cmd *Commands, audio *AudioState, raycaster AudioRaycaster, dt float32) {
+	listener, ok := audioListenerPosition(cmd)
+	if !ok {
+		return
+	}
+	cfg := s.Config
+
+	positions := map[EntityId]mgl32.Vec3{}
+	MakeQuery2[AudioSourceComponent, TransformComponent](cmd).Map(func(eid EntityId, src *AudioSourceComponent, tr *TransformComponent) bool {
+		if src.Spatial {
+			positions[eid] = tr.Position
+		}
+		return true
+	})
+
+	s.sinceSample += dt
+	if !s.sampled || s.sinceSample >= cfg.SampleInterval {
+		s.sinceSample = 0
+		s.targetRoom = sampleAudioRoom(raycaster, listener, cfg)
+		for eid, position := range positions {
+			o := s.occlusion[eid]
+			if o == nil {
+				// New sources start at their sampled occlusion instead of
+				// fading in from open.
+				o = &audioOcclusion{current: -1}
+				s.occlusion[eid] = o
+			}
+			o.target = sampleAudioOcclusion(raycaster, eid, listener, position, cfg)
+			if o.current < 0 {
+				o.current = o.target
+			}
+		}
+		if !s.sampled {
+			s.Room = s.targetRoom
+		}
+		s.sampled = true
+	}
+
+	blend := float32(1)
+	if cfg.Smoothing > 0 {
+		blend = float32(1 - math.Exp(-float64(dt/cfg.Smoothing)))
+	}
+	s.Room = s.Room.Lerp(s.targetRoom, blend)
+	for eid, o := range s.occlusion {
+		if _, ok := positions[eid]; !ok {
+			delete(s.occlusion, eid)
+			continue
+		}
+		o.current += (o.target - o.current) * blend
+	}
+
+	s.Underwater = pointUnderwater(cmd, listener)
+	if audio == nil {
+		return
+	}
+	reverb := s.Room.Reverb(cfg.ProbeDistance)
+	audio.listener = audioSourceEnvironment{}
+	if s.Underwater {
+		reverb.Damping = 1
+		audio.listener = audioSourceEnvironment{active: true, gain: cfg.UnderwaterGain, lowPass: cfg.UnderwaterCutoff}
+	}
+	audio.Mixer.SetReverb(reverb)
+
+	MakeQuery1[AudioSourceComponent](cmd).Map(func(eid EntityId, src *AudioSourceComponent) bool {
+		o := s.occlusion[eid]
+		if o == nil {
+			src.environment = audioSourceEnvironment{}
+			return true
+		}
+		gain, lowPass := cfg.Occlusion.Apply(o.current)
+		src.environment = audioSourceEnvironment{
+			active:     true,
+			gain:       gain,
+			lowPass:    lowPass,
+			reverbSend: cfg.ReverbSend * (1 + o.current),
+		}
+		return true
+	})
+}
+
+// audioOcclusionEndMargin ignores hits this close to the source, so the
+// floor a source stands on does not count as a wall.
+const audioOcclusionEndMargin = 0.25
+
+// sampleAudioOcclusion returns the fraction of rays from the listener to
+// points around the source that are blocked by geometry other than the
+// source itself.
+func sampleAudioOcclusion(raycaster AudioRaycaster, source EntityId, listener, position mgl32.Vec3, cfg AudioEnvironmentConfig) float32 {
+	if raycaster == nil || cfg.OcclusionRays <= 0 {
+		return 0
+	}
+	targets := audioOcclusionTargets(listener, position, cfg.SourceRadius, cfg.OcclusionRays)
+	blocked := 0
+	for _, target := range targets {
+		offset := target.Sub(listener)
+		distance := offset.Len()
+		if distance <= audioOcclusionEndMargin {
+			continue
+		}
+		hit := raycaster.Raycast(listener, offset.Mul(1/distance), distance)
+		if hit.Hit && hit.Entity != source && hit.T < distance-audioOcclusionEndMargin {
+			blocked++
+		}
+	}
+	return float32(blocked) / float32(len(targets))
+}
+
+// audioOcclusionTargets returns the source center followed by points evenly
+// spaced on a disc of radius facing the listener.
+func audioOcclusionTargets(listener, position mgl32.Vec3, radius float32, count int) []mgl32.Vec3 {
+	targets := []mgl32.Vec3{position}
+	if count <= 1 || radius <= 0 {
+		return targets
+	}
+	forward := position.Sub(listener)
+	if forward.Len() < 1e-5 {
+		return targets
+	}
+	forward = forward.Normalize()
+	up := mgl32.Vec3{0, 1, 0}
+	if math.Abs(float64(forward.Dot(up))) > 0.99 {
+		up = mgl32.Vec3{1, 0, 0}
+	}
+	right := forward.Cross(up).Normalize()
+	up = right.Cross(forward)
+	for i := 1; i < count; i++ {
+		angle := 2 * math.Pi * float64(i-1) / float64(count-1)
+		offset := right.Mul(float32(math.Cos(angle))).Add(up.Mul(float32(math.Sin(angle)))).Mul(radius)
+		targets = append(targets, position.Add(offset))
+	}
+	return targets
+}
+
+func sampleAudioRoom(raycaster AudioRaycaster, listener mgl32.Vec3, cfg AudioEnvironmentConfig) AudioRoom {
+	if raycaster == nil || cfg.ProbeRays <= 0 {
+		return AudioRoom{Openness: 1}
+	}
+	dirs := rootaudio.ProbeDirections(cfg.ProbeRays)
+	distances := make([]float32, len(dirs))
+	for i, dir := range dirs {
+		distances[i] = -1
+		if hit := raycaster.Raycast(listener, dir, cfg.ProbeDistance); hit.Hit {
+			distances[i] = hit.T
+		}
+	}
+	return rootaudio.EstimateRoom(distances)
+}
+
+// pointUnderwater reports whether p is below the surface of a water surface
+// or a resolved WaterBodyComponent patch.
+func pointUnderwater(cmd *Commands, p mgl32.Vec3) bool {
+	for _, water := range collectWaterInteractionBodies(cmd) {
+		if p.X() >= water.Center.X()-water.HalfExtents[0] && p.X() <= water.Center.X()+water.HalfExtents[0] &&
+			p.Z() >= water.Center.Z()-water.HalfExtents[1] && p.Z() <= water.Center.Z()+water.HalfExtents[1] &&
+			p.Y() < water.SurfaceY && p.Y() >= water.BottomY {
+			return true
+		}
+	}
+	return false
+}
+
+// audioListenerPosition finds the listener like findAudioListener, without
+// touching its velocity tracking.
+func audioListenerPosition(cmd *Commands) (mgl32.Vec3, bool) {
+	var position mgl32.Vec3
+	found := false
+	MakeQuery2[AudioListenerComponent, TransformComponent](cmd).Map(func(_ EntityId, _ *AudioListenerComponent, tr *TransformComponent) bool {
+		position, found = tr.Position, true
+		return false
+	})
+	if found {
+		return position, true
+	}
+	MakeQuery1[CameraComponent](cmd).Map(func(_ EntityId, cam *CameraComponent) bool {
+		position, found = cam.Position, true
+		return false
+	})
+	return position, found
+}
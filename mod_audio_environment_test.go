@@ -0,0 +1,131 @@
+package gekko
+
+import (
+	"testing"
+
+	rootaudio "github.com/gekko3d/gekko/audio"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// wallAudioRaycaster blocks rays crossing the plane x = wallX, and, when
+// boxHalf is set, rays leaving a box of that half size around the origin.
+type wallAudioRaycaster struct {
+	wallX   float32
+	boxHalf float32
+}
+
+func (r wallAudioRaycaster) Raycast(origin, dir mgl32.Vec3, tMax float32) RaycastHit {
+	best := tMax
+	hit := false
+	if r.wallX != 0 && dir.X() != 0 {
+		if t := (r.wallX - origin.X()) / dir.X(); t > 0 && t < best {
+			best, hit = t, true
+		}
+	}
+	if r.boxHalf > 0 {
+		for axis := 0; axis < 3; axis++ {
+			if dir[axis] == 0 {
+				continue
+			}
+			bound := r.boxHalf
+			if dir[axis] < 0 {
+				bound = -bound
+			}
+			if t := (bound - origin[axis]) / dir[axis]; t > 0 && t < best {
+				best, hit = t, true
+			}
+		}
+	}
+	if !hit {
+		return RaycastHit{}
+	}
+	return RaycastHit{Hit: true, T: best, Entity: 99}
+}
+
+func TestAudioEnvironmentOccludesSourcesBehindWalls(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	state := NewAudioState(&rootaudio.NullBackend{}, nil)
+	env := NewAudioEnvironmentState(AudioEnvironmentConfig{SourceRadius: 0.3})
+	cmd.AddEntity(&TransformComponent{Rotation: mgl32.QuatIdent()}, &AudioListenerComponent{})
+	behind := cmd.AddEntity(&TransformComponent{Position: mgl32.Vec3{5, 0, 0}, Rotation: mgl32.QuatIdent()}, &AudioSourceComponent{Spatial: true})
+	visible := cmd.AddEntity(&TransformComponent{Position: mgl32.Vec3{-5, 0, 0}, Rotation: mgl32.QuatIdent()}, &AudioSourceComponent{Spatial: true})
+	app.FlushCommands()
+
+	env.Update(cmd, state, wallAudioRaycaster{wallX: 2}, 1.0/60)
+	if env.Occlusion(behind) != 1 || env.Occlusion(visible) != 0 {
+		t.Fatalf("expected only the source behind the wall occluded, got %v and %v", env.Occlusion(behind), env.Occlusion(visible))
+	}
+	params := rootaudio.VoiceParams{Gain: 1}
+	sourceComponent(cmd, behind).environment.apply(&params)
+	if params.Gain >= 0.5 || params.LowPass <= 0 || params.LowPass > 1000 {
+		t.Fatalf("expected a muffled occluded source, got %+v", params)
+	}
+	params = rootaudio.VoiceParams{Gain: 1}
+	sourceComponent(cmd, visible).environment.apply(&params)
+	if params.Gain != 1 || params.LowPass != 0 {
+		t.Fatalf("expected an unblocked source to stay dry, got %+v", params)
+	}
+
+	// Removing the wall eases occlusion down rather than snapping.
+	env.Update(cmd, state, wallAudioRaycaster{}, 0.1)
+	if occlusion := env.Occlusion(behind); occlusion <= 0 || occlusion >= 1 {
+		t.Fatalf("expected occlusion to be easing toward open, got %v", occlusion)
+	}
+}
+
+func TestAudioEnvironmentEstimatesRoomReverb(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	state := NewAudioState(&rootaudio.NullBackend{}, nil)
+	cmd.AddEntity(&TransformComponent{Rotation: mgl32.QuatIdent()}, &AudioListenerComponent{})
+	app.FlushCommands()
+
+	env := NewAudioEnvironmentState(AudioEnvironmentConfig{})
+	env.Update(cmd, state, wallAudioRaycaster{boxHalf: 3}, 1.0/60)
+	if env.Room.Openness != 0 || state.Mixer.Reverb().Wet != 1 {
+		t.Fatalf("expected an enclosed room with full reverb, got %+v / %+v", env.Room, state.Mixer.Reverb())
+	}
+
+	open := NewAudioEnvironmentState(AudioEnvironmentConfig{})
+	open.Update(cmd, state, nil, 1.0/60)
+	if open.Room.Openness != 1 || state.Mixer.Reverb().Wet != 0 {
+		t.Fatalf("expected no geometry to disable the reverb, got %+v", state.Mixer.Reverb())
+	}
+}
+
+func TestAudioEnvironmentMufflesUnderwaterListener(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	state := NewAudioState(&rootaudio.NullBackend{}, nil)
+	listener := cmd.AddEntity(&TransformComponent{Position: mgl32.Vec3{0, -1, 0}, Rotation: mgl32.QuatIdent()}, &AudioListenerComponent{})
+	cmd.AddEntity(&TransformComponent{Rotation: mgl32.QuatIdent(), Scale: mgl32.Vec3{1, 1, 1}}, &WaterSurfaceComponent{HalfExtents: [2]float32{10, 10}, Depth: 4})
+	app.FlushCommands()
+
+	env := NewAudioEnvironmentState(AudioEnvironmentConfig{UnderwaterCutoff: 400})
+	env.Update(cmd, state, nil, 1.0/60)
+	if !env.Underwater {
+		t.Fatal("expected the listener below the surface to be underwater")
+	}
+	params := rootaudio.VoiceParams{Gain: 1, LowPass: 2000}
+	state.listener.apply(&params)
+	if params.LowPass != 400 || params.Gain >= 1 {
+		t.Fatalf("expected underwater muffling, got %+v", params)
+	}
+
+	tr, _ := transformForEntity(cmd, listener)
+	tr.Position = mgl32.Vec3{0, 1, 0}
+	env.Update(cmd, state, nil, 1.0/60)
+	if env.Underwater || state.listener.active {
+		t.Fatal("expected surfacing to clear underwater muffling")
+	}
+}
+
+func sourceComponent(cmd *Commands, eid EntityId) *AudioSourceComponent {
+	for _, comp := range cmd.GetAllComponents(eid) {
+		if src, ok := comp.(*AudioSourceComponent); ok {
+			return src
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package gekko
+
+import (
+	"testing"
+
+	rootaudio "github.com/gekko3d/gekko/audio"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func testAudioClip(frames int) *AudioClip {
+	samples := make([]float32, frames)
+	for i := range samples {
+		samples[i] = 0.5
+	}
+	return &AudioClip{SampleRate: 48000, Channels: 1, Samples: samples}
+}
+
+func TestAudioSystemSpatializesSourcesAroundListener(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	backend := rootaudio.NewWAVWriterBackend(48000)
+	state := NewAudioState(backend, nil)
+	cmd.AddResources(state)
+
+	cmd.AddEntity(&TransformComponent{Rotation: mgl32.QuatIdent()}, &AudioListenerComponent{})
+	source := cmd.AddEntity(
+		&TransformComponent{Position: mgl32.Vec3{4, 0, 0}, Rotation: mgl32.QuatIdent()},
+		&AudioSourceComponent{Clip: testAudioClip(48000), Playing: true, Loop: true, Spatial: true, Attenuation: AudioAttenuation{MinDistance: 1, MaxDistance: 50}},
+	)
+	app.FlushCommands()
+
+	audioSystem(&Time{Dt: 0.1}, state, cmd)
+	samples := backend.Samples()
+	if len(samples) != 4800*2 {
+		t.Fatalf("expected 4800 stereo frames for 0.1s, got %d", len(samples)/2)
+	}
+	left, right := samples[len(samples)-2], samples[len(samples)-1]
+	if right <= left || right > 0.5 {
+		t.Fatalf("expected an attenuated source on the right, got L=%v R=%v", left, right)
+	}
+
+	cmd.RemoveEntity(source)
+	app.FlushCommands()
+	audioSystem(&Time{Dt: 0.1}, state, cmd)
+	if len(state.voices) != 0 || state.Mixer.ActiveVoices() != 0 {
+		t.Fatal("expected a despawned source to stop its voice")
+	}
+}
+
+func TestAudioSystemClearsFinishedOneShots(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	backend := &rootaudio.NullBackend{}
+	state := NewAudioState(backend, nil)
+	cmd.AddResources(state)
+
+	eid := cmd.AddEntity(&AudioSourceComponent{Clip: testAudioClip(100), Playing: true})
+	app.FlushCommands()
+	var src *AudioSourceComponent
+	for _, comp := range cmd.GetAllComponents(eid) {
+		if c, ok := comp.(*AudioSourceComponent); ok {
+			src = c
+		}
+	}
+
+	audioSystem(&Time{Dt: 1.0 / 60}, state, cmd)
+	if !src.Playing {
+		t.Fatal("expected the source to still be marked playing until its voice is checked")
+	}
+	audioSystem(&Time{Dt: 1.0 / 60}, state, cmd)
+	if src.Playing || len(state.voices) != 0 {
+		t.Fatal("expected a finished one-shot to clear Playing")
+	}
+	if backend.Frames != 1600 {
+		t.Fatalf("expected two frames of 800 samples, got %d", backend.Frames)
+	}
+}
+
+func TestMixAudioFrameCarriesFractionalFrames(t *testing.T) {
+	backend := &rootaudio.NullBackend{}
+	state := NewAudioState(backend, rootaudio.NewMixer(1000, 0))
+	for i := 0; i < 3; i++ {
+		mixAudioFrame(state, 1.0/3)
+	}
+	if backend.Frames != 1000 {
+		t.Fatalf("expected exactly one second of frames, got %d", backend.Frames)
+	}
+}
@@ -1,6 +1,8 @@
 package gekko
 
 import (
+	"fmt"
+
 	"github.com/cogentcore/webgpu/wgpu"
 	"github.com/go-gl/mathgl/mgl32"
 
@@ -250,8 +252,11 @@ func loadMeshes(cmd *Commands, assets *AssetServer, gpuState *GpuState) {
 func loadTextures(cmd *Commands, assets *AssetServer, gpuState *GpuState) {
 	MakeQuery2[wgpuTextureSet, wgpuSamplerSet](cmd).Map(
 		func(entityId EntityId, gpuTextureSet *wgpuTextureSet, gpuSamplerSet *wgpuSamplerSet) bool {
-			descriptors := findTextureDescriptors(entityId, cmd, assets)
 			if nil == gpuTextureSet {
+				descriptors, err := findTextureDescriptors(entityId, cmd, assets)
+				if err != nil {
+					fmt.Printf("WARNING: Skipping texture bindings of entity %v: %v\n", entityId, err)
+				}
 				txs := map[AssetId]wgpuTexture{}
 				for id, d := range descriptors {
 					txView := createTextureFromAsset(d.textureAsset, gpuState)
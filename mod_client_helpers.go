@@ -3,6 +3,7 @@ package gekko
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -10,7 +11,6 @@ import (
 	"unsafe"
 
 	"github.com/cogentcore/webgpu/wgpu"
-	"github.com/google/uuid"
 )
 
 func parseFormat(name string) wgpu.VertexFormat {
@@ -58,8 +58,12 @@ func wgpuFilterMode(mode string) wgpu.FilterMode {
 	}
 }
 
-func findTextureDescriptors(entityId EntityId, cmd *Commands, assets *AssetServer) map[AssetId]textureDescriptor {
+// findTextureDescriptors collects the gekko:"texture" bindings of an entity.
+// Render target textures have no texels to upload; their bindings are left
+// out and reported in the returned error.
+func findTextureDescriptors(entityId EntityId, cmd *Commands, assets *AssetServer) (map[AssetId]textureDescriptor, error) {
 	descriptors := map[AssetId]textureDescriptor{}
+	var errs []error
 	assetIdType := reflect.TypeOf(AssetId{})
 	allComponents := cmd.GetAllComponents(entityId)
 	for _, c := range allComponents {
@@ -82,13 +86,12 @@ func findTextureDescriptors(entityId EntityId, cmd *Commands, assets *AssetServe
 				if nil != err {
 					panic(err)
 				}
-				fieldVal := val.Field(i)
-				u, err := uuid.Parse(fieldVal.String())
-				if err != nil {
-					panic(err)
-				}
-				assetId := AssetId{UUID: u}
+				assetId := val.Field(i).Interface().(AssetId)
 				textureAsset := assets.textures[assetId]
+				if textureAsset.RenderTarget {
+					errs = append(errs, fmt.Errorf("%s.%s binds render target %s; only sprites and UI images can show render targets", t.Name(), field.Name, assetId.UUID))
+					continue
+				}
 
 				descriptors[assetId] = textureDescriptor{
 					version:      textureAsset.Version,
@@ -99,7 +102,7 @@ func findTextureDescriptors(entityId EntityId, cmd *Commands, assets *AssetServe
 			}
 		}
 	}
-	return descriptors
+	return descriptors, errors.Join(errs...)
 }
 
 func tryFindSamplers(cmd *Commands, entityId EntityId) (res []struct {
@@ -205,12 +208,7 @@ func findVoxelModelAsset(entityId EntityId, cmd *Commands, server *AssetServer)
 				if field.Type != assetIdType {
 					panic("Voxel field must be type of AssetId")
 				}
-				fieldVal := val.Field(i)
-				u, err := uuid.Parse(fieldVal.String())
-				if err != nil {
-					panic(err)
-				}
-				assetId := AssetId{UUID: u}
+				assetId := val.Field(i).Interface().(AssetId)
 				if "model" == field.Tag.Get("usage") {
 					model, ok := server.voxModels[assetId]
 					if ok {
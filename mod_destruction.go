@@ -1,11 +1,23 @@
 package gekko
 
-import "github.com/go-gl/mathgl/mgl32"
+import (
+	"github.com/gekko3d/gekko/voxelrt/rt/volume"
+	"github.com/go-gl/mathgl/mgl32"
+)
 
 type DestructionEvent struct {
 	Entity EntityId
 	Center mgl32.Vec3 // World-space center of destruction
 	Radius float32    // Destruction radius in world units
+	// Brush optionally replaces the sphere with a world-space SDF (craters,
+	// trenches, shaped charges). Center and Radius are ignored when set.
+	Brush volume.SDF
+	// Decal optionally stamps a scorch mark through the DecalPool after the
+	// carve, projected against DecalNormal (zero faces up). It is centered
+	// on Center, or on the Brush bounds, and a zero Size covers the carved
+	// area.
+	Decal       *DecalComponent
+	DecalNormal mgl32.Vec3
 }
 
 type DestructionQueue struct {
@@ -51,8 +63,13 @@ func processDestructionEvent(state *VoxelRtState, event DestructionEvent, cmd *C
 	if err != nil || editableMap == nil {
 		return
 	}
-	voxelSphereEditWithTransform(editableMap, voxObj.Transform, event.Center, event.Radius, 0)
+	if event.Brush != nil {
+		voxelBrushEditWithTransform(editableMap, voxObj.Transform, volume.BrushEdit{Shape: event.Brush, Operation: volume.BrushSubtract})
+	} else {
+		voxelSphereEditWithTransform(editableMap, voxObj.Transform, event.Center, event.Radius, 0)
+	}
 	MarkVoxelEntityPersistenceDirty(cmd, event.Entity)
+	spawnDestructionDecal(decalPoolFromApp(cmd.app), event)
 
 	// 2. Detect disconnected components
 	components := editableMap.SplitDisconnectedComponents()
@@ -322,6 +322,48 @@ func TestVoxelSphereEdit_Carve(t *testing.T) {
 	}
 }
 
+func TestVoxelBrushEditCarvesWorldSpaceCapsule(t *testing.T) {
+	state := &VoxelRtState{
+		instanceMap:    make(map[EntityId]*core.VoxelObject),
+		objectToEntity: make(map[*core.VoxelObject]EntityId),
+	}
+	xbm := volume.NewXBrickMap()
+	volume.Cube(xbm, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{19, 9, 9}, 1)
+
+	entity := EntityId(1)
+	obj := core.NewVoxelObject()
+	obj.XBrickMap = xbm
+	obj.Transform.Position = mgl32.Vec3{10, 10, 10}
+	obj.Transform.Scale = mgl32.Vec3{VoxelSize, VoxelSize, VoxelSize}
+	obj.Transform.Rotation = mgl32.QuatIdent()
+	state.instanceMap[entity] = obj
+
+	// A trench from local voxel x=2..12 at y=z=5, radius 2 voxels.
+	result := state.VoxelBrushEdit(entity, volume.BrushEdit{
+		Shape: volume.SDFCapsule{
+			A:      mgl32.Vec3{10.25, 10.55, 10.55},
+			B:      mgl32.Vec3{11.25, 10.55, 10.55},
+			Radius: 0.2,
+		},
+		Operation: volume.BrushSubtract,
+	})
+
+	if result.Changed == 0 {
+		t.Fatal("expected brush to carve voxels")
+	}
+	for _, x := range []int{3, 7, 11} {
+		if found, _ := xbm.GetVoxel(x, 5, 5); found {
+			t.Fatalf("expected voxel (%d,5,5) inside the trench to be carved", x)
+		}
+	}
+	if found, _ := xbm.GetVoxel(17, 5, 5); !found {
+		t.Fatal("expected voxel past the trench end to remain")
+	}
+	if !state.runtimeEditedVoxelEntity(entity) {
+		t.Fatal("expected brush edit to mark the entity as runtime edited")
+	}
+}
+
 func TestVoxelRtSystem_MapSync(t *testing.T) {
 	// This test verifies syncing geometry override changes from ECS to internal state.
 	app := NewApp()
@@ -29,38 +29,16 @@ type FlyingCameraComponent struct {
 }
 
 func FlyingCameraInputSystem(input *Input, cmd *Commands) {
-	if input.JustPressed[KeyTab] {
+	actions := gameplayInputActions(cmd.app, input)
+	if actions.JustPressed(ActionToggleMouseCapture) {
 		input.MouseCaptured = !input.MouseCaptured
 	}
 
 	MakeQuery1[FlyingCameraComponent](cmd).Map(func(eid EntityId, fly *FlyingCameraComponent) bool {
-		fly.Move = mgl32.Vec3{0, 0, 0}
-		if input.Pressed[KeyW] {
-			fly.Move[2] += 1
-		}
-		if input.Pressed[KeyS] {
-			fly.Move[2] -= 1
-		}
-		if input.Pressed[KeyA] {
-			fly.Move[0] -= 1
-		}
-		if input.Pressed[KeyD] {
-			fly.Move[0] += 1
-		}
-		if input.Pressed[KeySpace] {
-			fly.Move[1] += 1
-		}
-		if input.Pressed[KeyControl] {
-			fly.Move[1] -= 1
-		}
+		move := actions.Axis2D(ActionMove)
+		fly.Move = mgl32.Vec3{move.X(), actions.Axis(ActionFlyVertical), move.Y()}
 
-		if input.MouseCaptured {
-			fly.Look[0] = float32(input.MouseDeltaX)
-			fly.Look[1] = float32(input.MouseDeltaY)
-		} else {
-			fly.Look[0] = 0
-			fly.Look[1] = 0
-		}
+		fly.Look = actions.Axis2D(ActionLook)
 
 		return true
 	})
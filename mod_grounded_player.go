@@ -180,29 +180,16 @@ func groundedPlayerInputSystem(input *Input, cmd *Commands) {
 	if input == nil {
 		return
 	}
-	if input.JustPressed[KeyTab] {
+	actions := gameplayInputActions(cmd.app, input)
+	if actions.JustPressed(ActionToggleMouseCapture) {
 		input.MouseCaptured = !input.MouseCaptured
 	}
 	MakeQuery1[GroundedPlayerControllerComponent](cmd).Map(func(_ EntityId, ctrl *GroundedPlayerControllerComponent) bool {
-		ctrl.MoveInput = mgl32.Vec2{}
-		if input.Pressed[KeyA] {
-			ctrl.MoveInput[0] -= 1
-		}
-		if input.Pressed[KeyD] {
-			ctrl.MoveInput[0] += 1
-		}
-		if input.Pressed[KeyW] {
-			ctrl.MoveInput[1] += 1
-		}
-		if input.Pressed[KeyS] {
-			ctrl.MoveInput[1] -= 1
-		}
-		ctrl.LookInput = mgl32.Vec2{}
-		if input.MouseCaptured {
-			ctrl.LookInput[0] = float32(input.MouseDeltaX)
-			ctrl.LookInput[1] = float32(input.MouseDeltaY)
-		}
-		ctrl.JumpQueued = input.JustPressed[KeySpace]
+		ctrl.MoveInput = actions.Axis2D(ActionMove)
+		// Mouse delta is already zero while the cursor is free, so the
+		// right stick can look around without capturing the mouse.
+		ctrl.LookInput = actions.Axis2D(ActionLook)
+		ctrl.JumpQueued = actions.JustPressed(ActionJump)
 		return true
 	})
 }
@@ -215,6 +202,7 @@ func groundedPlayerControlSystem(cmd *Commands, time *Time, input *Input, voxRt
 	if dt <= 0 {
 		return
 	}
+	sprinting := input != nil && gameplayInputActions(cmd.app, input).Pressed(ActionSprint)
 	MakeQuery2[CameraComponent, GroundedPlayerControllerComponent](cmd).Map(func(eid EntityId, cam *CameraComponent, ctrl *GroundedPlayerControllerComponent) bool {
 		applyGroundedLook(cam, ctrl)
 		basePos := cam.Position.Sub(mgl32.Vec3{0, maxf(ctrl.EyeHeight, 0.01), 0})
@@ -222,7 +210,7 @@ func groundedPlayerControlSystem(cmd *Commands, time *Time, input *Input, voxRt
 		flatForward := forwardFromYawPitch(cam.Yaw, 0)
 		right := flatForward.Cross(mgl32.Vec3{0, 1, 0}).Normalize()
 		speed := defaulted(ctrl.Speed, 5.5)
-		if input != nil && input.Pressed[KeyShift] {
+		if sprinting {
 			speed *= defaulted(ctrl.SprintMultiplier, 1.6)
 		}
 
@@ -264,7 +252,7 @@ func groundedPlayerControlSystem(cmd *Commands, time *Time, input *Input, voxRt
 }
 
 func groundedPlayerUseSystem(cmd *Commands, input *Input) {
-	if cmd == nil || input == nil || !input.JustPressed[KeyE] {
+	if cmd == nil || input == nil || !gameplayInputActions(cmd.app, input).JustPressed(ActionUse) {
 		return
 	}
 	MakeQuery2[CameraComponent, GroundedPlayerControllerComponent](cmd).Map(func(_ EntityId, cam *CameraComponent, _ *GroundedPlayerControllerComponent) bool {
@@ -100,6 +100,12 @@ type Input struct {
 	WindowWidth, WindowHeight int
 	CharBuffer                []rune
 	ClipboardText             string
+
+	Gamepads      [MaxGamepads]GamepadState
+	GamepadEvents []GamepadEvent
+	// GamepadBackend overrides GLFW joystick polling, e.g. with a
+	// SyntheticGamepadBackend.
+	GamepadBackend GamepadBackend
 }
 
 func (mod InputModule) Install(app *App, cmd *Commands) {
@@ -129,6 +135,7 @@ func inputSystem(s *WindowState, input *Input) {
 	})
 
 	glfw.PollEvents()
+	pollGamepadInput(input)
 
 	// Update Keyboard
 	for key, glfwKey := range keyToGlfw {
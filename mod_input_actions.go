@@ -0,0 +1,831 @@
+package gekko
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type InputActionKind string
+
+const (
+	InputActionButton InputActionKind = "button"
+	InputActionAxis1D InputActionKind = "axis_1d"
+	InputActionAxis2D InputActionKind = "axis_2d"
+)
+
+type InputSource string
+
+const (
+	// InputSourceKey reads a keyboard key or mouse button index from the Input
+	// resource.
+	InputSourceKey           InputSource = "key"
+	InputSourceMouseDeltaX   InputSource = "mouse_delta_x"
+	InputSourceMouseDeltaY   InputSource = "mouse_delta_y"
+	InputSourceScrollX       InputSource = "scroll_x"
+	InputSourceScrollY       InputSource = "scroll_y"
+	InputSourceGamepadButton InputSource = "gamepad_button"
+	InputSourceGamepadAxis   InputSource = "gamepad_axis"
+)
+
+// AnyGamepad makes a gamepad binding read every connected pad.
+const AnyGamepad = -1
+
+// gamepadLookScale converts a fully deflected stick into per-frame look
+// units comparable to mouse delta pixels.
+const gamepadLookScale = 12
+
+// Input contexts used by the built-in controllers. Games may add their own.
+const (
+	InputContextGameplay = "gameplay"
+	InputContextMenu     = "menu"
+	InputContextVehicle  = "vehicle"
+)
+
+// Actions in DefaultGameplayInputActions.
+const (
+	ActionMove               = "move"
+	ActionLook               = "look"
+	ActionJump               = "jump"
+	ActionSprint             = "sprint"
+	ActionUse                = "use"
+	ActionFlyVertical        = "fly_vertical"
+	ActionToggleMouseCapture = "toggle_mouse_capture"
+)
+
+// inputActionPressThreshold is the value magnitude at which a button action
+// counts as pressed.
+const inputActionPressThreshold = 0.5
+
+// InputBinding maps one input source onto an action. Key and gamepad button
+// bindings contribute Scale while held (keys also need every modifier); mouse
+// delta, scroll and gamepad axes contribute their raw value times Scale.
+type InputBinding struct {
+	Source    InputSource
+	Key       int
+	Modifiers []int
+	Scale     float32 // zero means 1
+	// Axis is the component of a 2D action this binding drives: 0 for X, 1
+	// for Y.
+	Axis int
+
+	// Gamepad is the pad slot gamepad sources read, or AnyGamepad.
+	Gamepad       int
+	GamepadButton GamepadButton
+	GamepadAxis   GamepadAxis
+	// DeadZone zeroes raw gamepad axis values whose magnitude is below it,
+	// before Scale.
+	DeadZone float32
+}
+
+func KeyBinding(key int, modifiers ...int) InputBinding {
+	return InputBinding{Source: InputSourceKey, Key: key, Modifiers: modifiers}
+}
+
+// KeyAxisBinding drives one component of an axis action with a key.
+func KeyAxisBinding(key int, axis int, scale float32) InputBinding {
+	return InputBinding{Source: InputSourceKey, Key: key, Axis: axis, Scale: scale}
+}
+
+func GamepadButtonBinding(button GamepadButton) InputBinding {
+	return InputBinding{Source: InputSourceGamepadButton, Gamepad: AnyGamepad, GamepadButton: button}
+}
+
+// GamepadAxisBinding drives one component of an axis action with a stick or
+// trigger on any pad.
+func GamepadAxisBinding(stick GamepadAxis, axis int, scale float32, deadZone float32) InputBinding {
+	return InputBinding{Source: InputSourceGamepadAxis, Gamepad: AnyGamepad, GamepadAxis: stick, Axis: axis, Scale: scale, DeadZone: deadZone}
+}
+
+func (b InputBinding) scale() float32 {
+	if b.Scale == 0 {
+		return 1
+	}
+	return b.Scale
+}
+
+// values returns the binding's contribution this frame and last frame. Only
+// key state persists across frames; mouse delta and scroll are impulses.
+func (b InputBinding) values(input *Input) (float32, float32) {
+	switch b.Source {
+	case InputSourceKey:
+		now, before := inputKeyDown(input, b.Key)
+		for _, modifier := range b.Modifiers {
+			modNow, modBefore := inputKeyDown(input, modifier)
+			now = now && modNow
+			before = before && modBefore
+		}
+		var current, previous float32
+		if now {
+			current = b.scale()
+		}
+		if before {
+			previous = b.scale()
+		}
+		return current, previous
+	case InputSourceMouseDeltaX:
+		return float32(input.MouseDeltaX) * b.scale(), 0
+	case InputSourceMouseDeltaY:
+		return float32(input.MouseDeltaY) * b.scale(), 0
+	case InputSourceScrollX:
+		return float32(input.MouseScrollX) * b.scale(), 0
+	case InputSourceScrollY:
+		return float32(input.MouseScrollY) * b.scale(), 0
+	case InputSourceGamepadButton:
+		var current, previous float32
+		b.eachGamepad(input, func(pad *GamepadState) {
+			if b.GamepadButton < 0 || b.GamepadButton >= GamepadButtonCount {
+				return
+			}
+			if pad.Pressed[b.GamepadButton] {
+				current = b.scale()
+			}
+			if (pad.Pressed[b.GamepadButton] && !pad.JustPressed[b.GamepadButton]) || pad.JustReleased[b.GamepadButton] {
+				previous = b.scale()
+			}
+		})
+		return current, previous
+	case InputSourceGamepadAxis:
+		var current, previous float32
+		b.eachGamepad(input, func(pad *GamepadState) {
+			if b.GamepadAxis < 0 || b.GamepadAxis >= GamepadAxisCount {
+				return
+			}
+			current = strongerAxis(current, b.axisValue(pad.Axes[b.GamepadAxis]))
+			previous = strongerAxis(previous, b.axisValue(pad.PreviousAxes[b.GamepadAxis]))
+		})
+		return current, previous
+	}
+	return 0, 0
+}
+
+// eachGamepad visits the binding's pad, or every pad for AnyGamepad.
+// Disconnected pads are visited too so their release edge is seen.
+func (b InputBinding) eachGamepad(input *Input, fn func(pad *GamepadState)) {
+	if b.Gamepad == AnyGamepad {
+		for i := range input.Gamepads {
+			fn(&input.Gamepads[i])
+		}
+		return
+	}
+	if pad := input.Gamepad(b.Gamepad); pad != nil {
+		fn(pad)
+	}
+}
+
+func (b InputBinding) axisValue(raw float32) float32 {
+	if b.DeadZone > 0 && raw < b.DeadZone && raw > -b.DeadZone {
+		return 0
+	}
+	return raw * b.scale()
+}
+
+// strongerAxis keeps whichever pad is deflected further when several pads
+// feed one binding.
+func strongerAxis(a, b float32) float32 {
+	if b*b > a*a {
+		return b
+	}
+	return a
+}
+
+// inputKeyDown reports whether key is held this frame and whether it was held
+// last frame, derived from the Input resource's edge flags.
+func inputKeyDown(input *Input, key int) (bool, bool) {
+	if key < 0 || key >= len(input.Pressed) {
+		return false, false
+	}
+	now := input.Pressed[key] || input.JustPressed[key]
+	before := (input.Pressed[key] && !input.JustPressed[key]) || input.JustReleased[key]
+	return now, before
+}
+
+type InputAction struct {
+	Name     string
+	Kind     InputActionKind
+	Bindings []InputBinding
+	// DeadZone zeroes values whose magnitude is below it.
+	DeadZone float32
+}
+
+func (a InputAction) evaluate(input *Input) InputActionState {
+	var current, previous mgl32.Vec2
+	for _, binding := range a.Bindings {
+		now, before := binding.values(input)
+		axis := 0
+		if a.Kind == InputActionAxis2D && binding.Axis == 1 {
+			axis = 1
+		}
+		current[axis] += now
+		previous[axis] += before
+	}
+	current = applyInputDeadZone(current, a.DeadZone)
+	previous = applyInputDeadZone(previous, a.DeadZone)
+	pressed, wasPressed := a.pressed(current), a.pressed(previous)
+	return InputActionState{
+		Value:        current,
+		Pressed:      pressed,
+		JustPressed:  pressed && !wasPressed,
+		JustReleased: !pressed && wasPressed,
+	}
+}
+
+func (a InputAction) pressed(value mgl32.Vec2) bool {
+	if a.Kind == InputActionButton {
+		return value.Len() >= inputActionPressThreshold
+	}
+	return value.Len() > 0
+}
+
+func applyInputDeadZone(value mgl32.Vec2, deadZone float32) mgl32.Vec2 {
+	if deadZone > 0 && value.Len() < deadZone {
+		return mgl32.Vec2{}
+	}
+	return value
+}
+
+// InputActionMap is the set of actions for one context such as gameplay, menu
+// or vehicle. Global maps stay active whatever the current context.
+type InputActionMap struct {
+	Name    string
+	Global  bool
+	Actions []InputAction
+}
+
+func (m *InputActionMap) Action(name string) *InputAction {
+	if m == nil {
+		return nil
+	}
+	for i := range m.Actions {
+		if m.Actions[i].Name == name {
+			return &m.Actions[i]
+		}
+	}
+	return nil
+}
+
+type InputActionState struct {
+	Value        mgl32.Vec2
+	Pressed      bool
+	JustPressed  bool
+	JustReleased bool
+}
+
+// InputActions resolves named actions from the Input resource through
+// per-context action maps. Global maps are evaluated first, so the current
+// context's actions win on name clashes.
+type InputActions struct {
+	Maps    []InputActionMap
+	Context string
+
+	states   map[string]InputActionState
+	defaults map[string][]InputAction
+	rebind   *inputRebind
+}
+
+type inputRebind struct {
+	mapName string
+	action  string
+	binding int
+}
+
+func NewInputActions(maps ...InputActionMap) *InputActions {
+	actions := &InputActions{}
+	for _, m := range maps {
+		actions.AddMap(m)
+	}
+	if len(actions.Maps) > 0 {
+		actions.Context = actions.Maps[0].Name
+	}
+	return actions
+}
+
+// AddMap adds or replaces the map with the same name. Its bindings become the
+// defaults ResetBindings restores.
+func (a *InputActions) AddMap(m InputActionMap) {
+	if a.defaults == nil {
+		a.defaults = map[string][]InputAction{}
+	}
+	a.defaults[m.Name] = cloneInputActions(m.Actions)
+	m.Actions = cloneInputActions(m.Actions)
+	for i := range a.Maps {
+		if a.Maps[i].Name == m.Name {
+			a.Maps[i] = m
+			return
+		}
+	}
+	a.Maps = append(a.Maps, m)
+}
+
+func (a *InputActions) Map(name string) *InputActionMap {
+	if a == nil {
+		return nil
+	}
+	for i := range a.Maps {
+		if a.Maps[i].Name == name {
+			return &a.Maps[i]
+		}
+	}
+	return nil
+}
+
+// SetContext switches which non-global map is active.
+func (a *InputActions) SetContext(name string) {
+	a.Context = name
+}
+
+// Update evaluates the active maps against input. While a rebind is pending,
+// input is captured for it and every action reads as idle.
+func (a *InputActions) Update(input *Input) {
+	a.states = map[string]InputActionState{}
+	if input == nil {
+		return
+	}
+	if a.rebind != nil {
+		a.captureRebind(input)
+		return
+	}
+	for _, global := range []bool{true, false} {
+		for _, m := range a.Maps {
+			if m.Global != global || (!global && m.Name != a.Context) {
+				continue
+			}
+			for _, action := range m.Actions {
+				a.states[action.Name] = action.evaluate(input)
+			}
+		}
+	}
+}
+
+func (a *InputActions) State(name string) InputActionState {
+	if a == nil {
+		return InputActionState{}
+	}
+	return a.states[name]
+}
+
+func (a *InputActions) Pressed(name string) bool      { return a.State(name).Pressed }
+func (a *InputActions) JustPressed(name string) bool  { return a.State(name).JustPressed }
+func (a *InputActions) JustReleased(name string) bool { return a.State(name).JustReleased }
+func (a *InputActions) Axis(name string) float32      { return a.State(name).Value.X() }
+func (a *InputActions) Axis2D(name string) mgl32.Vec2 { return a.State(name).Value }
+
+// BeginRebind replaces binding index of the named action with the next key,
+// mouse button or scroll the player uses. Escape cancels. An index equal to
+// the binding count appends a new binding.
+func (a *InputActions) BeginRebind(mapName, action string, binding int) error {
+	target := a.Map(mapName).Action(action)
+	if target == nil {
+		return fmt.Errorf("input action %s/%s not found", mapName, action)
+	}
+	if binding < 0 || binding > len(target.Bindings) {
+		return fmt.Errorf("input action %s/%s has no binding %d", mapName, action, binding)
+	}
+	a.rebind = &inputRebind{mapName: mapName, action: action, binding: binding}
+	return nil
+}
+
+func (a *InputActions) Rebinding() bool {
+	return a != nil && a.rebind != nil
+}
+
+func (a *InputActions) CancelRebind() {
+	a.rebind = nil
+}
+
+var inputModifierKeys = []int{KeyShift, KeyControl, KeySuper, KeyLeftAlt}
+
+func isInputModifierKey(key int) bool {
+	for _, modifier := range inputModifierKeys {
+		if key == modifier {
+			return true
+		}
+	}
+	return false
+}
+
+// captureRebind binds the first non-modifier key pressed this frame together
+// with the modifiers held. A modifier alone binds when it is released without
+// another key.
+func (a *InputActions) captureRebind(input *Input) {
+	if input.JustPressed[KeyEscape] {
+		a.rebind = nil
+		return
+	}
+	var captured *InputBinding
+	for key := 0; key <= MouseButtonMiddle; key++ {
+		if !input.JustPressed[key] || isInputModifierKey(key) {
+			continue
+		}
+		binding := KeyBinding(key)
+		for _, modifier := range inputModifierKeys {
+			if input.Pressed[modifier] {
+				binding.Modifiers = append(binding.Modifiers, modifier)
+			}
+		}
+		captured = &binding
+		break
+	}
+	if captured == nil {
+		for _, modifier := range inputModifierKeys {
+			if input.JustReleased[modifier] {
+				binding := KeyBinding(modifier)
+				captured = &binding
+				break
+			}
+		}
+	}
+	if captured == nil && input.MouseScrollY != 0 {
+		captured = &InputBinding{Source: InputSourceScrollY, Scale: inputScrollSign(input.MouseScrollY)}
+	}
+	if captured == nil {
+		captured = captureGamepadRebind(input)
+	}
+	if captured == nil {
+		return
+	}
+
+	target := a.Map(a.rebind.mapName).Action(a.rebind.action)
+	index := a.rebind.binding
+	a.rebind = nil
+	if target == nil {
+		return
+	}
+	if index < len(target.Bindings) {
+		// Keep which axis and direction the slot drives.
+		previous := target.Bindings[index]
+		captured.Axis = previous.Axis
+		captured.Scale = previous.scale() * captured.scale()
+		target.Bindings[index] = *captured
+		return
+	}
+	target.Bindings = append(target.Bindings, *captured)
+}
+
+// captureGamepadRebind binds the first pad button pressed this frame, or the
+// first stick or trigger pushed past half deflection.
+func captureGamepadRebind(input *Input) *InputBinding {
+	for slot := range input.Gamepads {
+		pad := &input.Gamepads[slot]
+		for button := GamepadButton(0); button < GamepadButtonCount; button++ {
+			if pad.JustPressed[button] {
+				binding := GamepadButtonBinding(button)
+				return &binding
+			}
+		}
+		for axis := GamepadAxis(0); axis < GamepadAxisCount; axis++ {
+			now, before := pad.Axes[axis], pad.PreviousAxes[axis]
+			if now*now >= 0.25 && before*before < 0.25 {
+				binding := GamepadAxisBinding(axis, 0, inputScrollSign(float64(now)), 0.15)
+				return &binding
+			}
+		}
+	}
+	return nil
+}
+
+func inputScrollSign(v float64) float32 {
+	if v < 0 {
+		return -1
+	}
+	return 1
+}
+
+// ResetBindings restores the bindings the named map was added with.
+func (a *InputActions) ResetBindings(mapName string) {
+	if m := a.Map(mapName); m != nil {
+		m.Actions = cloneInputActions(a.defaults[mapName])
+	}
+}
+
+func cloneInputActions(actions []InputAction) []InputAction {
+	out := make([]InputAction, len(actions))
+	for i, action := range actions {
+		out[i] = action
+		out[i].Bindings = make([]InputBinding, len(action.Bindings))
+		for j, binding := range action.Bindings {
+			binding.Modifiers = append([]int(nil), binding.Modifiers...)
+			out[i].Bindings[j] = binding
+		}
+	}
+	return out
+}
+
+const inputBindingsSchemaVersion = 1
+
+type inputBindingsFile struct {
+	SchemaVersion int                   `json:"schema_version"`
+	Maps          []inputBindingsMapDef `json:"maps"`
+}
+
+type inputBindingsMapDef struct {
+	Name    string                   `json:"name"`
+	Actions []inputBindingsActionDef `json:"actions"`
+}
+
+type inputBindingsActionDef struct {
+	Name     string            `json:"name"`
+	DeadZone float32           `json:"dead_zone,omitempty"`
+	Bindings []inputBindingDef `json:"bindings"`
+}
+
+// inputBindingDef stores keys by name so saved bindings survive changes to
+// the key constant order.
+type inputBindingDef struct {
+	Source      InputSource `json:"source"`
+	Key         string      `json:"key,omitempty"`
+	Modifiers   []string    `json:"modifiers,omitempty"`
+	Scale       float32     `json:"scale,omitempty"`
+	Axis        int         `json:"axis,omitempty"`
+	Gamepad     int         `json:"gamepad,omitempty"`
+	Button      string      `json:"button,omitempty"`
+	GamepadAxis string      `json:"gamepad_axis,omitempty"`
+	DeadZone    float32     `json:"dead_zone,omitempty"`
+}
+
+// MarshalBindings encodes every map's bindings and dead zones. Action kinds
+// stay owned by game code.
+func (a *InputActions) MarshalBindings() ([]byte, error) {
+	file := inputBindingsFile{SchemaVersion: inputBindingsSchemaVersion}
+	for _, m := range a.Maps {
+		mapDef := inputBindingsMapDef{Name: m.Name}
+		for _, action := range m.Actions {
+			actionDef := inputBindingsActionDef{Name: action.Name, DeadZone: action.DeadZone, Bindings: []inputBindingDef{}}
+			for _, binding := range action.Bindings {
+				def := inputBindingDef{Source: binding.Source, Scale: binding.Scale, Axis: binding.Axis}
+				switch binding.Source {
+				case InputSourceKey:
+					def.Key = InputKeyName(binding.Key)
+					for _, modifier := range binding.Modifiers {
+						def.Modifiers = append(def.Modifiers, InputKeyName(modifier))
+					}
+				case InputSourceGamepadButton:
+					def.Gamepad = binding.Gamepad
+					def.Button = gamepadButtonNames[binding.GamepadButton]
+				case InputSourceGamepadAxis:
+					def.Gamepad = binding.Gamepad
+					def.GamepadAxis = gamepadAxisNames[binding.GamepadAxis]
+					def.DeadZone = binding.DeadZone
+				}
+				actionDef.Bindings = append(actionDef.Bindings, def)
+			}
+			mapDef.Actions = append(mapDef.Actions, actionDef)
+		}
+		file.Maps = append(file.Maps, mapDef)
+	}
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// UnmarshalBindings applies saved bindings to the maps and actions that
+// already exist. Unknown maps and actions are ignored so old files keep
+// loading after actions are renamed or removed.
+func (a *InputActions) UnmarshalBindings(data []byte) error {
+	var file inputBindingsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+	if file.SchemaVersion != inputBindingsSchemaVersion {
+		return fmt.Errorf("unsupported input bindings schema version %d", file.SchemaVersion)
+	}
+	type pending struct {
+		action   *InputAction
+		deadZone float32
+		bindings []InputBinding
+	}
+	var updates []pending
+	for _, mapDef := range file.Maps {
+		m := a.Map(mapDef.Name)
+		for _, actionDef := range mapDef.Actions {
+			action := m.Action(actionDef.Name)
+			if action == nil {
+				continue
+			}
+			bindings := make([]InputBinding, 0, len(actionDef.Bindings))
+			for _, def := range actionDef.Bindings {
+				binding := InputBinding{Source: def.Source, Scale: def.Scale, Axis: def.Axis, Gamepad: def.Gamepad, DeadZone: def.DeadZone}
+				switch def.Source {
+				case InputSourceGamepadButton:
+					button, ok := gamepadButtonByName(def.Button)
+					if !ok {
+						return fmt.Errorf("input action %s/%s: unknown gamepad button %q", mapDef.Name, actionDef.Name, def.Button)
+					}
+					binding.GamepadButton = button
+				case InputSourceGamepadAxis:
+					axis, ok := gamepadAxisByName(def.GamepadAxis)
+					if !ok {
+						return fmt.Errorf("input action %s/%s: unknown gamepad axis %q", mapDef.Name, actionDef.Name, def.GamepadAxis)
+					}
+					binding.GamepadAxis = axis
+				case InputSourceKey:
+					key, ok := InputKeyByName(def.Key)
+					if !ok {
+						return fmt.Errorf("input action %s/%s: unknown key %q", mapDef.Name, actionDef.Name, def.Key)
+					}
+					binding.Key = key
+					for _, name := range def.Modifiers {
+						modifier, ok := InputKeyByName(name)
+						if !ok {
+							return fmt.Errorf("input action %s/%s: unknown modifier %q", mapDef.Name, actionDef.Name, name)
+						}
+						binding.Modifiers = append(binding.Modifiers, modifier)
+					}
+				}
+				bindings = append(bindings, binding)
+			}
+			updates = append(updates, pending{action: action, deadZone: actionDef.DeadZone, bindings: bindings})
+		}
+	}
+	for _, update := range updates {
+		update.action.DeadZone = update.deadZone
+		update.action.Bindings = update.bindings
+	}
+	return nil
+}
+
+func (a *InputActions) SaveBindings(path string) error {
+	data, err := a.MarshalBindings()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (a *InputActions) LoadBindings(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return a.UnmarshalBindings(data)
+}
+
+// DefaultGameplayInputActions is the WASD, mouse-look and gamepad map the
+// built-in grounded player and flying camera controllers read.
+func DefaultGameplayInputActions() InputActionMap {
+	return InputActionMap{
+		Name: InputContextGameplay,
+		Actions: []InputAction{
+			{Name: ActionMove, Kind: InputActionAxis2D, Bindings: []InputBinding{
+				KeyAxisBinding(KeyD, 0, 1),
+				KeyAxisBinding(KeyA, 0, -1),
+				KeyAxisBinding(KeyW, 1, 1),
+				KeyAxisBinding(KeyS, 1, -1),
+				GamepadAxisBinding(GamepadAxisLeftX, 0, 1, 0.2),
+				GamepadAxisBinding(GamepadAxisLeftY, 1, -1, 0.2),
+			}},
+			{Name: ActionLook, Kind: InputActionAxis2D, Bindings: []InputBinding{
+				{Source: InputSourceMouseDeltaX, Axis: 0},
+				{Source: InputSourceMouseDeltaY, Axis: 1},
+				GamepadAxisBinding(GamepadAxisRightX, 0, gamepadLookScale, 0.15),
+				GamepadAxisBinding(GamepadAxisRightY, 1, gamepadLookScale, 0.15),
+			}},
+			{Name: ActionJump, Kind: InputActionButton, Bindings: []InputBinding{KeyBinding(KeySpace), GamepadButtonBinding(GamepadButtonA)}},
+			{Name: ActionSprint, Kind: InputActionButton, Bindings: []InputBinding{KeyBinding(KeyShift), GamepadButtonBinding(GamepadButtonLeftThumb)}},
+			{Name: ActionUse, Kind: InputActionButton, Bindings: []InputBinding{KeyBinding(KeyE), GamepadButtonBinding(GamepadButtonX)}},
+			{Name: ActionFlyVertical, Kind: InputActionAxis1D, Bindings: []InputBinding{
+				KeyAxisBinding(KeySpace, 0, 1),
+				KeyAxisBinding(KeyControl, 0, -1),
+				GamepadAxisBinding(GamepadAxisRightTrigger, 0, 1, 0.1),
+				GamepadAxisBinding(GamepadAxisLeftTrigger, 0, -1, 0.1),
+			}},
+			{Name: ActionToggleMouseCapture, Kind: InputActionButton, Bindings: []InputBinding{KeyBinding(KeyTab)}},
+		},
+	}
+}
+
+// InputActionsModule evaluates action maps each frame. Install it after
+// InputModule so actions see the current frame's input.
+type InputActionsModule struct {
+	// Maps defaults to DefaultGameplayInputActions.
+	Maps []InputActionMap
+	// Context defaults to the first map.
+	Context string
+	// BindingsPath, when set, loads saved bindings at install if the file
+	// exists.
+	BindingsPath string
+}
+
+func (mod InputActionsModule) Install(app *App, cmd *Commands) {
+	maps := mod.Maps
+	if len(maps) == 0 {
+		maps = []InputActionMap{DefaultGameplayInputActions()}
+	}
+	actions := NewInputActions(maps...)
+	if mod.Context != "" {
+		actions.Context = mod.Context
+	}
+	if mod.BindingsPath != "" {
+		if err := actions.LoadBindings(mod.BindingsPath); err != nil && !os.IsNotExist(err) {
+			fmt.Printf("WARNING: failed to load input bindings %s: %v\n", mod.BindingsPath, err)
+		}
+	}
+	cmd.AddResources(actions)
+	app.UseSystem(
+		System(inputActionsSystem).
+			InStage(PreUpdate).
+			RunAlways(),
+	)
+}
+
+func inputActionsSystem(input *Input, actions *InputActions) {
+	actions.Update(input)
+}
+
+func inputActionsFromApp(app *App) *InputActions {
+	if app == nil {
+		return nil
+	}
+	if resource, ok := app.resources[reflect.TypeOf(InputActions{})]; ok {
+		if actions, ok := resource.(*InputActions); ok {
+			return actions
+		}
+	}
+	return nil
+}
+
+// gameplayInputActions returns the app's actions when they define a gameplay
+// map, otherwise the default gameplay map evaluated against input.
+func gameplayInputActions(app *App, input *Input) *InputActions {
+	if actions := inputActionsFromApp(app); actions != nil && actions.Map(InputContextGameplay) != nil {
+		return actions
+	}
+	actions := NewInputActions(DefaultGameplayInputActions())
+	actions.Update(input)
+	return actions
+}
+
+var inputKeyNames = map[int]string{
+	KeyA: "A", KeyB: "B", KeyC: "C", KeyD: "D", KeyE: "E", KeyF: "F", KeyG: "G",
+	KeyH: "H", KeyI: "I", KeyJ: "J", KeyK: "K", KeyL: "L", KeyM: "M", KeyN: "N",
+	KeyO: "O", KeyP: "P", KeyQ: "Q", KeyR: "R", KeyS: "S", KeyT: "T", KeyU: "U",
+	KeyV: "V", KeyW: "W", KeyX: "X", KeyY: "Y", KeyZ: "Z",
+	Key0: "0", Key1: "1", Key2: "2", Key3: "3", Key4: "4",
+	Key5: "5", Key6: "6", Key7: "7", Key8: "8", Key9: "9",
+	KeySpace: "Space", KeyEnter: "Enter", KeyEscape: "Escape", KeyTab: "Tab",
+	KeyBackspace: "Backspace", KeyInsert: "Insert", KeyDelete: "Delete",
+	KeyRight: "Right", KeyLeft: "Left", KeyDown: "Down", KeyUp: "Up",
+	KeyF1: "F1", KeyF2: "F2", KeyF3: "F3", KeyF4: "F4", KeyF5: "F5", KeyF6: "F6",
+	KeyF7: "F7", KeyF8: "F8", KeyF9: "F9", KeyF10: "F10", KeyF11: "F11", KeyF12: "F12",
+	KeyMinus: "Minus", KeyEqual: "Equal", KeyLeftBracket: "LeftBracket",
+	KeyRightBracket: "RightBracket", KeyComma: "Comma", KeyPeriod: "Period",
+	KeyGraveAccent: "GraveAccent", KeyKPPlus: "KeypadPlus", KeyKPMinus: "KeypadMinus",
+	KeyShift: "Shift", KeyControl: "Control", KeySuper: "Super", KeyLeftAlt: "Alt",
+	MouseButtonLeft: "MouseLeft", MouseButtonRight: "MouseRight", MouseButtonMiddle: "MouseMiddle",
+}
+
+var inputKeysByName = func() map[string]int {
+	out := make(map[string]int, len(inputKeyNames))
+	for key, name := range inputKeyNames {
+		out[name] = key
+	}
+	return out
+}()
+
+// InputKeyName returns the stable name used in saved bindings and rebinding
+// UI.
+func InputKeyName(key int) string {
+	if name, ok := inputKeyNames[key]; ok {
+		return name
+	}
+	return fmt.Sprintf("Key%d", key)
+}
+
+func InputKeyByName(name string) (int, bool) {
+	key, ok := inputKeysByName[name]
+	return key, ok
+}
+
+var gamepadButtonNames = map[GamepadButton]string{
+	GamepadButtonA: "A", GamepadButtonB: "B", GamepadButtonX: "X", GamepadButtonY: "Y",
+	GamepadButtonLeftBumper: "LeftBumper", GamepadButtonRightBumper: "RightBumper",
+	GamepadButtonBack: "Back", GamepadButtonStart: "Start", GamepadButtonGuide: "Guide",
+	GamepadButtonLeftThumb: "LeftThumb", GamepadButtonRightThumb: "RightThumb",
+	GamepadButtonDpadUp: "DpadUp", GamepadButtonDpadRight: "DpadRight",
+	GamepadButtonDpadDown: "DpadDown", GamepadButtonDpadLeft: "DpadLeft",
+}
+
+var gamepadAxisNames = map[GamepadAxis]string{
+	GamepadAxisLeftX: "LeftX", GamepadAxisLeftY: "LeftY",
+	GamepadAxisRightX: "RightX", GamepadAxisRightY: "RightY",
+	GamepadAxisLeftTrigger: "LeftTrigger", GamepadAxisRightTrigger: "RightTrigger",
+}
+
+func gamepadButtonByName(name string) (GamepadButton, bool) {
+	for button, buttonName := range gamepadButtonNames {
+		if buttonName == name {
+			return button, true
+		}
+	}
+	return 0, false
+}
+
+func gamepadAxisByName(name string) (GamepadAxis, bool) {
+	for axis, axisName := range gamepadAxisNames {
+		if axisName == name {
+			return axis, true
+		}
+	}
+	return 0, false
+}
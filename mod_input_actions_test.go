@@ -0,0 +1,192 @@
+package gekko
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestInputActionsEvaluateAxesModifiersAndEdges(t *testing.T) {
+	actions := NewInputActions(InputActionMap{
+		Name: InputContextGameplay,
+		Actions: []InputAction{
+			{Name: ActionMove, Kind: InputActionAxis2D, Bindings: []InputBinding{
+				KeyAxisBinding(KeyD, 0, 1),
+				KeyAxisBinding(KeyA, 0, -1),
+				KeyAxisBinding(KeyW, 1, 1),
+			}},
+			{Name: "zoom", Kind: InputActionAxis1D, DeadZone: 0.5, Bindings: []InputBinding{{Source: InputSourceScrollY, Scale: 0.25}}},
+			{Name: "save", Kind: InputActionButton, Bindings: []InputBinding{KeyBinding(KeyS, KeyControl)}},
+		},
+	})
+
+	input := &Input{}
+	input.Pressed[KeyW] = true
+	input.JustPressed[KeyD] = true
+	input.MouseScrollY = 1
+	input.JustPressed[KeyS] = true
+	actions.Update(input)
+	if got := actions.Axis2D(ActionMove); got != (mgl32.Vec2{1, 1}) {
+		t.Fatalf("expected move (1,1), got %v", got)
+	}
+	if actions.JustPressed(ActionMove) {
+		t.Fatal("expected move to stay active rather than restart while W is held")
+	}
+	if actions.Axis("zoom") != 0 {
+		t.Fatalf("expected scroll inside the dead zone to read zero, got %v", actions.Axis("zoom"))
+	}
+	if actions.Pressed("save") {
+		t.Fatal("expected save to require Control")
+	}
+
+	input = &Input{}
+	input.Pressed[KeyControl] = true
+	input.JustPressed[KeyS] = true
+	input.MouseScrollY = 4
+	actions.Update(input)
+	if !actions.JustPressed("save") || actions.Axis("zoom") != 1 {
+		t.Fatalf("expected Control+S and zoom 1, got save=%+v zoom=%v", actions.State("save"), actions.Axis("zoom"))
+	}
+
+	input = &Input{}
+	input.Pressed[KeyControl] = true
+	input.JustReleased[KeyS] = true
+	actions.Update(input)
+	if actions.Pressed("save") || !actions.JustReleased("save") {
+		t.Fatalf("expected save to be just released, got %+v", actions.State("save"))
+	}
+}
+
+func TestInputActionsContextsSelectMaps(t *testing.T) {
+	actions := NewInputActions(
+		DefaultGameplayInputActions(),
+		InputActionMap{Name: InputContextMenu, Actions: []InputAction{{Name: "confirm", Kind: InputActionButton, Bindings: []InputBinding{KeyBinding(KeyEnter)}}}},
+		InputActionMap{Name: "debug", Global: true, Actions: []InputAction{{Name: "console", Kind: InputActionButton, Bindings: []InputBinding{KeyBinding(KeyGraveAccent)}}}},
+	)
+	input := &Input{}
+	input.Pressed[KeyW] = true
+	input.Pressed[KeyEnter] = true
+	input.Pressed[KeyGraveAccent] = true
+
+	actions.Update(input)
+	if actions.Axis2D(ActionMove).Y() != 1 || actions.Pressed("confirm") || !actions.Pressed("console") {
+		t.Fatal("expected gameplay and global actions only")
+	}
+	actions.SetContext(InputContextMenu)
+	actions.Update(input)
+	if actions.Axis2D(ActionMove).Y() != 0 || !actions.Pressed("confirm") || !actions.Pressed("console") {
+		t.Fatal("expected menu and global actions only")
+	}
+}
+
+func TestInputActionsRebindCapturesKeysAndModifiers(t *testing.T) {
+	actions := NewInputActions(DefaultGameplayInputActions())
+	if err := actions.BeginRebind(InputContextGameplay, ActionMove, 2); err != nil {
+		t.Fatalf("BeginRebind failed: %v", err)
+	}
+	input := &Input{}
+	input.JustPressed[KeyUp] = true
+	actions.Update(input)
+	if actions.Rebinding() {
+		t.Fatal("expected the rebind to complete")
+	}
+	if actions.Axis2D(ActionMove) != (mgl32.Vec2{}) {
+		t.Fatal("expected the capturing frame not to drive actions")
+	}
+	forward := actions.Map(InputContextGameplay).Action(ActionMove).Bindings[2]
+	if forward.Key != KeyUp || forward.Axis != 1 || forward.scale() != 1 {
+		t.Fatalf("expected Up to replace W on the forward axis, got %+v", forward)
+	}
+
+	if err := actions.BeginRebind(InputContextGameplay, ActionUse, 0); err != nil {
+		t.Fatalf("BeginRebind failed: %v", err)
+	}
+	input = &Input{}
+	input.Pressed[KeyShift] = true
+	input.JustPressed[KeyF] = true
+	actions.Update(input)
+	use := actions.Map(InputContextGameplay).Action(ActionUse).Bindings[0]
+	if use.Key != KeyF || len(use.Modifiers) != 1 || use.Modifiers[0] != KeyShift {
+		t.Fatalf("expected Shift+F, got %+v", use)
+	}
+
+	if err := actions.BeginRebind(InputContextGameplay, ActionSprint, 0); err != nil {
+		t.Fatalf("BeginRebind failed: %v", err)
+	}
+	input = &Input{}
+	input.JustPressed[KeyLeftAlt] = true
+	actions.Update(input)
+	if !actions.Rebinding() {
+		t.Fatal("expected a held modifier to wait for another key or its release")
+	}
+	input = &Input{}
+	input.JustReleased[KeyLeftAlt] = true
+	actions.Update(input)
+	if sprint := actions.Map(InputContextGameplay).Action(ActionSprint).Bindings[0]; sprint.Key != KeyLeftAlt || len(sprint.Modifiers) != 0 {
+		t.Fatalf("expected Alt alone, got %+v", sprint)
+	}
+
+	actions.ResetBindings(InputContextGameplay)
+	if actions.Map(InputContextGameplay).Action(ActionMove).Bindings[2].Key != KeyW {
+		t.Fatal("expected ResetBindings to restore defaults")
+	}
+}
+
+func TestInputActionsSaveAndLoadBindings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bindings.json")
+	saved := NewInputActions(DefaultGameplayInputActions())
+	move := saved.Map(InputContextGameplay).Action(ActionMove)
+	move.Bindings[2] = KeyAxisBinding(KeyUp, 1, 1)
+	move.DeadZone = 0.2
+	saved.Map(InputContextGameplay).Action(ActionUse).Bindings[0] = KeyBinding(KeyF, KeyControl)
+	if err := saved.SaveBindings(path); err != nil {
+		t.Fatalf("SaveBindings failed: %v", err)
+	}
+
+	loaded := NewInputActions(DefaultGameplayInputActions())
+	if err := loaded.LoadBindings(path); err != nil {
+		t.Fatalf("LoadBindings failed: %v", err)
+	}
+	gotMove := loaded.Map(InputContextGameplay).Action(ActionMove)
+	if gotMove.Bindings[2].Key != KeyUp || gotMove.Bindings[2].Axis != 1 || gotMove.DeadZone != 0.2 {
+		t.Fatalf("unexpected loaded move action %+v", gotMove)
+	}
+	use := loaded.Map(InputContextGameplay).Action(ActionUse).Bindings[0]
+	if use.Key != KeyF || len(use.Modifiers) != 1 || use.Modifiers[0] != KeyControl {
+		t.Fatalf("unexpected loaded use binding %+v", use)
+	}
+
+	if err := loaded.UnmarshalBindings([]byte(`{"schema_version":1,"maps":[{"name":"gameplay","actions":[{"name":"jump","bindings":[{"source":"key","key":"Nope"}]}]}]}`)); err == nil {
+		t.Fatal("expected unknown key names to be rejected")
+	}
+	if loaded.Map(InputContextGameplay).Action(ActionJump).Bindings[0].Key != KeySpace {
+		t.Fatal("expected a rejected file to leave bindings unchanged")
+	}
+}
+
+func TestGroundedPlayerInputReadsReboundActions(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	actions := NewInputActions(DefaultGameplayInputActions())
+	actions.Map(InputContextGameplay).Action(ActionMove).Bindings[2] = KeyAxisBinding(KeyUp, 1, 1)
+	cmd.AddResources(actions)
+	eid := cmd.AddEntity(&GroundedPlayerControllerComponent{})
+	app.FlushCommands()
+
+	input := &Input{}
+	input.Pressed[KeyW] = true
+	input.Pressed[KeyUp] = true
+	actions.Update(input)
+	groundedPlayerInputSystem(input, cmd)
+
+	for _, comp := range cmd.GetAllComponents(eid) {
+		if ctrl, ok := comp.(*GroundedPlayerControllerComponent); ok {
+			if ctrl.MoveInput != (mgl32.Vec2{0, 1}) {
+				t.Fatalf("expected Up to move forward once, got %v", ctrl.MoveInput)
+			}
+			return
+		}
+	}
+	t.Fatal("controller component missing")
+}
@@ -0,0 +1,228 @@
+package gekko
+
+import (
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// MaxGamepads matches GLFW's joystick slot count.
+const MaxGamepads = 16
+
+// GamepadButton follows GLFW's standard gamepad mapping order.
+type GamepadButton int
+
+const (
+	GamepadButtonA GamepadButton = iota
+	GamepadButtonB
+	GamepadButtonX
+	GamepadButtonY
+	GamepadButtonLeftBumper
+	GamepadButtonRightBumper
+	GamepadButtonBack
+	GamepadButtonStart
+	GamepadButtonGuide
+	GamepadButtonLeftThumb
+	GamepadButtonRightThumb
+	GamepadButtonDpadUp
+	GamepadButtonDpadRight
+	GamepadButtonDpadDown
+	GamepadButtonDpadLeft
+	GamepadButtonCount
+)
+
+// GamepadAxis follows GLFW's standard gamepad mapping order. Sticks range
+// -1..1 with +Y pointing down; triggers range 0..1.
+type GamepadAxis int
+
+const (
+	GamepadAxisLeftX GamepadAxis = iota
+	GamepadAxisLeftY
+	GamepadAxisRightX
+	GamepadAxisRightY
+	GamepadAxisLeftTrigger
+	GamepadAxisRightTrigger
+	GamepadAxisCount
+)
+
+type GamepadState struct {
+	Connected bool
+	Name      string
+
+	Pressed      [GamepadButtonCount]bool
+	JustPressed  [GamepadButtonCount]bool
+	JustReleased [GamepadButtonCount]bool
+
+	Axes         [GamepadAxisCount]float32
+	PreviousAxes [GamepadAxisCount]float32
+}
+
+// GamepadEvent reports a pad connecting or disconnecting this frame.
+type GamepadEvent struct {
+	Slot      int
+	Name      string
+	Connected bool
+}
+
+// GamepadSample is one connected pad's raw state as read by a backend.
+type GamepadSample struct {
+	Slot    int
+	Name    string
+	Buttons [GamepadButtonCount]bool
+	Axes    [GamepadAxisCount]float32
+}
+
+// GamepadBackend reports the pads connected right now. Slots missing from a
+// poll are treated as disconnected.
+type GamepadBackend interface {
+	PollGamepads() []GamepadSample
+}
+
+// Gamepad returns the state of a slot, or nil when out of range.
+func (input *Input) Gamepad(slot int) *GamepadState {
+	if input == nil || slot < 0 || slot >= MaxGamepads {
+		return nil
+	}
+	return &input.Gamepads[slot]
+}
+
+// AnyGamepadJustPressed reports whether button went down on any connected
+// pad this frame.
+func (input *Input) AnyGamepadJustPressed(button GamepadButton) bool {
+	if input == nil || button < 0 || button >= GamepadButtonCount {
+		return false
+	}
+	for i := range input.Gamepads {
+		if input.Gamepads[i].Connected && input.Gamepads[i].JustPressed[button] {
+			return true
+		}
+	}
+	return false
+}
+
+func pollGamepadInput(input *Input) {
+	backend := input.GamepadBackend
+	if backend == nil {
+		backend = glfwGamepadBackend{}
+	}
+	updateGamepadInput(input, backend.PollGamepads())
+}
+
+// updateGamepadInput folds a poll into the Input resource, deriving button
+// edges and connect/disconnect events from the previous frame.
+func updateGamepadInput(input *Input, samples []GamepadSample) {
+	input.GamepadEvents = nil
+	var seen [MaxGamepads]bool
+	for _, sample := range samples {
+		if sample.Slot < 0 || sample.Slot >= MaxGamepads {
+			continue
+		}
+		seen[sample.Slot] = true
+		pad := &input.Gamepads[sample.Slot]
+		if !pad.Connected {
+			*pad = GamepadState{Connected: true, Name: sample.Name}
+			input.GamepadEvents = append(input.GamepadEvents, GamepadEvent{Slot: sample.Slot, Name: sample.Name, Connected: true})
+		}
+		for b, down := range sample.Buttons {
+			pad.JustPressed[b] = down && !pad.Pressed[b]
+			pad.JustReleased[b] = !down && pad.Pressed[b]
+			pad.Pressed[b] = down
+		}
+		pad.PreviousAxes = pad.Axes
+		pad.Axes = sample.Axes
+	}
+	for slot := range input.Gamepads {
+		pad := &input.Gamepads[slot]
+		if seen[slot] || !pad.Connected {
+			continue
+		}
+		input.GamepadEvents = append(input.GamepadEvents, GamepadEvent{Slot: slot, Name: pad.Name, Connected: false})
+		// Release everything so held actions see the edge.
+		released := pad.Pressed
+		*pad = GamepadState{JustReleased: released, PreviousAxes: pad.Axes}
+	}
+}
+
+type glfwGamepadBackend struct{}
+
+func (glfwGamepadBackend) PollGamepads() []GamepadSample {
+	var out []GamepadSample
+	for joy := glfw.Joystick1; joy <= glfw.JoystickLast; joy++ {
+		if !joy.Present() {
+			continue
+		}
+		sample := GamepadSample{Slot: int(joy - glfw.Joystick1)}
+		if joy.IsGamepad() {
+			state := joy.GetGamepadState()
+			if state == nil {
+				continue
+			}
+			sample.Name = joy.GetGamepadName()
+			for b := range sample.Buttons {
+				sample.Buttons[b] = state.Buttons[b] == glfw.Press
+			}
+			sample.Axes = state.Axes
+			// GLFW reports triggers as -1 at rest.
+			for _, trigger := range []GamepadAxis{GamepadAxisLeftTrigger, GamepadAxisRightTrigger} {
+				sample.Axes[trigger] = (sample.Axes[trigger] + 1) * 0.5
+			}
+		} else {
+			// Joysticks without a gamepad mapping expose their raw axes and
+			// buttons in device order.
+			sample.Name = joy.GetName()
+			for i, action := range joy.GetButtons() {
+				if i >= len(sample.Buttons) {
+					break
+				}
+				sample.Buttons[i] = action == glfw.Press
+			}
+			copy(sample.Axes[:], joy.GetAxes())
+		}
+		out = append(out, sample)
+	}
+	return out
+}
+
+// SyntheticGamepadBackend is a scripted GamepadBackend for tests and replays.
+type SyntheticGamepadBackend struct {
+	pads [MaxGamepads]*GamepadSample
+}
+
+func (b *SyntheticGamepadBackend) Connect(slot int, name string) {
+	if slot >= 0 && slot < MaxGamepads {
+		b.pads[slot] = &GamepadSample{Slot: slot, Name: name}
+	}
+}
+
+func (b *SyntheticGamepadBackend) Disconnect(slot int) {
+	if slot >= 0 && slot < MaxGamepads {
+		b.pads[slot] = nil
+	}
+}
+
+func (b *SyntheticGamepadBackend) SetButton(slot int, button GamepadButton, down bool) {
+	if pad := b.pad(slot); pad != nil && button >= 0 && button < GamepadButtonCount {
+		pad.Buttons[button] = down
+	}
+}
+
+func (b *SyntheticGamepadBackend) SetAxis(slot int, axis GamepadAxis, value float32) {
+	if pad := b.pad(slot); pad != nil && axis >= 0 && axis < GamepadAxisCount {
+		pad.Axes[axis] = value
+	}
+}
+
+func (b *SyntheticGamepadBackend) pad(slot int) *GamepadSample {
+	if slot < 0 || slot >= MaxGamepads {
+		return nil
+	}
+	return b.pads[slot]
+}
+
+func (b *SyntheticGamepadBackend) PollGamepads() []GamepadSample {
+	var out []GamepadSample
+	for _, pad := range b.pads {
+		if pad != nil {
+			out = append(out, *pad)
+		}
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+package gekko
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestGamepadInputTracksEdgesAndConnectionEvents(t *testing.T) {
+	backend := &SyntheticGamepadBackend{}
+	input := &Input{GamepadBackend: backend}
+
+	backend.Connect(1, "Pad")
+	backend.SetButton(1, GamepadButtonA, true)
+	backend.SetAxis(1, GamepadAxisLeftX, 0.75)
+	pollGamepadInput(input)
+	pad := input.Gamepad(1)
+	if len(input.GamepadEvents) != 1 || !input.GamepadEvents[0].Connected || input.GamepadEvents[0].Slot != 1 {
+		t.Fatalf("expected a connect event for slot 1, got %+v", input.GamepadEvents)
+	}
+	if !pad.Connected || !pad.JustPressed[GamepadButtonA] || pad.Axes[GamepadAxisLeftX] != 0.75 {
+		t.Fatalf("unexpected pad state %+v", pad)
+	}
+	if !input.AnyGamepadJustPressed(GamepadButtonA) || input.Gamepad(0).Connected {
+		t.Fatal("expected only slot 1 to be connected and pressing A")
+	}
+
+	pollGamepadInput(input)
+	if len(input.GamepadEvents) != 0 || pad.JustPressed[GamepadButtonA] || !pad.Pressed[GamepadButtonA] || pad.PreviousAxes[GamepadAxisLeftX] != 0.75 {
+		t.Fatalf("expected A held without a new edge, got %+v", pad)
+	}
+
+	backend.Disconnect(1)
+	pollGamepadInput(input)
+	if len(input.GamepadEvents) != 1 || input.GamepadEvents[0].Connected || input.GamepadEvents[0].Name != "Pad" {
+		t.Fatalf("expected a disconnect event, got %+v", input.GamepadEvents)
+	}
+	if pad.Connected || pad.Pressed[GamepadButtonA] || !pad.JustReleased[GamepadButtonA] {
+		t.Fatalf("expected disconnect to release held buttons, got %+v", pad)
+	}
+}
+
+func TestInputActionsReadGamepadButtonsAndSticks(t *testing.T) {
+	backend := &SyntheticGamepadBackend{}
+	input := &Input{GamepadBackend: backend}
+	actions := NewInputActions(DefaultGameplayInputActions())
+
+	backend.Connect(0, "Pad")
+	backend.SetAxis(0, GamepadAxisLeftY, -1)
+	backend.SetAxis(0, GamepadAxisLeftX, 0.1)
+	backend.SetAxis(0, GamepadAxisRightTrigger, 0.5)
+	backend.SetButton(0, GamepadButtonA, true)
+	pollGamepadInput(input)
+	actions.Update(input)
+	if got := actions.Axis2D(ActionMove); got != (mgl32.Vec2{0, 1}) {
+		t.Fatalf("expected stick up to move forward with X in the dead zone, got %v", got)
+	}
+	if !actions.JustPressed(ActionJump) || actions.Axis(ActionFlyVertical) != 0.5 {
+		t.Fatalf("expected A to jump and the trigger to climb, got jump=%+v fly=%v", actions.State(ActionJump), actions.Axis(ActionFlyVertical))
+	}
+
+	pollGamepadInput(input)
+	actions.Update(input)
+	if actions.JustPressed(ActionJump) || !actions.Pressed(ActionJump) {
+		t.Fatal("expected a held button not to retrigger jump")
+	}
+
+	if err := actions.BeginRebind(InputContextGameplay, ActionUse, 0); err != nil {
+		t.Fatalf("BeginRebind failed: %v", err)
+	}
+	backend.SetButton(0, GamepadButtonY, true)
+	pollGamepadInput(input)
+	actions.Update(input)
+	use := actions.Map(InputContextGameplay).Action(ActionUse).Bindings[0]
+	if use.Source != InputSourceGamepadButton || use.GamepadButton != GamepadButtonY || use.Gamepad != AnyGamepad {
+		t.Fatalf("expected Y to be captured, got %+v", use)
+	}
+
+	data, err := actions.MarshalBindings()
+	if err != nil {
+		t.Fatalf("MarshalBindings failed: %v", err)
+	}
+	loaded := NewInputActions(DefaultGameplayInputActions())
+	if err := loaded.UnmarshalBindings(data); err != nil {
+		t.Fatalf("UnmarshalBindings failed: %v", err)
+	}
+	if got := loaded.Map(InputContextGameplay).Action(ActionUse).Bindings[0]; got.GamepadButton != GamepadButtonY || got.Gamepad != AnyGamepad {
+		t.Fatalf("expected the gamepad binding to round-trip, got %+v", got)
+	}
+	if got := loaded.Map(InputContextGameplay).Action(ActionMove).Bindings[5]; got.GamepadAxis != GamepadAxisLeftY || got.DeadZone != 0.2 || got.Scale != -1 {
+		t.Fatalf("expected the stick binding to round-trip, got %+v", got)
+	}
+}
+
+func TestGroundedPlayerLooksWithRightStickWithoutMouseCapture(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	eid := cmd.AddEntity(&GroundedPlayerControllerComponent{})
+	app.FlushCommands()
+
+	backend := &SyntheticGamepadBackend{}
+	backend.Connect(0, "Pad")
+	backend.SetAxis(0, GamepadAxisRightX, 1)
+	input := &Input{GamepadBackend: backend}
+	pollGamepadInput(input)
+	groundedPlayerInputSystem(input, cmd)
+
+	for _, comp := range cmd.GetAllComponents(eid) {
+		if ctrl, ok := comp.(*GroundedPlayerControllerComponent); ok {
+			if ctrl.LookInput[0] != gamepadLookScale {
+				t.Fatalf("expected right stick look input, got %v", ctrl.LookInput)
+			}
+			return
+		}
+	}
+	t.Fatal("controller component missing")
+}
@@ -0,0 +1,270 @@
+package gekko
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gekko3d/gekko/content"
+)
+
+// DefaultLocale ends every fallback chain unless LocalizationModule names
+// another locale.
+const DefaultLocale = "en"
+
+// LocalizedText names a string table entry. Count picks the plural form and
+// fills {count}; Args fill the other {name} placeholders.
+type LocalizedText struct {
+	Key   string
+	Count int
+	Args  LocaleArgs
+}
+
+// LocaleArgs are the named values substituted into a localized string.
+type LocaleArgs map[string]any
+
+type LocalizationModule struct {
+	// Dir holds one .json or .po string table per locale.
+	Dir string
+	// Locale defaults to DefaultLocale.
+	Locale string
+	// DefaultLocale is the last locale in every fallback chain; empty uses
+	// the package DefaultLocale.
+	DefaultLocale string
+}
+
+func (mod LocalizationModule) Install(app *App, cmd *Commands) {
+	loc := NewLocalization(mod.DefaultLocale)
+	if mod.Dir != "" {
+		if err := loc.LoadDir(mod.Dir); err != nil {
+			fmt.Printf("WARNING: failed to load string tables %s: %v\n", mod.Dir, err)
+		}
+	}
+	if mod.Locale != "" {
+		loc.SetLocale(mod.Locale)
+	}
+	cmd.AddResources(loc)
+}
+
+// Localization holds string tables by locale and resolves keys through the
+// active locale's fallback chain: the locale, the fallbacks its tables name,
+// its base language, then the default locale. Switching locale bumps a
+// generation that makes the retained UI drop state built from old strings.
+type Localization struct {
+	tables        map[string]*content.StringTableDef
+	locale        string
+	defaultLocale string
+	chain         []string
+	generation    uint64
+	warned        map[string]bool
+}
+
+func NewLocalization(defaultLocale string) *Localization {
+	if defaultLocale == "" {
+		defaultLocale = DefaultLocale
+	}
+	defaultLocale = normalizeLocale(defaultLocale)
+	loc := &Localization{
+		tables:        make(map[string]*content.StringTableDef),
+		locale:        defaultLocale,
+		defaultLocale: defaultLocale,
+		warned:        make(map[string]bool),
+	}
+	loc.rebuildChain()
+	return loc
+}
+
+// AddTable adds or replaces the table for its locale.
+func (l *Localization) AddTable(def *content.StringTableDef) {
+	if def == nil {
+		return
+	}
+	l.tables[normalizeLocale(def.Locale)] = def
+	l.rebuildChain()
+	l.generation++
+	clear(l.warned)
+}
+
+// LoadDir adds every string table in dir. Tables that fail validation are
+// reported and skipped.
+func (l *Localization) LoadDir(dir string) error {
+	tables, err := content.LoadStringTableDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, def := range tables {
+		if result := content.ValidateStringTable(def); result.HasErrors() {
+			fmt.Printf("WARNING: skipping string table %q: %v\n", def.Locale, result)
+			continue
+		}
+		l.AddTable(def)
+	}
+	return nil
+}
+
+func (l *Localization) Locale() string {
+	return l.locale
+}
+
+// SetLocale switches the active locale. Locales without a table still work
+// through their fallback chain.
+func (l *Localization) SetLocale(locale string) {
+	locale = normalizeLocale(locale)
+	if locale == "" || locale == l.locale {
+		return
+	}
+	l.locale = locale
+	l.rebuildChain()
+	l.generation++
+	clear(l.warned)
+}
+
+// Locales lists the locales that have a table.
+func (l *Localization) Locales() []string {
+	locales := make([]string, 0, len(l.tables))
+	for locale := range l.tables {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// FallbackChain returns the locales consulted for the active locale, in
+// order.
+func (l *Localization) FallbackChain() []string {
+	return append([]string(nil), l.chain...)
+}
+
+func (l *Localization) rebuildChain() {
+	l.chain = l.chain[:0]
+	var add func(locale string)
+	add = func(locale string) {
+		locale = normalizeLocale(locale)
+		if locale == "" {
+			return
+		}
+		for _, existing := range l.chain {
+			if existing == locale {
+				return
+			}
+		}
+		l.chain = append(l.chain, locale)
+		if table, ok := l.tables[locale]; ok {
+			add(table.Fallback)
+		}
+	}
+	add(l.locale)
+	add(content.LocaleLanguage(l.locale))
+	add(l.defaultLocale)
+}
+
+// Has reports whether any locale in the chain defines key.
+func (l *Localization) Has(key string) bool {
+	_, _, ok := l.lookup(key)
+	return ok
+}
+
+// Text returns the string for key with args substituted. Missing keys
+// return the key itself and are reported once.
+func (l *Localization) Text(key string, args LocaleArgs) string {
+	return l.Localize("", LocalizedText{Key: key, Args: args})
+}
+
+// Plural returns the form of key for count, with count available as
+// {count}.
+func (l *Localization) Plural(key string, count int, args LocaleArgs) string {
+	return l.Localize("", LocalizedText{Key: key, Count: count, Args: args})
+}
+
+// Localize resolves text, or returns fallback when text has no key. A key
+// no table defines also returns fallback when it is set, so literal source
+// strings keep working before they are translated. Safe to call on a nil
+// Localization.
+func (l *Localization) Localize(fallback string, text LocalizedText) string {
+	if text.Key == "" {
+		return fallback
+	}
+	if l == nil {
+		if fallback != "" {
+			return fallback
+		}
+		return text.Key
+	}
+	entry, locale, ok := l.lookup(text.Key)
+	if !ok {
+		if !l.warned[text.Key] {
+			l.warned[text.Key] = true
+			fmt.Printf("WARNING: no %s string for %q\n", l.locale, text.Key)
+		}
+		if fallback != "" {
+			return fallback
+		}
+		return text.Key
+	}
+	return formatLocalized(entry.Form(content.PluralCategoryFor(locale, text.Count)), text.Count, text.Args)
+}
+
+func (l *Localization) lookup(key string) (content.StringEntryDef, string, bool) {
+	for _, locale := range l.chain {
+		table, ok := l.tables[locale]
+		if !ok {
+			continue
+		}
+		if entry, ok := table.Strings[key]; ok {
+			return entry, locale, true
+		}
+	}
+	return content.StringEntryDef{}, "", false
+}
+
+// formatLocalized replaces {name} placeholders with args and {count} with
+// count unless args sets it. Unknown placeholders are left in place so they
+// stand out.
+func formatLocalized(text string, count int, args LocaleArgs) string {
+	if !strings.ContainsAny(text, "{}") {
+		return text
+	}
+	var out strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if (c == '{' || c == '}') && i+1 < len(text) && text[i+1] == c {
+			out.WriteByte(c)
+			i++
+			continue
+		}
+		if c != '{' {
+			out.WriteByte(c)
+			continue
+		}
+		end := strings.IndexByte(text[i:], '}')
+		if end < 0 {
+			out.WriteString(text[i:])
+			break
+		}
+		name := text[i+1 : i+end]
+		if value, ok := args[name]; ok {
+			out.WriteString(fmt.Sprint(value))
+		} else if name == "count" {
+			fmt.Fprint(&out, count)
+		} else {
+			out.WriteString(text[i : i+end+1])
+		}
+		i += end
+	}
+	return out.String()
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+func localizationFromApp(app *App) *Localization {
+	if app == nil {
+		return nil
+	}
+	if resource, ok := app.resources[reflect.TypeOf(Localization{})]; ok {
+		return resource.(*Localization)
+	}
+	return nil
+}
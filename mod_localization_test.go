@@ -0,0 +1,139 @@
+package gekko
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gekko3d/gekko/content"
+)
+
+func newTestLocalization() *Localization {
+	loc := NewLocalization("")
+	loc.AddTable(&content.StringTableDef{Locale: "en", Strings: map[string]content.StringEntryDef{
+		"menu.play":   {Text: "Play"},
+		"menu.quit":   {Text: "Quit"},
+		"hud.ammo":    {Text: "Ammo {current}/{max}"},
+		"items.count": {Plural: map[content.PluralCategory]string{content.PluralOne: "{count} item", content.PluralOther: "{count} items"}},
+	}})
+	loc.AddTable(&content.StringTableDef{Locale: "ru", Strings: map[string]content.StringEntryDef{
+		"menu.play": {Text: "Играть"},
+		"items.count": {Plural: map[content.PluralCategory]string{
+			content.PluralOne:  "{count} предмет",
+			content.PluralFew:  "{count} предмета",
+			content.PluralMany: "{count} предметов",
+		}},
+	}})
+	loc.AddTable(&content.StringTableDef{Locale: "pt-BR", Fallback: "pt-PT", Strings: map[string]content.StringEntryDef{
+		"menu.play": {Text: "Jogar"},
+	}})
+	loc.AddTable(&content.StringTableDef{Locale: "pt-PT", Strings: map[string]content.StringEntryDef{
+		"menu.quit": {Text: "Sair"},
+	}})
+	return loc
+}
+
+func TestLocalizationResolvesPluralsArgsAndFallbackChains(t *testing.T) {
+	loc := newTestLocalization()
+	if got := loc.Plural("items.count", 1, nil); got != "1 item" {
+		t.Fatalf("expected the English one form, got %q", got)
+	}
+	if got := loc.Text("hud.ammo", LocaleArgs{"current": 12, "max": 30}); got != "Ammo 12/30" {
+		t.Fatalf("expected args to be substituted, got %q", got)
+	}
+
+	loc.SetLocale("ru_RU")
+	if got := loc.FallbackChain(); !reflect.DeepEqual(got, []string{"ru-ru", "ru", "en"}) {
+		t.Fatalf("expected region, language then default, got %v", got)
+	}
+	if got := loc.Plural("items.count", 22, nil); got != "22 предмета" {
+		t.Fatalf("expected the Russian few form, got %q", got)
+	}
+	if got := loc.Plural("items.count", 11, nil); got != "11 предметов" {
+		t.Fatalf("expected the Russian many form, got %q", got)
+	}
+	if got := loc.Text("menu.quit", nil); got != "Quit" {
+		t.Fatalf("expected the default locale to fill missing keys, got %q", got)
+	}
+
+	loc.SetLocale("pt-BR")
+	if got := loc.FallbackChain(); !reflect.DeepEqual(got, []string{"pt-br", "pt-pt", "pt", "en"}) {
+		t.Fatalf("expected the table's fallback before the language, got %v", got)
+	}
+	if got := loc.Text("menu.quit", nil); got != "Sair" {
+		t.Fatalf("expected the pt-PT fallback, got %q", got)
+	}
+
+	if got := loc.Text("menu.missing", nil); got != "menu.missing" {
+		t.Fatalf("expected a missing key to show itself, got %q", got)
+	}
+	if got := loc.Localize("Options", LocalizedText{Key: "menu.options"}); got != "Options" {
+		t.Fatalf("expected a missing key to keep the literal text, got %q", got)
+	}
+	var none *Localization
+	if got := none.Localize("Play", LocalizedText{Key: "menu.play"}); got != "Play" {
+		t.Fatalf("expected a nil localization to keep the literal text, got %q", got)
+	}
+	if got := formatLocalized("{{literal}} {unknown} {count}", 3, nil); got != "{literal} {unknown} 3" {
+		t.Fatalf("unexpected formatting %q", got)
+	}
+}
+
+func TestLocalizationModuleLoadsTablesFromDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, def := range []*content.StringTableDef{
+		{SchemaVersion: content.CurrentStringTableSchemaVersion, Locale: "en", Strings: map[string]content.StringEntryDef{"menu.play": {Text: "Play"}}},
+		{SchemaVersion: content.CurrentStringTableSchemaVersion, Locale: "de", Strings: map[string]content.StringEntryDef{"menu.play": {Text: "Spielen"}}},
+	} {
+		if err := content.SaveStringTable(filepath.Join(dir, def.Locale+".json"), def); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := NewApp()
+	cmd := app.Commands()
+	LocalizationModule{Dir: dir, Locale: "de"}.Install(app, cmd)
+	cmd.AddEntity(&TextComponent{Text: "Play", Loc: LocalizedText{Key: "menu.play"}})
+	app.FlushCommands()
+
+	loc := localizationFromApp(app)
+	if loc == nil || !reflect.DeepEqual(loc.Locales(), []string{"de", "en"}) {
+		t.Fatalf("expected both tables to load, got %v", loc)
+	}
+	items := buildTextBridgeItems(cmd)
+	if len(items) != 1 || items[0].Text != "Spielen" {
+		t.Fatalf("expected the text component to be localized, got %+v", items)
+	}
+}
+
+func TestUiLocaleSwitchRelabelsWidgetsAndResetsTextState(t *testing.T) {
+	loc := newTestLocalization()
+	runtime := newUiRuntime()
+	ctx := uiLayoutContext{runtime: runtime, loc: loc, pixelRatio: 1, scale: 1}
+
+	label := uiLayoutLabel(UiLabel{Text: "Play", Loc: LocalizedText{Key: "menu.play"}}, "0", 0, 0, ctx)
+	button := uiLayoutButton(UiButtonControl{Loc: LocalizedText{Key: "items.count", Count: 2}}, "1", 0, 0, ctx)
+	if label.node.(UiLabel).Text != "Play" || button.node.(UiButtonControl).Label != "2 items" {
+		t.Fatalf("expected localized layout nodes, got %+v %+v", label.node, button.node)
+	}
+
+	runtime.syncLocale(loc)
+	state := runtime.touch("dropdown/mode")
+	state.Open = true
+	state.Draft, state.LastControlled = "Windowed", "Windowed"
+	runtime.popup = uiPopup{owner: "dropdown/mode"}
+	runtime.syncLocale(loc)
+	if !state.Open {
+		t.Fatalf("expected state to survive while the locale is unchanged")
+	}
+
+	loc.SetLocale("ru")
+	runtime.syncLocale(loc)
+	if state.Open || state.Draft != "" || state.LastControlled != "" || runtime.popup.owner != "" {
+		t.Fatalf("expected a locale switch to reset text state, got %+v", state)
+	}
+	label = uiLayoutLabel(UiLabel{Text: "Play", Loc: LocalizedText{Key: "menu.play"}}, "0", 0, 0, ctx)
+	if label.node.(UiLabel).Text != "Играть" {
+		t.Fatalf("expected the new locale after switching, got %q", label.node.(UiLabel).Text)
+	}
+}
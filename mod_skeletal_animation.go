@@ -0,0 +1,379 @@
+package gekko
+
+import (
+	"math"
+
+	"github.com/gekko3d/gekko/content"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// BonePose is a bone transform relative to its parent bone.
+type BonePose struct {
+	Position mgl32.Vec3
+	Rotation mgl32.Quat
+}
+
+// AnimationEvent fires when playback reaches Frame. Event and Options are
+// game-defined; HL1 models use them for footsteps, sounds and muzzle flashes.
+type AnimationEvent struct {
+	Frame   int
+	Event   int
+	Options string
+}
+
+type SkeletalAnimationClip struct {
+	Name   string
+	FPS    float32
+	Loop   bool
+	Frames [][]BonePose
+	Events []AnimationEvent
+}
+
+// Duration is the time from the first to the last frame. Looping clips wrap
+// from the last frame back to the first, which authoring tools key to match.
+func (c *SkeletalAnimationClip) Duration() float32 {
+	if c == nil || len(c.Frames) < 2 || c.FPS <= 0 {
+		return 0
+	}
+	return float32(len(c.Frames)-1) / c.FPS
+}
+
+type SkeletonBone struct {
+	Name   string
+	Parent int
+	// Entity is the part the bone moves. Its LocalTransformComponent receives
+	// the pose; voxel chunks are children of it.
+	Entity EntityId
+	Bind   BonePose
+}
+
+// VoxelSkeletonComponent poses rigid per-bone voxel parts. Bones are ordered
+// parents first, as in the source skeleton.
+type VoxelSkeletonComponent struct {
+	Bones []SkeletonBone
+	Clips []SkeletalAnimationClip
+}
+
+func (s *VoxelSkeletonComponent) Clip(name string) *SkeletalAnimationClip {
+	if s == nil {
+		return nil
+	}
+	for i := range s.Clips {
+		if s.Clips[i].Name == name {
+			return &s.Clips[i]
+		}
+	}
+	return nil
+}
+
+// BindPose returns the skeleton's rest pose.
+func (s *VoxelSkeletonComponent) BindPose() []BonePose {
+	poses := make([]BonePose, len(s.Bones))
+	for i, bone := range s.Bones {
+		poses[i] = bone.Bind
+	}
+	return poses
+}
+
+// AnimationPlayerComponent plays clips of the entity's VoxelSkeletonComponent.
+// Use Play to switch clips with a crossfade; setting Clip directly cuts.
+type AnimationPlayerComponent struct {
+	Clip   string
+	Time   float32
+	Speed  float32 // zero plays at normal speed
+	Paused bool
+
+	fromClip      string
+	fromTime      float32
+	blendDuration float32
+	blendElapsed  float32
+	// cursor is the last frame position events were fired up to; -1 before
+	// the first advance so frame 0 events fire.
+	cursor     float32
+	cursorClip string
+}
+
+// Play switches to clip, crossfading from the current pose over blend
+// seconds. Playing the clip already active does nothing.
+func (p *AnimationPlayerComponent) Play(clip string, blend float32) {
+	if p.Clip == clip {
+		return
+	}
+	if blend > 0 && p.Clip != "" {
+		p.fromClip = p.Clip
+		p.fromTime = p.Time
+		p.blendDuration = blend
+		p.blendElapsed = 0
+	} else {
+		p.fromClip = ""
+		p.blendDuration = 0
+	}
+	p.Clip = clip
+	p.Time = 0
+}
+
+// Blending reports whether a crossfade is in progress.
+func (p *AnimationPlayerComponent) Blending() bool {
+	return p.fromClip != "" && p.blendElapsed < p.blendDuration
+}
+
+// Advance steps playback by dt seconds and returns the blended pose and the
+// events the active clip crossed.
+func (p *AnimationPlayerComponent) Advance(skeleton *VoxelSkeletonComponent, dt float32) ([]BonePose, []AnimationEvent) {
+	clip := skeleton.Clip(p.Clip)
+	if clip == nil {
+		return skeleton.BindPose(), nil
+	}
+	if p.cursorClip != p.Clip {
+		p.cursorClip = p.Clip
+		p.cursor = -1
+	}
+	step := dt
+	if p.Speed != 0 {
+		step *= p.Speed
+	}
+	if p.Paused {
+		step = 0
+	}
+
+	p.Time = advanceClipTime(clip, p.Time, step)
+	frame := clipFramePosition(clip, p.Time)
+	var events []AnimationEvent
+	if step != 0 || p.cursor < 0 {
+		events = clipEventsCrossed(clip, p.cursor, frame, step < 0)
+		p.cursor = frame
+	}
+
+	pose := SampleSkeletalClip(clip, p.Time, len(skeleton.Bones), skeleton.BindPose())
+	if p.fromClip != "" {
+		p.blendElapsed += float32(math.Abs(float64(dt)))
+		if p.blendElapsed >= p.blendDuration {
+			p.fromClip = ""
+		} else if from := skeleton.Clip(p.fromClip); from != nil {
+			p.fromTime = advanceClipTime(from, p.fromTime, step)
+			fromPose := SampleSkeletalClip(from, p.fromTime, len(skeleton.Bones), skeleton.BindPose())
+			pose = BlendBonePoses(fromPose, pose, p.blendElapsed/p.blendDuration)
+		}
+	}
+	return pose, events
+}
+
+func advanceClipTime(clip *SkeletalAnimationClip, t, step float32) float32 {
+	duration := clip.Duration()
+	if duration <= 0 {
+		return 0
+	}
+	t += step
+	if clip.Loop {
+		t = float32(math.Mod(float64(t), float64(duration)))
+		if t < 0 {
+			t += duration
+		}
+		return t
+	}
+	return clampf(t, 0, duration)
+}
+
+func clipFramePosition(clip *SkeletalAnimationClip, t float32) float32 {
+	if clip.FPS <= 0 || len(clip.Frames) == 0 {
+		return 0
+	}
+	return clampf(t*clip.FPS, 0, float32(len(clip.Frames)-1))
+}
+
+// clipEventsCrossed returns events after from up to and including to, in
+// frame positions. A wrap in the direction of play covers both ends.
+func clipEventsCrossed(clip *SkeletalAnimationClip, from, to float32, reverse bool) []AnimationEvent {
+	var out []AnimationEvent
+	last := float32(len(clip.Frames) - 1)
+	in := func(frame, lo, hi float32) bool { return frame > lo && frame <= hi }
+	for _, event := range clip.Events {
+		f := float32(event.Frame)
+		var hit bool
+		switch {
+		case from < 0:
+			hit = in(f, -1, to)
+		case !reverse && to >= from:
+			hit = in(f, from, to)
+		case !reverse:
+			hit = in(f, from, last) || in(f, -1, to)
+		case to <= from:
+			hit = f >= to && f < from
+		default:
+			hit = (f >= 0 && f < from) || (f >= to && f <= last)
+		}
+		if hit {
+			out = append(out, event)
+		}
+	}
+	return out
+}
+
+// SampleSkeletalClip interpolates the clip at t seconds. Bones the clip does
+// not key keep their fallback pose.
+func SampleSkeletalClip(clip *SkeletalAnimationClip, t float32, boneCount int, fallback []BonePose) []BonePose {
+	out := make([]BonePose, boneCount)
+	copy(out, fallback)
+	if clip == nil || len(clip.Frames) == 0 {
+		return out
+	}
+	position := clipFramePosition(clip, t)
+	f0 := int(position)
+	f1 := f0 + 1
+	if f1 >= len(clip.Frames) {
+		f1 = f0
+	}
+	alpha := position - float32(f0)
+	a, b := clip.Frames[f0], clip.Frames[f1]
+	for i := 0; i < boneCount && i < len(a) && i < len(b); i++ {
+		out[i] = lerpBonePose(a[i], b[i], alpha)
+	}
+	return out
+}
+
+// BlendBonePoses mixes two poses; t=0 returns a and t=1 returns b.
+func BlendBonePoses(a, b []BonePose, t float32) []BonePose {
+	out := make([]BonePose, len(b))
+	for i := range b {
+		if i < len(a) {
+			out[i] = lerpBonePose(a[i], b[i], t)
+		} else {
+			out[i] = b[i]
+		}
+	}
+	return out
+}
+
+func lerpBonePose(a, b BonePose, t float32) BonePose {
+	if t <= 0 {
+		return a
+	}
+	if t >= 1 {
+		return b
+	}
+	// Normalized lerp along the shorter arc; frames are close enough that
+	// slerp's constant velocity is not visible.
+	rb := b.Rotation
+	if a.Rotation.Dot(rb) < 0 {
+		rb = rb.Scale(-1)
+	}
+	rot := a.Rotation.Scale(1 - t).Add(rb.Scale(t))
+	if rot.Len() > 1e-6 {
+		rot = rot.Normalize()
+	} else {
+		rot = b.Rotation
+	}
+	return BonePose{
+		Position: a.Position.Add(b.Position.Sub(a.Position).Mul(t)),
+		Rotation: rot,
+	}
+}
+
+// FiredAnimationEvent is an AnimationEvent reached by an entity's player.
+type FiredAnimationEvent struct {
+	Entity EntityId
+	Clip   string
+	AnimationEvent
+}
+
+// AnimationEventQueue holds the animation events fired this frame.
+// skeletalAnimationSystem clears it before advancing players, so game code
+// reads Events until the next Update or takes them with Drain.
+type AnimationEventQueue struct {
+	Events []FiredAnimationEvent
+}
+
+func (q *AnimationEventQueue) Drain() []FiredAnimationEvent {
+	if q == nil || len(q.Events) == 0 {
+		return nil
+	}
+	events := q.Events
+	q.Events = nil
+	return events
+}
+
+type SkeletalAnimationModule struct{}
+
+func (SkeletalAnimationModule) Install(app *App, cmd *Commands) {
+	cmd.AddResources(&AnimationEventQueue{})
+	app.UseSystem(
+		System(skeletalAnimationSystem).
+			InStage(Update).
+			RunAlways(),
+	)
+}
+
+// skeletalAnimationSystem advances players and writes bone poses into the
+// bone parts' local transforms; TransformHierarchySystem then carries the
+// voxel chunks along in PostUpdate. Last frame's events are dropped first.
+func skeletalAnimationSystem(time *Time, queue *AnimationEventQueue, cmd *Commands) {
+	queue.Events = queue.Events[:0]
+	dt := float32(time.Dt)
+	MakeQuery2[VoxelSkeletonComponent, AnimationPlayerComponent](cmd).Map(func(eid EntityId, skeleton *VoxelSkeletonComponent, player *AnimationPlayerComponent) bool {
+		pose, events := player.Advance(skeleton, dt)
+		for _, event := range events {
+			queue.Events = append(queue.Events, FiredAnimationEvent{Entity: eid, Clip: player.Clip, AnimationEvent: event})
+		}
+		applySkeletonPose(cmd, skeleton, pose)
+		return true
+	})
+}
+
+func applySkeletonPose(cmd *Commands, skeleton *VoxelSkeletonComponent, pose []BonePose) {
+	for i, bone := range skeleton.Bones {
+		if bone.Entity == 0 || i >= len(pose) {
+			continue
+		}
+		for _, comp := range cmd.GetAllComponents(bone.Entity) {
+			if local, ok := comp.(*LocalTransformComponent); ok {
+				local.Position = pose[i].Position
+				local.Rotation = pose[i].Rotation
+				break
+			}
+		}
+	}
+}
+
+// voxelSkeletonFromAssetDef binds an authored skeleton to spawned parts.
+func voxelSkeletonFromAssetDef(def *content.AssetDef, entities map[string]EntityId) *VoxelSkeletonComponent {
+	if def == nil || def.Skeleton == nil {
+		return nil
+	}
+	transforms := make(map[string]content.AssetTransformDef, len(def.Parts))
+	for _, part := range def.Parts {
+		transforms[part.ID] = part.Transform
+	}
+	skeleton := &VoxelSkeletonComponent{Bones: make([]SkeletonBone, len(def.Skeleton.Bones))}
+	for i, bone := range def.Skeleton.Bones {
+		bind := AssetLocalTransformFromDef(transforms[bone.PartID])
+		skeleton.Bones[i] = SkeletonBone{
+			Name:   bone.Name,
+			Parent: bone.Parent,
+			Entity: entities[bone.PartID],
+			Bind:   BonePose{Position: bind.Position, Rotation: bind.Rotation},
+		}
+	}
+	for _, animation := range def.Skeleton.Animations {
+		clip := SkeletalAnimationClip{
+			Name:   animation.Name,
+			FPS:    animation.FPS,
+			Loop:   animation.Loop,
+			Frames: make([][]BonePose, len(animation.Frames)),
+		}
+		for f, frame := range animation.Frames {
+			poses := make([]BonePose, len(frame.Bones))
+			for b, pose := range frame.Bones {
+				poses[b] = BonePose{
+					Position: mgl32.Vec3(pose.Position),
+					Rotation: mgl32.Quat{W: pose.Rotation[3], V: mgl32.Vec3{pose.Rotation[0], pose.Rotation[1], pose.Rotation[2]}},
+				}
+			}
+			clip.Frames[f] = poses
+		}
+		for _, event := range animation.Events {
+			clip.Events = append(clip.Events, AnimationEvent{Frame: event.Frame, Event: event.Event, Options: event.Options})
+		}
+		skeleton.Clips = append(skeleton.Clips, clip)
+	}
+	return skeleton
+}
@@ -0,0 +1,149 @@
+package gekko
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gekko3d/gekko/content"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func yawPose(angle float32) BonePose {
+	return BonePose{Rotation: mgl32.QuatRotate(angle, mgl32.Vec3{0, 1, 0})}
+}
+
+func testSkeletalClip(name string, loop bool) SkeletalAnimationClip {
+	return SkeletalAnimationClip{
+		Name: name,
+		FPS:  10,
+		Loop: loop,
+		Frames: [][]BonePose{
+			{yawPose(0)},
+			{yawPose(1)},
+			{yawPose(0)},
+		},
+		Events: []AnimationEvent{{Frame: 0, Event: 1}, {Frame: 2, Event: 2}},
+	}
+}
+
+func TestSampleSkeletalClipInterpolatesAndWraps(t *testing.T) {
+	clip := testSkeletalClip("swing", true)
+	pose := SampleSkeletalClip(&clip, 0.05, 1, nil)
+	if !pose[0].Rotation.ApproxEqualThreshold(yawPose(0.5).Rotation, 1e-4) {
+		t.Fatalf("expected halfway yaw, got %v", pose[0].Rotation)
+	}
+	if got := advanceClipTime(&clip, 0.15, 0.1); math.Abs(float64(got-0.05)) > 1e-5 {
+		t.Fatalf("expected looping time to wrap to 0.05, got %v", got)
+	}
+	once := testSkeletalClip("swing", false)
+	if got := advanceClipTime(&once, 0.15, 0.1); got != once.Duration() {
+		t.Fatalf("expected one-shot time to clamp at %v, got %v", once.Duration(), got)
+	}
+}
+
+func TestAnimationPlayerFiresEventsOnceAcrossWrap(t *testing.T) {
+	clip := testSkeletalClip("swing", true)
+	skeleton := &VoxelSkeletonComponent{Bones: []SkeletonBone{{Parent: -1, Bind: yawPose(0)}}, Clips: []SkeletalAnimationClip{clip}}
+	player := &AnimationPlayerComponent{Clip: "swing"}
+
+	if _, events := player.Advance(skeleton, 0); len(events) != 1 || events[0].Event != 1 {
+		t.Fatalf("expected the frame 0 event on the first advance, got %+v", events)
+	}
+	if _, events := player.Advance(skeleton, 0.15); len(events) != 0 {
+		t.Fatalf("expected no events before frame 2, got %+v", events)
+	}
+	// 0.15s -> 0.25s wraps past frame 2 back to frame 0.5.
+	if _, events := player.Advance(skeleton, 0.1); len(events) != 2 {
+		t.Fatalf("expected both events across the wrap, got %+v", events)
+	}
+}
+
+func TestAnimationPlayerCrossfadesBetweenClips(t *testing.T) {
+	still := SkeletalAnimationClip{Name: "still", FPS: 10, Loop: true, Frames: [][]BonePose{{yawPose(0)}, {yawPose(0)}}}
+	turned := SkeletalAnimationClip{Name: "turned", FPS: 10, Loop: true, Frames: [][]BonePose{{yawPose(1)}, {yawPose(1)}}}
+	skeleton := &VoxelSkeletonComponent{Bones: []SkeletonBone{{Parent: -1, Bind: yawPose(0)}}, Clips: []SkeletalAnimationClip{still, turned}}
+	player := &AnimationPlayerComponent{Clip: "still"}
+	player.Advance(skeleton, 0)
+
+	player.Play("turned", 0.2)
+	pose, _ := player.Advance(skeleton, 0.1)
+	if !player.Blending() || !pose[0].Rotation.ApproxEqualThreshold(yawPose(0.5).Rotation, 1e-4) {
+		t.Fatalf("expected a half blend, got %v blending=%v", pose[0].Rotation, player.Blending())
+	}
+	pose, _ = player.Advance(skeleton, 0.1)
+	if player.Blending() || !pose[0].Rotation.ApproxEqualThreshold(yawPose(1).Rotation, 1e-4) {
+		t.Fatalf("expected the blend to finish on the new clip, got %v", pose[0].Rotation)
+	}
+}
+
+func TestSpawnAuthoredAssetBindsSkeletonAndAnimatesBoneParts(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+
+	identity := content.AssetTransformDef{Rotation: content.Quat{0, 0, 0, 1}, Scale: content.Vec3{1, 1, 1}}
+	arm := identity
+	arm.Position = content.Vec3{1, 0, 0}
+	def := content.NewAssetDef("rig")
+	def.Runtime = &content.AssetRuntimeDef{CollapseVoxelParts: true}
+	def.Parts = []content.AssetPartDef{
+		{ID: "bone_0", Name: "root", Source: content.AssetSourceDef{Kind: content.AssetSourceKindGroup}, Transform: identity},
+		{ID: "bone_1", Name: "arm", ParentID: "bone_0", Source: content.AssetSourceDef{Kind: content.AssetSourceKindGroup}, Transform: arm},
+	}
+	yaw := yawPose(1).Rotation
+	turned := content.Quat{yaw.V[0], yaw.V[1], yaw.V[2], yaw.W}
+	def.Skeleton = &content.AssetSkeletonDef{
+		Bones: []content.AssetBoneDef{
+			{Name: "root", PartID: "bone_0", Parent: -1},
+			{Name: "arm", PartID: "bone_1", Parent: 0},
+		},
+		Animations: []content.AssetAnimationDef{{
+			Name: "wave",
+			FPS:  10,
+			Frames: []content.AssetAnimationFrameDef{
+				{Bones: []content.AssetBonePoseDef{{Rotation: content.Quat{0, 0, 0, 1}}, {Position: arm.Position, Rotation: content.Quat{0, 0, 0, 1}}}},
+				{Bones: []content.AssetBonePoseDef{{Rotation: content.Quat{0, 0, 0, 1}}, {Position: arm.Position, Rotation: turned}}},
+			},
+			Events: []content.AssetAnimationEventDef{{Frame: 1, Event: 5004, Options: "wave"}},
+		}},
+	}
+
+	result, err := SpawnAuthoredAsset(cmd, nil, def, TransformComponent{Rotation: mgl32.QuatIdent(), Scale: mgl32.Vec3{1, 1, 1}})
+	if err != nil {
+		t.Fatalf("SpawnAuthoredAsset failed: %v", err)
+	}
+	if result.EntitiesByAssetID["bone_1"] == 0 {
+		t.Fatal("expected skeletal assets to keep their bone parts instead of collapsing")
+	}
+	var skeleton *VoxelSkeletonComponent
+	var player *AnimationPlayerComponent
+	for _, comp := range cmd.GetAllComponents(result.RootEntity) {
+		switch c := comp.(type) {
+		case *VoxelSkeletonComponent:
+			skeleton = c
+		case *AnimationPlayerComponent:
+			player = c
+		}
+	}
+	if skeleton == nil || player == nil || player.Clip != "wave" {
+		t.Fatalf("expected skeleton and player on the root, got %+v %+v", skeleton, player)
+	}
+	if skeleton.Bones[1].Entity != result.EntitiesByAssetID["bone_1"] || skeleton.Bones[1].Bind.Position != (mgl32.Vec3{1, 0, 0}) {
+		t.Fatalf("unexpected bound bone %+v", skeleton.Bones[1])
+	}
+
+	queue := &AnimationEventQueue{Events: []FiredAnimationEvent{{Clip: "stale"}}}
+	skeletalAnimationSystem(&Time{Dt: 0.1}, queue, cmd)
+	var local *LocalTransformComponent
+	for _, comp := range cmd.GetAllComponents(result.EntitiesByAssetID["bone_1"]) {
+		if c, ok := comp.(*LocalTransformComponent); ok {
+			local = c
+		}
+	}
+	if local == nil || !local.Rotation.ApproxEqualThreshold(yaw, 1e-4) {
+		t.Fatalf("expected the arm to be posed at the last frame, got %+v", local)
+	}
+	events := queue.Drain()
+	if len(events) != 1 || events[0].Entity != result.RootEntity || events[0].Event != 5004 || events[0].Clip != "wave" {
+		t.Fatalf("expected only this frame's fired event, got %+v", events)
+	}
+}
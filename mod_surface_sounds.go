@@ -0,0 +1,585 @@
+package gekko
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/gekko3d/gekko/content"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SurfaceComponent names the surface of an entity's geometry for footstep
+// and impact sounds. Imported-world chunks resolve theirs from material tags
+// instead.
+type SurfaceComponent struct {
+	Surface string
+}
+
+// SurfaceSoundBank lists the clip paths played for one surface. Each event
+// picks one; empty lists stay silent.
+type SurfaceSoundBank struct {
+	Footsteps []string
+	Impacts   []string
+	Scrapes   []string
+	Volume    float32 // zero means 1
+}
+
+// SurfaceSoundTable maps surface names such as "metal", "concrete", "grate",
+// "water" and "glass" to sound banks.
+type SurfaceSoundTable struct {
+	Banks map[string]SurfaceSoundBank
+	// DefaultSurface plays when a surface is unknown or has no bank.
+	DefaultSurface string
+}
+
+func NewSurfaceSoundTable(defaultSurface string) *SurfaceSoundTable {
+	return &SurfaceSoundTable{Banks: map[string]SurfaceSoundBank{}, DefaultSurface: defaultSurface}
+}
+
+func (t *SurfaceSoundTable) SetBank(surface string, bank SurfaceSoundBank) {
+	if t.Banks == nil {
+		t.Banks = map[string]SurfaceSoundBank{}
+	}
+	t.Banks[strings.ToLower(surface)] = bank
+}
+
+// Bank returns the bank for surface, falling back to DefaultSurface, along
+// with the surface name that matched.
+func (t *SurfaceSoundTable) Bank(surface string) (string, SurfaceSoundBank, bool) {
+	if t == nil {
+		return "", SurfaceSoundBank{}, false
+	}
+	surface = strings.ToLower(surface)
+	if bank, ok := t.Banks[surface]; ok {
+		return surface, bank, true
+	}
+	if bank, ok := t.Banks[t.DefaultSurface]; ok {
+		return t.DefaultSurface, bank, true
+	}
+	return "", SurfaceSoundBank{}, false
+}
+
+// DefaultHL1SurfaceSoundTable uses the stock HL1 player and debris sounds,
+// with paths under soundRoot (the game's sound/ directory). Surfaces match
+// the names the HL1 importer tags from materials.txt.
+func DefaultHL1SurfaceSoundTable(soundRoot string) *SurfaceSoundTable {
+	clips := func(pattern string, count int) []string {
+		out := make([]string, count)
+		for i := range out {
+			out[i] = path.Join(soundRoot, fmt.Sprintf(pattern, i+1))
+		}
+		return out
+	}
+	concrete := SurfaceSoundBank{Footsteps: clips("player/pl_step%d.wav", 4), Impacts: clips("player/pl_step%d.wav", 2), Scrapes: clips("debris/pushbox%d.wav", 3)}
+	table := NewSurfaceSoundTable("concrete")
+	table.SetBank("concrete", concrete)
+	table.SetBank("metal", SurfaceSoundBank{Footsteps: clips("player/pl_metal%d.wav", 4), Impacts: clips("debris/metal%d.wav", 3)})
+	table.SetBank("dirt", SurfaceSoundBank{Footsteps: clips("player/pl_dirt%d.wav", 4), Impacts: clips("player/pl_dirt%d.wav", 3)})
+	table.SetBank("vent", SurfaceSoundBank{Footsteps: clips("player/pl_duct%d.wav", 4), Impacts: clips("player/pl_duct%d.wav", 1)})
+	table.SetBank("grate", SurfaceSoundBank{Footsteps: clips("player/pl_grate%d.wav", 4), Impacts: clips("player/pl_grate%d.wav", 4)})
+	table.SetBank("tile", SurfaceSoundBank{Footsteps: clips("player/pl_tile%d.wav", 5), Impacts: clips("player/pl_tile%d.wav", 4)})
+	table.SetBank("water", SurfaceSoundBank{Footsteps: clips("player/pl_slosh%d.wav", 4), Impacts: clips("player/pl_wade%d.wav", 4)})
+	table.SetBank("ladder", SurfaceSoundBank{Footsteps: clips("player/pl_ladder%d.wav", 4)})
+	table.SetBank("wood", SurfaceSoundBank{Footsteps: concrete.Footsteps, Impacts: clips("debris/wood%d.wav", 3), Scrapes: concrete.Scrapes})
+	table.SetBank("glass", SurfaceSoundBank{Footsteps: concrete.Footsteps, Impacts: clips("debris/glass%d.wav", 3)})
+	table.SetBank("computer", SurfaceSoundBank{Footsteps: concrete.Footsteps, Impacts: clips("debris/glass%d.wav", 3)})
+	return table
+}
+
+// importedWorldKindSurfaces maps material kinds and tags from importers that
+// do not emit "surface:" tags.
+var importedWorldKindSurfaces = map[string]string{
+	"metal":            "metal",
+	"grate":            "grate",
+	"glass":            "glass",
+	"water":            "water",
+	"slime":            "water",
+	"concrete":         "concrete",
+	"wood":             "wood",
+	"terrain":          "dirt",
+	"ladder":           "ladder",
+	"material:metal":   "metal",
+	"material:glass":   "glass",
+	"material:liquid":  "water",
+	"material:masonry": "concrete",
+	"material:wood":    "wood",
+	"material:terrain": "dirt",
+}
+
+// SurfaceForImportedWorldMaterial returns a material's "surface:" tag, or a
+// surface derived from its kind and tags, or "".
+func SurfaceForImportedWorldMaterial(material content.ImportedWorldMaterialDef) string {
+	for _, tag := range material.Tags {
+		if surface, ok := strings.CutPrefix(tag, "surface:"); ok && surface != "" {
+			return strings.ToLower(surface)
+		}
+	}
+	if surface, ok := importedWorldKindSurfaces[strings.ToLower(material.Kind)]; ok {
+		return surface
+	}
+	for _, tag := range material.Tags {
+		if surface, ok := importedWorldKindSurfaces[tag]; ok {
+			return surface
+		}
+	}
+	return ""
+}
+
+type SurfaceSoundKind uint8
+
+const (
+	SurfaceSoundFootstep SurfaceSoundKind = iota
+	SurfaceSoundLanding
+	SurfaceSoundImpact
+	SurfaceSoundScrape
+)
+
+func (k SurfaceSoundKind) String() string {
+	switch k {
+	case SurfaceSoundFootstep:
+		return "footstep"
+	case SurfaceSoundLanding:
+		return "landing"
+	case SurfaceSoundImpact:
+		return "impact"
+	case SurfaceSoundScrape:
+		return "scrape"
+	default:
+		return "unknown"
+	}
+}
+
+type SurfaceSoundEvent struct {
+	Kind     SurfaceSoundKind
+	Entity   EntityId
+	Surface  string
+	Clip     string
+	Position mgl32.Vec3
+	Volume   float32
+}
+
+type SurfaceSoundConfig struct {
+	// StrideLength is the horizontal distance between footsteps, and
+	// LadderStride the vertical distance between ladder steps.
+	StrideLength float32
+	LadderStride float32
+	// A landing after at least LandingAirTime seconds airborne plays a
+	// louder footstep.
+	LandingAirTime float32
+	FootstepVolume float32
+	LandingVolume  float32
+	// Impacts below ImpactMinImpulse are silent; ImpactFullImpulse plays at
+	// full volume.
+	ImpactMinImpulse  float32
+	ImpactFullImpulse float32
+	// Sustained contacts scrape above ScrapeMinSpeed, at full volume by
+	// ScrapeFullSpeed.
+	ScrapeMinSpeed  float32
+	ScrapeFullSpeed float32
+	// ImpactInterval and ScrapeInterval are the minimum seconds between
+	// sounds of that kind from one entity.
+	ImpactInterval float32
+	ScrapeInterval float32
+	// WaterImpactMinSpeed gates splashes from WaterImpactEvents.
+	WaterImpactMinSpeed float32
+	Attenuation         AudioAttenuation
+}
+
+func DefaultSurfaceSoundConfig() SurfaceSoundConfig {
+	return SurfaceSoundConfig{
+		StrideLength:        1.7,
+		LadderStride:        0.5,
+		LandingAirTime:      0.3,
+		FootstepVolume:      0.5,
+		LandingVolume:       0.8,
+		ImpactMinImpulse:    1,
+		ImpactFullImpulse:   20,
+		ScrapeMinSpeed:      0.75,
+		ScrapeFullSpeed:     6,
+		ImpactInterval:      0.12,
+		ScrapeInterval:      0.35,
+		WaterImpactMinSpeed: 1.5,
+		Attenuation:         AudioAttenuation{MinDistance: 1.5, MaxDistance: 30},
+	}
+}
+
+func effectiveSurfaceSoundConfig(cfg SurfaceSoundConfig) SurfaceSoundConfig {
+	defaults := DefaultSurfaceSoundConfig()
+	if cfg.StrideLength != 0 {
+		defaults.StrideLength = cfg.StrideLength
+	}
+	if cfg.LadderStride != 0 {
+		defaults.LadderStride = cfg.LadderStride
+	}
+	if cfg.LandingAirTime != 0 {
+		defaults.LandingAirTime = cfg.LandingAirTime
+	}
+	if cfg.FootstepVolume != 0 {
+		defaults.FootstepVolume = cfg.FootstepVolume
+	}
+	if cfg.LandingVolume != 0 {
+		defaults.LandingVolume = cfg.LandingVolume
+	}
+	if cfg.ImpactMinImpulse != 0 {
+		defaults.ImpactMinImpulse = cfg.ImpactMinImpulse
+	}
+	if cfg.ImpactFullImpulse != 0 {
+		defaults.ImpactFullImpulse = cfg.ImpactFullImpulse
+	}
+	if cfg.ScrapeMinSpeed != 0 {
+		defaults.ScrapeMinSpeed = cfg.ScrapeMinSpeed
+	}
+	if cfg.ScrapeFullSpeed != 0 {
+		defaults.ScrapeFullSpeed = cfg.ScrapeFullSpeed
+	}
+	if cfg.ImpactInterval != 0 {
+		defaults.ImpactInterval = cfg.ImpactInterval
+	}
+	if cfg.ScrapeInterval != 0 {
+		defaults.ScrapeInterval = cfg.ScrapeInterval
+	}
+	if cfg.WaterImpactMinSpeed != 0 {
+		defaults.WaterImpactMinSpeed = cfg.WaterImpactMinSpeed
+	}
+	if cfg.Attenuation != (AudioAttenuation{}) {
+		defaults.Attenuation = cfg.Attenuation
+	}
+	return defaults
+}
+
+type footstepTracker struct {
+	lastPosition mgl32.Vec3
+	distance     float32
+	airTime      float32
+	wasGrounded  bool
+	steps        uint64
+}
+
+type surfaceSoundLimitKey struct {
+	entity EntityId
+	kind   SurfaceSoundKind
+}
+
+// SurfaceSoundState turns player strides and contacts into surface sounds.
+// Events are played through AudioState when it is installed and hold the
+// sounds emitted this frame; surfaceSoundSystem clears them before emitting,
+// so game code reads them until the next PostUpdate or takes them with Drain.
+type SurfaceSoundState struct {
+	Table  *SurfaceSoundTable
+	Config SurfaceSoundConfig
+	Events []SurfaceSoundEvent
+
+	clock     float64
+	footsteps map[EntityId]*footstepTracker
+	lastSound map[surfaceSoundLimitKey]float64
+}
+
+func NewSurfaceSoundState(table *SurfaceSoundTable, cfg SurfaceSoundConfig) *SurfaceSoundState {
+	if table == nil {
+		table = NewSurfaceSoundTable("concrete")
+	}
+	return &SurfaceSoundState{
+		Table:     table,
+		Config:    effectiveSurfaceSoundConfig(cfg),
+		footsteps: map[EntityId]*footstepTracker{},
+		lastSound: map[surfaceSoundLimitKey]float64{},
+	}
+}
+
+func (s *SurfaceSoundState) Drain() []SurfaceSoundEvent {
+	if s == nil || len(s.Events) == 0 {
+		return nil
+	}
+	events := s.Events
+	s.Events = nil
+	return events
+}
+
+// Advance moves the rate-limit clock and forgets entities that have been
+// quiet longer than any interval.
+func (s *SurfaceSoundState) Advance(dt float32) {
+	s.clock += float64(dt)
+	horizon := float64(max(s.Config.ImpactInterval, s.Config.ScrapeInterval))
+	for key, at := range s.lastSound {
+		if s.clock-at > horizon {
+			delete(s.lastSound, key)
+		}
+	}
+}
+
+// UpdateFootsteps emits a footstep each StrideLength a grounded player walks,
+// ladder steps while climbing, and a landing after a fall. The surface comes
+// from a short ray under the player's feet, or water when wading.
+func (s *SurfaceSoundState) UpdateFootsteps(cmd *Commands, raycaster AudioRaycaster, dt float32) {
+	cfg := s.Config
+	seen := map[EntityId]bool{}
+	MakeQuery2[GroundedPlayerControllerComponent, TransformComponent](cmd).Map(func(eid EntityId, ctrl *GroundedPlayerControllerComponent, tr *TransformComponent) bool {
+		seen[eid] = true
+		tracker := s.footsteps[eid]
+		if tracker == nil {
+			s.footsteps[eid] = &footstepTracker{lastPosition: tr.Position, wasGrounded: ctrl.Grounded}
+			return true
+		}
+		delta := tr.Position.Sub(tracker.lastPosition)
+		tracker.lastPosition = tr.Position
+
+		switch {
+		case ctrl.OnLadder:
+			tracker.airTime = 0
+			tracker.distance += absf(delta.Y())
+			if tracker.distance >= cfg.LadderStride {
+				tracker.distance -= cfg.LadderStride
+				s.emitFootstep(cmd, eid, SurfaceSoundFootstep, "ladder", tr.Position, cfg.FootstepVolume, tracker)
+			}
+		case ctrl.Grounded:
+			if !tracker.wasGrounded && tracker.airTime >= cfg.LandingAirTime {
+				tracker.distance = 0
+				s.emitFootstep(cmd, eid, SurfaceSoundLanding, surfaceUnderFeet(cmd, raycaster, tr.Position), tr.Position, cfg.LandingVolume, tracker)
+			}
+			tracker.airTime = 0
+			tracker.distance += mgl32.Vec2{delta.X(), delta.Z()}.Len()
+			if tracker.distance >= cfg.StrideLength {
+				tracker.distance -= cfg.StrideLength
+				s.emitFootstep(cmd, eid, SurfaceSoundFootstep, surfaceUnderFeet(cmd, raycaster, tr.Position), tr.Position, cfg.FootstepVolume, tracker)
+			}
+		default:
+			tracker.airTime += dt
+		}
+		tracker.wasGrounded = ctrl.Grounded && !ctrl.OnLadder
+		return true
+	})
+	for eid := range s.footsteps {
+		if !seen[eid] {
+			delete(s.footsteps, eid)
+		}
+	}
+}
+
+func (s *SurfaceSoundState) emitFootstep(cmd *Commands, eid EntityId, kind SurfaceSoundKind, surface string, position mgl32.Vec3, volume float32, tracker *footstepTracker) {
+	surface, bank, ok := s.Table.Bank(surface)
+	if !ok || len(bank.Footsteps) == 0 {
+		return
+	}
+	clip := bank.Footsteps[tracker.steps%uint64(len(bank.Footsteps))]
+	tracker.steps++
+	s.emit(cmd, SurfaceSoundEvent{Kind: kind, Entity: eid, Surface: surface, Clip: clip, Position: position, Volume: volume * defaulted(bank.Volume, 1)})
+}
+
+// EmitImpacts plays impact sounds for entering contacts and scrapes for
+// sustained sliding ones, scaled by impulse and speed and rate-limited per
+// entity. Pass the events drained from PhysicsProxy each frame. It returns
+// the number of sounds emitted.
+func (s *SurfaceSoundState) EmitImpacts(cmd *Commands, events []PhysicsCollisionEvent) int {
+	if s == nil || cmd == nil {
+		return 0
+	}
+	cfg := s.Config
+	var raycaster AudioRaycaster
+	if voxRt := voxelRtStateFromApp(cmd.app); voxRt != nil {
+		raycaster = voxRt
+	}
+	emitted := 0
+	for _, event := range events {
+		if event.IsTrigger {
+			continue
+		}
+		var kind SurfaceSoundKind
+		var loudness float32
+		var interval float32
+		switch event.Type {
+		case CollisionEventEnter:
+			if event.NormalImpulse < cfg.ImpactMinImpulse {
+				continue
+			}
+			kind, interval = SurfaceSoundImpact, cfg.ImpactInterval
+			loudness = surfaceSoundLoudness(event.NormalImpulse, cfg.ImpactMinImpulse, cfg.ImpactFullImpulse)
+		case CollisionEventStay:
+			if event.RelativeSpeed < cfg.ScrapeMinSpeed {
+				continue
+			}
+			kind, interval = SurfaceSoundScrape, cfg.ScrapeInterval
+			loudness = surfaceSoundLoudness(event.RelativeSpeed, cfg.ScrapeMinSpeed, cfg.ScrapeFullSpeed)
+		default:
+			continue
+		}
+		if s.limited(event.A, kind, interval) || s.limited(event.B, kind, interval) {
+			continue
+		}
+		surface, bank, ok := s.Table.Bank(surfaceForContact(cmd, raycaster, event))
+		clips := bank.Impacts
+		if kind == SurfaceSoundScrape {
+			clips = bank.Scrapes
+		}
+		if !ok || len(clips) == 0 {
+			continue
+		}
+		s.markPlayed(event.A, kind)
+		s.markPlayed(event.B, kind)
+		clip := clips[surfaceSoundPick(event.Tick, event.A, len(clips))]
+		s.emit(cmd, SurfaceSoundEvent{Kind: kind, Entity: event.A, Surface: surface, Clip: clip, Position: event.Point, Volume: loudness * defaulted(bank.Volume, 1)})
+		emitted++
+	}
+	return emitted
+}
+
+// EmitWaterImpacts plays the water bank's impacts for bodies entering water
+// fast enough.
+func (s *SurfaceSoundState) EmitWaterImpacts(cmd *Commands, impacts []WaterImpactEvent) int {
+	if s == nil {
+		return 0
+	}
+	cfg := s.Config
+	emitted := 0
+	for i, impact := range impacts {
+		if impact.Kind != WaterDisturbanceImpact || impact.Speed < cfg.WaterImpactMinSpeed || s.limited(impact.BodyEntity, SurfaceSoundImpact, cfg.ImpactInterval) {
+			continue
+		}
+		surface, bank, ok := s.Table.Bank("water")
+		if !ok || len(bank.Impacts) == 0 {
+			continue
+		}
+		s.markPlayed(impact.BodyEntity, SurfaceSoundImpact)
+		clip := bank.Impacts[surfaceSoundPick(uint64(s.clock*1000)+uint64(i), impact.BodyEntity, len(bank.Impacts))]
+		loudness := max(0.2, min(1, impact.Strength))
+		s.emit(cmd, SurfaceSoundEvent{Kind: SurfaceSoundImpact, Entity: impact.BodyEntity, Surface: surface, Clip: clip, Position: impact.Position, Volume: loudness * defaulted(bank.Volume, 1)})
+		emitted++
+	}
+	return emitted
+}
+
+func (s *SurfaceSoundState) limited(eid EntityId, kind SurfaceSoundKind, interval float32) bool {
+	at, ok := s.lastSound[surfaceSoundLimitKey{eid, kind}]
+	return ok && s.clock-at < float64(interval)
+}
+
+func (s *SurfaceSoundState) markPlayed(eid EntityId, kind SurfaceSoundKind) {
+	s.lastSound[surfaceSoundLimitKey{eid, kind}] = s.clock
+}
+
+func (s *SurfaceSoundState) emit(cmd *Commands, event SurfaceSoundEvent) {
+	s.Events = append(s.Events, event)
+	if cmd == nil {
+		return
+	}
+	if audio := audioStateFromApp(cmd.app); audio != nil {
+		audio.PlayAt(event.Clip, event.Position, event.Volume, 1, s.Config.Attenuation)
+	}
+}
+
+// surfaceSoundLoudness maps value from minValue..fullValue to 0.15..1.
+func surfaceSoundLoudness(value, minValue, fullValue float32) float32 {
+	if fullValue <= minValue {
+		return 1
+	}
+	t := (value - minValue) / (fullValue - minValue)
+	return 0.15 + 0.85*max(0, min(1, t))
+}
+
+// surfaceSoundPick is a deterministic clip index so replays sound the same.
+func surfaceSoundPick(tick uint64, eid EntityId, count int) int {
+	h := (tick*0x9E3779B97F4A7C15 ^ uint64(eid)*0xBF58476D1CE4E5B9) >> 33
+	return int(h % uint64(count))
+}
+
+// surfaceUnderFeet returns "water" when wading, otherwise the surface of the
+// geometry just below position.
+func surfaceUnderFeet(cmd *Commands, raycaster AudioRaycaster, position mgl32.Vec3) string {
+	if pointUnderwater(cmd, position.Add(mgl32.Vec3{0, 0.1, 0})) {
+		return "water"
+	}
+	if raycaster == nil {
+		return ""
+	}
+	const lift, reach = 0.25, 0.6
+	return surfaceForRaycastHit(cmd, raycaster.Raycast(position.Add(mgl32.Vec3{0, lift, 0}), mgl32.Vec3{0, -1, 0}, lift+reach))
+}
+
+// surfaceForContact prefers a SurfaceComponent on either body, then the
+// geometry at the contact point.
+func surfaceForContact(cmd *Commands, raycaster AudioRaycaster, event PhysicsCollisionEvent) string {
+	for _, eid := range [2]EntityId{event.A, event.B} {
+		if surface, ok := surfaceComponentForEntity(cmd, eid); ok {
+			return surface
+		}
+	}
+	if raycaster == nil || event.Normal.Len() < 1e-6 {
+		return ""
+	}
+	normal := event.Normal.Normalize()
+	const lift = 0.25
+	for _, dir := range [2]mgl32.Vec3{normal.Mul(-1), normal} {
+		hit := raycaster.Raycast(event.Point.Sub(dir.Mul(lift)), dir, 2*lift)
+		if surface := surfaceForRaycastHit(cmd, hit); surface != "" {
+			return surface
+		}
+	}
+	return ""
+}
+
+// surfaceForRaycastHit resolves the hit entity's SurfaceComponent or
+// imported-world material. Entity zero is an unmapped voxel object.
+func surfaceForRaycastHit(cmd *Commands, hit RaycastHit) string {
+	if !hit.Hit || hit.Entity == 0 {
+		return ""
+	}
+	if surface, ok := surfaceComponentForEntity(cmd, hit.Entity); ok {
+		return surface
+	}
+	if cmd == nil {
+		return ""
+	}
+	if material, ok := ImportedWorldMaterialForRaycastHit(cmd, streamedLevelRuntimeStateFromApp(cmd.app), hit); ok {
+		return SurfaceForImportedWorldMaterial(material.Material)
+	}
+	return ""
+}
+
+func surfaceComponentForEntity(cmd *Commands, eid EntityId) (string, bool) {
+	if cmd == nil {
+		return "", false
+	}
+	for _, comp := range cmd.GetAllComponents(eid) {
+		switch c := comp.(type) {
+		case *SurfaceComponent:
+			return c.Surface, c.Surface != ""
+		case SurfaceComponent:
+			return c.Surface, c.Surface != ""
+		}
+	}
+	return "", false
+}
+
+type SurfaceSoundModule struct {
+	// Table defaults to an empty table; DefaultHL1SurfaceSoundTable covers
+	// imported HL1 levels.
+	Table  *SurfaceSoundTable
+	Config SurfaceSoundConfig
+}
+
+func (mod SurfaceSoundModule) Install(app *App, cmd *Commands) {
+	cmd.AddResources(NewSurfaceSoundState(mod.Table, mod.Config))
+	app.UseSystem(
+		System(surfaceSoundSystem).
+			InStage(PostUpdate).
+			RunAlways(),
+	)
+}
+
+// surfaceSoundSystem runs after movement so footsteps see this frame's
+// positions, and picks up water impacts before they are cleared in
+// PreRender. Last frame's events are dropped first.
+func surfaceSoundSystem(time *Time, cmd *Commands, sounds *SurfaceSoundState) {
+	sounds.Events = sounds.Events[:0]
+	dt := float32(time.Dt)
+	sounds.Advance(dt)
+	var raycaster AudioRaycaster
+	if voxRt := voxelRtStateFromApp(cmd.app); voxRt != nil {
+		raycaster = voxRt
+	}
+	sounds.UpdateFootsteps(cmd, raycaster, dt)
+	if resource, ok := cmd.app.resources[reflect.TypeOf(WaterInteractionState{})]; ok {
+		sounds.EmitWaterImpacts(cmd, resource.(*WaterInteractionState).ImpactEvents())
+	}
+}
@@ -0,0 +1,172 @@
+package gekko
+
+import (
+	"testing"
+
+	rootaudio "github.com/gekko3d/gekko/audio"
+	"github.com/gekko3d/gekko/content"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// floorAudioRaycaster reports every downward ray hitting floor at y = 0.
+type floorAudioRaycaster struct {
+	floor EntityId
+}
+
+func (r floorAudioRaycaster) Raycast(origin, dir mgl32.Vec3, tMax float32) RaycastHit {
+	if dir.Y() >= 0 || origin.Y() < 0 {
+		return RaycastHit{}
+	}
+	t := origin.Y() / -dir.Y()
+	if t > tMax {
+		return RaycastHit{}
+	}
+	return RaycastHit{Hit: true, T: t, Normal: mgl32.Vec3{0, 1, 0}, Entity: r.floor}
+}
+
+func testSurfaceSoundTable() *SurfaceSoundTable {
+	table := NewSurfaceSoundTable("concrete")
+	table.SetBank("concrete", SurfaceSoundBank{Footsteps: []string{"step1.wav", "step2.wav"}, Impacts: []string{"hit.wav"}, Scrapes: []string{"scrape.wav"}})
+	table.SetBank("metal", SurfaceSoundBank{Footsteps: []string{"metal1.wav", "metal2.wav"}, Impacts: []string{"clang.wav"}})
+	table.SetBank("water", SurfaceSoundBank{Footsteps: []string{"slosh.wav"}, Impacts: []string{"splash.wav"}})
+	return table
+}
+
+func TestSurfaceForImportedWorldMaterialPrefersSurfaceTags(t *testing.T) {
+	tagged := content.ImportedWorldMaterialDef{Kind: "structural", Tags: []string{"source:hl1", "surface:Grate"}}
+	if got := SurfaceForImportedWorldMaterial(tagged); got != "grate" {
+		t.Fatalf("expected the materials.txt surface tag, got %q", got)
+	}
+	if got := SurfaceForImportedWorldMaterial(content.ImportedWorldMaterialDef{Kind: "glass"}); got != "glass" {
+		t.Fatalf("expected a surface from the material kind, got %q", got)
+	}
+	if got := SurfaceForImportedWorldMaterial(content.ImportedWorldMaterialDef{Tags: []string{"material:masonry"}}); got != "concrete" {
+		t.Fatalf("expected a surface from the material tags, got %q", got)
+	}
+	if got := SurfaceForImportedWorldMaterial(content.ImportedWorldMaterialDef{Kind: "structural"}); got != "" {
+		t.Fatalf("expected no surface for a plain structural material, got %q", got)
+	}
+}
+
+func TestSurfaceSoundFootstepsFollowStrideAndSurface(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	cmd.AddEntity(&TransformComponent{})
+	floor := cmd.AddEntity(&SurfaceComponent{Surface: "metal"})
+	player := cmd.AddEntity(
+		&TransformComponent{Rotation: mgl32.QuatIdent()},
+		&GroundedPlayerControllerComponent{Grounded: true},
+	)
+	app.FlushCommands()
+	tr, _ := transformForEntity(cmd, player)
+	sounds := NewSurfaceSoundState(testSurfaceSoundTable(), SurfaceSoundConfig{StrideLength: 1})
+	raycaster := floorAudioRaycaster{floor: floor}
+
+	sounds.UpdateFootsteps(cmd, raycaster, 0.1)
+	for i := 0; i < 5; i++ {
+		tr.Position = tr.Position.Add(mgl32.Vec3{0.45, 0, 0})
+		sounds.UpdateFootsteps(cmd, raycaster, 0.1)
+	}
+	events := sounds.Drain()
+	if len(events) != 2 {
+		t.Fatalf("expected two footsteps over 2.25m with a 1m stride, got %+v", events)
+	}
+	if events[0].Surface != "metal" || events[0].Clip != "metal1.wav" || events[1].Clip != "metal2.wav" {
+		t.Fatalf("expected alternating metal footsteps, got %+v", events)
+	}
+
+	// Falling for a while and landing plays a landing step.
+	ctrl := groundedControllerForTest(cmd, player)
+	ctrl.Grounded = false
+	for i := 0; i < 4; i++ {
+		sounds.UpdateFootsteps(cmd, raycaster, 0.1)
+	}
+	ctrl.Grounded = true
+	sounds.UpdateFootsteps(cmd, raycaster, 0.1)
+	events = sounds.Drain()
+	if len(events) != 1 || events[0].Kind != SurfaceSoundLanding || events[0].Volume != sounds.Config.LandingVolume {
+		t.Fatalf("expected one landing, got %+v", events)
+	}
+}
+
+func TestSurfaceSoundImpactsScaleAndRateLimit(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	crate := cmd.AddEntity(&SurfaceComponent{Surface: "metal"})
+	other := cmd.AddEntity(&TransformComponent{})
+	ground := cmd.AddEntity(&TransformComponent{})
+	app.FlushCommands()
+	sounds := NewSurfaceSoundState(testSurfaceSoundTable(), SurfaceSoundConfig{})
+
+	soft := PhysicsCollisionEvent{Type: CollisionEventEnter, A: crate, B: other, NormalImpulse: 2, Tick: 1}
+	hard := PhysicsCollisionEvent{Type: CollisionEventEnter, A: crate, B: other, NormalImpulse: 40, Tick: 2}
+	quiet := PhysicsCollisionEvent{Type: CollisionEventEnter, A: other, B: ground, NormalImpulse: 0.1, Tick: 3}
+	if n := sounds.EmitImpacts(cmd, []PhysicsCollisionEvent{quiet, soft, hard}); n != 1 {
+		t.Fatalf("expected the tiny impact skipped and the repeat rate-limited, got %d", n)
+	}
+	events := sounds.Drain()
+	if events[0].Surface != "metal" || events[0].Clip != "clang.wav" || events[0].Volume >= 0.5 {
+		t.Fatalf("expected a quiet metal clang, got %+v", events[0])
+	}
+
+	sounds.Advance(0.2)
+	sounds.EmitImpacts(cmd, []PhysicsCollisionEvent{hard})
+	if events = sounds.Drain(); len(events) != 1 || events[0].Volume != 1 {
+		t.Fatalf("expected a full-volume impact after the interval, got %+v", events)
+	}
+
+	scrape := PhysicsCollisionEvent{Type: CollisionEventStay, A: other, B: ground, RelativeSpeed: 3}
+	sounds.EmitImpacts(cmd, []PhysicsCollisionEvent{scrape})
+	if events = sounds.Drain(); len(events) != 1 || events[0].Kind != SurfaceSoundScrape || events[0].Surface != "concrete" {
+		t.Fatalf("expected a default-surface scrape, got %+v", events)
+	}
+}
+
+func TestSurfaceSoundSystemClearsLastFrameEvents(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	crate := cmd.AddEntity(&SurfaceComponent{Surface: "metal"})
+	other := cmd.AddEntity(&TransformComponent{})
+	app.FlushCommands()
+	sounds := NewSurfaceSoundState(testSurfaceSoundTable(), SurfaceSoundConfig{})
+
+	hit := PhysicsCollisionEvent{Type: CollisionEventEnter, A: crate, B: other, NormalImpulse: 40, Tick: 1}
+	sounds.EmitImpacts(cmd, []PhysicsCollisionEvent{hit})
+	if len(sounds.Events) != 1 {
+		t.Fatalf("expected one impact event, got %d", len(sounds.Events))
+	}
+	surfaceSoundSystem(&Time{Dt: 1.0 / 60}, cmd, sounds)
+	if len(sounds.Events) != 0 {
+		t.Fatalf("expected undrained events to be dropped on the next frame, got %+v", sounds.Events)
+	}
+}
+
+func TestSurfaceSoundsPlayThroughAudioState(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	audio := NewAudioState(&rootaudio.NullBackend{}, nil)
+	audio.clips["splash.wav"] = testAudioClip(4800)
+	cmd.AddResources(audio)
+	app.FlushCommands()
+	sounds := NewSurfaceSoundState(testSurfaceSoundTable(), SurfaceSoundConfig{})
+
+	impacts := []WaterImpactEvent{
+		{BodyEntity: 7, Speed: 4, Strength: 0.8, Kind: WaterDisturbanceImpact},
+		{BodyEntity: 8, Speed: 4, Strength: 0.8, Kind: WaterDisturbanceSkim},
+	}
+	if n := sounds.EmitWaterImpacts(cmd, impacts); n != 1 {
+		t.Fatalf("expected only the impact to splash, got %d", n)
+	}
+	if audio.Mixer.ActiveVoices() != 1 {
+		t.Fatalf("expected the splash to start a voice, got %d", audio.Mixer.ActiveVoices())
+	}
+}
+
+func groundedControllerForTest(cmd *Commands, eid EntityId) *GroundedPlayerControllerComponent {
+	for _, comp := range cmd.GetAllComponents(eid) {
+		if ctrl, ok := comp.(*GroundedPlayerControllerComponent); ok {
+			return ctrl
+		}
+	}
+	return nil
+}
@@ -15,7 +15,8 @@ const (
 type UiModule struct{}
 
 func (UiModule) Install(app *App, cmd *Commands) {
-	cmd.AddResources(newUiRuntime())
+	cmd.AddResources(newUiRuntime(), newUiDocuments())
+	app.UseSystem(System(uiDocumentReloadSystem).InStage(PreUpdate).RunAlways())
 	app.UseSystem(System(uiPanelInputSystem).InStage(PreUpdate).RunAlways())
 	app.UseSystem(System(uiPanelRenderSystem).InStage(PostUpdate).RunAlways())
 }
@@ -0,0 +1,633 @@
+package gekko
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gekko3d/gekko/content"
+)
+
+// uiDocumentPollInterval is how often, in seconds, document files are
+// checked for changes on disk.
+const uiDocumentPollInterval = 0.5
+
+// UiDocumentComponent shows a UI document file as a panel owned by the
+// entity. The file is reloaded when it changes on disk. Slots fill the
+// document's slot elements, Actions back its action names and Theme
+// overrides the document's default theme.
+type UiDocumentComponent struct {
+	Path    string
+	Theme   string
+	Slots   map[string][]UiNode
+	Actions map[string]func()
+}
+
+// UiDocuments caches UI documents by path. A reload that fails to load or
+// validate keeps the last good document on screen.
+type UiDocuments struct {
+	entries map[string]*uiDocumentEntry
+	warned  map[string]bool
+}
+
+type uiDocumentEntry struct {
+	def      *content.UiDocumentDef
+	modTime  time.Time
+	err      error
+	nextPoll float64
+}
+
+func newUiDocuments() *UiDocuments {
+	return &UiDocuments{
+		entries: make(map[string]*uiDocumentEntry),
+		warned:  make(map[string]bool),
+	}
+}
+
+// Status reports whether path has a document to show and the error from the
+// last load. Validation failures are content.UiDocumentValidationResult.
+func (d *UiDocuments) Status(path string) (bool, error) {
+	entry, ok := d.entries[path]
+	if !ok {
+		return false, nil
+	}
+	return entry.def != nil, entry.err
+}
+
+func (d *UiDocuments) document(path string) *content.UiDocumentDef {
+	if entry, ok := d.entries[path]; ok {
+		return entry.def
+	}
+	return nil
+}
+
+// poll loads path on first use and reloads it once its modification time
+// changes.
+func (d *UiDocuments) poll(path string, now float64) {
+	entry, ok := d.entries[path]
+	if !ok {
+		entry = &uiDocumentEntry{}
+		d.entries[path] = entry
+	} else if now < entry.nextPoll {
+		return
+	}
+	entry.nextPoll = now + uiDocumentPollInterval
+
+	info, err := os.Stat(path)
+	if err != nil {
+		d.fail(path, entry, err)
+		return
+	}
+	if ok && info.ModTime().Equal(entry.modTime) {
+		return
+	}
+	entry.modTime = info.ModTime()
+
+	def, err := content.LoadUiDocument(path)
+	if err != nil {
+		d.fail(path, entry, err)
+		return
+	}
+	if result := content.ValidateUiDocument(def); result.HasErrors() {
+		d.fail(path, entry, result)
+		return
+	}
+	entry.def = def
+	entry.err = nil
+	clear(d.warned)
+}
+
+// fail records err and logs it only when it differs from the previous
+// error, so a file that stays broken or missing is reported once.
+func (d *UiDocuments) fail(path string, entry *uiDocumentEntry, err error) {
+	changed := entry.err == nil || entry.err.Error() != err.Error()
+	entry.err = err
+	if changed {
+		fmt.Printf("WARNING: ui document %s: %v\n", path, err)
+	}
+}
+
+// warnOnce logs a binding that cannot be resolved without repeating it every
+// frame. Warnings reset when a document reloads.
+func (d *UiDocuments) warnOnce(key string, err error) {
+	if d.warned[key] {
+		return
+	}
+	d.warned[key] = true
+	fmt.Printf("WARNING: %v\n", err)
+}
+
+func uiDocumentReloadSystem(docs *UiDocuments, t *Time, cmd *Commands) {
+	if docs == nil {
+		return
+	}
+	now := float64(0)
+	if t != nil {
+		now = t.Elapsed
+	}
+	MakeQuery1[UiDocumentComponent](cmd).Map(func(eid EntityId, doc *UiDocumentComponent) bool {
+		if doc.Path != "" {
+			docs.poll(doc.Path, now)
+		}
+		return true
+	})
+}
+
+// uiDocumentPanels compiles every loaded document. Compiling reads bindings,
+// so it runs for both the input and the render pass.
+func uiDocumentPanels(panels []uiPanelRef, docs *UiDocuments, cmd *Commands) []uiPanelRef {
+	if docs == nil {
+		return panels
+	}
+	MakeQuery1[UiDocumentComponent](cmd).Map(func(eid EntityId, doc *UiDocumentComponent) bool {
+		def := docs.document(doc.Path)
+		if def == nil {
+			return true
+		}
+		compiler := uiDocumentCompiler{def: def, doc: doc, docs: docs, scope: uiBindingScope{cmd: cmd, eid: eid}}
+		if panel := compiler.panel(); panelVisible(panel) {
+			panels = append(panels, uiPanelRef{eid: eid, panel: panel})
+		}
+		return true
+	})
+	return panels
+}
+
+type uiDocumentCompiler struct {
+	def   *content.UiDocumentDef
+	doc   *UiDocumentComponent
+	docs  *UiDocuments
+	scope uiBindingScope
+}
+
+func (c uiDocumentCompiler) theme() string {
+	if c.doc != nil && c.doc.Theme != "" {
+		return c.doc.Theme
+	}
+	return c.def.Theme
+}
+
+func (c uiDocumentCompiler) panel() *UiPanel {
+	root := &c.def.Root
+	style := c.def.ResolveStyle(root, c.theme())
+	key := root.Key
+	if key == "" {
+		key = c.def.Name
+	}
+	return &UiPanel{
+		Key:        key,
+		Anchor:     uiDocumentAnchor(root.Anchor),
+		Position:   root.Position,
+		Width:      uiStyleFloat(style.Width),
+		MaxHeight:  root.MaxHeight,
+		Padding:    uiStyleFloat(style.Padding),
+		Spacing:    uiStyleFloat(style.Spacing),
+		Scale:      uiStyleFloat(style.Scale),
+		Title:      c.text(root, content.UiBindText, root.Text),
+		Visible:    c.bool(root, content.UiBindVisible, true),
+		Borderless: style.Borderless != nil && *style.Borderless,
+		BgColor:    uiStyleColor(style.BgColor),
+		TextColor:  uiStyleColor(style.TextColor),
+		Modal:      root.Modal,
+		Navigable:  root.Navigable,
+		Children:   c.nodes(root.Children),
+	}
+}
+
+// nodes compiles children in order, splicing slot contents in place and
+// dropping elements whose visible binding is false.
+func (c uiDocumentCompiler) nodes(elements []content.UiElementDef) []UiNode {
+	nodes := make([]UiNode, 0, len(elements))
+	for i := range elements {
+		element := &elements[i]
+		if !c.bool(element, content.UiBindVisible, true) {
+			continue
+		}
+		if element.Type == content.UiElementSlot {
+			if c.doc != nil {
+				nodes = append(nodes, c.doc.Slots[element.Slot]...)
+			}
+			continue
+		}
+		if node := c.node(element); node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+func (c uiDocumentCompiler) node(element *content.UiElementDef) UiNode {
+	style := c.def.ResolveStyle(element, c.theme())
+	width, scale := uiStyleFloat(style.Width), uiStyleFloat(style.Scale)
+	action := c.action(element)
+
+	switch element.Type {
+	case content.UiElementColumn:
+		return UiColumn{Key: element.Key, Spacing: uiStyleFloat(style.Spacing), Children: c.nodes(element.Children)}
+	case content.UiElementRow:
+		return UiRow{Key: element.Key, Spacing: uiStyleFloat(style.Spacing), Children: c.nodes(element.Children)}
+	case content.UiElementZStack:
+		return UiZStack{Key: element.Key, Children: c.nodes(element.Children)}
+	case content.UiElementSpacer:
+		return UiSpacer{Height: element.Height}
+	case content.UiElementLabel:
+		return UiLabel{
+			Key:   element.Key,
+			Text:  c.text(element, content.UiBindText, element.Text),
+			Width: width,
+			Scale: scale,
+			Color: c.color(element, content.UiBindColor, uiStyleColor(style.Color)),
+		}
+	case content.UiElementButton:
+		return UiButtonControl{
+			Key:     element.Key,
+			Label:   c.text(element, content.UiBindText, element.Text),
+			Width:   width,
+			Scale:   scale,
+			Align:   uiDocumentAlign(style.Align),
+			OnClick: action,
+		}
+	case content.UiElementCheckbox:
+		set := c.setter(element, content.UiBindChecked)
+		return UiCheckbox{
+			Key:      element.Key,
+			Label:    c.text(element, content.UiBindText, element.Text),
+			Checked:  c.bool(element, content.UiBindChecked, element.Checked),
+			Width:    width,
+			Scale:    scale,
+			OnChange: func(v bool) { set(v); action() },
+		}
+	case content.UiElementSlider:
+		set := c.setter(element, content.UiBindValue)
+		return UiSlider{
+			Key:       element.Key,
+			Value:     c.float(element, content.UiBindValue, element.Value),
+			Min:       c.float(element, content.UiBindMin, element.Min),
+			Max:       c.float(element, content.UiBindMax, element.Max),
+			Step:      element.Step,
+			Precision: element.Precision,
+			Width:     width,
+			Scale:     scale,
+			OnChange:  func(v float32) { set(v) },
+			OnCommit:  func(float32) { action() },
+		}
+	case content.UiElementProgress:
+		return UiProgressBar{
+			Key:       element.Key,
+			Label:     c.text(element, content.UiBindText, element.Text),
+			Value:     c.float(element, content.UiBindValue, element.Value),
+			Min:       c.float(element, content.UiBindMin, element.Min),
+			Max:       c.float(element, content.UiBindMax, element.Max),
+			Width:     width,
+			Scale:     scale,
+			Precision: element.Precision,
+		}
+	case content.UiElementTextField:
+		set := c.setter(element, content.UiBindValue)
+		return UiTextField{
+			Key:         element.Key,
+			Value:       c.text(element, content.UiBindValue, element.Text),
+			Placeholder: element.Placeholder,
+			Width:       width,
+			Scale:       scale,
+			OnCommit:    func(v string) { set(v); action() },
+		}
+	case content.UiElementNumberField:
+		set := c.setter(element, content.UiBindValue)
+		return UiNumberField{
+			Key:         element.Key,
+			Value:       c.float(element, content.UiBindValue, element.Value),
+			Precision:   element.Precision,
+			Placeholder: element.Placeholder,
+			Width:       width,
+			Scale:       scale,
+			OnCommit:    func(v float32) { set(v); action() },
+		}
+	case content.UiElementSelect:
+		set := c.setter(element, content.UiBindSelected)
+		return UiSelectCycle{
+			Key:      element.Key,
+			Options:  c.strings(element, content.UiBindOptions, element.Options),
+			Selected: c.int(element, content.UiBindSelected, int(element.Value)),
+			Width:    width,
+			Scale:    scale,
+			Align:    uiDocumentAlign(style.Align),
+			OnChange: func(v int) { set(v); action() },
+		}
+	case content.UiElementDropdown:
+		set := c.setter(element, content.UiBindSelected)
+		return UiDropdown{
+			Key:         element.Key,
+			Options:     c.strings(element, content.UiBindOptions, element.Options),
+			Selected:    c.int(element, content.UiBindSelected, int(element.Value)),
+			Placeholder: element.Placeholder,
+			Width:       width,
+			Scale:       scale,
+			OnChange:    func(v int) { set(v); action() },
+		}
+	case content.UiElementList:
+		set := c.setter(element, content.UiBindSelected)
+		return UiList{
+			Key:      element.Key,
+			Items:    c.strings(element, content.UiBindOptions, element.Options),
+			Selected: c.int(element, content.UiBindSelected, int(element.Value)),
+			Rows:     element.Rows,
+			Width:    width,
+			Scale:    scale,
+			OnSelect: func(v int) { set(v); action() },
+		}
+	case content.UiElementTabs:
+		set := c.setter(element, content.UiBindSelected)
+		tabs := UiTabs{
+			Key:      element.Key,
+			Selected: c.int(element, content.UiBindSelected, int(element.Value)),
+			Spacing:  uiStyleFloat(style.Spacing),
+			Scale:    scale,
+			OnChange: func(v int) { set(v); action() },
+		}
+		for i := range element.Children {
+			tab := &element.Children[i]
+			if !c.bool(tab, content.UiBindVisible, true) {
+				continue
+			}
+			tabs.Tabs = append(tabs.Tabs, UiTab{Label: c.text(tab, content.UiBindText, tab.Text), Content: uiDocumentContent(c.nodes(tab.Children))})
+		}
+		return tabs
+	case content.UiElementTooltip:
+		return UiTooltip{
+			Key:   element.Key,
+			Text:  c.text(element, content.UiBindText, element.Text),
+			Scale: scale,
+			Child: uiDocumentContent(c.nodes(element.Children)),
+		}
+	}
+	return nil
+}
+
+// action returns the handler for the element's action name. It is never nil
+// so widget callbacks can always call it.
+func (c uiDocumentCompiler) action(element *content.UiElementDef) func() {
+	if element.Action != "" && c.doc != nil {
+		if handler := c.doc.Actions[element.Action]; handler != nil {
+			return handler
+		}
+	}
+	return func() {}
+}
+
+// setter writes a widget change back to the bound field. The binding is
+// resolved again when the change happens, since components can move between
+// frames.
+func (c uiDocumentCompiler) setter(element *content.UiElementDef, property string) func(any) {
+	path := element.Bind[property]
+	if path == "" {
+		return func(any) {}
+	}
+	return func(value any) {
+		target, err := c.scope.resolve(path)
+		if err == nil {
+			err = uiBindingSet(target, value)
+		}
+		if err != nil {
+			c.docs.warnOnce(c.doc.Path+"|"+path, err)
+		}
+	}
+}
+
+func (c uiDocumentCompiler) bound(element *content.UiElementDef, property string) (reflect.Value, bool) {
+	path := element.Bind[property]
+	if path == "" {
+		return reflect.Value{}, false
+	}
+	value, err := c.scope.resolve(path)
+	if err != nil {
+		c.docs.warnOnce(c.doc.Path+"|"+path, err)
+		return reflect.Value{}, false
+	}
+	return value, true
+}
+
+func (c uiDocumentCompiler) text(element *content.UiElementDef, property string, fallback string) string {
+	if value, ok := c.bound(element, property); ok && value.CanInterface() {
+		return fmt.Sprint(value.Interface())
+	}
+	return fallback
+}
+
+func (c uiDocumentCompiler) float(element *content.UiElementDef, property string, fallback float32) float32 {
+	if value, ok := c.bound(element, property); ok {
+		switch value.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return float32(value.Float())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return float32(value.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return float32(value.Uint())
+		}
+	}
+	return fallback
+}
+
+func (c uiDocumentCompiler) int(element *content.UiElementDef, property string, fallback int) int {
+	if _, ok := element.Bind[property]; !ok {
+		return fallback
+	}
+	return int(c.float(element, property, float32(fallback)))
+}
+
+func (c uiDocumentCompiler) bool(element *content.UiElementDef, property string, fallback bool) bool {
+	if value, ok := c.bound(element, property); ok && value.Kind() == reflect.Bool {
+		return value.Bool()
+	}
+	return fallback
+}
+
+func (c uiDocumentCompiler) strings(element *content.UiElementDef, property string, fallback []string) []string {
+	value, ok := c.bound(element, property)
+	if !ok || (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) {
+		return fallback
+	}
+	items := make([]string, value.Len())
+	for i := range items {
+		items[i] = fmt.Sprint(value.Index(i).Interface())
+	}
+	return items
+}
+
+func (c uiDocumentCompiler) color(element *content.UiElementDef, property string, fallback [4]float32) [4]float32 {
+	value, ok := c.bound(element, property)
+	if !ok || (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) || value.Len() != 4 {
+		return fallback
+	}
+	var color [4]float32
+	for i := range color {
+		component := value.Index(i)
+		if component.Kind() != reflect.Float32 && component.Kind() != reflect.Float64 {
+			return fallback
+		}
+		color[i] = float32(component.Float())
+	}
+	return color
+}
+
+// uiBindingScope resolves binding paths against ECS resources and the
+// components of the document's entity.
+type uiBindingScope struct {
+	cmd *Commands
+	eid EntityId
+}
+
+func (s uiBindingScope) resolve(path string) (reflect.Value, error) {
+	kind, rest, _ := strings.Cut(path, ":")
+	typeName, fieldPath, _ := strings.Cut(rest, ".")
+
+	var value reflect.Value
+	switch kind + ":" {
+	case content.UiBindingResourcePrefix:
+		value = s.resource(typeName)
+	case content.UiBindingComponentPrefix:
+		value = s.component(typeName)
+	default:
+		return reflect.Value{}, fmt.Errorf("binding %q has no resource or component prefix", path)
+	}
+	if !value.IsValid() {
+		return reflect.Value{}, fmt.Errorf("binding %q: no %s of type %s", path, kind, typeName)
+	}
+
+	for _, segment := range strings.Split(fieldPath, ".") {
+		for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return reflect.Value{}, fmt.Errorf("binding %q: nil value before %q", path, segment)
+			}
+			value = value.Elem()
+		}
+		switch value.Kind() {
+		case reflect.Struct:
+			field, ok := value.Type().FieldByName(segment)
+			if !ok || !field.IsExported() {
+				return reflect.Value{}, fmt.Errorf("binding %q: %s has no exported field %q", path, value.Type(), segment)
+			}
+			value = value.FieldByIndex(field.Index)
+		case reflect.Slice, reflect.Array:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= value.Len() {
+				return reflect.Value{}, fmt.Errorf("binding %q: index %q out of range", path, segment)
+			}
+			value = value.Index(index)
+		case reflect.Map:
+			if value.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, fmt.Errorf("binding %q: map keys of %s are not strings", path, value.Type())
+			}
+			value = value.MapIndex(reflect.ValueOf(segment).Convert(value.Type().Key()))
+			if !value.IsValid() {
+				return reflect.Value{}, fmt.Errorf("binding %q: no map entry %q", path, segment)
+			}
+		default:
+			return reflect.Value{}, fmt.Errorf("binding %q: cannot read %q from %s", path, segment, value.Type())
+		}
+	}
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+	return value, nil
+}
+
+func (s uiBindingScope) resource(typeName string) reflect.Value {
+	app := s.cmd.app
+	app.cmdMutex.Lock()
+	defer app.cmdMutex.Unlock()
+	for resourceType, resource := range app.resources {
+		if resourceType.Name() == typeName {
+			return reflect.ValueOf(resource)
+		}
+	}
+	return reflect.Value{}
+}
+
+func (s uiBindingScope) component(typeName string) reflect.Value {
+	for _, component := range s.cmd.GetAllComponents(s.eid) {
+		value := reflect.ValueOf(component)
+		if value.Kind() == reflect.Pointer && value.Elem().Type().Name() == typeName {
+			return value
+		}
+	}
+	return reflect.Value{}
+}
+
+// uiBindingSet writes a widget value to a bound field of a matching kind.
+func uiBindingSet(target reflect.Value, value any) error {
+	if !target.CanSet() {
+		return fmt.Errorf("bound %s field is read-only", target.Type())
+	}
+	source := reflect.ValueOf(value)
+	if uiBindingKindClass(source.Kind()) != uiBindingKindClass(target.Kind()) || !source.CanConvert(target.Type()) {
+		return fmt.Errorf("cannot write %s to bound %s field", source.Type(), target.Type())
+	}
+	target.Set(source.Convert(target.Type()))
+	return nil
+}
+
+func uiBindingKindClass(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	default:
+		return kind.String()
+	}
+}
+
+func uiDocumentContent(nodes []UiNode) UiNode {
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+	return UiColumn{Children: nodes}
+}
+
+func uiDocumentAlign(align string) UiTextAlign {
+	switch align {
+	case "left":
+		return UiTextAlignLeft
+	case "center":
+		return UiTextAlignCenter
+	case "right":
+		return UiTextAlignRight
+	}
+	return UiTextAlignDefault
+}
+
+func uiDocumentAnchor(anchor string) UiAnchor {
+	switch anchor {
+	case "top_right":
+		return UiAnchorTopRight
+	case "bottom_left":
+		return UiAnchorBottomLeft
+	case "bottom_right":
+		return UiAnchorBottomRight
+	case "top_center":
+		return UiAnchorTopCenter
+	case "bottom_center":
+		return UiAnchorBottomCenter
+	case "center":
+		return UiAnchorCenter
+	}
+	return UiAnchorTopLeft
+}
+
+func uiStyleFloat(value *float32) float32 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+func uiStyleColor(value *[4]float32) [4]float32 {
+	if value == nil {
+		return [4]float32{}
+	}
+	return *value
+}
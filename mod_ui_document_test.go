@@ -0,0 +1,185 @@
+package gekko
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gekko3d/gekko/content"
+)
+
+type uiDocumentTestSettings struct {
+	Volume     float32
+	Fullscreen bool
+	Modes      []string
+}
+
+type uiDocumentTestHealth struct {
+	Current int
+}
+
+func writeUiDocumentTestFile(t *testing.T, path string, def *content.UiDocumentDef, modTime time.Time) {
+	t.Helper()
+	if err := content.SaveUiDocument(path, def); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+}
+
+func uiDocumentTestDef(title string) *content.UiDocumentDef {
+	small, large := float32(0.8), float32(1.5)
+	return &content.UiDocumentDef{
+		SchemaVersion: content.CurrentUiDocumentSchemaVersion,
+		Name:          "settings",
+		Styles:        map[string]content.UiStyleDef{"caption": {Scale: &small}},
+		Themes:        map[string]content.UiThemeDef{"large": {Styles: map[string]content.UiStyleDef{"caption": {Scale: &large}}}},
+		Root: content.UiElementDef{Type: content.UiElementPanel, Text: title, Children: []content.UiElementDef{
+			{Type: content.UiElementLabel, Key: "hp", Style: "caption", Bind: map[string]string{content.UiBindText: "component:uiDocumentTestHealth.Current"}},
+			{Type: content.UiElementSlider, Key: "volume", Max: 1, Action: "apply", Bind: map[string]string{content.UiBindValue: "resource:uiDocumentTestSettings.Volume"}},
+			{Type: content.UiElementCheckbox, Key: "fullscreen", Bind: map[string]string{
+				content.UiBindChecked: "resource:uiDocumentTestSettings.Fullscreen",
+			}},
+			{Type: content.UiElementDropdown, Key: "mode", Bind: map[string]string{
+				content.UiBindOptions: "resource:uiDocumentTestSettings.Modes",
+				content.UiBindVisible: "resource:uiDocumentTestSettings.Fullscreen",
+			}},
+			{Type: content.UiElementSlot, Slot: "footer"},
+		}},
+	}
+}
+
+func TestUiDocumentCompilesBindingsSlotsAndThemes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.ui.json")
+	writeUiDocumentTestFile(t, path, uiDocumentTestDef("Settings"), time.Unix(1000, 0))
+
+	app := NewApp()
+	cmd := app.Commands()
+	settings := &uiDocumentTestSettings{Volume: 0.25, Modes: []string{"Windowed", "Borderless"}}
+	docs := newUiDocuments()
+	cmd.AddResources(settings, docs)
+	applied := 0
+	eid := cmd.AddEntity(&UiDocumentComponent{
+		Path:    path,
+		Slots:   map[string][]UiNode{"footer": {UiLabel{Key: "hint", Text: "Esc to close"}}},
+		Actions: map[string]func(){"apply": func() { applied++ }},
+	}, &uiDocumentTestHealth{Current: 42})
+	app.FlushCommands()
+
+	uiDocumentReloadSystem(docs, &Time{}, cmd)
+	panels := uiVisiblePanels(cmd, docs)
+	if len(panels) != 1 || panels[0].eid != eid {
+		t.Fatalf("expected one document panel owned by the entity, got %+v", panels)
+	}
+	panel := panels[0].panel
+	if panel.Title != "Settings" || panel.Key != "settings" || len(panel.Children) != 4 {
+		t.Fatalf("unexpected panel %+v", panel)
+	}
+	label := panel.Children[0].(UiLabel)
+	if label.Text != "42" || label.Scale != 0.8 {
+		t.Fatalf("expected the component binding and caption style, got %+v", label)
+	}
+	if hint, ok := panel.Children[3].(UiLabel); !ok || hint.Key != "hint" {
+		t.Fatalf("expected the footer slot to be spliced in, got %#v", panel.Children[3])
+	}
+
+	slider := panel.Children[1].(UiSlider)
+	if slider.Value != 0.25 {
+		t.Fatalf("expected the resource binding, got %v", slider.Value)
+	}
+	slider.OnChange(0.75)
+	slider.OnCommit(0.75)
+	if settings.Volume != 0.75 || applied != 1 {
+		t.Fatalf("expected a two-way write and the action, got volume %v applied %d", settings.Volume, applied)
+	}
+	panel.Children[2].(UiCheckbox).OnChange(true)
+	if !settings.Fullscreen {
+		t.Fatalf("expected the checkbox to write back")
+	}
+
+	MakeQuery1[UiDocumentComponent](cmd).Map(func(_ EntityId, doc *UiDocumentComponent) bool {
+		doc.Theme = "large"
+		return true
+	})
+	panel = uiVisiblePanels(cmd, docs)[0].panel
+	if len(panel.Children) != 5 {
+		t.Fatalf("expected the visible binding to show the dropdown, got %d children", len(panel.Children))
+	}
+	if label := panel.Children[0].(UiLabel); label.Scale != 1.5 {
+		t.Fatalf("expected the component theme to apply, got %v", label.Scale)
+	}
+	if dropdown := panel.Children[3].(UiDropdown); len(dropdown.Options) != 2 || dropdown.Options[1] != "Borderless" {
+		t.Fatalf("expected bound options, got %+v", dropdown.Options)
+	}
+}
+
+func TestUiDocumentReloadKeepsLastGoodDocument(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.ui.json")
+	writeUiDocumentTestFile(t, path, uiDocumentTestDef("First"), time.Unix(1000, 0))
+
+	app := NewApp()
+	cmd := app.Commands()
+	docs := newUiDocuments()
+	cmd.AddResources(&uiDocumentTestSettings{}, docs)
+	cmd.AddEntity(&UiDocumentComponent{Path: path}, &uiDocumentTestHealth{})
+	app.FlushCommands()
+
+	title := func() string {
+		panels := uiVisiblePanels(cmd, docs)
+		if len(panels) != 1 {
+			t.Fatalf("expected one document panel, got %d", len(panels))
+		}
+		return panels[0].panel.Title
+	}
+
+	uiDocumentReloadSystem(docs, &Time{Elapsed: 0}, cmd)
+	writeUiDocumentTestFile(t, path, uiDocumentTestDef("Second"), time.Unix(2000, 0))
+	uiDocumentReloadSystem(docs, &Time{Elapsed: 0.1}, cmd)
+	if got := title(); got != "First" {
+		t.Fatalf("expected no reload before the poll interval, got %q", got)
+	}
+	uiDocumentReloadSystem(docs, &Time{Elapsed: 1}, cmd)
+	if got := title(); got != "Second" {
+		t.Fatalf("expected the changed file to reload, got %q", got)
+	}
+
+	invalid := uiDocumentTestDef("Third")
+	invalid.Root.Children[0].Type = "banner"
+	writeUiDocumentTestFile(t, path, invalid, time.Unix(3000, 0))
+	uiDocumentReloadSystem(docs, &Time{Elapsed: 2}, cmd)
+	if got := title(); got != "Second" {
+		t.Fatalf("expected an invalid reload to keep the last good document, got %q", got)
+	}
+	ok, err := docs.Status(path)
+	result, isValidation := err.(content.UiDocumentValidationResult)
+	if !ok || !isValidation || result.Issues[0].Code != "unknown_element_type" {
+		t.Fatalf("expected a validation error in the status, got %v %v", ok, err)
+	}
+}
+
+func TestUiBindingScopeResolvesPaths(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	cmd.AddResources(&uiDocumentTestSettings{Modes: []string{"a", "b"}})
+	eid := cmd.AddEntity(&uiDocumentTestHealth{Current: 7})
+	app.FlushCommands()
+	scope := uiBindingScope{cmd: cmd, eid: eid}
+
+	if value, err := scope.resolve("resource:uiDocumentTestSettings.Modes.1"); err != nil || value.String() != "b" {
+		t.Fatalf("expected a slice index to resolve, got %v %v", value, err)
+	}
+	target, err := scope.resolve("component:uiDocumentTestHealth.Current")
+	if err != nil || uiBindingSet(target, float32(3)) != nil || target.Int() != 3 {
+		t.Fatalf("expected a number write to an int field, got %v %v", target, err)
+	}
+	if uiBindingSet(target, "3") == nil {
+		t.Fatalf("expected a string write to an int field to fail")
+	}
+	for _, path := range []string{"resource:Missing.Value", "component:uiDocumentTestHealth.Max", "resource:uiDocumentTestSettings.Modes.5"} {
+		if _, err := scope.resolve(path); err == nil {
+			t.Fatalf("expected %q to fail", path)
+		}
+	}
+}
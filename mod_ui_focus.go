@@ -0,0 +1,438 @@
+package gekko
+
+import "math"
+
+// uiNavStickThreshold is how far the left stick must be pushed to count as a
+// directional press. Only the frame it crosses the threshold moves focus.
+const uiNavStickThreshold = 0.5
+
+var uiFocusRingColor = [4]float32{0.35, 0.8, 1, 1}
+
+type uiNavDir int
+
+const (
+	uiNavNone uiNavDir = iota
+	uiNavUp
+	uiNavDown
+	uiNavLeft
+	uiNavRight
+	uiNavNext
+	uiNavPrev
+)
+
+// uiFocusTarget is one focusable widget in tab order. Targets are collected
+// from the laid-out panels every frame, so the graph always matches what is
+// on screen.
+type uiFocusTarget struct {
+	id     string
+	eid    EntityId
+	layout *uiLayoutNode
+	root   *uiLayoutNode
+}
+
+func (t uiFocusTarget) panel() *UiPanel {
+	if t.root == nil || t.root.kind != uiNodePanel {
+		return nil
+	}
+	return t.root.node.(*UiPanel)
+}
+
+func uiFocusableKind(kind uiNodeKind) bool {
+	switch kind {
+	case uiNodeButton, uiNodeIconButton, uiNodeTextField, uiNodeNumberField, uiNodeSelectCycle,
+		uiNodeCheckbox, uiNodeSlider, uiNodeDropdown, uiNodeList, uiNodeTreeRow, uiNodeTab:
+		return true
+	}
+	return false
+}
+
+// uiCollectFocusTargets appends the focusable widgets under layout in
+// document order, which is also the tab order.
+func uiCollectFocusTargets(targets []uiFocusTarget, root *uiLayoutNode, layout *uiLayoutNode, eid EntityId) []uiFocusTarget {
+	if layout == nil {
+		return targets
+	}
+	if uiFocusableKind(layout.kind) {
+		targets = append(targets, uiFocusTarget{id: uiWidgetID(eid, layout.key), eid: eid, layout: layout, root: root})
+	}
+	for _, child := range layout.children {
+		targets = uiCollectFocusTargets(targets, root, child, eid)
+	}
+	return targets
+}
+
+func uiFindFocusTarget(targets []uiFocusTarget, id string) (uiFocusTarget, bool) {
+	if i := uiFocusTargetIndex(targets, id); i >= 0 {
+		return targets[i], true
+	}
+	return uiFocusTarget{}, false
+}
+
+func uiFocusTargetIndex(targets []uiFocusTarget, id string) int {
+	if id == "" {
+		return -1
+	}
+	for i, target := range targets {
+		if target.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// uiNavigate moves the navigation focus and activates widgets from
+// keyboard and gamepad input. Targets only come from navigable panels, so
+// with none on screen navigation leaves the input to gameplay. While a modal
+// is open the targets only cover the modal, which traps focus inside it; the
+// previous focus comes back when the modal closes.
+func uiNavigate(runtime *UiRuntime, input *Input, targets []uiFocusTarget, modal bool, editing bool) {
+	if input.JustPressed[MouseButtonLeft] {
+		runtime.focusVisible = false
+		for _, target := range targets {
+			layout := target.layout
+			if runtime.pointerOver(input, target.id, layout.x, layout.y, layout.w, layout.h) {
+				runtime.navFocus = target.id
+			}
+		}
+	}
+
+	current := uiFocusTargetIndex(targets, runtime.navFocus)
+	if current < 0 && runtime.navFocus != "" {
+		if modal && runtime.navReturn == "" {
+			runtime.navReturn = runtime.navFocus
+		}
+		runtime.navFocus = ""
+		if modal && runtime.focusVisible && len(targets) > 0 {
+			current = 0
+			runtime.navFocus = targets[0].id
+		}
+	}
+	if !modal && runtime.navReturn != "" {
+		if i := uiFocusTargetIndex(targets, runtime.navReturn); i >= 0 && current < 0 {
+			current = i
+			runtime.navFocus = runtime.navReturn
+		}
+		runtime.navReturn = ""
+	}
+	if editing || runtime.focused != "" || len(targets) == 0 {
+		return
+	}
+
+	dir := uiNavPressed(input)
+	activate := uiActivatePressed(input)
+	if dir == uiNavNone && !activate {
+		return
+	}
+	runtime.focusVisible = true
+
+	if current < 0 {
+		if dir == uiNavNone {
+			return
+		}
+		next := 0
+		if dir == uiNavPrev {
+			next = len(targets) - 1
+		}
+		uiSetNavFocus(runtime, targets[next])
+		return
+	}
+
+	target := targets[current]
+	if activate {
+		uiActivateFocusTarget(runtime, target)
+		return
+	}
+	if uiNavWithinWidget(runtime, target, dir) {
+		return
+	}
+	if next := uiNavNeighbor(targets, current, dir); next != current {
+		uiSetNavFocus(runtime, targets[next])
+	}
+}
+
+func uiSetNavFocus(runtime *UiRuntime, target uiFocusTarget) {
+	runtime.navFocus = target.id
+	uiScrollFocusIntoView(runtime, target)
+}
+
+// uiNavPressed reads Tab/Shift+Tab, the arrow keys, the d-pad and the left
+// stick. Stick up is negative, as in GLFW.
+func uiNavPressed(input *Input) uiNavDir {
+	switch {
+	case input.JustPressed[KeyTab] && input.Pressed[KeyShift]:
+		return uiNavPrev
+	case input.JustPressed[KeyTab]:
+		return uiNavNext
+	case input.JustPressed[KeyUp] || input.AnyGamepadJustPressed(GamepadButtonDpadUp) || uiNavStickPushed(input, GamepadAxisLeftY, -1):
+		return uiNavUp
+	case input.JustPressed[KeyDown] || input.AnyGamepadJustPressed(GamepadButtonDpadDown) || uiNavStickPushed(input, GamepadAxisLeftY, 1):
+		return uiNavDown
+	case input.JustPressed[KeyLeft] || input.AnyGamepadJustPressed(GamepadButtonDpadLeft) || uiNavStickPushed(input, GamepadAxisLeftX, -1):
+		return uiNavLeft
+	case input.JustPressed[KeyRight] || input.AnyGamepadJustPressed(GamepadButtonDpadRight) || uiNavStickPushed(input, GamepadAxisLeftX, 1):
+		return uiNavRight
+	}
+	return uiNavNone
+}
+
+func uiNavStickPushed(input *Input, axis GamepadAxis, sign float32) bool {
+	for i := range input.Gamepads {
+		pad := &input.Gamepads[i]
+		if pad.Connected && pad.Axes[axis]*sign >= uiNavStickThreshold && pad.PreviousAxes[axis]*sign < uiNavStickThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// uiNavNeighbor picks the next target in tab order, or the nearest target
+// in a direction. Candidates must lie past the focused widget's edge;
+// distance across the direction costs twice as much as along it.
+func uiNavNeighbor(targets []uiFocusTarget, current int, dir uiNavDir) int {
+	n := len(targets)
+	switch dir {
+	case uiNavNext:
+		return (current + 1) % n
+	case uiNavPrev:
+		return (current - 1 + n) % n
+	}
+
+	from := targets[current].layout
+	best := current
+	bestScore := float32(math.MaxFloat32)
+	for i, target := range targets {
+		if i == current {
+			continue
+		}
+		to := target.layout
+		var along, across float32
+		switch dir {
+		case uiNavUp:
+			along = from.y - (to.y + to.h)
+			across = uiNavSpanGap(from.x, from.w, to.x, to.w)
+		case uiNavDown:
+			along = to.y - (from.y + from.h)
+			across = uiNavSpanGap(from.x, from.w, to.x, to.w)
+		case uiNavLeft:
+			along = from.x - (to.x + to.w)
+			across = uiNavSpanGap(from.y, from.h, to.y, to.h)
+		case uiNavRight:
+			along = to.x - (from.x + from.w)
+			across = uiNavSpanGap(from.y, from.h, to.y, to.h)
+		}
+		if along < -0.5 {
+			continue
+		}
+		score := max(0, along) + 2*across
+		if score < bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// uiNavSpanGap is the distance between two 1D spans, zero when they overlap.
+func uiNavSpanGap(a, aLen, b, bLen float32) float32 {
+	switch {
+	case b > a+aLen:
+		return b - (a + aLen)
+	case a > b+bLen:
+		return a - (b + bLen)
+	}
+	return 0
+}
+
+// uiActivateFocusTarget runs the widget's action for Enter or gamepad A.
+func uiActivateFocusTarget(runtime *UiRuntime, target uiFocusTarget) {
+	layout := target.layout
+	switch layout.kind {
+	case uiNodeButton, uiNodeIconButton:
+		if onClick := uiButtonOnClick(layout); onClick != nil {
+			onClick()
+		}
+	case uiNodeTextField, uiNodeNumberField:
+		runtime.focus(target.id)
+	case uiNodeSelectCycle:
+		uiCycleSelect(layout.node.(UiSelectCycle), 1)
+	case uiNodeCheckbox:
+		if box := layout.node.(UiCheckbox); box.OnChange != nil {
+			box.OnChange(!box.Checked)
+		}
+	case uiNodeDropdown:
+		dropdown := layout.node.(UiDropdown)
+		state := runtime.touch(target.id)
+		if !state.Open {
+			if len(dropdown.Options) > 0 {
+				runtime.openPopup(target.id)
+				state.NavIndex = max(0, min(dropdown.Selected, len(dropdown.Options)-1))
+			}
+			return
+		}
+		if state.NavIndex != dropdown.Selected && state.NavIndex < len(dropdown.Options) && dropdown.OnChange != nil {
+			dropdown.OnChange(state.NavIndex)
+		}
+		runtime.closePopup()
+	case uiNodeTreeRow:
+		if row := layout.node.(uiTreeRow); row.onSelect != nil {
+			row.onSelect(row.item.Key)
+		}
+	case uiNodeTab:
+		if header := layout.node.(uiTabHeader); !header.selected && header.onChange != nil {
+			header.onChange(header.index)
+		}
+	}
+}
+
+// uiNavWithinWidget lets the focused widget use a direction itself, such as
+// a slider stepping or a list moving its selection. It returns false when
+// focus should move on instead.
+func uiNavWithinWidget(runtime *UiRuntime, target uiFocusTarget, dir uiNavDir) bool {
+	layout := target.layout
+	switch layout.kind {
+	case uiNodeSelectCycle:
+		step := uiNavHorizontalStep(dir)
+		if step == 0 {
+			return false
+		}
+		uiCycleSelect(layout.node.(UiSelectCycle), step)
+		return true
+	case uiNodeSlider:
+		step := uiNavHorizontalStep(dir)
+		if step == 0 {
+			return false
+		}
+		slider := layout.node.(UiSlider)
+		value := uiSliderStepValue(slider, step)
+		if value != slider.Value {
+			if slider.OnChange != nil {
+				slider.OnChange(value)
+			}
+			if slider.OnCommit != nil {
+				slider.OnCommit(value)
+			}
+		}
+		return true
+	case uiNodeDropdown:
+		state := runtime.touch(target.id)
+		step := uiNavVerticalStep(dir)
+		if !state.Open || step == 0 {
+			return state.Open
+		}
+		options := len(layout.node.(UiDropdown).Options)
+		state.NavIndex = max(0, min(options-1, state.NavIndex+step))
+		return true
+	case uiNodeList:
+		step := uiNavVerticalStep(dir)
+		if step == 0 {
+			return false
+		}
+		list := layout.node.(UiList)
+		next := list.Selected + step
+		if list.Selected < 0 {
+			next = 0
+		}
+		if next < 0 || next >= len(list.Items) {
+			return false
+		}
+		if list.OnSelect != nil {
+			list.OnSelect(next)
+		}
+		uiScrollListToIndex(runtime.touch(target.id), layout, next)
+		return true
+	case uiNodeTreeRow:
+		row := layout.node.(uiTreeRow)
+		if len(row.item.Children) == 0 {
+			return false
+		}
+		if (dir == uiNavRight && !row.open) || (dir == uiNavLeft && row.open) {
+			state := runtime.touch(target.id)
+			state.Toggled = !state.Toggled
+			return true
+		}
+	}
+	return false
+}
+
+func uiNavHorizontalStep(dir uiNavDir) int {
+	switch dir {
+	case uiNavLeft:
+		return -1
+	case uiNavRight:
+		return 1
+	}
+	return 0
+}
+
+func uiNavVerticalStep(dir uiNavDir) int {
+	switch dir {
+	case uiNavUp:
+		return -1
+	case uiNavDown:
+		return 1
+	}
+	return 0
+}
+
+func uiCycleSelect(field UiSelectCycle, step int) {
+	n := len(field.Options)
+	if n == 0 || field.OnChange == nil {
+		return
+	}
+	current := field.Selected
+	if current < 0 || current >= n {
+		current = 0
+	}
+	field.OnChange(((current+step)%n + n) % n)
+}
+
+// uiSliderStepValue moves a slider by Step, or a twentieth of its range
+// when Step is unset.
+func uiSliderStepValue(slider UiSlider, direction int) float32 {
+	if slider.Max <= slider.Min {
+		return slider.Min
+	}
+	step := slider.Step
+	if step <= 0 {
+		step = (slider.Max - slider.Min) / 20
+	}
+	return max(slider.Min, min(slider.Max, slider.Value+float32(direction)*step))
+}
+
+func uiScrollListToIndex(state *uiWidgetState, layout *uiLayoutNode, index int) {
+	rowH := uiListRowHeight(layout)
+	viewportH := layout.contentBottom - layout.contentTop
+	top := float32(index) * rowH
+	if top < state.ScrollY {
+		state.ScrollY = top
+	} else if top+rowH > state.ScrollY+viewportH {
+		state.ScrollY = top + rowH - viewportH
+	}
+	state.ScrollY = clampUiScroll(state.ScrollY, layout.scrollMax)
+}
+
+// uiScrollFocusIntoView scrolls the owning panel so a newly focused widget
+// sits inside its content viewport.
+func uiScrollFocusIntoView(runtime *UiRuntime, target uiFocusTarget) {
+	root, layout := target.root, target.layout
+	panel := target.panel()
+	if panel == nil || root.scrollMax <= 0 {
+		return
+	}
+	state := runtime.touch(uiWidgetID(target.eid, uiPanelRuntimeKey(panel)))
+	switch {
+	case layout.y < root.contentTop:
+		state.ScrollY -= root.contentTop - layout.y
+	case layout.y+layout.h > root.contentBottom:
+		state.ScrollY += layout.y + layout.h - root.contentBottom
+	}
+	state.ScrollY = clampUiScroll(state.ScrollY, root.scrollMax)
+}
+
+func uiDrawFocusRing(ctx uiLayoutContext, layout *uiLayoutNode) {
+	const thickness = float32(2)
+	x, y, w, h := layout.x-thickness, layout.y-thickness, layout.w+thickness*2, layout.h+thickness*2
+	uiDrawRect(ctx, x, y, w, thickness, uiFocusRingColor)
+	uiDrawRect(ctx, x, y+h-thickness, w, thickness, uiFocusRingColor)
+	uiDrawRect(ctx, x, y, thickness, h, uiFocusRingColor)
+	uiDrawRect(ctx, x+w-thickness, y, thickness, h, uiFocusRingColor)
+}
@@ -0,0 +1,295 @@
+package gekko
+
+import "testing"
+
+// uiNavHarness feeds synthetic Input frames through the panel input pass.
+// Panels are rebuilt every frame so controlled widgets see their callbacks'
+// effects, as they would in game code.
+type uiNavHarness struct {
+	runtime *UiRuntime
+	input   *Input
+	backend *SyntheticGamepadBackend
+	build   []func() *UiPanel
+}
+
+func newUiNavHarness(build ...func() *UiPanel) *uiNavHarness {
+	backend := &SyntheticGamepadBackend{}
+	backend.Connect(0, "Pad")
+	return &uiNavHarness{
+		runtime: newUiRuntime(),
+		input:   &Input{MouseX: -1, MouseY: -1, WindowWidth: 1280, WindowHeight: 720, GamepadBackend: backend},
+		backend: backend,
+		build:   build,
+	}
+}
+
+func (h *uiNavHarness) frame(keys ...int) {
+	h.input.JustPressed = [256]bool{}
+	h.input.Pressed = [256]bool{}
+	for _, key := range keys {
+		h.input.JustPressed[key] = true
+		h.input.Pressed[key] = true
+	}
+	pollGamepadInput(h.input)
+
+	var panels []uiPanelRef
+	for i, build := range h.build {
+		if panel := build(); panelVisible(panel) {
+			panels = append(panels, uiPanelRef{eid: EntityId(i), panel: panel})
+		}
+	}
+	ctx := uiLayoutContext{input: h.input, pixelRatio: 1, scale: 1}
+	uiHandlePanelsInput(ctx, h.input, h.runtime, panels)
+	h.runtime.endFrame()
+}
+
+func (h *uiNavHarness) pad(button GamepadButton) {
+	h.backend.SetButton(0, button, true)
+	h.frame()
+	h.backend.SetButton(0, button, false)
+}
+
+func (h *uiNavHarness) expectFocus(t *testing.T, eid EntityId, key string) {
+	t.Helper()
+	if want := uiWidgetID(eid, key); h.runtime.navFocus != want {
+		t.Fatalf("expected focus on %q, got %q", want, h.runtime.navFocus)
+	}
+}
+
+func TestUiNavTabOrderAndActivate(t *testing.T) {
+	var clicked string
+	h := newUiNavHarness(func() *UiPanel {
+		return &UiPanel{Key: "menu", Visible: true, Navigable: true, Children: []UiNode{
+			UiButtonControl{Key: "play", Label: "Play", OnClick: func() { clicked = "play" }},
+			UiButtonControl{Key: "options", Label: "Options", OnClick: func() { clicked = "options" }},
+			UiButtonControl{Key: "quit", Label: "Quit", OnClick: func() { clicked = "quit" }},
+		}}
+	})
+
+	h.frame(KeyTab)
+	h.expectFocus(t, 0, "button/play")
+	if !h.runtime.focusVisible {
+		t.Fatalf("expected keyboard navigation to show the focus ring")
+	}
+	h.frame(KeyTab)
+	h.frame(KeyTab)
+	h.expectFocus(t, 0, "button/quit")
+	h.frame(KeyTab)
+	h.expectFocus(t, 0, "button/play")
+	h.frame(KeyTab, KeyShift)
+	h.expectFocus(t, 0, "button/quit")
+	h.frame(KeyEnter)
+	if clicked != "quit" {
+		t.Fatalf("expected Enter to activate the focused button, got %q", clicked)
+	}
+
+	// A mouse click moves focus to the clicked widget and hides the ring.
+	h.input.MouseX, h.input.MouseY = 20, 40
+	h.frame(MouseButtonLeft)
+	h.expectFocus(t, 0, "button/play")
+	if h.runtime.focusVisible {
+		t.Fatalf("expected a mouse click to hide the focus ring")
+	}
+}
+
+func TestUiNavDirectionalUsesGeometry(t *testing.T) {
+	h := newUiNavHarness(func() *UiPanel {
+		return &UiPanel{Key: "grid", Visible: true, Navigable: true, Children: []UiNode{
+			UiRow{Children: []UiNode{
+				UiButtonControl{Key: "left", Label: "L", Width: 100},
+				UiButtonControl{Key: "right", Label: "R", Width: 100},
+			}},
+			UiButtonControl{Key: "bottom", Label: "B", Width: 100},
+		}}
+	})
+
+	h.pad(GamepadButtonDpadDown)
+	h.expectFocus(t, 0, "button/left")
+	h.pad(GamepadButtonDpadRight)
+	h.expectFocus(t, 0, "button/right")
+	h.pad(GamepadButtonDpadRight)
+	h.expectFocus(t, 0, "button/right")
+	h.pad(GamepadButtonDpadDown)
+	h.expectFocus(t, 0, "button/bottom")
+
+	// The stick moves once per push past the threshold.
+	h.backend.SetAxis(0, GamepadAxisLeftY, -0.9)
+	h.frame()
+	h.expectFocus(t, 0, "button/left")
+	h.frame()
+	h.expectFocus(t, 0, "button/left")
+}
+
+func TestUiNavWidgetsConsumeDirections(t *testing.T) {
+	volume := float32(0.5)
+	selected := 0
+	h := newUiNavHarness(func() *UiPanel {
+		return &UiPanel{Key: "settings", Visible: true, Navigable: true, Children: []UiNode{
+			UiSlider{Key: "volume", Value: volume, Min: 0, Max: 1, Step: 0.25, OnCommit: func(v float32) { volume = v }},
+			UiList{Key: "maps", Items: []string{"a", "b", "c"}, Rows: 2, Selected: selected, OnSelect: func(i int) { selected = i }},
+			UiButtonControl{Key: "back", Label: "Back"},
+		}}
+	})
+
+	h.frame(KeyTab)
+	h.frame(KeyRight)
+	if volume != 0.75 {
+		t.Fatalf("expected Right to step the slider, got %v", volume)
+	}
+	h.frame(KeyLeft)
+	h.frame(KeyLeft)
+	if volume != 0.25 {
+		t.Fatalf("expected Left to step the slider back, got %v", volume)
+	}
+
+	h.frame(KeyDown)
+	h.expectFocus(t, 0, "list/maps")
+	h.frame(KeyDown)
+	h.frame(KeyDown)
+	if selected != 2 {
+		t.Fatalf("expected Down to move the list selection, got %d", selected)
+	}
+	if scroll := h.runtime.touch(uiWidgetID(0, "list/maps")).ScrollY; scroll != uiMinBoxLineH {
+		t.Fatalf("expected the list to scroll the selection into view, got %v", scroll)
+	}
+	h.frame(KeyDown)
+	h.expectFocus(t, 0, "button/back")
+}
+
+func TestUiNavDropdownPicksWithKeys(t *testing.T) {
+	quality := 0
+	h := newUiNavHarness(func() *UiPanel {
+		return &UiPanel{Key: "video", Visible: true, Navigable: true, Children: []UiNode{
+			UiDropdown{Key: "quality", Options: []string{"Low", "Medium", "High"}, Selected: quality, OnChange: func(i int) { quality = i }},
+			UiButtonControl{Key: "apply", Label: "Apply"},
+		}}
+	})
+	id := uiWidgetID(0, "dropdown/quality")
+
+	h.frame(KeyTab)
+	h.pad(GamepadButtonA)
+	if !h.runtime.touch(id).Open {
+		t.Fatalf("expected A to open the dropdown")
+	}
+	h.frame(KeyDown)
+	h.frame(KeyDown)
+	h.frame(KeyDown)
+	h.expectFocus(t, 0, "dropdown/quality")
+	h.frame(KeyEnter)
+	if quality != 2 || h.runtime.touch(id).Open {
+		t.Fatalf("expected Enter to pick the highlighted option and close, got %d", quality)
+	}
+
+	h.frame(KeyEnter)
+	h.pad(GamepadButtonB)
+	if h.runtime.touch(id).Open || quality != 2 {
+		t.Fatalf("expected B to close the dropdown without changing it")
+	}
+}
+
+func TestUiNavTrapsFocusInModalAndRestoresIt(t *testing.T) {
+	confirm := false
+	h := newUiNavHarness(
+		func() *UiPanel {
+			return &UiPanel{Key: "menu", Visible: true, Navigable: true, Children: []UiNode{
+				UiButtonControl{Key: "quit", Label: "Quit", OnClick: func() { confirm = true }},
+			}}
+		},
+		func() *UiPanel {
+			return &UiPanel{Key: "confirm", Visible: confirm, Modal: true, OnDismiss: func() { confirm = false }, Children: []UiNode{
+				UiRow{Children: []UiNode{
+					UiButtonControl{Key: "yes", Label: "Yes"},
+					UiButtonControl{Key: "no", Label: "No"},
+				}},
+			}}
+		},
+	)
+
+	h.frame(KeyTab)
+	h.frame(KeyEnter)
+	if !confirm {
+		t.Fatalf("expected the quit button to open the modal")
+	}
+	h.frame()
+	h.expectFocus(t, 1, "button/yes")
+	h.frame(KeyTab)
+	h.expectFocus(t, 1, "button/no")
+	h.frame(KeyTab)
+	h.expectFocus(t, 1, "button/yes")
+	h.frame(KeyUp)
+	h.expectFocus(t, 1, "button/yes")
+
+	h.frame(KeyEscape)
+	if confirm {
+		t.Fatalf("expected Escape to dismiss the modal")
+	}
+	h.frame()
+	h.expectFocus(t, 0, "button/quit")
+}
+
+func TestUiNavEditsTextFieldsWithoutStealingKeys(t *testing.T) {
+	var committed string
+	h := newUiNavHarness(func() *UiPanel {
+		return &UiPanel{Key: "profile", Visible: true, Navigable: true, Children: []UiNode{
+			UiTextField{Key: "name", OnCommit: func(v string) { committed = v }},
+			UiButtonControl{Key: "save", Label: "Save"},
+		}}
+	})
+	id := uiWidgetID(0, "textfield/name")
+
+	h.frame(KeyTab)
+	h.frame(KeyEnter)
+	if h.runtime.focused != id {
+		t.Fatalf("expected Enter to start editing the focused field")
+	}
+	h.input.CharBuffer = []rune("ada")
+	h.frame(KeyTab)
+	h.input.CharBuffer = nil
+	h.expectFocus(t, 0, "textfield/name")
+	h.frame(KeyEnter)
+	if committed != "ada" || h.runtime.focused != "" {
+		t.Fatalf("expected Enter to commit the draft, got %q", committed)
+	}
+	h.frame(KeyTab)
+	h.expectFocus(t, 0, "button/save")
+}
+
+func TestUiNavScrollsFocusedWidgetIntoView(t *testing.T) {
+	panel := &UiPanel{Key: "long", Visible: true, Navigable: true, MaxHeight: 200, Children: []UiNode{
+		UiButtonControl{Key: "first", Label: "First"},
+		UiButtonControl{Key: "second", Label: "Second"},
+		UiButtonControl{Key: "third", Label: "Third"},
+	}}
+	h := newUiNavHarness(func() *UiPanel { return panel })
+
+	h.frame(KeyTab)
+	h.frame(KeyTab)
+	h.expectFocus(t, 0, "button/second")
+	// Content starts at y=36 and ends at y=160; the second button spans
+	// 125..209, so the panel scrolls by 49.
+	if scroll := h.runtime.touch(uiWidgetID(0, uiPanelRuntimeKey(panel))).ScrollY; scroll != 49 {
+		t.Fatalf("expected the panel to scroll the focused button into view, got %v", scroll)
+	}
+}
+
+func TestUiNavLeavesHudPanelsToGameplay(t *testing.T) {
+	clicked := false
+	h := newUiNavHarness(func() *UiPanel {
+		return &UiPanel{Key: "hud", Visible: true, Children: []UiNode{
+			UiLabel{Text: "Ammo 12/30"},
+			UiButtonControl{Key: "map", Label: "Map", OnClick: func() { clicked = true }},
+		}}
+	})
+
+	h.frame(KeyTab)
+	h.pad(GamepadButtonDpadDown)
+	h.backend.SetAxis(0, GamepadAxisLeftX, 0.9)
+	h.frame()
+	h.pad(GamepadButtonA)
+	if h.runtime.navFocus != "" || h.runtime.focusVisible || clicked {
+		t.Fatalf("expected a HUD panel to ignore navigation, got focus %q clicked %v", h.runtime.navFocus, clicked)
+	}
+	if h.input.GuiCaptured {
+		t.Fatalf("expected a HUD panel to leave input to gameplay")
+	}
+}
@@ -0,0 +1,291 @@
+package gekko
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// uiItemIconView is the object-space direction item icons are seen from:
+// the front, slightly from the right and above.
+var uiItemIconView = mgl32.Vec3{0.45, 0.35, 1}
+
+// UiRect is a pixel rectangle inside a texture.
+type UiRect struct {
+	X float32
+	Y float32
+	W float32
+	H float32
+}
+
+// UiImage draws a texture, or its AtlasRect region, in the overlay pass in
+// order with the UI rects. Width and Height default to the region's pixel
+// size; setting only one keeps the source aspect. PreserveAspect letterboxes
+// the image inside the box instead of stretching it.
+type UiImage struct {
+	Key            string
+	Texture        AssetId
+	AtlasRect      UiRect
+	Tint           [4]float32
+	Width          float32
+	Height         float32
+	PreserveAspect bool
+}
+
+func (UiImage) isUiNode() {}
+
+// UiIconButton is a button showing Icon with an optional Label after it. An
+// icon without a size is drawn as a square one and a half lines tall.
+type UiIconButton struct {
+	Key     string
+	Icon    UiImage
+	Label   string
+	Width   float32
+	Scale   float32
+	OnClick func()
+}
+
+func (UiIconButton) isUiNode() {}
+
+// UiNineSlice stretches a texture region over a rect while keeping its
+// corners at native size. Border is the left, top, right and bottom inset in
+// texels of Rect; Scale multiplies the on-screen border size.
+type UiNineSlice struct {
+	Texture AssetId
+	Rect    UiRect
+	Border  [4]float32
+	Scale   float32
+	Tint    [4]float32
+}
+
+type uiImageQuad struct {
+	x  float32
+	y  float32
+	w  float32
+	h  float32
+	uv [4]float32
+}
+
+// ItemIcon returns an image of a voxel model cut from its entity-LOD
+// impostor atlas, so inventory icons match the impostors drawn in the world.
+// The first call starts baking the atlas in the background; until it is done
+// ItemIcon returns false, so call it again on later frames.
+func (server *AssetServer) ItemIcon(geometryID, paletteID AssetId) (UiImage, bool) {
+	if server == nil {
+		return UiImage{}, false
+	}
+	source, ok := server.GetVoxelGeometry(geometryID)
+	if !ok {
+		return UiImage{}, false
+	}
+	texture, bake := server.entityLODImpostorTexture(geometryID, paletteID, &source)
+	if bake != entityLODImpostorReady {
+		return UiImage{}, false
+	}
+	cellX, cellY := entityLODImpostorNearestCell(uiItemIconView, entityLODImpostorViewGrid)
+	cell := float32(entityLODImpostorCellSize)
+	// Inset by half a texel so filtering does not bleed in neighbouring views.
+	return UiImage{
+		Texture:        texture,
+		AtlasRect:      UiRect{X: float32(cellX)*cell + 0.5, Y: float32(cellY)*cell + 0.5, W: cell - 1, H: cell - 1},
+		PreserveAspect: true,
+	}, true
+}
+
+func uiLayoutImage(img UiImage, path string, x, y float32, ctx uiLayoutContext) *uiLayoutNode {
+	w, h := uiImageSize(ctx, img)
+	return &uiLayoutNode{
+		kind: uiNodeImage,
+		key:  uiStableKey("image", img.Key, path),
+		x:    x,
+		y:    y,
+		w:    w,
+		h:    h,
+		node: img,
+	}
+}
+
+func uiLayoutIconButton(button UiIconButton, path string, x, y float32, ctx uiLayoutContext) *uiLayoutNode {
+	scale := uiNodeScale(button.Scale) * ctx.scale
+	iconW, iconH := uiIconButtonIconSize(ctx, button, scale)
+	_, h := uiBoxSize(ctx, 0, button.Label, scale)
+	h = max(h, iconH+uiFieldPaddingY*2)
+	w := button.Width
+	if w <= 0 {
+		w = iconW + uiFieldPaddingX*2
+		if button.Label != "" {
+			tw, _ := uiMeasureText(ctx, button.Label, scale)
+			w += uiPanelLabelGap + tw
+		}
+	}
+	return &uiLayoutNode{
+		kind: uiNodeIconButton,
+		key:  uiStableKey("iconbutton", button.Key, path),
+		x:    x,
+		y:    y,
+		w:    w,
+		h:    h,
+		node: button,
+	}
+}
+
+func uiRenderImage(layout *uiLayoutNode, ctx uiLayoutContext, img UiImage) {
+	uiDrawImage(ctx, img, layout.x, layout.y, layout.w, layout.h)
+}
+
+func uiRenderIconButton(layout *uiLayoutNode, ctx uiLayoutContext, button UiIconButton, state *uiWidgetState) {
+	scale := uiNodeScale(button.Scale) * ctx.scale
+	color := [4]float32{1, 1, 1, 1}
+	if state.Hovered {
+		color = [4]float32{1, 1, 0, 1}
+	}
+	uiDrawBox(ctx, layout.x, layout.y, layout.w, layout.h, color, scale)
+
+	iconW, iconH := uiIconButtonIconSize(ctx, button, scale)
+	iconX := layout.x + uiFieldPaddingX
+	if button.Label == "" {
+		iconX = layout.x + (layout.w-iconW)/2
+	}
+	uiDrawImage(ctx, button.Icon, iconX, layout.y+(layout.h-iconH)/2, iconW, iconH)
+	if button.Label != "" {
+		textY := layout.y + (layout.h-uiTextHeight(ctx, scale))/2
+		uiDrawText(ctx, button.Label, iconX+iconW+uiPanelLabelGap, textY, scale, color)
+	}
+}
+
+func uiIconButtonIconSize(ctx uiLayoutContext, button UiIconButton, scale float32) (float32, float32) {
+	if button.Icon.Width > 0 || button.Icon.Height > 0 {
+		return uiImageSize(ctx, button.Icon)
+	}
+	side := uiTextHeight(ctx, scale) * 1.5
+	return side, side
+}
+
+// uiImageSize resolves the layout size of an image from its explicit size
+// and the pixel size of its source region.
+func uiImageSize(ctx uiLayoutContext, img UiImage) (float32, float32) {
+	w, h := img.Width, img.Height
+	srcW, srcH := uiImageSourceSize(ctx, img.Texture, img.AtlasRect)
+	switch {
+	case w <= 0 && h <= 0:
+		return srcW, srcH
+	case w <= 0 && srcH > 0:
+		w = h * srcW / srcH
+	case h <= 0 && srcW > 0:
+		h = w * srcH / srcW
+	}
+	return max(w, 0), max(h, 0)
+}
+
+func uiImageSourceSize(ctx uiLayoutContext, texture AssetId, rect UiRect) (float32, float32) {
+	if rect.W > 0 && rect.H > 0 {
+		return rect.W, rect.H
+	}
+	texW, texH, ok := ctx.assets.TextureSize(texture)
+	if !ok {
+		return 0, 0
+	}
+	return float32(texW), float32(texH)
+}
+
+// uiImageUV converts a pixel region to normalized texture coordinates. The
+// zero rect covers the whole texture.
+func uiImageUV(ctx uiLayoutContext, texture AssetId, rect UiRect) ([4]float32, bool) {
+	if rect.W <= 0 || rect.H <= 0 {
+		return [4]float32{0, 0, 1, 1}, true
+	}
+	texW, texH, ok := ctx.assets.TextureSize(texture)
+	if !ok {
+		return [4]float32{}, false
+	}
+	w, h := float32(texW), float32(texH)
+	return [4]float32{rect.X / w, rect.Y / h, (rect.X + rect.W) / w, (rect.Y + rect.H) / h}, true
+}
+
+// uiFitRect centers the largest rect with the source aspect inside the box.
+func uiFitRect(srcW, srcH, x, y, w, h float32) (float32, float32, float32, float32) {
+	if srcW <= 0 || srcH <= 0 || w <= 0 || h <= 0 {
+		return x, y, w, h
+	}
+	fit := min(w/srcW, h/srcH)
+	fitW, fitH := srcW*fit, srcH*fit
+	return x + (w-fitW)/2, y + (h-fitH)/2, fitW, fitH
+}
+
+// uiNineSliceQuads splits the box into up to nine quads. Corners keep their
+// scaled border size and shrink together when the box is smaller than them.
+func uiNineSliceQuads(slice UiNineSlice, texW, texH, x, y, w, h float32) []uiImageQuad {
+	if texW <= 0 || texH <= 0 || w <= 0 || h <= 0 {
+		return nil
+	}
+	rect := slice.Rect
+	if rect.W <= 0 || rect.H <= 0 {
+		rect = UiRect{W: texW, H: texH}
+	}
+	border := slice.Border
+	for i := range border {
+		border[i] = max(border[i], 0)
+	}
+	scale := uiNodeScale(slice.Scale)
+	left, top, right, bottom := border[0]*scale, border[1]*scale, border[2]*scale, border[3]*scale
+	if left+right > w {
+		shrink := w / (left + right)
+		left, right = left*shrink, right*shrink
+	}
+	if top+bottom > h {
+		shrink := h / (top + bottom)
+		top, bottom = top*shrink, bottom*shrink
+	}
+
+	xs := [4]float32{x, x + left, x + w - right, x + w}
+	ys := [4]float32{y, y + top, y + h - bottom, y + h}
+	us := [4]float32{rect.X, rect.X + border[0], rect.X + rect.W - border[2], rect.X + rect.W}
+	vs := [4]float32{rect.Y, rect.Y + border[1], rect.Y + rect.H - border[3], rect.Y + rect.H}
+
+	quads := make([]uiImageQuad, 0, 9)
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			qw, qh := xs[col+1]-xs[col], ys[row+1]-ys[row]
+			if qw <= 0 || qh <= 0 {
+				continue
+			}
+			quads = append(quads, uiImageQuad{
+				x:  xs[col],
+				y:  ys[row],
+				w:  qw,
+				h:  qh,
+				uv: [4]float32{us[col] / texW, vs[row] / texH, us[col+1] / texW, vs[row+1] / texH},
+			})
+		}
+	}
+	return quads
+}
+
+func uiDrawImage(ctx uiLayoutContext, img UiImage, x, y, w, h float32) {
+	uv, ok := uiImageUV(ctx, img.Texture, img.AtlasRect)
+	if !ok {
+		return
+	}
+	if img.PreserveAspect {
+		srcW, srcH := uiImageSourceSize(ctx, img.Texture, img.AtlasRect)
+		x, y, w, h = uiFitRect(srcW, srcH, x, y, w, h)
+	}
+	uiDrawTexture(ctx, img.Texture, uv, x, y, w, h, img.Tint)
+}
+
+func uiDrawNineSlice(ctx uiLayoutContext, slice UiNineSlice, x, y, w, h float32) {
+	texW, texH, ok := ctx.assets.TextureSize(slice.Texture)
+	if !ok {
+		return
+	}
+	for _, quad := range uiNineSliceQuads(slice, float32(texW), float32(texH), x, y, w, h) {
+		uiDrawTexture(ctx, slice.Texture, quad.uv, quad.x, quad.y, quad.w, quad.h, slice.Tint)
+	}
+}
+
+// uiDrawTexture draws in the overlay pass with rects, in call order, so
+// images sit above their panel's background and below a later modal's
+// backdrop. Text still draws above all of them.
+func uiDrawTexture(ctx uiLayoutContext, texture AssetId, uv [4]float32, x, y, w, h float32, tint [4]float32) {
+	if tint == ([4]float32{}) {
+		tint = [4]float32{1, 1, 1, 1}
+	}
+	ratio := ctx.pixelRatio
+	ctx.state.DrawImage(x*ratio, y*ratio, w*ratio, h*ratio, texture, uv, tint)
+}
@@ -0,0 +1,195 @@
+package gekko
+
+import (
+	"testing"
+
+	app_rt "github.com/gekko3d/gekko/voxelrt/rt/app"
+	"github.com/gekko3d/gekko/voxelrt/rt/core"
+)
+
+func uiTestTexture(server *AssetServer, width, height uint32) AssetId {
+	server.ensureTextureStorage()
+	return server.CreateTextureFromTexels(make([]uint8, width*height*4), width, height, 1, TextureDimension2D, TextureFormatRGBA8Unorm)
+}
+
+func TestUiImageLayoutResolvesSizeAndRegion(t *testing.T) {
+	server := &AssetServer{}
+	texture := uiTestTexture(server, 64, 32)
+	ctx := uiTestContext(newUiRuntime(), &Input{})
+	ctx.assets = server
+
+	for _, tc := range []struct {
+		img  UiImage
+		w, h float32
+	}{
+		{UiImage{Texture: texture}, 64, 32},
+		{UiImage{Texture: texture, Width: 32}, 32, 16},
+		{UiImage{Texture: texture, AtlasRect: UiRect{X: 16, W: 16, H: 16}, Height: 48}, 48, 48},
+		{UiImage{Texture: texture, Width: 20, Height: 10}, 20, 10},
+		{UiImage{Texture: AssetId{}}, 0, 0},
+	} {
+		layout := uiLayoutNodeFor(tc.img, "panel/0", 0, 0, 200, ctx)
+		if layout.kind != uiNodeImage || layout.w != tc.w || layout.h != tc.h {
+			t.Fatalf("expected %vx%v image, got %vx%v", tc.w, tc.h, layout.w, layout.h)
+		}
+	}
+
+	uv, ok := uiImageUV(ctx, texture, UiRect{X: 16, W: 16, H: 16})
+	if !ok || uv != [4]float32{0.25, 0, 0.5, 0.5} {
+		t.Fatalf("unexpected atlas rect uv %v", uv)
+	}
+	if x, y, w, h := uiFitRect(64, 32, 0, 0, 100, 100); x != 0 || y != 25 || w != 100 || h != 50 {
+		t.Fatalf("expected letterboxed fit, got %v,%v %vx%v", x, y, w, h)
+	}
+}
+
+func TestUiNineSliceQuadsKeepCornersAndShrink(t *testing.T) {
+	slice := UiNineSlice{Border: [4]float32{16, 16, 16, 16}}
+	quads := uiNineSliceQuads(slice, 48, 48, 10, 20, 200, 100)
+	if len(quads) != 9 {
+		t.Fatalf("expected nine quads, got %d", len(quads))
+	}
+	corner, center := quads[0], quads[4]
+	if corner.x != 10 || corner.y != 20 || corner.w != 16 || corner.h != 16 || corner.uv != [4]float32{0, 0, 1.0 / 3, 1.0 / 3} {
+		t.Fatalf("unexpected top-left corner %+v", corner)
+	}
+	if center.w != 168 || center.h != 68 || center.uv != [4]float32{1.0 / 3, 1.0 / 3, 2.0 / 3, 2.0 / 3} {
+		t.Fatalf("unexpected stretched center %+v", center)
+	}
+
+	// A box narrower than both borders drops the middle column.
+	quads = uiNineSliceQuads(slice, 48, 48, 0, 0, 20, 100)
+	if len(quads) != 6 || quads[0].w != 10 || quads[1].x != 10 {
+		t.Fatalf("expected corners to shrink into a six-quad slice, got %+v", quads)
+	}
+}
+
+func TestUiPanelDrawsNineSliceBelowImages(t *testing.T) {
+	server := &AssetServer{}
+	skin := uiTestTexture(server, 48, 48)
+	icon := uiTestTexture(server, 32, 32)
+	state := &VoxelRtState{RtApp: &app_rt.App{}}
+	ctx := uiTestContext(newUiRuntime(), &Input{WindowWidth: 1280, WindowHeight: 720})
+	ctx.state, ctx.assets = state, server
+
+	var used string
+	panel := &UiPanel{Key: "hud", Visible: true, Background: UiNineSlice{Texture: skin, Border: [4]float32{16, 16, 16, 16}}, Children: []UiNode{
+		UiIconButton{Key: "potion", Icon: UiImage{Texture: icon}, Label: "Use", OnClick: func() { used = "potion" }},
+	}}
+	layout := uiBuildPanelLayout(ctx, panel, 0)
+	uiRenderLayout(layout, layout, ctx.eid, ctx, ctx.runtime)
+
+	images := uiTestOverlayImages(state)
+	if len(images) != 10 {
+		t.Fatalf("expected nine background quads and one icon, got %d images", len(images))
+	}
+	for _, rect := range images[:9] {
+		if rect.Texture != spriteAtlasKey(skin) {
+			t.Fatalf("expected background quads first, got %+v", rect)
+		}
+	}
+	button := layout.children[0]
+	iconRect := images[9]
+	if iconRect.Texture != spriteAtlasKey(icon) || iconRect.W != 42 || iconRect.H != 42 || iconRect.UV != [4]float32{0, 0, 1, 1} {
+		t.Fatalf("expected a 42px icon drawn after the skin, got %+v", iconRect)
+	}
+	if cy := button.y + button.h/2; iconRect.Y+iconRect.H/2 != cy {
+		t.Fatalf("expected the icon centered in the button at y=%v, got %v", cy, iconRect.Y+iconRect.H/2)
+	}
+	if keys := state.RtApp.OverlayImageTextures(); len(keys) != 2 {
+		t.Fatalf("expected both textures to be uploaded for the overlay, got %v", keys)
+	}
+
+	uiTestClick(ctx.input, button.x+4, button.y+4)
+	if !uiTestFrame(ctx.runtime, button, ctx) || used != "potion" {
+		t.Fatalf("expected a click to run the icon button")
+	}
+	if !uiFocusableKind(uiNodeIconButton) {
+		t.Fatalf("expected icon buttons to take navigation focus")
+	}
+}
+
+func TestUiPanelBgColorDrawsBelowImages(t *testing.T) {
+	server := &AssetServer{}
+	portrait := uiTestTexture(server, 32, 32)
+	state := &VoxelRtState{RtApp: &app_rt.App{}}
+	ctx := uiTestContext(newUiRuntime(), &Input{WindowWidth: 1280, WindowHeight: 720})
+	ctx.state, ctx.assets = state, server
+
+	bg := [4]float32{0.1, 0.1, 0.1, 1}
+	panel := &UiPanel{Key: "hud", Visible: true, BgColor: bg, Children: []UiNode{
+		UiImage{Key: "portrait", Texture: portrait},
+	}}
+	layout := uiBuildPanelLayout(ctx, panel, 0)
+	uiRenderLayout(layout, layout, ctx.eid, ctx, ctx.runtime)
+
+	background, image := -1, -1
+	for i, rect := range state.RtApp.TextResources.RectItems {
+		switch {
+		case rect.Texture == "" && rect.Color == bg && background < 0:
+			background = i
+		case rect.Texture == spriteAtlasKey(portrait):
+			image = i
+		}
+	}
+	if background < 0 || image < 0 || image < background {
+		t.Fatalf("expected the image to draw after the panel background, got background %d image %d", background, image)
+	}
+
+	app := NewApp()
+	if instances, _ := spritesSync(state, app.Commands()); len(instances) != 0 {
+		t.Fatalf("expected UI images to stay out of the world sprite pass, got %d", len(instances))
+	}
+}
+
+func uiTestOverlayImages(state *VoxelRtState) []core.RectItem {
+	var images []core.RectItem
+	for _, rect := range state.RtApp.TextResources.RectItems {
+		if rect.Texture != "" {
+			images = append(images, rect)
+		}
+	}
+	return images
+}
+
+func TestItemIconCutsImpostorView(t *testing.T) {
+	server := &AssetServer{
+		textures:       make(map[AssetId]TextureAsset),
+		textureKeys:    make(map[string]AssetId),
+		voxModels:      make(map[AssetId]VoxelGeometryAsset),
+		voxModelKeys:   make(map[string]AssetId),
+		voxPalettes:    make(map[AssetId]VoxelPaletteAsset),
+		voxPaletteKeys: make(map[string]AssetId),
+		voxFiles:       make(map[AssetId]*VoxFile),
+	}
+	geometryID := server.CreateFrameModel(12, 12, 12, 2, 1.0)
+	paletteID := server.CreateSimplePalette([4]uint8{255, 180, 96, 255})
+
+	if _, ok := server.ItemIcon(geometryID, paletteID); ok {
+		t.Fatal("expected no icon while the atlas bakes")
+	}
+	awaitEntityLODImpostorBakes(t, server)
+	icon, ok := server.ItemIcon(geometryID, paletteID)
+	if !ok {
+		t.Fatal("expected an item icon once the atlas is baked")
+	}
+	tex := server.textures[icon.Texture]
+	rect := icon.AtlasRect
+	if rect.W != entityLODImpostorCellSize-1 || rect.Y+rect.H > float32(tex.Height)/2 {
+		t.Fatalf("expected a color cell from the impostor atlas, got %+v", rect)
+	}
+	opaque := false
+	for y := int(rect.Y); y < int(rect.Y+rect.H); y++ {
+		for x := int(rect.X); x < int(rect.X+rect.W); x++ {
+			if tex.Texels[(y*int(tex.Width)+x)*4+3] > 0 {
+				opaque = true
+			}
+		}
+	}
+	if !opaque {
+		t.Fatal("expected the icon cell to show the model")
+	}
+	if _, ok := server.ItemIcon(AssetId{}, paletteID); ok {
+		t.Fatal("expected no icon for unknown geometry")
+	}
+}
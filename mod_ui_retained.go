@@ -18,10 +18,22 @@ const (
 	uiProgressCells  = 18
 )
 
+var uiModalBackdropColor = [4]float32{0, 0, 0, 0.55}
+
 type UiRuntime struct {
-	widgets map[string]*uiWidgetState
-	seen    map[string]bool
-	focused string
+	widgets  map[string]*uiWidgetState
+	seen     map[string]bool
+	focused  string
+	popup    uiPopup
+	deferred []func()
+	// navFocus is the widget the keyboard or gamepad cursor is on; focused
+	// is only set while a text or number field is being edited.
+	navFocus     string
+	navReturn    string
+	focusVisible bool
+	// localeGeneration is the Localization generation the widget state was
+	// built under.
+	localeGeneration uint64
 }
 
 type uiWidgetState struct {
@@ -31,6 +43,26 @@ type uiWidgetState struct {
 	Dirty          bool
 	LastControlled string
 	ScrollY        float32
+	// Open is set while a dropdown shows its options.
+	Open bool
+	// Toggled flips a tree node away from its declared Expanded state.
+	Toggled   bool
+	Dragging  bool
+	HoverTime float32
+	// NavIndex is the dropdown option highlighted by keyboard or gamepad.
+	NavIndex int
+}
+
+// uiPopup is the screen rect of the open dropdown list. It sits above the
+// panels, so widgets underneath ignore the pointer inside it. A closed popup
+// keeps blocking until the next frame so the closing click cannot fall
+// through.
+type uiPopup struct {
+	owner string
+	x     float32
+	y     float32
+	w     float32
+	h     float32
 }
 
 func newUiRuntime() *UiRuntime {
@@ -42,6 +74,11 @@ func newUiRuntime() *UiRuntime {
 
 func (rt *UiRuntime) beginFrame() {
 	clear(rt.seen)
+	if rt.popup.owner != "" {
+		if state, ok := rt.widgets[rt.popup.owner]; !ok || !state.Open {
+			rt.popup = uiPopup{}
+		}
+	}
 }
 
 func (rt *UiRuntime) endFrame() {
@@ -50,6 +87,9 @@ func (rt *UiRuntime) endFrame() {
 			if rt.focused == key {
 				rt.focused = ""
 			}
+			if rt.popup.owner == key {
+				rt.popup = uiPopup{}
+			}
 			delete(rt.widgets, key)
 			continue
 		}
@@ -80,6 +120,49 @@ func (rt *UiRuntime) focus(key string) {
 	state.Focused = true
 }
 
+// pointerOver reports whether the mouse is inside the rect and not covered by
+// another widget's popup.
+func (rt *UiRuntime) pointerOver(input *Input, owner string, x, y, w, h float32) bool {
+	mx, my := float32(input.MouseX), float32(input.MouseY)
+	if !uiPointInRect(mx, my, x, y, w, h) {
+		return false
+	}
+	popup := rt.popup
+	return popup.owner == "" || popup.owner == owner || !uiPointInRect(mx, my, popup.x, popup.y, popup.w, popup.h)
+}
+
+// deferDraw queues a draw that must land above every panel, such as a dropdown
+// list or a tooltip.
+func (rt *UiRuntime) deferDraw(draw func()) {
+	rt.deferred = append(rt.deferred, draw)
+}
+
+func (rt *UiRuntime) flushDeferred() {
+	for _, draw := range rt.deferred {
+		draw()
+	}
+	rt.deferred = rt.deferred[:0]
+}
+
+// syncLocale drops widget state that holds text from the previous locale:
+// edit drafts, open dropdowns and tooltip timers. Scroll, tree expansion and
+// navigation focus are kept.
+func (rt *UiRuntime) syncLocale(loc *Localization) {
+	if loc == nil || loc.generation == rt.localeGeneration {
+		return
+	}
+	rt.localeGeneration = loc.generation
+	rt.blurFocused()
+	rt.popup = uiPopup{}
+	for _, state := range rt.widgets {
+		state.Open = false
+		state.Dirty = false
+		state.Draft = ""
+		state.LastControlled = ""
+		state.HoverTime = 0
+	}
+}
+
 func (rt *UiRuntime) blurFocused() {
 	if rt.focused == "" {
 		return
@@ -111,6 +194,18 @@ type UiPanel struct {
 	BgColor    [4]float32
 	TextColor  [4]float32
 	Children   []UiNode
+	// Modal panels dim the screen, draw above other panels and take all
+	// input while visible. OnDismiss runs on Escape or gamepad B while the
+	// panel is modal or holds the navigation focus.
+	Modal     bool
+	OnDismiss func()
+	// Navigable panels join keyboard and gamepad focus navigation. HUD
+	// panels leave it off so Tab, the left stick and gamepad A stay with
+	// gameplay; a modal is always navigable while open.
+	Navigable bool
+	// Background skins the panel with a nine-slice texture in place of
+	// BgColor and the box-drawing border. Layout is unchanged.
+	Background UiNineSlice
 }
 
 func (UiPanel) isUiNode() {}
@@ -146,6 +241,9 @@ type UiLabel struct {
 	Scale float32
 	Dim   bool
 	Color [4]float32
+	// Loc, when its Key is set, replaces Text with the localized string.
+	// Text is kept while the key has no translation.
+	Loc LocalizedText
 }
 
 func (UiLabel) isUiNode() {}
@@ -214,6 +312,8 @@ type UiButtonControl struct {
 	Scale   float32
 	Align   UiTextAlign
 	OnClick func()
+	// Loc, when its Key is set, replaces Label with the localized string.
+	Loc LocalizedText
 }
 
 func (UiButtonControl) isUiNode() {}
@@ -270,6 +370,17 @@ const (
 	uiNodeNumberField
 	uiNodeSelectCycle
 	uiNodeZStack
+	uiNodeCheckbox
+	uiNodeSlider
+	uiNodeDropdown
+	uiNodeList
+	uiNodeTree
+	uiNodeTreeRow
+	uiNodeTabs
+	uiNodeTab
+	uiNodeTooltip
+	uiNodeImage
+	uiNodeIconButton
 )
 
 type uiLayoutNode struct {
@@ -290,35 +401,67 @@ type uiLayoutNode struct {
 
 type uiLayoutContext struct {
 	state      *VoxelRtState
+	assets     *AssetServer
 	input      *Input
+	runtime    *UiRuntime
+	loc        *Localization
+	eid        EntityId
+	dt         float32
 	pixelRatio float32
 	textColor  [4]float32
 	scale      float32
 }
 
-func uiPanelInputSystem(state *VoxelRtState, input *Input, runtime *UiRuntime, cmd *Commands) {
+func uiPanelInputSystem(state *VoxelRtState, server *AssetServer, input *Input, runtime *UiRuntime, docs *UiDocuments, t *Time, cmd *Commands) {
 	if state == nil || input == nil || runtime == nil || input.WindowWidth == 0 {
 		return
 	}
 
+	ctx := makeUiLayoutContext(state, server, input)
+	ctx.loc = localizationFromApp(cmd.app)
+	runtime.syncLocale(ctx.loc)
+	if t != nil {
+		ctx.dt = float32(t.Dt)
+	}
+	uiHandlePanelsInput(ctx, input, runtime, uiVisiblePanels(cmd, docs))
+}
+
+// uiHandlePanelsInput runs one frame of pointer, keyboard and gamepad input
+// over the visible panels.
+func uiHandlePanelsInput(ctx uiLayoutContext, input *Input, runtime *UiRuntime, panels []uiPanelRef) {
 	runtime.beginFrame()
 
-	ctx := makeUiLayoutContext(state, input)
+	ctx.runtime = runtime
 	clickedField := ""
 	clickConsumed := false
 	hasFocusedField := false
 
-	MakeQuery1[UiPanel](cmd).Map(func(eid EntityId, panel *UiPanel) bool {
-		if panel == nil || !panelVisible(panel) {
-			return true
+	modal, hasModal := uiTopModal(panels)
+	editing := runtime.focused != ""
+	canDismiss := !editing && runtime.popup.owner == ""
+	var targets []uiFocusTarget
+
+	for _, ref := range panels {
+		if hasModal && ref.eid != modal.eid {
+			continue
 		}
 
-		panelState := runtime.touch(uiWidgetID(eid, uiPanelRuntimeKey(panel)))
-		layout := uiBuildPanelLayout(ctx, panel, panelState.ScrollY)
+		ctx.eid = ref.eid
+		panelState := runtime.touch(uiWidgetID(ref.eid, uiPanelRuntimeKey(ref.panel)))
+		layout := uiBuildPanelLayout(ctx, ref.panel, panelState.ScrollY)
 		panelState.ScrollY = layout.scrollY
-		uiHandleInput(layout, layout, eid, ctx, input, runtime, &clickedField, &clickConsumed, &hasFocusedField)
-		return true
-	})
+		uiHandleInput(layout, layout, ref.eid, ctx, input, runtime, &clickedField, &clickConsumed, &hasFocusedField)
+		if hasModal || ref.panel.Navigable {
+			targets = uiCollectFocusTargets(targets, layout, layout, ref.eid)
+		}
+	}
+
+	if hasModal {
+		input.GuiCaptured = true
+		if input.JustPressed[MouseButtonLeft] {
+			clickConsumed = true
+		}
+	}
 
 	if input.JustPressed[MouseButtonLeft] && clickedField == "" && !clickConsumed {
 		runtime.blurFocused()
@@ -327,36 +470,92 @@ func uiPanelInputSystem(state *VoxelRtState, input *Input, runtime *UiRuntime, c
 	if !hasFocusedField && runtime.focused != "" {
 		runtime.blurFocused()
 	}
+
+	if canDismiss && uiCancelPressed(input) {
+		dismiss := modal.panel
+		if !hasModal {
+			if target, ok := uiFindFocusTarget(targets, runtime.navFocus); ok {
+				dismiss = target.panel()
+			}
+		}
+		if dismiss != nil && dismiss.OnDismiss != nil {
+			dismiss.OnDismiss()
+		}
+	}
+
+	uiNavigate(runtime, input, targets, hasModal, editing)
 }
 
-func uiPanelRenderSystem(state *VoxelRtState, input *Input, runtime *UiRuntime, cmd *Commands) {
+func uiPanelRenderSystem(state *VoxelRtState, server *AssetServer, input *Input, runtime *UiRuntime, docs *UiDocuments, cmd *Commands) {
 	if state == nil || input == nil || runtime == nil || input.WindowWidth == 0 {
 		return
 	}
 
-	ctx := makeUiLayoutContext(state, input)
-	MakeQuery1[UiPanel](cmd).Map(func(eid EntityId, panel *UiPanel) bool {
-		if panel == nil || !panelVisible(panel) {
-			return true
+	ctx := makeUiLayoutContext(state, server, input)
+	ctx.runtime = runtime
+	ctx.loc = localizationFromApp(cmd.app)
+	runtime.syncLocale(ctx.loc)
+	render := func(ref uiPanelRef) {
+		ctx.eid = ref.eid
+		panelState := runtime.touch(uiWidgetID(ref.eid, uiPanelRuntimeKey(ref.panel)))
+		layout := uiBuildPanelLayout(ctx, ref.panel, panelState.ScrollY)
+		panelState.ScrollY = layout.scrollY
+		uiRenderLayout(layout, layout, ref.eid, ctx, runtime)
+	}
+
+	panels := uiVisiblePanels(cmd, docs)
+	for _, ref := range panels {
+		if !ref.panel.Modal {
+			render(ref)
 		}
+	}
+	runtime.flushDeferred()
 
-		panelState := runtime.touch(uiWidgetID(eid, uiPanelRuntimeKey(panel)))
-		layout := uiBuildPanelLayout(ctx, panel, panelState.ScrollY)
-		panelState.ScrollY = layout.scrollY
-		uiRenderLayout(layout, layout, eid, ctx, runtime)
+	for _, ref := range panels {
+		if ref.panel.Modal {
+			uiDrawRect(ctx, 0, 0, float32(input.WindowWidth), float32(input.WindowHeight), uiModalBackdropColor)
+			render(ref)
+			runtime.flushDeferred()
+		}
+	}
+
+	runtime.endFrame()
+}
+
+type uiPanelRef struct {
+	eid   EntityId
+	panel *UiPanel
+}
+
+func uiVisiblePanels(cmd *Commands, docs *UiDocuments) []uiPanelRef {
+	var panels []uiPanelRef
+	MakeQuery1[UiPanel](cmd).Map(func(eid EntityId, panel *UiPanel) bool {
+		if panelVisible(panel) {
+			panels = append(panels, uiPanelRef{eid: eid, panel: panel})
+		}
 		return true
 	})
+	return uiDocumentPanels(panels, docs, cmd)
+}
 
-	runtime.endFrame()
+// uiTopModal returns the last visible modal panel; only it receives input.
+func uiTopModal(panels []uiPanelRef) (uiPanelRef, bool) {
+	for i := len(panels) - 1; i >= 0; i-- {
+		if panels[i].panel.Modal {
+			return panels[i], true
+		}
+	}
+	return uiPanelRef{}, false
 }
 
-func makeUiLayoutContext(state *VoxelRtState, input *Input) uiLayoutContext {
+func makeUiLayoutContext(state *VoxelRtState, server *AssetServer, input *Input) uiLayoutContext {
 	pixelRatio := float32(state.RtApp.Config.Width) / float32(input.WindowWidth)
 	if pixelRatio <= 0 {
 		pixelRatio = 1.0
 	}
 	return uiLayoutContext{
 		state:      state,
+		assets:     server,
 		input:      input,
 		pixelRatio: pixelRatio,
 		scale:      1.0,
@@ -511,6 +710,42 @@ func uiLayoutNodeFor(node UiNode, path string, x, y, width float32, ctx uiLayout
 		return uiLayoutZStack(typed, path, x, y, width, ctx)
 	case *UiZStack:
 		return uiLayoutZStack(*typed, path, x, y, width, ctx)
+	case UiCheckbox:
+		return uiLayoutCheckbox(typed, path, x, y, ctx)
+	case *UiCheckbox:
+		return uiLayoutCheckbox(*typed, path, x, y, ctx)
+	case UiSlider:
+		return uiLayoutSlider(typed, path, x, y, ctx)
+	case *UiSlider:
+		return uiLayoutSlider(*typed, path, x, y, ctx)
+	case UiDropdown:
+		return uiLayoutDropdown(typed, path, x, y, ctx)
+	case *UiDropdown:
+		return uiLayoutDropdown(*typed, path, x, y, ctx)
+	case UiList:
+		return uiLayoutList(typed, path, x, y, ctx)
+	case *UiList:
+		return uiLayoutList(*typed, path, x, y, ctx)
+	case UiTree:
+		return uiLayoutTree(typed, path, x, y, ctx)
+	case *UiTree:
+		return uiLayoutTree(*typed, path, x, y, ctx)
+	case UiTabs:
+		return uiLayoutTabs(typed, path, x, y, width, ctx)
+	case *UiTabs:
+		return uiLayoutTabs(*typed, path, x, y, width, ctx)
+	case UiTooltip:
+		return uiLayoutTooltip(typed, path, x, y, width, ctx)
+	case *UiTooltip:
+		return uiLayoutTooltip(*typed, path, x, y, width, ctx)
+	case UiImage:
+		return uiLayoutImage(typed, path, x, y, ctx)
+	case *UiImage:
+		return uiLayoutImage(*typed, path, x, y, ctx)
+	case UiIconButton:
+		return uiLayoutIconButton(typed, path, x, y, ctx)
+	case *UiIconButton:
+		return uiLayoutIconButton(*typed, path, x, y, ctx)
 	default:
 		return nil
 	}
@@ -634,6 +869,7 @@ func uiLayoutZStack(zstack UiZStack, path string, x, y, width float32, ctx uiLay
 }
 
 func uiLayoutLabel(label UiLabel, path string, x, y float32, ctx uiLayoutContext) *uiLayoutNode {
+	label.Text = ctx.loc.Localize(label.Text, label.Loc)
 	scale := uiNodeScale(label.Scale) * ctx.scale
 	tw, _ := uiMeasureText(ctx, label.Text, scale)
 	w := tw
@@ -683,6 +919,7 @@ func uiLayoutProgressBar(bar UiProgressBar, path string, x, y float32, ctx uiLay
 }
 
 func uiLayoutButton(button UiButtonControl, path string, x, y float32, ctx uiLayoutContext) *uiLayoutNode {
+	button.Label = ctx.loc.Localize(button.Label, button.Loc)
 	scale := uiNodeScale(button.Scale) * ctx.scale
 	w, h := uiBoxSize(ctx, button.Width, button.Label, scale)
 	return &uiLayoutNode{
@@ -696,6 +933,16 @@ func uiLayoutButton(button UiButtonControl, path string, x, y float32, ctx uiLay
 	}
 }
 
+func uiButtonOnClick(layout *uiLayoutNode) func() {
+	switch button := layout.node.(type) {
+	case UiButtonControl:
+		return button.OnClick
+	case UiIconButton:
+		return button.OnClick
+	}
+	return nil
+}
+
 func uiLayoutTextField(field UiTextField, path string, x, y float32, ctx uiLayoutContext) *uiLayoutNode {
 	scale := uiNodeScale(field.Scale) * ctx.scale
 	display := field.Value
@@ -759,7 +1006,8 @@ func uiHandleInput(root *uiLayoutNode, layout *uiLayoutNode, eid EntityId, ctx u
 			panelState := runtime.touch(uiWidgetID(eid, uiPanelRuntimeKey(layout.node.(*UiPanel))))
 			panelState.Hovered = true
 			if layout.scrollMax > 0 && input.MouseScrollY != 0 &&
-				uiPointInRect(float32(input.MouseX), float32(input.MouseY), layout.x, layout.contentTop, layout.w, layout.contentBottom-layout.contentTop) {
+				uiPointInRect(float32(input.MouseX), float32(input.MouseY), layout.x, layout.contentTop, layout.w, layout.contentBottom-layout.contentTop) &&
+				!uiScrollableAt(layout, float32(input.MouseX), float32(input.MouseY)) {
 				panelState.ScrollY = clampUiScroll(panelState.ScrollY-float32(input.MouseScrollY)*36, layout.scrollMax)
 				layout.scrollY = panelState.ScrollY
 			}
@@ -767,19 +1015,19 @@ func uiHandleInput(root *uiLayoutNode, layout *uiLayoutNode, eid EntityId, ctx u
 				*clickConsumed = true
 			}
 		}
-	case uiNodeButton:
+	case uiNodeButton, uiNodeIconButton:
 		if !uiLayoutVisible(layout, root) {
 			return
 		}
-		button := layout.node.(UiButtonControl)
-		state := runtime.touch(uiWidgetID(eid, layout.key))
-		state.Hovered = uiPointInRect(float32(input.MouseX), float32(input.MouseY), layout.x, layout.y, layout.w, layout.h)
+		id := uiWidgetID(eid, layout.key)
+		state := runtime.touch(id)
+		state.Hovered = runtime.pointerOver(input, id, layout.x, layout.y, layout.w, layout.h)
 		if state.Hovered {
 			input.GuiCaptured = true
 			if input.JustPressed[MouseButtonLeft] {
 				*clickConsumed = true
-				if button.OnClick != nil {
-					button.OnClick()
+				if onClick := uiButtonOnClick(layout); onClick != nil {
+					onClick()
 				}
 			}
 		}
@@ -788,19 +1036,14 @@ func uiHandleInput(root *uiLayoutNode, layout *uiLayoutNode, eid EntityId, ctx u
 			return
 		}
 		field := layout.node.(UiSelectCycle)
-		state := runtime.touch(uiWidgetID(eid, layout.key))
-		state.Hovered = uiPointInRect(float32(input.MouseX), float32(input.MouseY), layout.x, layout.y, layout.w, layout.h)
+		id := uiWidgetID(eid, layout.key)
+		state := runtime.touch(id)
+		state.Hovered = runtime.pointerOver(input, id, layout.x, layout.y, layout.w, layout.h)
 		if state.Hovered {
 			input.GuiCaptured = true
 			if input.JustPressed[MouseButtonLeft] {
 				*clickConsumed = true
-				if len(field.Options) > 0 && field.OnChange != nil {
-					next := field.Selected + 1
-					if next >= len(field.Options) {
-						next = 0
-					}
-					field.OnChange(next)
-				}
+				uiCycleSelect(field, 1)
 			}
 		}
 	case uiNodeTextField:
@@ -815,6 +1058,32 @@ func uiHandleInput(root *uiLayoutNode, layout *uiLayoutNode, eid EntityId, ctx u
 		}
 		field := layout.node.(UiNumberField)
 		uiHandleNumberFieldInput(layout, eid, field, input, runtime, clickedField, clickConsumed, hasFocusedField)
+	case uiNodeCheckbox:
+		if !uiLayoutVisible(layout, root) {
+			return
+		}
+		uiHandleCheckboxInput(layout, eid, layout.node.(UiCheckbox), input, runtime, clickConsumed)
+	case uiNodeSlider:
+		if !uiLayoutVisible(layout, root) {
+			return
+		}
+		uiHandleSliderInput(layout, eid, layout.node.(UiSlider), input, runtime, clickConsumed)
+	case uiNodeDropdown:
+		if !uiLayoutVisible(layout, root) {
+			return
+		}
+		uiHandleDropdownInput(layout, eid, ctx, layout.node.(UiDropdown), input, runtime, clickConsumed)
+	case uiNodeList:
+		if !uiLayoutVisible(layout, root) {
+			return
+		}
+		uiHandleListInput(layout, eid, layout.node.(UiList), input, runtime, clickConsumed)
+	case uiNodeTreeRow:
+		uiHandleTreeRowInput(layout, eid, ctx, layout.node.(uiTreeRow), input, runtime, clickConsumed)
+	case uiNodeTab:
+		uiHandleTabInput(layout, eid, layout.node.(uiTabHeader), input, runtime, clickConsumed)
+	case uiNodeTooltip:
+		uiHandleTooltipInput(layout, eid, ctx, input, runtime)
 	}
 
 	for _, child := range layout.children {
@@ -830,7 +1099,7 @@ func uiHandleTextFieldInput(layout *uiLayoutNode, eid EntityId, field UiTextFiel
 	state := runtime.touch(id)
 	syncUiFieldState(state, field.Value)
 
-	hovered := uiPointInRect(float32(input.MouseX), float32(input.MouseY), layout.x, layout.y, layout.w, layout.h)
+	hovered := runtime.pointerOver(input, id, layout.x, layout.y, layout.w, layout.h)
 	state.Hovered = hovered
 	if hovered {
 		input.GuiCaptured = true
@@ -864,7 +1133,7 @@ func uiHandleTextFieldInput(layout *uiLayoutNode, eid EntityId, field UiTextFiel
 			field.OnChange(state.Draft)
 		}
 	}
-	if input.JustPressed[KeyEnter] {
+	if uiActivatePressed(input) {
 		if field.OnCommit != nil {
 			field.OnCommit(state.Draft)
 		}
@@ -872,9 +1141,29 @@ func uiHandleTextFieldInput(layout *uiLayoutNode, eid EntityId, field UiTextFiel
 		state.Dirty = false
 		state.Focused = false
 		runtime.focused = ""
+	} else if uiCancelPressed(input) {
+		uiCancelFieldEdit(state, runtime)
 	}
 }
 
+// uiActivatePressed reports Enter or a gamepad A press this frame.
+func uiActivatePressed(input *Input) bool {
+	return input.JustPressed[KeyEnter] || input.AnyGamepadJustPressed(GamepadButtonA)
+}
+
+// uiCancelPressed reports Escape or a gamepad B press this frame.
+func uiCancelPressed(input *Input) bool {
+	return input.JustPressed[KeyEscape] || input.AnyGamepadJustPressed(GamepadButtonB)
+}
+
+// uiCancelFieldEdit drops the draft and releases focus.
+func uiCancelFieldEdit(state *uiWidgetState, runtime *UiRuntime) {
+	state.Draft = state.LastControlled
+	state.Dirty = false
+	state.Focused = false
+	runtime.focused = ""
+}
+
 func applyUiTextFieldPaste(state *uiWidgetState, clipboardText string, onChange func(string)) bool {
 	if state == nil || clipboardText == "" {
 		return false
@@ -904,7 +1193,7 @@ func uiHandleNumberFieldInput(layout *uiLayoutNode, eid EntityId, field UiNumber
 	controlled := formatUiNumber(field.Value, field.Precision)
 	syncUiFieldState(state, controlled)
 
-	hovered := uiPointInRect(float32(input.MouseX), float32(input.MouseY), layout.x, layout.y, layout.w, layout.h)
+	hovered := runtime.pointerOver(input, id, layout.x, layout.y, layout.w, layout.h)
 	state.Hovered = hovered
 	if hovered {
 		input.GuiCaptured = true
@@ -941,7 +1230,7 @@ func uiHandleNumberFieldInput(layout *uiLayoutNode, eid EntityId, field UiNumber
 			}
 		}
 	}
-	if input.JustPressed[KeyEnter] {
+	if uiActivatePressed(input) {
 		if parsed, ok := parseUiFloat(state.Draft); ok {
 			if field.OnCommit != nil {
 				field.OnCommit(parsed)
@@ -954,6 +1243,8 @@ func uiHandleNumberFieldInput(layout *uiLayoutNode, eid EntityId, field UiNumber
 		state.Dirty = false
 		state.Focused = false
 		runtime.focused = ""
+	} else if uiCancelPressed(input) {
+		uiCancelFieldEdit(state, runtime)
 	}
 }
 
@@ -1022,6 +1313,46 @@ func uiRenderLayout(root *uiLayoutNode, layout *uiLayoutNode, eid EntityId, ctx
 		}
 		field := layout.node.(UiSelectCycle)
 		uiRenderSelectCycle(layout, ctx, field, runtime.touch(uiWidgetID(eid, layout.key)))
+	case uiNodeCheckbox:
+		if !uiLayoutVisible(layout, root) {
+			return
+		}
+		uiRenderCheckbox(layout, ctx, layout.node.(UiCheckbox), runtime.touch(uiWidgetID(eid, layout.key)))
+	case uiNodeSlider:
+		if !uiLayoutVisible(layout, root) {
+			return
+		}
+		uiRenderSlider(layout, ctx, layout.node.(UiSlider), runtime.touch(uiWidgetID(eid, layout.key)))
+	case uiNodeDropdown:
+		if !uiLayoutVisible(layout, root) {
+			return
+		}
+		uiRenderDropdown(layout, ctx, layout.node.(UiDropdown), runtime.touch(uiWidgetID(eid, layout.key)), runtime)
+	case uiNodeList:
+		if !uiLayoutVisible(layout, root) {
+			return
+		}
+		uiRenderList(layout, ctx, layout.node.(UiList), runtime.touch(uiWidgetID(eid, layout.key)))
+	case uiNodeTreeRow:
+		uiRenderTreeRow(layout, ctx, layout.node.(uiTreeRow), runtime.touch(uiWidgetID(eid, layout.key)))
+	case uiNodeTab:
+		uiRenderTab(layout, ctx, layout.node.(uiTabHeader), runtime.touch(uiWidgetID(eid, layout.key)))
+	case uiNodeTooltip:
+		uiRenderTooltip(layout, ctx, layout.node.(UiTooltip), runtime.touch(uiWidgetID(eid, layout.key)), runtime)
+	case uiNodeImage:
+		if !uiLayoutVisible(layout, root) {
+			return
+		}
+		uiRenderImage(layout, ctx, layout.node.(UiImage))
+	case uiNodeIconButton:
+		if !uiLayoutVisible(layout, root) {
+			return
+		}
+		uiRenderIconButton(layout, ctx, layout.node.(UiIconButton), runtime.touch(uiWidgetID(eid, layout.key)))
+	}
+
+	if runtime.focusVisible && uiFocusableKind(layout.kind) && runtime.navFocus == uiWidgetID(eid, layout.key) && uiLayoutVisible(layout, root) {
+		uiDrawFocusRing(ctx, layout)
 	}
 
 	for _, child := range layout.children {
@@ -1050,10 +1381,13 @@ func uiRenderPanel(layout *uiLayoutNode, ctx uiLayoutContext, panel *UiPanel) {
 		borderColor[3] = 0.85
 	}
 
-	if panel.BgColor != ([4]float32{}) {
+	skinned := panel.Background.Texture != (AssetId{})
+	if skinned {
+		uiDrawNineSlice(ctx, panel.Background, layout.x, layout.y, layout.w, layout.h)
+	} else if panel.BgColor != ([4]float32{}) {
 		uiDrawRect(ctx, layout.x, layout.y, layout.w, layout.h, panel.BgColor)
 	}
-	if !panel.Borderless {
+	if !panel.Borderless && !skinned {
 		uiDrawBox(ctx, layout.x, layout.y, layout.w, layout.h, borderColor, scale)
 	}
 	if panel.Title != "" {
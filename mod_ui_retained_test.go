@@ -109,6 +109,41 @@ func TestUiTextFieldPastesFocusedClipboardText(t *testing.T) {
 	}
 }
 
+func TestUiTextFieldCommitsAndCancelsWithGamepad(t *testing.T) {
+	runtime := newUiRuntime()
+	eid := EntityId(7)
+	layout := &uiLayoutNode{kind: uiNodeTextField, key: "name", x: 10, y: 10, w: 200, h: 24}
+	id := uiWidgetID(eid, layout.key)
+	var committed string
+	field := UiTextField{Value: "old", OnCommit: func(text string) { committed = text }}
+	backend := &SyntheticGamepadBackend{}
+	backend.Connect(0, "Pad")
+	frame := func(chars []rune) {
+		input := &Input{MouseX: -1, MouseY: -1, CharBuffer: chars, GamepadBackend: backend}
+		pollGamepadInput(input)
+		clickedField, clickConsumed, hasFocusedField := "", false, false
+		uiHandleTextFieldInput(layout, eid, field, input, runtime, &clickedField, &clickConsumed, &hasFocusedField)
+	}
+
+	runtime.focus(id)
+	frame([]rune("er"))
+	backend.SetButton(0, GamepadButtonB, true)
+	frame(nil)
+	state := runtime.touch(id)
+	if state.Focused || state.Draft != "old" || committed != "" {
+		t.Fatalf("expected B to drop the draft and blur, got draft=%q focused=%v", state.Draft, state.Focused)
+	}
+
+	backend.SetButton(0, GamepadButtonB, false)
+	runtime.focus(id)
+	frame([]rune("!"))
+	backend.SetButton(0, GamepadButtonA, true)
+	frame(nil)
+	if state.Focused || committed != "old!" {
+		t.Fatalf("expected A to commit, got %q focused=%v", committed, state.Focused)
+	}
+}
+
 func TestResolveUiPositionAnchors(t *testing.T) {
 	x, y := resolveUiPosition(UiAnchorTopRight, [2]float32{20, 30}, 100, 50, 1280, 720)
 	if x != 1160 || y != 30 {
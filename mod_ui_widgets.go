@@ -0,0 +1,813 @@
+package gekko
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const (
+	uiSliderWidth     = float32(160)
+	uiListRowsDefault = 6
+	uiListScrollbarW  = float32(14)
+	uiTreeIndent      = float32(16)
+	uiTooltipDelay    = float32(0.5)
+)
+
+var (
+	uiPopupBgColor     = [4]float32{0.05, 0.06, 0.08, 0.95}
+	uiHighlightBgColor = [4]float32{1, 1, 0, 0.18}
+)
+
+// UiCheckbox toggles a boolean. Switch draws it as an ON/OFF toggle instead
+// of a tick box.
+type UiCheckbox struct {
+	Key      string
+	Label    string
+	Checked  bool
+	Switch   bool
+	Width    float32
+	Scale    float32
+	OnChange func(bool)
+}
+
+func (UiCheckbox) isUiNode() {}
+
+// UiSlider picks a value in Min..Max by dragging across the box. Step snaps
+// the value; OnCommit runs when the drag is released.
+type UiSlider struct {
+	Key       string
+	Value     float32
+	Min       float32
+	Max       float32
+	Step      float32
+	Precision int
+	Width     float32
+	Scale     float32
+	OnChange  func(float32)
+	OnCommit  func(float32)
+}
+
+func (UiSlider) isUiNode() {}
+
+// UiDropdown shows the selected option and opens the full option list in a
+// popup drawn above every panel.
+type UiDropdown struct {
+	Key         string
+	Options     []string
+	Selected    int
+	Placeholder string
+	Width       float32
+	Scale       float32
+	OnChange    func(int)
+}
+
+func (UiDropdown) isUiNode() {}
+
+// UiList is a scrollable, selectable list showing Rows items at a time.
+type UiList struct {
+	Key      string
+	Items    []string
+	Selected int
+	Rows     int
+	Width    float32
+	Scale    float32
+	OnSelect func(int)
+}
+
+func (UiList) isUiNode() {}
+
+// UiTree shows nested nodes that expand and collapse. Selected and OnSelect
+// use UiTreeNode.Key, so keys should be unique within a tree.
+type UiTree struct {
+	Key      string
+	Nodes    []UiTreeNode
+	Selected string
+	Indent   float32
+	Width    float32
+	Scale    float32
+	OnSelect func(string)
+}
+
+func (UiTree) isUiNode() {}
+
+// UiTreeNode is one tree entry. Expanded is the initial state; clicking the
+// marker flips it in the runtime.
+type UiTreeNode struct {
+	Key      string
+	Label    string
+	Expanded bool
+	Children []UiTreeNode
+}
+
+// UiTabs draws a tab bar and the content of the selected tab below it.
+type UiTabs struct {
+	Key      string
+	Tabs     []UiTab
+	Selected int
+	Spacing  float32
+	Scale    float32
+	OnChange func(int)
+}
+
+func (UiTabs) isUiNode() {}
+
+type UiTab struct {
+	Label   string
+	Content UiNode
+}
+
+// UiTooltip shows Text next to the pointer once Child has been hovered for
+// Delay seconds.
+type UiTooltip struct {
+	Key   string
+	Text  string
+	Delay float32
+	Scale float32
+	Child UiNode
+}
+
+func (UiTooltip) isUiNode() {}
+
+type uiTreeRow struct {
+	item     UiTreeNode
+	depth    int
+	open     bool
+	selected bool
+	scale    float32
+	onSelect func(string)
+}
+
+func (uiTreeRow) isUiNode() {}
+
+type uiTabHeader struct {
+	label    string
+	index    int
+	selected bool
+	scale    float32
+	onChange func(int)
+}
+
+func (uiTabHeader) isUiNode() {}
+
+func (rt *UiRuntime) openPopup(owner string) {
+	rt.closePopup()
+	rt.popup = uiPopup{owner: owner}
+	rt.touch(owner).Open = true
+}
+
+func (rt *UiRuntime) closePopup() {
+	if state, ok := rt.widgets[rt.popup.owner]; ok {
+		state.Open = false
+	}
+}
+
+func uiLayoutCheckbox(box UiCheckbox, path string, x, y float32, ctx uiLayoutContext) *uiLayoutNode {
+	scale := uiNodeScale(box.Scale) * ctx.scale
+	w, _ := uiMeasureText(ctx, uiCheckboxText(box), scale)
+	if box.Width > 0 {
+		w = box.Width
+	}
+	return &uiLayoutNode{
+		kind: uiNodeCheckbox,
+		key:  uiStableKey("checkbox", box.Key, path),
+		x:    x,
+		y:    y,
+		w:    w,
+		h:    uiTextHeight(ctx, scale),
+		node: box,
+	}
+}
+
+func uiLayoutSlider(slider UiSlider, path string, x, y float32, ctx uiLayoutContext) *uiLayoutNode {
+	scale := uiNodeScale(slider.Scale) * ctx.scale
+	width := slider.Width
+	if width <= 0 {
+		width = uiSliderWidth
+	}
+	w, h := uiBoxSize(ctx, width, uiSliderValueLabel(slider), scale)
+	return &uiLayoutNode{
+		kind: uiNodeSlider,
+		key:  uiStableKey("slider", slider.Key, path),
+		x:    x,
+		y:    y,
+		w:    w,
+		h:    h,
+		node: slider,
+	}
+}
+
+func uiLayoutDropdown(dropdown UiDropdown, path string, x, y float32, ctx uiLayoutContext) *uiLayoutNode {
+	scale := uiNodeScale(dropdown.Scale) * ctx.scale
+	widest := dropdown.Placeholder
+	widestW, _ := uiMeasureText(ctx, widest, scale)
+	for _, option := range dropdown.Options {
+		if optionW, _ := uiMeasureText(ctx, option, scale); optionW > widestW {
+			widest, widestW = option, optionW
+		}
+	}
+	w, h := uiBoxSize(ctx, dropdown.Width, widest+" v", scale)
+	return &uiLayoutNode{
+		kind: uiNodeDropdown,
+		key:  uiStableKey("dropdown", dropdown.Key, path),
+		x:    x,
+		y:    y,
+		w:    w,
+		h:    h,
+		node: dropdown,
+	}
+}
+
+func uiLayoutList(list UiList, path string, x, y float32, ctx uiLayoutContext) *uiLayoutNode {
+	scale := uiNodeScale(list.Scale) * ctx.scale
+	rowH := uiTextHeight(ctx, scale)
+	rows := uiListRows(list)
+	w := list.Width
+	if w <= 0 {
+		for _, item := range list.Items {
+			if itemW, _ := uiMeasureText(ctx, item, scale); itemW > w {
+				w = itemW
+			}
+		}
+		w += (uiPanelBorderWidth(ctx, scale)+uiFieldPaddingX)*2 + uiListScrollbarW
+	}
+	layout := &uiLayoutNode{
+		kind: uiNodeList,
+		key:  uiStableKey("list", list.Key, path),
+		x:    x,
+		y:    y,
+		w:    w,
+		h:    float32(rows+2) * rowH,
+		node: list,
+	}
+	layout.contentTop = y + rowH
+	layout.contentBottom = y + layout.h - rowH
+	layout.contentHeight = float32(len(list.Items)) * rowH
+	layout.scrollMax = layout.contentHeight - float32(rows)*rowH
+	if layout.scrollMax < 0 {
+		layout.scrollMax = 0
+	}
+	return layout
+}
+
+func uiLayoutTree(tree UiTree, path string, x, y float32, ctx uiLayoutContext) *uiLayoutNode {
+	scale := uiNodeScale(tree.Scale) * ctx.scale
+	indent := tree.Indent
+	if indent <= 0 {
+		indent = uiTreeIndent
+	}
+	layout := &uiLayoutNode{
+		kind: uiNodeTree,
+		key:  uiStableKey("tree", tree.Key, path),
+		x:    x,
+		y:    y,
+		node: tree,
+	}
+
+	rowH := uiTextHeight(ctx, scale)
+	currY := y
+	maxW := tree.Width
+	var walk func(nodes []UiTreeNode, prefix string, depth int)
+	walk = func(nodes []UiTreeNode, prefix string, depth int) {
+		for idx, item := range nodes {
+			itemKey := item.Key
+			if itemKey == "" {
+				itemKey = strconv.Itoa(idx)
+			}
+			rowKey := prefix + "/" + itemKey
+			open := item.Expanded
+			if ctx.runtime != nil {
+				open = item.Expanded != ctx.runtime.touch(uiWidgetID(ctx.eid, rowKey)).Toggled
+			}
+			row := uiTreeRow{
+				item:     item,
+				depth:    depth,
+				open:     open,
+				selected: item.Key != "" && item.Key == tree.Selected,
+				scale:    scale,
+				onSelect: tree.OnSelect,
+			}
+			rowX := x + float32(depth)*indent
+			rowW, _ := uiMeasureText(ctx, uiTreeRowText(row), scale)
+			if tree.Width > 0 {
+				rowW = tree.Width - float32(depth)*indent
+			}
+			layout.children = append(layout.children, &uiLayoutNode{
+				kind: uiNodeTreeRow,
+				key:  rowKey,
+				x:    rowX,
+				y:    currY,
+				w:    rowW,
+				h:    rowH,
+				node: row,
+			})
+			if rowX+rowW-x > maxW {
+				maxW = rowX + rowW - x
+			}
+			currY += rowH
+			if open && len(item.Children) > 0 {
+				walk(item.Children, rowKey, depth+1)
+			}
+		}
+	}
+	walk(tree.Nodes, layout.key, 0)
+
+	layout.w = maxW
+	layout.h = currY - y
+	return layout
+}
+
+func uiLayoutTabs(tabs UiTabs, path string, x, y, width float32, ctx uiLayoutContext) *uiLayoutNode {
+	scale := uiNodeScale(tabs.Scale) * ctx.scale
+	spacing := tabs.Spacing
+	if spacing <= 0 {
+		spacing = uiPanelSpacing
+	}
+	layout := &uiLayoutNode{
+		kind: uiNodeTabs,
+		key:  uiStableKey("tabs", tabs.Key, path),
+		x:    x,
+		y:    y,
+		node: tabs,
+	}
+
+	selected := uiTabsSelected(tabs)
+	currX := x
+	headerH := float32(0)
+	for idx, tab := range tabs.Tabs {
+		w, h := uiBoxSize(ctx, 0, tab.Label, scale)
+		layout.children = append(layout.children, &uiLayoutNode{
+			kind: uiNodeTab,
+			key:  fmt.Sprintf("%s/tab/%d", layout.key, idx),
+			x:    currX,
+			y:    y,
+			w:    w,
+			h:    h,
+			node: uiTabHeader{
+				label:    tab.Label,
+				index:    idx,
+				selected: idx == selected,
+				scale:    scale,
+				onChange: tabs.OnChange,
+			},
+		})
+		currX += w + spacing
+		if h > headerH {
+			headerH = h
+		}
+	}
+	if len(tabs.Tabs) > 0 {
+		currX -= spacing
+	}
+	layout.w = currX - x
+	layout.h = headerH
+
+	if selected < 0 || tabs.Tabs[selected].Content == nil {
+		return layout
+	}
+	contentY := y + headerH + spacing
+	content := uiLayoutNodeFor(tabs.Tabs[selected].Content, fmt.Sprintf("%s/%d", path, selected), x, contentY, width, ctx)
+	if content == nil {
+		return layout
+	}
+	layout.children = append(layout.children, content)
+	if content.w > layout.w {
+		layout.w = content.w
+	}
+	layout.h = contentY + content.h - y
+	return layout
+}
+
+func uiLayoutTooltip(tip UiTooltip, path string, x, y, width float32, ctx uiLayoutContext) *uiLayoutNode {
+	child := uiLayoutNodeFor(tip.Child, path+"/0", x, y, width, ctx)
+	if child == nil {
+		return nil
+	}
+	return &uiLayoutNode{
+		kind:     uiNodeTooltip,
+		key:      uiStableKey("tooltip", tip.Key, path),
+		x:        x,
+		y:        y,
+		w:        child.w,
+		h:        child.h,
+		node:     tip,
+		children: []*uiLayoutNode{child},
+	}
+}
+
+func uiHandleCheckboxInput(layout *uiLayoutNode, eid EntityId, box UiCheckbox, input *Input, runtime *UiRuntime, clickConsumed *bool) {
+	id := uiWidgetID(eid, layout.key)
+	state := runtime.touch(id)
+	state.Hovered = runtime.pointerOver(input, id, layout.x, layout.y, layout.w, layout.h)
+	if !state.Hovered {
+		return
+	}
+	input.GuiCaptured = true
+	if input.JustPressed[MouseButtonLeft] {
+		*clickConsumed = true
+		if box.OnChange != nil {
+			box.OnChange(!box.Checked)
+		}
+	}
+}
+
+func uiHandleSliderInput(layout *uiLayoutNode, eid EntityId, slider UiSlider, input *Input, runtime *UiRuntime, clickConsumed *bool) {
+	id := uiWidgetID(eid, layout.key)
+	state := runtime.touch(id)
+	state.Hovered = runtime.pointerOver(input, id, layout.x, layout.y, layout.w, layout.h)
+	if state.Hovered {
+		input.GuiCaptured = true
+		if input.JustPressed[MouseButtonLeft] {
+			*clickConsumed = true
+			state.Dragging = true
+		}
+	}
+	if !state.Dragging {
+		return
+	}
+
+	input.GuiCaptured = true
+	value := uiSliderValueAt(layout, slider, float32(input.MouseX))
+	if value != slider.Value && slider.OnChange != nil {
+		slider.OnChange(value)
+	}
+	if !input.Pressed[MouseButtonLeft] {
+		state.Dragging = false
+		if slider.OnCommit != nil {
+			slider.OnCommit(value)
+		}
+	}
+}
+
+func uiHandleDropdownInput(layout *uiLayoutNode, eid EntityId, ctx uiLayoutContext, dropdown UiDropdown, input *Input, runtime *UiRuntime, clickConsumed *bool) {
+	id := uiWidgetID(eid, layout.key)
+	state := runtime.touch(id)
+	state.Hovered = runtime.pointerOver(input, id, layout.x, layout.y, layout.w, layout.h)
+	justOpened := false
+	if state.Hovered {
+		input.GuiCaptured = true
+		if input.JustPressed[MouseButtonLeft] {
+			*clickConsumed = true
+			if state.Open {
+				runtime.closePopup()
+			} else if len(dropdown.Options) > 0 {
+				runtime.openPopup(id)
+				justOpened = true
+			}
+		}
+	}
+	if !state.Open {
+		return
+	}
+
+	x, y, w, h, rowH := uiDropdownPopupRect(layout, ctx, dropdown)
+	runtime.popup = uiPopup{owner: id, x: x, y: y, w: w, h: h}
+	mx, my := float32(input.MouseX), float32(input.MouseY)
+	switch {
+	case uiCancelPressed(input):
+		runtime.closePopup()
+	case uiPointInRect(mx, my, x, y, w, h):
+		input.GuiCaptured = true
+		if input.JustPressed[MouseButtonLeft] && !justOpened {
+			*clickConsumed = true
+			index := int((my - y - uiFieldPaddingY) / rowH)
+			if index >= 0 && index < len(dropdown.Options) && index != dropdown.Selected && dropdown.OnChange != nil {
+				dropdown.OnChange(index)
+			}
+			runtime.closePopup()
+		}
+	case input.JustPressed[MouseButtonLeft] && !state.Hovered:
+		runtime.closePopup()
+	}
+}
+
+func uiHandleListInput(layout *uiLayoutNode, eid EntityId, list UiList, input *Input, runtime *UiRuntime, clickConsumed *bool) {
+	id := uiWidgetID(eid, layout.key)
+	state := runtime.touch(id)
+	state.ScrollY = clampUiScroll(state.ScrollY, layout.scrollMax)
+	layout.scrollY = state.ScrollY
+	state.Hovered = runtime.pointerOver(input, id, layout.x, layout.y, layout.w, layout.h)
+	if !state.Hovered {
+		return
+	}
+
+	input.GuiCaptured = true
+	if input.MouseScrollY != 0 && layout.scrollMax > 0 {
+		state.ScrollY = clampUiScroll(state.ScrollY-float32(input.MouseScrollY)*uiListRowHeight(layout), layout.scrollMax)
+		layout.scrollY = state.ScrollY
+	}
+	if input.JustPressed[MouseButtonLeft] {
+		*clickConsumed = true
+		if index, ok := uiListIndexAt(layout, len(list.Items), float32(input.MouseY)); ok && list.OnSelect != nil {
+			list.OnSelect(index)
+		}
+	}
+}
+
+func uiHandleTreeRowInput(layout *uiLayoutNode, eid EntityId, ctx uiLayoutContext, row uiTreeRow, input *Input, runtime *UiRuntime, clickConsumed *bool) {
+	id := uiWidgetID(eid, layout.key)
+	state := runtime.touch(id)
+	state.Hovered = runtime.pointerOver(input, id, layout.x, layout.y, layout.w, layout.h)
+	if !state.Hovered {
+		return
+	}
+	input.GuiCaptured = true
+	if !input.JustPressed[MouseButtonLeft] {
+		return
+	}
+	*clickConsumed = true
+	if len(row.item.Children) > 0 && float32(input.MouseX) < layout.x+uiTreeMarkerWidth(ctx, row.scale) {
+		state.Toggled = !state.Toggled
+		return
+	}
+	if row.onSelect != nil {
+		row.onSelect(row.item.Key)
+	}
+}
+
+func uiHandleTabInput(layout *uiLayoutNode, eid EntityId, header uiTabHeader, input *Input, runtime *UiRuntime, clickConsumed *bool) {
+	id := uiWidgetID(eid, layout.key)
+	state := runtime.touch(id)
+	state.Hovered = runtime.pointerOver(input, id, layout.x, layout.y, layout.w, layout.h)
+	if !state.Hovered {
+		return
+	}
+	input.GuiCaptured = true
+	if input.JustPressed[MouseButtonLeft] {
+		*clickConsumed = true
+		if !header.selected && header.onChange != nil {
+			header.onChange(header.index)
+		}
+	}
+}
+
+func uiHandleTooltipInput(layout *uiLayoutNode, eid EntityId, ctx uiLayoutContext, input *Input, runtime *UiRuntime) {
+	id := uiWidgetID(eid, layout.key)
+	state := runtime.touch(id)
+	state.Hovered = runtime.pointerOver(input, id, layout.x, layout.y, layout.w, layout.h)
+	if !state.Hovered || input.JustPressed[MouseButtonLeft] {
+		state.HoverTime = 0
+		return
+	}
+	state.HoverTime += ctx.dt
+}
+
+func uiRenderCheckbox(layout *uiLayoutNode, ctx uiLayoutContext, box UiCheckbox, state *uiWidgetState) {
+	scale := uiNodeScale(box.Scale) * ctx.scale
+	uiDrawText(ctx, uiCheckboxText(box), layout.x, layout.y, scale, uiWidgetTextColor(ctx, state))
+}
+
+func uiRenderSlider(layout *uiLayoutNode, ctx uiLayoutContext, slider UiSlider, state *uiWidgetState) {
+	scale := uiNodeScale(slider.Scale) * ctx.scale
+	color := [4]float32{1, 1, 1, 1}
+	if state.Hovered || state.Dragging {
+		color = [4]float32{1, 1, 0, 1}
+	}
+	fraction := uiProgressFraction(slider.Value, slider.Min, slider.Max)
+	if fill := (layout.w - uiFieldPaddingX*2) * fraction; fill > 0 {
+		uiDrawRect(ctx, layout.x+uiFieldPaddingX, layout.y+uiFieldPaddingY, fill, layout.h-uiFieldPaddingY*2, uiHighlightBgColor)
+	}
+	uiDrawButtonBox(ctx, layout.x, layout.y, layout.w, layout.h, scale, color, uiSliderValueLabel(slider), false, false, UiTextAlignCenter)
+}
+
+func uiRenderDropdown(layout *uiLayoutNode, ctx uiLayoutContext, dropdown UiDropdown, state *uiWidgetState, runtime *UiRuntime) {
+	scale := uiNodeScale(dropdown.Scale) * ctx.scale
+	color := [4]float32{1, 1, 1, 1}
+	if state.Hovered || state.Open {
+		color = [4]float32{1, 1, 0, 1}
+	}
+	label := uiDropdownLabel(dropdown)
+	placeholder := dropdown.Selected < 0 || dropdown.Selected >= len(dropdown.Options)
+	uiDrawButtonBox(ctx, layout.x, layout.y, layout.w, layout.h, scale, color, label+" v", false, placeholder, UiTextAlignLeft)
+	if !state.Open {
+		return
+	}
+
+	x, y, w, h, rowH := uiDropdownPopupRect(layout, ctx, dropdown)
+	mx, my := float32(ctx.input.MouseX), float32(ctx.input.MouseY)
+	navIndex := -1
+	if runtime.focusVisible {
+		navIndex = state.NavIndex
+	}
+	runtime.deferDraw(func() {
+		uiDrawRect(ctx, x, y, w, h, uiPopupBgColor)
+		for idx, option := range dropdown.Options {
+			rowY := y + uiFieldPaddingY + float32(idx)*rowH
+			textColor := [4]float32{1, 1, 1, 1}
+			if idx == navIndex || uiPointInRect(mx, my, x, rowY, w, rowH) {
+				uiDrawRect(ctx, x, rowY, w, rowH, uiHighlightBgColor)
+			}
+			if idx == dropdown.Selected {
+				textColor = [4]float32{1, 1, 0, 1}
+			}
+			uiDrawText(ctx, option, x+uiFieldPaddingX, rowY, scale, textColor)
+		}
+	})
+}
+
+func uiRenderList(layout *uiLayoutNode, ctx uiLayoutContext, list UiList, state *uiWidgetState) {
+	scale := uiNodeScale(list.Scale) * ctx.scale
+	layout.scrollY = clampUiScroll(state.ScrollY, layout.scrollMax)
+	color := [4]float32{1, 1, 1, 1}
+	if state.Hovered {
+		color = [4]float32{1, 1, 0.5, 1}
+	}
+	uiDrawBox(ctx, layout.x, layout.y, layout.w, layout.h, color, scale)
+
+	rowH := uiListRowHeight(layout)
+	textX := layout.x + uiPanelBorderWidth(ctx, scale) + uiFieldPaddingX
+	rowW := layout.w - uiPanelBorderWidth(ctx, scale)*2 - uiListScrollbarW
+	for idx, item := range list.Items {
+		rowY := layout.contentTop + float32(idx)*rowH - layout.scrollY
+		if rowY < layout.contentTop-0.5 || rowY+rowH > layout.contentBottom+0.5 {
+			continue
+		}
+		textColor := uiWidgetTextColor(ctx, nil)
+		if idx == list.Selected {
+			uiDrawRect(ctx, layout.x+uiPanelBorderWidth(ctx, scale), rowY, rowW, rowH, uiHighlightBgColor)
+			textColor = [4]float32{1, 1, 0, 1}
+		}
+		uiDrawText(ctx, item, textX, rowY, scale, textColor)
+	}
+	uiDrawScrollbar(ctx, layout)
+}
+
+func uiRenderTreeRow(layout *uiLayoutNode, ctx uiLayoutContext, row uiTreeRow, state *uiWidgetState) {
+	color := uiWidgetTextColor(ctx, state)
+	if row.selected {
+		uiDrawRect(ctx, layout.x, layout.y, layout.w, layout.h, uiHighlightBgColor)
+		color = [4]float32{1, 1, 0, 1}
+	}
+	uiDrawText(ctx, uiTreeRowText(row), layout.x, layout.y, row.scale, color)
+}
+
+func uiRenderTab(layout *uiLayoutNode, ctx uiLayoutContext, header uiTabHeader, state *uiWidgetState) {
+	color := [4]float32{0.65, 0.65, 0.65, 1}
+	switch {
+	case header.selected:
+		color = [4]float32{1, 1, 0, 1}
+	case state.Hovered:
+		color = [4]float32{1, 1, 0.5, 1}
+	}
+	uiDrawButtonBox(ctx, layout.x, layout.y, layout.w, layout.h, header.scale, color, header.label, false, false, UiTextAlignCenter)
+}
+
+func uiRenderTooltip(layout *uiLayoutNode, ctx uiLayoutContext, tip UiTooltip, state *uiWidgetState, runtime *UiRuntime) {
+	if !uiTooltipVisible(tip, state) {
+		return
+	}
+	scale := uiNodeScale(tip.Scale) * ctx.scale
+	textW, _ := uiMeasureText(ctx, tip.Text, scale)
+	w := textW + uiFieldPaddingX*2
+	h := uiTextHeight(ctx, scale) + uiFieldPaddingY*2
+	x := float32(ctx.input.MouseX) + 12
+	y := float32(ctx.input.MouseY) + 18
+	if limit := float32(ctx.input.WindowWidth) - w; x > limit {
+		x = maxf(0, limit)
+	}
+	if limit := float32(ctx.input.WindowHeight) - h; y > limit {
+		y = float32(ctx.input.MouseY) - h - 4
+	}
+	runtime.deferDraw(func() {
+		uiDrawRect(ctx, x, y, w, h, uiPopupBgColor)
+		uiDrawText(ctx, tip.Text, x+uiFieldPaddingX, y+uiFieldPaddingY, scale, [4]float32{1, 1, 1, 1})
+	})
+}
+
+// uiWidgetTextColor is the plain text color, brightened while hovered.
+func uiWidgetTextColor(ctx uiLayoutContext, state *uiWidgetState) [4]float32 {
+	if state != nil && state.Hovered {
+		return [4]float32{1, 1, 0, 1}
+	}
+	if ctx.textColor != ([4]float32{}) {
+		return ctx.textColor
+	}
+	return [4]float32{1, 1, 1, 1}
+}
+
+// uiScrollableAt reports whether a scrollable list below the panel wants the
+// wheel at the given point.
+func uiScrollableAt(layout *uiLayoutNode, px, py float32) bool {
+	for _, child := range layout.children {
+		if child.kind == uiNodeList && child.scrollMax > 0 && uiPointInRect(px, py, child.x, child.y, child.w, child.h) {
+			return true
+		}
+		if uiScrollableAt(child, px, py) {
+			return true
+		}
+	}
+	return false
+}
+
+func uiCheckboxText(box UiCheckbox) string {
+	glyph := "[ ]"
+	switch {
+	case box.Switch && box.Checked:
+		glyph = "[ON ]"
+	case box.Switch:
+		glyph = "[OFF]"
+	case box.Checked:
+		glyph = "[x]"
+	}
+	if box.Label == "" {
+		return glyph
+	}
+	return glyph + " " + box.Label
+}
+
+func uiSliderValueLabel(slider UiSlider) string {
+	return formatUiNumber(slider.Value, slider.Precision)
+}
+
+// uiSliderValueAt maps a pointer x across the slider box to a value, snapped
+// to Step and clamped to the range.
+func uiSliderValueAt(layout *uiLayoutNode, slider UiSlider, px float32) float32 {
+	if slider.Max <= slider.Min {
+		return slider.Min
+	}
+	fraction := float32(0)
+	if trackW := layout.w - uiFieldPaddingX*2; trackW > 0 {
+		fraction = max(0, min(1, (px-layout.x-uiFieldPaddingX)/trackW))
+	}
+	value := slider.Min + fraction*(slider.Max-slider.Min)
+	if slider.Step > 0 {
+		steps := math.Round(float64((value - slider.Min) / slider.Step))
+		value = min(slider.Max, slider.Min+float32(steps)*slider.Step)
+	}
+	return value
+}
+
+func uiDropdownLabel(dropdown UiDropdown) string {
+	if dropdown.Selected >= 0 && dropdown.Selected < len(dropdown.Options) {
+		return dropdown.Options[dropdown.Selected]
+	}
+	return dropdown.Placeholder
+}
+
+func uiDropdownPopupRect(layout *uiLayoutNode, ctx uiLayoutContext, dropdown UiDropdown) (x, y, w, h, rowH float32) {
+	rowH = uiTextHeight(ctx, uiNodeScale(dropdown.Scale)*ctx.scale)
+	h = rowH*float32(len(dropdown.Options)) + uiFieldPaddingY*2
+	return layout.x, layout.y + layout.h, layout.w, h, rowH
+}
+
+func uiListRows(list UiList) int {
+	if list.Rows > 0 {
+		return list.Rows
+	}
+	return max(1, min(len(list.Items), uiListRowsDefault))
+}
+
+func uiListRowHeight(layout *uiLayoutNode) float32 {
+	return layout.contentTop - layout.y
+}
+
+// uiListIndexAt returns the item under a pointer y inside the list viewport.
+func uiListIndexAt(layout *uiLayoutNode, count int, py float32) (int, bool) {
+	rowH := uiListRowHeight(layout)
+	if rowH <= 0 || py < layout.contentTop || py >= layout.contentBottom {
+		return 0, false
+	}
+	index := int((py - layout.contentTop + layout.scrollY) / rowH)
+	if index < 0 || index >= count {
+		return 0, false
+	}
+	return index, true
+}
+
+func uiTreeRowText(row uiTreeRow) string {
+	marker := " - "
+	if len(row.item.Children) > 0 {
+		marker = "[+]"
+		if row.open {
+			marker = "[-]"
+		}
+	}
+	return marker + " " + row.item.Label
+}
+
+// uiTreeMarkerWidth is the clickable expand/collapse area at the start of a
+// tree row.
+func uiTreeMarkerWidth(ctx uiLayoutContext, scale float32) float32 {
+	if w, _ := uiMeasureText(ctx, "[+] ", scale); w > 0 {
+		return w
+	}
+	return uiTextHeight(ctx, scale)
+}
+
+func uiTabsSelected(tabs UiTabs) int {
+	if len(tabs.Tabs) == 0 {
+		return -1
+	}
+	if tabs.Selected < 0 || tabs.Selected >= len(tabs.Tabs) {
+		return 0
+	}
+	return tabs.Selected
+}
+
+func uiTooltipVisible(tip UiTooltip, state *uiWidgetState) bool {
+	delay := tip.Delay
+	if delay <= 0 {
+		delay = uiTooltipDelay
+	}
+	return tip.Text != "" && state.Hovered && state.HoverTime >= delay
+}
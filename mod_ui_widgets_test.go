@@ -0,0 +1,277 @@
+package gekko
+
+import "testing"
+
+// uiTestContext lays out without a renderer: text measures zero wide and
+// every line is uiMinBoxLineH tall.
+func uiTestContext(runtime *UiRuntime, input *Input) uiLayoutContext {
+	return uiLayoutContext{input: input, runtime: runtime, eid: 7, dt: 0.1, pixelRatio: 1, scale: 1}
+}
+
+func uiTestFrame(runtime *UiRuntime, layout *uiLayoutNode, ctx uiLayoutContext) bool {
+	runtime.beginFrame()
+	clickedField, clickConsumed, hasFocusedField := "", false, false
+	uiHandleInput(layout, layout, ctx.eid, ctx, ctx.input, runtime, &clickedField, &clickConsumed, &hasFocusedField)
+	return clickConsumed
+}
+
+func uiTestClick(input *Input, x, y float32) {
+	input.MouseX, input.MouseY = float64(x), float64(y)
+	input.JustPressed[MouseButtonLeft] = true
+	input.Pressed[MouseButtonLeft] = true
+}
+
+func uiTestRelease(input *Input) {
+	input.JustPressed[MouseButtonLeft] = false
+	input.Pressed[MouseButtonLeft] = false
+}
+
+func TestUiCheckboxTogglesOnClick(t *testing.T) {
+	runtime := newUiRuntime()
+	input := &Input{MouseX: -1, MouseY: -1}
+	ctx := uiTestContext(runtime, input)
+	checked := false
+	box := UiCheckbox{Label: "Fullscreen", Width: 120, OnChange: func(v bool) { checked = v }}
+	layout := uiLayoutNodeFor(box, "panel/0", 10, 20, 200, ctx)
+	if layout.kind != uiNodeCheckbox || layout.w != 120 || layout.h != uiMinBoxLineH {
+		t.Fatalf("unexpected checkbox layout: %+v", layout)
+	}
+
+	uiTestClick(input, 15, 25)
+	if !uiTestFrame(runtime, layout, ctx) || !checked || !input.GuiCaptured {
+		t.Fatalf("expected click to toggle the checkbox on and capture the mouse")
+	}
+	if got := uiCheckboxText(UiCheckbox{Label: "Mute", Switch: true, Checked: true}); got != "[ON ] Mute" {
+		t.Fatalf("unexpected switch text %q", got)
+	}
+}
+
+func TestUiSliderDragSnapsToStep(t *testing.T) {
+	runtime := newUiRuntime()
+	input := &Input{MouseX: -1, MouseY: -1}
+	ctx := uiTestContext(runtime, input)
+	var changed, committed []float32
+	slider := UiSlider{
+		Value: 0, Min: 0, Max: 10, Step: 1, Width: 112,
+		OnChange: func(v float32) { changed = append(changed, v) },
+		OnCommit: func(v float32) { committed = append(committed, v) },
+	}
+	layout := uiLayoutNodeFor(slider, "panel/0", 0, 0, 200, ctx)
+	if layout.w != 112 {
+		t.Fatalf("expected configured slider width, got %v", layout.w)
+	}
+
+	// The track spans x 6..106, so x=40 is 34% of the range.
+	uiTestClick(input, 40, 10)
+	uiTestFrame(runtime, layout, ctx)
+	input.JustPressed[MouseButtonLeft] = false
+	input.MouseX = 500 // dragging keeps going past the box and clamps
+	uiTestFrame(runtime, layout, ctx)
+	uiTestRelease(input)
+	uiTestFrame(runtime, layout, ctx)
+
+	if len(changed) != 3 || changed[0] != 3 || changed[1] != 10 {
+		t.Fatalf("unexpected slider changes %v", changed)
+	}
+	if len(committed) != 1 || committed[0] != 10 {
+		t.Fatalf("expected one commit at release, got %v", committed)
+	}
+	if runtime.touch(uiWidgetID(7, layout.key)).Dragging {
+		t.Fatalf("expected release to end the drag")
+	}
+}
+
+func TestUiDropdownPopupSelectsAndBlocksWidgetsBelow(t *testing.T) {
+	runtime := newUiRuntime()
+	input := &Input{MouseX: -1, MouseY: -1}
+	ctx := uiTestContext(runtime, input)
+	selected := -1
+	clicked := false
+	column := UiColumn{Spacing: 4, Children: []UiNode{
+		UiDropdown{Key: "quality", Options: []string{"Low", "Medium", "High"}, Width: 120, OnChange: func(i int) { selected = i }},
+		UiButtonControl{Key: "apply", Label: "Apply", Width: 120, OnClick: func() { clicked = true }},
+	}}
+	layout := uiLayoutNodeFor(column, "panel/0", 0, 0, 200, ctx)
+	dropdown, button := layout.children[0], layout.children[1]
+	if dropdown.h != 84 || button.y != 88 {
+		t.Fatalf("unexpected dropdown column layout: dropdown h=%v button y=%v", dropdown.h, button.y)
+	}
+
+	uiTestClick(input, 10, 10)
+	uiTestFrame(runtime, layout, ctx)
+	id := uiWidgetID(7, dropdown.key)
+	if !runtime.touch(id).Open || runtime.popup.owner != id {
+		t.Fatalf("expected click to open the dropdown popup")
+	}
+	if runtime.popup.y != 84 || runtime.popup.h != 3*uiMinBoxLineH+uiFieldPaddingY*2 {
+		t.Fatalf("unexpected popup rect %+v", runtime.popup)
+	}
+
+	// "High" is the third row and overlaps the Apply button.
+	uiTestClick(input, 10, 84+uiFieldPaddingY+2*uiMinBoxLineH+4)
+	uiTestFrame(runtime, layout, ctx)
+	if selected != 2 {
+		t.Fatalf("expected third option to be picked, got %d", selected)
+	}
+	if clicked {
+		t.Fatalf("expected popup to block the button underneath")
+	}
+	if runtime.touch(id).Open {
+		t.Fatalf("expected picking an option to close the popup")
+	}
+	runtime.beginFrame()
+	if runtime.popup.owner != "" {
+		t.Fatalf("expected the closed popup to stop blocking on the next frame")
+	}
+}
+
+func TestUiListLayoutScrollsAndSelects(t *testing.T) {
+	runtime := newUiRuntime()
+	input := &Input{MouseX: -1, MouseY: -1}
+	ctx := uiTestContext(runtime, input)
+	selected := -1
+	list := UiList{Key: "maps", Items: []string{"a", "b", "c", "d", "e"}, Rows: 3, Width: 150, OnSelect: func(i int) { selected = i }}
+	layout := uiLayoutNodeFor(list, "panel/0", 0, 0, 200, ctx)
+	if layout.h != 5*uiMinBoxLineH || layout.contentTop != uiMinBoxLineH || layout.scrollMax != 2*uiMinBoxLineH {
+		t.Fatalf("unexpected list layout: h=%v top=%v scrollMax=%v", layout.h, layout.contentTop, layout.scrollMax)
+	}
+
+	input.MouseX, input.MouseY = 20, 40
+	input.MouseScrollY = -1
+	uiTestFrame(runtime, layout, ctx)
+	if layout.scrollY != uiMinBoxLineH {
+		t.Fatalf("expected wheel to scroll one row, got %v", layout.scrollY)
+	}
+
+	input.MouseScrollY = 0
+	uiTestClick(input, 20, uiMinBoxLineH+5)
+	uiTestFrame(runtime, layout, ctx)
+	if selected != 1 {
+		t.Fatalf("expected first visible row to be item 1 after scrolling, got %d", selected)
+	}
+
+	panel := &uiLayoutNode{kind: uiNodePanel, children: []*uiLayoutNode{layout}}
+	if !uiScrollableAt(panel, 20, 40) || uiScrollableAt(panel, 400, 40) {
+		t.Fatalf("expected the list to take the wheel only under the pointer")
+	}
+}
+
+func TestUiTreeLayoutFollowsExpansionState(t *testing.T) {
+	runtime := newUiRuntime()
+	input := &Input{MouseX: -1, MouseY: -1}
+	ctx := uiTestContext(runtime, input)
+	var selected string
+	tree := UiTree{
+		Key:   "scene",
+		Width: 200,
+		Nodes: []UiTreeNode{
+			{Key: "world", Label: "World", Expanded: true, Children: []UiTreeNode{
+				{Key: "lights", Label: "Lights", Children: []UiTreeNode{{Key: "sun", Label: "Sun"}}},
+				{Key: "player", Label: "Player"},
+			}},
+		},
+		OnSelect: func(key string) { selected = key },
+	}
+	layout := uiLayoutNodeFor(tree, "panel/0", 0, 0, 200, ctx)
+	if len(layout.children) != 3 || layout.h != 3*uiMinBoxLineH {
+		t.Fatalf("expected world, lights and player rows, got %d rows h=%v", len(layout.children), layout.h)
+	}
+	lights := layout.children[1]
+	if lights.x != uiTreeIndent || uiTreeRowText(lights.node.(uiTreeRow)) != "[+] Lights" {
+		t.Fatalf("unexpected lights row %+v", lights)
+	}
+
+	// Clicking the marker expands; clicking the label selects.
+	uiTestClick(input, lights.x+2, lights.y+2)
+	uiTestFrame(runtime, layout, ctx)
+	layout = uiLayoutNodeFor(tree, "panel/0", 0, 0, 200, ctx)
+	if len(layout.children) != 4 || layout.children[2].x != 2*uiTreeIndent {
+		t.Fatalf("expected the sun row under expanded lights, got %d rows", len(layout.children))
+	}
+	player := layout.children[3]
+	uiTestClick(input, player.x+100, player.y+2)
+	uiTestFrame(runtime, layout, ctx)
+	if selected != "player" {
+		t.Fatalf("expected player to be selected, got %q", selected)
+	}
+}
+
+func TestUiTabsLayoutShowsSelectedContent(t *testing.T) {
+	runtime := newUiRuntime()
+	input := &Input{MouseX: -1, MouseY: -1}
+	ctx := uiTestContext(runtime, input)
+	changed := -1
+	tabs := UiTabs{
+		Key:      "settings",
+		Selected: 1,
+		Spacing:  4,
+		Tabs: []UiTab{
+			{Label: "Video", Content: UiLabel{Key: "video", Text: "video"}},
+			{Label: "Audio", Content: UiLabel{Key: "audio", Text: "audio"}},
+		},
+		OnChange: func(i int) { changed = i },
+	}
+	layout := uiLayoutNodeFor(tabs, "panel/0", 0, 0, 200, ctx)
+	if len(layout.children) != 3 {
+		t.Fatalf("expected two headers and one content node, got %d", len(layout.children))
+	}
+	content := layout.children[2]
+	if content.key != "label/audio" || content.y != 84+4 || layout.h != 84+4+uiMinBoxLineH {
+		t.Fatalf("unexpected tab content layout: key=%q y=%v h=%v", content.key, content.y, layout.h)
+	}
+	if second := layout.children[1]; second.x != uiFieldPaddingX*2+4 {
+		t.Fatalf("expected second tab after the first, got x=%v", second.x)
+	}
+
+	uiTestClick(input, 2, 10)
+	uiTestFrame(runtime, layout, ctx)
+	if changed != 0 {
+		t.Fatalf("expected clicking the first tab to select it, got %d", changed)
+	}
+}
+
+func TestUiTooltipWaitsForHoverDelay(t *testing.T) {
+	runtime := newUiRuntime()
+	input := &Input{MouseX: 5, MouseY: 5}
+	ctx := uiTestContext(runtime, input)
+	tip := UiTooltip{Key: "hint", Text: "Saves the map", Delay: 0.25, Child: UiButtonControl{Label: "Save", Width: 80}}
+	layout := uiLayoutNodeFor(tip, "panel/0", 0, 0, 200, ctx)
+	if layout.w != 80 || len(layout.children) != 1 {
+		t.Fatalf("expected tooltip to wrap its child layout, got %+v", layout)
+	}
+
+	state := runtime.touch(uiWidgetID(7, layout.key))
+	uiTestFrame(runtime, layout, ctx)
+	uiTestFrame(runtime, layout, ctx)
+	if uiTooltipVisible(tip, state) {
+		t.Fatalf("expected tooltip to stay hidden before the delay")
+	}
+	uiTestFrame(runtime, layout, ctx)
+	if !uiTooltipVisible(tip, state) {
+		t.Fatalf("expected tooltip after %vs of hover, got %v", tip.Delay, state.HoverTime)
+	}
+
+	input.MouseX = 500
+	uiTestFrame(runtime, layout, ctx)
+	if uiTooltipVisible(tip, state) || state.HoverTime != 0 {
+		t.Fatalf("expected leaving the widget to reset the tooltip")
+	}
+}
+
+func TestUiTopModalPicksLastVisibleModal(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	cmd.AddEntity(&UiPanel{Visible: true})
+	first := cmd.AddEntity(&UiPanel{Visible: true, Modal: true})
+	cmd.AddEntity(&UiPanel{Visible: false, Modal: true})
+	app.FlushCommands()
+
+	panels := uiVisiblePanels(cmd, nil)
+	if len(panels) != 2 {
+		t.Fatalf("expected hidden panels to be skipped, got %d", len(panels))
+	}
+	modal, ok := uiTopModal(panels)
+	if !ok || modal.eid != first || !modal.panel.Modal {
+		t.Fatalf("expected the visible modal panel, got %v %v", modal.eid, ok)
+	}
+}
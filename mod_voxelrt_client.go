@@ -25,6 +25,16 @@ func voxelSphereEditWithTransform(xbm *volume.XBrickMap, tr *core.Transform, wor
 	volume.Sphere(xbm, voxelCenter, radius/avgScale, val)
 }
 
+// voxelBrushEditWithTransform applies a brush whose shape is authored in
+// world space to an object's voxel space.
+func voxelBrushEditWithTransform(xbm *volume.XBrickMap, tr *core.Transform, edit volume.BrushEdit) volume.BrushEditResult {
+	if xbm == nil || tr == nil || edit.Shape == nil {
+		return volume.BrushEditResult{}
+	}
+	edit.Shape = volume.NewSDFTransformed(edit.Shape, tr.WorldToObject())
+	return volume.ApplyBrush(xbm, edit)
+}
+
 type RaycastHit struct {
 	Hit          bool
 	T            float32
@@ -78,6 +88,8 @@ type VoxelRtFeatureConfig = app_rt.AppFeatureConfig
 type VoxelRtRenderFeature = app_rt.Feature
 type VoxelRtRenderNode = app_rt.RenderNode
 type VoxelRtRenderNodeSpec = app_rt.RenderNodeSpec
+type VoxelRtRenderResourceDesc = app_rt.RenderResourceDesc
+type DynamicResolutionSettings = app_rt.DynamicResolutionSettings
 
 const (
 	LightingQualityPerformance = core.LightingQualityPresetPerformance
@@ -116,6 +128,18 @@ type VoxelRtModule struct {
 	BridgeFeatures   []VoxelRtBridgeFeatureRegistration
 	RenderFeatures   []VoxelRtRenderFeature
 	RenderGraphNodes []VoxelRtRenderNodeSpec
+	// FontFallbackPaths cover scripts the UI font lacks, such as CJK. Nil
+	// uses the common system fonts that exist.
+	FontFallbackPaths []string
+	// RenderGraphResources declares textures and buffers that custom graph
+	// nodes list in Reads and Writes.
+	RenderGraphResources []VoxelRtRenderResourceDesc
+	// EntityLODChangeBudget caps how many entities may switch LOD band per
+	// frame. Zero means unlimited.
+	EntityLODChangeBudget int
+	// DynamicResolution lowers the internal render scale when the G-buffer and
+	// lighting passes exceed their time target.
+	DynamicResolution DynamicResolutionSettings
 }
 
 type VoxelRtState struct {
@@ -126,6 +150,7 @@ type VoxelRtState struct {
 	instanceObjectScopedGeometry map[EntityId]bool
 	runtimeEditedVoxelEntities   map[EntityId]struct{}
 	entityLODSelections          map[EntityId]EntityLODSelection
+	entityLODChangeBudget        int
 	runtimeSprites               []SpriteComponent
 	lastMaterialKeys             map[*core.VoxelObject]materialTableCacheKey
 	materialTableCache           map[materialTableCacheKey][]core.Material
@@ -134,6 +159,10 @@ type VoxelRtState struct {
 	objectToEntity               map[*core.VoxelObject]EntityId
 	skyboxLayers                 map[EntityId]SkyboxLayerComponent // Stored values to detect changes
 	skyboxSun                    SkyboxSunComponent
+	postProcessSettings          PostProcessSettings
+	renderViewEntities           []EntityId
+	renderViewLastTick           map[EntityId]uint64
+	renderViewTick               uint64
 	SunDirection                 mgl32.Vec3
 	SunIntensity                 float32
 	lastParticleAtlas            AssetId
@@ -148,6 +177,25 @@ func (s *VoxelRtState) WindowSize() (int, int) {
 	return int(s.RtApp.Config.Width), int(s.RtApp.Config.Height)
 }
 
+// EntityLODSelection returns the LOD selection the renderer used for eid this
+// frame, including any cross-fade in progress.
+func (s *VoxelRtState) EntityLODSelection(eid EntityId) (EntityLODSelection, bool) {
+	if s == nil {
+		return EntityLODSelection{}, false
+	}
+	selection, ok := s.entityLODSelections[eid]
+	return selection, ok
+}
+
+// PostProcessSettings returns the blended post-process settings sent to the
+// renderer for the current frame.
+func (s *VoxelRtState) PostProcessSettings() PostProcessSettings {
+	if s == nil {
+		return DefaultPostProcessSettings()
+	}
+	return s.postProcessSettings
+}
+
 func (s *VoxelRtState) FPS() float64 {
 	if s == nil || s.RtApp == nil {
 		return 0
@@ -188,6 +236,15 @@ func (s *VoxelRtState) DrawRect(x, y, w, h float32, color [4]float32) {
 	}
 }
 
+// DrawImage queues a textured screen rect in the overlay pass, in order with
+// DrawRect and below text. uv (u0, v0, u1, v1) selects the texture region;
+// the zero value uses the whole texture.
+func (s *VoxelRtState) DrawImage(x, y, w, h float32, texture AssetId, uv [4]float32, color [4]float32) {
+	if s != nil && s.RtApp != nil && texture != (AssetId{}) {
+		s.RtApp.DrawImage(x, y, w, h, spriteAtlasKey(texture), uv, color)
+	}
+}
+
 func (s *VoxelRtState) MeasureText(text string, scale float32) (float32, float32) {
 	if s == nil || s.RtApp == nil {
 		return 0, 0
@@ -254,6 +311,29 @@ func (s *VoxelRtState) SetLightingQuality(cfg LightingQualityConfig) {
 	}
 }
 
+// SetDynamicResolution replaces the render-scale controller settings.
+func (s *VoxelRtState) SetDynamicResolution(settings DynamicResolutionSettings) {
+	if s != nil && s.RtApp != nil {
+		s.RtApp.DynamicResolution = settings
+	}
+}
+
+// SetRenderScale sets the internal render scale per axis. With dynamic
+// resolution enabled the controller continues from it.
+func (s *VoxelRtState) SetRenderScale(scale float32) {
+	if s != nil && s.RtApp != nil {
+		s.RtApp.SetRenderScale(scale)
+	}
+}
+
+// RenderScale returns the internal render scale currently in use.
+func (s *VoxelRtState) RenderScale() float32 {
+	if s == nil || s.RtApp == nil {
+		return 1
+	}
+	return s.RtApp.RenderScale()
+}
+
 func (s *VoxelRtState) GetTextAscent(scale float32) float32 {
 	if s == nil || s.RtApp == nil {
 		return 0
@@ -317,6 +397,23 @@ func (s *VoxelRtState) VoxelSphereEdit(eid EntityId, worldCenter mgl32.Vec3, rad
 	s.markRuntimeEditedVoxelEntity(eid)
 }
 
+// VoxelBrushEdit applies a world-space SDF brush to the entity's voxels and
+// returns the touched region in object voxel coordinates.
+func (s *VoxelRtState) VoxelBrushEdit(eid EntityId, edit volume.BrushEdit) volume.BrushEditResult {
+	if s == nil {
+		return volume.BrushEditResult{}
+	}
+	obj := s.GetVoxelObject(eid)
+	if obj == nil || obj.XBrickMap == nil {
+		return volume.BrushEditResult{}
+	}
+	result := voxelBrushEditWithTransform(obj.XBrickMap, obj.Transform, edit)
+	if result.Changed > 0 {
+		s.markRuntimeEditedVoxelEntity(eid)
+	}
+	return result
+}
+
 func (s *VoxelRtState) markRuntimeEditedVoxelEntity(eid EntityId) {
 	if s == nil || eid == 0 {
 		return
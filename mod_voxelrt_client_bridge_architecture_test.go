@@ -23,6 +23,7 @@ func TestVoxelRtBridgeRendererInternalsTouchpointsAreAudited(t *testing.T) {
 		"state.RtApp.BufferManager.BeginBatch()": "frame batch boundary",
 		"state.RtApp.BufferManager.EndBatch()":   "frame batch boundary",
 		"if texAsset, ok := spriteAtlasTexture(server, batch.AtlasKey); ok && state.RtApp.BufferManager != nil {": "transitional sprite atlas upload",
+		"if texAsset, ok := spriteAtlasTexture(server, key); ok && state.RtApp.BufferManager != nil {":            "transitional sprite atlas upload",
 		"state.RtApp.BufferManager.SetSpriteAtlas(":                                                               "transitional sprite atlas upload",
 	}
 	gpuHostRef := regexp.MustCompile(`\bgpu_rt\.[A-Za-z0-9_]*Host\b`)
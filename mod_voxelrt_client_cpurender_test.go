@@ -0,0 +1,114 @@
+package gekko
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gekko3d/gekko/voxelrt/rt/core"
+	"github.com/gekko3d/gekko/voxelrt/rt/cpurender"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func cpuRenderTestBox(sizeX, sizeY, sizeZ uint32, color byte) VoxModel {
+	model := VoxModel{SizeX: sizeX, SizeY: sizeY, SizeZ: sizeZ}
+	for x := uint32(0); x < sizeX; x++ {
+		for y := uint32(0); y < sizeY; y++ {
+			for z := uint32(0); z < sizeZ; z++ {
+				model.Voxels = append(model.Voxels, Voxel{X: x, Y: y, Z: z, ColorIndex: color})
+			}
+		}
+	}
+	return model
+}
+
+// buildCPURenderECSScene spawns a lit scene as game code would and syncs it
+// through the voxelrt bridge, so the reference render covers palette
+// materials, transforms, entity LOD and the skybox mapping.
+func buildCPURenderECSScene(t *testing.T) (*VoxelRtState, *core.CameraState) {
+	t.Helper()
+	app := NewApp()
+	cmd := app.Commands()
+	server := newVoxelRtAssetServerTest(t)
+	state := newVoxelRtStateTest()
+
+	floorModel := server.CreateVoxelModel(cpuRenderTestBox(40, 1, 40, 1), 1)
+	boxModel := server.CreateVoxelModel(cpuRenderTestBox(8, 8, 8, 1), 1)
+	gray := server.CreateSimplePalette([4]uint8{200, 200, 200, 255})
+	red := server.CreateSimplePalette([4]uint8{220, 60, 40, 255})
+	blue := server.CreateSimplePalette([4]uint8{60, 90, 220, 255})
+
+	cmd.AddEntity(
+		&TransformComponent{Position: mgl32.Vec3{0, -0.1, 0}, Rotation: mgl32.QuatIdent(), Scale: mgl32.Vec3{1, 1, 1}},
+		&VoxelModelComponent{VoxelModel: floorModel, VoxelPalette: gray},
+	)
+	cmd.AddEntity(
+		&TransformComponent{Position: mgl32.Vec3{0, 0.35, 0}, Rotation: mgl32.QuatRotate(mgl32.DegToRad(30), mgl32.Vec3{0, 1, 0}), Scale: mgl32.Vec3{1, 1, 1}},
+		&VoxelModelComponent{VoxelModel: boxModel, VoxelPalette: red},
+	)
+	// A distant copy of the box drawn through its simplified LOD proxy.
+	cmd.AddEntity(
+		&TransformComponent{Position: mgl32.Vec3{0.6, 0.35, -1.4}, Rotation: mgl32.QuatIdent(), Scale: mgl32.Vec3{1, 1, 1}},
+		&VoxelModelComponent{VoxelModel: boxModel, VoxelPalette: blue},
+		&EntityLODComponent{SelectionValid: true, ActiveRepresentation: EntityLODRepresentationSimplifiedVoxel},
+	)
+	cmd.AddEntity(
+		&TransformComponent{Rotation: mgl32.QuatBetweenVectors(mgl32.Vec3{1, -1, 0}.Normalize(), mgl32.Vec3{-0.4, -1, -0.3}.Normalize()), Scale: mgl32.Vec3{1, 1, 1}},
+		&LightComponent{Type: LightTypeDirectional, Color: [3]float32{1, 0.95, 0.9}, Intensity: 1.2, CastsShadows: true},
+	)
+	cmd.AddEntity(
+		&TransformComponent{Position: mgl32.Vec3{1, 0.6, 1}, Rotation: mgl32.QuatIdent(), Scale: mgl32.Vec3{1, 1, 1}},
+		&LightComponent{Type: LightTypePoint, Color: [3]float32{0.3, 0.5, 1}, Intensity: 1, Range: 3},
+	)
+	cmd.AddEntity(&SkyboxLayerComponent{
+		LayerType: SkyboxLayerGradient,
+		ColorA:    mgl32.Vec3{0.25, 0.2, 0.15},
+		ColorB:    mgl32.Vec3{0.1, 0.3, 0.7},
+		Opacity:   1,
+	})
+	app.FlushCommands()
+
+	voxelRtSystem(nil, state, server, &Time{Dt: 1.0 / 60.0}, cmd, nil)
+	syncVoxelRtLights(state, cmd)
+	state.syncSkybox(cmd, &Time{Dt: 1.0 / 60.0})
+
+	camera := core.NewCameraState()
+	camera.Position = mgl32.Vec3{2.2, 2, 2.6}
+	camera.LookAt = mgl32.Vec3{0, 0.2, 0}
+	return state, camera
+}
+
+func TestCPURenderECSSceneMatchesGolden(t *testing.T) {
+	state, camera := buildCPURenderECSScene(t)
+	if got := len(state.RtApp.Scene.Objects); got != 3 {
+		t.Fatalf("expected the bridge to add three voxel objects, got %d", got)
+	}
+	opts := cpurender.DefaultOptions(64, 48)
+	opts.Sky = state.ReferenceSky()
+	img := cpurender.Render(state.RtApp.Scene, camera, opts)
+	if err := cpurender.CheckGolden(filepath.Join("testdata", "cpurender", "ecs_scene.png"), img, cpurender.Tolerance{Channel: 3, MaxMismatchFraction: 0.01}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReferenceSkyMapsGradientLayersAndSun(t *testing.T) {
+	layers := map[EntityId]SkyboxLayerComponent{
+		1: {LayerType: SkyboxLayerGradient, ColorA: mgl32.Vec3{0.2, 0.2, 0.2}, ColorB: mgl32.Vec3{0, 0.4, 0.8}, Opacity: 1},
+		2: {LayerType: SkyboxLayerGradient, ColorA: mgl32.Vec3{0.1, 0, 0}, ColorB: mgl32.Vec3{0.1, 0, 0}, Opacity: 1, BlendMode: SkyboxBlendAdd, Priority: 1},
+		3: {LayerType: SkyboxLayerStars, ColorA: mgl32.Vec3{1, 1, 1}, ColorB: mgl32.Vec3{1, 1, 1}, Opacity: 1},
+	}
+	sun := SkyboxSunComponent{Direction: mgl32.Vec3{0, -1, 0}, Intensity: 2, DiskColor: mgl32.Vec3{1, 1, 1}, DiskStrength: 1, DiskStart: 0.99, DiskEnd: 0.99}
+	sky := referenceSkyFromSkybox(layers, sun)
+
+	if !sky.Linear || !sky.Ground.ApproxEqual(mgl32.Vec3{0.3, 0.2, 0.2}) || !sky.Horizon.ApproxEqual(mgl32.Vec3{0.2, 0.3, 0.5}) || !sky.Zenith.ApproxEqual(mgl32.Vec3{0.1, 0.4, 0.8}) {
+		t.Fatalf("expected gradient stops blended in priority order, got %+v", sky)
+	}
+	if got := sky.Sample(mgl32.Vec3{1, 1, 0}); !got.ApproxEqualThreshold(mgl32.Vec3{0.2, 0.3, 0.5}.Add(mgl32.Vec3{-0.1, 0.1, 0.3}.Mul(0.70710677)), 1e-5) {
+		t.Fatalf("expected a linear blend like the skybox shader, got %v", got)
+	}
+	if sky.SunDirection != (mgl32.Vec3{0, 1, 0}) || sky.SunColor != (mgl32.Vec3{2, 2, 2}) || sky.SunAngularRadius <= 0 {
+		t.Fatalf("expected the sun disk toward the sun, got %+v", sky)
+	}
+	if got := referenceSkyFromSkybox(nil, SkyboxSunComponent{}); got != cpurender.DefaultSky() {
+		t.Fatalf("expected the default sky without gradient layers, got %+v", got)
+	}
+}
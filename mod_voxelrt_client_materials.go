@@ -491,13 +491,21 @@ func (s *VoxelRtState) materialTableKey(paletteID AssetId, gekkoPalette *VoxelPa
 }
 
 func (s *VoxelRtState) buildMaterialTable(key materialTableCacheKey, gekkoPalette *VoxelPaletteAsset) []core.Material {
-	if s != nil {
-		s.ensureMaterialCaches()
-		if cached, ok := s.materialTableCache[key]; ok {
-			return cached
-		}
+	if s == nil {
+		return materialTableFromPalette(gekkoPalette)
 	}
+	s.ensureMaterialCaches()
+	if cached, ok := s.materialTableCache[key]; ok {
+		return cached
+	}
+	materialTable := materialTableFromPalette(gekkoPalette)
+	s.materialTableCache[key] = materialTable
+	return materialTable
+}
 
+// materialTableFromPalette converts a palette and its MagicaVoxel and PBR
+// material data into the renderer's 256-entry material table.
+func materialTableFromPalette(gekkoPalette *VoxelPaletteAsset) []core.Material {
 	materialTable := make([]core.Material, 256)
 
 	matMap := make(map[int]VoxMaterial)
@@ -554,10 +562,6 @@ func (s *VoxelRtState) buildMaterialTable(key materialTableCacheKey, gekkoPalett
 		}
 		materialTable[i] = mat
 	}
-
-	if s != nil {
-		s.materialTableCache[key] = materialTable
-	}
 	return materialTable
 }
 
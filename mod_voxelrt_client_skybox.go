@@ -1,9 +1,11 @@
 package gekko
 
 import (
+	"math"
 	"sort"
 
 	app_rt "github.com/gekko3d/gekko/voxelrt/rt/app"
+	"github.com/gekko3d/gekko/voxelrt/rt/cpurender"
 	"github.com/go-gl/mathgl/mgl32"
 )
 
@@ -166,6 +168,64 @@ func buildSkyboxBridgeInput(layerMap map[EntityId]SkyboxLayerComponent, sun Skyb
 	}, true
 }
 
+// ReferenceSky maps the synced skybox into a cpurender.Sky for CPU
+// reference renders. Gradient layers are blended in priority order at the
+// ground, horizon and zenith, as the skybox shader does; noise, star and
+// nebula layers have no CPU equivalent. Without a gradient layer the default
+// reference sky is used. The sun disk comes from the skybox sun.
+func (s *VoxelRtState) ReferenceSky() cpurender.Sky {
+	if s == nil {
+		return cpurender.DefaultSky()
+	}
+	return referenceSkyFromSkybox(s.skyboxLayers, s.skyboxSun)
+}
+
+func referenceSkyFromSkybox(layerMap map[EntityId]SkyboxLayerComponent, sun SkyboxSunComponent) cpurender.Sky {
+	sorted := make([]skyboxBridgeLayer, 0, len(layerMap))
+	for eid, layer := range layerMap {
+		if layer.LayerType == SkyboxLayerGradient {
+			sorted = append(sorted, skyboxBridgeLayer{entityID: eid, layer: layer})
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].layer.Priority != sorted[j].layer.Priority {
+			return sorted[i].layer.Priority < sorted[j].layer.Priority
+		}
+		return sorted[i].entityID < sorted[j].entityID
+	})
+
+	sky := cpurender.DefaultSky()
+	if len(sorted) > 0 {
+		// The shader starts from black and mixes ColorA to ColorB over
+		// dir.y mapped from [-1, 1] to [0, 1].
+		stops := [3]mgl32.Vec3{}
+		for _, li := range sorted {
+			l := li.layer
+			for i, t := range [3]float32{0, 0.5, 1} {
+				c := l.ColorA.Add(l.ColorB.Sub(l.ColorA).Mul(t))
+				switch l.BlendMode {
+				case SkyboxBlendAdd:
+					stops[i] = stops[i].Add(c.Mul(l.Opacity))
+				case SkyboxBlendMultiply:
+					stops[i] = stops[i].Mul(1 - l.Opacity).Add(vec3MulComponents(stops[i], c).Mul(l.Opacity))
+				default:
+					stops[i] = stops[i].Mul(1 - l.Opacity).Add(c.Mul(l.Opacity))
+				}
+			}
+		}
+		sky.Ground, sky.Horizon, sky.Zenith = stops[0], stops[1], stops[2]
+		sky.Linear = true
+	}
+
+	if sun.Intensity > 0 && sun.Direction.LenSqr() > 1e-12 && sun.DiskStart < 1 {
+		edge := (sun.DiskStart + sun.DiskEnd) / 2
+		sky.SunDirection = sun.Direction.Mul(-1)
+		sky.SunColor = sun.DiskColor.Mul(sun.Intensity * sun.DiskStrength)
+		sky.SunAngularRadius = float32(math.Acos(float64(max(-1, min(1, edge)))))
+	}
+	return sky
+}
+
 type skyboxBridgeLayer struct {
 	entityID EntityId
 	layer    SkyboxLayerComponent
@@ -1,6 +1,7 @@
 package gekko
 
 import (
+	"fmt"
 	"math"
 	"sort"
 	"time"
@@ -23,8 +24,10 @@ func (mod VoxelRtModule) Install(app *App, cmd *Commands) {
 	RtApp.RenderMode = uint32(mod.RenderMode)
 	RtApp.QualityPreset = mod.QualityPreset
 	RtApp.LightingQuality = mod.LightingQuality
+	RtApp.DynamicResolution = mod.DynamicResolution
 	RtApp.OcclusionMode = mod.OcclusionMode
 	RtApp.FontPath = mod.FontPath
+	RtApp.FontFallbackPaths = mod.FontFallbackPaths
 	RtApp.UIFontSize = mod.UIFontSize
 	if mod.FeatureConfig != nil {
 		RtApp.FeatureConfig = *mod.FeatureConfig
@@ -42,11 +45,13 @@ func (mod VoxelRtModule) Install(app *App, cmd *Commands) {
 		instanceObjectScopedGeometry: make(map[EntityId]bool),
 		runtimeEditedVoxelEntities:   make(map[EntityId]struct{}),
 		entityLODSelections:          make(map[EntityId]EntityLODSelection),
+		entityLODChangeBudget:        mod.EntityLODChangeBudget,
 		lastMaterialKeys:             make(map[*core.VoxelObject]materialTableCacheKey),
 		materialTableCache:           make(map[materialTableCacheKey][]core.Material),
 		caVolumeMap:                  make(map[EntityId]*core.VoxelObject),
 		objectToEntity:               make(map[*core.VoxelObject]EntityId),
 		skyboxLayers:                 make(map[EntityId]SkyboxLayerComponent),
+		postProcessSettings:          DefaultPostProcessSettings(),
 		bridgeFeatures:               mod.bridgeFeatureRegistry(),
 	}
 	cmd.AddResources(state)
@@ -134,12 +139,15 @@ func (mod VoxelRtModule) applyRenderExtensions(rtApp *app_rt.App) {
 	for _, feature := range mod.RenderFeatures {
 		rtApp.RegisterFeature(feature)
 	}
-	if len(mod.RenderGraphNodes) == 0 {
+	if len(mod.RenderGraphNodes) == 0 && len(mod.RenderGraphResources) == 0 {
 		return
 	}
 	if rtApp.RenderGraph == nil {
 		rtApp.RenderGraph = app_rt.NewDefaultRenderGraph()
 	}
+	for _, desc := range mod.RenderGraphResources {
+		rtApp.RenderGraph.DeclareResource(desc)
+	}
 	for _, spec := range mod.RenderGraphNodes {
 		rtApp.RenderGraph.Register(spec)
 	}
@@ -271,34 +279,103 @@ type caVolumeBudgetCandidate struct {
 	priority          float32
 }
 
-func readEntityLODCameraPosition(cmd *Commands, fallback *core.CameraState) (mgl32.Vec3, bool) {
+func readEntityLODView(cmd *Commands, fallback *core.CameraState, viewportHeight float32) (EntityLODView, bool) {
+	view := EntityLODView{ViewportHeight: viewportHeight}
 	if cmd != nil {
-		found := false
-		position := mgl32.Vec3{}
-		MakeQuery1[CameraComponent](cmd).Map(func(entityId EntityId, camera *CameraComponent) bool {
-			if camera == nil {
-				return true
-			}
-			position = camera.Position
-			found = true
-			return false
-		})
-		if found {
-			return position, true
+		if camera, ok := primaryRenderCamera(cmd); ok {
+			view.Position = camera.Position
+			view.FovY = (&core.CameraState{Fov: camera.Fov}).FovRadians()
+			return view, true
 		}
 	}
 	if fallback != nil {
-		return fallback.Position, true
+		view.Position = fallback.Position
+		view.FovY = fallback.FovRadians()
+		return view, true
+	}
+	return EntityLODView{}, false
+}
+
+// entityLODWorldBounds returns the world AABB of the entity's voxel geometry,
+// or a point at the transform when no geometry resolves.
+func entityLODWorldBounds(cmd *Commands, server *AssetServer, entityId EntityId, transform *TransformComponent) EntityLODBounds {
+	bounds := EntityLODBounds{Min: transform.Position, Max: transform.Position}
+	if server == nil {
+		return bounds
+	}
+	if cmd == nil {
+		return bounds
+	}
+	voxValue, ok := voxelModelComponentForEntity(cmd, entityId)
+	if !ok {
+		return bounds
 	}
-	return mgl32.Vec3{}, false
+	vox := &voxValue
+	_, geometry, ok := ResolveVoxelGeometry(server, vox)
+	if !ok || geometry == nil {
+		return bounds
+	}
+	minB, maxB := entityLODLocalBounds(geometry)
+	if maxB.Sub(minB).LenSqr() <= 0 {
+		return bounds
+	}
+	pivot := entityLODSourcePivot(vox, geometry)
+	scale := EffectiveVoxelScale(vox, transform)
+	for i := 0; i < 8; i++ {
+		corner := mgl32.Vec3{minB.X(), minB.Y(), minB.Z()}
+		if i&1 != 0 {
+			corner[0] = maxB.X()
+		}
+		if i&2 != 0 {
+			corner[1] = maxB.Y()
+		}
+		if i&4 != 0 {
+			corner[2] = maxB.Z()
+		}
+		world := entityLODWorldPoint(transform, corner, pivot, scale)
+		if i == 0 {
+			bounds = EntityLODBounds{Min: world, Max: world}
+			continue
+		}
+		for axis := 0; axis < 3; axis++ {
+			bounds.Min[axis] = min(bounds.Min[axis], world[axis])
+			bounds.Max[axis] = max(bounds.Max[axis], world[axis])
+		}
+	}
+	return bounds
+}
+
+type pendingEntityLODChange struct {
+	entityId  EntityId
+	lod       *EntityLODComponent
+	selection EntityLODSelection
+	bandDelta int
 }
 
-func entityLODSelectionSystem(cmd *Commands, state *VoxelRtState) {
+// entityLODSelectionSystem refreshes every EntityLODComponent. Entities
+// without a selection get one immediately; band changes on the rest are
+// limited to VoxelRtState's change budget per frame, largest jumps and
+// nearest entities first. Deferred entities keep their band and retry next
+// frame.
+func entityLODSelectionSystem(cmd *Commands, state *VoxelRtState, server *AssetServer, t *Time) {
 	cameraState := (*core.CameraState)(nil)
-	if state != nil && state.RtApp != nil {
-		cameraState = state.RtApp.Camera
+	viewportHeight := float32(0)
+	budget := 0
+	if state != nil {
+		budget = state.entityLODChangeBudget
+		if state.RtApp != nil {
+			cameraState = state.RtApp.Camera
+			if state.RtApp.Config != nil {
+				viewportHeight = float32(state.RtApp.Config.Height)
+			}
+		}
+	}
+	dt := float32(0)
+	if t != nil {
+		dt = float32(t.Dt)
 	}
-	cameraPosition, ok := readEntityLODCameraPosition(cmd, cameraState)
+	view, ok := readEntityLODView(cmd, cameraState, viewportHeight)
+	var pending []pendingEntityLODChange
 	MakeQuery2[TransformComponent, EntityLODComponent](cmd).Map(func(entityId EntityId, transform *TransformComponent, lod *EntityLODComponent) bool {
 		if lod == nil || !lod.Enabled() || !ok {
 			if lod != nil {
@@ -306,23 +383,49 @@ func entityLODSelectionSystem(cmd *Commands, state *VoxelRtState) {
 			}
 			return true
 		}
-		selection, err := SelectEntityLOD(cameraPosition, transform, lod)
+		bounds := EntityLODBounds{Min: transform.Position, Max: transform.Position}
+		if lod.NormalizedDistanceMetric() != EntityLODDistanceMetricOrigin {
+			bounds = entityLODWorldBounds(cmd, server, entityId, transform)
+		}
+		selection, err := SelectEntityLODForView(view, bounds, transform, lod)
 		if err != nil {
 			lod.ClearRuntimeSelection()
 			return true
 		}
+		lod.AdvanceTransition(dt)
+		if budget > 0 && lod.SelectionValid && selection.BandIndex != lod.ActiveBandIndex {
+			delta := selection.BandIndex - lod.ActiveBandIndex
+			if delta < 0 {
+				delta = -delta
+			}
+			pending = append(pending, pendingEntityLODChange{entityId: entityId, lod: lod, selection: selection, bandDelta: delta})
+			lod.ActiveDistance = selection.Distance
+			lod.ActiveScreenSize = selection.ScreenSize
+			return true
+		}
 		lod.ApplySelection(selection)
 		return true
 	})
+
+	sort.Slice(pending, func(i, j int) bool {
+		a, b := pending[i], pending[j]
+		if a.bandDelta != b.bandDelta {
+			return a.bandDelta > b.bandDelta
+		}
+		if a.selection.Distance != b.selection.Distance {
+			return a.selection.Distance < b.selection.Distance
+		}
+		return a.entityId < b.entityId
+	})
+	for i := 0; i < len(pending) && i < budget; i++ {
+		pending[i].lod.ApplySelection(pending[i].selection)
+	}
 }
 
 func readCAVolumeBudgetCamera(cmd *Commands, fallback *core.CameraState) caBudgetCameraView {
 	view := caBudgetCameraView{}
 	if cmd != nil {
-		MakeQuery1[CameraComponent](cmd).Map(func(entityId EntityId, camera *CameraComponent) bool {
-			if camera == nil {
-				return true
-			}
+		if camera, ok := primaryRenderCamera(cmd); ok {
 			view.Position = camera.Position
 			forward := camera.LookAt.Sub(camera.Position)
 			if forward.LenSqr() <= 1e-6 {
@@ -337,10 +440,8 @@ func readCAVolumeBudgetCamera(cmd *Commands, fallback *core.CameraState) caBudge
 			if forward.LenSqr() > 1e-6 {
 				view.Forward = forward.Normalize()
 				view.Valid = true
-				return false
 			}
-			return true
-		})
+		}
 	}
 	if !view.Valid && fallback != nil {
 		view.Position = fallback.Position
@@ -691,6 +792,8 @@ func voxelRtSystem(input *Input, state *VoxelRtState, server *AssetServer, t *Ti
 	currentVoxelEntities := make(map[EntityId]bool, len(state.entityLODSelections))
 	frameMaterialKeys := make(map[AssetId]materialTableCacheKey)
 	frameVoxelPalettes := make(map[AssetId]VoxelPaletteAsset)
+	impostorView, _ := readEntityLODView(cmd, state.RtApp.Camera, 0)
+	impostorEye := impostorView.Position
 	elapsed := float64(0)
 	if t != nil {
 		elapsed = t.Elapsed
@@ -707,12 +810,7 @@ func voxelRtSystem(input *Input, state *VoxelRtState, server *AssetServer, t *Ti
 		}
 		currentVoxelEntities[entityId] = true
 		if lod, ok := entityLODComponentForEntity(cmd, entityId); ok && lod.SelectionValid {
-			state.entityLODSelections[entityId] = EntityLODSelection{
-				Distance:       lod.ActiveDistance,
-				BandIndex:      lod.ActiveBandIndex,
-				MaxDistance:    lod.ActiveMaxDistance,
-				Representation: lod.ActiveRepresentation,
-			}
+			state.entityLODSelections[entityId], _ = lod.ActiveSelection()
 		} else {
 			delete(state.entityLODSelections, entityId)
 		}
@@ -728,33 +826,57 @@ func voxelRtSystem(input *Input, state *VoxelRtState, server *AssetServer, t *Ti
 		scaleAdjustX, scaleAdjustY, scaleAdjustZ := float32(1), float32(1), float32(1)
 		spriteBridgeEnabled := state.bridgeFeatureEnabled(voxelRtBridgeFeatureSprites)
 		if selection, ok := state.entityLODSelections[entityId]; ok {
-			switch selection.Representation {
-			case EntityLODRepresentationSimplifiedVoxel:
+			spriteFor := func(representation EntityLODRepresentation) (SpriteComponent, bool) {
+				if !spriteBridgeEnabled {
+					return SpriteComponent{}, false
+				}
+				switch representation {
+				case EntityLODRepresentationImpostor:
+					// The voxel model stays drawn until the atlas is baked.
+					sprite, bake := buildEntityLODImpostorSprite(state, server, transform, vox, geometryID, geometryAsset, impostorEye)
+					switch bake {
+					case entityLODImpostorReady:
+						return sprite, true
+					case entityLODImpostorBaking:
+						return SpriteComponent{}, false
+					}
+					return buildEntityLODDotSprite(state, server, transform, vox, geometryAsset)
+				case EntityLODRepresentationDot:
+					return buildEntityLODDotSprite(state, server, transform, vox, geometryAsset)
+				}
+				return SpriteComponent{}, false
+			}
+
+			// Sprites cross-fade by alpha. Voxel objects are opaque, so a
+			// voxel representation stays drawn, fully, under a fading sprite
+			// until the transition ends.
+			activeAlpha, previousAlpha := selection.TransitionAlpha()
+			voxelRepresentation := selection.Representation
+			sprite, activeIsSprite := spriteFor(selection.Representation)
+			if activeIsSprite {
+				sprite.Color[3] *= activeAlpha
+				state.runtimeSprites = append(state.runtimeSprites, sprite)
+			}
+			previousIsVoxel := false
+			if selection.Transitioning() {
+				if previous, previousIsSprite := spriteFor(selection.PreviousRepresentation); previousIsSprite {
+					previous.Color[3] *= previousAlpha
+					state.runtimeSprites = append(state.runtimeSprites, previous)
+				} else if activeIsSprite {
+					previousIsVoxel = true
+					voxelRepresentation = selection.PreviousRepresentation
+				}
+			}
+			if activeIsSprite && !previousIsVoxel {
+				return true
+			}
+			if voxelRepresentation == EntityLODRepresentationSimplifiedVoxel {
 				simplifiedID, simplifiedAsset, simplifiedOK := server.entityLODSimplifiedGeometry(geometryID, vox.VoxelPalette, geometryAsset)
 				if simplifiedOK && simplifiedAsset != nil && simplifiedAsset.XBrickMap != nil {
 					displayGeometryID = simplifiedID
 					displayGeometryAsset = simplifiedAsset
 					scaleAdjustX, scaleAdjustY, scaleAdjustZ = entityLODProxyScaleAdjust(geometryAsset, simplifiedAsset)
 				}
-			case EntityLODRepresentationImpostor:
-				if spriteBridgeEnabled {
-					sprite, spriteOK := buildEntityLODImpostorSprite(state, server, transform, vox, geometryID, geometryAsset)
-					if !spriteOK {
-						sprite, spriteOK = buildEntityLODDotSprite(state, server, transform, vox, geometryAsset)
-					}
-					if spriteOK {
-						state.runtimeSprites = append(state.runtimeSprites, sprite)
-						return true
-					}
-				}
-			case EntityLODRepresentationDot:
-				if spriteBridgeEnabled {
-					sprite, spriteOK := buildEntityLODDotSprite(state, server, transform, vox, geometryAsset)
-					if spriteOK {
-						state.runtimeSprites = append(state.runtimeSprites, sprite)
-						return true
-					}
-				}
 			}
 		}
 
@@ -896,7 +1018,9 @@ func voxelRtSystem(input *Input, state *VoxelRtState, server *AssetServer, t *Ti
 	state.RtApp.Profiler.EndScope("Sync Instances")
 
 	state.RtApp.Profiler.BeginScope("Sync Lights")
-	MakeQuery1[CameraComponent](cmd).Map(func(entityId EntityId, camera *CameraComponent) bool {
+	cameras := collectRenderCameras(cmd)
+	if cameras.hasPrimary {
+		camera := cameras.primary.camera
 		state.RtApp.Camera.Position = camera.Position
 		state.RtApp.Camera.LookAt = camera.LookAt
 		state.RtApp.Camera.Up = camera.Up
@@ -906,9 +1030,11 @@ func voxelRtSystem(input *Input, state *VoxelRtState, server *AssetServer, t *Ti
 		state.RtApp.Camera.Near = camera.Near
 		state.RtApp.Camera.Far = camera.Far
 		state.RtApp.Camera.DepthMode = camera.DepthMode.Normalized()
-		return false
-	})
+	}
+	syncVoxelRtRenderViews(state, server, cameras)
 	syncVoxelRtLights(state, cmd)
+	syncVoxelRtPostProcess(state, cmd)
+	syncVoxelRtDecals(state, server, cmd)
 }
 
 func buildAnalyticMediumInputs(cmd *Commands, t *Time) []app_rt.AnalyticMediumInput {
@@ -1027,7 +1153,12 @@ func syncVoxelRtParticles(state *VoxelRtState, server *AssetServer, t *Time, cmd
 
 	if atlasId != (AssetId{}) && atlasId != state.lastParticleAtlas && server != nil {
 		if texAsset, ok := server.textures[atlasId]; ok {
-			state.RtApp.SetParticleAtlas(texAsset.Texels, texAsset.Width, texAsset.Height)
+			if texAsset.RenderTarget {
+				// Particles only sample uploaded atlases; keep the previous one.
+				fmt.Printf("WARNING: Particle atlas %s is a render target; only sprites and UI images can show render targets\n", atlasId.UUID)
+			} else {
+				state.RtApp.SetParticleAtlas(texAsset.Texels, texAsset.Width, texAsset.Height)
+			}
 			state.lastParticleAtlas = atlasId
 		}
 	}
@@ -1458,18 +1589,21 @@ func entityLODImpostorBaseSize(vox *VoxelModelComponent, transform *TransformCom
 	return size
 }
 
+// entityLODImpostorSpriteSize is the square card covering the bounding sphere
+// the impostor views were baked on. Non-uniform scale uses the largest axis so
+// no view is cropped.
 func entityLODImpostorSpriteSize(vox *VoxelModelComponent, transform *TransformComponent, source *VoxelGeometryAsset) [2]float32 {
-	baseScale := EffectiveVoxelScale(vox, transform)
-	extentX, extentY, _ := entityLODGeometryExtents(source)
-	width := float32(math.Abs(float64(baseScale.X()))) * extentX * 1.1
-	height := float32(math.Abs(float64(baseScale.Y()))) * extentY * 1.1
-	if width <= 0 {
-		width = 2 * VoxelSize
+	if source == nil {
+		return [2]float32{2 * VoxelSize, 2 * VoxelSize}
 	}
-	if height <= 0 {
-		height = 2 * VoxelSize
+	_, radius, ok := entityLODImpostorSphere(source.XBrickMap)
+	baseScale := EffectiveVoxelScale(vox, transform)
+	scale := max(absf(baseScale.X()), absf(baseScale.Y()), absf(baseScale.Z()))
+	size := 2 * radius * scale
+	if !ok || size <= 0 {
+		size = 2 * VoxelSize
 	}
-	return [2]float32{width, height}
+	return [2]float32{size, size}
 }
 
 func entityLODLuminance(rgb mgl32.Vec3) float32 {
@@ -1515,30 +1649,45 @@ func entityLODDotBrightnessTint(state *VoxelRtState, transform *TransformCompone
 	return clampF(entityLODImpostorBrightnessTint(state, transform)*0.6, 0.08, 0.6)
 }
 
-func buildEntityLODImpostorSprite(state *VoxelRtState, server *AssetServer, transform *TransformComponent, vox *VoxelModelComponent, geometryID AssetId, source *VoxelGeometryAsset) (SpriteComponent, bool) {
+// buildEntityLODImpostorSprite returns entityLODImpostorBaking while the
+// atlas is still baking; the caller keeps drawing the voxel model meanwhile.
+func buildEntityLODImpostorSprite(state *VoxelRtState, server *AssetServer, transform *TransformComponent, vox *VoxelModelComponent, geometryID AssetId, source *VoxelGeometryAsset, eye mgl32.Vec3) (SpriteComponent, entityLODImpostorState) {
 	if server == nil || transform == nil || vox == nil || source == nil {
-		return SpriteComponent{}, false
+		return SpriteComponent{}, entityLODImpostorFailed
 	}
-	textureID, ok := server.entityLODImpostorTexture(geometryID, vox.VoxelPalette, source)
-	if !ok || textureID == (AssetId{}) {
-		return SpriteComponent{}, false
+	textureID, bake := server.entityLODImpostorTexture(geometryID, vox.VoxelPalette, source)
+	if bake != entityLODImpostorReady {
+		return SpriteComponent{}, bake
+	}
+	if textureID == (AssetId{}) {
+		return SpriteComponent{}, entityLODImpostorFailed
+	}
+	localCenter, _, ok := entityLODImpostorSphere(source.XBrickMap)
+	if !ok {
+		return SpriteComponent{}, entityLODImpostorFailed
 	}
 	baseScale := EffectiveVoxelScale(vox, transform)
 	sourcePivot := entityLODSourcePivot(vox, source)
-	minB, maxB := entityLODLocalBounds(source)
-	localCenter := minB.Add(maxB).Mul(0.5)
+	worldCenter := entityLODWorldPoint(transform, localCenter, sourcePivot, baseScale)
+	view := entityLODImpostorViewForCamera(eye, worldCenter, transform.Rotation)
 	size := entityLODImpostorSpriteSize(vox, transform, source)
 	brightness := entityLODImpostorBrightnessTint(state, transform)
 	return SpriteComponent{
 		Enabled:       true,
-		Position:      entityLODWorldPoint(transform, localCenter, sourcePivot, baseScale),
+		Position:      worldCenter,
 		Size:          size,
 		Color:         [4]float32{brightness, brightness, brightness, 1},
+		SpriteIndex:   view.Index,
+		AtlasCols:     entityLODImpostorViewGrid,
+		AtlasRows:     2 * entityLODImpostorViewGrid,
 		Texture:       textureID,
 		BillboardMode: BillboardSpherical,
 		Unlit:         false,
 		AlphaMode:     SpriteAlphaTexture,
-	}, true
+		Impostor:      true,
+		BlendIndices:  view.BlendIndices,
+		BlendWeights:  view.BlendWeights,
+	}, entityLODImpostorReady
 }
 
 func buildEntityLODDotSprite(state *VoxelRtState, server *AssetServer, transform *TransformComponent, vox *VoxelModelComponent, source *VoxelGeometryAsset) (SpriteComponent, bool) {
@@ -1567,6 +1716,9 @@ func buildEntityLODDotSprite(state *VoxelRtState, server *AssetServer, transform
 	}, true
 }
 
+// spriteAtlasTexture returns the CPU texels to upload for atlasKey.
+// Render-target textures have none; the renderer registers their atlas
+// itself.
 func spriteAtlasTexture(server *AssetServer, atlasKey string) (TextureAsset, bool) {
 	if server == nil || atlasKey == "" {
 		return TextureAsset{}, false
@@ -1576,6 +1728,9 @@ func spriteAtlasTexture(server *AssetServer, atlasKey string) (TextureAsset, boo
 		return server.entityLODTextureByCacheKey(atlasKey)
 	}
 	texAsset, ok := server.textures[AssetId{UUID: parsed}]
+	if texAsset.RenderTarget {
+		return TextureAsset{}, false
+	}
 	return texAsset, ok
 }
 
@@ -1772,12 +1927,30 @@ func voxelRtDebugSystem(input *Input, state *VoxelRtState) {
 	}
 }
 
-func voxelRtTextBridgeSystem(state *VoxelRtState, cmd *Commands) {
+func voxelRtTextBridgeSystem(state *VoxelRtState, server *AssetServer, cmd *Commands) {
 	if state == nil || state.RtApp == nil {
 		return
 	}
 	if state.bridgeFeatureEnabled(voxelRtBridgeFeatureText) {
 		syncVoxelRtText(state, cmd)
+		syncVoxelRtOverlayImages(state, server)
+	}
+}
+
+// syncVoxelRtOverlayImages uploads the textures drawn with DrawImage this
+// frame into sprite atlases, which the overlay pass samples by key.
+func syncVoxelRtOverlayImages(state *VoxelRtState, server *AssetServer) {
+	for _, key := range state.RtApp.OverlayImageTextures() {
+		if texAsset, ok := spriteAtlasTexture(server, key); ok && state.RtApp.BufferManager != nil {
+			state.RtApp.BufferManager.SetSpriteAtlas(
+				key,
+				texAsset.Texels,
+				texAsset.Width,
+				texAsset.Height,
+				texAsset.Version,
+				assetTextureFormatToWGPU(texAsset.Format),
+			)
+		}
 	}
 }
 
@@ -1793,9 +1966,10 @@ func buildTextBridgeItems(cmd *Commands) []app_rt.TextOverlayItem {
 		return nil
 	}
 	items := make([]app_rt.TextOverlayItem, 0)
+	loc := localizationFromApp(cmd.app)
 	MakeQuery1[TextComponent](cmd).Map(func(entityId EntityId, text *TextComponent) bool {
 		items = append(items, app_rt.TextOverlayItem{
-			Text:     text.Text,
+			Text:     loc.Localize(text.Text, text.Loc),
 			Position: text.Position,
 			Scale:    text.Scale,
 			Color:    text.Color,
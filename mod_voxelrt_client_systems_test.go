@@ -2,6 +2,7 @@ package gekko
 
 import (
 	"math"
+	"reflect"
 	"testing"
 
 	"github.com/cogentcore/webgpu/wgpu"
@@ -274,7 +275,7 @@ func TestVoxelRtSystemGatesTextAndGizmoBridgeSyncByRegisteredFeature(t *testing.
 	app.FlushCommands()
 
 	voxelRtSystem(nil, state, nil, &Time{Dt: 1.0 / 60.0}, cmd, nil)
-	voxelRtTextBridgeSystem(state, cmd)
+	voxelRtTextBridgeSystem(state, nil, cmd)
 	voxelRtGizmoBridgeSystem(state, cmd)
 
 	if got := len(state.RtApp.TextResources.Items); got != 2 {
@@ -295,7 +296,7 @@ func TestVoxelRtSystemGatesTextAndGizmoBridgeSyncByRegisteredFeature(t *testing.
 	disabledState.RtApp.Scene.Gizmos = []core.Gizmo{{Type: core.GizmoLine}}
 
 	voxelRtSystem(nil, disabledState, nil, &Time{Dt: 1.0 / 60.0}, cmd, nil)
-	voxelRtTextBridgeSystem(disabledState, cmd)
+	voxelRtTextBridgeSystem(disabledState, nil, cmd)
 	voxelRtGizmoBridgeSystem(disabledState, cmd)
 
 	if got := len(disabledState.RtApp.TextResources.Items); got != 1 {
@@ -1311,6 +1312,45 @@ func TestVoxelRtModuleCustomRenderGraphNodeLifecycle(t *testing.T) {
 	}
 }
 
+func TestVoxelRtModuleDeclaresCustomRenderGraphResources(t *testing.T) {
+	const (
+		writer = "feature-custom-mask-write"
+		reader = "feature-custom-mask-read"
+		mask   = "feature-custom-mask"
+	)
+	rtApp := app_rt.NewApp(nil)
+	mod := VoxelRtModule{
+		RenderGraphResources: []VoxelRtRenderResourceDesc{
+			{Name: mask, Kind: app_rt.RenderResourceTexture, Format: wgpu.TextureFormatR8Unorm, Scale: 0.5},
+		},
+		RenderGraphNodes: []VoxelRtRenderNodeSpec{
+			{
+				Name:   writer,
+				After:  []string{app_rt.RenderNodeCoreLighting},
+				Writes: []string{mask},
+				Node:   voxelRtModuleTestRenderNode{name: writer},
+			},
+			{
+				Name:  reader,
+				After: []string{app_rt.RenderNodeCoreResolve},
+				Reads: []string{mask},
+				Node:  voxelRtModuleTestRenderNode{name: reader},
+			},
+		},
+	}
+
+	mod.applyRenderExtensions(rtApp)
+
+	plan, err := rtApp.RenderGraph.ResourcePlan()
+	if err != nil {
+		t.Fatalf("custom render graph resources failed to compile: %v", err)
+	}
+	lifetime, ok := plan.Lifetime(mask)
+	if !ok || lifetime.Slot < 0 || lifetime.First >= lifetime.Last {
+		t.Fatalf("expected transient lifetime from writer to reader, got %+v (ok=%v)", lifetime, ok)
+	}
+}
+
 func sameStringSlices(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -1702,7 +1742,7 @@ func TestEntityLODSelectionSystemUpdatesRuntimeSelection(t *testing.T) {
 	)
 	app.FlushCommands()
 
-	entityLODSelectionSystem(cmd, state)
+	entityLODSelectionSystem(cmd, state, nil, nil)
 
 	found := false
 	MakeQuery1[EntityLODComponent](cmd).Map(func(entityId EntityId, lod *EntityLODComponent) bool {
@@ -1726,6 +1766,60 @@ func TestEntityLODSelectionSystemUpdatesRuntimeSelection(t *testing.T) {
 	}
 }
 
+func TestEntityLODSelectionSystemLimitsBandChangesPerFrame(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	state := newVoxelRtStateTest()
+	state.entityLODChangeBudget = 2
+
+	cmd.AddEntity(&CameraComponent{Position: mgl32.Vec3{0, 0, 0}, LookAt: mgl32.Vec3{0, 0, -1}, Fov: 60})
+	for i := 0; i < 4; i++ {
+		cmd.AddEntity(
+			&TransformComponent{
+				Position: mgl32.Vec3{float32(i), 0, -20},
+				Rotation: mgl32.QuatIdent(),
+				Scale:    mgl32.Vec3{1, 1, 1},
+			},
+			&EntityLODComponent{
+				Bands: []EntityLODBand{
+					{MaxDistance: 50, Representation: EntityLODRepresentationFullVoxel},
+					{MaxDistance: 0, Representation: EntityLODRepresentationImpostor},
+				},
+			},
+		)
+	}
+	app.FlushCommands()
+
+	countBand := func(band int) int {
+		count := 0
+		MakeQuery1[EntityLODComponent](cmd).Map(func(entityId EntityId, lod *EntityLODComponent) bool {
+			if lod.SelectionValid && lod.ActiveBandIndex == band {
+				count++
+			}
+			return true
+		})
+		return count
+	}
+
+	entityLODSelectionSystem(cmd, state, nil, &Time{Dt: 1.0 / 60})
+	if got := countBand(0); got != 4 {
+		t.Fatalf("expected initial selections to bypass the budget, got %d near entities", got)
+	}
+
+	MakeQuery1[TransformComponent](cmd).Map(func(entityId EntityId, transform *TransformComponent) bool {
+		transform.Position = transform.Position.Add(mgl32.Vec3{0, 0, -100})
+		return true
+	})
+	entityLODSelectionSystem(cmd, state, nil, &Time{Dt: 1.0 / 60})
+	if got := countBand(1); got != 2 {
+		t.Fatalf("expected budget to allow 2 band changes, got %d", got)
+	}
+	entityLODSelectionSystem(cmd, state, nil, &Time{Dt: 1.0 / 60})
+	if got := countBand(1); got != 4 {
+		t.Fatalf("expected deferred entities to switch next frame, got %d", got)
+	}
+}
+
 func TestVoxelRtSystemCapturesEntityLODSelectionForVoxelEntities(t *testing.T) {
 	app := NewApp()
 	cmd := app.Commands()
@@ -1771,7 +1865,7 @@ func TestVoxelRtSystemCapturesEntityLODSelectionForVoxelEntities(t *testing.T) {
 	)
 	app.FlushCommands()
 
-	entityLODSelectionSystem(cmd, state)
+	entityLODSelectionSystem(cmd, state, nil, nil)
 	voxelRtSystem(nil, state, server, &Time{Dt: 1.0 / 60.0}, cmd, nil)
 
 	selection, ok := state.entityLODSelections[eid]
@@ -1825,7 +1919,7 @@ func TestVoxelRtSystemUsesSimplifiedGeometryForSimplifiedLOD(t *testing.T) {
 	)
 	app.FlushCommands()
 
-	entityLODSelectionSystem(cmd, state)
+	entityLODSelectionSystem(cmd, state, nil, nil)
 	voxelRtSystem(nil, state, server, &Time{Dt: 1.0 / 60.0}, cmd, nil)
 
 	obj, ok := state.instanceMap[eid]
@@ -1894,9 +1988,14 @@ func TestVoxelRtSystemUsesRuntimeImpostorSpritesForFarLOD(t *testing.T) {
 	)
 	app.FlushCommands()
 
-	entityLODSelectionSystem(cmd, state)
+	entityLODSelectionSystem(cmd, state, nil, nil)
 	voxelRtSystem(nil, state, server, &Time{Dt: 1.0 / 60.0}, cmd, nil)
+	if _, ok := state.instanceMap[eid]; !ok || len(state.runtimeSprites) != 0 {
+		t.Fatalf("expected the voxel model to stay drawn while the impostor bakes, got %d sprites", len(state.runtimeSprites))
+	}
 
+	awaitEntityLODImpostorBakes(t, server)
+	voxelRtSystem(nil, state, server, &Time{Dt: 1.0 / 60.0}, cmd, nil)
 	if _, ok := state.instanceMap[eid]; ok {
 		t.Fatal("expected impostor LOD to suppress voxel object sync")
 	}
@@ -1913,6 +2012,13 @@ func TestVoxelRtSystemUsesRuntimeImpostorSpritesForFarLOD(t *testing.T) {
 	if sprite.Unlit {
 		t.Fatal("expected impostor sprite to use lit world-sprite shading")
 	}
+	if !sprite.Impostor || sprite.AtlasCols != entityLODImpostorViewGrid || sprite.AtlasRows != 2*entityLODImpostorViewGrid {
+		t.Fatalf("expected octahedral impostor atlas layout, got impostor=%v cols=%d rows=%d", sprite.Impostor, sprite.AtlasCols, sprite.AtlasRows)
+	}
+	primary := entityLODImpostorViewDirection(entityLODImpostorViewGrid, int(sprite.SpriteIndex)%entityLODImpostorViewGrid, int(sprite.SpriteIndex)/entityLODImpostorViewGrid)
+	if primary.Dot(mgl32.Vec3{0, 0, 1}) < 0.8 {
+		t.Fatalf("expected primary impostor view to face the camera on +Z, got direction %v", primary)
+	}
 	if sprite.Size[0] <= 0 || sprite.Size[1] <= 0 {
 		t.Fatalf("expected positive sprite size, got %v", sprite.Size)
 	}
@@ -1961,7 +2067,7 @@ func TestVoxelRtSystemKeepsVoxelObjectForImpostorLODWhenSpritesDisabled(t *testi
 	)
 	app.FlushCommands()
 
-	entityLODSelectionSystem(cmd, state)
+	entityLODSelectionSystem(cmd, state, nil, nil)
 	voxelRtSystem(nil, state, server, &Time{Dt: 1.0 / 60.0}, cmd, nil)
 
 	if _, ok := state.RtApp.Profiler.ScopeTimes["Sync Sprites"]; ok {
@@ -2049,7 +2155,9 @@ func TestVoxelRtSystemFallsBackToDotSpritesWhenImpostorGenerationFails(t *testin
 	)
 	app.FlushCommands()
 
-	entityLODSelectionSystem(cmd, state)
+	entityLODSelectionSystem(cmd, state, nil, nil)
+	voxelRtSystem(nil, state, server, &Time{Dt: 1.0 / 60.0}, cmd, nil)
+	awaitEntityLODImpostorBakes(t, server)
 	voxelRtSystem(nil, state, server, &Time{Dt: 1.0 / 60.0}, cmd, nil)
 
 	if _, ok := state.instanceMap[eid]; ok {
@@ -2070,6 +2178,77 @@ func TestVoxelRtSystemFallsBackToDotSpritesWhenImpostorGenerationFails(t *testin
 	}
 }
 
+func TestVoxelRtSystemCrossFadesLODTransitionsThroughSprites(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	server := newVoxelRtAssetServerTest(t)
+	state := newVoxelRtStateTest()
+	state.RtApp.RegisterFeature(&app_rt.SpriteFeature{})
+
+	modelID := server.CreateFrameModel(12, 18, 12, 2, 1.0)
+	paletteID := server.CreateSimplePalette([4]uint8{112, 206, 255, 255})
+
+	cmd.AddEntity(&CameraComponent{
+		Position: mgl32.Vec3{0, 0, 0},
+		LookAt:   mgl32.Vec3{0, 0, -1},
+		Up:       mgl32.Vec3{0, 1, 0},
+		Fov:      60,
+		Aspect:   1,
+		Near:     0.1,
+		Far:      1000,
+	})
+	eid := cmd.AddEntity(
+		&TransformComponent{
+			Position: mgl32.Vec3{0, 0, -5},
+			Rotation: mgl32.QuatIdent(),
+			Scale:    mgl32.Vec3{1, 1, 1},
+		},
+		&VoxelModelComponent{
+			VoxelModel:   modelID,
+			VoxelPalette: paletteID,
+			PivotMode:    PivotModeCenter,
+		},
+		&EntityLODComponent{
+			TransitionDuration: 1,
+			Bands: []EntityLODBand{
+				{MaxDistance: 50, Representation: EntityLODRepresentationFullVoxel},
+				{MaxDistance: 0, Representation: EntityLODRepresentationDot},
+			},
+		},
+	)
+	app.FlushCommands()
+	frame := &Time{Dt: 0.5}
+
+	entityLODSelectionSystem(cmd, state, server, frame)
+	voxelRtSystem(nil, state, server, frame, cmd, nil)
+	if _, ok := state.instanceMap[eid]; !ok || len(state.runtimeSprites) != 0 {
+		t.Fatalf("expected a near entity to draw only its voxels, got %d sprites", len(state.runtimeSprites))
+	}
+
+	cmd.GetComponent(eid, reflect.TypeOf(TransformComponent{})).(*TransformComponent).Position = mgl32.Vec3{0, 0, -150}
+	entityLODSelectionSystem(cmd, state, server, frame)
+	entityLODSelectionSystem(cmd, state, server, frame)
+	voxelRtSystem(nil, state, server, frame, cmd, nil)
+	if _, ok := state.instanceMap[eid]; !ok {
+		t.Fatal("expected the voxel object to stay drawn while the dot fades in")
+	}
+	if len(state.runtimeSprites) != 1 {
+		t.Fatalf("expected the fading dot sprite, got %d sprites", len(state.runtimeSprites))
+	}
+	if alpha := state.runtimeSprites[0].Color[3]; alpha <= 0 || alpha >= 1 {
+		t.Fatalf("expected a partially faded dot, got alpha %v", alpha)
+	}
+
+	entityLODSelectionSystem(cmd, state, server, frame)
+	voxelRtSystem(nil, state, server, frame, cmd, nil)
+	if _, ok := state.instanceMap[eid]; ok {
+		t.Fatal("expected the voxel object to be dropped once the transition ends")
+	}
+	if len(state.runtimeSprites) != 1 || state.runtimeSprites[0].Color[3] != 1 {
+		t.Fatalf("expected an opaque dot after the transition, got %+v", state.runtimeSprites)
+	}
+}
+
 func TestEntityLODImpostorBaseSizeUsesFull3DBounds(t *testing.T) {
 	server := newVoxelRtAssetServerTest(t)
 	modelID := server.CreateCubeModel(2, 2, 40, 1.0)
@@ -2095,7 +2274,7 @@ func TestEntityLODImpostorBaseSizeUsesFull3DBounds(t *testing.T) {
 	}
 }
 
-func TestEntityLODImpostorSpriteSizePreservesProjectedAspect(t *testing.T) {
+func TestEntityLODImpostorSpriteSizeCoversBakedBoundingSphere(t *testing.T) {
 	server := newVoxelRtAssetServerTest(t)
 	modelID := server.CreateCubeModel(24, 8, 8, 1.0)
 	source, ok := server.GetVoxelGeometry(modelID)
@@ -2103,13 +2282,16 @@ func TestEntityLODImpostorSpriteSizePreservesProjectedAspect(t *testing.T) {
 		t.Fatal("expected source geometry")
 	}
 
-	size := entityLODImpostorSpriteSize(
-		&VoxelModelComponent{VoxelModel: modelID, PivotMode: PivotModeCenter},
-		&TransformComponent{Scale: mgl32.Vec3{1, 1, 1}},
-		&source,
-	)
-	if size[0] <= size[1] {
-		t.Fatalf("expected wide model impostor sprite to preserve projected aspect, got %v", size)
+	vox := &VoxelModelComponent{VoxelModel: modelID, PivotMode: PivotModeCenter}
+	transform := &TransformComponent{Scale: mgl32.Vec3{1, 1, 1}}
+	size := entityLODImpostorSpriteSize(vox, transform, &source)
+	_, radius, _ := entityLODImpostorSphere(source.XBrickMap)
+	want := 2 * radius * EffectiveVoxelScale(vox, transform).X()
+	if size[0] != size[1] {
+		t.Fatalf("expected square octahedral impostor card, got %v", size)
+	}
+	if math.Abs(float64(size[0]-want)) > 1e-4 {
+		t.Fatalf("expected card size %v from the baked bounding sphere, got %v", want, size[0])
 	}
 }
 
@@ -2185,6 +2367,9 @@ func TestSpriteAtlasTextureLooksUpTextureByAtlasKey(t *testing.T) {
 	if _, ok := spriteAtlasTexture(server, "not-a-valid-atlas-key"); ok {
 		t.Fatalf("expected invalid atlas key lookup to fail")
 	}
+	if _, ok := spriteAtlasTexture(server, spriteAtlasKey(server.CreateRenderTargetTexture(64, 64))); ok {
+		t.Fatalf("expected render-target atlas to have no texels to upload")
+	}
 }
 
 func TestVoxelObjectAllowsOcclusionKeepsTerrainAndGroupedChunksEligible(t *testing.T) {
@@ -0,0 +1,300 @@
+package gekko
+
+import (
+	"math"
+	"sort"
+
+	app_rt "github.com/gekko3d/gekko/voxelrt/rt/app"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type PostProcessSettings = app_rt.PostProcessSettings
+type PostProcessToneMapper = app_rt.ToneMapper
+type PostProcessAntiAliasing = app_rt.AntiAliasingMode
+
+const (
+	PostProcessToneMapperACES     = app_rt.ToneMapperACES
+	PostProcessToneMapperReinhard = app_rt.ToneMapperReinhard
+	PostProcessToneMapperNone     = app_rt.ToneMapperNone
+	PostProcessAntiAliasingNone   = app_rt.AntiAliasingNone
+	PostProcessAntiAliasingFXAA   = app_rt.AntiAliasingFXAA
+)
+
+func DefaultPostProcessSettings() PostProcessSettings {
+	return app_rt.DefaultPostProcessSettings()
+}
+
+// PostProcessOverride selects which setting groups a volume contributes.
+type PostProcessOverride uint32
+
+const (
+	PostProcessOverrideExposure PostProcessOverride = 1 << iota
+	PostProcessOverrideToneMapper
+	PostProcessOverrideBloom
+	PostProcessOverrideColorGrading
+	PostProcessOverrideVignette
+	PostProcessOverrideChromaticAberration
+	PostProcessOverrideAntiAliasing
+
+	PostProcessOverrideAll = PostProcessOverrideExposure | PostProcessOverrideToneMapper | PostProcessOverrideBloom |
+		PostProcessOverrideColorGrading | PostProcessOverrideVignette | PostProcessOverrideChromaticAberration |
+		PostProcessOverrideAntiAliasing
+)
+
+// PostProcessSettingsComponent contributes post-process settings around the
+// camera. Volumes are blended over DefaultPostProcessSettings in ascending
+// Priority order.
+//
+// The volume shape comes from the entity:
+//   - with a WaterBodyComponent, the camera is inside while it is below the
+//     surface of one of the body's resolved water patches (underwater tint);
+//   - with BoundsHalfExtents set, an oriented box around the TransformComponent;
+//   - otherwise the volume is global.
+type PostProcessSettingsComponent struct {
+	Disabled bool
+
+	// Settings should start from DefaultPostProcessSettings; overridden fields
+	// are applied as written, including zeros.
+	Settings PostProcessSettings
+	// Overrides limits which groups of Settings apply. Zero overrides all groups.
+	Overrides PostProcessOverride
+
+	Priority int
+	// Weight scales the volume's influence. Zero counts as full weight; use
+	// Disabled to switch a volume off.
+	Weight float32
+
+	BoundsHalfExtents mgl32.Vec3
+	// BlendDistance fades a local volume in over this distance outside its shape.
+	BlendDistance float32
+}
+
+func (p *PostProcessSettingsComponent) NormalizedOverrides() PostProcessOverride {
+	if p == nil {
+		return 0
+	}
+	if p.Overrides == 0 {
+		return PostProcessOverrideAll
+	}
+	return p.Overrides & PostProcessOverrideAll
+}
+
+func (p *PostProcessSettingsComponent) NormalizedWeight() float32 {
+	if p == nil {
+		return 0
+	}
+	if p.Weight <= 0 || p.Weight != p.Weight {
+		return 1
+	}
+	return minf(p.Weight, 1)
+}
+
+func (p *PostProcessSettingsComponent) NormalizedBlendDistance() float32 {
+	if p == nil || p.BlendDistance <= 0 {
+		return 0
+	}
+	return p.BlendDistance
+}
+
+func (p *PostProcessSettingsComponent) HasBounds() bool {
+	return p != nil && p.BoundsHalfExtents.X() > 0 && p.BoundsHalfExtents.Y() > 0 && p.BoundsHalfExtents.Z() > 0
+}
+
+// postProcessVolumeSample is one volume's contribution at the camera.
+type postProcessVolumeSample struct {
+	entity    EntityId
+	priority  int
+	influence float32
+	overrides PostProcessOverride
+	settings  PostProcessSettings
+}
+
+// blendPostProcessVolumes layers samples over the defaults, lowest priority
+// first, so higher priorities win where their influence is full.
+func blendPostProcessVolumes(samples []postProcessVolumeSample) PostProcessSettings {
+	sorted := append([]postProcessVolumeSample(nil), samples...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].priority != sorted[j].priority {
+			return sorted[i].priority < sorted[j].priority
+		}
+		return sorted[i].entity < sorted[j].entity
+	})
+
+	result := DefaultPostProcessSettings()
+	for _, sample := range sorted {
+		if sample.influence <= 0 {
+			continue
+		}
+		target := applyPostProcessOverrides(result, sample.settings.Normalized(), sample.overrides)
+		result = app_rt.LerpPostProcessSettings(result, target, sample.influence)
+	}
+	return result.Normalized()
+}
+
+func applyPostProcessOverrides(base, volume PostProcessSettings, overrides PostProcessOverride) PostProcessSettings {
+	out := base
+	if overrides&PostProcessOverrideExposure != 0 {
+		out.Exposure = volume.Exposure
+	}
+	if overrides&PostProcessOverrideToneMapper != 0 {
+		out.ToneMapper = volume.ToneMapper
+	}
+	if overrides&PostProcessOverrideBloom != 0 {
+		out.BloomIntensity = volume.BloomIntensity
+		out.BloomThreshold = volume.BloomThreshold
+		out.BloomKnee = volume.BloomKnee
+	}
+	if overrides&PostProcessOverrideColorGrading != 0 {
+		out.Saturation = volume.Saturation
+		out.Contrast = volume.Contrast
+		out.ColorFilter = volume.ColorFilter
+	}
+	if overrides&PostProcessOverrideVignette != 0 {
+		out.Vignette = volume.Vignette
+	}
+	if overrides&PostProcessOverrideChromaticAberration != 0 {
+		out.ChromaticAberration = volume.ChromaticAberration
+	}
+	if overrides&PostProcessOverrideAntiAliasing != 0 {
+		out.AntiAliasing = volume.AntiAliasing
+	}
+	return out
+}
+
+// postProcessVolumeInfluence converts the distance from the camera to a
+// volume's shape into a [0,1] factor. Global volumes pass a zero distance.
+func postProcessVolumeInfluence(component *PostProcessSettingsComponent, distance float32) float32 {
+	if distance <= 0 {
+		return component.NormalizedWeight()
+	}
+	blend := component.NormalizedBlendDistance()
+	if blend <= 0 || distance >= blend {
+		return 0
+	}
+	return component.NormalizedWeight() * (1 - distance/blend)
+}
+
+// postProcessBoxDistance returns how far eye lies outside an oriented box, or
+// zero when it is inside.
+func postProcessBoxDistance(eye mgl32.Vec3, tr *TransformComponent, halfExtents mgl32.Vec3) float32 {
+	center := mgl32.Vec3{}
+	rotation := mgl32.QuatIdent()
+	scale := mgl32.Vec3{1, 1, 1}
+	if tr != nil {
+		center = tr.Position
+		rotation = tr.Rotation
+		scale = tr.Scale
+	}
+	if rotation.Len() == 0 {
+		rotation = mgl32.QuatIdent()
+	}
+	local := rotation.Normalize().Inverse().Rotate(eye.Sub(center))
+	var outside mgl32.Vec3
+	for i := 0; i < 3; i++ {
+		s := absf(scale[i])
+		if s == 0 {
+			s = 1
+		}
+		outside[i] = maxf(absf(local[i])-halfExtents[i]*s, 0)
+	}
+	return outside.Len()
+}
+
+// postProcessWaterDistance returns how far eye lies outside the submerged
+// volume of the given resolved surface patches.
+func postProcessWaterDistance(eye mgl32.Vec3, patches []ResolvedWaterPatchComponent) float32 {
+	best := float32(math.MaxFloat32)
+	for i := range patches {
+		patch := &patches[i]
+		minY := patch.Center.Y() - patch.Depth
+		maxY := patch.Center.Y()
+		dx := maxf(absf(eye.X()-patch.Center.X())-patch.HalfExtents[0], 0)
+		dz := maxf(absf(eye.Z()-patch.Center.Z())-patch.HalfExtents[1], 0)
+		dy := maxf(maxf(minY-eye.Y(), eye.Y()-maxY), 0)
+		d := mgl32.Vec3{dx, dy, dz}.Len()
+		if d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// collectPostProcessVolumeSamples evaluates every enabled volume at eye.
+func collectPostProcessVolumeSamples(cmd *Commands, eye mgl32.Vec3) []postProcessVolumeSample {
+	if cmd == nil {
+		return nil
+	}
+	components := make(map[EntityId]PostProcessSettingsComponent)
+	MakeQuery1[PostProcessSettingsComponent](cmd).Map(func(eid EntityId, component *PostProcessSettingsComponent) bool {
+		if component != nil && !component.Disabled {
+			components[eid] = *component
+		}
+		return true
+	})
+	if len(components) == 0 {
+		return nil
+	}
+
+	transforms := make(map[EntityId]TransformComponent)
+	MakeQuery2[TransformComponent, PostProcessSettingsComponent](cmd).Map(func(eid EntityId, tr *TransformComponent, _ *PostProcessSettingsComponent) bool {
+		if tr != nil {
+			transforms[eid] = *tr
+		}
+		return true
+	})
+	waterOwners := make(map[EntityId]bool)
+	MakeQuery2[WaterBodyComponent, PostProcessSettingsComponent](cmd).Map(func(eid EntityId, body *WaterBodyComponent, _ *PostProcessSettingsComponent) bool {
+		waterOwners[eid] = body != nil && !body.Disabled
+		return true
+	})
+	patchesByOwner := make(map[EntityId][]ResolvedWaterPatchComponent)
+	if len(waterOwners) > 0 {
+		MakeQuery1[ResolvedWaterPatchComponent](cmd).Map(func(_ EntityId, patch *ResolvedWaterPatchComponent) bool {
+			if patch != nil && patch.Enabled() && patch.Kind == WaterPatchKindSurface && waterOwners[patch.Owner] {
+				patchesByOwner[patch.Owner] = append(patchesByOwner[patch.Owner], *patch)
+			}
+			return true
+		})
+	}
+
+	samples := make([]postProcessVolumeSample, 0, len(components))
+	for eid, component := range components {
+		component := component
+		var distance float32
+		if enabled, isWater := waterOwners[eid]; isWater {
+			if !enabled || len(patchesByOwner[eid]) == 0 {
+				continue
+			}
+			distance = postProcessWaterDistance(eye, patchesByOwner[eid])
+		} else if component.HasBounds() {
+			var tr *TransformComponent
+			if value, ok := transforms[eid]; ok {
+				tr = &value
+			}
+			distance = postProcessBoxDistance(eye, tr, component.BoundsHalfExtents)
+		}
+		samples = append(samples, postProcessVolumeSample{
+			entity:    eid,
+			priority:  component.Priority,
+			influence: postProcessVolumeInfluence(&component, distance),
+			overrides: component.NormalizedOverrides(),
+			settings:  component.Settings,
+		})
+	}
+	return samples
+}
+
+// syncVoxelRtPostProcess blends the post-process volumes at the render
+// camera and hands the result to the renderer.
+func syncVoxelRtPostProcess(state *VoxelRtState, cmd *Commands) {
+	if state == nil || state.RtApp == nil {
+		return
+	}
+	eye := mgl32.Vec3{}
+	if state.RtApp.Camera != nil {
+		eye = state.RtApp.Camera.Position
+	}
+	settings := blendPostProcessVolumes(collectPostProcessVolumeSamples(cmd, eye))
+	state.postProcessSettings = settings
+	state.RtApp.SetPostProcessSettings(settings)
+}
@@ -0,0 +1,137 @@
+package gekko
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestBlendPostProcessVolumesLayersByPriorityAndOverrides(t *testing.T) {
+	global := DefaultPostProcessSettings()
+	global.Exposure = 2
+	global.Vignette = 0.3
+
+	underwater := DefaultPostProcessSettings()
+	underwater.Exposure = 0.5
+	underwater.ColorFilter = [3]float32{0.2, 0.6, 0.9}
+
+	got := blendPostProcessVolumes([]postProcessVolumeSample{
+		{entity: 2, priority: 1, influence: 1, overrides: PostProcessOverrideColorGrading, settings: underwater},
+		{entity: 1, priority: 0, influence: 1, overrides: PostProcessOverrideAll, settings: global},
+	})
+	if got.Exposure != 2 || got.Vignette != 0.3 {
+		t.Fatalf("expected global exposure and vignette to survive a color-grading override, got %+v", got)
+	}
+	if got.ColorFilter != underwater.ColorFilter {
+		t.Fatalf("expected underwater color filter, got %v", got.ColorFilter)
+	}
+
+	half := blendPostProcessVolumes([]postProcessVolumeSample{
+		{entity: 1, priority: 0, influence: 1, overrides: PostProcessOverrideAll, settings: global},
+		{entity: 2, priority: 1, influence: 0.5, overrides: PostProcessOverrideAll, settings: underwater},
+	})
+	if !approxPostProcessECS(half.Exposure, 1.25) || !approxPostProcessECS(half.ColorFilter[0], 0.6) {
+		t.Fatalf("expected half-way blend toward the higher priority volume, got %+v", half)
+	}
+}
+
+func TestPostProcessVolumeInfluenceFadesOverBlendDistance(t *testing.T) {
+	component := &PostProcessSettingsComponent{BlendDistance: 4}
+	cases := []struct {
+		distance float32
+		want     float32
+	}{
+		{distance: 0, want: 1},
+		{distance: 1, want: 0.75},
+		{distance: 4, want: 0},
+		{distance: 10, want: 0},
+	}
+	for _, tc := range cases {
+		if got := postProcessVolumeInfluence(component, tc.distance); !approxPostProcessECS(got, tc.want) {
+			t.Fatalf("distance %v: influence = %v, want %v", tc.distance, got, tc.want)
+		}
+	}
+
+	hard := &PostProcessSettingsComponent{Weight: 0.4}
+	if got := postProcessVolumeInfluence(hard, 0); !approxPostProcessECS(got, 0.4) {
+		t.Fatalf("expected weight to scale influence inside the volume, got %v", got)
+	}
+	if got := postProcessVolumeInfluence(hard, 0.01); got != 0 {
+		t.Fatalf("expected no influence outside a volume without blend distance, got %v", got)
+	}
+}
+
+func TestSyncVoxelRtPostProcessAppliesUnderwaterVolume(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	state := newVoxelRtStateTest()
+
+	global := DefaultPostProcessSettings()
+	global.Vignette = 0.25
+	cmd.AddEntity(&PostProcessSettingsComponent{Settings: global})
+
+	underwater := DefaultPostProcessSettings()
+	underwater.ColorFilter = [3]float32{0.3, 0.7, 1}
+	underwater.Saturation = 0.6
+	water := cmd.AddEntity(
+		&WaterBodyComponent{SurfaceY: 0, Depth: 5, RectHalfExtents: [2]float32{10, 10}},
+		&PostProcessSettingsComponent{
+			Settings:      underwater,
+			Overrides:     PostProcessOverrideColorGrading,
+			Priority:      10,
+			BlendDistance: 1,
+		},
+	)
+	cmd.AddEntity(&ResolvedWaterPatchComponent{
+		Owner:       water,
+		Kind:        WaterPatchKindSurface,
+		Center:      mgl32.Vec3{0, 0, 0},
+		HalfExtents: [2]float32{10, 10},
+		Depth:       5,
+	})
+	app.FlushCommands()
+
+	state.RtApp.Camera.Position = mgl32.Vec3{0, 3, 0}
+	syncVoxelRtPostProcess(state, cmd)
+	above := state.PostProcessSettings()
+	if above.ColorFilter != [3]float32{1, 1, 1} || above.Vignette != 0.25 {
+		t.Fatalf("expected only the global volume above water, got %+v", above)
+	}
+	if state.RtApp.PostProcess != above {
+		t.Fatalf("expected renderer to receive blended settings, got %+v", state.RtApp.PostProcess)
+	}
+
+	state.RtApp.Camera.Position = mgl32.Vec3{2, -1, 3}
+	syncVoxelRtPostProcess(state, cmd)
+	below := state.PostProcessSettings()
+	if below.ColorFilter != underwater.ColorFilter || below.Saturation != 0.6 || below.Vignette != 0.25 {
+		t.Fatalf("expected underwater grading over the global vignette, got %+v", below)
+	}
+
+	state.RtApp.Camera.Position = mgl32.Vec3{0, 0.5, 0}
+	syncVoxelRtPostProcess(state, cmd)
+	surface := state.PostProcessSettings()
+	if !approxPostProcessECS(surface.ColorFilter[0], 0.65) {
+		t.Fatalf("expected half-blended tint just above the surface, got %v", surface.ColorFilter)
+	}
+}
+
+func TestPostProcessBoxDistanceUsesTransformRotation(t *testing.T) {
+	tr := &TransformComponent{
+		Position: mgl32.Vec3{10, 0, 0},
+		Rotation: mgl32.QuatRotate(mgl32.DegToRad(90), mgl32.Vec3{0, 1, 0}),
+		Scale:    mgl32.Vec3{1, 1, 1},
+	}
+	halfExtents := mgl32.Vec3{4, 1, 1}
+	if got := postProcessBoxDistance(mgl32.Vec3{10, 0, 3}, tr, halfExtents); got > 1e-4 {
+		t.Fatalf("expected point along the rotated long axis to be inside, got distance %v", got)
+	}
+	if got := postProcessBoxDistance(mgl32.Vec3{13, 0, 0}, tr, halfExtents); !approxPostProcessECS(got, 2) {
+		t.Fatalf("expected distance 2 across the rotated short axis, got %v", got)
+	}
+}
+
+func approxPostProcessECS(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-4
+}
@@ -0,0 +1,42 @@
+package gekko
+
+import (
+	"fmt"
+
+	app_rt "github.com/gekko3d/gekko/voxelrt/rt/app"
+)
+
+type CaptureOptions = app_rt.CaptureRequest
+type CaptureResult = app_rt.CaptureResult
+type CaptureMetadata = app_rt.CaptureMetadata
+type CaptureChannel = app_rt.CaptureChannel
+type CaptureFormat = app_rt.CaptureFormat
+
+const (
+	CaptureColor       = app_rt.CaptureColor
+	CaptureAlbedo      = app_rt.CaptureAlbedo
+	CaptureNormals     = app_rt.CaptureNormals
+	CaptureDepth       = app_rt.CaptureDepth
+	CaptureAllChannels = app_rt.CaptureAllChannels
+
+	CaptureFormatPNG = app_rt.CaptureFormatPNG
+	CaptureFormatEXR = app_rt.CaptureFormatEXR
+)
+
+// RequestCapture saves the next rendered frame, or opts.Frames consecutive
+// frames, to disk. Color is read back from the final image; albedo, normals
+// and depth are optional. Each frame also gets a JSON sidecar with the
+// camera, render mode, lighting quality and profiler stats. Files are written
+// in the background; opts.OnComplete reports them.
+func (s *VoxelRtState) RequestCapture(opts CaptureOptions) error {
+	if s == nil || s.RtApp == nil {
+		return fmt.Errorf("voxel renderer is not running")
+	}
+	return s.RtApp.RequestCapture(opts)
+}
+
+// CapturesPending reports whether requested captures have not been written
+// yet.
+func (s *VoxelRtState) CapturesPending() bool {
+	return s != nil && s.RtApp != nil && s.RtApp.CapturesPending()
+}
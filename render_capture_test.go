@@ -0,0 +1,24 @@
+package gekko
+
+import "testing"
+
+func TestVoxelRtStateRequestCaptureQueuesOnRenderer(t *testing.T) {
+	var missing *VoxelRtState
+	if err := missing.RequestCapture(CaptureOptions{}); err == nil {
+		t.Fatal("expected a capture without a renderer to fail")
+	}
+
+	state := newVoxelRtStateTest()
+	if state.CapturesPending() {
+		t.Fatal("expected no pending captures on a fresh state")
+	}
+	if err := state.RequestCapture(CaptureOptions{Path: "shots/bug", Channels: CaptureColor | CaptureNormals, Frames: 4}); err != nil {
+		t.Fatalf("RequestCapture returned error: %v", err)
+	}
+	if !state.CapturesPending() {
+		t.Fatal("expected the burst to be queued on the renderer")
+	}
+	if err := state.RequestCapture(CaptureOptions{Format: CaptureFormat(9)}); err == nil {
+		t.Fatal("expected an unknown format to be rejected")
+	}
+}
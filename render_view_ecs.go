@@ -0,0 +1,228 @@
+package gekko
+
+import (
+	"sort"
+
+	app_rt "github.com/gekko3d/gekko/voxelrt/rt/app"
+)
+
+type RenderViewport = app_rt.RenderViewport
+type RenderViewFeature = app_rt.RenderViewFeature
+
+const (
+	RenderViewSky             = app_rt.RenderViewSky
+	RenderViewBloom           = app_rt.RenderViewBloom
+	RenderViewAntiAliasing    = app_rt.RenderViewAntiAliasing
+	RenderViewParticles       = app_rt.RenderViewParticles
+	RenderViewSprites         = app_rt.RenderViewSprites
+	RenderViewWater           = app_rt.RenderViewWater
+	RenderViewTransparency    = app_rt.RenderViewTransparency
+	RenderViewAllFeatures     = app_rt.RenderViewAllFeatures
+	RenderViewDefaultFeatures = app_rt.RenderViewDefaultFeatures
+)
+
+// RenderViewComponent turns a CameraComponent entity into an extra view.
+//
+// With Target set the camera renders into that texture, which must come from
+// AssetServer.CreateRenderTargetTexture; sprites (world and UI) whose Texture
+// is the target show the image. Without a Target the camera renders into
+// Viewport of the window, for split-screen.
+//
+// The main view renders from the first camera without a RenderViewComponent.
+// When every camera has one, the window view with the lowest Priority becomes
+// the main view and keeps its Viewport.
+type RenderViewComponent struct {
+	Disabled bool
+
+	Target   AssetId
+	Viewport RenderViewport
+	// Features selects optional passes. Zero uses RenderViewDefaultFeatures.
+	Features RenderViewFeature
+	// Priority orders views; lower renders first and draws underneath in
+	// overlapping viewports.
+	Priority int
+	// UpdateInterval re-renders an offscreen target every N frames and keeps
+	// the previous image in between. Zero or one renders every frame.
+	UpdateInterval int
+}
+
+func (v *RenderViewComponent) NormalizedFeatures() RenderViewFeature {
+	if v == nil || v.Features == 0 {
+		return RenderViewDefaultFeatures
+	}
+	return v.Features & RenderViewAllFeatures
+}
+
+func (v *RenderViewComponent) Offscreen() bool {
+	return v != nil && v.Target != (AssetId{})
+}
+
+type renderViewCamera struct {
+	entity EntityId
+	camera CameraComponent
+	view   RenderViewComponent
+}
+
+// renderCameraSet is the camera split for one frame: the main camera and the
+// enabled extra views ordered by entity.
+type renderCameraSet struct {
+	primary         renderViewCamera
+	hasPrimary      bool
+	primaryViewport RenderViewport
+	views           []renderViewCamera
+}
+
+func collectRenderCameras(cmd *Commands) renderCameraSet {
+	set := renderCameraSet{}
+	if cmd == nil {
+		return set
+	}
+	viewComponents := make(map[EntityId]RenderViewComponent)
+	MakeQuery2[CameraComponent, RenderViewComponent](cmd).Map(func(eid EntityId, _ *CameraComponent, view *RenderViewComponent) bool {
+		if view != nil {
+			viewComponents[eid] = *view
+		}
+		return true
+	})
+
+	var first renderViewCamera
+	hasFirst := false
+	MakeQuery1[CameraComponent](cmd).Map(func(eid EntityId, camera *CameraComponent) bool {
+		if camera == nil {
+			return true
+		}
+		entry := renderViewCamera{entity: eid, camera: *camera}
+		if !hasFirst {
+			first, hasFirst = entry, true
+		}
+		view, isView := viewComponents[eid]
+		if !isView {
+			if !set.hasPrimary {
+				set.primary, set.hasPrimary = entry, true
+			}
+			return true
+		}
+		if view.Disabled {
+			return true
+		}
+		entry.view = view
+		set.views = append(set.views, entry)
+		return true
+	})
+	sort.SliceStable(set.views, func(i, j int) bool {
+		return set.views[i].entity < set.views[j].entity
+	})
+
+	if !set.hasPrimary {
+		best := -1
+		for i, view := range set.views {
+			if view.view.Offscreen() {
+				continue
+			}
+			if best < 0 || view.view.Priority < set.views[best].view.Priority {
+				best = i
+			}
+		}
+		if best >= 0 {
+			set.primary, set.hasPrimary = set.views[best], true
+			set.primaryViewport = set.views[best].view.Viewport
+			set.views = append(set.views[:best], set.views[best+1:]...)
+		} else if hasFirst {
+			// Only offscreen views: the window still shows the first camera.
+			set.primary, set.hasPrimary = first, true
+		}
+	}
+	return set
+}
+
+// primaryRenderCamera returns the camera the main view renders from.
+func primaryRenderCamera(cmd *Commands) (CameraComponent, bool) {
+	set := collectRenderCameras(cmd)
+	return set.primary.camera, set.hasPrimary
+}
+
+// syncVoxelRtRenderViews hands the extra views of set to the renderer.
+func syncVoxelRtRenderViews(state *VoxelRtState, server *AssetServer, set renderCameraSet) {
+	if state == nil || state.RtApp == nil {
+		return
+	}
+	state.renderViewTick++
+	if state.renderViewLastTick == nil {
+		state.renderViewLastTick = make(map[EntityId]uint64)
+	}
+
+	views := make([]app_rt.RenderView, 0, len(set.views))
+	entities := make([]EntityId, 0, len(set.views))
+	live := make(map[EntityId]bool, len(set.views))
+	for _, entry := range set.views {
+		view := app_rt.RenderView{
+			Camera:   cameraStateFromComponent(&entry.camera),
+			Viewport: entry.view.Viewport,
+			Features: entry.view.NormalizedFeatures(),
+			Priority: entry.view.Priority,
+		}
+		if entry.view.Offscreen() {
+			target, ok := renderTargetTexture(server, entry.view.Target)
+			if !ok {
+				continue
+			}
+			view.TargetKey = spriteAtlasKey(entry.view.Target)
+			view.Width, view.Height = target.Width, target.Height
+			live[entry.entity] = true
+			last, rendered := state.renderViewLastTick[entry.entity]
+			if rendered && entry.view.UpdateInterval > 1 && state.renderViewTick-last < uint64(entry.view.UpdateInterval) {
+				view.Hold = true
+			} else {
+				state.renderViewLastTick[entry.entity] = state.renderViewTick
+			}
+		}
+		views = append(views, view)
+		entities = append(entities, entry.entity)
+	}
+	for eid := range state.renderViewLastTick {
+		if !live[eid] {
+			delete(state.renderViewLastTick, eid)
+		}
+	}
+
+	state.renderViewEntities = entities
+	state.RtApp.SetRenderViews(views)
+	state.RtApp.PrimaryViewport = set.primaryViewport
+}
+
+func renderTargetTexture(server *AssetServer, id AssetId) (TextureAsset, bool) {
+	if server == nil {
+		return TextureAsset{}, false
+	}
+	texture, ok := server.textures[id]
+	if !ok || !texture.RenderTarget || texture.Width == 0 || texture.Height == 0 {
+		return TextureAsset{}, false
+	}
+	return texture, true
+}
+
+// RenderViewVisibleEntities returns the voxel entities inside the frustum of
+// the view camera eid as of the last rendered frame.
+func (s *VoxelRtState) RenderViewVisibleEntities(eid EntityId) []EntityId {
+	if s == nil || s.RtApp == nil {
+		return nil
+	}
+	for i, viewEntity := range s.renderViewEntities {
+		if viewEntity != eid {
+			continue
+		}
+		objects := s.RtApp.RenderViewVisibleObjects(i)
+		out := make([]EntityId, 0, len(objects))
+		seen := make(map[EntityId]bool, len(objects))
+		for _, obj := range objects {
+			entity, ok := s.objectToEntity[obj]
+			if !ok || seen[entity] {
+				continue
+			}
+			seen[entity] = true
+			out = append(out, entity)
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package gekko
+
+import (
+	"testing"
+
+	"github.com/gekko3d/gekko/voxelrt/rt/core"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func renderViewTestCamera(position, lookAt mgl32.Vec3) *CameraComponent {
+	return &CameraComponent{
+		Position: position,
+		LookAt:   lookAt,
+		Up:       mgl32.Vec3{0, 1, 0},
+		Fov:      60,
+		Near:     0.1,
+		Far:      1000,
+	}
+}
+
+func TestCollectRenderCamerasKeepsPlainCameraAsPrimary(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	server := newVoxelRtAssetServerTest(t)
+	target := server.CreateRenderTargetTexture(256, 128)
+
+	mirror := cmd.AddEntity(renderViewTestCamera(mgl32.Vec3{0, 0, 10}, mgl32.Vec3{0, 0, 0}), &RenderViewComponent{Target: target})
+	cmd.AddEntity(renderViewTestCamera(mgl32.Vec3{0, 0, 5}, mgl32.Vec3{0, 0, 0}), &RenderViewComponent{Disabled: true})
+	player := cmd.AddEntity(renderViewTestCamera(mgl32.Vec3{1, 2, 3}, mgl32.Vec3{0, 0, 0}))
+	app.FlushCommands()
+
+	set := collectRenderCameras(cmd)
+	if !set.hasPrimary || set.primary.entity != player {
+		t.Fatalf("expected the plain camera to be primary, got %+v", set.primary)
+	}
+	if set.primaryViewport != (RenderViewport{}) {
+		t.Fatalf("expected the plain primary camera to cover the window, got %+v", set.primaryViewport)
+	}
+	if len(set.views) != 1 || set.views[0].entity != mirror {
+		t.Fatalf("expected only the enabled mirror view, got %+v", set.views)
+	}
+	if camera, ok := primaryRenderCamera(cmd); !ok || camera.Position != (mgl32.Vec3{1, 2, 3}) {
+		t.Fatalf("expected primaryRenderCamera to return the player camera, got %+v", camera)
+	}
+}
+
+func TestCollectRenderCamerasPromotesLowestPriorityWindowView(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+
+	left := RenderViewport{Width: 0.5, Height: 1}
+	right := RenderViewport{X: 0.5, Width: 0.5, Height: 1}
+	second := cmd.AddEntity(renderViewTestCamera(mgl32.Vec3{5, 0, 0}, mgl32.Vec3{0, 0, 0}), &RenderViewComponent{Viewport: right, Priority: 1})
+	first := cmd.AddEntity(renderViewTestCamera(mgl32.Vec3{-5, 0, 0}, mgl32.Vec3{0, 0, 0}), &RenderViewComponent{Viewport: left})
+	app.FlushCommands()
+
+	set := collectRenderCameras(cmd)
+	if !set.hasPrimary || set.primary.entity != first || set.primaryViewport != left {
+		t.Fatalf("expected the priority-0 split-screen camera to be primary in its viewport, got %+v", set)
+	}
+	if len(set.views) != 1 || set.views[0].entity != second {
+		t.Fatalf("expected the other split-screen camera as an extra view, got %+v", set.views)
+	}
+}
+
+func TestSyncVoxelRtRenderViewsBuildsTargetsAndHoldsBetweenIntervals(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	server := newVoxelRtAssetServerTest(t)
+	state := newVoxelRtStateTest()
+
+	target := server.CreateRenderTargetTexture(320, 180)
+	plain := server.CreateTextureFromTexels(make([]uint8, 4), 1, 1, 1, TextureDimension2D, TextureFormatRGBA8Unorm)
+	cmd.AddEntity(renderViewTestCamera(mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 0, -1}))
+	monitor := cmd.AddEntity(
+		renderViewTestCamera(mgl32.Vec3{0, 10, 0}, mgl32.Vec3{0, 0, 0}),
+		&RenderViewComponent{Target: target, Features: RenderViewSky | RenderViewBloom, UpdateInterval: 3},
+	)
+	cmd.AddEntity(renderViewTestCamera(mgl32.Vec3{0, 5, 0}, mgl32.Vec3{0, 0, 0}), &RenderViewComponent{Target: plain})
+	app.FlushCommands()
+
+	var holds []bool
+	for i := 0; i < 4; i++ {
+		syncVoxelRtRenderViews(state, server, collectRenderCameras(cmd))
+		views := state.RtApp.RenderViews
+		if len(views) != 1 {
+			t.Fatalf("expected only the render-target view, got %d", len(views))
+		}
+		holds = append(holds, views[0].Hold)
+	}
+	view := state.RtApp.RenderViews[0]
+	if view.TargetKey != spriteAtlasKey(target) || view.Width != 320 || view.Height != 180 {
+		t.Fatalf("unexpected offscreen view target %q %dx%d", view.TargetKey, view.Width, view.Height)
+	}
+	if view.Features != RenderViewSky|RenderViewBloom || view.Camera.Position != (mgl32.Vec3{0, 10, 0}) {
+		t.Fatalf("unexpected view features %v or camera %+v", view.Features, view.Camera.Position)
+	}
+	if want := []bool{false, true, true, false}; holds[0] != want[0] || holds[1] != want[1] || holds[2] != want[2] || holds[3] != want[3] {
+		t.Fatalf("hold pattern = %v, want %v", holds, want)
+	}
+	if len(state.renderViewEntities) != 1 || state.renderViewEntities[0] != monitor {
+		t.Fatalf("expected view entities to follow RenderViews, got %v", state.renderViewEntities)
+	}
+
+	cmd.RemoveEntity(monitor)
+	app.FlushCommands()
+	syncVoxelRtRenderViews(state, server, collectRenderCameras(cmd))
+	if len(state.RtApp.RenderViews) != 0 || len(state.renderViewLastTick) != 0 {
+		t.Fatalf("expected removed view to be dropped, got %d views and %d ticks", len(state.RtApp.RenderViews), len(state.renderViewLastTick))
+	}
+}
+
+func TestRenderViewVisibleEntitiesMapsPerViewCulling(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	server := newVoxelRtAssetServerTest(t)
+	state := newVoxelRtStateTest()
+
+	target := server.CreateRenderTargetTexture(64, 64)
+	cmd.AddEntity(renderViewTestCamera(mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 0, -1}))
+	rear := cmd.AddEntity(renderViewTestCamera(mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 0, 1}), &RenderViewComponent{Target: target})
+	app.FlushCommands()
+
+	front := core.NewVoxelObject()
+	front.Transform.Position = mgl32.Vec3{0, 0, -20}
+	front.XBrickMap.SetVoxel(0, 0, 0, 1)
+	behind := core.NewVoxelObject()
+	behind.Transform.Position = mgl32.Vec3{0, 0, 20}
+	behind.XBrickMap.SetVoxel(0, 0, 0, 1)
+	state.RtApp.Scene.AddObject(front)
+	state.RtApp.Scene.AddObject(behind)
+	const frontEntity, behindEntity EntityId = 101, 102
+	state.objectToEntity[front] = frontEntity
+	state.objectToEntity[behind] = behindEntity
+
+	syncVoxelRtRenderViews(state, server, collectRenderCameras(cmd))
+	views := state.RtApp.RenderViews
+	frustums := make([][6]mgl32.Vec4, len(views))
+	for i := range views {
+		proj := views[i].Camera.ProjectionMatrix(1)
+		frustums[i] = views[i].Camera.ExtractFrustum(proj.Mul4(views[i].Camera.GetViewMatrix()))
+	}
+	mainView := state.RtApp.Camera
+	mainView.Position = mgl32.Vec3{0, 0, 0}
+	mainView.LookAt = mgl32.Vec3{0, 0, -1}
+	mainPlanes := mainView.ExtractFrustum(mainView.ProjectionMatrix(1).Mul4(mainView.GetViewMatrix()))
+	state.RtApp.Scene.Commit(mainPlanes, core.SceneCommitOptions{ViewFrustums: frustums})
+
+	got := state.RenderViewVisibleEntities(rear)
+	if len(got) != 1 || got[0] != behindEntity {
+		t.Fatalf("expected rear view to see only the entity behind the player, got %v", got)
+	}
+	if len(state.RtApp.Scene.VisibleObjects) != 2 {
+		t.Fatalf("expected the shared upload to include both objects, got %d", len(state.RtApp.Scene.VisibleObjects))
+	}
+	if state.RenderViewVisibleEntities(frontEntity) != nil {
+		t.Fatal("expected non-view entities to have no visible list")
+	}
+}
+
+type renderViewTestTextured struct {
+	Albedo AssetId `gekko:"texture" group:"0" binding:"1"`
+	Target AssetId `gekko:"texture" group:"0" binding:"2"`
+}
+
+func TestLegacyTextureBindingsSkipRenderTargets(t *testing.T) {
+	app := NewApp()
+	cmd := app.Commands()
+	server := newVoxelRtAssetServerTest(t)
+
+	albedo := server.CreateTextureFromTexels(make([]uint8, 2*2*4), 2, 2, 1, TextureDimension2D, TextureFormatRGBA8UnormSrgb)
+	target := server.CreateRenderTargetTexture(64, 64)
+	e := cmd.AddEntity(&renderViewTestTextured{Albedo: albedo, Target: target})
+	app.FlushCommands()
+
+	descriptors, err := findTextureDescriptors(e, cmd, server)
+	if err == nil {
+		t.Fatal("expected the render target binding to be reported")
+	}
+	if _, ok := descriptors[target]; ok || len(descriptors) != 1 {
+		t.Fatalf("expected only the uploaded texture to be bound, got %v", descriptors)
+	}
+	if d, ok := descriptors[albedo]; !ok || d.binding != 1 {
+		t.Fatalf("expected the albedo binding to be kept, got %+v", d)
+	}
+}
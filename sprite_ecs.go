@@ -41,6 +41,13 @@ type SpriteComponent struct {
 	BillboardMode BillboardMode
 	Unlit         bool
 	AlphaMode     SpriteAlphaMode
+
+	// Impostor marks an octahedral impostor card. SpriteIndex is the primary
+	// view and BlendIndices/BlendWeights add two neighbouring views; the
+	// texture stacks color cells over normal/depth cells.
+	Impostor     bool
+	BlendIndices [2]uint32
+	BlendWeights [2]float32
 }
 
 type spriteSyncItem struct {
@@ -81,6 +88,11 @@ func spriteSyncItemFromComponent(sp *SpriteComponent) (spriteSyncItem, bool) {
 	if sp.Unlit {
 		inst.IsUnlit = 1
 	}
+	if sp.Impostor {
+		inst.IsImpostor = 1
+		inst.BlendIndices = sp.BlendIndices
+		inst.BlendWeights = sp.BlendWeights
+	}
 
 	return spriteSyncItem{
 		Instance: inst,
@@ -346,7 +346,7 @@ type streamedPreparedChunk struct {
 	PreparedImportedWorldGeometry         *volume.XBrickMap
 	PreparedImportedWorldGeometryCacheKey string
 	PlacementItems                        []streamedPlacementInstance
-	ObjectSnapshots                       map[string]*content.VoxelObjectSnapshotDef
+	ObjectSnapshots                       map[string]*volume.XBrickMap
 	PrepareDuration                       time.Duration
 	Err                                   error
 }
@@ -1740,7 +1740,7 @@ func prepareStreamedChunkLoad(job streamedChunkLoadJob) (result streamedPrepared
 	result = streamedPreparedChunk{
 		Coord:           job.Coord,
 		PlacementItems:  append([]streamedPlacementInstance(nil), job.Placements...),
-		ObjectSnapshots: make(map[string]*content.VoxelObjectSnapshotDef),
+		ObjectSnapshots: make(map[string]*volume.XBrickMap),
 	}
 	defer func() {
 		result.PrepareDuration = time.Since(start)
@@ -1789,7 +1789,7 @@ func prepareStreamedChunkLoad(job streamedChunkLoadJob) (result streamedPrepared
 	}
 	for key, override := range job.VoxelOverrides {
 		snapshotPath := content.ResolveDocumentPath(override.SnapshotPath, job.WorldDeltaPath)
-		snapshot, err := content.LoadVoxelObjectSnapshot(snapshotPath)
+		snapshot, err := LoadVoxelObjectSnapshotXBrickMap(snapshotPath)
 		if err != nil {
 			result.Err = err
 			return result
@@ -2247,7 +2247,7 @@ func persistChunkOverrides(cmd *Commands, state *StreamedLevelRuntimeState, coor
 			continue
 		}
 		snapshotPath := filepath.Join(state.WorldDataDir, fmt.Sprintf("object_%s_%s.gkvoxobj", sanitizePathSegment(placementID), sanitizePathSegment(itemID)))
-		if err := content.SaveVoxelObjectSnapshot(snapshotPath, VoxelObjectSnapshotFromXBrickMap(xbm)); err != nil {
+		if err := SaveVoxelObjectSnapshotXBrickMap(snapshotPath, xbm); err != nil {
 			return err
 		}
 		override := content.VoxelObjectOverrideDef{
@@ -2270,7 +2270,7 @@ func persistChunkOverrides(cmd *Commands, state *StreamedLevelRuntimeState, coor
 	return content.SaveWorldDelta(state.WorldDeltaPath, state.WorldDelta)
 }
 
-func applyVoxelObjectSnapshotToEntity(cmd *Commands, eid EntityId, snapshot *content.VoxelObjectSnapshotDef) error {
+func applyVoxelObjectSnapshotToEntity(cmd *Commands, eid EntityId, snapshot *volume.XBrickMap) error {
 	vmc, ok := voxelModelComponentForEntity(cmd, eid)
 	if !ok {
 		return nil
@@ -2279,7 +2279,7 @@ func applyVoxelObjectSnapshotToEntity(cmd *Commands, eid EntityId, snapshot *con
 	if assets == nil {
 		return fmt.Errorf("asset server not available")
 	}
-	vmc.OverrideGeometry = assets.RegisterSharedVoxelGeometry(XBrickMapFromVoxelObjectSnapshot(snapshot), "")
+	vmc.OverrideGeometry = assets.RegisterSharedVoxelGeometry(snapshot, "")
 	cmd.AddComponents(eid, &vmc)
 	return nil
 }
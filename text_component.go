@@ -5,4 +5,6 @@ type TextComponent struct {
 	Position [2]float32 // Pixels, top-left
 	Scale    float32
 	Color    [4]float32
+	// Loc, when its Key is set, replaces Text with the localized string.
+	Loc LocalizedText
 }
@@ -0,0 +1,199 @@
+package gekko
+
+import (
+	"fmt"
+
+	"github.com/gekko3d/gekko/content"
+	"github.com/gekko3d/gekko/voxelrt/rt/volume"
+)
+
+// voxSceneBuilder assembles a root transform and group with one named
+// transform+shape pair per model, the layout MagicaVoxel itself writes.
+type voxSceneBuilder struct {
+	file   *VoxFile
+	nextID int
+}
+
+func newVoxSceneBuilder(palette *VoxelPaletteAsset) *voxSceneBuilder {
+	file := &VoxFile{
+		Version: voxFileVersion,
+		Palette: defaultPalette(),
+		Nodes:   make(map[int]VoxNode),
+	}
+	if palette != nil {
+		file.Palette = palette.VoxPalette
+		file.VoxMaterials = append(file.VoxMaterials, palette.Materials...)
+	}
+	file.Nodes[0] = VoxNode{ID: 0, Type: VoxNodeTransform, Attributes: map[string]string{}, ChildID: 1, ReservedID: -1, LayerID: -1, Frames: []VoxTransformFrame{{}}}
+	file.Nodes[1] = VoxNode{ID: 1, Type: VoxNodeGroup, Attributes: map[string]string{}}
+	return &voxSceneBuilder{file: file, nextID: 2}
+}
+
+// addXBrickMap splits xbm into models of at most 256³ voxels and places each
+// one so its voxels keep their original coordinates.
+func (b *voxSceneBuilder) addXBrickMap(name string, xbm *volume.XBrickMap) {
+	if xbm == nil || xbm.GetVoxelCount() == 0 {
+		return
+	}
+	minB, maxB := xbm.ComputeAABB()
+	origin := [3]int{int(minB.X()), int(minB.Y()), int(minB.Z())}
+	extent := [3]int{int(maxB.X()) - origin[0], int(maxB.Y()) - origin[1], int(maxB.Z()) - origin[2]}
+	tiles := [3]int{}
+	for axis := 0; axis < 3; axis++ {
+		tiles[axis] = (extent[axis] + voxMaxModelSize - 1) / voxMaxModelSize
+	}
+	split := tiles[0]*tiles[1]*tiles[2] > 1
+	for tz := 0; tz < tiles[2]; tz++ {
+		for ty := 0; ty < tiles[1]; ty++ {
+			for tx := 0; tx < tiles[0]; tx++ {
+				tileMin := [3]int{origin[0] + tx*voxMaxModelSize, origin[1] + ty*voxMaxModelSize, origin[2] + tz*voxMaxModelSize}
+				var size [3]int
+				for axis, t := range [3]int{tx, ty, tz} {
+					size[axis] = min(voxMaxModelSize, extent[axis]-t*voxMaxModelSize)
+					// MagicaVoxel centers models on their translation, so even
+					// sizes keep the placement on whole voxels.
+					size[axis] += size[axis] % 2
+				}
+				model := VoxModel{SizeX: uint32(size[0]), SizeY: uint32(size[1]), SizeZ: uint32(size[2])}
+				for x := 0; x < size[0]; x++ {
+					for y := 0; y < size[1]; y++ {
+						for z := 0; z < size[2]; z++ {
+							if ok, value := xbm.GetVoxel(tileMin[0]+x, tileMin[1]+y, tileMin[2]+z); ok && value != 0 {
+								model.Voxels = append(model.Voxels, Voxel{X: uint32(x), Y: uint32(y), Z: uint32(z), ColorIndex: value})
+							}
+						}
+					}
+				}
+				if len(model.Voxels) == 0 {
+					continue
+				}
+				modelName := name
+				if split {
+					modelName = fmt.Sprintf("%s_%d_%d_%d", name, tx, ty, tz)
+				}
+				b.addModel(modelName, model, [3]float32{
+					float32(tileMin[0] + size[0]/2),
+					float32(tileMin[1] + size[1]/2),
+					float32(tileMin[2] + size[2]/2),
+				})
+			}
+		}
+	}
+}
+
+func (b *voxSceneBuilder) addModel(name string, model VoxModel, center [3]float32) {
+	modelIndex := len(b.file.Models)
+	b.file.Models = append(b.file.Models, model)
+
+	transformID, shapeID := b.nextID, b.nextID+1
+	b.nextID += 2
+	attrs := map[string]string{}
+	if name != "" {
+		attrs["_name"] = name
+	}
+	b.file.Nodes[transformID] = VoxNode{
+		ID:         transformID,
+		Type:       VoxNodeTransform,
+		Attributes: attrs,
+		ChildID:    shapeID,
+		ReservedID: -1,
+		Frames:     []VoxTransformFrame{{LocalTrans: center}},
+	}
+	b.file.Nodes[shapeID] = VoxNode{
+		ID:         shapeID,
+		Type:       VoxNodeShape,
+		Attributes: map[string]string{},
+		Models:     []VoxShapeModel{{ModelID: modelIndex, Attributes: map[string]string{}}},
+	}
+	group := b.file.Nodes[1]
+	group.ChildrenIDs = append(group.ChildrenIDs, transformID)
+	b.file.Nodes[1] = group
+}
+
+// VoxFileFromXBrickMap converts xbm into a MagicaVoxel scene, splitting it
+// into 256³ models as needed. A nil palette writes the default palette.
+func VoxFileFromXBrickMap(name string, xbm *volume.XBrickMap, palette *VoxelPaletteAsset) *VoxFile {
+	builder := newVoxSceneBuilder(palette)
+	builder.addXBrickMap(name, xbm)
+	return builder.file
+}
+
+// SaveXBrickMapVox writes xbm to a .vox file.
+func SaveXBrickMapVox(filename string, name string, xbm *volume.XBrickMap, palette *VoxelPaletteAsset) error {
+	return SaveVoxFile(filename, VoxFileFromXBrickMap(name, xbm, palette))
+}
+
+// VoxFileFromAuthoredAsset bakes each voxel-backed part of def into the
+// asset's voxel grid and writes it as its own named model, so artists can
+// touch parts up individually. Subtractive parts carve the parts before them
+// the same way voxel collapse does.
+func VoxFileFromAuthoredAsset(assets *AssetServer, def *content.AssetDef, documentPath string) (*VoxFile, error) {
+	if assets == nil {
+		return nil, fmt.Errorf("vox export requires asset server")
+	}
+	if def == nil {
+		return nil, fmt.Errorf("asset definition is nil")
+	}
+	parts, err := resolveAuthoredCollapseParts(assets, def, documentPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("asset %s has no voxel-backed parts", def.ID)
+	}
+	voxelResolution := parts[0].voxelResolution
+	paletteID, err := collapsePaletteID(assets, parts)
+	if err != nil {
+		return nil, err
+	}
+	palette, ok := assets.GetVoxelPalette(paletteID)
+	if !ok {
+		return nil, fmt.Errorf("missing palette for asset %s", def.ID)
+	}
+
+	type bakedPart struct {
+		name string
+		xbm  *volume.XBrickMap
+	}
+	baked := make([]bakedPart, 0, len(parts))
+	for _, part := range parts {
+		if absf(part.voxelResolution-voxelResolution) > 1e-5 {
+			return nil, fmt.Errorf("vox export requires matching voxel_resolution")
+		}
+		if voxelGeometryIsEmpty(part.geometry) {
+			continue
+		}
+		if content.EffectiveAssetSourceOperation(part.def.Source) == content.AssetShapeOperationSubtract {
+			for _, prior := range baked {
+				if err := bakeResolvedPartIntoComposite(prior.xbm, part, voxelResolution); err != nil {
+					return nil, fmt.Errorf("vox export bake failed for part %s: %w", part.def.ID, err)
+				}
+			}
+			continue
+		}
+		xbm := volume.NewXBrickMap()
+		if err := bakeResolvedPartIntoComposite(xbm, part, voxelResolution); err != nil {
+			return nil, fmt.Errorf("vox export bake failed for part %s: %w", part.def.ID, err)
+		}
+		name := part.def.Name
+		if name == "" {
+			name = part.def.ID
+		}
+		baked = append(baked, bakedPart{name: name, xbm: xbm})
+	}
+
+	builder := newVoxSceneBuilder(&palette)
+	for _, part := range baked {
+		builder.addXBrickMap(part.name, part.xbm)
+	}
+	return builder.file, nil
+}
+
+// SaveAuthoredAssetVox writes the voxel-backed parts of def to a .vox file.
+func SaveAuthoredAssetVox(assets *AssetServer, def *content.AssetDef, documentPath string, filename string) error {
+	voxFile, err := VoxFileFromAuthoredAsset(assets, def, documentPath)
+	if err != nil {
+		return err
+	}
+	return SaveVoxFile(filename, voxFile)
+}
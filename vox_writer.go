@@ -0,0 +1,226 @@
+package gekko
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+const (
+	voxFileVersion  = 150
+	voxMaxModelSize = 256
+)
+
+// SaveVoxFile writes voxFile as a MagicaVoxel .vox file. Models, palette,
+// MATL materials and the nTRN/nGRP/nSHP scene graph are written in the same
+// engine-axis convention LoadVoxFile produces.
+func SaveVoxFile(filename string, voxFile *VoxFile) error {
+	if dir := filepath.Dir(filename); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	out := bufio.NewWriter(file)
+	if err := WriteVoxFile(out, voxFile); err != nil {
+		return err
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func WriteVoxFile(w io.Writer, voxFile *VoxFile) error {
+	if voxFile == nil {
+		return fmt.Errorf("vox file is nil")
+	}
+
+	var children bytes.Buffer
+	for i, model := range voxFile.Models {
+		if model.SizeX == 0 || model.SizeY == 0 || model.SizeZ == 0 ||
+			model.SizeX > voxMaxModelSize || model.SizeY > voxMaxModelSize || model.SizeZ > voxMaxModelSize {
+			return fmt.Errorf("vox model %d has invalid size %dx%dx%d", i, model.SizeX, model.SizeY, model.SizeZ)
+		}
+		var size bytes.Buffer
+		writeVoxUint32(&size, model.SizeX)
+		writeVoxUint32(&size, model.SizeZ) // Vox Y is engine Z
+		writeVoxUint32(&size, model.SizeY) // Vox Z is engine Y
+		writeVoxChunk(&children, "SIZE", size.Bytes())
+
+		var xyzi bytes.Buffer
+		writeVoxUint32(&xyzi, uint32(len(model.Voxels)))
+		for _, voxel := range model.Voxels {
+			if voxel.X >= model.SizeX || voxel.Y >= model.SizeY || voxel.Z >= model.SizeZ {
+				return fmt.Errorf("vox model %d voxel (%d,%d,%d) is outside its size", i, voxel.X, voxel.Y, voxel.Z)
+			}
+			xyzi.WriteByte(byte(voxel.X))
+			xyzi.WriteByte(byte(voxel.Z))
+			xyzi.WriteByte(byte(voxel.Y))
+			xyzi.WriteByte(voxel.ColorIndex)
+		}
+		writeVoxChunk(&children, "XYZI", xyzi.Bytes())
+	}
+
+	nodeIDs := make([]int, 0, len(voxFile.Nodes))
+	for id := range voxFile.Nodes {
+		nodeIDs = append(nodeIDs, id)
+	}
+	sort.Ints(nodeIDs)
+	for _, id := range nodeIDs {
+		node := voxFile.Nodes[id]
+		chunkID, data := encodeVoxNode(node)
+		writeVoxChunk(&children, chunkID, data)
+	}
+
+	// RGBA entry i holds color index i+1; index 0 is always empty.
+	var rgba bytes.Buffer
+	for i := 0; i < 256; i++ {
+		if i < 255 {
+			rgba.Write(voxFile.Palette[i+1][:])
+		} else {
+			rgba.Write([]byte{0, 0, 0, 0})
+		}
+	}
+	writeVoxChunk(&children, "RGBA", rgba.Bytes())
+
+	materials := append([]VoxMaterial(nil), voxFile.VoxMaterials...)
+	sort.SliceStable(materials, func(i, j int) bool { return materials[i].ID < materials[j].ID })
+	for _, material := range materials {
+		writeVoxChunk(&children, "MATL", encodeVoxMaterial(material))
+	}
+
+	version := voxFile.Version
+	if version == 0 {
+		version = voxFileVersion
+	}
+	var header bytes.Buffer
+	header.WriteString(VOXMagicNumber)
+	writeVoxUint32(&header, uint32(version))
+	header.WriteString("MAIN")
+	writeVoxUint32(&header, 0)
+	writeVoxUint32(&header, uint32(children.Len()))
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return err
+	}
+	_, err := w.Write(children.Bytes())
+	return err
+}
+
+func encodeVoxNode(node VoxNode) (string, []byte) {
+	var buf bytes.Buffer
+	writeVoxUint32(&buf, uint32(node.ID))
+	writeVoxDICT(&buf, node.Attributes)
+	switch node.Type {
+	case VoxNodeGroup:
+		writeVoxUint32(&buf, uint32(len(node.ChildrenIDs)))
+		for _, childID := range node.ChildrenIDs {
+			writeVoxUint32(&buf, uint32(childID))
+		}
+		return "nGRP", buf.Bytes()
+	case VoxNodeShape:
+		writeVoxUint32(&buf, uint32(len(node.Models)))
+		for _, model := range node.Models {
+			writeVoxUint32(&buf, uint32(model.ModelID))
+			writeVoxDICT(&buf, model.Attributes)
+		}
+		return "nSHP", buf.Bytes()
+	default:
+		writeVoxUint32(&buf, uint32(node.ChildID))
+		writeVoxUint32(&buf, uint32(node.ReservedID))
+		writeVoxUint32(&buf, uint32(node.LayerID))
+		writeVoxUint32(&buf, uint32(len(node.Frames)))
+		for _, frame := range node.Frames {
+			writeVoxDICT(&buf, voxFrameAttributes(frame))
+		}
+		return "nTRN", buf.Bytes()
+	}
+}
+
+// voxFrameAttributes rebuilds _t and _r from the decoded frame fields so edits
+// to LocalTrans and Rotation win over the raw attributes read from disk.
+func voxFrameAttributes(frame VoxTransformFrame) map[string]string {
+	attrs := make(map[string]string, len(frame.Attributes)+2)
+	for key, value := range frame.Attributes {
+		attrs[key] = value
+	}
+	_, hadTrans := attrs["_t"]
+	if frame.LocalTrans != ([3]float32{}) || hadTrans {
+		attrs["_t"] = fmt.Sprintf("%d %d %d",
+			int(math.Round(float64(frame.LocalTrans[0]))),
+			int(math.Round(float64(frame.LocalTrans[2]))), // Vox Y is engine Z
+			int(math.Round(float64(frame.LocalTrans[1]))), // Vox Z is engine Y
+		)
+	}
+	_, hadRot := attrs["_r"]
+	if frame.Rotation != 0 || hadRot {
+		attrs["_r"] = strconv.Itoa(int(frame.Rotation))
+	}
+	return attrs
+}
+
+func encodeVoxMaterial(material VoxMaterial) []byte {
+	props := make(map[string]string, len(material.Property)+1)
+	for key, value := range material.Property {
+		switch v := value.(type) {
+		case float32:
+			props[key] = strconv.FormatFloat(float64(v), 'f', -1, 32)
+		case float64:
+			props[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		case string:
+			props[key] = v
+		default:
+			props[key] = fmt.Sprint(v)
+		}
+	}
+	if _, ok := props["_weight"]; !ok && material.Weight != 0 {
+		props["_weight"] = strconv.FormatFloat(float64(material.Weight), 'f', -1, 32)
+	}
+	var buf bytes.Buffer
+	writeVoxUint32(&buf, uint32(material.ID))
+	writeVoxDICT(&buf, props)
+	return buf.Bytes()
+}
+
+func writeVoxChunk(buf *bytes.Buffer, id string, data []byte) {
+	buf.WriteString(id)
+	writeVoxUint32(buf, uint32(len(data)))
+	writeVoxUint32(buf, 0)
+	buf.Write(data)
+}
+
+func writeVoxDICT(buf *bytes.Buffer, values map[string]string) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	writeVoxUint32(buf, uint32(len(keys)))
+	for _, key := range keys {
+		writeVoxString(buf, key)
+		writeVoxString(buf, values[key])
+	}
+}
+
+func writeVoxString(buf *bytes.Buffer, value string) {
+	writeVoxUint32(buf, uint32(len(value)))
+	buf.WriteString(value)
+}
+
+func writeVoxUint32(buf *bytes.Buffer, value uint32) {
+	var raw [4]byte
+	binary.LittleEndian.PutUint32(raw[:], value)
+	buf.Write(raw[:])
+}
@@ -0,0 +1,197 @@
+package gekko
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gekko3d/gekko/content"
+	"github.com/gekko3d/gekko/voxelrt/rt/volume"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestSaveVoxFileRoundTripsSyntheticScene(t *testing.T) {
+	dir := t.TempDir()
+	sourcePath := filepath.Join(dir, "source.vox")
+	writeSyntheticVoxFixture(t, sourcePath, syntheticNamedSceneNodes())
+
+	first, err := LoadVoxFile(sourcePath)
+	if err != nil {
+		t.Fatalf("LoadVoxFile failed: %v", err)
+	}
+	savedPath := filepath.Join(dir, "saved.vox")
+	if err := SaveVoxFile(savedPath, first); err != nil {
+		t.Fatalf("SaveVoxFile failed: %v", err)
+	}
+	second, err := LoadVoxFile(savedPath)
+	if err != nil {
+		t.Fatalf("LoadVoxFile of saved file failed: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("expected load->save->load to be lossless\nfirst:  %+v\nsecond: %+v", first, second)
+	}
+}
+
+func TestSaveVoxFilePreservesPaletteMaterialsAndTransforms(t *testing.T) {
+	source := namedHierarchyVoxForTest()
+	source.Models[0].Voxels = []Voxel{{X: 1, Y: 0, Z: 1, ColorIndex: 3}}
+	source.Models[1].Voxels = []Voxel{{X: 3, Y: 1, Z: 0, ColorIndex: 7}}
+	source.Palette = defaultPalette()
+	source.Palette[3] = [4]uint8{10, 20, 30, 255}
+	source.Palette[255] = [4]uint8{1, 2, 3, 4}
+	source.VoxMaterials = []VoxMaterial{{ID: 3, Property: map[string]interface{}{"_type": "_emit", "_emit": float32(2.5)}}}
+	arm := source.Nodes[4]
+	arm.Frames[0].Rotation = 0x11
+	arm.Frames[0].LocalTrans = [3]float32{-3, 5, 7}
+	source.Nodes[4] = arm
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scene.vox")
+	if err := SaveVoxFile(path, source); err != nil {
+		t.Fatalf("SaveVoxFile failed: %v", err)
+	}
+	loaded, err := LoadVoxFile(path)
+	if err != nil {
+		t.Fatalf("LoadVoxFile failed: %v", err)
+	}
+	if loaded.Palette[3] != source.Palette[3] || loaded.Palette[255] != source.Palette[255] {
+		t.Fatalf("palette not preserved: %v %v", loaded.Palette[3], loaded.Palette[255])
+	}
+	if len(loaded.VoxMaterials) != 1 || loaded.VoxMaterials[0].ID != 3 || loaded.VoxMaterials[0].Property["_emit"] != float32(2.5) {
+		t.Fatalf("material not preserved: %+v", loaded.VoxMaterials)
+	}
+	if !reflect.DeepEqual(loaded.Models, source.Models) {
+		t.Fatalf("models not preserved: %+v", loaded.Models)
+	}
+	frame := loaded.Nodes[4].Frames[0]
+	if frame.Rotation != 0x11 || frame.LocalTrans != [3]float32{-3, 5, 7} {
+		t.Fatalf("transform not preserved: %+v", frame)
+	}
+	if loaded.Nodes[4].Attributes["_name"] != "arm" {
+		t.Fatalf("node name not preserved: %+v", loaded.Nodes[4].Attributes)
+	}
+
+	againPath := filepath.Join(dir, "again.vox")
+	if err := SaveVoxFile(againPath, loaded); err != nil {
+		t.Fatalf("second SaveVoxFile failed: %v", err)
+	}
+	again, err := LoadVoxFile(againPath)
+	if err != nil {
+		t.Fatalf("second LoadVoxFile failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, again) {
+		t.Fatal("expected load->save->load to be lossless")
+	}
+}
+
+func TestVoxFileFromXBrickMapSplitsLargeMaps(t *testing.T) {
+	xbm := volume.NewXBrickMap()
+	volume.Cube(xbm, mgl32.Vec3{-10, 0, 0}, mgl32.Vec3{300, 2, 3}, 4)
+	xbm.SetVoxel(5, 1, 1, 9)
+
+	path := filepath.Join(t.TempDir(), "split.vox")
+	if err := SaveXBrickMapVox(path, "wall", xbm, nil); err != nil {
+		t.Fatalf("SaveXBrickMapVox failed: %v", err)
+	}
+	loaded, err := LoadVoxFile(path)
+	if err != nil {
+		t.Fatalf("LoadVoxFile failed: %v", err)
+	}
+	if len(loaded.Models) != 2 {
+		t.Fatalf("expected a 311-voxel span to split into 2 models, got %d", len(loaded.Models))
+	}
+
+	rebuilt := volume.NewXBrickMap()
+	for _, node := range loaded.Nodes {
+		if node.Type != VoxNodeTransform || node.Attributes["_name"] == "" {
+			continue
+		}
+		shape := loaded.Nodes[node.ChildID]
+		model := loaded.Models[shape.Models[0].ModelID]
+		center := node.Frames[0].LocalTrans
+		for _, voxel := range model.Voxels {
+			rebuilt.SetVoxel(
+				int(center[0])-int(model.SizeX)/2+int(voxel.X),
+				int(center[1])-int(model.SizeY)/2+int(voxel.Y),
+				int(center[2])-int(model.SizeZ)/2+int(voxel.Z),
+				voxel.ColorIndex,
+			)
+		}
+	}
+	assertVoxExportMapsEqual(t, xbm, rebuilt)
+}
+
+func TestVoxFileFromAuthoredAssetWritesNamedParts(t *testing.T) {
+	assets := newSpawnTestAssetServer()
+	def := content.NewAssetDef("vox-export")
+	def.Parts = []content.AssetPartDef{
+		{
+			ID:     "body",
+			Name:   "body",
+			Source: testProceduralPartSource(),
+			Transform: content.AssetTransformDef{
+				Rotation: content.Quat{0, 0, 0, 1},
+				Scale:    content.Vec3{1, 1, 1},
+			},
+			ModelScale: 1,
+		},
+		{
+			ID:   "tail",
+			Name: "tail",
+			Source: content.AssetSourceDef{
+				Kind:      content.AssetSourceKindProceduralPrimitive,
+				Primitive: "cube",
+				Params:    map[string]float32{"sx": 2, "sy": 2, "sz": 2},
+			},
+			Transform: content.AssetTransformDef{
+				Position: content.Vec3{2, 0, 0},
+				Rotation: content.Quat{0, 0, 0, 1},
+				Scale:    content.Vec3{1, 1, 1},
+			},
+			ModelScale: 1,
+		},
+	}
+
+	voxFile, err := VoxFileFromAuthoredAsset(assets, def, "")
+	if err != nil {
+		t.Fatalf("VoxFileFromAuthoredAsset failed: %v", err)
+	}
+	if len(voxFile.Models) != 2 {
+		t.Fatalf("expected one model per part, got %d", len(voxFile.Models))
+	}
+	names := map[string]bool{}
+	for _, node := range voxFile.Nodes {
+		if name := node.Attributes["_name"]; name != "" {
+			names[name] = true
+		}
+	}
+	if !names["body"] || !names["tail"] {
+		t.Fatalf("expected part names in the scene graph, got %v", names)
+	}
+	path := filepath.Join(t.TempDir(), "asset.vox")
+	if err := SaveVoxFile(path, voxFile); err != nil {
+		t.Fatalf("SaveVoxFile failed: %v", err)
+	}
+	if _, err := LoadVoxFile(path); err != nil {
+		t.Fatalf("LoadVoxFile failed: %v", err)
+	}
+}
+
+func assertVoxExportMapsEqual(t *testing.T, want, got *volume.XBrickMap) {
+	t.Helper()
+	if want.GetVoxelCount() != got.GetVoxelCount() {
+		t.Fatalf("voxel count mismatch: want %d, got %d", want.GetVoxelCount(), got.GetVoxelCount())
+	}
+	minB, maxB := want.ComputeAABB()
+	for x := int(minB.X()); x < int(maxB.X()); x++ {
+		for y := int(minB.Y()); y < int(maxB.Y()); y++ {
+			for z := int(minB.Z()); z < int(maxB.Z()); z++ {
+				_, wantValue := want.GetVoxel(x, y, z)
+				_, gotValue := got.GetVoxel(x, y, z)
+				if wantValue != gotValue {
+					t.Fatalf("voxel (%d,%d,%d): want %d, got %d", x, y, z, wantValue, gotValue)
+				}
+			}
+		}
+	}
+}
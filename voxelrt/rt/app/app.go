@@ -2,6 +2,8 @@ package app
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
 
 	"github.com/gekko3d/gekko/voxelrt/rt/core"
 	"github.com/gekko3d/gekko/voxelrt/rt/gpu"
@@ -59,6 +61,7 @@ type App struct {
 	AnalyticMediumResources *AnalyticMediumResources
 	AstronomicalResources   *AstronomicalResources
 	PlanetBodyResources     *PlanetBodyResources
+	PostProcessResources    *PostProcessResources
 
 	LastViewProj       mgl32.Mat4
 	LastTime           float64
@@ -76,6 +79,20 @@ type App struct {
 	OcclusionMode      core.OcclusionMode
 	FontPath           string
 	UIFontSize         float64
+	PostProcess        PostProcessSettings
+	// FontFallbackPaths are tried in order for glyphs FontPath lacks. Nil
+	// uses the common system fonts that exist.
+	FontFallbackPaths []string
+	// RenderViews are extra cameras rendered before the main view; see
+	// RenderView. PrimaryViewport places the main view on the swapchain.
+	RenderViews     []RenderView
+	PrimaryViewport RenderViewport
+	// DynamicResolution scales the internal render size to hold the G-buffer
+	// and lighting pass time; see DynamicResolutionController.
+	DynamicResolution DynamicResolutionSettings
+	// MaxDecals caps the decals uploaded per frame; zero uses
+	// DefaultMaxDecals.
+	MaxDecals int
 
 	FrameCount            int
 	FPS                   float64
@@ -92,6 +109,21 @@ type App struct {
 
 	features                  []Feature
 	defaultFeaturesRegistered bool
+
+	activeRenderView         *RenderView
+	renderViewTargets        map[string]*renderViewTarget
+	retiredRenderViewTargets []*renderViewTarget
+
+	captureRequests      []CaptureRequest
+	captureBurstIndex    int
+	frameCaptures        []*frameCapture
+	captureWriters       sync.WaitGroup
+	captureWritersActive atomic.Int32
+
+	renderScale          float32
+	resolutionController *DynamicResolutionController
+
+	decalInputs []DecalInput
 }
 
 const DefaultUIFontSize = 26.0
@@ -153,6 +185,7 @@ func NewApp(window *glfw.Window) *App {
 		OcclusionMode:   core.OcclusionOff,
 		FeatureConfig:   DefaultFeatureConfig(),
 		RenderGraph:     NewDefaultRenderGraph(),
+		PostProcess:     DefaultPostProcessSettings(),
 	}
 	return app
 }
@@ -484,7 +517,7 @@ func (a *App) Init() error {
 		return err
 	}
 
-	// Group 2: Materials
+	// Group 2: Materials and decals
 	lightBGL2, err := a.Device.CreateBindGroupLayout(&wgpu.BindGroupLayoutDescriptor{
 		Label: "Lighting BGL2",
 		Entries: []wgpu.BindGroupLayoutEntry{
@@ -497,6 +530,33 @@ func (a *App) Init() error {
 					HasDynamicOffset: false,
 				},
 			},
+			// Decal list
+			{
+				Binding:    4,
+				Visibility: wgpu.ShaderStageCompute,
+				Buffer: wgpu.BufferBindingLayout{
+					Type:             wgpu.BufferBindingTypeReadOnlyStorage,
+					MinBindingSize:   0,
+					HasDynamicOffset: false,
+				},
+			},
+			// Decal atlas
+			{
+				Binding:    5,
+				Visibility: wgpu.ShaderStageCompute,
+				Texture: wgpu.TextureBindingLayout{
+					SampleType:    wgpu.TextureSampleTypeFloat,
+					ViewDimension: wgpu.TextureViewDimension2D,
+				},
+			},
+			// Decal atlas sampler
+			{
+				Binding:    6,
+				Visibility: wgpu.ShaderStageCompute,
+				Sampler: wgpu.SamplerBindingLayout{
+					Type: wgpu.SamplerBindingTypeFiltering,
+				},
+			},
 		},
 	})
 	if err != nil {
@@ -602,11 +662,12 @@ func (a *App) Init() error {
 	invView := mgl32.Ident4()
 	invProj := mgl32.Ident4()
 	a.BufferManager.LightingQuality = a.EffectiveLightingQuality()
-	a.BufferManager.UpdateCamera(view, invView, invProj, a.Camera.Position, mgl32.Vec3{10, 20, 10}, a.Scene.AmbientLight, a.Camera.Position, 1.0, a.Scene.SkyAmbientMix, a.Camera.FarPlane(), a.Camera.DebugMode, a.RenderMode, uint32(len(a.Scene.Lights)), uint32(width), uint32(height), a.EffectiveLightingQuality())
+	renderWidth, renderHeight := a.renderExtent()
+	a.BufferManager.UpdateCamera(view, invView, invProj, a.Camera.Position, mgl32.Vec3{10, 20, 10}, a.Scene.AmbientLight, a.Camera.Position, 1.0, a.Scene.SkyAmbientMix, a.Camera.FarPlane(), a.Camera.DebugMode, a.RenderMode, uint32(len(a.Scene.Lights)), renderWidth, renderHeight, a.EffectiveLightingQuality())
 
 	// Ensure scene buffers are created (even if empty) before bind groups
 	a.BufferManager.UpdateScene(a.Scene, a.Camera, float32(width)/float32(height), a.Camera.Position)
-	a.BufferManager.UpdateTiledLightingResources(uint32(width), uint32(height))
+	a.BufferManager.UpdateTiledLightingResources(renderWidth, renderHeight)
 
 	// Shadow Pipeline
 	err = a.BufferManager.CreateShadowPipeline(shaders.ShadowMapWGSL)
@@ -614,7 +675,7 @@ func (a *App) Init() error {
 		return err
 	}
 
-	a.rebuildCoreSwapchainResources(width, height)
+	a.rebuildCoreSwapchainResources(int(renderWidth), int(renderHeight))
 	a.rebuildCoreSceneBindings()
 
 	// Initialize Hi-Z Occlusion
@@ -625,7 +686,8 @@ func (a *App) Init() error {
 		},
 	})
 	if err == nil {
-		a.BufferManager.SetupHiZ(a.Config.Width, a.Config.Height, hizMod)
+		hizW, hizH := a.renderExtent()
+		a.BufferManager.SetupHiZ(hizW, hizH, hizMod)
 	} else {
 		fmt.Printf("ERROR: Failed to create Hi-Z shader module: %v\n", err)
 	}
@@ -650,6 +712,8 @@ func (a *App) SetSpriteAtlas(data []byte, w, h uint32) {
 }
 
 func (a *App) Shutdown() {
+	a.flushFrameCaptures()
+	a.releaseRenderViewTargets()
 	a.shutdownRenderGraphNodes()
 	a.shutdownFeatures()
 }
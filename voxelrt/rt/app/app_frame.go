@@ -97,49 +97,44 @@ func (a *App) Resize(w, h int) {
 		a.Config.Width = uint32(w)
 		a.Config.Height = uint32(h)
 		a.Surface.Configure(a.Adapter, a.Device, a.Config)
-		a.rebuildCoreSwapchainResources(w, h)
-		a.rebuildCoreSceneBindings()
+		a.resizeRenderTargets()
+	}
+}
 
-		if err := a.resizeFeatures(uint32(w), uint32(h)); err != nil {
-			fmt.Printf("ERROR: Feature resize failed: %v\n", err)
-		}
-		if err := a.resizeRenderGraphNodes(uint32(w), uint32(h)); err != nil {
-			fmt.Printf("ERROR: Render graph resize failed: %v\n", err)
-		}
+// resizeRenderTargets rebuilds every internal target at renderExtent. Only
+// the swapchain keeps the surface size.
+func (a *App) resizeRenderTargets() {
+	w, h := a.renderExtent()
+	a.rebuildCoreSwapchainResources(int(w), int(h))
+	a.rebuildCoreSceneBindings()
+	if a.BufferManager.HiZPipeline != nil {
+		a.BufferManager.SetupHiZ(w, h, nil)
+	}
+
+	if err := a.resizeFeatures(w, h); err != nil {
+		fmt.Printf("ERROR: Feature resize failed: %v\n", err)
+	}
+	if err := a.resizeRenderGraphNodes(w, h); err != nil {
+		fmt.Printf("ERROR: Render graph resize failed: %v\n", err)
 	}
 }
 
 func (a *App) Update() {
 	a.PreviousProfilerStats = a.Profiler.GetStatsString()
 
+	scaledPassMs := a.scaledPassMs()
+
 	// Reset profiler timestamps for the upcoming render passes
 	a.Profiler.Reset()
-
-	// We assume a default light position or sync it if needed.
-	// Sync with scene light 0 if available
-	lightPos := mgl32.Vec3{500, 1000, 500}
-	sunIntensity := float32(1.0)
-	if len(a.Scene.Lights) > 0 {
-		lp := a.Scene.Lights[0].Position
-		lightPos = mgl32.Vec3{lp[0], lp[1], lp[2]}
-		sunIntensity = a.Scene.Lights[0].Color[3]
-		if sunIntensity < 0 {
-			sunIntensity = 0
-		}
-	}
+	a.updateDynamicResolution(scaledPassMs)
 
 	// Matrices
 	view := a.Camera.GetViewMatrix()
-	aspect := float32(a.Config.Width) / float32(a.Config.Height)
-	if aspect == 0 {
-		aspect = 1.0
-	}
+	aspect := a.mainViewAspect()
 	proj := a.Camera.ProjectionMatrix(aspect)
 
 	// Combined
 	viewProj := proj.Mul4(view)
-	invView := view.Inv()
-	invProj := proj.Inv()
 	renderOrigin := a.Camera.Position
 	fastCameraMotion := a.hasFastCameraMotion()
 
@@ -159,6 +154,7 @@ func (a *App) Update() {
 		CameraPosition:   a.Camera.Position,
 		FastCameraMotion: fastCameraMotion,
 		Profiler:         a.Profiler,
+		ViewFrustums:     a.renderViewFrustums(),
 	})
 	a.Profiler.EndScope("Scene Commit")
 
@@ -215,7 +211,10 @@ func (a *App) Update() {
 	if a.BufferManager.UpdateScene(a.Scene, a.Camera, aspect, renderOrigin) {
 		recreated = true
 	}
-	if a.BufferManager.UpdateTiledLightingResources(a.Config.Width, a.Config.Height) {
+	if a.BufferManager.UpdateTiledLightingResources(a.renderExtent()) {
+		recreated = true
+	}
+	if a.uploadDecals(renderOrigin, append([][6]mgl32.Vec4{planes}, a.renderViewFrustums()...)) {
 		recreated = true
 	}
 	a.Profiler.SetCount("VoxelSecUp", a.BufferManager.VoxelSectorsUploaded)
@@ -242,7 +241,7 @@ func (a *App) Update() {
 	}
 
 	// Update Camera Uniforms
-	a.BufferManager.UpdateCamera(viewProj, invView, invProj, a.Camera.Position, lightPos, a.Scene.AmbientLight, renderOrigin, sunIntensity, a.Scene.SkyAmbientMix, a.Camera.FarPlane(), a.Camera.DebugMode, a.RenderMode, uint32(len(a.Scene.Lights)), a.Config.Width, a.Config.Height, lightingQuality)
+	_, invView := a.writeCameraUniforms(a.Camera, aspect)
 	a.BufferManager.BeginVolumetricFrame()
 	historyBlend := float32(0.7)
 	if fastCameraMotion {
@@ -363,6 +362,22 @@ func (a *App) DrawRect(x, y, w, h float32, color [4]float32) {
 	})
 }
 
+// DrawImage queues a textured overlay rect that samples the sprite atlas
+// named by texture over uv (u0, v0, u1, v1). It keeps its order among
+// DrawRect calls and draws below text.
+func (a *App) DrawImage(x, y, w, h float32, texture string, uv [4]float32, color [4]float32) {
+	textResources := a.ensureTextResources()
+	textResources.RectItems = append(textResources.RectItems, core.RectItem{
+		X:       x,
+		Y:       y,
+		W:       w,
+		H:       h,
+		Color:   color,
+		Texture: texture,
+		UV:      uv,
+	})
+}
+
 func (a *App) MeasureText(text string, scale float32) (float32, float32) {
 	textRenderer := a.textRenderer()
 	if textRenderer == nil {
@@ -410,18 +425,15 @@ func (a *App) Render() {
 		return
 	}
 
-	wgX := (a.Config.Width + 7) / 8
-	wgY := (a.Config.Height + 7) / 8
-	frame := &FrameContext{
-		Width:         a.Config.Width,
-		Height:        a.Config.Height,
-		SwapchainView: view,
-		WorkgroupsX:   wgX,
-		WorkgroupsY:   wgY,
-	}
+	frame := a.newFrameContext(view)
 
 	a.recordRenderFrameMetrics()
+	capture := a.nextFrameCapture()
+	frame.LoadTarget = a.renderSecondaryViews(view)
+	a.renderFrameCaptureAlbedo(capture)
+	frame.Viewport = a.PrimaryViewport.Pixels(a.Config.Width, a.Config.Height)
 	a.recordRenderGraph(encoder, frame)
+	a.recordFrameCapture(encoder, capture)
 
 	a.Profiler.BeginScope("Submit/Present")
 	cmd, err := encoder.Finish(nil)
@@ -437,6 +449,8 @@ func (a *App) Render() {
 	a.Device.Poll(false, nil)
 	a.BufferManager.AdvanceRetiredBuffers()
 	a.Profiler.EndScope("Submit/Present")
+	a.submitFrameCapture(capture)
+	a.collectFrameCaptures(false)
 
 	// Update FPS
 	now := glfw.GetTime()
@@ -476,6 +490,9 @@ func (a *App) recordRenderGraph(encoder *wgpu.CommandEncoder, frame *FrameContex
 	}
 	if a.RenderGraph == nil {
 		for _, nodeName := range runtimeRenderGraphNodeSequence() {
+			if !a.renderViewRunsNode(nodeName) {
+				continue
+			}
 			a.runLegacyRenderGraphFeatureNode(nodeName, encoder, frame)
 		}
 		return
@@ -513,6 +530,9 @@ func runtimeRenderGraphNodesAfterLightingMetrics() []string {
 		RenderNodeCoreAccumulation,
 		RenderNodeFeaturePreResolve,
 		RenderNodeCoreResolve,
+		RenderNodePostProcessBloom,
+		RenderNodePostProcessComposite,
+		RenderNodePostProcessAntiAlias,
 		RenderNodeFeatureTextOverlay,
 		RenderNodeFeatureGizmosOverlay,
 		RenderNodeFeaturePostResolve,
@@ -597,6 +617,19 @@ func (a *App) runLegacyRenderGraphFeatureNode(name string, encoder *wgpu.Command
 		if err := a.recordResolvePass(encoder, frame); err != nil {
 			fmt.Printf("ERROR: Resolve pass failed: %v\n", err)
 		}
+	case RenderNodePostProcessBloom:
+		if err := a.recordPostProcessBloomPass(encoder); err != nil {
+			fmt.Printf("ERROR: Post-process bloom pass failed: %v\n", err)
+		}
+	case RenderNodePostProcessComposite:
+		a.updatePostProcessUniform()
+		if err := a.recordPostProcessCompositePass(encoder, frame); err != nil {
+			fmt.Printf("ERROR: Post-process composite pass failed: %v\n", err)
+		}
+	case RenderNodePostProcessAntiAlias:
+		if err := a.recordPostProcessAntiAliasPass(encoder, frame); err != nil {
+			fmt.Printf("ERROR: Post-process anti-alias pass failed: %v\n", err)
+		}
 	case RenderNodeFeatureTextOverlay:
 		if err := a.recordTextOverlayPass(encoder, frame); err != nil {
 			fmt.Printf("ERROR: Text overlay pass failed: %v\n", err)
@@ -984,6 +1017,7 @@ func (a *App) recordAccumulationPass(encoder *wgpu.CommandEncoder) error {
 	})
 	var featureErr error
 	if needsAccumulation {
+		a.setInternalRenderRectViewport(accPass)
 		featureErr = a.renderPassStageForRenderGraph(FeaturePassStageAccumulation, accPass)
 	}
 	if err := accPass.End(); err != nil {
@@ -1020,13 +1054,17 @@ func (a *App) recordResolvePass(encoder *wgpu.CommandEncoder, frame *FrameContex
 	if frame.SwapchainView == nil {
 		return fmt.Errorf("resolve swapchain view is nil")
 	}
+	target := a.resolveTargetView()
+	if target == nil {
+		return fmt.Errorf("resolve HDR target view is nil")
+	}
 
 	a.Profiler.BeginScope("Resolve")
 	defer a.Profiler.EndScope("Resolve")
 
 	rPass := encoder.BeginRenderPass(&wgpu.RenderPassDescriptor{
 		ColorAttachments: []wgpu.RenderPassColorAttachment{{
-			View:       frame.SwapchainView,
+			View:       target,
 			LoadOp:     wgpu.LoadOpClear,
 			StoreOp:    wgpu.StoreOpStore,
 			ClearValue: wgpu.Color{R: 0, G: 0, B: 0, A: 1},
@@ -1034,6 +1072,7 @@ func (a *App) recordResolvePass(encoder *wgpu.CommandEncoder, frame *FrameContex
 	})
 	if a.ResolvePipeline != nil && a.ResolveBG != nil {
 		rPass.SetPipeline(a.ResolvePipeline)
+		a.setInternalRenderRectViewport(rPass)
 		rPass.SetBindGroup(0, a.ResolveBG, nil)
 		rPass.Draw(3, 1, 0, 0)
 	}
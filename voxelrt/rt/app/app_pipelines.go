@@ -604,7 +604,8 @@ func (a *App) setupTransparentOverlayPipeline() {
 }
 
 // setupResolvePipeline creates a fullscreen resolve pass that composites the opaque lit
-// color (StorageTexture) with the accumulated transparent color/weight textures onto the swapchain.
+// color (StorageTexture) with the accumulated transparent color/weight textures into the
+// linear HDR post-process target.
 func (a *App) setupResolvePipeline() {
 	// Build shader module
 	resMod, err := a.Device.CreateShaderModule(&wgpu.ShaderModuleDescriptor{
@@ -717,7 +718,7 @@ func (a *App) setupResolvePipeline() {
 		return
 	}
 
-	// Render pipeline to swapchain
+	// Render pipeline to the HDR post-process target
 	pipeline, err := a.Device.CreateRenderPipeline(&wgpu.RenderPipelineDescriptor{
 		Label:  "Resolve Pipeline",
 		Layout: pl,
@@ -729,7 +730,7 @@ func (a *App) setupResolvePipeline() {
 			Module:     resMod,
 			EntryPoint: "fs_main",
 			Targets: []wgpu.ColorTargetState{{
-				Format:    a.Config.Format,
+				Format:    wgpu.TextureFormatRGBA16Float,
 				WriteMask: wgpu.ColorWriteMaskAll,
 			}},
 		},
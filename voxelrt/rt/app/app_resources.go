@@ -12,7 +12,10 @@ func (a *App) rebuildCoreSwapchainResources(width, height int) {
 	a.BufferManager.UpdateTiledLightingResources(uint32(width), uint32(height))
 	a.BufferManager.StorageView = a.StorageView
 	a.setupBindGroups()
+	a.setupPostProcessTargets(width, height)
 	a.setupResolvePipeline()
+	a.setupPostProcessPipelines()
+	a.allocateRenderGraphResources(uint32(width), uint32(height))
 }
 
 // rebuildCoreSceneBindings recreates bind groups backed by scene, lighting, or
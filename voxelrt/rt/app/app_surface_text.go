@@ -21,28 +21,7 @@ func (a *App) setupTextResources() {
 	if textResources == nil || textResources.Renderer == nil {
 		return
 	}
-	// Texture
-	tr := textResources.Renderer
-	w, h := tr.AtlasImage.Bounds().Dx(), tr.AtlasImage.Bounds().Dy()
-	tex, err := a.Device.CreateTexture(&wgpu.TextureDescriptor{
-		Label:         "Text Atlas",
-		Size:          wgpu.Extent3D{Width: uint32(w), Height: uint32(h), DepthOrArrayLayers: 1},
-		Format:        wgpu.TextureFormatR8Unorm,
-		Usage:         wgpu.TextureUsageTextureBinding | wgpu.TextureUsageCopyDst,
-		Dimension:     wgpu.TextureDimension2D,
-		MipLevelCount: 1,
-		SampleCount:   1,
-	})
-	if err != nil {
-		panic(err)
-	}
-	a.Queue.WriteTexture(tex.AsImageCopy(), tr.AtlasImage.Pix, &wgpu.TextureDataLayout{
-		Offset:       0,
-		BytesPerRow:  uint32(w),
-		RowsPerImage: uint32(h),
-	}, &wgpu.Extent3D{Width: uint32(w), Height: uint32(h), DepthOrArrayLayers: 1})
-
-	textResources.AtlasView, _ = tex.CreateView(nil)
+	a.createTextAtlas()
 
 	// Pipeline
 	textMod, err := a.Device.CreateShaderModule(&wgpu.ShaderModuleDescriptor{
@@ -54,8 +33,28 @@ func (a *App) setupTextResources() {
 		return
 	}
 
-	textResources.Pipeline, err = a.Device.CreateRenderPipeline(&wgpu.RenderPipelineDescriptor{
-		Label: "Text Pipeline",
+	textResources.Pipeline, err = a.createTextPipeline(textMod, "Text Pipeline", "fs_main")
+	if err != nil {
+		fmt.Printf("ERROR: Failed to create text render pipeline: %v\n", err)
+		return
+	}
+	textResources.ImagePipeline, err = a.createTextPipeline(textMod, "Text Image Pipeline", "fs_image")
+	if err != nil {
+		fmt.Printf("ERROR: Failed to create text image pipeline: %v\n", err)
+	}
+	for key, cached := range textResources.imageBindGroups {
+		cached.group.Release()
+		delete(textResources.imageBindGroups, key)
+	}
+
+	a.createTextBindGroup()
+}
+
+// createTextPipeline builds an overlay pipeline over the text vertex layout
+// with the given fragment entry point.
+func (a *App) createTextPipeline(textMod *wgpu.ShaderModule, label string, entryPoint string) (*wgpu.RenderPipeline, error) {
+	return a.Device.CreateRenderPipeline(&wgpu.RenderPipelineDescriptor{
+		Label: label,
 		Vertex: wgpu.VertexState{
 			Module:     textMod,
 			EntryPoint: "vs_main",
@@ -71,7 +70,7 @@ func (a *App) setupTextResources() {
 		},
 		Fragment: &wgpu.FragmentState{
 			Module:     textMod,
-			EntryPoint: "fs_main",
+			EntryPoint: entryPoint,
 			Targets: []wgpu.ColorTargetState{{
 				Format: a.Config.Format,
 				Blend: &wgpu.BlendState{
@@ -97,11 +96,46 @@ func (a *App) setupTextResources() {
 			Mask:  0xFFFFFFFF,
 		},
 	})
+}
+
+// createTextAtlas uploads the glyph atlas to a new texture.
+func (a *App) createTextAtlas() {
+	textResources := a.textResources()
+	tr := textResources.Renderer
+	w, h := tr.AtlasImage.Bounds().Dx(), tr.AtlasImage.Bounds().Dy()
+	tex, err := a.Device.CreateTexture(&wgpu.TextureDescriptor{
+		Label:         "Text Atlas",
+		Size:          wgpu.Extent3D{Width: uint32(w), Height: uint32(h), DepthOrArrayLayers: 1},
+		Format:        wgpu.TextureFormatR8Unorm,
+		Usage:         wgpu.TextureUsageTextureBinding | wgpu.TextureUsageCopyDst,
+		Dimension:     wgpu.TextureDimension2D,
+		MipLevelCount: 1,
+		SampleCount:   1,
+	})
 	if err != nil {
-		fmt.Printf("ERROR: Failed to create text render pipeline: %v\n", err)
-		return
+		panic(err)
 	}
+	textResources.AtlasTexture = tex
+	a.writeTextAtlas()
 
+	textResources.AtlasView, _ = tex.CreateView(nil)
+}
+
+func (a *App) writeTextAtlas() {
+	textResources := a.textResources()
+	tr := textResources.Renderer
+	w, h := tr.AtlasImage.Bounds().Dx(), tr.AtlasImage.Bounds().Dy()
+	a.Queue.WriteTexture(textResources.AtlasTexture.AsImageCopy(), tr.AtlasImage.Pix, &wgpu.TextureDataLayout{
+		Offset:       0,
+		BytesPerRow:  uint32(w),
+		RowsPerImage: uint32(h),
+	}, &wgpu.Extent3D{Width: uint32(w), Height: uint32(h), DepthOrArrayLayers: 1})
+	tr.AtlasDirty = false
+}
+
+func (a *App) createTextBindGroup() {
+	textResources := a.textResources()
+	var err error
 	textResources.BindGroup, err = a.Device.CreateBindGroup(&wgpu.BindGroupDescriptor{
 		Layout: textResources.Pipeline.GetBindGroupLayout(0),
 		Entries: []wgpu.BindGroupEntry{
@@ -115,6 +149,26 @@ func (a *App) setupTextResources() {
 	}
 }
 
+// refreshTextAtlas uploads glyphs added since the last frame. A grown atlas
+// gets a new texture and bind group.
+func (a *App) refreshTextAtlas() {
+	textResources := a.textResources()
+	if textResources == nil || textResources.Renderer == nil || !textResources.Renderer.AtlasDirty || textResources.AtlasTexture == nil {
+		return
+	}
+	tr := textResources.Renderer
+	if textResources.AtlasTexture.GetWidth() == uint32(tr.AtlasImage.Bounds().Dx()) {
+		a.writeTextAtlas()
+		return
+	}
+	textResources.AtlasView.Release()
+	textResources.AtlasTexture.Release()
+	a.createTextAtlas()
+	if textResources.Pipeline != nil {
+		a.createTextBindGroup()
+	}
+}
+
 func (a *App) createParticleSimPipelines(mod *wgpu.ShaderModule) {
 	var err error
 
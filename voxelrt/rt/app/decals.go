@@ -0,0 +1,162 @@
+package app
+
+import (
+	"math"
+	"sort"
+
+	"github.com/cogentcore/webgpu/wgpu"
+	"github.com/gekko3d/gekko/voxelrt/rt/core"
+	"github.com/gekko3d/gekko/voxelrt/rt/gpu"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// DefaultMaxDecals caps the decals uploaded per frame when App.MaxDecals is
+// zero. Deferred lighting tests every uploaded decal per pixel.
+const DefaultMaxDecals = 256
+
+// DecalInput is one box projector in world space. The box is centered on
+// Position, oriented by Rotation and spans Size. Its image covers the local
+// XY face and it projects along local -Z onto surfaces facing +Z.
+type DecalInput struct {
+	Position mgl32.Vec3
+	Rotation mgl32.Quat
+	Size     mgl32.Vec3
+	// Region is the atlas UV rectangle (u0, v0, u1, v1).
+	Region [4]float32
+	// Color tints the texture; alpha includes any lifetime fade.
+	Color [4]float32
+	// Priority draws higher decals on top and keeps them first when over
+	// budget. Order breaks ties; higher values are newer.
+	Priority int32
+	Order    uint64
+	// MinFacing is the minimum cosine between a surface normal and local +Z.
+	MinFacing float32
+	// VoxelSnap projects at voxel centers so the image follows the voxel grid.
+	VoxelSnap bool
+}
+
+// ApplyDecalInput replaces the decals projected on the next frames.
+func (a *App) ApplyDecalInput(decals []DecalInput) {
+	if a == nil {
+		return
+	}
+	a.decalInputs = append(a.decalInputs[:0], decals...)
+}
+
+// SetDecalAtlas uploads the texture every decal Region samples from.
+func (a *App) SetDecalAtlas(texels []byte, w, h uint32, version uint, format wgpu.TextureFormat) {
+	if a == nil || a.BufferManager == nil {
+		return
+	}
+	a.BufferManager.SetDecalAtlas(texels, w, h, version, format)
+}
+
+func (a *App) maxDecals() int {
+	if a.MaxDecals > 0 {
+		return a.MaxDecals
+	}
+	return DefaultMaxDecals
+}
+
+// uploadDecals culls the decal input against the view frustums and uploads
+// what fits the budget. It reports whether lighting bindings must be rebuilt.
+func (a *App) uploadDecals(renderOrigin mgl32.Vec3, frustums [][6]mgl32.Vec4) bool {
+	if a == nil || a.BufferManager == nil {
+		return false
+	}
+	visible := selectVisibleDecals(a.decalInputs, frustums, a.maxDecals())
+	hosts := make([]gpu.DecalHost, 0, len(visible))
+	for _, decal := range visible {
+		hosts = append(hosts, decalHost(decal, renderOrigin))
+	}
+	a.Profiler.SetCount("Decals", len(a.decalInputs))
+	a.Profiler.SetCount("VisibleDecals", len(hosts))
+	return a.BufferManager.UpdateDecals(hosts)
+}
+
+// selectVisibleDecals returns the decals whose projector box touches any of
+// the frustums, in draw order: lower priority first, older first within a
+// priority. Past budget the lowest priority, then oldest, decals are dropped.
+func selectVisibleDecals(decals []DecalInput, frustums [][6]mgl32.Vec4, budget int) []DecalInput {
+	if budget <= 0 || len(decals) == 0 {
+		return nil
+	}
+	visible := make([]DecalInput, 0, len(decals))
+	for _, decal := range decals {
+		if !decalDrawable(decal) {
+			continue
+		}
+		bounds := decalBounds(decal)
+		inView := len(frustums) == 0
+		for _, planes := range frustums {
+			if core.AABBInFrustum(bounds, planes) {
+				inView = true
+				break
+			}
+		}
+		if inView {
+			visible = append(visible, decal)
+		}
+	}
+	sort.SliceStable(visible, func(i, j int) bool {
+		if visible[i].Priority != visible[j].Priority {
+			return visible[i].Priority < visible[j].Priority
+		}
+		return visible[i].Order < visible[j].Order
+	})
+	if len(visible) > budget {
+		visible = visible[len(visible)-budget:]
+	}
+	return visible
+}
+
+func decalDrawable(decal DecalInput) bool {
+	if !(decal.Color[3] > 0) {
+		return false
+	}
+	for i := 0; i < 3; i++ {
+		if !(decal.Size[i] > 0) || math.IsInf(float64(decal.Size[i]), 0) {
+			return false
+		}
+		if math.IsNaN(float64(decal.Position[i])) || math.IsInf(float64(decal.Position[i]), 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func decalRotation(decal DecalInput) mgl32.Quat {
+	if decal.Rotation.Len() < 1e-6 {
+		return mgl32.QuatIdent()
+	}
+	return decal.Rotation.Normalize()
+}
+
+// decalBounds returns the world-space AABB of the decal's projector box.
+func decalBounds(decal DecalInput) [2]mgl32.Vec3 {
+	rot := decalRotation(decal).Mat4()
+	half := decal.Size.Mul(0.5)
+	var extent mgl32.Vec3
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			extent[row] += float32(math.Abs(float64(rot.At(row, col)))) * half[col]
+		}
+	}
+	return [2]mgl32.Vec3{decal.Position.Sub(extent), decal.Position.Add(extent)}
+}
+
+func decalHost(decal DecalInput, renderOrigin mgl32.Vec3) gpu.DecalHost {
+	rot := decalRotation(decal)
+	center := decal.Position.Sub(renderOrigin)
+	worldToDecal := mgl32.Scale3D(1/decal.Size.X(), 1/decal.Size.Y(), 1/decal.Size.Z()).
+		Mul4(rot.Inverse().Mat4()).
+		Mul4(mgl32.Translate3D(-center.X(), -center.Y(), -center.Z()))
+	axis := rot.Rotate(mgl32.Vec3{0, 0, 1})
+	return gpu.DecalHost{
+		WorldToDecal: worldToDecal,
+		Region:       decal.Region,
+		Color:        decal.Color,
+		Facing:       [4]float32{axis.X(), axis.Y(), axis.Z(), decal.MinFacing},
+		VoxelSnap:    decal.VoxelSnap,
+	}
+}
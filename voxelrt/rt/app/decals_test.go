@@ -0,0 +1,85 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// boxFrustum returns inward-facing planes bounding |x|,|y|,|z| <= half.
+func boxFrustum(half float32) [6]mgl32.Vec4 {
+	return [6]mgl32.Vec4{
+		{1, 0, 0, half}, {-1, 0, 0, half},
+		{0, 1, 0, half}, {0, -1, 0, half},
+		{0, 0, 1, half}, {0, 0, -1, half},
+	}
+}
+
+func testDecal(x float32, priority int32, order uint64) DecalInput {
+	return DecalInput{
+		Position: mgl32.Vec3{x, 0, 0},
+		Rotation: mgl32.QuatIdent(),
+		Size:     mgl32.Vec3{1, 1, 1},
+		Color:    [4]float32{1, 1, 1, 1},
+		Priority: priority,
+		Order:    order,
+	}
+}
+
+func TestSelectVisibleDecalsCullsAgainstAnyFrustum(t *testing.T) {
+	decals := []DecalInput{testDecal(0, 0, 1), testDecal(20, 0, 2), testDecal(10.4, 0, 3)}
+	got := selectVisibleDecals(decals, [][6]mgl32.Vec4{boxFrustum(10)}, 8)
+	if len(got) != 2 || got[0].Order != 1 || got[1].Order != 3 {
+		t.Fatalf("expected the far decal culled and the straddling one kept, got %+v", got)
+	}
+
+	second := boxFrustum(10)
+	second[0][3], second[1][3] = -15, 25 // x in [15, 25]
+	got = selectVisibleDecals(decals, [][6]mgl32.Vec4{boxFrustum(10), second}, 8)
+	if len(got) != 3 {
+		t.Fatalf("expected a decal seen by a second view to be kept, got %d", len(got))
+	}
+	if got = selectVisibleDecals(decals, nil, 8); len(got) != 3 {
+		t.Fatalf("expected no frustums to keep every decal, got %d", len(got))
+	}
+}
+
+func TestSelectVisibleDecalsKeepsHighestPriorityNewestWithinBudget(t *testing.T) {
+	invisible := testDecal(0, 9, 9)
+	invisible.Color[3] = 0
+	degenerate := testDecal(0, 9, 10)
+	degenerate.Size[2] = 0
+	decals := []DecalInput{testDecal(0, 1, 5), testDecal(0, 0, 1), testDecal(0, 0, 4), testDecal(0, 2, 2), invisible, degenerate}
+
+	got := selectVisibleDecals(decals, nil, 3)
+	if len(got) != 3 || got[0].Order != 4 || got[1].Order != 5 || got[2].Order != 2 {
+		t.Fatalf("unexpected budgeted draw order %+v", got)
+	}
+	if selectVisibleDecals(decals, nil, 0) != nil {
+		t.Fatal("expected a zero budget to select nothing")
+	}
+}
+
+func TestDecalHostMapsBoxToUnitSpace(t *testing.T) {
+	decal := testDecal(0, 0, 0)
+	decal.Position = mgl32.Vec3{10, 5, 0}
+	decal.Rotation = mgl32.QuatRotate(mgl32.DegToRad(-90), mgl32.Vec3{1, 0, 0}) // +Z to +Y
+	decal.Size = mgl32.Vec3{4, 2, 1}
+	decal.MinFacing = 0.25
+
+	host := decalHost(decal, mgl32.Vec3{10, 0, 0})
+	axis := mgl32.Vec3{host.Facing[0], host.Facing[1], host.Facing[2]}
+	if axis.Sub(mgl32.Vec3{0, 1, 0}).Len() > 1e-5 || host.Facing[3] != 0.25 {
+		t.Fatalf("unexpected facing %v", host.Facing)
+	}
+	// Render-relative center is (0, 5, 0); the box's local X spans 4 units.
+	corner := host.WorldToDecal.Mul4x1(mgl32.Vec4{2, 5.5, 0, 1})
+	if corner.Sub(mgl32.Vec4{0.5, 0, 0.5, 1}).Len() > 1e-5 {
+		t.Fatalf("unexpected decal-space point %v", corner)
+	}
+
+	bounds := decalBounds(decal)
+	if bounds[0].Sub(mgl32.Vec3{8, 4.5, -1}).Len() > 1e-5 || bounds[1].Sub(mgl32.Vec3{12, 5.5, 1}).Len() > 1e-5 {
+		t.Fatalf("unexpected rotated bounds %v", bounds)
+	}
+}
@@ -0,0 +1,264 @@
+package app
+
+import (
+	"math"
+	"time"
+)
+
+// DynamicResolutionSettings configures the render-scale controller. Zero
+// fields use the defaults of DefaultDynamicResolutionSettings.
+type DynamicResolutionSettings struct {
+	Enabled bool
+	// TargetFrameMs is the time the scaled passes must stay under.
+	TargetFrameMs float32
+	// MinScale and MaxScale bound the internal render scale per axis.
+	MinScale float32
+	MaxScale float32
+	// Step quantizes the scale so small timing changes do not reallocate
+	// targets.
+	Step float32
+	// Headroom is the fraction of TargetFrameMs the pass time must stay
+	// under before the scale is raised. Between Headroom*TargetFrameMs and
+	// TargetFrameMs the scale is held.
+	Headroom float32
+	// Window is the number of frame timings averaged per decision.
+	Window int
+	// Cooldown is the number of frames to wait after a change.
+	Cooldown int
+}
+
+func DefaultDynamicResolutionSettings() DynamicResolutionSettings {
+	return DynamicResolutionSettings{
+		TargetFrameMs: 16.6,
+		MinScale:      0.5,
+		MaxScale:      1,
+		Step:          0.05,
+		Headroom:      0.8,
+		Window:        8,
+		Cooldown:      30,
+	}
+}
+
+func (s DynamicResolutionSettings) normalized() DynamicResolutionSettings {
+	def := DefaultDynamicResolutionSettings()
+	if !(s.TargetFrameMs > 0) {
+		s.TargetFrameMs = def.TargetFrameMs
+	}
+	if !(s.MaxScale > 0) {
+		s.MaxScale = def.MaxScale
+	}
+	if !(s.MinScale > 0) {
+		s.MinScale = minf(def.MinScale, s.MaxScale)
+	}
+	s.MaxScale = minf(s.MaxScale, 2)
+	s.MinScale = minf(maxf(s.MinScale, 0.1), s.MaxScale)
+	if !(s.Step > 0) {
+		s.Step = def.Step
+	}
+	if !(s.Headroom > 0) || s.Headroom >= 1 {
+		s.Headroom = def.Headroom
+	}
+	if s.Window <= 0 {
+		s.Window = def.Window
+	}
+	if s.Cooldown < 0 {
+		s.Cooldown = 0
+	} else if s.Cooldown == 0 {
+		s.Cooldown = def.Cooldown
+	}
+	return s
+}
+
+// DynamicResolutionController picks the render scale from per-frame pass times.
+// It is a pure state machine: Observe takes one timing per frame.
+//
+// When the averaged time is over budget the scale drops straight to the step
+// predicted to land inside the hold band, assuming cost grows with pixel
+// count. When it is under Headroom of the budget the scale rises one step.
+// Every change clears the window and starts a cooldown.
+type DynamicResolutionController struct {
+	settings DynamicResolutionSettings
+	scale    float32
+	samples  []float32
+	cooldown int
+}
+
+func NewDynamicResolutionController(settings DynamicResolutionSettings) *DynamicResolutionController {
+	settings = settings.normalized()
+	return &DynamicResolutionController{settings: settings, scale: settings.MaxScale}
+}
+
+func (c *DynamicResolutionController) Settings() DynamicResolutionSettings {
+	return c.settings
+}
+
+func (c *DynamicResolutionController) Scale() float32 {
+	return c.scale
+}
+
+// AverageFrameMs returns the mean of the timings collected since the last
+// change.
+func (c *DynamicResolutionController) AverageFrameMs() float32 {
+	if len(c.samples) == 0 {
+		return 0
+	}
+	sum := float32(0)
+	for _, v := range c.samples {
+		sum += v
+	}
+	return sum / float32(len(c.samples))
+}
+
+// Observe feeds one frame's pass time and returns the scale to render with and
+// whether it changed.
+func (c *DynamicResolutionController) Observe(frameMs float32) (float32, bool) {
+	if !(frameMs > 0) || math.IsInf(float64(frameMs), 0) {
+		return c.scale, false
+	}
+	if c.cooldown > 0 {
+		c.cooldown--
+		return c.scale, false
+	}
+	c.samples = append(c.samples, frameMs)
+	if len(c.samples) > c.settings.Window {
+		c.samples = c.samples[len(c.samples)-c.settings.Window:]
+	}
+	if len(c.samples) < c.settings.Window {
+		return c.scale, false
+	}
+
+	s := c.settings
+	avg := c.AverageFrameMs()
+	next := c.scale
+	switch {
+	case avg > s.TargetFrameMs:
+		goal := s.TargetFrameMs * (1 + s.Headroom) * 0.5
+		predicted := c.scale * float32(math.Sqrt(float64(goal/avg)))
+		next = minf(c.quantizeDown(predicted), c.scale-s.Step)
+	case avg < s.TargetFrameMs*s.Headroom:
+		next = c.scale + s.Step
+	}
+	next = c.clamp(next)
+	if math.Abs(float64(next-c.scale)) < float64(s.Step)*0.5 {
+		return c.scale, false
+	}
+	c.scale = next
+	c.samples = c.samples[:0]
+	c.cooldown = s.Cooldown
+	return c.scale, true
+}
+
+// Reset clears the collected timings and sets the scale, clamped to the
+// bounds.
+func (c *DynamicResolutionController) Reset(scale float32) {
+	c.scale = c.clamp(scale)
+	c.samples = c.samples[:0]
+	c.cooldown = 0
+}
+
+func (c *DynamicResolutionController) quantizeDown(scale float32) float32 {
+	step := c.settings.Step
+	return float32(math.Floor(float64(scale/step)+1e-4)) * step
+}
+
+func (c *DynamicResolutionController) clamp(scale float32) float32 {
+	if scale >= c.settings.MaxScale-1e-4 {
+		return c.settings.MaxScale
+	}
+	if scale <= c.settings.MinScale+1e-4 {
+		return c.settings.MinScale
+	}
+	return scale
+}
+
+// scaledRenderExtent returns the internal render size for a surface size and
+// scale.
+func scaledRenderExtent(width, height uint32, scale float32) (uint32, uint32) {
+	if !(scale > 0) || scale == 1 {
+		return width, height
+	}
+	return max(1, uint32(float32(width)*scale+0.5)), max(1, uint32(float32(height)*scale+0.5))
+}
+
+// RenderScale returns the internal render scale per axis. The final
+// post-process pass upscales into the swapchain.
+func (a *App) RenderScale() float32 {
+	if a == nil || !(a.renderScale > 0) {
+		return 1
+	}
+	return a.renderScale
+}
+
+// SetRenderScale sets a fixed render scale. With dynamic resolution enabled
+// the controller restarts from it.
+func (a *App) SetRenderScale(scale float32) {
+	if a == nil || !(scale > 0) {
+		return
+	}
+	if a.resolutionController != nil {
+		a.resolutionController.Reset(scale)
+		scale = a.resolutionController.Scale()
+	}
+	a.applyRenderScale(scale)
+}
+
+// renderExtent is the size of the internal render targets.
+func (a *App) renderExtent() (uint32, uint32) {
+	if a == nil || a.Config == nil {
+		return 0, 0
+	}
+	return scaledRenderExtent(a.Config.Width, a.Config.Height, a.RenderScale())
+}
+
+func (a *App) applyRenderScale(scale float32) {
+	if scale == a.RenderScale() {
+		return
+	}
+	a.renderScale = scale
+	if a.Device != nil && a.Config != nil {
+		a.resizeRenderTargets()
+	}
+}
+
+// dynamicResolutionScopes are the profiler scopes whose cost scales with the
+// internal render size; their summed time drives the controller.
+var dynamicResolutionScopes = [...]string{"G-Buffer", "Lighting"}
+
+// scaledPassMs returns the time the last frame spent in the scaled passes,
+// or 0 when none ran. It must be read before the profiler is reset.
+func (a *App) scaledPassMs() float32 {
+	if a == nil || a.Profiler == nil {
+		return 0
+	}
+	var total time.Duration
+	for _, name := range dynamicResolutionScopes {
+		total += a.Profiler.ScopeTimes[name]
+	}
+	return float32(total.Seconds() * 1000)
+}
+
+// updateDynamicResolution feeds the last frame's scaled pass time to the
+// controller and resizes the internal targets when the scale changes.
+func (a *App) updateDynamicResolution(frameMs float32) {
+	if a == nil {
+		return
+	}
+	if !a.DynamicResolution.Enabled {
+		a.resolutionController = nil
+		return
+	}
+	settings := a.DynamicResolution.normalized()
+	if a.resolutionController == nil || a.resolutionController.Settings() != settings {
+		a.resolutionController = NewDynamicResolutionController(settings)
+		a.resolutionController.Reset(a.RenderScale())
+	}
+	scale := a.resolutionController.Scale()
+	if frameMs > 0 {
+		scale, _ = a.resolutionController.Observe(frameMs)
+	}
+	a.applyRenderScale(scale)
+	if a.Profiler != nil {
+		a.Profiler.SetCount("RenderScalePercent", int(a.RenderScale()*100+0.5))
+		a.Profiler.SetCount("ScaledPassUs", int(frameMs*1000))
+	}
+}
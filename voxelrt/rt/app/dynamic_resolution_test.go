@@ -0,0 +1,158 @@
+package app
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/cogentcore/webgpu/wgpu"
+)
+
+func feedDynamicResolution(c *DynamicResolutionController, ms float32, frames int) (changes int) {
+	for i := 0; i < frames; i++ {
+		if _, changed := c.Observe(ms); changed {
+			changes++
+		}
+	}
+	return changes
+}
+
+func approxScale(got, want float32) bool {
+	return math.Abs(float64(got-want)) < 1e-4
+}
+
+func TestDynamicResolutionSettingsNormalize(t *testing.T) {
+	got := (DynamicResolutionSettings{}).normalized()
+	if got != DefaultDynamicResolutionSettings() {
+		t.Fatalf("zero settings normalized to %+v", got)
+	}
+	got = (DynamicResolutionSettings{MinScale: 0.9, MaxScale: 0.6, Headroom: 1.5, Cooldown: -1}).normalized()
+	if got.MinScale != 0.6 || got.MaxScale != 0.6 || got.Headroom != 0.8 || got.Cooldown != 0 {
+		t.Fatalf("expected inverted bounds and invalid headroom to be repaired, got %+v", got)
+	}
+}
+
+func TestDynamicResolutionControllerDropsToPredictedScaleWhenOverBudget(t *testing.T) {
+	c := NewDynamicResolutionController(DynamicResolutionSettings{TargetFrameMs: 10, Window: 4, Cooldown: 3})
+	if c.Scale() != 1 {
+		t.Fatalf("expected controller to start at MaxScale, got %v", c.Scale())
+	}
+	if changes := feedDynamicResolution(c, 20, 3); changes != 0 {
+		t.Fatal("expected no decision before the window fills")
+	}
+	scale, changed := c.Observe(20)
+	// Goal 9ms from 20ms at full scale predicts sqrt(0.45) ~ 0.67, floored
+	// to the 0.05 grid.
+	if !changed || !approxScale(scale, 0.65) {
+		t.Fatalf("expected a drop to 0.65, got %v changed=%v", scale, changed)
+	}
+
+	if changes := feedDynamicResolution(c, 20, 3); changes != 0 {
+		t.Fatal("expected the cooldown to ignore timings right after a change")
+	}
+	if c.AverageFrameMs() != 0 {
+		t.Fatal("expected the window to be cleared by the change")
+	}
+	feedDynamicResolution(c, 40, 4)
+	if !approxScale(c.Scale(), 0.5) {
+		t.Fatalf("expected the scale to clamp at MinScale, got %v", c.Scale())
+	}
+}
+
+func TestDynamicResolutionControllerHoldsInsideBandAndRisesOneStep(t *testing.T) {
+	c := NewDynamicResolutionController(DynamicResolutionSettings{TargetFrameMs: 10, Headroom: 0.8, Window: 2, Cooldown: 1})
+	c.Reset(0.7)
+	if changes := feedDynamicResolution(c, 9, 20); changes != 0 || !approxScale(c.Scale(), 0.7) {
+		t.Fatalf("expected timings inside the hysteresis band to hold the scale, got %v after %d changes", c.Scale(), changes)
+	}
+
+	var scales []float32
+	for i := 0; i < 12; i++ {
+		if scale, changed := c.Observe(5); changed {
+			scales = append(scales, scale)
+		}
+	}
+	want := []float32{0.75, 0.8, 0.85, 0.9}
+	if len(scales) != len(want) {
+		t.Fatalf("rising scales = %v, want %v", scales, want)
+	}
+	for i := range want {
+		if !approxScale(scales[i], want[i]) {
+			t.Fatalf("rising scales = %v, want %v", scales, want)
+		}
+	}
+	feedDynamicResolution(c, 1, 40)
+	if c.Scale() != 1 {
+		t.Fatalf("expected the scale to stop at MaxScale, got %v", c.Scale())
+	}
+}
+
+func TestDynamicResolutionControllerIgnoresInvalidTimings(t *testing.T) {
+	c := NewDynamicResolutionController(DynamicResolutionSettings{TargetFrameMs: 10, Window: 1, Cooldown: 1})
+	for _, ms := range []float32{0, -3, float32(math.NaN()), float32(math.Inf(1))} {
+		if _, changed := c.Observe(ms); changed {
+			t.Fatalf("expected %v to be ignored", ms)
+		}
+	}
+	if c.AverageFrameMs() != 0 {
+		t.Fatal("expected no samples to be kept")
+	}
+}
+
+func TestScaledPassMsSumsGBufferAndLightingScopes(t *testing.T) {
+	app := NewApp(nil)
+	if got := app.scaledPassMs(); got != 0 {
+		t.Fatalf("expected no pass time before a frame, got %v", got)
+	}
+	app.Profiler.ScopeTimes["G-Buffer"] = 3 * time.Millisecond
+	app.Profiler.ScopeTimes["Lighting"] = 5 * time.Millisecond
+	app.Profiler.ScopeTimes["Post Bloom"] = 7 * time.Millisecond
+	if got := app.scaledPassMs(); !approxScale(got, 8) {
+		t.Fatalf("scaledPassMs = %v, want 8", got)
+	}
+}
+
+func TestAppRenderScaleDrivesInternalExtent(t *testing.T) {
+	app := NewApp(nil)
+	app.Config = &wgpu.SurfaceConfiguration{Width: 1920, Height: 1080}
+	if w, h := app.renderExtent(); w != 1920 || h != 1080 || app.RenderScale() != 1 {
+		t.Fatalf("expected full-size rendering by default, got %dx%d", w, h)
+	}
+
+	app.SetRenderScale(0.5)
+	frame := app.newFrameContext(nil)
+	if frame.Width != 960 || frame.Height != 540 || frame.WorkgroupsX != 120 || frame.WorkgroupsY != 68 {
+		t.Fatalf("unexpected scaled frame %+v", frame)
+	}
+	if got := app.mainViewAspect(); math.Abs(float64(got-1920.0/1080.0)) > 1e-5 {
+		t.Fatalf("expected the projection aspect to follow the surface, got %v", got)
+	}
+	for _, desc := range defaultRenderGraphResources() {
+		if desc.Name != RenderResourcePostProcessBloom {
+			continue
+		}
+		if w, h := desc.Extent(app.renderExtent()); w != 480 || h != 270 {
+			t.Fatalf("expected bloom at half the internal extent, got %dx%d", w, h)
+		}
+	}
+
+	app.DynamicResolution = DynamicResolutionSettings{Enabled: true, TargetFrameMs: 10, Window: 2, Cooldown: 1}
+	app.updateDynamicResolution(4)
+	app.updateDynamicResolution(4)
+	if !approxScale(app.RenderScale(), 0.55) {
+		t.Fatalf("expected fast frames to raise the scale one step, got %v", app.RenderScale())
+	}
+	if got := app.Profiler.Counts["RenderScalePercent"]; got != 55 {
+		t.Fatalf("RenderScalePercent = %d, want 55", got)
+	}
+
+	if got := app.Profiler.Counts["ScaledPassUs"]; got != 4000 {
+		t.Fatalf("ScaledPassUs = %d, want 4000", got)
+	}
+
+	app.DynamicResolution.Enabled = false
+	app.updateDynamicResolution(4)
+	if app.resolutionController != nil || !approxScale(app.RenderScale(), 0.55) {
+		t.Fatal("expected disabling to drop the controller and keep the current scale")
+	}
+}
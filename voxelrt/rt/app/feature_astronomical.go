@@ -148,6 +148,7 @@ func (a *App) recordAstronomicalPass(encoder *wgpu.CommandEncoder) error {
 	})
 	if a.BufferManager.AstronomicalBodyCount > 0 && a.BufferManager.AstronomicalBG0 != nil && a.BufferManager.AstronomicalBG1 != nil && a.BufferManager.AstronomicalBG2 != nil {
 		pass.SetPipeline(a.astronomicalPipeline())
+		a.setInternalRenderRectViewport(pass)
 		pass.SetBindGroup(0, a.BufferManager.AstronomicalBG0, nil)
 		pass.SetBindGroup(1, a.BufferManager.AstronomicalBG1, nil)
 		pass.SetBindGroup(2, a.BufferManager.AstronomicalBG2, nil)
@@ -242,7 +242,12 @@ func (a *App) recordCAVolumeRenderPass(encoder *wgpu.CommandEncoder) error {
 		a.SetHadCAVolumePass(false)
 		return nil
 	}
-	candidates := buildCAVolumeRenderCandidates(a.Camera, a.BufferManager.VolumetricWidth, a.BufferManager.VolumetricHeight, volumes)
+	// Scissors stay inside the active render rect of the volumetric target.
+	rectW, rectH := a.BufferManager.VolumetricWidth, a.BufferManager.VolumetricHeight
+	if viewport := a.renderRectViewport(rectW, rectH); !viewport.Empty() {
+		rectW, rectH = max(uint32(viewport.Width), 1), max(uint32(viewport.Height), 1)
+	}
+	candidates := buildCAVolumeRenderCandidates(a.renderCamera(), rectW, rectH, volumes)
 	if len(candidates) == 0 {
 		a.SetHadCAVolumePass(false)
 		return nil
@@ -181,6 +181,7 @@ func (a *App) recordPlanetBodiesPass(encoder *wgpu.CommandEncoder) error {
 	})
 	if a.BufferManager.PlanetBodyCount > 0 && a.BufferManager.PlanetBodyBG0 != nil && a.BufferManager.PlanetBodyBG1 != nil && a.BufferManager.PlanetBodyBG2 != nil {
 		pass.SetPipeline(a.planetBodyPipeline())
+		a.setInternalRenderRectViewport(pass)
 		pass.SetBindGroup(0, a.BufferManager.PlanetBodyBG0, nil)
 		pass.SetBindGroup(1, a.BufferManager.PlanetBodyBG1, nil)
 		pass.SetBindGroup(2, a.BufferManager.PlanetBodyBG2, nil)
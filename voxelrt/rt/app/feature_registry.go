@@ -122,6 +122,22 @@ func (a *App) setupRenderGraphNodes() error {
 	return a.RenderGraph.Setup(a)
 }
 
+// allocateRenderGraphResources (re)creates graph transients for the internal
+// render extent and rebinds the nodes that use them. Without a graph the post-process
+// chain binds its core targets directly.
+func (a *App) allocateRenderGraphResources(width, height uint32) {
+	if a == nil {
+		return
+	}
+	if a.RenderGraph == nil {
+		a.createPostProcessBindGroups()
+		return
+	}
+	if err := a.RenderGraph.AllocateResources(a, width, height); err != nil {
+		fmt.Printf("ERROR: Render graph resource allocation failed: %v\n", err)
+	}
+}
+
 func (a *App) resizeFeatures(width, height uint32) error {
 	if a == nil {
 		return nil
@@ -274,7 +290,7 @@ func (a *App) renderPassStageForRenderGraph(stage FeaturePassStage, pass *wgpu.R
 		return nil
 	}
 	for _, feature := range a.features {
-		if feature == nil || !feature.Enabled(a) {
+		if feature == nil || !feature.Enabled(a) || !a.renderViewAllowsFeature(feature.Name()) {
 			continue
 		}
 		if graphFeature, ok := feature.(FeatureGraphPassStageOwner); ok && featureUsesGraphPassStage(feature, stage) {
@@ -320,7 +336,7 @@ func (a *App) hasPassStageWorkForRenderGraph(stage FeaturePassStage) bool {
 		return false
 	}
 	for _, feature := range a.features {
-		if feature == nil || !feature.Enabled(a) {
+		if feature == nil || !feature.Enabled(a) || !a.renderViewAllowsFeature(feature.Name()) {
 			continue
 		}
 		if graphFeature, ok := feature.(FeatureGraphPassStageOwner); ok && featureUsesGraphPassStage(feature, stage) {
@@ -41,6 +41,12 @@ func DefaultFeatureResourceInventory() []FeatureResourceInventoryItem {
 			},
 			NextStep: "keep under core app or buffer manager",
 		},
+		{
+			FeatureName: "post-process",
+			Owner:       FeatureResourceOwnerCore,
+			AppFields:   []string{"PostProcessResources"},
+			NextStep:    "graph-owned bloom, composite, and FXAA nodes; the resolve pass writes into the HDR target, and bloom and LDR targets are render graph transients",
+		},
 		{
 			FeatureName: "text",
 			Owner:       FeatureResourceOwnerFeatureHooks,
@@ -28,6 +28,13 @@ type SpriteInstanceInput struct {
 	AtlasCols     uint32
 	AtlasRows     uint32
 	BillboardMode uint32
+
+	// BlendIndices and BlendWeights add two more atlas views for octahedral
+	// impostors. SpriteIndex takes the remaining weight.
+	BlendIndices [2]uint32
+	BlendWeights [2]float32
+	IsImpostor   uint32
+	_            [3]uint32
 }
 
 type SpriteBatchInput struct {
@@ -20,6 +20,9 @@ func TestSpriteInstanceBytesPacksRendererInput(t *testing.T) {
 			AtlasCols:     7,
 			AtlasRows:     8,
 			BillboardMode: 9,
+			BlendIndices:  [2]uint32{10, 11},
+			BlendWeights:  [2]float32{0.25, 0.5},
+			IsImpostor:    1,
 		},
 	}
 
@@ -31,6 +34,16 @@ func TestSpriteInstanceBytesPacksRendererInput(t *testing.T) {
 	if got, want := len(bytes), int(unsafe.Sizeof(SpriteInstanceInput{})); got != want {
 		t.Fatalf("sprite byte length = %d, want %d", got, want)
 	}
+	// sprites.wgsl SpriteInstance is 96 bytes with blend_indices at offset 64.
+	if got := len(bytes); got != 96 {
+		t.Fatalf("sprite stride = %d, want 96 to match sprites.wgsl", got)
+	}
+	if got := *(*uint32)(unsafe.Pointer(&bytes[64])); got != 10 {
+		t.Fatalf("blend index at offset 64 = %d, want 10", got)
+	}
+	if got := *(*uint32)(unsafe.Pointer(&bytes[80])); got != 1 {
+		t.Fatalf("impostor flag at offset 80 = %d, want 1", got)
+	}
 	emptyBytes, emptyCount := spriteInstanceBytes(nil)
 	if len(emptyBytes) != 0 || emptyCount != 0 {
 		t.Fatalf("expected empty sprite byte output, got bytes=%d count=%d", len(emptyBytes), emptyCount)
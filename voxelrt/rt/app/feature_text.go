@@ -3,6 +3,7 @@ package app
 import (
 	"fmt"
 	"os"
+	"slices"
 	"unsafe"
 
 	"github.com/cogentcore/webgpu/wgpu"
@@ -22,12 +23,31 @@ type TextOverlayItem struct {
 type TextResources struct {
 	Renderer     *core.TextRenderer
 	Pipeline     *wgpu.RenderPipeline
+	AtlasTexture *wgpu.Texture
 	AtlasView    *wgpu.TextureView
 	BindGroup    *wgpu.BindGroup
 	VertexBuffer *wgpu.Buffer
 	Items        []core.TextItem
 	RectItems    []core.RectItem
 	VertexCount  uint32
+	// ImagePipeline draws textured rects from sprite atlases; Batches split
+	// the vertex buffer wherever the texture changes.
+	ImagePipeline   *wgpu.RenderPipeline
+	Batches         []TextBatch
+	imageBindGroups map[string]textImageBindGroup
+}
+
+// TextBatch is a run of overlay vertices drawn with one texture. An empty
+// Texture uses the glyph atlas and the text pipeline.
+type TextBatch struct {
+	Texture string
+	First   uint32
+	Count   uint32
+}
+
+type textImageBindGroup struct {
+	view  *wgpu.TextureView
+	group *wgpu.BindGroup
 }
 
 func (f *TextFeature) Name() string {
@@ -62,6 +82,15 @@ func (f *TextFeature) Setup(a *App) error {
 		fmt.Printf("WARNING: Failed to initialize text renderer: %v\n", err)
 		return nil
 	}
+	fallbackPaths := a.FontFallbackPaths
+	if fallbackPaths == nil {
+		fallbackPaths = f.resolveDefaultFallbackFontPaths()
+	}
+	for _, path := range fallbackPaths {
+		if err := textRenderer.AddFallbackFont(path); err != nil {
+			fmt.Printf("WARNING: Failed to load fallback font: %v\n", err)
+		}
+	}
 	a.ensureTextResources().Renderer = textRenderer
 	a.setupTextResources()
 	return nil
@@ -104,10 +133,12 @@ func (f *TextFeature) Update(a *App) error {
 	}
 
 	vertices := textResources.Renderer.BuildVertices(textResources.Items, textResources.RectItems, int(a.Config.Width), int(a.Config.Height))
+	a.refreshTextAtlas()
 	if len(vertices) == 0 {
 		textResources.VertexCount = 0
 		return nil
 	}
+	textResources.Batches = textOverlayBatches(textResources.Batches[:0], textResources.RectItems, uint32(len(vertices)))
 	vSize := uint64(len(vertices) * int(unsafe.Sizeof(core.TextVertex{})))
 	if textResources.VertexBuffer == nil || textResources.VertexBuffer.GetSize() < vSize {
 		if textResources.VertexBuffer != nil {
@@ -178,7 +209,7 @@ func (a *App) renderTextOverlay(encoder *wgpu.CommandEncoder, target *wgpu.Textu
 		return nil
 	}
 	textResources := a.textResources()
-	if textResources == nil || len(textResources.Items) == 0 || textResources.VertexBuffer == nil || textResources.Pipeline == nil || textResources.BindGroup == nil {
+	if textResources == nil || textResources.VertexCount == 0 || textResources.VertexBuffer == nil || textResources.Pipeline == nil || textResources.BindGroup == nil {
 		return nil
 	}
 
@@ -189,10 +220,21 @@ func (a *App) renderTextOverlay(encoder *wgpu.CommandEncoder, target *wgpu.Textu
 			StoreOp: wgpu.StoreOpStore,
 		}},
 	})
-	pass.SetPipeline(textResources.Pipeline)
-	pass.SetBindGroup(0, textResources.BindGroup, nil)
 	pass.SetVertexBuffer(0, textResources.VertexBuffer, 0, textResources.VertexBuffer.GetSize())
-	pass.Draw(textResources.VertexCount, 1, 0, 0)
+	for _, batch := range textResources.Batches {
+		if batch.Texture == "" {
+			pass.SetPipeline(textResources.Pipeline)
+			pass.SetBindGroup(0, textResources.BindGroup, nil)
+		} else {
+			bindGroup := a.textImageBindGroup(batch.Texture)
+			if bindGroup == nil {
+				continue
+			}
+			pass.SetPipeline(textResources.ImagePipeline)
+			pass.SetBindGroup(0, bindGroup, nil)
+		}
+		pass.Draw(batch.Count, 1, batch.First, 0)
+	}
 	if err := pass.End(); err != nil {
 		return fmt.Errorf("text render pass end failed: %w", err)
 	}
@@ -254,6 +296,85 @@ func (a *App) AppendTextOverlayItems(items []TextOverlayItem) {
 	textResources.VertexCount = 0
 }
 
+// textOverlayBatches groups consecutive rects that share a texture. Text
+// follows the rects and always uses the glyph atlas.
+func textOverlayBatches(batches []TextBatch, rects []core.RectItem, vertexCount uint32) []TextBatch {
+	next := uint32(0)
+	for _, rect := range rects {
+		if next+6 > vertexCount {
+			break
+		}
+		if n := len(batches); n > 0 && batches[n-1].Texture == rect.Texture {
+			batches[n-1].Count += 6
+		} else {
+			batches = append(batches, TextBatch{Texture: rect.Texture, First: next, Count: 6})
+		}
+		next += 6
+	}
+	if next < vertexCount {
+		if n := len(batches); n > 0 && batches[n-1].Texture == "" {
+			batches[n-1].Count += vertexCount - next
+		} else {
+			batches = append(batches, TextBatch{First: next, Count: vertexCount - next})
+		}
+	}
+	return batches
+}
+
+// textImageBindGroup binds the sprite atlas named by key for the image
+// pipeline, or returns nil while the atlas is not uploaded.
+func (a *App) textImageBindGroup(key string) *wgpu.BindGroup {
+	textResources := a.textResources()
+	if textResources.ImagePipeline == nil || a.BufferManager == nil {
+		return nil
+	}
+	atlas, ok := a.BufferManager.SpriteAtlases[key]
+	if !ok || atlas == nil || atlas.View == nil {
+		return nil
+	}
+	if cached, ok := textResources.imageBindGroups[key]; ok {
+		if cached.view == atlas.View {
+			return cached.group
+		}
+		cached.group.Release()
+		delete(textResources.imageBindGroups, key)
+	}
+	group, err := a.Device.CreateBindGroup(&wgpu.BindGroupDescriptor{
+		Label:  "Text Image BindGroup",
+		Layout: textResources.ImagePipeline.GetBindGroupLayout(0),
+		Entries: []wgpu.BindGroupEntry{
+			{Binding: 0, TextureView: atlas.View},
+			{Binding: 1, Sampler: a.Sampler},
+		},
+	})
+	if err != nil {
+		fmt.Printf("ERROR: Failed to create text image bind group: %v\n", err)
+		return nil
+	}
+	if textResources.imageBindGroups == nil {
+		textResources.imageBindGroups = make(map[string]textImageBindGroup)
+	}
+	textResources.imageBindGroups[key] = textImageBindGroup{view: atlas.View, group: group}
+	return group
+}
+
+// OverlayImageTextures lists the sprite atlas keys drawn by this frame's
+// overlay images, so the caller can upload them before Update.
+func (a *App) OverlayImageTextures() []string {
+	textResources := a.textResources()
+	if textResources == nil {
+		return nil
+	}
+	var keys []string
+	for _, rect := range textResources.RectItems {
+		if rect.Texture == "" || slices.Contains(keys, rect.Texture) {
+			continue
+		}
+		keys = append(keys, rect.Texture)
+	}
+	return keys
+}
+
 func (a *App) textRenderer() *core.TextRenderer {
 	resources := a.textResources()
 	if resources == nil {
@@ -277,3 +398,38 @@ func (f *TextFeature) resolveDefaultFontPath() string {
 	}
 	return "Roboto-Medium.ttf"
 }
+
+// resolveDefaultFallbackFontPaths returns the common system fonts that
+// exist, so CJK, Arabic, Hebrew, Thai and Devanagari text draws without
+// configuration.
+func (f *TextFeature) resolveDefaultFallbackFontPaths() []string {
+	candidates := []string{
+		// Linux
+		"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/noto-cjk/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/google-noto-cjk/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/truetype/noto/NotoSansArabic-Regular.ttf",
+		"/usr/share/fonts/truetype/noto/NotoSansHebrew-Regular.ttf",
+		"/usr/share/fonts/truetype/noto/NotoSansThai-Regular.ttf",
+		"/usr/share/fonts/truetype/noto/NotoSansDevanagari-Regular.ttf",
+		"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+		// macOS
+		"/System/Library/Fonts/PingFang.ttc",
+		"/System/Library/Fonts/Hiragino Sans GB.ttc",
+		"/System/Library/Fonts/Supplemental/Arial Unicode.ttf",
+		// Windows
+		"C:/Windows/Fonts/msyh.ttc",
+		"C:/Windows/Fonts/YuGothM.ttc",
+		"C:/Windows/Fonts/malgun.ttf",
+		"C:/Windows/Fonts/arial.ttf",
+		"C:/Windows/Fonts/seguisym.ttf",
+	}
+
+	var paths []string
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err == nil {
+			paths = append(paths, c)
+		}
+	}
+	return paths
+}
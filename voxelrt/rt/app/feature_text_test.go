@@ -1,6 +1,11 @@
 package app
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gekko3d/gekko/voxelrt/rt/core"
+)
 
 func TestSetTextOverlayItemsCopiesItemsAndClearsVertexCount(t *testing.T) {
 	app := &App{}
@@ -79,3 +84,30 @@ func TestAppendTextOverlayItemsPreservesExistingImmediateText(t *testing.T) {
 		t.Fatalf("expected vertex count reset after append, got %d", app.TextResources.VertexCount)
 	}
 }
+
+func TestTextOverlayBatchesSplitRectsByTextureInOrder(t *testing.T) {
+	rects := []core.RectItem{
+		{W: 10, H: 10},
+		{W: 10, H: 10, Texture: "skin"},
+		{W: 10, H: 10, Texture: "skin"},
+		{W: 10, H: 10},
+		{W: 10, H: 10, Texture: "icon"},
+	}
+	// Six vertices of text follow the rects.
+	batches := textOverlayBatches(nil, rects, 36)
+	want := []TextBatch{
+		{First: 0, Count: 6},
+		{Texture: "skin", First: 6, Count: 12},
+		{First: 18, Count: 6},
+		{Texture: "icon", First: 24, Count: 6},
+		{First: 30, Count: 6},
+	}
+	if !reflect.DeepEqual(batches, want) {
+		t.Fatalf("unexpected batches %+v", batches)
+	}
+
+	batches = textOverlayBatches(batches[:0], rects[:1], 12)
+	if len(batches) != 1 || batches[0].Count != 12 {
+		t.Fatalf("expected solid rects and text to share a batch, got %+v", batches)
+	}
+}
@@ -129,7 +129,8 @@ func (f *WaterFeature) RenderPassStage(a *App, stage FeaturePassStage, pass *wgp
 	if a.Config == nil || a.Config.Width == 0 || a.Config.Height == 0 {
 		return nil
 	}
-	candidates := buildWaterRenderCandidates(a.Camera, a.Config.Width, a.Config.Height, a.BufferManager.WaterSurfaces)
+	renderWidth, renderHeight := a.renderExtent()
+	candidates := buildWaterRenderCandidates(a.Camera, renderWidth, renderHeight, a.BufferManager.WaterSurfaces)
 	if a.Profiler != nil {
 		a.Profiler.SetCount("WaterRenderCandidates", len(candidates))
 		a.Profiler.SetCount("WaterRipplesSource", int(a.BufferManager.WaterRippleSourceCount))
@@ -149,7 +150,7 @@ func (f *WaterFeature) RenderPassStage(a *App, stage FeaturePassStage, pass *wgp
 		pass.SetScissorRect(candidate.Scissor.X, candidate.Scissor.Y, candidate.Scissor.W, candidate.Scissor.H)
 		pass.Draw(3, 1, 0, uint32(candidate.WaterIndex))
 	}
-	pass.SetScissorRect(0, 0, a.Config.Width, a.Config.Height)
+	pass.SetScissorRect(0, 0, renderWidth, renderHeight)
 	return nil
 }
 
@@ -0,0 +1,478 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cogentcore/webgpu/wgpu"
+)
+
+// CaptureChannel selects the images written for a captured frame.
+type CaptureChannel uint32
+
+const (
+	// CaptureColor is the final image as presented, after post-processing.
+	CaptureColor CaptureChannel = 1 << iota
+	// CaptureAlbedo renders the frame again in the Albedo render mode.
+	CaptureAlbedo
+	// CaptureNormals reads the world-space G-buffer normals.
+	CaptureNormals
+	// CaptureDepth reads the G-buffer ray distance.
+	CaptureDepth
+
+	CaptureAllChannels = CaptureColor | CaptureAlbedo | CaptureNormals | CaptureDepth
+)
+
+// captureAlbedoRenderMode is the RenderMode the albedo channel renders with.
+const captureAlbedoRenderMode = 1
+
+func (c CaptureChannel) String() string {
+	switch c {
+	case CaptureColor:
+		return "color"
+	case CaptureAlbedo:
+		return "albedo"
+	case CaptureNormals:
+		return "normals"
+	case CaptureDepth:
+		return "depth"
+	}
+	var names []string
+	for _, channel := range []CaptureChannel{CaptureColor, CaptureAlbedo, CaptureNormals, CaptureDepth} {
+		if c&channel != 0 {
+			names = append(names, channel.String())
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+func (c CaptureChannel) fileSuffix() string {
+	if c == CaptureColor {
+		return ""
+	}
+	return "_" + c.String()
+}
+
+// CaptureFormat is the file format of the color and albedo channels.
+// Normals and depth are always written as EXR.
+type CaptureFormat uint8
+
+const (
+	CaptureFormatPNG CaptureFormat = iota
+	CaptureFormatEXR
+)
+
+// CaptureRequest describes a frame capture.
+type CaptureRequest struct {
+	// Path is the file prefix: a single frame writes Path.png, Path_depth.exr
+	// and Path.json; a burst inserts the frame number, as Path_0003.png.
+	// Empty uses capture_<frame> in the working directory.
+	Path     string
+	Channels CaptureChannel
+	Format   CaptureFormat
+	// Frames captures that many consecutive frames. Zero or one captures
+	// the next frame only.
+	Frames int
+	// HideOverlays leaves text, gizmos and screen-stage features out of the
+	// color channel.
+	HideOverlays bool
+	// OnComplete, when set, is called from the writer goroutine once a
+	// frame's files are written.
+	OnComplete func(CaptureResult)
+}
+
+// CaptureResult reports the files written for one captured frame.
+type CaptureResult struct {
+	Frame      uint64
+	BurstIndex int
+	Files      []string
+	Err        error
+}
+
+func (r CaptureRequest) normalized() CaptureRequest {
+	if r.Channels == 0 {
+		r.Channels = CaptureColor
+	}
+	if r.Frames < 1 {
+		r.Frames = 1
+	}
+	return r
+}
+
+// captureFileBase returns the file prefix of burst frame index.
+func captureFileBase(req CaptureRequest, frame uint64, index int) string {
+	path := req.Path
+	if path == "" {
+		path = fmt.Sprintf("capture_%06d", frame)
+	}
+	if req.Frames > 1 {
+		return fmt.Sprintf("%s_%04d", path, index)
+	}
+	return path
+}
+
+// RequestCapture queues a capture of the next rendered frames. Requests run
+// one after another; the files are written asynchronously.
+func (a *App) RequestCapture(req CaptureRequest) error {
+	if a == nil {
+		return fmt.Errorf("capture needs an app")
+	}
+	if req.Channels&^CaptureAllChannels != 0 {
+		return fmt.Errorf("unknown capture channels %#x", uint32(req.Channels&^CaptureAllChannels))
+	}
+	if req.Format != CaptureFormatPNG && req.Format != CaptureFormatEXR {
+		return fmt.Errorf("unknown capture format %d", req.Format)
+	}
+	if req.Frames < 0 {
+		return fmt.Errorf("capture frame count %d is negative", req.Frames)
+	}
+	a.captureRequests = append(a.captureRequests, req.normalized())
+	return nil
+}
+
+// CapturesPending reports whether captures are queued or still being read
+// back or written.
+func (a *App) CapturesPending() bool {
+	return a != nil && (len(a.captureRequests) > 0 || len(a.frameCaptures) > 0 || a.captureWritersActive.Load() > 0)
+}
+
+// frameCapture is one frame being captured.
+type frameCapture struct {
+	request CaptureRequest
+	index   int
+	base    string
+	meta    CaptureMetadata
+
+	targets   []*renderViewTarget
+	readbacks []*frameCaptureReadback
+}
+
+// frameCaptureReadback is one texture copied into a mappable buffer.
+type frameCaptureReadback struct {
+	channel       CaptureChannel
+	buffer        *wgpu.Buffer
+	width, height uint32
+	bytesPerRow   uint32
+	format        captureTexelFormat
+	state         atomic.Int32
+}
+
+const (
+	captureReadbackCopying int32 = iota
+	captureReadbackMapped
+	captureReadbackFailed
+)
+
+// nextFrameCapture pops the capture of the frame about to render, if any.
+func (a *App) nextFrameCapture() *frameCapture {
+	if a == nil || len(a.captureRequests) == 0 {
+		return nil
+	}
+	req := a.captureRequests[0]
+	capture := &frameCapture{request: req, index: a.captureBurstIndex}
+	capture.base = captureFileBase(req, a.RenderFrameIndex, capture.index)
+	a.captureBurstIndex++
+	if a.captureBurstIndex >= req.Frames {
+		a.captureRequests = a.captureRequests[1:]
+		a.captureBurstIndex = 0
+	}
+	return capture
+}
+
+// captureExtent is the size of the color and albedo images: the main view's
+// viewport, or the whole surface.
+func (a *App) captureExtent() (uint32, uint32) {
+	rect := a.PrimaryViewport.Pixels(a.Config.Width, a.Config.Height)
+	if rect.Empty() {
+		return a.Config.Width, a.Config.Height
+	}
+	return max(1, uint32(rect.Width)), max(1, uint32(rect.Height))
+}
+
+// captureColorNodes are the nodes that write the final target.
+func captureColorNodes(overlays bool) map[string]bool {
+	nodes := map[string]bool{
+		RenderNodePostProcessComposite: true,
+		RenderNodePostProcessAntiAlias: true,
+	}
+	if overlays {
+		nodes[RenderNodeFeatureTextOverlay] = true
+		nodes[RenderNodeFeatureGizmosOverlay] = true
+		nodes[RenderNodeFeaturePostResolve] = true
+	}
+	return nodes
+}
+
+// renderFrameCaptureAlbedo renders the main camera in the Albedo render mode
+// into an offscreen target and copies it out. It submits on its own before
+// the main frame, like the secondary views.
+func (a *App) renderFrameCaptureAlbedo(capture *frameCapture) {
+	if capture == nil || capture.request.Channels&CaptureAlbedo == 0 || a.Device == nil || a.BufferManager == nil {
+		return
+	}
+	width, height := a.captureExtent()
+	target, err := a.createRenderViewTarget("Capture Albedo", width, height)
+	if err != nil {
+		fmt.Printf("ERROR: Capture albedo target creation failed: %v\n", err)
+		return
+	}
+	capture.targets = append(capture.targets, target)
+	encoder, err := a.Device.CreateCommandEncoder(nil)
+	if err != nil {
+		fmt.Printf("ERROR: Capture albedo CreateCommandEncoder failed: %v\n", err)
+		return
+	}
+
+	view := RenderView{Camera: *a.Camera, Features: RenderViewDefaultFeatures}
+	frame := a.newFrameContext(target.view)
+	mode := a.RenderMode
+	a.RenderMode = captureAlbedoRenderMode
+	a.writeCameraUniforms(a.Camera, a.mainViewAspect())
+	a.RenderMode = mode
+	a.activeRenderView = &view
+	a.recordRenderGraph(encoder, frame)
+	a.activeRenderView = nil
+	a.copyCaptureTexture(encoder, capture, CaptureAlbedo, target.texture, a.Config.Format)
+
+	cmd, err := encoder.Finish(nil)
+	if err != nil {
+		fmt.Printf("ERROR: Capture albedo encoder Finish failed: %v\n", err)
+	} else {
+		a.Queue.Submit(cmd)
+	}
+	a.writeCameraUniforms(a.Camera, a.mainViewAspect())
+}
+
+// recordFrameCapture records the color and G-buffer copies of capture into
+// the main frame's encoder, after the render graph. The color channel
+// re-records the final passes into an offscreen target, since the swapchain
+// cannot be copied from.
+func (a *App) recordFrameCapture(encoder *wgpu.CommandEncoder, capture *frameCapture) {
+	if capture == nil || encoder == nil {
+		return
+	}
+	channels := capture.request.Channels
+	if channels&CaptureColor != 0 {
+		width, height := a.captureExtent()
+		target, err := a.createRenderViewTarget("Capture Color", width, height)
+		if err != nil {
+			fmt.Printf("ERROR: Capture color target creation failed: %v\n", err)
+		} else {
+			capture.targets = append(capture.targets, target)
+			frame := a.newFrameContext(target.view)
+			a.recordRenderGraphNodes(encoder, frame, captureColorNodes(!capture.request.HideOverlays))
+			a.copyCaptureTexture(encoder, capture, CaptureColor, target.texture, a.Config.Format)
+		}
+	}
+	if channels&CaptureNormals != 0 {
+		a.copyCaptureTexture(encoder, capture, CaptureNormals, a.BufferManager.GBufferNormal, wgpu.TextureFormatRGBA16Float)
+	}
+	if channels&CaptureDepth != 0 {
+		a.copyCaptureTexture(encoder, capture, CaptureDepth, a.BufferManager.GBufferDepth, wgpu.TextureFormatRGBA32Float)
+	}
+}
+
+// recordRenderGraphNodes records the enabled nodes in names, in graph order.
+func (a *App) recordRenderGraphNodes(encoder *wgpu.CommandEncoder, frame *FrameContext, names map[string]bool) {
+	if a.RenderGraph == nil {
+		for _, nodeName := range runtimeRenderGraphNodeSequence() {
+			if names[nodeName] {
+				a.runLegacyRenderGraphFeatureNode(nodeName, encoder, frame)
+			}
+		}
+		return
+	}
+	ordered, err := a.RenderGraph.Compile()
+	if err != nil {
+		fmt.Printf("ERROR: Render graph failed: %v\n", err)
+		return
+	}
+	for _, spec := range ordered {
+		if !names[spec.Name] || spec.Node == nil || !spec.Node.Enabled(a) {
+			continue
+		}
+		if err := spec.Node.Record(a, encoder, frame); err != nil {
+			fmt.Printf("ERROR: Capture render graph node %q failed: %v\n", spec.Name, err)
+		}
+	}
+}
+
+func (a *App) copyCaptureTexture(encoder *wgpu.CommandEncoder, capture *frameCapture, channel CaptureChannel, texture *wgpu.Texture, format wgpu.TextureFormat) {
+	if texture == nil {
+		return
+	}
+	texel, ok := captureTexelFormatFor(format)
+	if !ok {
+		fmt.Printf("ERROR: Capture %s: unsupported texture format %v\n", channel, format)
+		return
+	}
+	width, height := texture.GetWidth(), texture.GetHeight()
+	bytesPerRow := captureBytesPerRow(width, texel)
+	buffer, err := a.Device.CreateBuffer(&wgpu.BufferDescriptor{
+		Label: fmt.Sprintf("Capture Readback %s", channel),
+		Size:  uint64(bytesPerRow) * uint64(height),
+		Usage: wgpu.BufferUsageCopyDst | wgpu.BufferUsageMapRead,
+	})
+	if err != nil {
+		fmt.Printf("ERROR: Capture %s readback buffer creation failed: %v\n", channel, err)
+		return
+	}
+	encoder.CopyTextureToBuffer(
+		&wgpu.ImageCopyTexture{Texture: texture},
+		&wgpu.ImageCopyBuffer{
+			Buffer: buffer,
+			Layout: wgpu.TextureDataLayout{BytesPerRow: bytesPerRow, RowsPerImage: height},
+		},
+		&wgpu.Extent3D{Width: width, Height: height, DepthOrArrayLayers: 1},
+	)
+	capture.readbacks = append(capture.readbacks, &frameCaptureReadback{
+		channel:     channel,
+		buffer:      buffer,
+		width:       width,
+		height:      height,
+		bytesPerRow: bytesPerRow,
+		format:      texel,
+	})
+}
+
+// submitFrameCapture starts mapping the readbacks of a submitted capture and
+// snapshots the frame's metadata.
+func (a *App) submitFrameCapture(capture *frameCapture) {
+	if capture == nil {
+		return
+	}
+	width, height := a.captureExtent()
+	aspect := a.mainViewAspect()
+	capture.meta = CaptureMetadata{
+		Frame:      a.RenderFrameIndex,
+		Time:       time.Now(),
+		BurstIndex: capture.index,
+		BurstCount: capture.request.Frames,
+		Width:      width,
+		Height:     height,
+		Camera: CaptureCamera{
+			Position:   a.Camera.Position,
+			LookAt:     a.Camera.LookAt,
+			Up:         a.Camera.Up,
+			Fov:        a.Camera.Fov,
+			Near:       a.Camera.Near,
+			Far:        a.Camera.Far,
+			Aspect:     aspect,
+			View:       a.Camera.GetViewMatrix(),
+			Projection: a.Camera.ProjectionMatrix(aspect),
+		},
+		RenderMode:      a.RenderMode,
+		RenderModeName:  renderModeLabel(a.RenderMode),
+		LightingQuality: a.EffectiveLightingQuality(),
+		Profiler:        captureProfilerStats(a.Profiler, a.FPS),
+	}
+	for _, readback := range capture.readbacks {
+		readback := readback
+		readback.buffer.MapAsync(wgpu.MapModeRead, 0, readback.buffer.GetSize(), func(status wgpu.BufferMapAsyncStatus) {
+			if status == wgpu.BufferMapAsyncStatusSuccess {
+				readback.state.Store(captureReadbackMapped)
+			} else {
+				readback.state.Store(captureReadbackFailed)
+			}
+		})
+	}
+	a.frameCaptures = append(a.frameCaptures, capture)
+}
+
+// collectFrameCaptures hands every fully mapped capture to a writer
+// goroutine. With wait set it blocks until the GPU has finished.
+func (a *App) collectFrameCaptures(wait bool) {
+	if a == nil || len(a.frameCaptures) == 0 {
+		return
+	}
+	a.Device.Poll(wait, nil)
+	remaining := a.frameCaptures[:0]
+	for _, capture := range a.frameCaptures {
+		if !capture.mapped() {
+			remaining = append(remaining, capture)
+			continue
+		}
+		images, err := capture.decode()
+		capture.release()
+		a.captureWriters.Add(1)
+		a.captureWritersActive.Add(1)
+		go func(capture *frameCapture, images []captureImage, err error) {
+			defer a.captureWriters.Done()
+			defer a.captureWritersActive.Add(-1)
+			result := writeFrameCapture(capture.base, capture.request.Format, images, capture.meta)
+			if err != nil && result.Err == nil {
+				result.Err = err
+			}
+			if result.Err != nil {
+				fmt.Printf("ERROR: Frame capture %s: %v\n", capture.base, result.Err)
+			}
+			if capture.request.OnComplete != nil {
+				capture.request.OnComplete(result)
+			}
+		}(capture, images, err)
+	}
+	clear(a.frameCaptures[len(remaining):])
+	a.frameCaptures = remaining
+}
+
+// flushFrameCaptures finishes every submitted capture and waits for the
+// writers. Queued requests that never rendered are dropped.
+func (a *App) flushFrameCaptures() {
+	if a == nil {
+		return
+	}
+	a.captureRequests = nil
+	a.captureBurstIndex = 0
+	for len(a.frameCaptures) > 0 && a.Device != nil {
+		a.collectFrameCaptures(true)
+	}
+	a.captureWriters.Wait()
+}
+
+func (c *frameCapture) mapped() bool {
+	for _, readback := range c.readbacks {
+		if readback.state.Load() == captureReadbackCopying {
+			return false
+		}
+	}
+	return true
+}
+
+// decode copies the mapped readbacks into CPU images.
+func (c *frameCapture) decode() ([]captureImage, error) {
+	images := make([]captureImage, 0, len(c.readbacks))
+	var firstErr error
+	for _, readback := range c.readbacks {
+		if readback.state.Load() != captureReadbackMapped {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("capture %s readback failed to map", readback.channel)
+			}
+			continue
+		}
+		size := readback.buffer.GetSize()
+		data := readback.buffer.GetMappedRange(0, uint(size))
+		img, err := decodeCaptureImage(readback.channel, data, readback.width, readback.height, readback.bytesPerRow, readback.format)
+		readback.buffer.Unmap()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		images = append(images, img)
+	}
+	return images, firstErr
+}
+
+func (c *frameCapture) release() {
+	for _, readback := range c.readbacks {
+		readback.buffer.Release()
+	}
+	c.readbacks = nil
+	for _, target := range c.targets {
+		target.release()
+	}
+	c.targets = nil
+}
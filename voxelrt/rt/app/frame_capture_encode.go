@@ -0,0 +1,425 @@
+package app
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gekko3d/gekko/voxelrt/rt/core"
+
+	"github.com/cogentcore/webgpu/wgpu"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// CaptureMetadata is the JSON sidecar written next to every captured frame.
+type CaptureMetadata struct {
+	Frame           uint64                     `json:"frame"`
+	Time            time.Time                  `json:"time"`
+	BurstIndex      int                        `json:"burst_index"`
+	BurstCount      int                        `json:"burst_count"`
+	Width           uint32                     `json:"width"`
+	Height          uint32                     `json:"height"`
+	Camera          CaptureCamera              `json:"camera"`
+	RenderMode      uint32                     `json:"render_mode"`
+	RenderModeName  string                     `json:"render_mode_name"`
+	LightingQuality core.LightingQualityConfig `json:"lighting_quality"`
+	Profiler        CaptureProfilerStats       `json:"profiler"`
+	Files           []string                   `json:"files"`
+}
+
+// CaptureCamera is the main camera transform at capture time. View and
+// Projection are column-major.
+type CaptureCamera struct {
+	Position   mgl32.Vec3 `json:"position"`
+	LookAt     mgl32.Vec3 `json:"look_at"`
+	Up         mgl32.Vec3 `json:"up"`
+	Fov        float32    `json:"fov"`
+	Near       float32    `json:"near"`
+	Far        float32    `json:"far"`
+	Aspect     float32    `json:"aspect"`
+	View       mgl32.Mat4 `json:"view"`
+	Projection mgl32.Mat4 `json:"projection"`
+}
+
+// CaptureProfilerStats is a copy of the profiler counters and CPU scope
+// timings of the captured frame.
+type CaptureProfilerStats struct {
+	FPS      float64            `json:"fps"`
+	Counts   map[string]int     `json:"counts"`
+	ScopesMs map[string]float64 `json:"scopes_ms"`
+}
+
+func captureProfilerStats(p *core.Profiler, fps float64) CaptureProfilerStats {
+	stats := CaptureProfilerStats{FPS: fps, Counts: map[string]int{}, ScopesMs: map[string]float64{}}
+	if p == nil {
+		return stats
+	}
+	for name, count := range p.Counts {
+		stats.Counts[name] = count
+	}
+	for name, d := range p.ScopeTimes {
+		stats.ScopesMs[name] = d.Seconds() * 1000.0
+	}
+	return stats
+}
+
+// captureTexelFormat is the layout of a texture read back for capture.
+type captureTexelFormat uint8
+
+const (
+	captureTexelRGBA8 captureTexelFormat = iota
+	captureTexelBGRA8
+	captureTexelRGBA16Float
+	captureTexelRGBA32Float
+)
+
+func captureTexelFormatFor(format wgpu.TextureFormat) (captureTexelFormat, bool) {
+	switch format {
+	case wgpu.TextureFormatRGBA8Unorm, wgpu.TextureFormatRGBA8UnormSrgb:
+		return captureTexelRGBA8, true
+	case wgpu.TextureFormatBGRA8Unorm, wgpu.TextureFormatBGRA8UnormSrgb:
+		return captureTexelBGRA8, true
+	case wgpu.TextureFormatRGBA16Float:
+		return captureTexelRGBA16Float, true
+	case wgpu.TextureFormatRGBA32Float:
+		return captureTexelRGBA32Float, true
+	default:
+		return 0, false
+	}
+}
+
+func (f captureTexelFormat) bytesPerPixel() uint32 {
+	switch f {
+	case captureTexelRGBA16Float:
+		return 8
+	case captureTexelRGBA32Float:
+		return 16
+	default:
+		return 4
+	}
+}
+
+// captureBytesPerRow pads a row to the 256-byte copy alignment.
+func captureBytesPerRow(width uint32, format captureTexelFormat) uint32 {
+	return (width*format.bytesPerPixel() + 255) &^ 255
+}
+
+// captureImage is one decoded capture channel: display channels keep 8-bit
+// pixels, data channels keep floats.
+type captureImage struct {
+	channel CaptureChannel
+	rgba    *image.NRGBA
+	float   *captureFloatImage
+}
+
+// captureFloatImage holds interleaved float pixels written as EXR.
+type captureFloatImage struct {
+	width, height int
+	names         []string
+	pix           []float32
+	half          bool
+}
+
+// decodeCaptureImage unpacks a padded readback buffer for channel.
+func decodeCaptureImage(channel CaptureChannel, data []byte, width, height, bytesPerRow uint32, format captureTexelFormat) (captureImage, error) {
+	if uint64(len(data)) < uint64(bytesPerRow)*uint64(height) {
+		return captureImage{}, fmt.Errorf("capture readback for %s is %d bytes, want %d", channel, len(data), bytesPerRow*height)
+	}
+	w, h := int(width), int(height)
+	switch format {
+	case captureTexelRGBA8, captureTexelBGRA8:
+		img := image.NewNRGBA(image.Rect(0, 0, w, h))
+		for y := 0; y < h; y++ {
+			row := data[y*int(bytesPerRow):]
+			for x := 0; x < w; x++ {
+				src := row[x*4 : x*4+4]
+				dst := img.Pix[y*img.Stride+x*4:]
+				if format == captureTexelBGRA8 {
+					dst[0], dst[1], dst[2] = src[2], src[1], src[0]
+				} else {
+					dst[0], dst[1], dst[2] = src[0], src[1], src[2]
+				}
+				// The swapchain alpha is not meaningful on screen.
+				dst[3] = 0xff
+			}
+		}
+		return captureImage{channel: channel, rgba: img}, nil
+	case captureTexelRGBA16Float:
+		img := &captureFloatImage{width: w, height: h, names: []string{"R", "G", "B"}, pix: make([]float32, w*h*3), half: true}
+		for y := 0; y < h; y++ {
+			row := data[y*int(bytesPerRow):]
+			for x := 0; x < w; x++ {
+				for c := 0; c < 3; c++ {
+					img.pix[(y*w+x)*3+c] = halfToFloat32(binary.LittleEndian.Uint16(row[x*8+c*2:]))
+				}
+			}
+		}
+		return captureImage{channel: channel, float: img}, nil
+	case captureTexelRGBA32Float:
+		// Only the first component is kept: G-buffer depth stores the ray
+		// distance in x.
+		img := &captureFloatImage{width: w, height: h, names: []string{"Z"}, pix: make([]float32, w*h)}
+		for y := 0; y < h; y++ {
+			row := data[y*int(bytesPerRow):]
+			for x := 0; x < w; x++ {
+				img.pix[y*w+x] = math.Float32frombits(binary.LittleEndian.Uint32(row[x*16:]))
+			}
+		}
+		return captureImage{channel: channel, float: img}, nil
+	default:
+		return captureImage{}, fmt.Errorf("unsupported capture texel format %d", format)
+	}
+}
+
+// linearFloatImage converts display pixels to linear half-float RGB for EXR.
+func linearFloatImage(img *image.NRGBA) *captureFloatImage {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	out := &captureFloatImage{width: w, height: h, names: []string{"R", "G", "B"}, pix: make([]float32, w*h*3), half: true}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			src := img.Pix[y*img.Stride+x*4:]
+			for c := 0; c < 3; c++ {
+				out.pix[(y*w+x)*3+c] = srgbToLinear(float32(src[c]) / 255.0)
+			}
+		}
+	}
+	return out
+}
+
+func srgbToLinear(v float32) float32 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return float32(math.Pow(float64((v+0.055)/1.055), 2.4))
+}
+
+// writeCaptureImage writes img next to base and returns the file name.
+func writeCaptureImage(base string, img captureImage, format CaptureFormat) (string, error) {
+	path := base + img.channel.fileSuffix()
+	if img.rgba != nil && format == CaptureFormatPNG {
+		path += ".png"
+		return path, writeCaptureFile(path, func(w io.Writer) error {
+			return png.Encode(w, img.rgba)
+		})
+	}
+	floatImg := img.float
+	if floatImg == nil {
+		if img.rgba == nil {
+			return "", fmt.Errorf("capture %s has no pixels", img.channel)
+		}
+		floatImg = linearFloatImage(img.rgba)
+	}
+	path += ".exr"
+	return path, writeCaptureFile(path, func(w io.Writer) error {
+		return encodeCaptureEXR(w, floatImg)
+	})
+}
+
+func writeCaptureFile(path string, encode func(io.Writer) error) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	buf := bufio.NewWriter(file)
+	if err := encode(buf); err != nil {
+		file.Close()
+		return err
+	}
+	if err := buf.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeCaptureSidecar writes meta as base.json.
+func writeCaptureSidecar(base string, meta CaptureMetadata) (string, error) {
+	path := base + ".json"
+	return path, writeCaptureFile(path, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(meta)
+	})
+}
+
+// encodeCaptureEXR writes img as a single-part, uncompressed scanline
+// OpenEXR file.
+func encodeCaptureEXR(w io.Writer, img *captureFloatImage) error {
+	channels := len(img.names)
+	if img.width <= 0 || img.height <= 0 || channels == 0 || len(img.pix) != img.width*img.height*channels {
+		return fmt.Errorf("invalid EXR image %dx%d with %d channels and %d values", img.width, img.height, channels, len(img.pix))
+	}
+	// EXR stores channels sorted by name.
+	order := make([]int, channels)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return img.names[order[i]] < img.names[order[j]] })
+
+	pixelType, sampleSize := int32(2), 4
+	if img.half {
+		pixelType, sampleSize = 1, 2
+	}
+
+	var header []byte
+	attr := func(name, typ string, value []byte) {
+		header = append(header, name...)
+		header = append(header, 0)
+		header = append(header, typ...)
+		header = append(header, 0)
+		header = binary.LittleEndian.AppendUint32(header, uint32(len(value)))
+		header = append(header, value...)
+	}
+	var chlist []byte
+	for _, c := range order {
+		chlist = append(chlist, img.names[c]...)
+		chlist = append(chlist, 0)
+		chlist = binary.LittleEndian.AppendUint32(chlist, uint32(pixelType))
+		chlist = append(chlist, 0, 0, 0, 0) // pLinear and reserved
+		chlist = binary.LittleEndian.AppendUint32(chlist, 1)
+		chlist = binary.LittleEndian.AppendUint32(chlist, 1)
+	}
+	chlist = append(chlist, 0)
+	box := binary.LittleEndian.AppendUint32(nil, 0)
+	box = binary.LittleEndian.AppendUint32(box, 0)
+	box = binary.LittleEndian.AppendUint32(box, uint32(img.width-1))
+	box = binary.LittleEndian.AppendUint32(box, uint32(img.height-1))
+	one := binary.LittleEndian.AppendUint32(nil, math.Float32bits(1))
+
+	attr("channels", "chlist", chlist)
+	attr("compression", "compression", []byte{0})
+	attr("dataWindow", "box2i", box)
+	attr("displayWindow", "box2i", box)
+	attr("lineOrder", "lineOrder", []byte{0})
+	attr("pixelAspectRatio", "float", one)
+	attr("screenWindowCenter", "v2f", make([]byte, 8))
+	attr("screenWindowWidth", "float", one)
+	header = append(header, 0)
+
+	prefix := []byte{0x76, 0x2f, 0x31, 0x01, 2, 0, 0, 0}
+	lineBytes := img.width * channels * sampleSize
+	blockSize := 8 + lineBytes
+	offset := uint64(len(prefix)+len(header)) + uint64(img.height)*8
+
+	out := make([]byte, 0, int(offset)+img.height*blockSize)
+	out = append(out, prefix...)
+	out = append(out, header...)
+	for y := 0; y < img.height; y++ {
+		out = binary.LittleEndian.AppendUint64(out, offset+uint64(y*blockSize))
+	}
+	for y := 0; y < img.height; y++ {
+		out = binary.LittleEndian.AppendUint32(out, uint32(y))
+		out = binary.LittleEndian.AppendUint32(out, uint32(lineBytes))
+		for _, c := range order {
+			for x := 0; x < img.width; x++ {
+				v := img.pix[(y*img.width+x)*channels+c]
+				if img.half {
+					out = binary.LittleEndian.AppendUint16(out, float32ToHalf(v))
+				} else {
+					out = binary.LittleEndian.AppendUint32(out, math.Float32bits(v))
+				}
+			}
+		}
+	}
+	_, err := w.Write(out)
+	return err
+}
+
+func float32ToHalf(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	biased := int32(bits>>23) & 0xff
+	mant := bits & 0x7fffff
+	if biased == 0xff {
+		if mant != 0 {
+			return sign | 0x7e00
+		}
+		return sign | 0x7c00
+	}
+	exp := biased - 127 + 15
+	switch {
+	case exp >= 0x1f:
+		return sign | 0x7c00
+	case exp <= 0:
+		if exp < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint32(14 - exp)
+		half := uint16(mant >> shift)
+		if mant>>(shift-1)&1 != 0 {
+			half++
+		}
+		return sign | half
+	}
+	half := sign | uint16(exp)<<10 | uint16(mant>>13)
+	if mant&0x1000 != 0 {
+		half++
+	}
+	return half
+}
+
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+	switch exp {
+	case 0:
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+		exp = 127 - 15 + 1
+		for mant&0x400 == 0 {
+			mant <<= 1
+			exp--
+		}
+		return math.Float32frombits(sign | exp<<23 | (mant&0x3ff)<<13)
+	case 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+	}
+}
+
+// writeFrameCapture encodes every channel of one captured frame and its
+// sidecar. Channels that fail are reported but do not stop the others.
+func writeFrameCapture(base string, format CaptureFormat, images []captureImage, meta CaptureMetadata) CaptureResult {
+	result := CaptureResult{Frame: meta.Frame, BurstIndex: meta.BurstIndex}
+	for _, img := range images {
+		path, err := writeCaptureImage(base, img, format)
+		if err != nil {
+			if result.Err == nil {
+				result.Err = fmt.Errorf("capture %s: %w", img.channel, err)
+			}
+			continue
+		}
+		result.Files = append(result.Files, path)
+	}
+	meta.Files = make([]string, len(result.Files))
+	for i, path := range result.Files {
+		meta.Files[i] = filepath.Base(path)
+	}
+	path, err := writeCaptureSidecar(base, meta)
+	if err != nil {
+		if result.Err == nil {
+			result.Err = fmt.Errorf("capture sidecar: %w", err)
+		}
+	} else {
+		result.Files = append(result.Files, path)
+	}
+	return result
+}
@@ -0,0 +1,199 @@
+package app
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRequestCaptureQueuesBurstFrames(t *testing.T) {
+	app := NewApp(nil)
+	if err := app.RequestCapture(CaptureRequest{Channels: 1 << 8}); err == nil {
+		t.Fatal("expected unknown channels to be rejected")
+	}
+	if err := app.RequestCapture(CaptureRequest{Frames: -1}); err == nil {
+		t.Fatal("expected a negative burst to be rejected")
+	}
+	if err := app.RequestCapture(CaptureRequest{Path: "shots/run", Frames: 3, Channels: CaptureColor | CaptureDepth}); err != nil {
+		t.Fatalf("RequestCapture returned error: %v", err)
+	}
+	if err := app.RequestCapture(CaptureRequest{Path: "shots/single"}); err != nil {
+		t.Fatalf("RequestCapture returned error: %v", err)
+	}
+	if !app.CapturesPending() {
+		t.Fatal("expected queued captures to be pending")
+	}
+
+	var bases []string
+	for capture := app.nextFrameCapture(); capture != nil; capture = app.nextFrameCapture() {
+		bases = append(bases, capture.base)
+		app.RenderFrameIndex++
+	}
+	want := []string{"shots/run_0000", "shots/run_0001", "shots/run_0002", "shots/single"}
+	if !sameStrings(bases, want) {
+		t.Fatalf("captured bases %v, want %v", bases, want)
+	}
+	if app.CapturesPending() {
+		t.Fatal("expected the queue to drain")
+	}
+
+	app.RenderFrameIndex = 42
+	if err := app.RequestCapture(CaptureRequest{}); err != nil {
+		t.Fatalf("RequestCapture returned error: %v", err)
+	}
+	capture := app.nextFrameCapture()
+	if capture.base != "capture_000042" || capture.request.Channels != CaptureColor {
+		t.Fatalf("expected a default color capture named after the frame, got %q %v", capture.base, capture.request.Channels)
+	}
+}
+
+func TestRecordRenderGraphNodesRecordsOnlyFinalPasses(t *testing.T) {
+	var calls []string
+	graph := NewRenderGraph()
+	for _, name := range []string{RenderNodeCoreLighting, RenderNodePostProcessComposite, RenderNodePostProcessAntiAlias, RenderNodeFeatureTextOverlay} {
+		graph.Register(RenderNodeSpec{Name: name, Node: &testRenderNode{name: name, enabled: name != RenderNodePostProcessAntiAlias, calls: &calls}})
+	}
+	app := NewApp(nil)
+	app.RenderGraph = graph
+
+	app.recordRenderGraphNodes(nil, &FrameContext{}, captureColorNodes(true))
+	if !sameStrings(calls, []string{RenderNodePostProcessComposite, RenderNodeFeatureTextOverlay}) {
+		t.Fatalf("capture with overlays recorded %v", calls)
+	}
+	calls = calls[:0]
+	app.recordRenderGraphNodes(nil, &FrameContext{}, captureColorNodes(false))
+	if !sameStrings(calls, []string{RenderNodePostProcessComposite}) {
+		t.Fatalf("capture without overlays recorded %v", calls)
+	}
+}
+
+func TestDecodeCaptureImageUnpadsRowsAndSwizzlesBGRA(t *testing.T) {
+	const bytesPerRow = 256
+	data := make([]byte, bytesPerRow*2)
+	copy(data[0:], []byte{10, 20, 30, 0, 40, 50, 60, 0})
+	copy(data[bytesPerRow:], []byte{70, 80, 90, 0, 100, 110, 120, 0})
+
+	img, err := decodeCaptureImage(CaptureColor, data, 2, 2, bytesPerRow, captureTexelBGRA8)
+	if err != nil {
+		t.Fatalf("decode returned error: %v", err)
+	}
+	if got := img.rgba.NRGBAAt(1, 1); got.R != 120 || got.G != 110 || got.B != 100 || got.A != 255 {
+		t.Fatalf("pixel (1,1) = %+v, want swizzled opaque 120,110,100", got)
+	}
+
+	depth := make([]byte, bytesPerRow)
+	binary.LittleEndian.PutUint32(depth[16:], math.Float32bits(12.5))
+	img, err = decodeCaptureImage(CaptureDepth, depth, 2, 1, bytesPerRow, captureTexelRGBA32Float)
+	if err != nil {
+		t.Fatalf("decode depth returned error: %v", err)
+	}
+	if img.float == nil || !sameStrings(img.float.names, []string{"Z"}) || img.float.pix[1] != 12.5 {
+		t.Fatalf("unexpected depth image %+v", img.float)
+	}
+	if _, err := decodeCaptureImage(CaptureColor, data[:10], 2, 2, bytesPerRow, captureTexelRGBA8); err == nil {
+		t.Fatal("expected a short readback to be rejected")
+	}
+}
+
+func TestHalfFloatConversionRoundTrips(t *testing.T) {
+	for _, v := range []float32{0, 1, -2.5, 0.333251953125, 65504, 6.1035156e-05, 5.9604645e-08} {
+		if got := halfToFloat32(float32ToHalf(v)); got != v {
+			t.Fatalf("half round trip of %v = %v", v, got)
+		}
+	}
+	if float32ToHalf(1e6) != 0x7c00 || float32ToHalf(1e-9) != 0 {
+		t.Fatal("expected overflow to infinity and underflow to zero")
+	}
+}
+
+func TestEncodeCaptureEXRWritesSortedChannelsAndOffsets(t *testing.T) {
+	img := &captureFloatImage{width: 2, height: 1, names: []string{"R", "G", "B"}, pix: []float32{1, 2, 3, 4, 5, 6}, half: true}
+	var buf bytes.Buffer
+	if err := encodeCaptureEXR(&buf, img); err != nil {
+		t.Fatalf("encode returned error: %v", err)
+	}
+	out := buf.Bytes()
+	if !bytes.Equal(out[:8], []byte{0x76, 0x2f, 0x31, 0x01, 2, 0, 0, 0}) {
+		t.Fatalf("unexpected EXR magic/version %x", out[:8])
+	}
+	chlist := bytes.Index(out, []byte("chlist\x00"))
+	if chlist < 0 {
+		t.Fatal("missing channel list")
+	}
+	names := out[chlist+len("chlist\x00")+4:]
+	if names[0] != 'B' || names[18] != 'G' || names[36] != 'R' {
+		t.Fatal("expected channels sorted as B, G, R")
+	}
+	headerEnd := bytes.Index(out, []byte("screenWindowWidth\x00float\x00")) + len("screenWindowWidth\x00float\x00") + 4 + 4 + 1
+	offset := binary.LittleEndian.Uint64(out[headerEnd:])
+	if offset != uint64(headerEnd+8) {
+		t.Fatalf("scanline offset = %d, want %d", offset, headerEnd+8)
+	}
+	block := out[offset:]
+	if y, size := binary.LittleEndian.Uint32(block), binary.LittleEndian.Uint32(block[4:]); y != 0 || size != 12 {
+		t.Fatalf("scanline header y=%d size=%d, want 0 and 12", y, size)
+	}
+	if got := halfToFloat32(binary.LittleEndian.Uint16(block[8:])); got != 3 {
+		t.Fatalf("first stored sample = %v, want blue of the first pixel", got)
+	}
+	if len(out) != int(offset)+8+12 {
+		t.Fatalf("file is %d bytes, want %d", len(out), int(offset)+8+12)
+	}
+}
+
+func TestWriteFrameCaptureWritesImagesAndSidecar(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "nested", "shot")
+	color := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	color.Pix[0], color.Pix[3] = 200, 255
+	depth := &captureFloatImage{width: 3, height: 2, names: []string{"Z"}, pix: make([]float32, 6)}
+	meta := CaptureMetadata{Frame: 7, BurstIndex: 1, BurstCount: 2, RenderMode: 1, RenderModeName: renderModeLabel(1)}
+
+	result := writeFrameCapture(base, CaptureFormatPNG, []captureImage{
+		{channel: CaptureColor, rgba: color},
+		{channel: CaptureDepth, float: depth},
+	}, meta)
+	if result.Err != nil {
+		t.Fatalf("write returned error: %v", result.Err)
+	}
+	want := []string{base + ".png", base + "_depth.exr", base + ".json"}
+	if !sameStrings(result.Files, want) || result.Frame != 7 || result.BurstIndex != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	file, err := os.Open(base + ".png")
+	if err != nil {
+		t.Fatalf("open png: %v", err)
+	}
+	defer file.Close()
+	decoded, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	if r, _, _, _ := decoded.At(0, 0).RGBA(); r>>8 != 200 {
+		t.Fatalf("png pixel red = %d, want 200", r>>8)
+	}
+
+	raw, err := os.ReadFile(base + ".json")
+	if err != nil {
+		t.Fatalf("read sidecar: %v", err)
+	}
+	var sidecar CaptureMetadata
+	if err := json.Unmarshal(raw, &sidecar); err != nil {
+		t.Fatalf("parse sidecar: %v", err)
+	}
+	if sidecar.Frame != 7 || sidecar.RenderModeName != "Albedo" || !sameStrings(sidecar.Files, []string{"shot.png", "shot_depth.exr"}) {
+		t.Fatalf("unexpected sidecar %+v", sidecar)
+	}
+
+	exr := writeFrameCapture(filepath.Join(dir, "linear"), CaptureFormatEXR, []captureImage{{channel: CaptureAlbedo, rgba: color}}, meta)
+	if exr.Err != nil || len(exr.Files) != 2 || filepath.Ext(exr.Files[0]) != ".exr" || filepath.Base(exr.Files[0]) != "linear_albedo.exr" {
+		t.Fatalf("expected an EXR albedo file, got %+v", exr)
+	}
+}
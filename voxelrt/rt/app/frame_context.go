@@ -9,4 +9,49 @@ type FrameContext struct {
 	SwapchainView *wgpu.TextureView
 	WorkgroupsX   uint32
 	WorkgroupsY   uint32
+
+	// Viewport limits the final pass into SwapchainView to a pixel
+	// rectangle; empty covers the whole target. LoadTarget keeps what
+	// earlier views drew into the target instead of clearing it.
+	Viewport   FrameViewport
+	LoadTarget bool
+}
+
+// newFrameContext returns a frame at the active render rect size that finishes
+// into target.
+func (a *App) newFrameContext(target *wgpu.TextureView) *FrameContext {
+	width, height := a.activeRenderExtent()
+	return &FrameContext{
+		Width:         width,
+		Height:        height,
+		SwapchainView: target,
+		WorkgroupsX:   (width + 7) / 8,
+		WorkgroupsY:   (height + 7) / 8,
+	}
+}
+
+// FrameViewport is a pixel rectangle inside the final target.
+type FrameViewport struct {
+	X, Y, Width, Height float32
+}
+
+func (v FrameViewport) Empty() bool {
+	return v.Width <= 0 || v.Height <= 0
+}
+
+// finalPassLoadOp returns how a pass writing target starts: only the final
+// target of a view that shares it with earlier views loads.
+func (f *FrameContext) finalPassLoadOp(target *wgpu.TextureView) wgpu.LoadOp {
+	if f != nil && f.LoadTarget && target != nil && target == f.SwapchainView {
+		return wgpu.LoadOpLoad
+	}
+	return wgpu.LoadOpClear
+}
+
+// finalPassViewport returns the viewport for a pass writing target.
+func (f *FrameContext) finalPassViewport(target *wgpu.TextureView) FrameViewport {
+	if f == nil || target == nil || target != f.SwapchainView {
+		return FrameViewport{}
+	}
+	return f.Viewport
 }
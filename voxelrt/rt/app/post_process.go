@@ -0,0 +1,600 @@
+package app
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/gekko3d/gekko/voxelrt/rt/shaders"
+
+	"github.com/cogentcore/webgpu/wgpu"
+)
+
+// ToneMapper selects the curve used by the post-process composite.
+type ToneMapper uint32
+
+const (
+	ToneMapperACES ToneMapper = iota
+	ToneMapperReinhard
+	ToneMapperNone
+)
+
+// AntiAliasingMode selects the post-process anti-aliasing pass.
+type AntiAliasingMode uint32
+
+const (
+	AntiAliasingNone AntiAliasingMode = iota
+	AntiAliasingFXAA
+)
+
+// PostProcessSettings drives the post-process chain that runs after the
+// transparency resolve. The defaults reproduce the fixed ACES + gamma output
+// the resolve pass used before the chain existed.
+type PostProcessSettings struct {
+	Exposure   float32
+	ToneMapper ToneMapper
+
+	// BloomIntensity scales the blurred bright pass; zero skips the bloom node.
+	BloomIntensity float32
+	BloomThreshold float32
+	// BloomKnee softens the threshold as a fraction of BloomThreshold.
+	BloomKnee float32
+
+	Saturation  float32
+	Contrast    float32
+	ColorFilter [3]float32
+
+	Vignette            float32
+	ChromaticAberration float32
+
+	AntiAliasing AntiAliasingMode
+}
+
+func DefaultPostProcessSettings() PostProcessSettings {
+	return PostProcessSettings{
+		Exposure:       1,
+		ToneMapper:     ToneMapperACES,
+		BloomThreshold: 1,
+		BloomKnee:      0.5,
+		Saturation:     1,
+		Contrast:       1,
+		ColorFilter:    [3]float32{1, 1, 1},
+	}
+}
+
+// Normalized clamps settings into the ranges the shader expects.
+func (s PostProcessSettings) Normalized() PostProcessSettings {
+	s.Exposure = postProcessNonNegative(s.Exposure)
+	if s.ToneMapper > ToneMapperNone {
+		s.ToneMapper = ToneMapperACES
+	}
+	s.BloomIntensity = postProcessNonNegative(s.BloomIntensity)
+	s.BloomThreshold = postProcessNonNegative(s.BloomThreshold)
+	s.BloomKnee = postProcessClamp01(s.BloomKnee)
+	s.Saturation = postProcessNonNegative(s.Saturation)
+	s.Contrast = postProcessNonNegative(s.Contrast)
+	for i := range s.ColorFilter {
+		s.ColorFilter[i] = postProcessNonNegative(s.ColorFilter[i])
+	}
+	s.Vignette = postProcessClamp01(s.Vignette)
+	s.ChromaticAberration = postProcessNonNegative(s.ChromaticAberration)
+	if s.AntiAliasing > AntiAliasingFXAA {
+		s.AntiAliasing = AntiAliasingNone
+	}
+	return s
+}
+
+// LerpPostProcessSettings blends continuous parameters linearly. Discrete
+// choices (tone mapper, anti-aliasing) switch to b once t reaches one half.
+func LerpPostProcessSettings(a, b PostProcessSettings, t float32) PostProcessSettings {
+	t = postProcessClamp01(t)
+	out := a
+	out.Exposure = postProcessLerp(a.Exposure, b.Exposure, t)
+	out.BloomIntensity = postProcessLerp(a.BloomIntensity, b.BloomIntensity, t)
+	out.BloomThreshold = postProcessLerp(a.BloomThreshold, b.BloomThreshold, t)
+	out.BloomKnee = postProcessLerp(a.BloomKnee, b.BloomKnee, t)
+	out.Saturation = postProcessLerp(a.Saturation, b.Saturation, t)
+	out.Contrast = postProcessLerp(a.Contrast, b.Contrast, t)
+	for i := range out.ColorFilter {
+		out.ColorFilter[i] = postProcessLerp(a.ColorFilter[i], b.ColorFilter[i], t)
+	}
+	out.Vignette = postProcessLerp(a.Vignette, b.Vignette, t)
+	out.ChromaticAberration = postProcessLerp(a.ChromaticAberration, b.ChromaticAberration, t)
+	if t >= 0.5 {
+		out.ToneMapper = b.ToneMapper
+		out.AntiAliasing = b.AntiAliasing
+	}
+	return out
+}
+
+func postProcessLerp(a, b, t float32) float32 {
+	return a*(1-t) + b*t
+}
+
+func postProcessClamp01(v float32) float32 {
+	if v < 0 || v != v {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+func postProcessNonNegative(v float32) float32 {
+	if v < 0 || v != v {
+		return 0
+	}
+	return v
+}
+
+// postProcessUniformSize matches PostProcessParams in post_process.wgsl.
+const postProcessUniformSize = 64
+
+// packPostProcessUniform packs s and uvScale, the part of the source
+// textures the current view covers.
+func packPostProcessUniform(s PostProcessSettings, uvScale [2]float32) []byte {
+	s = s.Normalized()
+	buf := make([]byte, postProcessUniformSize)
+	putF32 := func(offset int, v float32) {
+		binary.LittleEndian.PutUint32(buf[offset:], math.Float32bits(v))
+	}
+	putF32(0, s.Exposure)
+	binary.LittleEndian.PutUint32(buf[4:], uint32(s.ToneMapper))
+	putF32(8, s.BloomThreshold)
+	putF32(12, s.BloomIntensity)
+	putF32(16, s.Saturation)
+	putF32(20, s.Contrast)
+	putF32(24, s.Vignette)
+	putF32(28, s.ChromaticAberration)
+	putF32(32, s.ColorFilter[0])
+	putF32(36, s.ColorFilter[1])
+	putF32(40, s.ColorFilter[2])
+	putF32(44, s.BloomKnee)
+	putF32(48, uvScale[0])
+	putF32(52, uvScale[1])
+	return buf
+}
+
+// PostProcessResources owns the HDR resolve target and the pipelines of the
+// post-process chain. HDRView receives the linear resolve output. The bloom
+// and LDR views are render graph transients, borrowed when the graph binds
+// its resources.
+type PostProcessResources struct {
+	HDRTexture *wgpu.Texture
+	HDRView    *wgpu.TextureView
+	BloomViews [2]*wgpu.TextureView
+	LDRView    *wgpu.TextureView
+
+	UniformBuffer *wgpu.Buffer
+	Sampler       *wgpu.Sampler
+	Layout        *wgpu.BindGroupLayout
+
+	BloomPrefilterPipeline *wgpu.RenderPipeline
+	BloomBlurHPipeline     *wgpu.RenderPipeline
+	BloomBlurVPipeline     *wgpu.RenderPipeline
+	CompositePipeline      *wgpu.RenderPipeline
+	FXAAPipeline           *wgpu.RenderPipeline
+
+	BloomPrefilterBG *wgpu.BindGroup
+	BloomBlurHBG     *wgpu.BindGroup
+	BloomBlurVBG     *wgpu.BindGroup
+	CompositeBG      *wgpu.BindGroup
+	FXAABG           *wgpu.BindGroup
+}
+
+// SetPostProcessSettings replaces the settings used by the post-process nodes.
+func (a *App) SetPostProcessSettings(settings PostProcessSettings) {
+	if a == nil {
+		return
+	}
+	a.PostProcess = settings.Normalized()
+}
+
+func (a *App) ensurePostProcessResources() *PostProcessResources {
+	if a == nil {
+		return nil
+	}
+	if a.PostProcessResources == nil {
+		a.PostProcessResources = &PostProcessResources{}
+	}
+	return a.PostProcessResources
+}
+
+// resolveTargetView returns the HDR target the resolve pass writes into.
+func (a *App) resolveTargetView() *wgpu.TextureView {
+	if a == nil || a.PostProcessResources == nil {
+		return nil
+	}
+	return a.PostProcessResources.HDRView
+}
+
+func (a *App) postProcessBloomPassEnabled() bool {
+	return a != nil && a.PostProcess.Normalized().BloomIntensity > 0 && a.renderViewHasFeature(RenderViewBloom)
+}
+
+func (a *App) postProcessCompositePassEnabled() bool {
+	return a != nil
+}
+
+func (a *App) postProcessAntiAliasPassEnabled() bool {
+	return a != nil && a.PostProcess.Normalized().AntiAliasing != AntiAliasingNone && a.renderViewHasFeature(RenderViewAntiAliasing)
+}
+
+// postProcessCompositeTarget picks where the composite writes: the swapchain,
+// or the LDR intermediate when an anti-aliasing pass still has to run.
+func (a *App) postProcessCompositeTarget(frame *FrameContext) *wgpu.TextureView {
+	if frame == nil {
+		return nil
+	}
+	if a.postProcessAntiAliasPassEnabled() && a.PostProcessResources != nil && a.PostProcessResources.LDRView != nil && a.PostProcessResources.FXAAPipeline != nil && a.PostProcessResources.FXAABG != nil {
+		return a.PostProcessResources.LDRView
+	}
+	return frame.SwapchainView
+}
+
+func (a *App) updatePostProcessUniform() {
+	if a == nil || a.Queue == nil || a.PostProcessResources == nil || a.PostProcessResources.UniformBuffer == nil {
+		return
+	}
+	settings := a.PostProcess
+	if a.PostProcessResources.BloomPrefilterBG == nil || !a.postProcessBloomPassEnabled() {
+		// Without bloom targets the composite's secondary texture is the HDR
+		// source itself, and a view that skips bloom would add stale
+		// results, so neither may be added.
+		settings.BloomIntensity = 0
+	}
+	a.Queue.WriteBuffer(a.PostProcessResources.UniformBuffer, 0, packPostProcessUniform(settings, a.renderRectUVScale()))
+}
+
+func (a *App) recordPostProcessBloomPass(encoder *wgpu.CommandEncoder) error {
+	if !a.postProcessBloomPassEnabled() {
+		return nil
+	}
+	if encoder == nil {
+		return fmt.Errorf("post-process bloom command encoder is nil")
+	}
+	res := a.PostProcessResources
+	if res == nil || res.BloomPrefilterPipeline == nil || res.BloomBlurHPipeline == nil || res.BloomBlurVPipeline == nil ||
+		res.BloomPrefilterBG == nil || res.BloomBlurHBG == nil || res.BloomBlurVBG == nil ||
+		res.BloomViews[0] == nil || res.BloomViews[1] == nil {
+		return nil
+	}
+
+	a.Profiler.BeginScope("Post Bloom")
+	defer a.Profiler.EndScope("Post Bloom")
+
+	steps := []struct {
+		pipeline *wgpu.RenderPipeline
+		bg       *wgpu.BindGroup
+		target   *wgpu.TextureView
+	}{
+		{res.BloomPrefilterPipeline, res.BloomPrefilterBG, res.BloomViews[0]},
+		{res.BloomBlurHPipeline, res.BloomBlurHBG, res.BloomViews[1]},
+		{res.BloomBlurVPipeline, res.BloomBlurVBG, res.BloomViews[0]},
+	}
+	bloomW, bloomH := a.RenderGraph.TextureExtent(RenderResourcePostProcessBloom)
+	viewport := a.renderRectViewport(bloomW, bloomH)
+	for _, step := range steps {
+		if err := recordPostProcessPass(encoder, step.target, step.pipeline, step.bg, wgpu.LoadOpClear, viewport); err != nil {
+			return fmt.Errorf("post-process bloom pass End failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func (a *App) recordPostProcessCompositePass(encoder *wgpu.CommandEncoder, frame *FrameContext) error {
+	if !a.postProcessCompositePassEnabled() {
+		return nil
+	}
+	res := a.PostProcessResources
+	a.Profiler.SetCount("PostProcessCompositeReady", boolToCount(res != nil && res.CompositePipeline != nil && res.CompositeBG != nil))
+
+	if encoder == nil {
+		return fmt.Errorf("post-process composite command encoder is nil")
+	}
+	if frame == nil {
+		return fmt.Errorf("post-process composite frame context is nil")
+	}
+	if frame.SwapchainView == nil {
+		return fmt.Errorf("post-process composite swapchain view is nil")
+	}
+
+	a.Profiler.BeginScope("Post Composite")
+	defer a.Profiler.EndScope("Post Composite")
+
+	var pipeline *wgpu.RenderPipeline
+	var bg *wgpu.BindGroup
+	if res != nil {
+		pipeline, bg = res.CompositePipeline, res.CompositeBG
+	}
+	target := a.postProcessCompositeTarget(frame)
+	viewport := frame.finalPassViewport(target)
+	if target != frame.SwapchainView {
+		viewport = a.renderRectViewport(a.RenderGraph.TextureExtent(RenderResourcePostProcessLDR))
+	}
+	if err := recordPostProcessPass(encoder, target, pipeline, bg, frame.finalPassLoadOp(target), viewport); err != nil {
+		return fmt.Errorf("post-process composite pass End failed: %w", err)
+	}
+	return nil
+}
+
+func (a *App) recordPostProcessAntiAliasPass(encoder *wgpu.CommandEncoder, frame *FrameContext) error {
+	if !a.postProcessAntiAliasPassEnabled() {
+		return nil
+	}
+	if encoder == nil {
+		return fmt.Errorf("post-process anti-alias command encoder is nil")
+	}
+	if frame == nil || frame.SwapchainView == nil {
+		return fmt.Errorf("post-process anti-alias swapchain view is nil")
+	}
+	res := a.PostProcessResources
+	if res == nil || res.FXAAPipeline == nil || res.FXAABG == nil || res.LDRView == nil {
+		// The composite wrote straight to the swapchain.
+		return nil
+	}
+
+	a.Profiler.BeginScope("Post FXAA")
+	defer a.Profiler.EndScope("Post FXAA")
+
+	target := frame.SwapchainView
+	if err := recordPostProcessPass(encoder, target, res.FXAAPipeline, res.FXAABG, frame.finalPassLoadOp(target), frame.finalPassViewport(target)); err != nil {
+		return fmt.Errorf("post-process anti-alias pass End failed: %w", err)
+	}
+	return nil
+}
+
+// recordPostProcessPass draws a fullscreen triangle into target, or into
+// viewport of it when one is set. The source is sampled by UV scaled to the
+// view's render rect, so a smaller viewport scales that rect into it.
+func recordPostProcessPass(encoder *wgpu.CommandEncoder, target *wgpu.TextureView, pipeline *wgpu.RenderPipeline, bg *wgpu.BindGroup, loadOp wgpu.LoadOp, viewport FrameViewport) error {
+	pass := encoder.BeginRenderPass(&wgpu.RenderPassDescriptor{
+		ColorAttachments: []wgpu.RenderPassColorAttachment{{
+			View:       target,
+			LoadOp:     loadOp,
+			StoreOp:    wgpu.StoreOpStore,
+			ClearValue: wgpu.Color{R: 0, G: 0, B: 0, A: 1},
+		}},
+	})
+	if !viewport.Empty() {
+		pass.SetViewport(viewport.X, viewport.Y, viewport.Width, viewport.Height, 0, 1)
+	}
+	if pipeline != nil && bg != nil {
+		pass.SetPipeline(pipeline)
+		pass.SetBindGroup(0, bg, nil)
+		pass.Draw(3, 1, 0, 0)
+	}
+	return pass.End()
+}
+
+// setupPostProcessTargets recreates the size-dependent post-process targets.
+func (a *App) setupPostProcessTargets(w, h int) {
+	if a == nil || a.Device == nil || a.Config == nil || w <= 0 || h <= 0 {
+		return
+	}
+	res := a.ensurePostProcessResources()
+	res.releaseTargets()
+
+	var err error
+	res.HDRTexture, res.HDRView, err = a.createPostProcessTarget("Post HDR Color", uint32(w), uint32(h), wgpu.TextureFormatRGBA16Float)
+	if err != nil {
+		fmt.Printf("ERROR: Failed to create post-process HDR target: %v\n", err)
+		return
+	}
+}
+
+func (a *App) createPostProcessTarget(label string, w, h uint32, format wgpu.TextureFormat) (*wgpu.Texture, *wgpu.TextureView, error) {
+	tex, err := a.Device.CreateTexture(&wgpu.TextureDescriptor{
+		Label:         label,
+		Size:          wgpu.Extent3D{Width: w, Height: h, DepthOrArrayLayers: 1},
+		MipLevelCount: 1,
+		Dimension:     wgpu.TextureDimension2D,
+		Format:        format,
+		Usage:         wgpu.TextureUsageTextureBinding | wgpu.TextureUsageRenderAttachment,
+		SampleCount:   1,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	view, err := tex.CreateView(nil)
+	if err != nil {
+		tex.Release()
+		return nil, nil, err
+	}
+	return tex, view, nil
+}
+
+func (r *PostProcessResources) releaseTargets() {
+	if r == nil {
+		return
+	}
+	release := func(tex **wgpu.Texture, view **wgpu.TextureView) {
+		if *view != nil {
+			(*view).Release()
+			*view = nil
+		}
+		if *tex != nil {
+			(*tex).Release()
+			*tex = nil
+		}
+	}
+	release(&r.HDRTexture, &r.HDRView)
+}
+
+// setupPostProcessPipelines builds the bloom, composite, and FXAA pipelines.
+// Bind groups follow once the render graph has allocated its transients.
+func (a *App) setupPostProcessPipelines() {
+	if a == nil || a.Device == nil || a.Config == nil {
+		return
+	}
+	res := a.ensurePostProcessResources()
+
+	mod, err := a.Device.CreateShaderModule(&wgpu.ShaderModuleDescriptor{
+		Label:          "Post Process",
+		WGSLDescriptor: &wgpu.ShaderModuleWGSLDescriptor{Code: shaders.PostProcessWGSL},
+	})
+	if err != nil {
+		fmt.Printf("ERROR: Failed to create post-process shader module: %v\n", err)
+		return
+	}
+
+	if res.UniformBuffer == nil {
+		res.UniformBuffer, err = a.Device.CreateBuffer(&wgpu.BufferDescriptor{
+			Label: "Post Process Params",
+			Size:  postProcessUniformSize,
+			Usage: wgpu.BufferUsageUniform | wgpu.BufferUsageCopyDst,
+		})
+		if err != nil {
+			fmt.Printf("ERROR: Failed to create post-process uniform buffer: %v\n", err)
+			return
+		}
+	}
+	if res.Sampler == nil {
+		res.Sampler, err = a.Device.CreateSampler(&wgpu.SamplerDescriptor{
+			AddressModeU:  wgpu.AddressModeClampToEdge,
+			AddressModeV:  wgpu.AddressModeClampToEdge,
+			AddressModeW:  wgpu.AddressModeClampToEdge,
+			MagFilter:     wgpu.FilterModeLinear,
+			MinFilter:     wgpu.FilterModeLinear,
+			MipmapFilter:  wgpu.MipmapFilterModeNearest,
+			MaxAnisotropy: 1,
+		})
+		if err != nil {
+			fmt.Printf("ERROR: Failed to create post-process sampler: %v\n", err)
+			return
+		}
+	}
+
+	// Group 0: params, sampler, source texture, secondary texture
+	res.Layout, err = a.Device.CreateBindGroupLayout(&wgpu.BindGroupLayoutDescriptor{
+		Label: "Post Process BGL0",
+		Entries: []wgpu.BindGroupLayoutEntry{
+			{
+				Binding:    0,
+				Visibility: wgpu.ShaderStageFragment,
+				Buffer:     wgpu.BufferBindingLayout{Type: wgpu.BufferBindingTypeUniform, MinBindingSize: postProcessUniformSize},
+			},
+			{
+				Binding:    1,
+				Visibility: wgpu.ShaderStageFragment,
+				Sampler:    wgpu.SamplerBindingLayout{Type: wgpu.SamplerBindingTypeFiltering},
+			},
+			{
+				Binding:    2,
+				Visibility: wgpu.ShaderStageFragment,
+				Texture: wgpu.TextureBindingLayout{
+					SampleType:    wgpu.TextureSampleTypeFloat,
+					ViewDimension: wgpu.TextureViewDimension2D,
+				},
+			},
+			{
+				Binding:    3,
+				Visibility: wgpu.ShaderStageFragment,
+				Texture: wgpu.TextureBindingLayout{
+					SampleType:    wgpu.TextureSampleTypeFloat,
+					ViewDimension: wgpu.TextureViewDimension2D,
+				},
+			},
+		},
+	})
+	if err != nil {
+		fmt.Printf("ERROR: Failed to create post-process BGL0: %v\n", err)
+		return
+	}
+
+	layout, err := a.Device.CreatePipelineLayout(&wgpu.PipelineLayoutDescriptor{
+		Label:            "Post Process Layout",
+		BindGroupLayouts: []*wgpu.BindGroupLayout{res.Layout},
+	})
+	if err != nil {
+		fmt.Printf("ERROR: Failed to create post-process pipeline layout: %v\n", err)
+		return
+	}
+
+	create := func(label, entryPoint string, format wgpu.TextureFormat) *wgpu.RenderPipeline {
+		pipeline, err := a.Device.CreateRenderPipeline(&wgpu.RenderPipelineDescriptor{
+			Label:  label,
+			Layout: layout,
+			Vertex: wgpu.VertexState{Module: mod, EntryPoint: "vs_main"},
+			Fragment: &wgpu.FragmentState{
+				Module:     mod,
+				EntryPoint: entryPoint,
+				Targets: []wgpu.ColorTargetState{{
+					Format:    format,
+					WriteMask: wgpu.ColorWriteMaskAll,
+				}},
+			},
+			Primitive:   wgpu.PrimitiveState{Topology: wgpu.PrimitiveTopologyTriangleList},
+			Multisample: wgpu.MultisampleState{Count: 1, Mask: 0xFFFFFFFF},
+		})
+		if err != nil {
+			fmt.Printf("ERROR: Failed to create %s pipeline: %v\n", label, err)
+			return nil
+		}
+		return pipeline
+	}
+	res.BloomPrefilterPipeline = create("Post Bloom Prefilter", "fs_bloom_prefilter", wgpu.TextureFormatRGBA16Float)
+	res.BloomBlurHPipeline = create("Post Bloom Blur H", "fs_bloom_blur_h", wgpu.TextureFormatRGBA16Float)
+	res.BloomBlurVPipeline = create("Post Bloom Blur V", "fs_bloom_blur_v", wgpu.TextureFormatRGBA16Float)
+	res.CompositePipeline = create("Post Composite", "fs_composite", a.Config.Format)
+	res.FXAAPipeline = create("Post FXAA", "fs_fxaa", a.Config.Format)
+
+	a.updatePostProcessUniform()
+}
+
+// bindPostProcessGraphResources picks up the graph-owned bloom and LDR views
+// and rebuilds the post-process bind groups around them.
+func (a *App) bindPostProcessGraphResources() {
+	if a == nil {
+		return
+	}
+	res := a.ensurePostProcessResources()
+	res.BloomViews = [2]*wgpu.TextureView{
+		a.RenderGraph.TextureView(RenderResourcePostProcessBloom),
+		a.RenderGraph.TextureView(RenderResourcePostProcessBloomScratch),
+	}
+	res.LDRView = a.RenderGraph.TextureView(RenderResourcePostProcessLDR)
+	a.createPostProcessBindGroups()
+}
+
+func (a *App) createPostProcessBindGroups() {
+	res := a.PostProcessResources
+	if res == nil {
+		return
+	}
+	res.BloomPrefilterBG, res.BloomBlurHBG, res.BloomBlurVBG, res.CompositeBG, res.FXAABG = nil, nil, nil, nil, nil
+	if res.Layout == nil || res.UniformBuffer == nil || res.Sampler == nil || res.HDRView == nil {
+		// Targets not ready yet (e.g., during early init/resize), skip creating BGs
+		return
+	}
+	create := func(label string, source, secondary *wgpu.TextureView) *wgpu.BindGroup {
+		bg, err := a.Device.CreateBindGroup(&wgpu.BindGroupDescriptor{
+			Label:  label,
+			Layout: res.Layout,
+			Entries: []wgpu.BindGroupEntry{
+				{Binding: 0, Buffer: res.UniformBuffer, Size: postProcessUniformSize},
+				{Binding: 1, Sampler: res.Sampler},
+				{Binding: 2, TextureView: source},
+				{Binding: 3, TextureView: secondary},
+			},
+		})
+		if err != nil {
+			fmt.Printf("ERROR: Failed to create %s bind group: %v\n", label, err)
+			return nil
+		}
+		return bg
+	}
+	bloom := res.HDRView
+	if res.BloomViews[0] != nil && res.BloomViews[1] != nil {
+		res.BloomPrefilterBG = create("Post Bloom Prefilter BG", res.HDRView, res.HDRView)
+		res.BloomBlurHBG = create("Post Bloom Blur H BG", res.BloomViews[0], res.BloomViews[0])
+		res.BloomBlurVBG = create("Post Bloom Blur V BG", res.BloomViews[1], res.BloomViews[1])
+		bloom = res.BloomViews[0]
+	}
+	res.CompositeBG = create("Post Composite BG", res.HDRView, bloom)
+	if res.LDRView != nil {
+		res.FXAABG = create("Post FXAA BG", res.LDRView, res.LDRView)
+	}
+	a.updatePostProcessUniform()
+}
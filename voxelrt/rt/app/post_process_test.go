@@ -0,0 +1,167 @@
+package app
+
+import (
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/cogentcore/webgpu/wgpu"
+	"github.com/gekko3d/gekko/voxelrt/rt/core"
+)
+
+func TestDefaultRenderGraphRunsPostProcessBetweenResolveAndOverlays(t *testing.T) {
+	ordered, err := NewDefaultRenderGraph().Compile()
+	if err != nil {
+		t.Fatalf("Compile returned error: %v", err)
+	}
+	index := make(map[string]int, len(ordered))
+	for i, spec := range ordered {
+		index[spec.Name] = i
+	}
+	chain := []string{
+		RenderNodeCoreResolve,
+		RenderNodePostProcessBloom,
+		RenderNodePostProcessComposite,
+		RenderNodePostProcessAntiAlias,
+		RenderNodeFeatureTextOverlay,
+	}
+	for i := 1; i < len(chain); i++ {
+		if index[chain[i-1]] >= index[chain[i]] {
+			t.Fatalf("expected %q before %q, got order %v", chain[i-1], chain[i], renderNodeNames(ordered))
+		}
+	}
+}
+
+func TestDefaultRenderGraphPostProcessNodeGating(t *testing.T) {
+	bloom := defaultRenderGraphNode(RenderNodePostProcessBloom)
+	composite := defaultRenderGraphNode(RenderNodePostProcessComposite)
+	antiAlias := defaultRenderGraphNode(RenderNodePostProcessAntiAlias)
+	if bloom.Enabled(nil) || composite.Enabled(nil) || antiAlias.Enabled(nil) {
+		t.Fatal("expected post-process nodes to be disabled without app")
+	}
+
+	app := NewApp(nil)
+	if bloom.Enabled(app) {
+		t.Fatal("expected bloom node to be disabled with default settings")
+	}
+	if !composite.Enabled(app) {
+		t.Fatal("expected composite node to always run with an app")
+	}
+	if antiAlias.Enabled(app) {
+		t.Fatal("expected anti-alias node to be disabled with default settings")
+	}
+
+	settings := DefaultPostProcessSettings()
+	settings.BloomIntensity = 0.4
+	settings.AntiAliasing = AntiAliasingFXAA
+	app.SetPostProcessSettings(settings)
+	if !bloom.Enabled(app) || !antiAlias.Enabled(app) {
+		t.Fatal("expected bloom and anti-alias nodes to enable from settings")
+	}
+}
+
+func TestPostProcessCompositeTargetsLDRTextureOnlyWhenFXAAReady(t *testing.T) {
+	swapchain := &wgpu.TextureView{}
+	ldr := &wgpu.TextureView{}
+	frame := &FrameContext{SwapchainView: swapchain}
+	app := &App{PostProcess: DefaultPostProcessSettings()}
+
+	if got := app.postProcessCompositeTarget(frame); got != swapchain {
+		t.Fatal("expected composite to write to the swapchain without anti-aliasing")
+	}
+
+	app.PostProcess.AntiAliasing = AntiAliasingFXAA
+	if got := app.postProcessCompositeTarget(frame); got != swapchain {
+		t.Fatal("expected composite to fall back to the swapchain while FXAA resources are missing")
+	}
+
+	app.PostProcessResources = &PostProcessResources{LDRView: ldr, FXAAPipeline: &wgpu.RenderPipeline{}, FXAABG: &wgpu.BindGroup{}}
+	if got := app.postProcessCompositeTarget(frame); got != ldr {
+		t.Fatal("expected composite to write to the LDR target ahead of FXAA")
+	}
+}
+
+func TestRecordPostProcessPassesRequireEncoder(t *testing.T) {
+	settings := DefaultPostProcessSettings()
+	settings.BloomIntensity = 1
+	settings.AntiAliasing = AntiAliasingFXAA
+	app := &App{Profiler: core.NewProfiler(), PostProcess: settings}
+	frame := &FrameContext{SwapchainView: &wgpu.TextureView{}}
+
+	if err := app.recordPostProcessBloomPass(nil); err == nil || !strings.Contains(err.Error(), "command encoder is nil") {
+		t.Fatalf("expected bloom nil-encoder error, got %v", err)
+	}
+	if err := app.recordPostProcessCompositePass(nil, frame); err == nil || !strings.Contains(err.Error(), "command encoder is nil") {
+		t.Fatalf("expected composite nil-encoder error, got %v", err)
+	}
+	if got := app.Profiler.Counts["PostProcessCompositeReady"]; got != 0 {
+		t.Fatalf("PostProcessCompositeReady = %d, want 0", got)
+	}
+	if err := app.recordPostProcessAntiAliasPass(nil, frame); err == nil || !strings.Contains(err.Error(), "command encoder is nil") {
+		t.Fatalf("expected anti-alias nil-encoder error, got %v", err)
+	}
+}
+
+func TestLerpPostProcessSettingsBlendsContinuousAndSwitchesDiscrete(t *testing.T) {
+	a := DefaultPostProcessSettings()
+	b := a
+	b.Exposure = 3
+	b.ColorFilter = [3]float32{0.2, 0.6, 1}
+	b.Vignette = 0.8
+	b.ToneMapper = ToneMapperReinhard
+	b.AntiAliasing = AntiAliasingFXAA
+
+	quarter := LerpPostProcessSettings(a, b, 0.25)
+	if !approxPostProcess(quarter.Exposure, 1.5) || !approxPostProcess(quarter.ColorFilter[0], 0.8) || !approxPostProcess(quarter.Vignette, 0.2) {
+		t.Fatalf("unexpected quarter blend %+v", quarter)
+	}
+	if quarter.ToneMapper != ToneMapperACES || quarter.AntiAliasing != AntiAliasingNone {
+		t.Fatalf("expected discrete settings to hold below one half, got %+v", quarter)
+	}
+
+	half := LerpPostProcessSettings(a, b, 0.5)
+	if half.ToneMapper != ToneMapperReinhard || half.AntiAliasing != AntiAliasingFXAA {
+		t.Fatalf("expected discrete settings to switch at one half, got %+v", half)
+	}
+	if got := LerpPostProcessSettings(a, b, 4); got != b {
+		t.Fatalf("expected t to clamp to 1, got %+v", got)
+	}
+}
+
+func TestPackPostProcessUniformMatchesShaderLayout(t *testing.T) {
+	settings := DefaultPostProcessSettings()
+	settings.Exposure = 2
+	settings.ToneMapper = ToneMapperReinhard
+	settings.BloomIntensity = 0.5
+	settings.Saturation = -1
+	settings.ColorFilter = [3]float32{0.25, 0.5, 0.75}
+	settings.Vignette = 3
+
+	buf := packPostProcessUniform(settings, [2]float32{0.5, 0.25})
+	if len(buf) != postProcessUniformSize {
+		t.Fatalf("uniform size = %d, want %d", len(buf), postProcessUniformSize)
+	}
+	f32 := func(offset int) float32 {
+		return math.Float32frombits(binary.LittleEndian.Uint32(buf[offset:]))
+	}
+	if f32(0) != 2 || binary.LittleEndian.Uint32(buf[4:]) != uint32(ToneMapperReinhard) || f32(12) != 0.5 {
+		t.Fatalf("unexpected exposure/tone-mapper/bloom header %v", buf[:16])
+	}
+	if f32(16) != 0 {
+		t.Fatalf("expected negative saturation to clamp to 0, got %v", f32(16))
+	}
+	if f32(24) != 1 {
+		t.Fatalf("expected vignette to clamp to 1, got %v", f32(24))
+	}
+	if f32(32) != 0.25 || f32(36) != 0.5 || f32(40) != 0.75 || f32(44) != 0.5 {
+		t.Fatalf("unexpected color filter/knee lanes %v %v %v %v", f32(32), f32(36), f32(40), f32(44))
+	}
+	if f32(48) != 0.5 || f32(52) != 0.25 {
+		t.Fatalf("unexpected uv scale lanes %v %v", f32(48), f32(52))
+	}
+}
+
+func approxPostProcess(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-5
+}
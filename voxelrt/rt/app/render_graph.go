@@ -9,11 +9,18 @@ import (
 )
 
 // RenderGraph stores graph node declarations and compiles them into a stable
-// dependency order.
+// dependency order. Resource reads and writes declared on nodes add ordering
+// edges and drive transient resource allocation.
 type RenderGraph struct {
-	specs    []RenderNodeSpec
-	compiled []RenderNodeSpec
-	dirty    bool
+	specs     []RenderNodeSpec
+	resources []RenderResourceDesc
+	compiled  []RenderNodeSpec
+	plan      RenderGraphResourcePlan
+	dirty     bool
+
+	allocations     []*renderGraphAllocation
+	allocationSlots map[string]int
+	allocationStale bool
 }
 
 func NewRenderGraph() *RenderGraph {
@@ -28,6 +35,25 @@ func (g *RenderGraph) Register(spec RenderNodeSpec) {
 	g.dirty = true
 }
 
+// DeclareResource registers a texture or buffer that nodes may list in Reads
+// and Writes.
+func (g *RenderGraph) DeclareResource(desc RenderResourceDesc) {
+	if g == nil {
+		return
+	}
+	g.resources = append(g.resources, desc)
+	g.dirty = true
+}
+
+func (g *RenderGraph) Resources() []RenderResourceDesc {
+	if g == nil {
+		return nil
+	}
+	out := make([]RenderResourceDesc, len(g.resources))
+	copy(out, g.resources)
+	return out
+}
+
 func (g *RenderGraph) Specs() []RenderNodeSpec {
 	if g == nil {
 		return nil
@@ -63,8 +89,32 @@ func (g *RenderGraph) Compile() ([]RenderNodeSpec, error) {
 		registrationOrder = append(registrationOrder, spec.Name)
 	}
 
+	resources := make(map[string]RenderResourceDesc, len(g.resources))
+	for _, desc := range g.resources {
+		if err := desc.validate(); err != nil {
+			return nil, err
+		}
+		if _, exists := resources[desc.Name]; exists {
+			return nil, fmt.Errorf("render graph resource %q declared more than once", desc.Name)
+		}
+		resources[desc.Name] = desc
+	}
+	resourceEdges, err := renderGraphResourceEdges(g.specs, resources)
+	if err != nil {
+		return nil, err
+	}
+
 	indegree := make(map[string]int, len(g.specs))
 	dependents := make(map[string][]string, len(g.specs))
+	seenEdges := make(map[[2]string]bool)
+	addEdge := func(from, to string) {
+		if seenEdges[[2]string{from, to}] {
+			return
+		}
+		seenEdges[[2]string{from, to}] = true
+		indegree[to]++
+		dependents[from] = append(dependents[from], to)
+	}
 	for _, name := range registrationOrder {
 		indegree[name] = 0
 	}
@@ -76,10 +126,12 @@ func (g *RenderGraph) Compile() ([]RenderNodeSpec, error) {
 			if _, exists := byName[dep]; !exists {
 				return nil, fmt.Errorf("render graph node %q depends on missing node %q", spec.Name, dep)
 			}
-			indegree[spec.Name]++
-			dependents[dep] = append(dependents[dep], spec.Name)
+			addEdge(dep, spec.Name)
 		}
 	}
+	for _, edge := range resourceEdges {
+		addEdge(edge[0], edge[1])
+	}
 
 	ready := make([]string, 0, len(g.specs))
 	for _, name := range registrationOrder {
@@ -113,7 +165,9 @@ func (g *RenderGraph) Compile() ([]RenderNodeSpec, error) {
 	}
 
 	g.compiled = ordered
+	g.plan = planRenderGraphResources(ordered, g.resources)
 	g.dirty = false
+	g.allocationStale = true
 	out := make([]RenderNodeSpec, len(g.compiled))
 	copy(out, g.compiled)
 	return out, nil
@@ -125,7 +179,7 @@ func (g *RenderGraph) Record(a *App, encoder *wgpu.CommandEncoder, frame *FrameC
 		return err
 	}
 	for _, spec := range ordered {
-		if spec.Node == nil || !spec.Node.Enabled(a) {
+		if spec.Node == nil || !spec.Node.Enabled(a) || !a.renderViewRunsNode(spec.Name) {
 			continue
 		}
 		if err := spec.Node.Record(a, encoder, frame); err != nil {
@@ -140,6 +194,12 @@ func (g *RenderGraph) Setup(a *App) error {
 	if err != nil {
 		return err
 	}
+	if g.allocationStale && a != nil && a.Config != nil {
+		width, height := a.renderExtent()
+		if err := g.AllocateResources(a, width, height); err != nil {
+			return err
+		}
+	}
 	for _, spec := range ordered {
 		if spec.Node == nil || !spec.Node.Enabled(a) {
 			continue
@@ -211,6 +271,7 @@ func (g *RenderGraph) Shutdown(a *App) {
 		}
 		spec.Node.Shutdown(a)
 	}
+	g.ReleaseResources()
 }
 
 func (g *RenderGraph) RecordNode(name string, a *App, encoder *wgpu.CommandEncoder, frame *FrameContext) error {
@@ -235,3 +296,159 @@ func (g *RenderGraph) RecordNode(name string, a *App, encoder *wgpu.CommandEncod
 	}
 	return fmt.Errorf("render graph node %q is not registered", name)
 }
+
+// ResourcePlan compiles the graph and returns the resource lifetimes and
+// aliasing slots. It needs no device.
+func (g *RenderGraph) ResourcePlan() (RenderGraphResourcePlan, error) {
+	if g == nil {
+		return RenderGraphResourcePlan{}, nil
+	}
+	if _, err := g.Compile(); err != nil {
+		return RenderGraphResourcePlan{}, err
+	}
+	return g.plan.clone(), nil
+}
+
+// AllocateResources creates the transient resources of the compiled plan for
+// the given internal render extent, keeping allocations whose description
+// and size are unchanged, and then lets every RenderNodeResourceBinder
+// rebuild its bind groups. The app calls it whenever swapchain-bound
+// resources are rebuilt, including when the render scale changes.
+func (g *RenderGraph) AllocateResources(a *App, width, height uint32) error {
+	if g == nil {
+		return nil
+	}
+	ordered, err := g.Compile()
+	if err != nil {
+		return err
+	}
+	if a == nil || a.Device == nil {
+		return nil
+	}
+
+	previous := append([]*renderGraphAllocation(nil), g.allocations...)
+	next := make([]*renderGraphAllocation, len(g.plan.Slots))
+	for i, slot := range g.plan.Slots {
+		w, h := uint32(0), uint32(0)
+		format := wgpu.TextureFormatUndefined
+		if slot.Desc.Kind == RenderResourceTexture {
+			w, h = slot.Desc.Extent(width, height)
+			format = slot.Desc.Format
+			if slot.Desc.SwapchainFormat {
+				if a.Config == nil {
+					return fmt.Errorf("render graph texture %q needs the swapchain format before the surface is configured", slot.Resources[0])
+				}
+				format = a.Config.Format
+			}
+		}
+		key := slot.Desc.aliasKey()
+		for j, old := range previous {
+			if old.matches(key, w, h, format) {
+				next[i] = old
+				previous[j] = nil
+				break
+			}
+		}
+		if next[i] != nil {
+			continue
+		}
+		alloc, err := createRenderGraphAllocation(a.Device, slot, w, h, format)
+		if err != nil {
+			for _, created := range next {
+				if created != nil && !containsRenderGraphAllocation(g.allocations, created) {
+					created.release()
+				}
+			}
+			return fmt.Errorf("render graph resource %q allocation failed: %w", slot.Resources[0], err)
+		}
+		next[i] = alloc
+	}
+	for _, old := range previous {
+		old.release()
+	}
+
+	g.allocations = next
+	g.allocationSlots = make(map[string]int, len(g.plan.Lifetimes))
+	for _, lifetime := range g.plan.Lifetimes {
+		if lifetime.Slot >= 0 {
+			g.allocationSlots[lifetime.Name] = lifetime.Slot
+		}
+	}
+	g.allocationStale = false
+
+	for _, spec := range ordered {
+		binder, ok := spec.Node.(RenderNodeResourceBinder)
+		if !ok {
+			continue
+		}
+		if err := binder.BindResources(a); err != nil {
+			return fmt.Errorf("render graph node %q bind resources failed: %w", spec.Name, err)
+		}
+	}
+	return nil
+}
+
+func containsRenderGraphAllocation(list []*renderGraphAllocation, target *renderGraphAllocation) bool {
+	for _, alloc := range list {
+		if alloc == target {
+			return true
+		}
+	}
+	return false
+}
+
+// ReleaseResources frees every transient allocation.
+func (g *RenderGraph) ReleaseResources() {
+	if g == nil {
+		return
+	}
+	for _, alloc := range g.allocations {
+		alloc.release()
+	}
+	g.allocations = nil
+	g.allocationSlots = nil
+	g.allocationStale = true
+}
+
+func (g *RenderGraph) allocation(name string) *renderGraphAllocation {
+	if g == nil {
+		return nil
+	}
+	slot, ok := g.allocationSlots[name]
+	if !ok || slot < 0 || slot >= len(g.allocations) {
+		return nil
+	}
+	return g.allocations[slot]
+}
+
+// TextureView returns the view of an allocated transient texture, or nil.
+func (g *RenderGraph) TextureView(name string) *wgpu.TextureView {
+	if alloc := g.allocation(name); alloc != nil {
+		return alloc.view
+	}
+	return nil
+}
+
+// Texture returns an allocated transient texture, or nil.
+func (g *RenderGraph) Texture(name string) *wgpu.Texture {
+	if alloc := g.allocation(name); alloc != nil {
+		return alloc.texture
+	}
+	return nil
+}
+
+// TextureExtent returns the size of an allocated transient texture, or zero.
+func (g *RenderGraph) TextureExtent(name string) (uint32, uint32) {
+	if alloc := g.allocation(name); alloc != nil && alloc.texture != nil {
+		return alloc.width, alloc.height
+	}
+	return 0, 0
+}
+
+// Buffer returns an allocated transient buffer, or nil.
+func (g *RenderGraph) Buffer(name string) *wgpu.Buffer {
+	if alloc := g.allocation(name); alloc != nil {
+		return alloc.buffer
+	}
+	return nil
+}
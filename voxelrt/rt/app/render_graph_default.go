@@ -24,16 +24,29 @@ const (
 	RenderNodeCoreAccumulation         = "core-accumulation"
 	RenderNodeFeaturePreResolve        = "feature-pre-resolve"
 	RenderNodeCoreResolve              = "core-resolve"
+	RenderNodePostProcessBloom         = "post-process-bloom"
+	RenderNodePostProcessComposite     = "post-process-composite"
+	RenderNodePostProcessAntiAlias     = "post-process-anti-alias"
 	RenderNodeFeatureTextOverlay       = "feature-text-overlay"
 	RenderNodeFeatureGizmosOverlay     = "feature-gizmos-overlay"
 	RenderNodeFeaturePostResolve       = "feature-post-resolve"
 )
 
+const (
+	RenderResourcePostProcessHDR          = "post-process-hdr"
+	RenderResourcePostProcessBloom        = "post-process-bloom"
+	RenderResourcePostProcessBloomScratch = "post-process-bloom-scratch"
+	RenderResourcePostProcessLDR          = "post-process-ldr"
+)
+
 // NewDefaultRenderGraph declares the current App.Render order as graph nodes.
 // Core compatibility nodes are no-ops until their passes migrate out of
 // App.Render; feature-stage nodes already wrap the existing feature registry.
 func NewDefaultRenderGraph() *RenderGraph {
 	graph := NewRenderGraph()
+	for _, desc := range defaultRenderGraphResources() {
+		graph.DeclareResource(desc)
+	}
 	for _, spec := range defaultRenderGraphSpecs() {
 		graph.Register(spec)
 	}
@@ -62,13 +75,40 @@ func defaultRenderGraphSpecs() []RenderNodeSpec {
 		defaultRenderGraphSpec(RenderNodeCoreDebugScene, RenderNodeFeatureAnalyticMedia),
 		defaultRenderGraphSpec(RenderNodeCoreAccumulation, RenderNodeCoreDebugScene),
 		defaultRenderGraphSpec(RenderNodeFeaturePreResolve, RenderNodeCoreAccumulation),
-		defaultRenderGraphSpec(RenderNodeCoreResolve, RenderNodeFeaturePreResolve),
-		defaultRenderGraphSpec(RenderNodeFeatureTextOverlay, RenderNodeCoreResolve),
+		defaultRenderGraphSpec(RenderNodeCoreResolve, RenderNodeFeaturePreResolve).
+			withResources(nil, []string{RenderResourcePostProcessHDR}),
+		defaultRenderGraphSpec(RenderNodePostProcessBloom, RenderNodeCoreResolve).
+			withResources([]string{RenderResourcePostProcessHDR}, []string{RenderResourcePostProcessBloom, RenderResourcePostProcessBloomScratch}),
+		defaultRenderGraphSpec(RenderNodePostProcessComposite, RenderNodePostProcessBloom).
+			withResources([]string{RenderResourcePostProcessHDR, RenderResourcePostProcessBloom}, []string{RenderResourcePostProcessLDR}),
+		defaultRenderGraphSpec(RenderNodePostProcessAntiAlias, RenderNodePostProcessComposite).
+			withResources([]string{RenderResourcePostProcessLDR}, nil),
+		defaultRenderGraphSpec(RenderNodeFeatureTextOverlay, RenderNodePostProcessAntiAlias),
 		defaultRenderGraphSpec(RenderNodeFeatureGizmosOverlay, RenderNodeFeatureTextOverlay),
 		defaultRenderGraphSpec(RenderNodeFeaturePostResolve, RenderNodeFeatureGizmosOverlay),
 	}
 }
 
+// defaultRenderGraphResources declares the resources default nodes exchange.
+// The HDR resolve target is owned by PostProcessResources; the bloom chain
+// and the LDR intermediate are graph transients. Like the HDR target they are
+// sized from the internal render extent: bloom at half of it, LDR at all of
+// it, and only the FXAA pass upscales into the swapchain.
+func defaultRenderGraphResources() []RenderResourceDesc {
+	return []RenderResourceDesc{
+		{Name: RenderResourcePostProcessHDR, Kind: RenderResourceTexture, Imported: true},
+		{Name: RenderResourcePostProcessBloom, Kind: RenderResourceTexture, Format: wgpu.TextureFormatRGBA16Float, Scale: 0.5},
+		{Name: RenderResourcePostProcessBloomScratch, Kind: RenderResourceTexture, Format: wgpu.TextureFormatRGBA16Float, Scale: 0.5},
+		{Name: RenderResourcePostProcessLDR, Kind: RenderResourceTexture, SwapchainFormat: true},
+	}
+}
+
+func (s RenderNodeSpec) withResources(reads, writes []string) RenderNodeSpec {
+	s.Reads = append([]string(nil), reads...)
+	s.Writes = append([]string(nil), writes...)
+	return s
+}
+
 func defaultRenderGraphSpec(name string, after ...string) RenderNodeSpec {
 	return RenderNodeSpec{
 		Name:  name,
@@ -145,6 +185,12 @@ func defaultRenderGraphNode(name string) RenderNode {
 		}
 	case RenderNodeCoreResolve:
 		return coreResolveRenderNode{name: name}
+	case RenderNodePostProcessBloom:
+		return postProcessBloomRenderNode{name: name}
+	case RenderNodePostProcessComposite:
+		return postProcessCompositeRenderNode{name: name}
+	case RenderNodePostProcessAntiAlias:
+		return postProcessAntiAliasRenderNode{name: name}
 	case RenderNodeFeatureTextOverlay:
 		return textOverlayRenderNode{name: name}
 	case RenderNodeFeatureGizmosOverlay:
@@ -467,6 +513,114 @@ func (n coreResolveRenderNode) Record(a *App, encoder *wgpu.CommandEncoder, fram
 
 func (n coreResolveRenderNode) Shutdown(*App) {}
 
+type postProcessBloomRenderNode struct {
+	name string
+}
+
+func (n postProcessBloomRenderNode) Name() string {
+	return n.name
+}
+
+func (n postProcessBloomRenderNode) Enabled(a *App) bool {
+	return a.postProcessBloomPassEnabled()
+}
+
+func (n postProcessBloomRenderNode) Setup(*App) error {
+	return nil
+}
+
+func (n postProcessBloomRenderNode) Resize(*App, uint32, uint32) error {
+	return nil
+}
+
+func (n postProcessBloomRenderNode) OnSceneBuffersRecreated(*App) error {
+	return nil
+}
+
+func (n postProcessBloomRenderNode) Update(*App) error {
+	return nil
+}
+
+func (n postProcessBloomRenderNode) Record(a *App, encoder *wgpu.CommandEncoder, _ *FrameContext) error {
+	return a.recordPostProcessBloomPass(encoder)
+}
+
+func (n postProcessBloomRenderNode) Shutdown(*App) {}
+
+type postProcessCompositeRenderNode struct {
+	name string
+}
+
+func (n postProcessCompositeRenderNode) Name() string {
+	return n.name
+}
+
+func (n postProcessCompositeRenderNode) Enabled(a *App) bool {
+	return a.postProcessCompositePassEnabled()
+}
+
+func (n postProcessCompositeRenderNode) Setup(*App) error {
+	return nil
+}
+
+func (n postProcessCompositeRenderNode) Resize(*App, uint32, uint32) error {
+	return nil
+}
+
+func (n postProcessCompositeRenderNode) OnSceneBuffersRecreated(*App) error {
+	return nil
+}
+
+func (n postProcessCompositeRenderNode) Update(a *App) error {
+	a.updatePostProcessUniform()
+	return nil
+}
+
+func (n postProcessCompositeRenderNode) Record(a *App, encoder *wgpu.CommandEncoder, frame *FrameContext) error {
+	return a.recordPostProcessCompositePass(encoder, frame)
+}
+
+func (n postProcessCompositeRenderNode) BindResources(a *App) error {
+	a.bindPostProcessGraphResources()
+	return nil
+}
+
+func (n postProcessCompositeRenderNode) Shutdown(*App) {}
+
+type postProcessAntiAliasRenderNode struct {
+	name string
+}
+
+func (n postProcessAntiAliasRenderNode) Name() string {
+	return n.name
+}
+
+func (n postProcessAntiAliasRenderNode) Enabled(a *App) bool {
+	return a.postProcessAntiAliasPassEnabled()
+}
+
+func (n postProcessAntiAliasRenderNode) Setup(*App) error {
+	return nil
+}
+
+func (n postProcessAntiAliasRenderNode) Resize(*App, uint32, uint32) error {
+	return nil
+}
+
+func (n postProcessAntiAliasRenderNode) OnSceneBuffersRecreated(*App) error {
+	return nil
+}
+
+func (n postProcessAntiAliasRenderNode) Update(*App) error {
+	return nil
+}
+
+func (n postProcessAntiAliasRenderNode) Record(a *App, encoder *wgpu.CommandEncoder, frame *FrameContext) error {
+	return a.recordPostProcessAntiAliasPass(encoder, frame)
+}
+
+func (n postProcessAntiAliasRenderNode) Shutdown(*App) {}
+
 type coreDebugSceneRenderNode struct {
 	name string
 }
@@ -39,6 +39,9 @@ func TestDefaultRenderGraphOrderMatchesRuntimeFrameSequence(t *testing.T) {
 		RenderNodeCoreAccumulation,
 		RenderNodeFeaturePreResolve,
 		RenderNodeCoreResolve,
+		RenderNodePostProcessBloom,
+		RenderNodePostProcessComposite,
+		RenderNodePostProcessAntiAlias,
 		RenderNodeFeatureTextOverlay,
 		RenderNodeFeatureGizmosOverlay,
 		RenderNodeFeaturePostResolve,
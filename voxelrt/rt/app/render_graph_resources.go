@@ -0,0 +1,366 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cogentcore/webgpu/wgpu"
+)
+
+// RenderResourceKind selects the GPU object a graph resource describes.
+type RenderResourceKind uint8
+
+const (
+	RenderResourceTexture RenderResourceKind = iota
+	RenderResourceBuffer
+)
+
+func (k RenderResourceKind) String() string {
+	switch k {
+	case RenderResourceTexture:
+		return "texture"
+	case RenderResourceBuffer:
+		return "buffer"
+	default:
+		return fmt.Sprintf("RenderResourceKind(%d)", uint8(k))
+	}
+}
+
+// RenderResourceDesc declares a texture or buffer that graph nodes read or
+// write.
+//
+// Transient resources are allocated by the graph and live for one frame:
+// their contents are undefined before the first writer runs, and resources
+// with the same description and non-overlapping lifetimes share one
+// allocation. Imported resources are owned elsewhere (core targets,
+// GpuBufferManager buffers); the graph only uses them for ordering.
+type RenderResourceDesc struct {
+	Name     string
+	Kind     RenderResourceKind
+	Imported bool
+
+	// Format is the texture format. SwapchainFormat uses the surface format
+	// instead.
+	Format          wgpu.TextureFormat
+	SwapchainFormat bool
+	// Scale sizes a texture relative to the internal render extent, which
+	// dynamic resolution shrinks below the swapchain; zero means 1. Width and
+	// Height, when both set, pin a fixed size instead.
+	Scale         float32
+	Width, Height uint32
+	// TextureUsage defaults to TextureBinding|RenderAttachment.
+	TextureUsage wgpu.TextureUsage
+
+	// Size is the buffer size in bytes.
+	Size uint64
+	// BufferUsage defaults to Storage|CopyDst.
+	BufferUsage wgpu.BufferUsage
+}
+
+func (d RenderResourceDesc) normalized() RenderResourceDesc {
+	out := d
+	switch out.Kind {
+	case RenderResourceTexture:
+		if out.Scale <= 0 || out.Scale != out.Scale {
+			out.Scale = 1
+		}
+		if out.Width == 0 || out.Height == 0 {
+			out.Width, out.Height = 0, 0
+		} else {
+			out.Scale = 1
+		}
+		if out.TextureUsage == 0 {
+			out.TextureUsage = wgpu.TextureUsageTextureBinding | wgpu.TextureUsageRenderAttachment
+		}
+		if out.SwapchainFormat {
+			out.Format = wgpu.TextureFormatUndefined
+		}
+	case RenderResourceBuffer:
+		if out.BufferUsage == 0 {
+			out.BufferUsage = wgpu.BufferUsageStorage | wgpu.BufferUsageCopyDst
+		}
+	}
+	return out
+}
+
+// aliasKey identifies descriptions whose allocations are interchangeable.
+func (d RenderResourceDesc) aliasKey() RenderResourceDesc {
+	key := d.normalized()
+	key.Name = ""
+	key.Imported = false
+	return key
+}
+
+func (d RenderResourceDesc) validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("render graph resource has empty name")
+	}
+	switch d.Kind {
+	case RenderResourceTexture, RenderResourceBuffer:
+	default:
+		return fmt.Errorf("render graph resource %q has unknown kind %v", d.Name, d.Kind)
+	}
+	if d.Imported {
+		return nil
+	}
+	switch d.Kind {
+	case RenderResourceTexture:
+		if !d.SwapchainFormat && d.Format == wgpu.TextureFormatUndefined {
+			return fmt.Errorf("render graph texture %q has no format", d.Name)
+		}
+		if (d.Width == 0) != (d.Height == 0) {
+			return fmt.Errorf("render graph texture %q sets only one of width and height", d.Name)
+		}
+		if d.Scale < 0 || d.Scale != d.Scale {
+			return fmt.Errorf("render graph texture %q has invalid scale %v", d.Name, d.Scale)
+		}
+	case RenderResourceBuffer:
+		if d.Size == 0 {
+			return fmt.Errorf("render graph buffer %q has zero size", d.Name)
+		}
+	}
+	return nil
+}
+
+// Extent returns the texture size for the given internal render extent.
+func (d RenderResourceDesc) Extent(width, height uint32) (uint32, uint32) {
+	n := d.normalized()
+	if n.Width > 0 && n.Height > 0 {
+		return n.Width, n.Height
+	}
+	return max(uint32(float64(width)*float64(n.Scale)), 1), max(uint32(float64(height)*float64(n.Scale)), 1)
+}
+
+// RenderResourceLifetime is the span of compiled node indices that touch a
+// resource. Slot is the shared allocation index, or -1 for imported
+// resources.
+type RenderResourceLifetime struct {
+	Name  string
+	First int
+	Last  int
+	Slot  int
+}
+
+// RenderResourceSlot is one physical allocation and the transient resources
+// aliased onto it, in lifetime order.
+type RenderResourceSlot struct {
+	Desc      RenderResourceDesc
+	Resources []string
+}
+
+// RenderGraphResourcePlan is the device-independent result of compiling the
+// graph's resource declarations.
+type RenderGraphResourcePlan struct {
+	Lifetimes []RenderResourceLifetime
+	Slots     []RenderResourceSlot
+}
+
+// Lifetime returns the lifetime for the named resource.
+func (p RenderGraphResourcePlan) Lifetime(name string) (RenderResourceLifetime, bool) {
+	for _, lifetime := range p.Lifetimes {
+		if lifetime.Name == name {
+			return lifetime, true
+		}
+	}
+	return RenderResourceLifetime{}, false
+}
+
+func (p RenderGraphResourcePlan) clone() RenderGraphResourcePlan {
+	out := RenderGraphResourcePlan{
+		Lifetimes: append([]RenderResourceLifetime(nil), p.Lifetimes...),
+		Slots:     make([]RenderResourceSlot, len(p.Slots)),
+	}
+	for i, slot := range p.Slots {
+		out.Slots[i] = RenderResourceSlot{Desc: slot.Desc, Resources: append([]string(nil), slot.Resources...)}
+	}
+	return out
+}
+
+// renderGraphResourceEdges derives ordering edges from resource accesses in
+// registration order: a reader runs after the latest earlier writer, and a
+// writer runs after the previous writer and every reader in between.
+func renderGraphResourceEdges(specs []RenderNodeSpec, resources map[string]RenderResourceDesc) ([][2]string, error) {
+	type resourceState struct {
+		lastWriter string
+		readers    []string
+	}
+	states := make(map[string]*resourceState, len(resources))
+	var edges [][2]string
+	for _, spec := range specs {
+		for _, name := range spec.Reads {
+			desc, ok := resources[name]
+			if !ok {
+				return nil, fmt.Errorf("render graph node %q reads undeclared resource %q", spec.Name, name)
+			}
+			state := states[name]
+			if state == nil {
+				state = &resourceState{}
+				states[name] = state
+			}
+			if state.lastWriter == "" {
+				if !desc.Imported {
+					return nil, fmt.Errorf("render graph node %q reads transient resource %q before any node writes it", spec.Name, name)
+				}
+			} else if state.lastWriter != spec.Name {
+				edges = append(edges, [2]string{state.lastWriter, spec.Name})
+			}
+			state.readers = append(state.readers, spec.Name)
+		}
+		for _, name := range spec.Writes {
+			if _, ok := resources[name]; !ok {
+				return nil, fmt.Errorf("render graph node %q writes undeclared resource %q", spec.Name, name)
+			}
+			state := states[name]
+			if state == nil {
+				state = &resourceState{}
+				states[name] = state
+			}
+			if state.lastWriter != "" && state.lastWriter != spec.Name {
+				edges = append(edges, [2]string{state.lastWriter, spec.Name})
+			}
+			for _, reader := range state.readers {
+				if reader != spec.Name {
+					edges = append(edges, [2]string{reader, spec.Name})
+				}
+			}
+			state.lastWriter = spec.Name
+			state.readers = state.readers[:0]
+		}
+	}
+	return edges, nil
+}
+
+// planRenderGraphResources computes resource lifetimes over the compiled
+// order and greedily aliases compatible transient resources whose lifetimes
+// do not overlap.
+func planRenderGraphResources(ordered []RenderNodeSpec, resources []RenderResourceDesc) RenderGraphResourcePlan {
+	first := make(map[string]int, len(resources))
+	last := make(map[string]int, len(resources))
+	for i, spec := range ordered {
+		touch := func(name string) {
+			if _, ok := first[name]; !ok {
+				first[name] = i
+			}
+			last[name] = i
+		}
+		for _, name := range spec.Reads {
+			touch(name)
+		}
+		for _, name := range spec.Writes {
+			touch(name)
+		}
+	}
+
+	lifetimes := make([]RenderResourceLifetime, 0, len(resources))
+	byName := make(map[string]RenderResourceDesc, len(resources))
+	for _, desc := range resources {
+		byName[desc.Name] = desc
+		f, used := first[desc.Name]
+		if !used {
+			continue
+		}
+		lifetimes = append(lifetimes, RenderResourceLifetime{Name: desc.Name, First: f, Last: last[desc.Name], Slot: -1})
+	}
+	sort.SliceStable(lifetimes, func(i, j int) bool {
+		if lifetimes[i].First != lifetimes[j].First {
+			return lifetimes[i].First < lifetimes[j].First
+		}
+		return lifetimes[i].Name < lifetimes[j].Name
+	})
+
+	plan := RenderGraphResourcePlan{Lifetimes: lifetimes}
+	slotLast := make([]int, 0)
+	for i := range plan.Lifetimes {
+		lifetime := &plan.Lifetimes[i]
+		desc := byName[lifetime.Name]
+		if desc.Imported {
+			continue
+		}
+		key := desc.aliasKey()
+		slot := -1
+		for s := range plan.Slots {
+			if plan.Slots[s].Desc.aliasKey() == key && slotLast[s] < lifetime.First {
+				slot = s
+				break
+			}
+		}
+		if slot < 0 {
+			slot = len(plan.Slots)
+			plan.Slots = append(plan.Slots, RenderResourceSlot{Desc: desc.normalized()})
+			slotLast = append(slotLast, -1)
+		}
+		plan.Slots[slot].Resources = append(plan.Slots[slot].Resources, lifetime.Name)
+		slotLast[slot] = lifetime.Last
+		lifetime.Slot = slot
+	}
+	return plan
+}
+
+// renderGraphAllocation is one live slot allocation.
+type renderGraphAllocation struct {
+	key           RenderResourceDesc
+	width, height uint32
+	format        wgpu.TextureFormat
+	texture       *wgpu.Texture
+	view          *wgpu.TextureView
+	buffer        *wgpu.Buffer
+}
+
+func (r *renderGraphAllocation) matches(key RenderResourceDesc, width, height uint32, format wgpu.TextureFormat) bool {
+	return r != nil && r.key == key && r.width == width && r.height == height && r.format == format
+}
+
+func (r *renderGraphAllocation) release() {
+	if r == nil {
+		return
+	}
+	if r.view != nil {
+		r.view.Release()
+		r.view = nil
+	}
+	if r.texture != nil {
+		r.texture.Release()
+		r.texture = nil
+	}
+	if r.buffer != nil {
+		r.buffer.Release()
+		r.buffer = nil
+	}
+}
+
+func createRenderGraphAllocation(device *wgpu.Device, slot RenderResourceSlot, width, height uint32, format wgpu.TextureFormat) (*renderGraphAllocation, error) {
+	alloc := &renderGraphAllocation{key: slot.Desc.aliasKey(), width: width, height: height, format: format}
+	label := fmt.Sprintf("Render Graph %s", slot.Resources[0])
+	switch slot.Desc.Kind {
+	case RenderResourceTexture:
+		tex, err := device.CreateTexture(&wgpu.TextureDescriptor{
+			Label:         label,
+			Size:          wgpu.Extent3D{Width: width, Height: height, DepthOrArrayLayers: 1},
+			MipLevelCount: 1,
+			Dimension:     wgpu.TextureDimension2D,
+			Format:        format,
+			Usage:         slot.Desc.TextureUsage,
+			SampleCount:   1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		view, err := tex.CreateView(nil)
+		if err != nil {
+			tex.Release()
+			return nil, err
+		}
+		alloc.texture, alloc.view = tex, view
+	case RenderResourceBuffer:
+		buf, err := device.CreateBuffer(&wgpu.BufferDescriptor{
+			Label: label,
+			Size:  slot.Desc.Size,
+			Usage: slot.Desc.BufferUsage,
+		})
+		if err != nil {
+			return nil, err
+		}
+		alloc.buffer = buf
+	}
+	return alloc, nil
+}
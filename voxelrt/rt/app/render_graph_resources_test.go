@@ -0,0 +1,259 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cogentcore/webgpu/wgpu"
+)
+
+func resourceTestSpec(name string, reads, writes []string, after ...string) RenderNodeSpec {
+	return RenderNodeSpec{
+		Name:   name,
+		After:  after,
+		Reads:  reads,
+		Writes: writes,
+		Node:   &testRenderNode{name: name, enabled: true},
+	}
+}
+
+func hdrTestTexture(name string) RenderResourceDesc {
+	return RenderResourceDesc{Name: name, Kind: RenderResourceTexture, Format: wgpu.TextureFormatRGBA16Float}
+}
+
+func TestRenderGraphCompileDerivesOrderFromResourceAccess(t *testing.T) {
+	graph := NewRenderGraph()
+	graph.DeclareResource(hdrTestTexture("scene"))
+	graph.DeclareResource(hdrTestTexture("bloom"))
+	// Registration order defines access order; the compiled order must keep
+	// writers before readers and readers before the next writer.
+	graph.Register(resourceTestSpec("lighting", nil, []string{"scene"}))
+	graph.Register(resourceTestSpec("bloom", []string{"scene"}, []string{"bloom"}))
+	graph.Register(resourceTestSpec("composite", []string{"scene", "bloom"}, nil))
+	graph.Register(resourceTestSpec("overwrite", nil, []string{"scene"}))
+	graph.Register(resourceTestSpec("late-reader", []string{"scene"}, nil))
+
+	ordered, err := graph.Compile()
+	if err != nil {
+		t.Fatalf("Compile returned error: %v", err)
+	}
+	got := renderNodeNames(ordered)
+	want := []string{"lighting", "bloom", "composite", "overwrite", "late-reader"}
+	if !sameStrings(got, want) {
+		t.Fatalf("compiled order = %v, want %v", got, want)
+	}
+
+	resources := map[string]RenderResourceDesc{"scene": hdrTestTexture("scene"), "bloom": hdrTestTexture("bloom")}
+	edges, err := renderGraphResourceEdges(graph.Specs(), resources)
+	if err != nil {
+		t.Fatalf("renderGraphResourceEdges returned error: %v", err)
+	}
+	has := make(map[[2]string]bool, len(edges))
+	for _, edge := range edges {
+		has[edge] = true
+	}
+	for _, edge := range [][2]string{
+		{"lighting", "bloom"},
+		{"lighting", "composite"},
+		{"bloom", "composite"},
+		{"lighting", "overwrite"},
+		{"composite", "overwrite"},
+		{"overwrite", "late-reader"},
+	} {
+		if !has[edge] {
+			t.Fatalf("missing derived edge %v in %v", edge, edges)
+		}
+	}
+	if has[[2]string{"lighting", "late-reader"}] {
+		t.Fatalf("expected late reader to depend only on the latest writer, got %v", edges)
+	}
+}
+
+func TestRenderGraphCompileReportsResourceCycleWithAfter(t *testing.T) {
+	graph := NewRenderGraph()
+	graph.DeclareResource(hdrTestTexture("scene"))
+	graph.Register(resourceTestSpec("writer", nil, []string{"scene"}, "reader"))
+	graph.Register(resourceTestSpec("reader", []string{"scene"}, nil))
+
+	_, err := graph.Compile()
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle") {
+		t.Fatalf("expected cycle between After and resource edges, got %v", err)
+	}
+}
+
+func TestRenderGraphCompileValidatesResources(t *testing.T) {
+	cases := []struct {
+		name      string
+		resources []RenderResourceDesc
+		specs     []RenderNodeSpec
+		want      string
+	}{
+		{
+			name:  "undeclared read",
+			specs: []RenderNodeSpec{resourceTestSpec("a", []string{"missing"}, nil)},
+			want:  `reads undeclared resource "missing"`,
+		},
+		{
+			name:  "undeclared write",
+			specs: []RenderNodeSpec{resourceTestSpec("a", nil, []string{"missing"})},
+			want:  `writes undeclared resource "missing"`,
+		},
+		{
+			name:      "transient read before write",
+			resources: []RenderResourceDesc{hdrTestTexture("t")},
+			specs: []RenderNodeSpec{
+				resourceTestSpec("reader", []string{"t"}, nil),
+				resourceTestSpec("writer", nil, []string{"t"}),
+			},
+			want: "before any node writes it",
+		},
+		{
+			name:      "duplicate resource",
+			resources: []RenderResourceDesc{hdrTestTexture("t"), hdrTestTexture("t")},
+			want:      "declared more than once",
+		},
+		{
+			name:      "texture without format",
+			resources: []RenderResourceDesc{{Name: "t", Kind: RenderResourceTexture}},
+			want:      "has no format",
+		},
+		{
+			name:      "half fixed size",
+			resources: []RenderResourceDesc{{Name: "t", Kind: RenderResourceTexture, Format: wgpu.TextureFormatR32Float, Width: 64}},
+			want:      "only one of width and height",
+		},
+		{
+			name:      "empty buffer",
+			resources: []RenderResourceDesc{{Name: "b", Kind: RenderResourceBuffer}},
+			want:      "zero size",
+		},
+	}
+	for _, tc := range cases {
+		graph := NewRenderGraph()
+		for _, desc := range tc.resources {
+			graph.DeclareResource(desc)
+		}
+		for _, spec := range tc.specs {
+			graph.Register(spec)
+		}
+		_, err := graph.Compile()
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	imported := NewRenderGraph()
+	imported.DeclareResource(RenderResourceDesc{Name: "swapchain", Kind: RenderResourceTexture, Imported: true})
+	imported.Register(resourceTestSpec("overlay", []string{"swapchain"}, []string{"swapchain"}))
+	if _, err := imported.Compile(); err != nil {
+		t.Fatalf("expected imported resources to be readable without a graph writer, got %v", err)
+	}
+}
+
+func TestRenderGraphResourcePlanAliasesCompatibleNonOverlappingLifetimes(t *testing.T) {
+	graph := NewRenderGraph()
+	graph.DeclareResource(hdrTestTexture("a"))
+	graph.DeclareResource(hdrTestTexture("b"))
+	graph.DeclareResource(hdrTestTexture("c"))
+	graph.DeclareResource(RenderResourceDesc{Name: "half", Kind: RenderResourceTexture, Format: wgpu.TextureFormatRGBA16Float, Scale: 0.5})
+	graph.DeclareResource(RenderResourceDesc{Name: "ext", Kind: RenderResourceTexture, Imported: true})
+	graph.DeclareResource(RenderResourceDesc{Name: "unused", Kind: RenderResourceBuffer, Size: 64})
+	graph.Register(resourceTestSpec("n0", []string{"ext"}, []string{"a"}))
+	graph.Register(resourceTestSpec("n1", []string{"a"}, []string{"b"}))
+	graph.Register(resourceTestSpec("n2", []string{"b"}, []string{"c", "half"}))
+	graph.Register(resourceTestSpec("n3", []string{"c", "half"}, nil))
+
+	plan, err := graph.ResourcePlan()
+	if err != nil {
+		t.Fatalf("ResourcePlan returned error: %v", err)
+	}
+	lifetime := func(name string) RenderResourceLifetime {
+		t.Helper()
+		got, ok := plan.Lifetime(name)
+		if !ok {
+			t.Fatalf("missing lifetime for %q in %+v", name, plan.Lifetimes)
+		}
+		return got
+	}
+	if got := lifetime("a"); got.First != 0 || got.Last != 1 {
+		t.Fatalf("lifetime a = %+v, want [0,1]", got)
+	}
+	if got := lifetime("ext"); got.Slot != -1 {
+		t.Fatalf("expected imported resource to have no slot, got %+v", got)
+	}
+	if _, ok := plan.Lifetime("unused"); ok {
+		t.Fatal("expected unused resources to be left out of the plan")
+	}
+	a, b, c, half := lifetime("a"), lifetime("b"), lifetime("c"), lifetime("half")
+	if a.Slot != c.Slot {
+		t.Fatalf("expected c to alias a after a's last use, got a=%d c=%d", a.Slot, c.Slot)
+	}
+	if a.Slot == b.Slot {
+		t.Fatalf("expected overlapping a and b in separate slots, both %d", a.Slot)
+	}
+	if half.Slot == a.Slot || half.Slot == b.Slot {
+		t.Fatalf("expected differently sized texture in its own slot, got %d", half.Slot)
+	}
+	if len(plan.Slots) != 3 {
+		t.Fatalf("slot count = %d, want 3: %+v", len(plan.Slots), plan.Slots)
+	}
+	if got := plan.Slots[a.Slot].Resources; !sameStrings(got, []string{"a", "c"}) {
+		t.Fatalf("slot resources = %v, want [a c]", got)
+	}
+}
+
+func TestRenderResourceDescExtentFollowsSwapchain(t *testing.T) {
+	half := RenderResourceDesc{Kind: RenderResourceTexture, Scale: 0.5}
+	if w, h := half.Extent(1920, 1081); w != 960 || h != 540 {
+		t.Fatalf("half extent = %dx%d, want 960x540", w, h)
+	}
+	if w, h := half.Extent(1, 1); w != 1 || h != 1 {
+		t.Fatalf("expected extent to clamp to 1, got %dx%d", w, h)
+	}
+	fixed := RenderResourceDesc{Kind: RenderResourceTexture, Scale: 0.25, Width: 256, Height: 128}
+	if w, h := fixed.Extent(1920, 1080); w != 256 || h != 128 {
+		t.Fatalf("fixed extent = %dx%d, want 256x128", w, h)
+	}
+	full := RenderResourceDesc{Kind: RenderResourceTexture}
+	if w, h := full.Extent(800, 600); w != 800 || h != 600 {
+		t.Fatalf("full extent = %dx%d, want 800x600", w, h)
+	}
+}
+
+func TestDefaultRenderGraphPlansPostProcessTransients(t *testing.T) {
+	graph := NewDefaultRenderGraph()
+	plan, err := graph.ResourcePlan()
+	if err != nil {
+		t.Fatalf("ResourcePlan returned error: %v", err)
+	}
+	ordered, _ := graph.Compile()
+	index := make(map[string]int, len(ordered))
+	for i, spec := range ordered {
+		index[spec.Name] = i
+	}
+
+	hdr, _ := plan.Lifetime(RenderResourcePostProcessHDR)
+	if hdr.Slot != -1 || hdr.First != index[RenderNodeCoreResolve] || hdr.Last != index[RenderNodePostProcessComposite] {
+		t.Fatalf("unexpected HDR lifetime %+v", hdr)
+	}
+	bloom, _ := plan.Lifetime(RenderResourcePostProcessBloom)
+	if bloom.Slot < 0 || bloom.First != index[RenderNodePostProcessBloom] || bloom.Last != index[RenderNodePostProcessComposite] {
+		t.Fatalf("unexpected bloom lifetime %+v", bloom)
+	}
+	ldr, _ := plan.Lifetime(RenderResourcePostProcessLDR)
+	if ldr.Slot < 0 || !plan.Slots[ldr.Slot].Desc.SwapchainFormat {
+		t.Fatalf("expected LDR transient in swapchain format, got %+v", ldr)
+	}
+	scratch, _ := plan.Lifetime(RenderResourcePostProcessBloomScratch)
+	if scratch.Slot == bloom.Slot {
+		t.Fatal("expected bloom ping-pong targets to stay separate while both are live")
+	}
+
+	// Without a device allocation is skipped and lookups stay nil.
+	if err := graph.AllocateResources(NewApp(nil), 1280, 720); err != nil {
+		t.Fatalf("AllocateResources without device returned error: %v", err)
+	}
+	if graph.TextureView(RenderResourcePostProcessBloom) != nil {
+		t.Fatal("expected no transient views without a device")
+	}
+}
@@ -17,9 +17,20 @@ type RenderNode interface {
 	Shutdown(*App)
 }
 
-// RenderNodeSpec declares a node and the nodes that must run before it.
+// RenderNodeSpec declares a node, the nodes that must run before it, and the
+// graph resources it reads and writes. Resource accesses add ordering edges
+// in registration order on top of After.
 type RenderNodeSpec struct {
-	Name  string
-	After []string
-	Node  RenderNode
+	Name   string
+	After  []string
+	Reads  []string
+	Writes []string
+	Node   RenderNode
+}
+
+// RenderNodeResourceBinder is implemented by nodes whose bind groups
+// reference graph-allocated resources. BindResources runs after every
+// RenderGraph.AllocateResources, including on resize.
+type RenderNodeResourceBinder interface {
+	BindResources(*App) error
 }
@@ -0,0 +1,497 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gekko3d/gekko/voxelrt/rt/core"
+
+	"github.com/cogentcore/webgpu/wgpu"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// RenderViewFeature selects optional render work for a secondary view.
+type RenderViewFeature uint32
+
+const (
+	// RenderViewSky draws astronomical bodies, planets and far scenery.
+	RenderViewSky RenderViewFeature = 1 << iota
+	RenderViewBloom
+	RenderViewAntiAliasing
+	RenderViewParticles
+	RenderViewSprites
+	RenderViewWater
+	RenderViewTransparency
+
+	RenderViewAllFeatures = RenderViewSky | RenderViewBloom | RenderViewAntiAliasing |
+		RenderViewParticles | RenderViewSprites | RenderViewWater | RenderViewTransparency
+	// RenderViewDefaultFeatures leaves out the full-screen post effects, which
+	// cost the same per view but matter little at monitor or mirror sizes.
+	RenderViewDefaultFeatures = RenderViewSky | RenderViewParticles | RenderViewSprites |
+		RenderViewWater | RenderViewTransparency
+)
+
+// RenderViewport is a swapchain rectangle in normalized coordinates with the
+// origin at the top left. The zero value covers the whole surface.
+type RenderViewport struct {
+	X, Y, Width, Height float32
+}
+
+// Normalized clamps the rectangle to the unit square; empty rectangles
+// become the full surface.
+func (v RenderViewport) Normalized() RenderViewport {
+	x0 := postProcessClamp01(v.X)
+	y0 := postProcessClamp01(v.Y)
+	x1 := postProcessClamp01(v.X + v.Width)
+	y1 := postProcessClamp01(v.Y + v.Height)
+	if x1 <= x0 || y1 <= y0 {
+		return RenderViewport{Width: 1, Height: 1}
+	}
+	return RenderViewport{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// Full reports whether the viewport covers the whole surface.
+func (v RenderViewport) Full() bool {
+	return v.Normalized() == RenderViewport{Width: 1, Height: 1}
+}
+
+// Pixels converts the viewport to a pixel rectangle of a width x height
+// target. Full viewports return an empty rectangle.
+func (v RenderViewport) Pixels(width, height uint32) FrameViewport {
+	if v.Full() {
+		return FrameViewport{}
+	}
+	n := v.Normalized()
+	return FrameViewport{
+		X:      n.X * float32(width),
+		Y:      n.Y * float32(height),
+		Width:  max(n.Width*float32(width), 1),
+		Height: max(n.Height*float32(height), 1),
+	}
+}
+
+// RenderView is an extra camera rendered before the main view each frame.
+//
+// Offscreen views (TargetKey set) render into a swapchain-format texture of
+// Width x Height that is registered as the sprite atlas TargetKey, so world
+// sprites and UI images can sample it. Other views render into Viewport of
+// the swapchain. All views share the main view's scene upload, lights and
+// shadow maps. The internal passes cover only the top-left rectangle of the
+// internal targets that matches the view's target or viewport at the render
+// scale, and the final pass scales it into the view's target.
+type RenderView struct {
+	Camera        core.CameraState
+	TargetKey     string
+	Width, Height uint32
+	Viewport      RenderViewport
+	Features      RenderViewFeature
+	Priority      int
+	// Hold keeps the previous image of an offscreen target and skips
+	// rendering the view this frame.
+	Hold bool
+}
+
+// Offscreen reports whether the view renders into its own texture.
+func (v RenderView) Offscreen() bool {
+	return v.TargetKey != ""
+}
+
+// Aspect returns the projection aspect for the view on a swapchainW x
+// swapchainH surface.
+func (v RenderView) Aspect(swapchainW, swapchainH uint32) float32 {
+	w, h := float32(swapchainW), float32(swapchainH)
+	if v.Offscreen() {
+		tw, th := v.targetExtent(swapchainW, swapchainH)
+		w, h = float32(tw), float32(th)
+	} else {
+		n := v.Viewport.Normalized()
+		w, h = w*n.Width, h*n.Height
+	}
+	if w <= 0 || h <= 0 {
+		return 1
+	}
+	return w / h
+}
+
+func (v RenderView) targetExtent(swapchainW, swapchainH uint32) (uint32, uint32) {
+	if v.Width > 0 && v.Height > 0 {
+		return v.Width, v.Height
+	}
+	return max(swapchainW, 1), max(swapchainH, 1)
+}
+
+// renderExtent returns the size of the view's render rect: its target or
+// viewport size at renderScale, clamped to the internalW x internalH
+// targets it renders into.
+func (v RenderView) renderExtent(swapchainW, swapchainH, internalW, internalH uint32, renderScale float32) (uint32, uint32) {
+	var w, h uint32
+	if v.Offscreen() {
+		w, h = v.targetExtent(swapchainW, swapchainH)
+	} else {
+		n := v.Viewport.Normalized()
+		w = uint32(float32(swapchainW)*n.Width + 0.5)
+		h = uint32(float32(swapchainH)*n.Height + 0.5)
+	}
+	w, h = scaledRenderExtent(max(w, 1), max(h, 1), renderScale)
+	return min(w, max(internalW, 1)), min(h, max(internalH, 1))
+}
+
+// runsRenderNode reports whether a graph node records for this view. Views
+// reuse the main view's simulation, Hi-Z, shadow and volumetric history
+// work, and never draw overlays or custom nodes.
+func (v RenderView) runsRenderNode(name string) bool {
+	switch name {
+	case RenderNodeCoreGBuffer,
+		RenderNodeCoreTiledLightCull,
+		RenderNodeCoreLighting,
+		RenderNodeFeatureCAVolumesRender,
+		RenderNodeCoreAccumulation,
+		RenderNodeCoreResolve,
+		RenderNodePostProcessComposite:
+		return true
+	case RenderNodeFeatureAstronomical, RenderNodeFeaturePlanetBodies:
+		return v.Features&RenderViewSky != 0
+	case RenderNodePostProcessBloom:
+		return v.Features&RenderViewBloom != 0
+	case RenderNodePostProcessAntiAlias:
+		return v.Features&RenderViewAntiAliasing != 0
+	default:
+		return false
+	}
+}
+
+// allowsFeature reports whether a feature contributes to this view's
+// accumulation pass.
+func (v RenderView) allowsFeature(name string) bool {
+	switch name {
+	case "particles":
+		return v.Features&RenderViewParticles != 0
+	case "sprites":
+		return v.Features&RenderViewSprites != 0
+	case "water":
+		return v.Features&RenderViewWater != 0
+	case "transparency":
+		return v.Features&RenderViewTransparency != 0
+	case "far_planet_ring", "debris_midfield":
+		return v.Features&RenderViewSky != 0
+	default:
+		return false
+	}
+}
+
+// renderViewSchedule returns the indices of the views to render this frame,
+// ordered by priority and then by index.
+func renderViewSchedule(views []RenderView) []int {
+	order := make([]int, 0, len(views))
+	for i, view := range views {
+		if view.Hold {
+			continue
+		}
+		order = append(order, i)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return views[order[i]].Priority < views[order[j]].Priority
+	})
+	return order
+}
+
+// SetRenderViews replaces the secondary views. The order is kept so
+// RenderViewVisibleObjects indices match the caller's.
+func (a *App) SetRenderViews(views []RenderView) {
+	if a == nil {
+		return
+	}
+	a.RenderViews = append(a.RenderViews[:0], views...)
+}
+
+// RenderViewVisibleObjects returns the objects inside view i's frustum as of
+// the last Update.
+func (a *App) RenderViewVisibleObjects(i int) []*core.VoxelObject {
+	if a == nil || a.Scene == nil || i < 0 || i >= len(a.Scene.ViewVisibleObjects) {
+		return nil
+	}
+	return a.Scene.ViewVisibleObjects[i]
+}
+
+// RenderViewTargetView returns the texture view of the offscreen target key.
+func (a *App) RenderViewTargetView(key string) *wgpu.TextureView {
+	if a == nil {
+		return nil
+	}
+	if target := a.renderViewTargets[key]; target != nil {
+		return target.view
+	}
+	return nil
+}
+
+func (a *App) renderViewRunsNode(name string) bool {
+	return a == nil || a.activeRenderView == nil || a.activeRenderView.runsRenderNode(name)
+}
+
+func (a *App) renderViewAllowsFeature(name string) bool {
+	return a == nil || a.activeRenderView == nil || a.activeRenderView.allowsFeature(name)
+}
+
+func (a *App) renderViewHasFeature(feature RenderViewFeature) bool {
+	return a == nil || a.activeRenderView == nil || a.activeRenderView.Features&feature != 0
+}
+
+// renderCamera returns the camera of the view being recorded.
+func (a *App) renderCamera() *core.CameraState {
+	if a.activeRenderView != nil {
+		return &a.activeRenderView.Camera
+	}
+	return a.Camera
+}
+
+// activeRenderExtent returns the render rect of the view being recorded. The
+// main view covers the internal targets.
+func (a *App) activeRenderExtent() (uint32, uint32) {
+	width, height := a.renderExtent()
+	if a == nil || a.activeRenderView == nil || a.Config == nil {
+		return width, height
+	}
+	return a.activeRenderView.renderExtent(a.Config.Width, a.Config.Height, width, height, a.RenderScale())
+}
+
+// renderRectViewport returns the active render rect inside a texW x texH
+// target that is sized relative to the internal extent. It is empty when the
+// rect covers the whole target.
+func (a *App) renderRectViewport(texW, texH uint32) FrameViewport {
+	width, height := a.renderExtent()
+	rectW, rectH := a.activeRenderExtent()
+	if width == 0 || height == 0 || (rectW >= width && rectH >= height) {
+		return FrameViewport{}
+	}
+	return FrameViewport{
+		Width:  float32(texW) * float32(rectW) / float32(width),
+		Height: float32(texH) * float32(rectH) / float32(height),
+	}
+}
+
+// renderRectUVScale returns the active render rect as a fraction of the
+// internal targets.
+func (a *App) renderRectUVScale() [2]float32 {
+	width, height := a.renderExtent()
+	rectW, rectH := a.activeRenderExtent()
+	if width == 0 || height == 0 {
+		return [2]float32{1, 1}
+	}
+	return [2]float32{float32(rectW) / float32(width), float32(rectH) / float32(height)}
+}
+
+// setRenderRectViewport limits pass to the active render rect of a texW x
+// texH target.
+func (a *App) setRenderRectViewport(pass *wgpu.RenderPassEncoder, texW, texH uint32) {
+	if viewport := a.renderRectViewport(texW, texH); !viewport.Empty() {
+		pass.SetViewport(viewport.X, viewport.Y, viewport.Width, viewport.Height, 0, 1)
+	}
+}
+
+// setInternalRenderRectViewport limits pass to the active render rect of an
+// internal-resolution target.
+func (a *App) setInternalRenderRectViewport(pass *wgpu.RenderPassEncoder) {
+	width, height := a.renderExtent()
+	a.setRenderRectViewport(pass, width, height)
+}
+
+// mainViewAspect is the projection aspect of the main camera inside
+// PrimaryViewport.
+func (a *App) mainViewAspect() float32 {
+	if a.Config == nil {
+		return 1
+	}
+	return RenderView{Viewport: a.PrimaryViewport}.Aspect(a.Config.Width, a.Config.Height)
+}
+
+// renderViewFrustums returns each view's frustum planes in RenderViews order.
+func (a *App) renderViewFrustums() [][6]mgl32.Vec4 {
+	if len(a.RenderViews) == 0 || a.Config == nil {
+		return nil
+	}
+	frustums := make([][6]mgl32.Vec4, len(a.RenderViews))
+	for i := range a.RenderViews {
+		view := &a.RenderViews[i]
+		proj := view.Camera.ProjectionMatrix(view.Aspect(a.Config.Width, a.Config.Height))
+		frustums[i] = view.Camera.ExtractFrustum(proj.Mul4(view.Camera.GetViewMatrix()))
+	}
+	return frustums
+}
+
+// writeCameraUniforms uploads the camera uniform block for camera and the
+// screen size of the active render rect. Views keep the main camera's render
+// origin, since the scene upload is relative to it.
+func (a *App) writeCameraUniforms(camera *core.CameraState, aspect float32) (viewProj, invView mgl32.Mat4) {
+	lightPos := mgl32.Vec3{500, 1000, 500}
+	sunIntensity := float32(1.0)
+	if len(a.Scene.Lights) > 0 {
+		lp := a.Scene.Lights[0].Position
+		lightPos = mgl32.Vec3{lp[0], lp[1], lp[2]}
+		sunIntensity = a.Scene.Lights[0].Color[3]
+		if sunIntensity < 0 {
+			sunIntensity = 0
+		}
+	}
+	view := camera.GetViewMatrix()
+	proj := camera.ProjectionMatrix(aspect)
+	viewProj = proj.Mul4(view)
+	invView = view.Inv()
+	renderWidth, renderHeight := a.activeRenderExtent()
+	a.BufferManager.UpdateCamera(viewProj, invView, proj.Inv(), camera.Position, lightPos, a.Scene.AmbientLight, a.Camera.Position, sunIntensity, a.Scene.SkyAmbientMix, camera.FarPlane(), camera.DebugMode, a.RenderMode, uint32(len(a.Scene.Lights)), renderWidth, renderHeight, a.EffectiveLightingQuality())
+	a.BufferManager.SetTiledLightingScreenExtent(renderWidth, renderHeight)
+	return viewProj, invView
+}
+
+// renderSecondaryViews records and submits every scheduled view before the
+// main frame, then restores the main camera uniforms. It reports whether a
+// view drew into the swapchain.
+func (a *App) renderSecondaryViews(swapchainView *wgpu.TextureView) bool {
+	if a == nil || len(a.RenderViews) == 0 || a.Device == nil || a.BufferManager == nil {
+		return false
+	}
+	a.prepareRenderViewTargets()
+
+	drewSwapchain := false
+	for _, i := range renderViewSchedule(a.RenderViews) {
+		view := &a.RenderViews[i]
+		a.activeRenderView = view
+		frame := a.newFrameContext(nil)
+		a.activeRenderView = nil
+		if view.Offscreen() {
+			target := a.renderViewTargets[view.TargetKey]
+			if target == nil {
+				continue
+			}
+			frame.SwapchainView = target.view
+		} else {
+			frame.SwapchainView = swapchainView
+			frame.Viewport = view.Viewport.Pixels(a.Config.Width, a.Config.Height)
+			frame.LoadTarget = drewSwapchain
+		}
+
+		encoder, err := a.Device.CreateCommandEncoder(nil)
+		if err != nil {
+			fmt.Printf("ERROR: Render view CreateCommandEncoder failed: %v\n", err)
+			break
+		}
+		// The camera buffer is shared, so every view needs its own submit
+		// after its uniforms are written.
+		a.activeRenderView = view
+		a.writeCameraUniforms(&view.Camera, view.Aspect(a.Config.Width, a.Config.Height))
+		a.recordRenderGraph(encoder, frame)
+		a.activeRenderView = nil
+		cmd, err := encoder.Finish(nil)
+		if err != nil {
+			fmt.Printf("ERROR: Render view encoder Finish failed: %v\n", err)
+			continue
+		}
+		a.Queue.Submit(cmd)
+		if !view.Offscreen() {
+			drewSwapchain = true
+		}
+	}
+
+	a.writeCameraUniforms(a.Camera, a.mainViewAspect())
+	return drewSwapchain
+}
+
+// renderViewTarget is an offscreen view's color target.
+type renderViewTarget struct {
+	texture       *wgpu.Texture
+	view          *wgpu.TextureView
+	width, height uint32
+	format        wgpu.TextureFormat
+}
+
+func (t *renderViewTarget) release() {
+	if t == nil {
+		return
+	}
+	if t.view != nil {
+		t.view.Release()
+		t.view = nil
+	}
+	if t.texture != nil {
+		t.texture.Release()
+		t.texture = nil
+	}
+}
+
+// prepareRenderViewTargets creates or resizes the offscreen targets of the
+// current views and drops targets no view uses. Replaced targets are
+// released one frame later, after the sprite batches have rebound.
+func (a *App) prepareRenderViewTargets() {
+	for _, target := range a.retiredRenderViewTargets {
+		target.release()
+	}
+	a.retiredRenderViewTargets = a.retiredRenderViewTargets[:0]
+
+	used := make(map[string]bool, len(a.RenderViews))
+	for _, view := range a.RenderViews {
+		if !view.Offscreen() || used[view.TargetKey] {
+			continue
+		}
+		used[view.TargetKey] = true
+		width, height := view.targetExtent(a.Config.Width, a.Config.Height)
+		existing := a.renderViewTargets[view.TargetKey]
+		if existing != nil && existing.width == width && existing.height == height && existing.format == a.Config.Format {
+			continue
+		}
+		target, err := a.createRenderViewTarget(view.TargetKey, width, height)
+		if err != nil {
+			fmt.Printf("ERROR: Render view target %q creation failed: %v\n", view.TargetKey, err)
+			continue
+		}
+		if existing != nil {
+			a.retiredRenderViewTargets = append(a.retiredRenderViewTargets, existing)
+		}
+		if a.renderViewTargets == nil {
+			a.renderViewTargets = make(map[string]*renderViewTarget)
+		}
+		a.renderViewTargets[view.TargetKey] = target
+		a.BufferManager.SetExternalSpriteAtlas(view.TargetKey, target.texture, target.view, width, height, target.format)
+	}
+	for key, target := range a.renderViewTargets {
+		if used[key] {
+			continue
+		}
+		delete(a.renderViewTargets, key)
+		a.BufferManager.RemoveSpriteAtlas(key)
+		a.retiredRenderViewTargets = append(a.retiredRenderViewTargets, target)
+	}
+}
+
+func (a *App) createRenderViewTarget(key string, width, height uint32) (*renderViewTarget, error) {
+	tex, err := a.Device.CreateTexture(&wgpu.TextureDescriptor{
+		Label:         fmt.Sprintf("Render View %s", key),
+		Size:          wgpu.Extent3D{Width: width, Height: height, DepthOrArrayLayers: 1},
+		MipLevelCount: 1,
+		SampleCount:   1,
+		Dimension:     wgpu.TextureDimension2D,
+		Format:        a.Config.Format,
+		Usage:         wgpu.TextureUsageRenderAttachment | wgpu.TextureUsageTextureBinding | wgpu.TextureUsageCopySrc,
+	})
+	if err != nil {
+		return nil, err
+	}
+	view, err := tex.CreateView(nil)
+	if err != nil {
+		tex.Release()
+		return nil, err
+	}
+	return &renderViewTarget{texture: tex, view: view, width: width, height: height, format: a.Config.Format}, nil
+}
+
+func (a *App) releaseRenderViewTargets() {
+	for key, target := range a.renderViewTargets {
+		if a.BufferManager != nil {
+			a.BufferManager.RemoveSpriteAtlas(key)
+		}
+		target.release()
+	}
+	a.renderViewTargets = nil
+	for _, target := range a.retiredRenderViewTargets {
+		target.release()
+	}
+	a.retiredRenderViewTargets = nil
+}
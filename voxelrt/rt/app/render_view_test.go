@@ -0,0 +1,218 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/gekko3d/gekko/voxelrt/rt/core"
+
+	"github.com/cogentcore/webgpu/wgpu"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestRenderViewportNormalizesAndConvertsToPixels(t *testing.T) {
+	if got := (RenderViewport{}).Normalized(); got != (RenderViewport{Width: 1, Height: 1}) {
+		t.Fatalf("zero viewport normalized to %+v, want full surface", got)
+	}
+	if !(RenderViewport{}).Full() {
+		t.Fatal("expected zero viewport to cover the full surface")
+	}
+	if got := (RenderViewport{}).Pixels(1920, 1080); !got.Empty() {
+		t.Fatalf("expected full viewport to need no pixel rectangle, got %+v", got)
+	}
+
+	right := RenderViewport{X: 0.5, Width: 0.75, Height: 1}
+	if got := right.Normalized(); got != (RenderViewport{X: 0.5, Width: 0.5, Height: 1}) {
+		t.Fatalf("expected viewport to be clamped to the surface, got %+v", got)
+	}
+	if got := right.Pixels(1920, 1080); got != (FrameViewport{X: 960, Width: 960, Height: 1080}) {
+		t.Fatalf("right half pixels = %+v", got)
+	}
+}
+
+func TestRenderViewAspectFollowsTargetOrViewport(t *testing.T) {
+	offscreen := RenderView{TargetKey: "mirror", Width: 512, Height: 256}
+	if got := offscreen.Aspect(1920, 1080); got != 2 {
+		t.Fatalf("offscreen aspect = %v, want 2", got)
+	}
+	sized := RenderView{TargetKey: "monitor"}
+	if w, h := sized.targetExtent(1280, 720); w != 1280 || h != 720 {
+		t.Fatalf("expected unsized target to follow the swapchain, got %dx%d", w, h)
+	}
+	split := RenderView{Viewport: RenderViewport{Width: 0.5, Height: 1}}
+	if got := split.Aspect(1920, 1080); mgl32.Abs(got-960.0/1080.0) > 1e-5 {
+		t.Fatalf("split-screen aspect = %v, want %v", got, 960.0/1080.0)
+	}
+}
+
+func TestRenderViewRendersIntoRectOfItsTargetSize(t *testing.T) {
+	a := &App{Config: &wgpu.SurfaceConfiguration{Width: 1920, Height: 1080}}
+	if w, h := a.activeRenderExtent(); w != 1920 || h != 1080 {
+		t.Fatalf("main view extent = %dx%d, want the internal extent", w, h)
+	}
+	if got := a.renderRectViewport(960, 540); !got.Empty() {
+		t.Fatalf("expected the main view to need no viewport, got %+v", got)
+	}
+
+	mirror := RenderView{TargetKey: "mirror", Width: 512, Height: 256}
+	a.activeRenderView = &mirror
+	if w, h := a.activeRenderExtent(); w != 512 || h != 256 {
+		t.Fatalf("mirror extent = %dx%d, want its target size", w, h)
+	}
+	if got := a.renderRectViewport(960, 540); got != (FrameViewport{Width: 256, Height: 128}) {
+		t.Fatalf("mirror viewport in a half-size target = %+v", got)
+	}
+	if got := a.renderRectUVScale(); mgl32.Abs(got[0]-512.0/1920.0) > 1e-6 || mgl32.Abs(got[1]-256.0/1080.0) > 1e-6 {
+		t.Fatalf("mirror uv scale = %v", got)
+	}
+
+	a.renderScale = 0.5
+	if w, h := a.activeRenderExtent(); w != 256 || h != 128 {
+		t.Fatalf("scaled mirror extent = %dx%d, want 256x128", w, h)
+	}
+	a.renderScale = 1
+
+	large := RenderView{TargetKey: "poster", Width: 4096, Height: 512}
+	a.activeRenderView = &large
+	if w, h := a.activeRenderExtent(); w != 1920 || h != 512 {
+		t.Fatalf("expected a large target to clamp to the internal extent, got %dx%d", w, h)
+	}
+
+	split := RenderView{Viewport: RenderViewport{X: 0.5, Width: 0.5, Height: 1}}
+	a.activeRenderView = &split
+	if w, h := a.activeRenderExtent(); w != 960 || h != 1080 {
+		t.Fatalf("split-screen extent = %dx%d, want 960x1080", w, h)
+	}
+}
+
+func TestRenderViewScheduleOrdersByPriorityAndSkipsHeldViews(t *testing.T) {
+	views := []RenderView{
+		{TargetKey: "a", Priority: 2},
+		{TargetKey: "b", Priority: 0, Hold: true},
+		{TargetKey: "c", Priority: 1},
+		{TargetKey: "d", Priority: 1},
+	}
+	got := renderViewSchedule(views)
+	want := []int{2, 3, 0}
+	if len(got) != len(want) {
+		t.Fatalf("schedule = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("schedule = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRenderViewGatesGraphNodesAndFeatures(t *testing.T) {
+	view := RenderView{Features: RenderViewDefaultFeatures}
+	for _, name := range []string{RenderNodeCoreGBuffer, RenderNodeCoreLighting, RenderNodeCoreResolve, RenderNodePostProcessComposite, RenderNodeFeatureAstronomical} {
+		if !view.runsRenderNode(name) {
+			t.Fatalf("expected default view to run %q", name)
+		}
+	}
+	for _, name := range []string{RenderNodeCoreShadows, RenderNodeCoreHiZ, RenderNodeFeatureParticlesSim, RenderNodeFeatureAnalyticMedia, RenderNodeFeatureTextOverlay, RenderNodePostProcessBloom, RenderNodePostProcessAntiAlias, "custom"} {
+		if view.runsRenderNode(name) {
+			t.Fatalf("expected default view to skip %q", name)
+		}
+	}
+	if !view.allowsFeature("sprites") || view.allowsFeature("custom-feature") {
+		t.Fatal("expected views to take built-in contributors by mask and skip unknown ones")
+	}
+	bare := RenderView{}
+	if bare.runsRenderNode(RenderNodeFeaturePlanetBodies) || bare.allowsFeature("water") || bare.allowsFeature("debris_midfield") {
+		t.Fatal("expected an empty mask to skip optional work")
+	}
+
+	app := NewApp(nil)
+	app.PostProcess.BloomIntensity = 0.5
+	app.PostProcess.AntiAliasing = AntiAliasingFXAA
+	if !app.postProcessBloomPassEnabled() || !app.renderViewRunsNode("custom") || !app.renderViewAllowsFeature("custom-feature") {
+		t.Fatal("expected the main view to run everything")
+	}
+	app.activeRenderView = &view
+	if app.postProcessBloomPassEnabled() || app.postProcessAntiAliasPassEnabled() {
+		t.Fatal("expected view mask to disable bloom and anti-aliasing")
+	}
+	all := RenderView{Features: RenderViewAllFeatures}
+	app.activeRenderView = &all
+	if !app.postProcessBloomPassEnabled() || !app.postProcessAntiAliasPassEnabled() {
+		t.Fatal("expected full mask to keep bloom and anti-aliasing")
+	}
+}
+
+func TestRenderGraphRecordSkipsNodesOutsideActiveView(t *testing.T) {
+	var calls []string
+	graph := NewRenderGraph()
+	for _, name := range []string{RenderNodeCoreShadows, RenderNodeCoreLighting, RenderNodePostProcessComposite, "custom"} {
+		graph.Register(RenderNodeSpec{Name: name, Node: &testRenderNode{name: name, enabled: true, calls: &calls}})
+	}
+	app := NewApp(nil)
+	app.activeRenderView = &RenderView{}
+	if err := graph.Record(app, nil, &FrameContext{}); err != nil {
+		t.Fatalf("Record returned error: %v", err)
+	}
+	if !sameStrings(calls, []string{RenderNodeCoreLighting, RenderNodePostProcessComposite}) {
+		t.Fatalf("view recorded %v", calls)
+	}
+
+	calls = calls[:0]
+	app.activeRenderView = nil
+	if err := graph.Record(app, nil, &FrameContext{}); err != nil {
+		t.Fatalf("Record returned error: %v", err)
+	}
+	if len(calls) != 4 {
+		t.Fatalf("main view recorded %v, want all nodes", calls)
+	}
+}
+
+func TestFrameContextFinalPassLoadsAndLimitsOnlyTheSharedTarget(t *testing.T) {
+	swapchain := &wgpu.TextureView{}
+	intermediate := &wgpu.TextureView{}
+	frame := &FrameContext{
+		SwapchainView: swapchain,
+		Viewport:      FrameViewport{X: 960, Width: 960, Height: 1080},
+		LoadTarget:    true,
+	}
+	if frame.finalPassLoadOp(swapchain) != wgpu.LoadOpLoad || frame.finalPassViewport(swapchain).Empty() {
+		t.Fatal("expected the swapchain pass to load and use the view rectangle")
+	}
+	if frame.finalPassLoadOp(intermediate) != wgpu.LoadOpClear || !frame.finalPassViewport(intermediate).Empty() {
+		t.Fatal("expected intermediate targets to clear and cover the whole texture")
+	}
+	frame.LoadTarget = false
+	if frame.finalPassLoadOp(swapchain) != wgpu.LoadOpClear {
+		t.Fatal("expected the first view on the swapchain to clear it")
+	}
+}
+
+func TestRenderViewFrustumsCullPerView(t *testing.T) {
+	app := NewApp(nil)
+	app.Config = &wgpu.SurfaceConfiguration{Width: 800, Height: 600}
+
+	obj := core.NewVoxelObject()
+	obj.Transform.Position = mgl32.Vec3{0, 0, -20}
+	obj.XBrickMap.SetVoxel(0, 0, 0, 1)
+	app.Scene.AddObject(obj)
+
+	toward := *core.NewCameraState()
+	toward.Position = mgl32.Vec3{0, 0, 10}
+	toward.LookAt = mgl32.Vec3{0, 0, -20}
+	away := toward
+	away.LookAt = mgl32.Vec3{0, 0, 40}
+	app.SetRenderViews([]RenderView{{TargetKey: "toward", Camera: toward}, {TargetKey: "away", Camera: away}})
+
+	frustums := app.renderViewFrustums()
+	if len(frustums) != 2 {
+		t.Fatalf("expected a frustum per view, got %d", len(frustums))
+	}
+	app.Scene.Commit(frustums[0], core.SceneCommitOptions{ViewFrustums: frustums})
+	if got := app.RenderViewVisibleObjects(0); len(got) != 1 || got[0] != obj {
+		t.Fatalf("expected first view to see the object, got %d", len(got))
+	}
+	if got := app.RenderViewVisibleObjects(1); len(got) != 0 {
+		t.Fatalf("expected second view to see nothing, got %d", len(got))
+	}
+	if app.RenderViewVisibleObjects(2) != nil {
+		t.Fatal("expected out-of-range view to return nil")
+	}
+}
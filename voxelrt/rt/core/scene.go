@@ -40,6 +40,12 @@ type SceneCommitOptions struct {
 	FastCameraMotion bool
 	DepthSlack       float32
 	Profiler         *Profiler
+	// ViewFrustums are secondary camera frustums (render-to-texture and
+	// split-screen views). Objects inside any of them stay visible for the
+	// shared scene upload even when the main camera culls them, and are
+	// listed per view in Scene.ViewVisibleObjects. Hi-Z occlusion only
+	// applies to the main camera.
+	ViewFrustums [][6]mgl32.Vec4
 }
 
 type OcclusionStats struct {
@@ -175,6 +181,9 @@ type Scene struct {
 	Objects                   []*VoxelObject
 	VisibleObjects            []*VoxelObject
 	TransparentVisibleObjects []*VoxelObject
+	// ViewVisibleObjects holds the frustum-culled objects of each
+	// SceneCommitOptions.ViewFrustums entry, in the same order.
+	ViewVisibleObjects        [][]*VoxelObject
 	ShadowObjects             []*VoxelObject
 	BVHNodesBytes             []byte // Linearized BVH nodes
 	TransparentBVHNodesBytes  []byte
@@ -387,6 +396,7 @@ func (s *Scene) Commit(planes [6]mgl32.Vec4, opts SceneCommitOptions) {
 	s.VisibleObjects = s.VisibleObjects[:0] // Clear but keep capacity
 	s.TransparentVisibleObjects = s.TransparentVisibleObjects[:0]
 	s.OcclusionStats = OcclusionStats{}
+	s.resetViewVisibleObjects(len(opts.ViewFrustums))
 
 	depthSlack := opts.DepthSlack
 	if depthSlack <= 0 {
@@ -407,9 +417,14 @@ func (s *Scene) Commit(planes [6]mgl32.Vec4, opts SceneCommitOptions) {
 			continue
 		}
 
+		inSecondaryView := s.appendViewVisibleObject(obj, opts.ViewFrustums)
+
 		// 1. Frustum Culling
 		if !AABBInFrustum(*obj.WorldAABB, planes) {
 			s.lastVisibility[obj] = false
+			if inSecondaryView {
+				s.appendVisibleObject(obj)
+			}
 			continue
 		}
 		s.OcclusionStats.FrustumVisible++
@@ -431,14 +446,14 @@ func (s *Scene) Commit(planes [6]mgl32.Vec4, opts SceneCommitOptions) {
 			} else {
 				s.OcclusionStats.HiZCulled++
 				s.lastVisibility[obj] = false
+				if inSecondaryView {
+					s.appendVisibleObject(obj)
+				}
 				continue
 			}
 		}
 
-		s.VisibleObjects = append(s.VisibleObjects, obj)
-		if obj.HasTransparency() {
-			s.TransparentVisibleObjects = append(s.TransparentVisibleObjects, obj)
-		}
+		s.appendVisibleObject(obj)
 		s.lastVisibility[obj] = true
 		if !occluded {
 			delete(s.occlusionWarmup, obj)
@@ -582,6 +597,36 @@ func (s *Scene) Commit(planes [6]mgl32.Vec4, opts SceneCommitOptions) {
 	opts.Profiler.EndScope("Commit: Shadows")
 }
 
+func (s *Scene) appendVisibleObject(obj *VoxelObject) {
+	s.VisibleObjects = append(s.VisibleObjects, obj)
+	if obj.HasTransparency() {
+		s.TransparentVisibleObjects = append(s.TransparentVisibleObjects, obj)
+	}
+}
+
+func (s *Scene) resetViewVisibleObjects(count int) {
+	for len(s.ViewVisibleObjects) < count {
+		s.ViewVisibleObjects = append(s.ViewVisibleObjects, nil)
+	}
+	s.ViewVisibleObjects = s.ViewVisibleObjects[:count]
+	for i := range s.ViewVisibleObjects {
+		s.ViewVisibleObjects[i] = s.ViewVisibleObjects[i][:0]
+	}
+}
+
+// appendViewVisibleObject records obj in every secondary view whose frustum
+// contains it and reports whether any did.
+func (s *Scene) appendViewVisibleObject(obj *VoxelObject, frustums [][6]mgl32.Vec4) bool {
+	visible := false
+	for i, frustum := range frustums {
+		if AABBInFrustum(*obj.WorldAABB, frustum) {
+			s.ViewVisibleObjects[i] = append(s.ViewVisibleObjects[i], obj)
+			visible = true
+		}
+	}
+	return visible
+}
+
 func sceneHasShadowCastingLights(lights []Light) bool {
 	for _, light := range lights {
 		if sceneLightCastsValidShadows(light) {
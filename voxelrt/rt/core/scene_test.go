@@ -732,3 +732,69 @@ func closeEnough(a, b, epsilon float32) bool {
 	}
 	return diff < epsilon
 }
+
+func TestSceneCommitListsObjectsPerSecondaryView(t *testing.T) {
+	scene := NewScene()
+
+	front := NewVoxelObject()
+	front.Transform.Position = mgl32.Vec3{0, 0, -20}
+	front.XBrickMap.SetVoxel(0, 0, 0, 1)
+	scene.AddObject(front)
+
+	behind := NewVoxelObject()
+	behind.Transform.Position = mgl32.Vec3{0, 0, 20}
+	behind.XBrickMap.SetVoxel(0, 0, 0, 1)
+	scene.AddObject(behind)
+
+	proj := mgl32.Perspective(mgl32.DegToRad(90), 1.0, 1.0, 100.0)
+	rearView := proj.Mul4(mgl32.LookAtV(mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 0, 1}, mgl32.Vec3{0, 1, 0}))
+	rearPlanes := (&CameraState{}).ExtractFrustum(rearView)
+
+	hiz := make([]float32, 16)
+	for i := range hiz {
+		hiz[i] = 1.0
+	}
+	scene.Commit(testSceneFrustumPlanes(), SceneCommitOptions{
+		ViewFrustums: [][6]mgl32.Vec4{testSceneFrustumPlanes(), rearPlanes},
+	})
+
+	if len(scene.ViewVisibleObjects) != 2 {
+		t.Fatalf("expected one visible list per view, got %d", len(scene.ViewVisibleObjects))
+	}
+	if got := scene.ViewVisibleObjects[0]; len(got) != 1 || got[0] != front {
+		t.Fatalf("expected forward view to see only the front object, got %d objects", len(got))
+	}
+	if got := scene.ViewVisibleObjects[1]; len(got) != 1 || got[0] != behind {
+		t.Fatalf("expected rear view to see only the rear object, got %d objects", len(got))
+	}
+	if len(scene.VisibleObjects) != 2 {
+		t.Fatalf("expected shared upload to include objects seen by any view, got %d", len(scene.VisibleObjects))
+	}
+
+	// Hi-Z is main-camera only: a main-camera occluded object stays uploaded
+	// while a secondary view can see it.
+	for i := 0; i <= occlusionWarmupFrames; i++ {
+		scene.Commit(testSceneFrustumPlanes(), SceneCommitOptions{
+			OcclusionMode: OcclusionConservative,
+			HiZData:       hiz,
+			HiZW:          4,
+			HiZH:          4,
+			LastViewProj:  testSceneViewProj(),
+			ViewFrustums:  [][6]mgl32.Vec4{testSceneFrustumPlanes()},
+		})
+	}
+	if scene.OcclusionStats.HiZCulled != 1 {
+		t.Fatalf("expected main camera to occlusion-cull the front object, got %d culled", scene.OcclusionStats.HiZCulled)
+	}
+	if len(scene.VisibleObjects) != 1 || scene.VisibleObjects[0] != front {
+		t.Fatalf("expected secondary view to keep the occluded object uploaded, got %d visible", len(scene.VisibleObjects))
+	}
+	if len(scene.ViewVisibleObjects) != 1 {
+		t.Fatalf("expected view lists to shrink with the view count, got %d", len(scene.ViewVisibleObjects))
+	}
+
+	scene.Commit(testSceneFrustumPlanes(), SceneCommitOptions{})
+	if len(scene.ViewVisibleObjects) != 0 || len(scene.VisibleObjects) != 1 {
+		t.Fatalf("expected no view lists without view frustums, got %d views and %d visible", len(scene.ViewVisibleObjects), len(scene.VisibleObjects))
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
 	"golang.org/x/image/math/fixed"
 )
 
@@ -27,6 +28,10 @@ type TextItem struct {
 type RectItem struct {
 	X, Y, W, H float32
 	Color      [4]float32
+	// Texture names a sprite atlas sampled over UV (u0, v0, u1, v1) and
+	// tinted by Color. Empty draws a solid rect.
+	Texture string
+	UV      [4]float32
 }
 
 type GlyphInfo struct {
@@ -42,83 +47,223 @@ type TextRenderer struct {
 	Glyphs       map[rune]GlyphInfo
 	Face         font.Face
 	WhitePixelUV [2]float32
+	// AtlasDirty is set when glyphs were added to AtlasImage after the last
+	// upload. The atlas may also have grown, so the texture size must be
+	// checked.
+	AtlasDirty bool
+
+	fontSize float64
+	// fonts are tried in order for a glyph; the first is the primary font
+	// and the rest are fallbacks for scripts it does not cover.
+	fonts   []textFont
+	missing map[rune]bool
+	buf     sfnt.Buffer
+	packX   int
+	packY   int
+	packRow int
 }
 
+type textFont struct {
+	font *sfnt.Font
+	face font.Face
+}
+
+const (
+	textAtlasInitialSize = 512
+	textAtlasMaxSize     = 4096
+	textAtlasGlyphGap    = 4
+)
+
 func NewTextRenderer(fontPath string, fontSize float64) (*TextRenderer, error) {
 	fontBytes, err := os.ReadFile(fontPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read font file: %w", err)
 	}
 
-	f, err := opentype.Parse(fontBytes)
+	f, err := parseTextFont(fontBytes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse font: %w", err)
 	}
 
-	face, err := opentype.NewFace(f, &opentype.FaceOptions{
-		Size:    fontSize,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
+	face, err := newTextFace(f, fontSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create face: %w", err)
 	}
 
-	const atlasSize = 512
-	atlas := image.NewAlpha(image.Rect(0, 0, atlasSize, atlasSize))
-	glyphs := make(map[rune]GlyphInfo)
+	atlas := image.NewAlpha(image.Rect(0, 0, textAtlasInitialSize, textAtlasInitialSize))
 
 	// Reserve (0,0) for white pixel (used for rects)
 	atlas.Pix[0] = 255
-	whitePixelUV := [2]float32{0.5 / atlasSize, 0.5 / atlasSize}
 
-	x, y := 2, 2
-	rowHeight := 0
+	tr := &TextRenderer{
+		AtlasImage:   atlas,
+		Glyphs:       make(map[rune]GlyphInfo),
+		Face:         face,
+		WhitePixelUV: [2]float32{0.5 / textAtlasInitialSize, 0.5 / textAtlasInitialSize},
+		fontSize:     fontSize,
+		fonts:        []textFont{{font: f, face: face}},
+		missing:      make(map[rune]bool),
+		packX:        2,
+		packY:        2,
+	}
 
+	// Printable ASCII is packed up front; everything else is added the
+	// first time it is drawn or measured.
 	for r := rune(32); r < 127; r++ {
-		bounds, mask, _, adv, ok := face.Glyph(fixed.Point26_6{}, r)
+		tr.glyph(r)
+	}
+	tr.AtlasDirty = false
+
+	return tr, nil
+}
+
+// AddFallbackFont appends a font consulted for glyphs the primary font and
+// earlier fallbacks lack, such as CJK or Arabic script. TrueType
+// collections use their first font. Glyphs are drawn one rune at a time, so
+// scripts that need shaping or right-to-left layout render unjoined.
+func (tr *TextRenderer) AddFallbackFont(fontPath string) error {
+	fontBytes, err := os.ReadFile(fontPath)
+	if err != nil {
+		return fmt.Errorf("failed to read fallback font file: %w", err)
+	}
+	f, err := parseTextFont(fontBytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse fallback font %s: %w", fontPath, err)
+	}
+	face, err := newTextFace(f, tr.fontSize)
+	if err != nil {
+		return fmt.Errorf("failed to create fallback face: %w", err)
+	}
+	tr.fonts = append(tr.fonts, textFont{font: f, face: face})
+	clear(tr.missing)
+	return nil
+}
+
+func parseTextFont(fontBytes []byte) (*sfnt.Font, error) {
+	f, err := opentype.Parse(fontBytes)
+	if err == nil {
+		return f, nil
+	}
+	collection, collectionErr := opentype.ParseCollection(fontBytes)
+	if collectionErr != nil || collection.NumFonts() == 0 {
+		return nil, err
+	}
+	return collection.Font(0)
+}
+
+func newTextFace(f *sfnt.Font, fontSize float64) (font.Face, error) {
+	return opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    fontSize,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+}
+
+// glyph returns the atlas entry for r, rasterizing it from the first font
+// that has it. Runes no font covers are skipped rather than drawn as tofu.
+func (tr *TextRenderer) glyph(r rune) (GlyphInfo, bool) {
+	if g, ok := tr.Glyphs[r]; ok {
+		return g, true
+	}
+	if r < 32 || tr.missing[r] {
+		return GlyphInfo{}, false
+	}
+	for _, f := range tr.fonts {
+		if index, err := f.font.GlyphIndex(&tr.buf, r); err != nil || index == 0 {
+			continue
+		}
+		bounds, mask, _, adv, ok := f.face.Glyph(fixed.Point26_6{}, r)
 		if !ok {
 			continue
 		}
+		g, ok := tr.packGlyph(bounds, mask, adv)
+		if !ok {
+			break
+		}
+		tr.Glyphs[r] = g
+		tr.AtlasDirty = true
+		return g, true
+	}
+	tr.missing[r] = true
+	return GlyphInfo{}, false
+}
 
-		w := mask.Bounds().Dx()
-		h := mask.Bounds().Dy()
+func (tr *TextRenderer) packGlyph(bounds image.Rectangle, mask image.Image, adv fixed.Int26_6) (GlyphInfo, bool) {
+	w := mask.Bounds().Dx()
+	h := mask.Bounds().Dy()
 
-		if x+w >= atlasSize {
+	for {
+		size := tr.AtlasImage.Bounds().Dx()
+		x, y, row := tr.packX, tr.packY, tr.packRow
+		if x+w >= size {
 			x = 2
-			y += rowHeight + 4
-			rowHeight = 0
+			y += row + textAtlasGlyphGap
+			row = 0
 		}
-
-		if y+h >= atlasSize {
+		if y+h < size {
+			tr.packX, tr.packY, tr.packRow = x, y, row
 			break
 		}
+		if !tr.growAtlas() {
+			return GlyphInfo{}, false
+		}
+	}
 
-		draw.Draw(atlas, image.Rect(x, y, x+w, y+h), mask, mask.Bounds().Min, draw.Src)
+	x, y := tr.packX, tr.packY
+	draw.Draw(tr.AtlasImage, image.Rect(x, y, x+w, y+h), mask, mask.Bounds().Min, draw.Src)
 
-		glyphs[r] = GlyphInfo{
-			UVMin: [2]float32{float32(x) / atlasSize, float32(y) / atlasSize},
-			UVMax: [2]float32{float32(x+w) / atlasSize, float32(y+h) / atlasSize},
-			Size:  [2]float32{float32(w), float32(h)},
-			Off:   [2]float32{float32(bounds.Min.X), float32(bounds.Min.Y)},
-			Adv:   float32(adv) / 64.0, // Convert fixed 26.6 to float
-		}
+	size := float32(tr.AtlasImage.Bounds().Dx())
+	g := GlyphInfo{
+		UVMin: [2]float32{float32(x) / size, float32(y) / size},
+		UVMax: [2]float32{float32(x+w) / size, float32(y+h) / size},
+		Size:  [2]float32{float32(w), float32(h)},
+		Off:   [2]float32{float32(bounds.Min.X), float32(bounds.Min.Y)},
+		Adv:   float32(adv) / 64.0, // Convert fixed 26.6 to float
+	}
 
-		x += w + 4
-		if h > rowHeight {
-			rowHeight = h
-		}
+	tr.packX += w + textAtlasGlyphGap
+	if h > tr.packRow {
+		tr.packRow = h
 	}
+	return g, true
+}
 
-	return &TextRenderer{
-		AtlasImage:   atlas,
-		Glyphs:       glyphs,
-		Face:         face,
-		WhitePixelUV: whitePixelUV,
-	}, nil
+// growAtlas doubles the atlas, keeping packed glyphs at the same pixel
+// positions and rescaling their UVs.
+func (tr *TextRenderer) growAtlas() bool {
+	oldSize := tr.AtlasImage.Bounds().Dx()
+	newSize := oldSize * 2
+	if newSize > textAtlasMaxSize {
+		return false
+	}
+	atlas := image.NewAlpha(image.Rect(0, 0, newSize, newSize))
+	draw.Draw(atlas, tr.AtlasImage.Bounds(), tr.AtlasImage, image.Point{}, draw.Src)
+	tr.AtlasImage = atlas
+
+	ratio := float32(oldSize) / float32(newSize)
+	for r, g := range tr.Glyphs {
+		g.UVMin = [2]float32{g.UVMin[0] * ratio, g.UVMin[1] * ratio}
+		g.UVMax = [2]float32{g.UVMax[0] * ratio, g.UVMax[1] * ratio}
+		tr.Glyphs[r] = g
+	}
+	tr.WhitePixelUV = [2]float32{0.5 / float32(newSize), 0.5 / float32(newSize)}
+	tr.AtlasDirty = true
+	return true
 }
 
+// BuildVertices emits six vertices per rect in order, then the text, so
+// text draws above every rect.
 func (tr *TextRenderer) BuildVertices(items []TextItem, rects []RectItem, screenW, screenH int) []TextVertex {
+	// Pack every glyph first: growing the atlas rescales UVs, so no vertex
+	// may be emitted before the atlas size is final.
+	for _, item := range items {
+		for _, r := range item.Text {
+			if r != '\n' {
+				tr.glyph(r)
+			}
+		}
+	}
+
 	vertices := make([]TextVertex, 0, (len(items)+len(rects))*6)
 
 	sw := float32(screenW)
@@ -133,17 +278,23 @@ func (tr *TextRenderer) BuildVertices(items []TextItem, rects []RectItem, screen
 		x1 := (r.X+r.W)/sw*2.0 - 1.0
 		y1 := 1.0 - (r.Y+r.H)/sh*2.0
 
-		uv := tr.WhitePixelUV
+		u0, v0, u1, v1 := tr.WhitePixelUV[0], tr.WhitePixelUV[1], tr.WhitePixelUV[0], tr.WhitePixelUV[1]
+		if r.Texture != "" {
+			u0, v0, u1, v1 = 0, 0, 1, 1
+			if r.UV[2] > r.UV[0] {
+				u0, v0, u1, v1 = r.UV[0], r.UV[1], r.UV[2], r.UV[3]
+			}
+		}
 
 		// Triangle 1
-		vertices = append(vertices, TextVertex{Pos: [2]float32{x0, y0}, UV: uv, Color: r.Color})
-		vertices = append(vertices, TextVertex{Pos: [2]float32{x1, y0}, UV: uv, Color: r.Color})
-		vertices = append(vertices, TextVertex{Pos: [2]float32{x0, y1}, UV: uv, Color: r.Color})
+		vertices = append(vertices, TextVertex{Pos: [2]float32{x0, y0}, UV: [2]float32{u0, v0}, Color: r.Color})
+		vertices = append(vertices, TextVertex{Pos: [2]float32{x1, y0}, UV: [2]float32{u1, v0}, Color: r.Color})
+		vertices = append(vertices, TextVertex{Pos: [2]float32{x0, y1}, UV: [2]float32{u0, v1}, Color: r.Color})
 
 		// Triangle 2
-		vertices = append(vertices, TextVertex{Pos: [2]float32{x1, y0}, UV: uv, Color: r.Color})
-		vertices = append(vertices, TextVertex{Pos: [2]float32{x1, y1}, UV: uv, Color: r.Color})
-		vertices = append(vertices, TextVertex{Pos: [2]float32{x0, y1}, UV: uv, Color: r.Color})
+		vertices = append(vertices, TextVertex{Pos: [2]float32{x1, y0}, UV: [2]float32{u1, v0}, Color: r.Color})
+		vertices = append(vertices, TextVertex{Pos: [2]float32{x1, y1}, UV: [2]float32{u1, v1}, Color: r.Color})
+		vertices = append(vertices, TextVertex{Pos: [2]float32{x0, y1}, UV: [2]float32{u0, v1}, Color: r.Color})
 	}
 
 	for _, item := range items {
@@ -158,7 +309,7 @@ func (tr *TextRenderer) BuildVertices(items []TextItem, rects []RectItem, screen
 				continue
 			}
 
-			g, ok := tr.Glyphs[r]
+			g, ok := tr.glyph(r)
 			if !ok {
 				continue
 			}
@@ -207,7 +358,7 @@ func (tr *TextRenderer) MeasureText(text string, scale float32) (float32, float3
 			continue
 		}
 
-		g, ok := tr.Glyphs[r]
+		g, ok := tr.glyph(r)
 		if !ok {
 			continue
 		}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func newTestTextRenderer(t *testing.T) *TextRenderer {
+	t.Helper()
+	tr, err := NewTextRenderer("../fonts/Roboto-Medium.ttf", 16)
+	if err != nil {
+		t.Fatalf("NewTextRenderer: %v", err)
+	}
+	return tr
+}
+
+func TestTextRendererAddsGlyphsLazilyAndFromFallbackFonts(t *testing.T) {
+	tr := newTestTextRenderer(t)
+	if tr.AtlasDirty {
+		t.Fatalf("expected the ASCII atlas to start clean")
+	}
+	if _, ok := tr.Glyphs['é']; ok {
+		t.Fatalf("expected non-ASCII glyphs to be packed on demand")
+	}
+	if w, _ := tr.MeasureText("é", 1); w <= 0 || !tr.AtlasDirty {
+		t.Fatalf("expected measuring to pack the glyph and dirty the atlas, got width %v", w)
+	}
+
+	if w, _ := tr.MeasureText("─", 1); w != 0 {
+		t.Fatalf("expected a rune the font lacks to be skipped, got width %v", w)
+	}
+	path := filepath.Join(t.TempDir(), "goregular.ttf")
+	if err := os.WriteFile(path, goregular.TTF, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.AddFallbackFont(path); err != nil {
+		t.Fatalf("AddFallbackFont: %v", err)
+	}
+	if w, _ := tr.MeasureText("─", 1); w <= 0 {
+		t.Fatalf("expected the fallback font to supply the glyph")
+	}
+	if err := tr.AddFallbackFont(filepath.Join(t.TempDir(), "missing.ttf")); err == nil {
+		t.Fatalf("expected a missing fallback font to fail")
+	}
+}
+
+func TestTextRendererGrowAtlasKeepsGlyphPixels(t *testing.T) {
+	tr := newTestTextRenderer(t)
+	before := tr.Glyphs['A']
+	oldSize := tr.AtlasImage.Bounds().Dx()
+	x, y := int(before.UVMin[0]*float32(oldSize)), int(before.UVMin[1]*float32(oldSize))
+	w, h := int(before.Size[0]), int(before.Size[1])
+	pixels := make([]uint8, 0, w*h)
+	for row := y; row < y+h; row++ {
+		pixels = append(pixels, tr.AtlasImage.Pix[row*oldSize+x:row*oldSize+x+w]...)
+	}
+
+	if !tr.growAtlas() {
+		t.Fatalf("expected the atlas to grow")
+	}
+	newSize := tr.AtlasImage.Bounds().Dx()
+	after := tr.Glyphs['A']
+	if newSize != oldSize*2 || after.UVMin[0]*float32(newSize) != float32(x) || after.UVMax[1]*float32(newSize) != float32(y+h) {
+		t.Fatalf("expected UVs to keep the glyph's pixel rect, got %+v in %d", after, newSize)
+	}
+	if tr.WhitePixelUV[0] != 0.5/float32(newSize) || tr.AtlasImage.Pix[0] != 255 {
+		t.Fatalf("expected the white pixel to stay at the origin")
+	}
+	for row := 0; row < h; row++ {
+		for col := 0; col < w; col++ {
+			if tr.AtlasImage.Pix[(y+row)*newSize+x+col] != pixels[row*w+col] {
+				t.Fatalf("expected glyph pixels to be copied at (%d,%d)", col, row)
+			}
+		}
+	}
+	for tr.growAtlas() {
+	}
+	if tr.AtlasImage.Bounds().Dx() != textAtlasMaxSize {
+		t.Fatalf("expected growth to stop at %d, got %d", textAtlasMaxSize, tr.AtlasImage.Bounds().Dx())
+	}
+}
+
+func TestTextRendererBuildVerticesUsesFinalAtlasUVs(t *testing.T) {
+	tr, err := NewTextRenderer("../fonts/Roboto-Medium.ttf", 48)
+	if err != nil {
+		t.Fatalf("NewTextRenderer: %v", err)
+	}
+	oldSize := tr.AtlasImage.Bounds().Dx()
+	text := []rune{'A'}
+	for r := rune(0xC0); r < 0x180; r++ {
+		text = append(text, r)
+	}
+	vertices := tr.BuildVertices(
+		[]TextItem{{Text: string(text), Scale: 1, Color: [4]float32{1, 1, 1, 1}}},
+		[]RectItem{{W: 10, H: 10, Color: [4]float32{1, 1, 1, 1}}},
+		800, 600,
+	)
+	if tr.AtlasImage.Bounds().Dx() == oldSize {
+		t.Fatalf("expected the new glyphs to grow the atlas")
+	}
+	if vertices[0].UV != tr.WhitePixelUV {
+		t.Fatalf("expected the rect to use the final white pixel UV %v, got %v", tr.WhitePixelUV, vertices[0].UV)
+	}
+	if g := tr.Glyphs['A']; vertices[6].UV != g.UVMin {
+		t.Fatalf("expected the first glyph to use its final UV %v, got %v", g.UVMin, vertices[6].UV)
+	}
+}
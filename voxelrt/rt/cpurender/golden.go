@@ -0,0 +1,116 @@
+package cpurender
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UpdateGoldenEnv names the environment variable that makes CheckGolden
+// rewrite golden files instead of comparing against them.
+const UpdateGoldenEnv = "GEKKO_UPDATE_GOLDEN"
+
+// Tolerance bounds how far a rendered image may drift from its golden.
+type Tolerance struct {
+	// Channel is the largest per-channel difference that still counts as a
+	// matching pixel.
+	Channel uint8
+	// MaxMismatchFraction is the share of pixels allowed to exceed Channel.
+	MaxMismatchFraction float64
+}
+
+type ImageDiff struct {
+	TotalPixels      int
+	MismatchedPixels int
+	MaxChannelDelta  uint8
+}
+
+func (d ImageDiff) MismatchFraction() float64 {
+	if d.TotalPixels == 0 {
+		return 0
+	}
+	return float64(d.MismatchedPixels) / float64(d.TotalPixels)
+}
+
+// CompareImages diffs got against want. It fails when the sizes differ or the
+// mismatching share exceeds tol.
+func CompareImages(want, got image.Image, tol Tolerance) (ImageDiff, error) {
+	diff := ImageDiff{}
+	if want.Bounds().Size() != got.Bounds().Size() {
+		return diff, fmt.Errorf("image size %v does not match golden %v", got.Bounds().Size(), want.Bounds().Size())
+	}
+	wb, gb := want.Bounds(), got.Bounds()
+	for y := 0; y < wb.Dy(); y++ {
+		for x := 0; x < wb.Dx(); x++ {
+			wr, wg, wbl, wa := want.At(wb.Min.X+x, wb.Min.Y+y).RGBA()
+			gr, gg, gbl, ga := got.At(gb.Min.X+x, gb.Min.Y+y).RGBA()
+			delta := max(channelDelta(wr, gr), channelDelta(wg, gg), channelDelta(wbl, gbl), channelDelta(wa, ga))
+			diff.TotalPixels++
+			diff.MaxChannelDelta = max(diff.MaxChannelDelta, delta)
+			if delta > tol.Channel {
+				diff.MismatchedPixels++
+			}
+		}
+	}
+	if diff.MismatchFraction() > tol.MaxMismatchFraction {
+		return diff, fmt.Errorf("%d of %d pixels differ by more than %d (max delta %d)", diff.MismatchedPixels, diff.TotalPixels, tol.Channel, diff.MaxChannelDelta)
+	}
+	return diff, nil
+}
+
+// CheckGolden compares got with the PNG at path. When UpdateGoldenEnv is set
+// the golden is rewritten instead. On mismatch the actual image is written
+// next to the test's temp files and its path is included in the error.
+func CheckGolden(path string, got image.Image, tol Tolerance) error {
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		return SavePNG(path, got)
+	}
+	want, err := LoadPNG(path)
+	if err != nil {
+		return fmt.Errorf("load golden %s (set %s=1 to create it): %w", path, UpdateGoldenEnv, err)
+	}
+	if _, err := CompareImages(want, got, tol); err != nil {
+		actual := filepath.Join(os.TempDir(), strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))+".actual.png")
+		if saveErr := SavePNG(actual, got); saveErr == nil {
+			return fmt.Errorf("golden %s: %w (actual written to %s)", path, err, actual)
+		}
+		return fmt.Errorf("golden %s: %w", path, err)
+	}
+	return nil
+}
+
+func LoadPNG(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return png.Decode(file)
+}
+
+func SavePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func channelDelta(a, b uint32) uint8 {
+	// image.Color.RGBA returns 16-bit channels.
+	a, b = a>>8, b>>8
+	if a > b {
+		return uint8(a - b)
+	}
+	return uint8(b - a)
+}
@@ -0,0 +1,200 @@
+// Package cpurender is a software reference renderer for core.Scene. It
+// traces the same XBrickMap data the GPU path uploads, so scenes can be
+// checked against golden images on machines without a WebGPU device.
+//
+// The shading model is intentionally small: Lambert diffuse, hard shadows,
+// palette emission and a gradient sky. It is a regression oracle, not a
+// match for the deferred lighting shader.
+package cpurender
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/gekko3d/gekko/voxelrt/rt/core"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type Options struct {
+	Width  int
+	Height int
+	Sky    Sky
+	// ShadowBias offsets shadow rays along the surface normal, in world units.
+	ShadowBias float32
+}
+
+func DefaultOptions(width, height int) Options {
+	return Options{
+		Width:      width,
+		Height:     height,
+		Sky:        DefaultSky(),
+		ShadowBias: 1e-3,
+	}
+}
+
+// Render traces one primary ray per pixel through scene from camera. Object
+// world AABBs are refreshed first, the same as Scene.Commit does.
+func Render(scene *core.Scene, camera *core.CameraState, opts Options) *image.RGBA {
+	width, height := max(opts.Width, 1), max(opts.Height, 1)
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	if scene == nil || camera == nil {
+		return img
+	}
+	if opts.ShadowBias <= 0 {
+		opts.ShadowBias = 1e-3
+	}
+	casters := &core.Scene{}
+	for _, obj := range scene.Objects {
+		if obj == nil || obj.XBrickMap == nil || obj.Transform == nil {
+			continue
+		}
+		obj.UpdateWorldAABB()
+		if obj.CastsShadows {
+			casters.Objects = append(casters.Objects, obj)
+		}
+	}
+
+	far := camera.FarPlane()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			ray := camera.ScreenToWorldRay(float64(x)+0.5, float64(y)+0.5, width, height)
+			var linear mgl32.Vec3
+			if hit := scene.Raycast(ray, far); hit != nil {
+				linear = shadeHit(scene, casters, ray, hit, far, opts)
+			} else {
+				linear = opts.Sky.Sample(ray.Direction)
+			}
+			img.SetRGBA(x, y, encodeLinear(linear))
+		}
+	}
+	return img
+}
+
+func shadeHit(scene, casters *core.Scene, ray core.Ray, hit *core.HitResult, far float32, opts Options) mgl32.Vec3 {
+	material := core.DefaultMaterial()
+	if _, value := hit.Object.XBrickMap.GetVoxel(hit.Coord[0], hit.Coord[1], hit.Coord[2]); int(value) < len(hit.Object.MaterialTable) {
+		material = hit.Object.MaterialTable[value]
+	}
+	albedo := srgbToLinear(material.BaseColor)
+	normal := hit.Normal
+	position := ray.Origin.Add(ray.Direction.Mul(hit.T))
+	origin := position.Add(normal.Mul(opts.ShadowBias))
+
+	upness := clamp01(normal.Y()*0.5 + 0.5)
+	hemi := 0.22 + 0.78*upness + (1-absf(normal.Y()))*0.12
+	ambient := lerpVec(scene.AmbientLight, opts.Sky.Sample(normal), scene.SkyAmbientMix).Mul(hemi)
+	lit := mulVec(albedo, ambient)
+
+	for _, light := range scene.Lights {
+		radiance, toLight, distance, ok := lightAt(light, position, far)
+		if !ok {
+			continue
+		}
+		nDotL := normal.Dot(toLight)
+		if nDotL <= 0 {
+			continue
+		}
+		if light.Params[3] > 0.5 && casters.Raycast(core.Ray{Origin: origin, Direction: toLight}, distance) != nil {
+			continue
+		}
+		lit = lit.Add(mulVec(albedo, radiance.Mul(nDotL)))
+	}
+
+	if material.Emission > 0 {
+		lit = lit.Add(srgbToLinear(material.Emissive).Mul(material.Emission))
+	}
+	return lit
+}
+
+// lightAt mirrors the attenuation in deferred_lighting.wgsl. Directional
+// lights report far as their distance so shadow rays stay bounded.
+func lightAt(light core.Light, position mgl32.Vec3, far float32) (mgl32.Vec3, mgl32.Vec3, float32, bool) {
+	radiance := mgl32.Vec3{light.Color[0], light.Color[1], light.Color[2]}.Mul(light.Color[3])
+	lightType := uint32(light.Params[2])
+	if lightType == core.LightTypeDirectional {
+		dir := mgl32.Vec3{light.Direction[0], light.Direction[1], light.Direction[2]}
+		if dir.LenSqr() < 1e-12 {
+			return mgl32.Vec3{}, mgl32.Vec3{}, 0, false
+		}
+		return radiance, dir.Normalize().Mul(-1), far, true
+	}
+
+	toLight := mgl32.Vec3{light.Position[0], light.Position[1], light.Position[2]}.Sub(position)
+	distance := toLight.Len()
+	lightRange := light.Params[0]
+	if distance < 1e-6 || distance > lightRange {
+		return mgl32.Vec3{}, mgl32.Vec3{}, 0, false
+	}
+	toLight = toLight.Mul(1 / distance)
+	factor := distance / lightRange
+	smooth := max(0, 1-factor*factor)
+	attenuation := smooth * smooth * 50 / (distance*distance + 1)
+	if lightType == core.LightTypeSpot {
+		spotDir := mgl32.Vec3{light.Direction[0], light.Direction[1], light.Direction[2]}.Normalize()
+		cosCur := toLight.Mul(-1).Dot(spotDir)
+		cosCone := light.Params[1]
+		if cosCur < cosCone {
+			return mgl32.Vec3{}, mgl32.Vec3{}, 0, false
+		}
+		attenuation *= smoothstep(cosCone, cosCone+0.1, cosCur)
+	}
+	return radiance.Mul(attenuation), toLight, distance, true
+}
+
+func encodeLinear(c mgl32.Vec3) color.RGBA {
+	return color.RGBA{
+		R: linearToSRGB8(c.X()),
+		G: linearToSRGB8(c.Y()),
+		B: linearToSRGB8(c.Z()),
+		A: 255,
+	}
+}
+
+func linearToSRGB8(v float32) uint8 {
+	v = clamp01(v)
+	var s float64
+	if v <= 0.0031308 {
+		s = float64(v) * 12.92
+	} else {
+		s = 1.055*math.Pow(float64(v), 1/2.4) - 0.055
+	}
+	return uint8(math.Round(s * 255))
+}
+
+func srgbToLinear(c [4]uint8) mgl32.Vec3 {
+	var out mgl32.Vec3
+	for i := 0; i < 3; i++ {
+		v := float64(c[i]) / 255
+		if v <= 0.04045 {
+			out[i] = float32(v / 12.92)
+		} else {
+			out[i] = float32(math.Pow((v+0.055)/1.055, 2.4))
+		}
+	}
+	return out
+}
+
+func mulVec(a, b mgl32.Vec3) mgl32.Vec3 {
+	return mgl32.Vec3{a.X() * b.X(), a.Y() * b.Y(), a.Z() * b.Z()}
+}
+
+func lerpVec(a, b mgl32.Vec3, t float32) mgl32.Vec3 {
+	return a.Add(b.Sub(a).Mul(t))
+}
+
+func smoothstep(edge0, edge1, x float32) float32 {
+	t := clamp01((x - edge0) / (edge1 - edge0))
+	return t * t * (3 - 2*t)
+}
+
+func clamp01(v float32) float32 {
+	return min(max(v, 0), 1)
+}
+
+func absf(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
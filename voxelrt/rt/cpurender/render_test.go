@@ -0,0 +1,139 @@
+package cpurender
+
+import (
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/gekko3d/gekko/voxelrt/rt/core"
+	"github.com/gekko3d/gekko/voxelrt/rt/volume"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func buildReferenceScene() (*core.Scene, *core.CameraState) {
+	materials := make([]core.Material, 256)
+	for i := range materials {
+		materials[i] = core.DefaultMaterial()
+	}
+	materials[1].BaseColor = [4]uint8{200, 200, 200, 255}
+	materials[2].BaseColor = [4]uint8{220, 60, 40, 255}
+	materials[3].BaseColor = [4]uint8{255, 240, 160, 255}
+	materials[3].Emissive = [4]uint8{255, 240, 160, 255}
+	materials[3].Emission = 1
+
+	floor := core.NewVoxelObject()
+	volume.Cube(floor.XBrickMap, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{39, 0, 39}, 1)
+	floor.MaterialTable = materials
+	floor.Transform.Scale = mgl32.Vec3{0.1, 0.1, 0.1}
+	floor.Transform.Position = mgl32.Vec3{-2, -0.1, -2}
+
+	box := core.NewVoxelObject()
+	volume.Cube(box.XBrickMap, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{7, 7, 7}, 2)
+	box.XBrickMap.SetVoxel(3, 8, 3, 3)
+	box.MaterialTable = materials
+	box.Transform.Scale = mgl32.Vec3{0.1, 0.1, 0.1}
+	box.Transform.Position = mgl32.Vec3{-0.4, 0, -0.4}
+	box.Transform.Rotation = mgl32.QuatRotate(mgl32.DegToRad(30), mgl32.Vec3{0, 1, 0})
+
+	scene := core.NewScene()
+	scene.AddObject(floor)
+	scene.AddObject(box)
+	scene.Lights = []core.Light{
+		{
+			Direction: [4]float32{-0.4, -1, -0.3, 0},
+			Color:     [4]float32{1, 0.95, 0.9, 1.2},
+			Params:    [4]float32{0, 0, float32(core.LightTypeDirectional), 1},
+		},
+		{
+			Position: [4]float32{1, 0.6, 1, 0},
+			Color:    [4]float32{0.3, 0.5, 1, 1},
+			Params:   [4]float32{3, 0, float32(core.LightTypePoint), 1},
+		},
+	}
+
+	camera := core.NewCameraState()
+	camera.Position = mgl32.Vec3{2.2, 2, 2.6}
+	camera.LookAt = mgl32.Vec3{0, 0.2, 0}
+	return scene, camera
+}
+
+func TestRenderMatchesGolden(t *testing.T) {
+	scene, camera := buildReferenceScene()
+	img := Render(scene, camera, DefaultOptions(64, 48))
+	if err := CheckGolden(filepath.Join("testdata", "lit_box.png"), img, Tolerance{Channel: 3, MaxMismatchFraction: 0.01}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRenderCastsHardShadows(t *testing.T) {
+	scene, camera := buildReferenceScene()
+	scene.Lights = scene.Lights[:1]
+	scene.AmbientLight = mgl32.Vec3{}
+	scene.SkyAmbientMix = 0
+	camera.Position = mgl32.Vec3{0, 6, 0.01}
+	camera.LookAt = mgl32.Vec3{0, 0, 0}
+
+	withShadows := Render(scene, camera, DefaultOptions(32, 32))
+	scene.Lights[0].Params[3] = 0
+	withoutShadows := Render(scene, camera, DefaultOptions(32, 32))
+
+	darker := 0
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			if withShadows.RGBAAt(x, y).R < withoutShadows.RGBAAt(x, y).R {
+				darker++
+			}
+		}
+	}
+	if darker == 0 {
+		t.Fatal("expected the box to shadow part of the floor")
+	}
+}
+
+func TestRenderShowsSkyWhenNothingIsHit(t *testing.T) {
+	camera := core.NewCameraState()
+	camera.Position = mgl32.Vec3{0, 0, 0}
+	camera.LookAt = mgl32.Vec3{0, 1, 0}
+	camera.Up = mgl32.Vec3{0, 0, -1}
+	opts := DefaultOptions(4, 4)
+	img := Render(core.NewScene(), camera, opts)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			ray := camera.ScreenToWorldRay(float64(x)+0.5, float64(y)+0.5, 4, 4)
+			if want, got := encodeLinear(opts.Sky.Sample(ray.Direction)), img.RGBAAt(x, y); want != got {
+				t.Fatalf("pixel (%d,%d): expected sky color %v, got %v", x, y, want, got)
+			}
+		}
+	}
+	if zenith := encodeLinear(opts.Sky.Zenith); absDelta(img.RGBAAt(2, 2).B, zenith.B) > 24 {
+		t.Fatalf("expected the center of an upward view to approach the zenith color %v, got %v", zenith, img.RGBAAt(2, 2))
+	}
+}
+
+func absDelta(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
+
+func TestCompareImagesAppliesTolerance(t *testing.T) {
+	want := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	got := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	got.SetRGBA(0, 0, color.RGBA{R: 2})
+	if _, err := CompareImages(want, got, Tolerance{Channel: 2}); err != nil {
+		t.Fatalf("expected small delta to be within tolerance: %v", err)
+	}
+	got.SetRGBA(1, 0, color.RGBA{G: 50})
+	diff, err := CompareImages(want, got, Tolerance{Channel: 2})
+	if err == nil || diff.MismatchedPixels != 1 || diff.MaxChannelDelta != 50 {
+		t.Fatalf("expected one mismatching pixel, got %+v err=%v", diff, err)
+	}
+	if _, err := CompareImages(want, got, Tolerance{Channel: 2, MaxMismatchFraction: 0.05}); err != nil {
+		t.Fatalf("expected mismatch fraction tolerance to accept one pixel: %v", err)
+	}
+	if _, err := CompareImages(want, image.NewRGBA(image.Rect(0, 0, 5, 5)), Tolerance{}); err == nil {
+		t.Fatal("expected size mismatch to fail")
+	}
+}
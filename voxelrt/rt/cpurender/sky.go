@@ -0,0 +1,56 @@
+package cpurender
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Sky is a three-stop vertical gradient with an optional sun disk. Colors
+// are linear.
+type Sky struct {
+	Zenith  mgl32.Vec3
+	Horizon mgl32.Vec3
+	Ground  mgl32.Vec3
+	// SunDirection points toward the sun. A zero vector disables the disk.
+	SunDirection mgl32.Vec3
+	SunColor     mgl32.Vec3
+	// SunAngularRadius is the disk radius in radians.
+	SunAngularRadius float32
+	// Linear blends by the view elevation itself, like the skybox gradient
+	// layer, instead of its square root, which keeps the horizon band wide.
+	Linear bool
+}
+
+func DefaultSky() Sky {
+	return Sky{
+		Zenith:  mgl32.Vec3{0.18, 0.32, 0.62},
+		Horizon: mgl32.Vec3{0.62, 0.72, 0.85},
+		Ground:  mgl32.Vec3{0.22, 0.20, 0.18},
+	}
+}
+
+// Sample returns the sky radiance seen along dir.
+func (s Sky) Sample(dir mgl32.Vec3) mgl32.Vec3 {
+	if dir.LenSqr() < 1e-12 {
+		return s.Horizon
+	}
+	dir = dir.Normalize()
+	y := dir.Y()
+	t := float32(math.Abs(float64(y)))
+	if !s.Linear {
+		t = float32(math.Sqrt(float64(t)))
+	}
+	var c mgl32.Vec3
+	if y >= 0 {
+		c = lerpVec(s.Horizon, s.Zenith, t)
+	} else {
+		c = lerpVec(s.Horizon, s.Ground, t)
+	}
+	if s.SunAngularRadius > 0 && s.SunDirection.LenSqr() > 1e-12 {
+		if dir.Dot(s.SunDirection.Normalize()) >= float32(math.Cos(float64(s.SunAngularRadius))) {
+			c = c.Add(s.SunColor)
+		}
+	}
+	return c
+}
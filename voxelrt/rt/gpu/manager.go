@@ -75,6 +75,9 @@ type SpriteAtlasResource struct {
 	Width     uint32
 	Height    uint32
 	MipLevels uint32
+	// External atlases are owned by the caller (render-view targets) and are
+	// never released by the manager.
+	External bool
 }
 
 type SpriteRenderBatch struct {
@@ -381,6 +384,13 @@ type GpuBufferManager struct {
 	spritesBG1Depth    *wgpu.TextureView
 	spritesBG1Pipeline *wgpu.RenderPipeline
 
+	// Decals (projected in deferred lighting)
+	DecalBuf           *wgpu.Buffer
+	DecalCount         uint32
+	DecalAtlas         *SpriteAtlasResource
+	DecalSampler       *wgpu.Sampler
+	decalBindingsDirty bool
+
 	// Transparent overlay (single-layer transparency over lit image)
 	TransparentBG0 *wgpu.BindGroup // camera + instances + BVH
 	TransparentBG1 *wgpu.BindGroup // voxel data buffers
@@ -0,0 +1,156 @@
+package gpu
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/cogentcore/webgpu/wgpu"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// DecalHost is one projected decal ready for the deferred lighting pass.
+// WorldToDecal maps render-relative world positions into the unit projector
+// box centered on the origin.
+type DecalHost struct {
+	WorldToDecal mgl32.Mat4
+	Region       [4]float32
+	Color        [4]float32
+	// Facing is the box's outward axis in xyz; w is the minimum cosine
+	// between it and a surface normal.
+	Facing    [4]float32
+	VoxelSnap bool
+}
+
+type decalRecord struct {
+	WorldToDecal [16]float32
+	Region       [4]float32
+	Color        [4]float32
+	Facing       [4]float32
+	Params       [4]float32
+}
+
+type decalListHeader struct {
+	Count uint32
+	_     [3]uint32
+}
+
+func buildDecalListData(decals []DecalHost) []byte {
+	headerSize := int(unsafe.Sizeof(decalListHeader{}))
+	recordSize := int(unsafe.Sizeof(decalRecord{}))
+	// Keep one record even when empty so the runtime-sized array binds.
+	data := make([]byte, headerSize+recordSize*max(len(decals), 1))
+	header := (*decalListHeader)(unsafe.Pointer(&data[0]))
+	header.Count = uint32(len(decals))
+	for i, decal := range decals {
+		rec := (*decalRecord)(unsafe.Pointer(&data[headerSize+i*recordSize]))
+		rec.WorldToDecal = decal.WorldToDecal
+		rec.Region = decal.Region
+		rec.Color = decal.Color
+		rec.Facing = decal.Facing
+		if decal.VoxelSnap {
+			rec.Params[0] = 1
+		}
+	}
+	return data
+}
+
+// UpdateDecals uploads the decal list read by deferred lighting. It reports
+// whether the lighting bind groups must be rebuilt, either because the buffer
+// grew or because the decal atlas changed since the last upload.
+func (m *GpuBufferManager) UpdateDecals(decals []DecalHost) bool {
+	m.ensureDecalAtlas()
+	recreated := m.ensureBuffer("DecalBuf", &m.DecalBuf, buildDecalListData(decals), wgpu.BufferUsageStorage, 0)
+	m.DecalCount = uint32(len(decals))
+	if m.decalBindingsDirty {
+		m.decalBindingsDirty = false
+		recreated = true
+	}
+	return recreated
+}
+
+// SetDecalAtlas replaces the texture decal regions sample from. Uploads with
+// the same version and size are skipped.
+func (m *GpuBufferManager) SetDecalAtlas(data []byte, w, h uint32, version uint, format wgpu.TextureFormat) {
+	if format == 0 {
+		format = wgpu.TextureFormatRGBA8UnormSrgb
+	}
+	if existing := m.DecalAtlas; existing != nil &&
+		existing.Version == version &&
+		existing.Format == format &&
+		existing.Width == w &&
+		existing.Height == h {
+		return
+	}
+	if w == 0 || h == 0 {
+		panic("decal atlas dimensions must be non-zero")
+	}
+	required := int(w) * int(h) * 4
+	if len(data) < required {
+		panic(fmt.Errorf("decal atlas data too short: got %d bytes, need %d", len(data), required))
+	}
+
+	size := wgpu.Extent3D{Width: w, Height: h, DepthOrArrayLayers: 1}
+	tex, err := m.Device.CreateTexture(&wgpu.TextureDescriptor{
+		Label:         "Decal Atlas",
+		Size:          size,
+		MipLevelCount: 1,
+		SampleCount:   1,
+		Dimension:     wgpu.TextureDimension2D,
+		Format:        format,
+		Usage:         wgpu.TextureUsageTextureBinding | wgpu.TextureUsageCopyDst,
+	})
+	if err != nil {
+		panic(err)
+	}
+	view, err := tex.CreateView(nil)
+	if err != nil {
+		tex.Release()
+		panic(err)
+	}
+	m.Device.GetQueue().WriteTexture(
+		&wgpu.ImageCopyTexture{Texture: tex},
+		data[:required],
+		&wgpu.TextureDataLayout{BytesPerRow: 4 * w, RowsPerImage: h},
+		&size,
+	)
+
+	if old := m.DecalAtlas; old != nil {
+		old.View.Release()
+		old.Texture.Release()
+	}
+	m.DecalAtlas = &SpriteAtlasResource{
+		Texture:   tex,
+		View:      view,
+		Version:   version,
+		Format:    format,
+		Width:     w,
+		Height:    h,
+		MipLevels: 1,
+	}
+	m.decalBindingsDirty = true
+}
+
+func (m *GpuBufferManager) ensureDecalAtlas() {
+	if m.DecalAtlas == nil {
+		m.SetDecalAtlas([]byte{255, 255, 255, 255}, 1, 1, 0, wgpu.TextureFormatRGBA8UnormSrgb)
+	}
+	if m.DecalSampler == nil {
+		var err error
+		m.DecalSampler, err = m.Device.CreateSampler(&wgpu.SamplerDescriptor{
+			AddressModeU:  wgpu.AddressModeClampToEdge,
+			AddressModeV:  wgpu.AddressModeClampToEdge,
+			AddressModeW:  wgpu.AddressModeClampToEdge,
+			MagFilter:     wgpu.FilterModeLinear,
+			MinFilter:     wgpu.FilterModeLinear,
+			MipmapFilter:  wgpu.MipmapFilterModeNearest,
+			LodMaxClamp:   0,
+			MaxAnisotropy: 1,
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+	if m.DecalBuf == nil {
+		m.ensureBuffer("DecalBuf", &m.DecalBuf, buildDecalListData(nil), wgpu.BufferUsageStorage, 0)
+	}
+}
@@ -48,8 +48,8 @@ func (m *GpuBufferManager) CreateGBufferTextures(w, h uint32) {
 		}
 	}
 
-	setupTexture(&m.GBufferDepth, &m.DepthView, "GBuffer Depth", wgpu.TextureFormatRGBA32Float, wgpu.TextureUsageStorageBinding|wgpu.TextureUsageTextureBinding, w, h)
-	setupTexture(&m.GBufferNormal, &m.NormalView, "GBuffer Normal", wgpu.TextureFormatRGBA16Float, wgpu.TextureUsageStorageBinding|wgpu.TextureUsageTextureBinding, w, h)
+	setupTexture(&m.GBufferDepth, &m.DepthView, "GBuffer Depth", wgpu.TextureFormatRGBA32Float, wgpu.TextureUsageStorageBinding|wgpu.TextureUsageTextureBinding|wgpu.TextureUsageCopySrc, w, h)
+	setupTexture(&m.GBufferNormal, &m.NormalView, "GBuffer Normal", wgpu.TextureFormatRGBA16Float, wgpu.TextureUsageStorageBinding|wgpu.TextureUsageTextureBinding|wgpu.TextureUsageCopySrc, w, h)
 	setupTexture(&m.GBufferMaterial, &m.MaterialView, "GBuffer Material", wgpu.TextureFormatRGBA32Float, wgpu.TextureUsageStorageBinding|wgpu.TextureUsageTextureBinding, w, h)
 	setupTexture(&m.PlanetDepthTex, &m.PlanetDepthView, "Planet Depth", wgpu.TextureFormatR32Float, wgpu.TextureUsageRenderAttachment|wgpu.TextureUsageTextureBinding, w, h)
 
@@ -193,17 +193,22 @@ func (m *GpuBufferManager) CreateLightingBindGroups(lightPipeline *wgpu.ComputeP
 		panic(err)
 	}
 
-	// Create materials bind group (group 2)
+	// Create materials and decals bind group (group 2)
+	m.ensureDecalAtlas()
 	m.LightingBindGroupMaterial, err = m.Device.CreateBindGroup(&wgpu.BindGroupDescriptor{
 		Label:  "Lighting Material BG2",
 		Layout: lightPipeline.GetBindGroupLayout(2),
 		Entries: []wgpu.BindGroupEntry{
 			{Binding: 3, Buffer: m.MaterialBuf, Size: wgpu.WholeSize},
+			{Binding: 4, Buffer: m.DecalBuf, Size: wgpu.WholeSize},
+			{Binding: 5, TextureView: m.DecalAtlas.View},
+			{Binding: 6, Sampler: m.DecalSampler},
 		},
 	})
 	if err != nil {
 		panic(err)
 	}
+	m.decalBindingsDirty = false
 }
 
 // UpdateParticles manages GPU particle buffers and state.
@@ -179,7 +179,7 @@ func (m *GpuBufferManager) SetSpriteAtlas(key string, data []byte, w, h uint32,
 	if len(data) < required {
 		panic(fmt.Errorf("sprite atlas data too short: got %d bytes, need %d", len(data), required))
 	}
-	if existing, ok := m.SpriteAtlases[key]; ok && existing != nil {
+	if existing, ok := m.SpriteAtlases[key]; ok && existing != nil && !existing.External {
 		if existing.View != nil {
 			existing.View.Release()
 		}
@@ -328,6 +328,46 @@ func downsampleSpriteMipRGBA8(prev []byte, prevW, prevH, nextW, nextH uint32) []
 	return next
 }
 
+// SetExternalSpriteAtlas registers a caller-owned texture, such as a render
+// target, as the atlas for key. Sprite batches rebind on their next sync.
+func (m *GpuBufferManager) SetExternalSpriteAtlas(key string, tex *wgpu.Texture, view *wgpu.TextureView, w, h uint32, format wgpu.TextureFormat) {
+	if m == nil || view == nil {
+		return
+	}
+	m.RemoveSpriteAtlas(key)
+	m.SpriteAtlases[key] = &SpriteAtlasResource{
+		Texture:   tex,
+		View:      view,
+		Format:    format,
+		Width:     w,
+		Height:    h,
+		MipLevels: 1,
+		External:  true,
+	}
+}
+
+// RemoveSpriteAtlas drops the atlas for key, releasing it unless it is
+// external. Sprites using key fall back to the default atlas.
+func (m *GpuBufferManager) RemoveSpriteAtlas(key string) {
+	if m == nil {
+		return
+	}
+	existing, ok := m.SpriteAtlases[key]
+	if !ok {
+		return
+	}
+	delete(m.SpriteAtlases, key)
+	if existing == nil || existing.External {
+		return
+	}
+	if existing.View != nil {
+		existing.View.Release()
+	}
+	if existing.Texture != nil {
+		existing.Texture.Release()
+	}
+}
+
 func (m *GpuBufferManager) ensureSpriteAtlasSampler() {
 	if m.SpriteAtlasSampler != nil {
 		return
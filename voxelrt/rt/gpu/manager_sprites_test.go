@@ -89,3 +89,28 @@ func TestSpriteAtlasMipChainAlphaWeightsRGB(t *testing.T) {
 		}
 	}
 }
+
+func TestExternalSpriteAtlasIsRegisteredAndRemovedWithoutOwnership(t *testing.T) {
+	m := &GpuBufferManager{SpriteAtlases: make(map[string]*SpriteAtlasResource)}
+	view := &wgpu.TextureView{}
+
+	m.SetExternalSpriteAtlas("mirror", nil, view, 256, 128, wgpu.TextureFormatBGRA8Unorm)
+	entry := m.SpriteAtlases["mirror"]
+	if entry == nil || !entry.External || entry.View != view || entry.Width != 256 || entry.Height != 128 {
+		t.Fatalf("unexpected external atlas entry: %+v", entry)
+	}
+	if got := m.spriteAtlasView("mirror"); got != view {
+		t.Fatal("expected sprite batches to bind the external view")
+	}
+
+	// Removing an external atlas must not release the caller's texture.
+	m.RemoveSpriteAtlas("mirror")
+	if _, ok := m.SpriteAtlases["mirror"]; ok {
+		t.Fatal("expected external atlas to be removed")
+	}
+
+	m.SetExternalSpriteAtlas("missing", nil, nil, 1, 1, wgpu.TextureFormatBGRA8Unorm)
+	if _, ok := m.SpriteAtlases["missing"]; ok {
+		t.Fatal("expected nil view to be ignored")
+	}
+}
@@ -65,6 +65,16 @@ func (m *GpuBufferManager) UpdateTiledLightingResources(screenW, screenH uint32)
 	return recreated
 }
 
+// SetTiledLightingScreenExtent rewrites the screen size of the tile
+// parameters without resizing the tile grid. Render views use it to cover
+// only their top-left render rect; tiles outside it receive no lights.
+func (m *GpuBufferManager) SetTiledLightingScreenExtent(screenW, screenH uint32) {
+	if m == nil || m.Device == nil || m.TileLightParamsBuf == nil || screenW == 0 || screenH == 0 {
+		return
+	}
+	m.Device.GetQueue().WriteBuffer(m.TileLightParamsBuf, 0, buildTileLightParamsData(screenW, screenH, m.TileLightTilesX, m.TileLightTilesY))
+}
+
 func (m *GpuBufferManager) CreateTiledLightCullBindGroups(pipeline *wgpu.ComputePipeline) {
 	if pipeline == nil {
 		return
@@ -814,7 +814,8 @@ fn fs_main(
     t_limit = far_t;
   }
 
-  let uv_screen = render_uv;
+  // Render views cover only the top-left screen_size pixels of the targets.
+  let uv_screen = render_uv * vec2<f32>(f32(dims.x), f32(dims.y)) / max(uCamera.screen_size, vec2<f32>(1.0));
   let ray_ws = get_ray_from_uv(uv_screen);
   let light_color = primary_light_color();
   let ambient = uCamera.ambient_color.xyz;
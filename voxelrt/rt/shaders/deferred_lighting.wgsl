@@ -80,6 +80,22 @@ struct TileLightHeader {
     pad0: u32,
 };
 
+struct Decal {
+    world_to_decal: mat4x4<f32>,
+    region: vec4<f32>,
+    color: vec4<f32>,
+    facing: vec4<f32>, // xyz: projector outward axis, w: minimum cosine to the surface normal
+    params: vec4<f32>, // x: sample at the voxel center
+};
+
+struct DecalList {
+    count: u32,
+    pad0: u32,
+    pad1: u32,
+    pad2: u32,
+    items: array<Decal>,
+};
+
 struct Ray {
     origin: vec3<f32>,
     dir: vec3<f32>,
@@ -116,6 +132,11 @@ struct LightingContribution {
 // Group 2: Voxel Data (reuse)
 @group(2) @binding(3) var<storage, read> materials: array<vec4<f32>>;
 
+// Decals, sorted so later entries draw on top
+@group(2) @binding(4) var<storage, read> decals: DecalList;
+@group(2) @binding(5) var decal_atlas: texture_2d<f32>;
+@group(2) @binding(6) var decal_sampler: sampler;
+
 // Group 3: Tiled lighting buffers
 @group(3) @binding(0) var<uniform> tile_light_params: TileLightListParams;
 @group(3) @binding(1) var<storage, read> tile_light_headers: array<TileLightHeader>;
@@ -573,6 +594,35 @@ fn reconstruct_world_pos(uv: vec2<f32>, depth: f32) -> vec3<f32> {
     return ray.origin + ray.dir * depth;
 }
 
+// apply_decals blends projected decals over the surface albedo. The decal
+// box spans [-0.5, 0.5] on each axis in decal space; the image covers xy and
+// fades out toward the ends of the projection depth.
+fn apply_decals(base_color: vec3<f32>, hit_pos: vec3<f32>, voxel_center: vec3<f32>, normal: vec3<f32>) -> vec3<f32> {
+    var color = base_color;
+    for (var i = 0u; i < decals.count; i++) {
+        let decal = decals.items[i];
+        var pos = hit_pos;
+        if (decal.params.x > 0.5) {
+            pos = voxel_center;
+        }
+        let local = (decal.world_to_decal * vec4<f32>(pos, 1.0)).xyz;
+        if (any(abs(local) > vec3<f32>(0.5))) {
+            continue;
+        }
+        let facing = dot(normal, decal.facing.xyz);
+        if (facing < decal.facing.w) {
+            continue;
+        }
+        let uv = vec2<f32>(local.x + 0.5, 0.5 - local.y);
+        let texel = textureSampleLevel(decal_atlas, decal_sampler, mix(decal.region.xy, decal.region.zw, uv), 0.0);
+        let depth_fade = 1.0 - smoothstep(0.35, 0.5, abs(local.z));
+        let facing_fade = saturate((facing - decal.facing.w) / 0.1);
+        let alpha = saturate(texel.a * decal.color.a * depth_fade * facing_fade);
+        color = mix(color, texel.rgb * decal.color.rgb, alpha);
+    }
+    return color;
+}
+
 fn tile_index_for_pixel(pixel: vec2<u32>) -> u32 {
     let tile_coord = min(
         pixel / tile_light_params.tile_size,
@@ -586,7 +636,8 @@ fn tile_index_for_pixel(pixel: vec2<u32>) -> u32 {
 
 @compute @workgroup_size(8, 8, 1)
 fn main(@builtin(global_invocation_id) global_id: vec3<u32>) {
-    let size = textureDimensions(in_depth);
+    // Render views cover only the top-left screen_size pixels of the targets.
+    let size = min(textureDimensions(in_depth), vec2<u32>(camera.screen_size));
     if (global_id.x >= size.x || global_id.y >= size.y) { return; }
     
     let uv = (vec2<f32>(f32(global_id.x), f32(global_id.y)) + 0.5) / vec2<f32>(f32(size.x), f32(size.y));
@@ -612,8 +663,11 @@ fn main(@builtin(global_invocation_id) global_id: vec3<u32>) {
     let receiver_shadow_seam_epsilon = max(mat_data.z, 0.0);
     let mat_idx = u32(mat_data.w + 0.5);
     
+    let hit_pos_ws = reconstruct_world_pos(uv, depth);
+    let voxel_center_ws = depth_data.gba;
+
     let mat_packed = materials[mat_idx];
-    let base_color = srgb_to_linear(mat_packed.xyz);
+    let base_color = apply_decals(srgb_to_linear(mat_packed.xyz), hit_pos_ws, voxel_center_ws, normal);
     let emissive_linear = srgb_to_linear(materials[mat_idx + 1u].xyz);
     let pbr_params = materials[mat_idx + 2u];
     let material_extra = materials[mat_idx + 3u];
@@ -621,9 +675,6 @@ fn main(@builtin(global_invocation_id) global_id: vec3<u32>) {
     let metalness = clamp(pbr_params.y, 0.0, 1.0);
     let ior = pbr_params.z;
     let emissive = emissive_linear * max(material_extra.x, 0.0);
-    
-    let hit_pos_ws = reconstruct_world_pos(uv, depth);
-    let voxel_center_ws = depth_data.gba;
 
     // Shade the voxel as one cell by evaluating BRDF terms and receiving
     // shadows from the stored voxel center, to act like a 3d pixel.
@@ -1139,7 +1139,8 @@ fn get_ray(uv: vec2<f32>) -> Ray {
 
 @compute @workgroup_size(8, 8, 1)
 fn main(@builtin(global_invocation_id) global_id: vec3<u32>) {
-    let size = textureDimensions(out_depth);
+    // Render views cover only the top-left screen_size pixels of the targets.
+    let size = min(textureDimensions(out_depth), vec2<u32>(camera.screen_size));
     if (global_id.x >= size.x || global_id.y >= size.y) { return; }
     
     let uv = (vec2<f32>(f32(global_id.x), f32(global_id.y)) + 0.5) / vec2<f32>(f32(size.x), f32(size.y));
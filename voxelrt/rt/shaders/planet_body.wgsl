@@ -1089,9 +1089,15 @@ fn vs_main(@builtin(vertex_index) vid: u32) -> VSOut {
   return out;
 }
 
+// view_dims clamps a screen target's size to the current view's render rect,
+// the top-left screen_size pixels. The main view covers the whole target.
+fn view_dims(tex_dims: vec2<u32>) -> vec2<u32> {
+  return max(min(tex_dims, vec2<u32>(camera.screen_size)), vec2<u32>(1u));
+}
+
 @fragment
 fn fs_main(in: VSOut) -> FSOut {
-  let dims = textureDimensions(scene_depth);
+  let dims = view_dims(textureDimensions(scene_depth));
   let ipos = vec2<i32>(
     clamp(i32(in.position.x), 0, i32(dims.x) - 1),
     clamp(i32(in.position.y), 0, i32(dims.y) - 1),
@@ -0,0 +1,221 @@
+// post_process.wgsl
+// Fullscreen post-process chain run after the transparency resolve.
+// Bloom:     fs_bloom_prefilter (HDR -> half-res bright pass), fs_bloom_blur_h, fs_bloom_blur_v
+// Composite: fs_composite (exposure, bloom, tone mapping, color grading, vignette, chromatic aberration, gamma)
+// AA:        fs_fxaa (luma-based FXAA over the gamma-encoded composite)
+
+struct VSOut {
+  @builtin(position) position : vec4<f32>,
+  @location(0) uv : vec2<f32>,
+};
+
+struct PostProcessParams {
+  exposure: f32,
+  tone_mapper: u32,
+  bloom_threshold: f32,
+  bloom_intensity: f32,
+  saturation: f32,
+  contrast: f32,
+  vignette: f32,
+  chromatic_aberration: f32,
+  color_filter: vec3<f32>,
+  bloom_knee: f32,
+  // uv_scale is the current view's render rect as a fraction of the screen
+  // targets; the main view covers them with (1, 1).
+  uv_scale: vec2<f32>,
+};
+
+const TONE_MAPPER_ACES: u32 = 0u;
+const TONE_MAPPER_REINHARD: u32 = 1u;
+
+// Group 0:
+//  - 0: post-process parameters
+//  - 1: linear clamp sampler
+//  - 2: source texture (HDR scene color, bloom ping-pong, or LDR composite)
+//  - 3: secondary texture (bloom result for the composite, otherwise the source again)
+@group(0) @binding(0) var<uniform> params : PostProcessParams;
+@group(0) @binding(1) var sLinear : sampler;
+@group(0) @binding(2) var tSource : texture_2d<f32>;
+@group(0) @binding(3) var tSecondary : texture_2d<f32>;
+
+@vertex
+fn vs_main(@builtin(vertex_index) vi : u32) -> VSOut {
+  var out : VSOut;
+  let x = f32((vi << 1u) & 2u);
+  let y = f32(vi & 2u);
+  out.position = vec4<f32>(x * 2.0 - 1.0, 1.0 - y * 2.0, 0.0, 1.0);
+  out.uv = vec2<f32>(x, y);
+  return out;
+}
+
+fn source_texel() -> vec2<f32> {
+  let dims = textureDimensions(tSource);
+  return vec2<f32>(1.0 / f32(max(dims.x, 1u)), 1.0 / f32(max(dims.y, 1u)));
+}
+
+// sample_source reads tSource at a target UV, clamped to the view's render
+// rect so filtering never picks up pixels outside it.
+fn sample_source(uv: vec2<f32>) -> vec3<f32> {
+  let max_uv = params.uv_scale - source_texel() * 0.5;
+  return textureSampleLevel(tSource, sLinear, min(uv, max_uv), 0.0).rgb;
+}
+
+fn sample_secondary(uv: vec2<f32>) -> vec3<f32> {
+  let dims = textureDimensions(tSecondary);
+  let max_uv = params.uv_scale - vec2<f32>(0.5 / f32(max(dims.x, 1u)), 0.5 / f32(max(dims.y, 1u)));
+  return textureSampleLevel(tSecondary, sLinear, min(uv, max_uv), 0.0).rgb;
+}
+
+// target_uv maps a fullscreen UV of the view to the screen targets.
+fn target_uv(uv: vec2<f32>) -> vec2<f32> {
+  return uv * params.uv_scale;
+}
+
+fn luminance(c: vec3<f32>) -> f32 {
+  return dot(c, vec3<f32>(0.2126, 0.7152, 0.0722));
+}
+
+fn aces_tonemap(x: vec3<f32>) -> vec3<f32> {
+  let a = 2.51;
+  let b = 0.03;
+  let c = 2.43;
+  let d = 0.59;
+  let e = 0.14;
+  return clamp((x * (a * x + b)) / (x * (c * x + d) + e), vec3<f32>(0.0), vec3<f32>(1.0));
+}
+
+fn reinhard_tonemap(x: vec3<f32>) -> vec3<f32> {
+  return x / (vec3<f32>(1.0) + x);
+}
+
+fn tonemap(x: vec3<f32>) -> vec3<f32> {
+  if (params.tone_mapper == TONE_MAPPER_ACES) {
+    return aces_tonemap(x);
+  }
+  if (params.tone_mapper == TONE_MAPPER_REINHARD) {
+    return reinhard_tonemap(x);
+  }
+  return clamp(x, vec3<f32>(0.0), vec3<f32>(1.0));
+}
+
+@fragment
+fn fs_bloom_prefilter(in: VSOut) -> @location(0) vec4<f32> {
+  // Four bilinear taps straddling the full-res texel grid give a 4x4 box
+  // downsample, which keeps single bright pixels from flickering.
+  let texel = source_texel();
+  let uv = target_uv(in.uv);
+  var color = sample_source(uv + texel * vec2<f32>(-1.0, -1.0));
+  color += sample_source(uv + texel * vec2<f32>(1.0, -1.0));
+  color += sample_source(uv + texel * vec2<f32>(-1.0, 1.0));
+  color += sample_source(uv + texel * vec2<f32>(1.0, 1.0));
+  color = color * 0.25 * max(params.exposure, 0.0);
+
+  // Soft-knee threshold on the brightest channel.
+  let brightness = max(color.r, max(color.g, color.b));
+  let knee = max(params.bloom_threshold * params.bloom_knee, 1e-5);
+  let soft = clamp(brightness - params.bloom_threshold + knee, 0.0, 2.0 * knee);
+  let soft_curve = soft * soft / (4.0 * knee);
+  let contribution = max(soft_curve, brightness - params.bloom_threshold) / max(brightness, 1e-5);
+  return vec4<f32>(color * max(contribution, 0.0), 1.0);
+}
+
+fn bloom_blur(uv: vec2<f32>, axis: vec2<f32>) -> vec3<f32> {
+  let tap = source_texel() * axis;
+  // 9-tap Gaussian folded into 5 bilinear taps.
+  var color = sample_source(uv) * 0.2270270270;
+  color += sample_source(uv + tap * 1.3846153846) * 0.3162162162;
+  color += sample_source(uv - tap * 1.3846153846) * 0.3162162162;
+  color += sample_source(uv + tap * 3.2307692308) * 0.0702702703;
+  color += sample_source(uv - tap * 3.2307692308) * 0.0702702703;
+  return color;
+}
+
+@fragment
+fn fs_bloom_blur_h(in: VSOut) -> @location(0) vec4<f32> {
+  return vec4<f32>(bloom_blur(target_uv(in.uv), vec2<f32>(1.0, 0.0)), 1.0);
+}
+
+@fragment
+fn fs_bloom_blur_v(in: VSOut) -> @location(0) vec4<f32> {
+  return vec4<f32>(bloom_blur(target_uv(in.uv), vec2<f32>(0.0, 1.0)), 1.0);
+}
+
+@fragment
+fn fs_composite(in: VSOut) -> @location(0) vec4<f32> {
+  var hdr = sample_source(target_uv(in.uv));
+  if (params.chromatic_aberration > 0.0) {
+    let offset = (in.uv - vec2<f32>(0.5)) * params.chromatic_aberration * 0.02;
+    hdr.r = sample_source(target_uv(in.uv + offset)).r;
+    hdr.b = sample_source(target_uv(in.uv - offset)).b;
+  }
+  if (params.bloom_intensity > 0.0) {
+    hdr += sample_secondary(target_uv(in.uv)) * params.bloom_intensity;
+  }
+
+  var col = tonemap(hdr * max(params.exposure, 0.0));
+
+  // Color grading in display-referred space so neutral settings are exact.
+  col = col * params.color_filter;
+  col = mix(vec3<f32>(luminance(col)), col, params.saturation);
+  col = (col - vec3<f32>(0.5)) * params.contrast + vec3<f32>(0.5);
+  col = clamp(col, vec3<f32>(0.0), vec3<f32>(1.0));
+
+  if (params.vignette > 0.0) {
+    let d = length(in.uv - vec2<f32>(0.5)) * 1.41421356;
+    col = col * (1.0 - params.vignette * smoothstep(0.35, 1.0, d));
+  }
+
+  // Gamma correction
+  col = pow(col, vec3<f32>(1.0 / 2.2));
+
+  return vec4<f32>(col, 1.0);
+}
+
+const FXAA_REDUCE_MIN: f32 = 1.0 / 128.0;
+const FXAA_REDUCE_MUL: f32 = 1.0 / 8.0;
+const FXAA_SPAN_MAX: f32 = 8.0;
+
+fn fxaa_luma(c: vec3<f32>) -> f32 {
+  return dot(c, vec3<f32>(0.299, 0.587, 0.114));
+}
+
+@fragment
+fn fs_fxaa(in: VSOut) -> @location(0) vec4<f32> {
+  let texel = source_texel();
+  let uv = target_uv(in.uv);
+  let rgb_nw = sample_source(uv + vec2<f32>(-1.0, -1.0) * texel);
+  let rgb_ne = sample_source(uv + vec2<f32>(1.0, -1.0) * texel);
+  let rgb_sw = sample_source(uv + vec2<f32>(-1.0, 1.0) * texel);
+  let rgb_se = sample_source(uv + vec2<f32>(1.0, 1.0) * texel);
+  let rgb_m = sample_source(uv);
+
+  let luma_nw = fxaa_luma(rgb_nw);
+  let luma_ne = fxaa_luma(rgb_ne);
+  let luma_sw = fxaa_luma(rgb_sw);
+  let luma_se = fxaa_luma(rgb_se);
+  let luma_m = fxaa_luma(rgb_m);
+  let luma_min = min(luma_m, min(min(luma_nw, luma_ne), min(luma_sw, luma_se)));
+  let luma_max = max(luma_m, max(max(luma_nw, luma_ne), max(luma_sw, luma_se)));
+
+  var dir = vec2<f32>(
+    -((luma_nw + luma_ne) - (luma_sw + luma_se)),
+    (luma_nw + luma_sw) - (luma_ne + luma_se)
+  );
+  let dir_reduce = max((luma_nw + luma_ne + luma_sw + luma_se) * 0.25 * FXAA_REDUCE_MUL, FXAA_REDUCE_MIN);
+  let rcp_dir_min = 1.0 / (min(abs(dir.x), abs(dir.y)) + dir_reduce);
+  dir = clamp(dir * rcp_dir_min, vec2<f32>(-FXAA_SPAN_MAX), vec2<f32>(FXAA_SPAN_MAX)) * texel;
+
+  let rgb_a = 0.5 * (
+    sample_source(uv + dir * (1.0 / 3.0 - 0.5)) +
+    sample_source(uv + dir * (2.0 / 3.0 - 0.5))
+  );
+  let rgb_b = rgb_a * 0.5 + 0.25 * (
+    sample_source(uv + dir * -0.5) +
+    sample_source(uv + dir * 0.5)
+  );
+  let luma_b = fxaa_luma(rgb_b);
+  if (luma_b < luma_min || luma_b > luma_max) {
+    return vec4<f32>(rgb_a, 1.0);
+  }
+  return vec4<f32>(rgb_b, 1.0);
+}
@@ -1,7 +1,7 @@
 // resolve_transparency.wgsl
 // Fullscreen resolve for weighted blended OIT.
 // Inputs: opaque lit color, accumulated premultiplied transparent color sum, accumulated weight sum.
-// Output: final color = opaque + accum.rgb / max(weight, eps)
+// Output: linear HDR color = opaque + accum.rgb / max(weight, eps), consumed by post_process.wgsl
 
 struct VSOut {
   @builtin(position) position : vec4<f32>,
@@ -65,15 +65,6 @@ fn finite_depth_limit() -> f32 {
   return camera_far_t() - max(camera_far_t() * 1e-5, 1e-3);
 }
 
-fn aces_tonemap(x: vec3<f32>) -> vec3<f32> {
-  let a = 2.51;
-  let b = 0.03;
-  let c = 2.43;
-  let d = 0.59;
-  let e = 0.14;
-  return clamp((x * (a * x + b)) / (x * (c * x + d) + e), vec3<f32>(0.0), vec3<f32>(1.0));
-}
-
 fn sanitize_scene_depth(depth: f32) -> f32 {
   let far_t = camera_far_t();
   if (depth > 0.0 && depth < far_t) {
@@ -203,13 +194,8 @@ fn fs_main(@builtin(position) frag_pos: vec4<f32>, @location(0) uv: vec2<f32>) -
   let T = exp(-w_scale * a_unweighted);
   let transp = acc / max(w, 1e-5);
   // Composite: attenuate background by T, add normalized transparent contribution
-  var col = base * T + transp;
-  
-  // Tone mapping
-  col = aces_tonemap(col);
-  
-  // Gamma correction
-  col = pow(col, vec3<f32>(1.0 / 2.2));
-  
+  // Output stays linear HDR; tone mapping and gamma run in the post-process composite.
+  let col = base * T + transp;
+
   return vec4<f32>(col, 1.0);
 }
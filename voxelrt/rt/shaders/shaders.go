@@ -66,6 +66,9 @@ var TransparentOverlayWGSL string
 //go:embed resolve_transparency.wgsl
 var ResolveTransparencyWGSL string
 
+//go:embed post_process.wgsl
+var PostProcessWGSL string
+
 //go:embed hiz.wgsl
 var HiZWGSL string
 
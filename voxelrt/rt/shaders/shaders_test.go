@@ -27,6 +27,20 @@ func TestBillboardShadersApplyWebGPUClipZConversion(t *testing.T) {
 	}
 }
 
+func TestSpritesShaderBlendsOctahedralImpostorViews(t *testing.T) {
+	for _, needle := range []string{
+		"blend_indices: vec2<u32>",
+		"is_impostor: u32",
+		"let normal_offset = in.atlas_cols * in.atlas_rows / 2u;",
+		"impostor_normal = normalize(in.card_right * n.x + in.card_up * n.y + to_cam * n.z);",
+		"max(dot(impostor_normal, L), 0.0)",
+	} {
+		if !strings.Contains(SpritesWGSL, needle) {
+			t.Fatalf("sprites shader missing impostor contract %q", needle)
+		}
+	}
+}
+
 func TestAstronomicalShaderIsEmbedded(t *testing.T) {
 	for _, needle := range []string{
 		"struct AstronomicalRecord",
@@ -1036,3 +1050,40 @@ func TestAnalyticMediumShaderHasPixelSteppedAtmosphereHaze(t *testing.T) {
 		}
 	}
 }
+
+func TestPostProcessShaderOwnsToneMappingAfterResolve(t *testing.T) {
+	for _, needle := range []string{
+		"fn fs_bloom_prefilter",
+		"fn fs_bloom_blur_h",
+		"fn fs_bloom_blur_v",
+		"fn fs_composite",
+		"fn fs_fxaa",
+		"aces_tonemap",
+		"params.chromatic_aberration",
+		"params.vignette",
+		"pow(col, vec3<f32>(1.0 / 2.2))",
+	} {
+		if !strings.Contains(PostProcessWGSL, needle) {
+			t.Fatalf("post-process shader missing %q", needle)
+		}
+	}
+	for _, needle := range []string{"aces_tonemap", "1.0 / 2.2"} {
+		if strings.Contains(ResolveTransparencyWGSL, needle) {
+			t.Fatalf("resolve shader should output linear HDR, found %q", needle)
+		}
+	}
+}
+
+func TestDeferredLightingShaderProjectsDecals(t *testing.T) {
+	for _, needle := range []string{
+		"var<storage, read> decals: DecalList",
+		"var decal_atlas: texture_2d<f32>",
+		"var decal_sampler: sampler",
+		"fn apply_decals",
+		"base_color = apply_decals(",
+	} {
+		if !strings.Contains(DeferredLightingWGSL, needle) {
+			t.Fatalf("deferred lighting shader missing %q", needle)
+		}
+	}
+}
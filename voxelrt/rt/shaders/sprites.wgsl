@@ -29,6 +29,14 @@ struct SpriteInstance {
     atlas_cols: u32,
     atlas_rows: u32,
     billboard_mode: u32,
+    // Octahedral impostors blend sprite_index with two more views. Their
+    // atlas stacks color cells over normal/depth cells.
+    blend_indices: vec2<u32>,
+    blend_weights: vec2<f32>,
+    is_impostor: u32,
+    pad0: u32,
+    pad1: u32,
+    pad2: u32,
 };
 
 @group(0) @binding(0) var<uniform> camera: CameraData;
@@ -50,6 +58,12 @@ struct VSOut {
     @location(7) @interpolate(flat) is_unlit: u32,
     @location(8) @interpolate(flat) alpha_mode: u32,
     @location(9) sprite_center: vec3<f32>,
+    @location(10) @interpolate(flat) blend_indices: vec2<u32>,
+    @location(11) @interpolate(flat) blend_weights: vec2<f32>,
+    @location(12) @interpolate(flat) is_impostor: u32,
+    @location(13) @interpolate(flat) card_right: vec3<f32>,
+    @location(14) @interpolate(flat) card_up: vec3<f32>,
+    @location(15) @interpolate(flat) card_extent: f32,
 };
 
 fn get_camera_right() -> vec3<f32> {
@@ -92,6 +106,10 @@ fn vs_main(@builtin(vertex_index) vid: u32, @builtin(instance_index) iid: u32) -
     out.is_unlit = inst.is_unlit;
     out.alpha_mode = inst.alpha_mode;
     out.sprite_center = inst.pos;
+    out.blend_indices = inst.blend_indices;
+    out.blend_weights = inst.blend_weights;
+    out.is_impostor = inst.is_impostor;
+    out.card_extent = max(inst.size.x, inst.size.y);
 
     if (inst.is_ui != 0u) {
         // UI Space: inst.pos.xy is screen pixels, inst.size is pixels
@@ -115,6 +133,8 @@ fn vs_main(@builtin(vertex_index) vid: u32, @builtin(instance_index) iid: u32) -
             u = normalize(get_camera_up());
         }
 
+        out.card_right = r;
+        out.card_up = u;
         let world_pos = inst.pos + (r * corner.x * inst.size.x + u * corner.y * inst.size.y);
         out.position = raster_clip_pos(world_pos);
         out.world_pos = world_pos;
@@ -128,16 +148,44 @@ struct FSOut {
     @location(1) weight: f32,
 };
 
+fn atlas_cell_uv(index: u32, quad_uv: vec2<f32>, cols: u32, rows: u32) -> vec2<f32> {
+    let col_w = 1.0 / f32(cols);
+    let row_h = 1.0 / f32(rows);
+    let cell = vec2<f32>(f32(index % cols) * col_w, f32(index / cols) * row_h);
+    return cell + quad_uv * vec2<f32>(col_w, row_h);
+}
+
 @fragment
 fn fs_main(in: VSOut) -> FSOut {
     // Sprite Atlas Mapping
-    let col_w = 1.0 / f32(in.atlas_cols);
-    let row_h = 1.0 / f32(in.atlas_rows);
-    let sprite_x = f32(in.sprite_index % in.atlas_cols) * col_w;
-    let sprite_y = f32(in.sprite_index / in.atlas_cols) * row_h;
-    
-    let sprite_uv = vec2<f32>(sprite_x, sprite_y) + in.quad_uv * vec2<f32>(col_w, row_h);
-    let atlas_color = textureSample(atlas_tex, atlas_sampler, sprite_uv);
+    let sprite_uv = atlas_cell_uv(in.sprite_index, in.quad_uv, in.atlas_cols, in.atlas_rows);
+    var atlas_color = textureSample(atlas_tex, atlas_sampler, sprite_uv);
+
+    // Octahedral impostors: blend three baked views and fetch the matching
+    // normal/depth cells, which sit half an atlas below the color cells.
+    // Normals are stored in each view's card basis (x right, y up, z toward
+    // the viewer) so they can be rebuilt from the billboard axes.
+    var impostor_normal = vec3<f32>(0.0, 0.0, 1.0);
+    var impostor_depth = 0.5;
+    // is_impostor is not uniform, so the extra views use explicit-LOD samples.
+    if (in.is_impostor != 0u) {
+        let normal_offset = in.atlas_cols * in.atlas_rows / 2u;
+        let w0 = max(0.0, 1.0 - in.blend_weights.x - in.blend_weights.y);
+        let uv1 = atlas_cell_uv(in.blend_indices.x, in.quad_uv, in.atlas_cols, in.atlas_rows);
+        let uv2 = atlas_cell_uv(in.blend_indices.y, in.quad_uv, in.atlas_cols, in.atlas_rows);
+        atlas_color = atlas_color * w0 +
+            textureSampleLevel(atlas_tex, atlas_sampler, uv1, 0.0) * in.blend_weights.x +
+            textureSampleLevel(atlas_tex, atlas_sampler, uv2, 0.0) * in.blend_weights.y;
+        let nd = textureSampleLevel(atlas_tex, atlas_sampler, atlas_cell_uv(in.sprite_index + normal_offset, in.quad_uv, in.atlas_cols, in.atlas_rows), 0.0) * w0 +
+            textureSampleLevel(atlas_tex, atlas_sampler, atlas_cell_uv(in.blend_indices.x + normal_offset, in.quad_uv, in.atlas_cols, in.atlas_rows), 0.0) * in.blend_weights.x +
+            textureSampleLevel(atlas_tex, atlas_sampler, atlas_cell_uv(in.blend_indices.y + normal_offset, in.quad_uv, in.atlas_cols, in.atlas_rows), 0.0) * in.blend_weights.y;
+        let n = nd.xyz * 2.0 - 1.0;
+        if (dot(n, n) > 1e-4) {
+            let to_cam = normalize(cross(in.card_right, in.card_up));
+            impostor_normal = normalize(in.card_right * n.x + in.card_up * n.y + to_cam * n.z);
+        }
+        impostor_depth = nd.a;
+    }
 
     let tex_alpha = atlas_color.a;
     var alpha = in.color.a * tex_alpha;
@@ -161,6 +209,11 @@ fn fs_main(in: VSOut) -> FSOut {
         
         let view_ray = in.world_pos - camera.cam_pos.xyz;
         t_pixel = length(view_ray);
+        if (in.is_impostor != 0u) {
+            // Baked depth is 0.5 at the card plane and 1.0 at the near edge of
+            // the bounding sphere.
+            t_pixel = t_pixel - (impostor_depth - 0.5) * in.card_extent;
+        }
 
         let depth_delta = t_pixel - t_scene;
         let depth_fade = 1.0 - smoothstep(0.02, 0.45, depth_delta);
@@ -191,7 +244,10 @@ fn fs_main(in: VSOut) -> FSOut {
         let light_len_sq = dot(light_vec, light_vec);
         let view_len_sq = dot(view_vec, view_vec);
         var lighting = camera.ambient_color.rgb;
-        if (light_len_sq > 1e-6 && view_len_sq > 1e-6) {
+        if (in.is_impostor != 0u && light_len_sq > 1e-6) {
+            let L = light_vec * inverseSqrt(light_len_sq);
+            lighting = lighting + vec3<f32>(0.65 * max(dot(impostor_normal, L), 0.0));
+        } else if (light_len_sq > 1e-6 && view_len_sq > 1e-6) {
             let L = light_vec * inverseSqrt(light_len_sq);
             let N = view_vec * inverseSqrt(view_len_sq);
             // Billboards are effectively two-sided cards. Using the center-facing
@@ -29,3 +29,10 @@ fn fs_main(in: VertexOutput) -> @location(0) vec4<f32> {
     let tex_color = textureSample(t_diffuse, s_diffuse, in.uv);
     return vec4<f32>(in.color.rgb, in.color.a * tex_color.r);
 }
+
+// fs_image draws textured overlay rects, such as UI images, with the same
+// vertices and a color texture bound in place of the glyph atlas.
+@fragment
+fn fs_image(in: VertexOutput) -> @location(0) vec4<f32> {
+    return textureSample(t_diffuse, s_diffuse, in.uv) * in.color;
+}
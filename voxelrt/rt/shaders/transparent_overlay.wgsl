@@ -391,8 +391,14 @@ fn world_to_uv(p_ws: vec3<f32>) -> vec2<f32> {
   return vec2<f32>(ndc.x * 0.5 + 0.5, -ndc.y * 0.5 + 0.5);
 }
 
+// view_dims clamps a screen target's size to the current view's render rect,
+// the top-left screen_size pixels. The main view covers the whole target.
+fn view_dims(tex_dims: vec2<u32>) -> vec2<u32> {
+  return max(min(tex_dims, vec2<u32>(uCamera.screen_size)), vec2<u32>(1u));
+}
+
 fn sample_opaque_lit(uv: vec2<f32>) -> vec3<f32> {
-  let dims = textureDimensions(in_opaque_lit);
+  let dims = view_dims(textureDimensions(in_opaque_lit));
   let clamped_uv = clamp_uv01(uv);
   let px = vec2<i32>(
     clamp(i32(clamped_uv.x * f32(dims.x)), 0, i32(dims.x) - 1),
@@ -1206,7 +1212,7 @@ struct FSOut {
 
 @fragment
 fn fs_main(@builtin(position) frag_pos: vec4<f32>, @location(0) uv: vec2<f32>) -> FSOut {
-  let dims = textureDimensions(in_depth);
+  let dims = view_dims(textureDimensions(in_depth));
   let ipos = vec2<i32>( clamp(i32(frag_pos.x), 0, i32(dims.x) - 1),
                         clamp(i32(frag_pos.y), 0, i32(dims.y) - 1) );
   let tile_index = tile_index_for_frag_pos(frag_pos);
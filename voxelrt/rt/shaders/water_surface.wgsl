@@ -425,8 +425,14 @@ fn point_inside_water_footprint(pos: vec3<f32>, water: WaterRecord) -> bool {
   return local.x <= water.extents.x + EPS && local.y <= water.extents.y + EPS;
 }
 
+// view_dims clamps a screen target's size to the current view's render rect,
+// the top-left screen_size pixels. The main view covers the whole target.
+fn view_dims(tex_dims: vec2<u32>) -> vec2<u32> {
+  return max(min(tex_dims, vec2<u32>(camera.screen_size)), vec2<u32>(1u));
+}
+
 fn sample_opaque(uv: vec2<f32>) -> vec3<f32> {
-  let dims = textureDimensions(opaque_lit);
+  let dims = view_dims(textureDimensions(opaque_lit));
   let coord = vec2<i32>(
     clamp(i32(uv.x * f32(dims.x)), 0, i32(dims.x) - 1),
     clamp(i32(uv.y * f32(dims.y)), 0, i32(dims.y) - 1),
@@ -435,7 +441,7 @@ fn sample_opaque(uv: vec2<f32>) -> vec3<f32> {
 }
 
 fn sample_scene_depth(uv: vec2<f32>) -> f32 {
-  let dims = textureDimensions(scene_depth);
+  let dims = view_dims(textureDimensions(scene_depth));
   let coord = vec2<i32>(
     clamp(i32(uv.x * f32(dims.x)), 0, i32(dims.x) - 1),
     clamp(i32(uv.y * f32(dims.y)), 0, i32(dims.y) - 1),
@@ -470,7 +476,7 @@ fn vs_main(@builtin(vertex_index) vi: u32, @builtin(instance_index) ii: u32) ->
 
 @fragment
 fn fs_main(in: VSOut) -> FSOut {
-  let dims = textureDimensions(scene_depth);
+  let dims = view_dims(textureDimensions(scene_depth));
   let ipos = vec2<i32>(
     clamp(i32(in.position.x), 0, i32(dims.x) - 1),
     clamp(i32(in.position.y), 0, i32(dims.y) - 1),
@@ -0,0 +1,473 @@
+package volume
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// XBrickMapEditMagic prefixes every encoded edit record.
+var XBrickMapEditMagic = []byte{'G', 'K', 'X', 'E', 'D', 'I', 'T', '\n'}
+
+const (
+	xbrickMapEditVersion = 1
+
+	editDeltaHasBefore = 1
+	editDeltaHasAfter  = 1 << 1
+
+	// brickSnapshotBytes approximates the retained size of one brick copy for
+	// journal memory accounting.
+	brickSnapshotBytes = brickVoxelCount + 16
+)
+
+// BrickDelta is the before/after content of one brick touched by an edit.
+// A nil state means the brick did not exist (was empty).
+type BrickDelta struct {
+	Key    [6]int
+	Before *Brick
+	After  *Brick
+}
+
+// EditRecord is one committed transaction. Deltas are sorted by brick key.
+type EditRecord struct {
+	Label       string
+	CoalesceKey string
+	Deltas      []BrickDelta
+}
+
+// ByteSize is the approximate memory retained by the record's brick copies.
+func (r *EditRecord) ByteSize() int {
+	size := 0
+	for _, delta := range r.Deltas {
+		if delta.Before != nil {
+			size += brickSnapshotBytes
+		}
+		if delta.After != nil {
+			size += brickSnapshotBytes
+		}
+	}
+	return size
+}
+
+type EditJournalOptions struct {
+	// MaxBytes caps the memory held by undo and redo history. The oldest undo
+	// records are dropped first; the latest record is always kept. Zero means
+	// unbounded.
+	MaxBytes int
+	// MaxRecords caps the undo depth. Zero means unbounded.
+	MaxRecords int
+}
+
+// EditJournal records brick-level deltas for edits made to one XBrickMap
+// and supports undo, redo and replay.
+type EditJournal struct {
+	target *XBrickMap
+	opts   EditJournalOptions
+	undo   []EditRecord
+	redo   []EditRecord
+	open   *EditTransaction
+	bytes  int
+}
+
+func NewEditJournal(target *XBrickMap, opts EditJournalOptions) *EditJournal {
+	return &EditJournal{target: target, opts: opts}
+}
+
+// EditTransaction captures the state of each brick the first time it is
+// touched. All edits must go through the transaction so the capture happens
+// before the mutation.
+type EditTransaction struct {
+	// CoalesceKey merges this transaction into the previous record when both
+	// share the same non-empty key, e.g. every dab of one sculpt stroke.
+	CoalesceKey string
+
+	journal *EditJournal
+	label   string
+	before  map[[6]int]*Brick
+	done    bool
+}
+
+// Begin opens a transaction. Only one transaction may be open at a time.
+func (j *EditJournal) Begin(label string) (*EditTransaction, error) {
+	if j.open != nil {
+		return nil, fmt.Errorf("edit transaction %q is still open", j.open.label)
+	}
+	tx := &EditTransaction{
+		journal: j,
+		label:   label,
+		before:  make(map[[6]int]*Brick),
+	}
+	j.open = tx
+	return tx, nil
+}
+
+func (tx *EditTransaction) SetVoxel(gx, gy, gz int, val uint8) {
+	if tx.done {
+		return
+	}
+	_, key := sectorBrickKeyForVoxel(gx, gy, gz)
+	tx.capture(key)
+	tx.journal.target.SetVoxel(gx, gy, gz, val)
+}
+
+// CaptureRegion snapshots every brick overlapping the inclusive voxel region
+// so callers can mutate the map directly afterwards.
+func (tx *EditTransaction) CaptureRegion(minV, maxV [3]int) {
+	if tx.done {
+		return
+	}
+	_, minKey := sectorBrickKeyForVoxel(minV[0], minV[1], minV[2])
+	_, maxKey := sectorBrickKeyForVoxel(maxV[0], maxV[1], maxV[2])
+	minBrick := [3]int{minKey[0]*SectorBricks + minKey[3], minKey[1]*SectorBricks + minKey[4], minKey[2]*SectorBricks + minKey[5]}
+	maxBrick := [3]int{maxKey[0]*SectorBricks + maxKey[3], maxKey[1]*SectorBricks + maxKey[4], maxKey[2]*SectorBricks + maxKey[5]}
+	for bx := minBrick[0]; bx <= maxBrick[0]; bx++ {
+		for by := minBrick[1]; by <= maxBrick[1]; by++ {
+			for bz := minBrick[2]; bz <= maxBrick[2]; bz++ {
+				_, key := sectorBrickKeyForVoxel(bx*BrickSize, by*BrickSize, bz*BrickSize)
+				tx.capture(key)
+			}
+		}
+	}
+}
+
+// ApplyBrush captures the brush region and applies edit.
+func (tx *EditTransaction) ApplyBrush(edit BrushEdit) BrushEditResult {
+	if tx.done || edit.Shape == nil {
+		return BrushEditResult{}
+	}
+	if minI, maxI, ok := brushVoxelRegion(tx.journal.target, edit); ok {
+		tx.CaptureRegion(minI, maxI)
+	}
+	return ApplyBrush(tx.journal.target, edit)
+}
+
+func (tx *EditTransaction) capture(key [6]int) {
+	if _, ok := tx.before[key]; ok {
+		return
+	}
+	tx.before[key] = tx.journal.target.brickSnapshot(key)
+}
+
+// Commit closes the transaction and pushes its deltas onto the undo stack,
+// clearing redo history. Bricks whose content did not change are dropped;
+// a transaction with no changes records nothing and returns false.
+func (tx *EditTransaction) Commit() bool {
+	if tx.done {
+		return false
+	}
+	tx.done = true
+	j := tx.journal
+	j.open = nil
+
+	record := EditRecord{Label: tx.label, CoalesceKey: tx.CoalesceKey}
+	for key, before := range tx.before {
+		after := j.target.brickSnapshot(key)
+		if bricksEqual(before, after) {
+			continue
+		}
+		record.Deltas = append(record.Deltas, BrickDelta{Key: key, Before: before, After: after})
+	}
+	if len(record.Deltas) == 0 {
+		return false
+	}
+	sortBrickDeltas(record.Deltas)
+
+	j.dropRedo()
+	if n := len(j.undo); n > 0 && record.CoalesceKey != "" && j.undo[n-1].CoalesceKey == record.CoalesceKey {
+		j.bytes -= j.undo[n-1].ByteSize()
+		j.undo[n-1] = coalesceEditRecords(j.undo[n-1], record)
+		j.bytes += j.undo[n-1].ByteSize()
+	} else {
+		j.undo = append(j.undo, record)
+		j.bytes += record.ByteSize()
+	}
+	j.enforceLimits()
+	return true
+}
+
+// Cancel restores every captured brick and closes the transaction.
+func (tx *EditTransaction) Cancel() {
+	if tx.done {
+		return
+	}
+	tx.done = true
+	tx.journal.open = nil
+	for key, before := range tx.before {
+		tx.journal.target.restoreBrick(key, before)
+	}
+}
+
+func (j *EditJournal) CanUndo() bool { return j.open == nil && len(j.undo) > 0 }
+func (j *EditJournal) CanRedo() bool { return j.open == nil && len(j.redo) > 0 }
+
+// Undo reverts the most recent record. It returns false when there is
+// nothing to undo or a transaction is open.
+func (j *EditJournal) Undo() bool {
+	if !j.CanUndo() {
+		return false
+	}
+	record := j.undo[len(j.undo)-1]
+	j.undo = j.undo[:len(j.undo)-1]
+	for i := len(record.Deltas) - 1; i >= 0; i-- {
+		j.target.restoreBrick(record.Deltas[i].Key, record.Deltas[i].Before)
+	}
+	j.redo = append(j.redo, record)
+	return true
+}
+
+func (j *EditJournal) Redo() bool {
+	if !j.CanRedo() {
+		return false
+	}
+	record := j.redo[len(j.redo)-1]
+	j.redo = j.redo[:len(j.redo)-1]
+	for _, delta := range record.Deltas {
+		j.target.restoreBrick(delta.Key, delta.After)
+	}
+	j.undo = append(j.undo, record)
+	return true
+}
+
+// Records returns the undo history, oldest first.
+func (j *EditJournal) Records() []EditRecord {
+	return append([]EditRecord(nil), j.undo...)
+}
+
+// ByteSize is the approximate memory held by undo and redo history.
+func (j *EditJournal) ByteSize() int {
+	return j.bytes
+}
+
+func (j *EditJournal) Clear() {
+	j.undo = nil
+	j.redo = nil
+	j.bytes = 0
+}
+
+func (j *EditJournal) dropRedo() {
+	for _, record := range j.redo {
+		j.bytes -= record.ByteSize()
+	}
+	j.redo = nil
+}
+
+func (j *EditJournal) enforceLimits() {
+	for len(j.undo) > 1 {
+		overRecords := j.opts.MaxRecords > 0 && len(j.undo) > j.opts.MaxRecords
+		overBytes := j.opts.MaxBytes > 0 && j.bytes > j.opts.MaxBytes
+		if !overRecords && !overBytes {
+			return
+		}
+		j.bytes -= j.undo[0].ByteSize()
+		j.undo = j.undo[1:]
+	}
+}
+
+// ReplayEdits applies the after state of each record, in order, to dst. It is
+// the forward half of a journal, e.g. for rebuilding a replay from a fresh
+// copy of the starting map.
+func ReplayEdits(dst *XBrickMap, records ...EditRecord) {
+	if dst == nil {
+		return
+	}
+	for _, record := range records {
+		for _, delta := range record.Deltas {
+			dst.restoreBrick(delta.Key, delta.After)
+		}
+	}
+}
+
+func coalesceEditRecords(prev, next EditRecord) EditRecord {
+	merged := make(map[[6]int]BrickDelta, len(prev.Deltas)+len(next.Deltas))
+	for _, delta := range prev.Deltas {
+		merged[delta.Key] = delta
+	}
+	for _, delta := range next.Deltas {
+		if existing, ok := merged[delta.Key]; ok {
+			existing.After = delta.After
+			merged[delta.Key] = existing
+			continue
+		}
+		merged[delta.Key] = delta
+	}
+	out := EditRecord{Label: prev.Label, CoalesceKey: prev.CoalesceKey}
+	for _, delta := range merged {
+		if bricksEqual(delta.Before, delta.After) {
+			continue
+		}
+		out.Deltas = append(out.Deltas, delta)
+	}
+	sortBrickDeltas(out.Deltas)
+	return out
+}
+
+func sortBrickDeltas(deltas []BrickDelta) {
+	sort.Slice(deltas, func(i, j int) bool {
+		a, b := deltas[i].Key, deltas[j].Key
+		for k := 0; k < 6; k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+}
+
+func bricksEqual(a, b *Brick) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Payload == b.Payload
+}
+
+// brickSnapshot returns a copy of the brick at key, or nil if it is absent.
+func (x *XBrickMap) brickSnapshot(key [6]int) *Brick {
+	sector, ok := x.Sectors[[3]int{key[0], key[1], key[2]}]
+	if !ok {
+		return nil
+	}
+	brick := sector.GetBrick(key[3], key[4], key[5])
+	if brick == nil || brick.IsEmpty() {
+		return nil
+	}
+	return brick.Copy()
+}
+
+// restoreBrick rewrites the brick at key to state through SetVoxel, so dirty
+// tracking, AABB updates and GPU edit queuing behave as for any other edit.
+func (x *XBrickMap) restoreBrick(key [6]int, state *Brick) {
+	current := x.brickSnapshot(key)
+	originX := key[0]*SectorSize + key[3]*BrickSize
+	originY := key[1]*SectorSize + key[4]*BrickSize
+	originZ := key[2]*SectorSize + key[5]*BrickSize
+	for vx := 0; vx < BrickSize; vx++ {
+		for vy := 0; vy < BrickSize; vy++ {
+			for vz := 0; vz < BrickSize; vz++ {
+				var want, have uint8
+				if state != nil {
+					want = state.Payload[vx][vy][vz]
+				}
+				if current != nil {
+					have = current.Payload[vx][vy][vz]
+				}
+				if want != have {
+					x.SetVoxel(originX+vx, originY+vy, originZ+vz, want)
+				}
+			}
+		}
+	}
+}
+
+// EncodeEditRecord serializes record as a compact edit stream suitable for
+// sending over the network. Brick states use the binary XBrickMap brick
+// encoding.
+func EncodeEditRecord(record EditRecord) []byte {
+	var out bytes.Buffer
+	out.Write(XBrickMapEditMagic)
+	var buf [4]byte
+	binary.LittleEndian.PutUint16(buf[:2], xbrickMapEditVersion)
+	out.Write(buf[:2])
+	writeEditString(&out, record.Label)
+	writeEditString(&out, record.CoalesceKey)
+	binary.LittleEndian.PutUint32(buf[:], uint32(len(record.Deltas)))
+	out.Write(buf[:])
+	for _, delta := range record.Deltas {
+		for _, v := range delta.Key {
+			binary.LittleEndian.PutUint32(buf[:], uint32(int32(v)))
+			out.Write(buf[:])
+		}
+		var flags byte
+		if delta.Before != nil {
+			flags |= editDeltaHasBefore
+		}
+		if delta.After != nil {
+			flags |= editDeltaHasAfter
+		}
+		out.WriteByte(flags)
+		if delta.Before != nil {
+			encodeXBrickMapBrick(&out, delta.Before)
+		}
+		if delta.After != nil {
+			encodeXBrickMapBrick(&out, delta.After)
+		}
+	}
+	return out.Bytes()
+}
+
+func DecodeEditRecord(data []byte) (EditRecord, error) {
+	record := EditRecord{}
+	if !bytes.HasPrefix(data, XBrickMapEditMagic) {
+		return record, fmt.Errorf("not an xbrickmap edit record")
+	}
+	r := bytes.NewReader(data[len(XBrickMapEditMagic):])
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:2]); err != nil {
+		return record, err
+	}
+	if version := binary.LittleEndian.Uint16(buf[:2]); version != xbrickMapEditVersion {
+		return record, fmt.Errorf("unsupported xbrickmap edit version %d", version)
+	}
+	var err error
+	if record.Label, err = readEditString(r); err != nil {
+		return record, err
+	}
+	if record.CoalesceKey, err = readEditString(r); err != nil {
+		return record, err
+	}
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return record, err
+	}
+	count := int(binary.LittleEndian.Uint32(buf[:]))
+	if count > r.Len() {
+		return record, fmt.Errorf("xbrickmap edit record declares %d deltas in %d bytes", count, r.Len())
+	}
+	record.Deltas = make([]BrickDelta, 0, count)
+	for i := 0; i < count; i++ {
+		var delta BrickDelta
+		for k := range delta.Key {
+			if _, err := io.ReadFull(r, buf[:]); err != nil {
+				return record, err
+			}
+			delta.Key[k] = int(int32(binary.LittleEndian.Uint32(buf[:])))
+		}
+		flags, err := r.ReadByte()
+		if err != nil {
+			return record, err
+		}
+		if flags&editDeltaHasBefore != 0 {
+			if delta.Before, err = decodeXBrickMapBrick(r); err != nil {
+				return record, err
+			}
+		}
+		if flags&editDeltaHasAfter != 0 {
+			if delta.After, err = decodeXBrickMapBrick(r); err != nil {
+				return record, err
+			}
+		}
+		record.Deltas = append(record.Deltas, delta)
+	}
+	return record, nil
+}
+
+// writeEditString writes value with a uvarint length prefix.
+func writeEditString(out *bytes.Buffer, value string) {
+	out.Write(binary.AppendUvarint(nil, uint64(len(value))))
+	out.WriteString(value)
+}
+
+func readEditString(r *bytes.Reader) (string, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return "", err
+	}
+	if length > uint64(r.Len()) {
+		return "", fmt.Errorf("edit string length %d exceeds the %d remaining bytes", length, r.Len())
+	}
+	value := make([]byte, length)
+	if _, err := io.ReadFull(r, value); err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
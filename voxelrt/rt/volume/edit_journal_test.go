@@ -0,0 +1,149 @@
+package volume
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestEditJournalUndoRedoRestoresBricks(t *testing.T) {
+	xbm := NewXBrickMap()
+	Cube(xbm, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{15, 15, 15}, 2)
+	original, _ := EncodeXBrickMap(xbm, XBrickMapWriteOptions{})
+	journal := NewEditJournal(xbm, EditJournalOptions{})
+
+	tx, err := journal.Begin("carve")
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	tx.ApplyBrush(BrushEdit{Shape: SDFSphere{Center: mgl32.Vec3{8, 8, 8}, Radius: 4}, Operation: BrushSubtract})
+	tx.SetVoxel(-3, 0, 0, 7)
+	if !tx.Commit() {
+		t.Fatal("expected commit to record changes")
+	}
+	edited, _ := EncodeXBrickMap(xbm, XBrickMapWriteOptions{})
+
+	if !journal.Undo() {
+		t.Fatal("expected undo to succeed")
+	}
+	if got, _ := EncodeXBrickMap(xbm, XBrickMapWriteOptions{}); string(got) != string(original) {
+		t.Fatal("expected undo to restore the original map")
+	}
+	if !journal.Redo() {
+		t.Fatal("expected redo to succeed")
+	}
+	if got, _ := EncodeXBrickMap(xbm, XBrickMapWriteOptions{}); string(got) != string(edited) {
+		t.Fatal("expected redo to restore the edited map")
+	}
+	if journal.Redo() {
+		t.Fatal("expected nothing left to redo")
+	}
+}
+
+func TestEditJournalCoalescesStrokeAndDropsNoOps(t *testing.T) {
+	xbm := NewXBrickMap()
+	journal := NewEditJournal(xbm, EditJournalOptions{})
+	for i := 0; i < 5; i++ {
+		tx, _ := journal.Begin("stroke")
+		tx.CoalesceKey = "stroke-1"
+		tx.SetVoxel(i*4, 0, 0, 3)
+		tx.Commit()
+	}
+	if records := journal.Records(); len(records) != 1 || len(records[0].Deltas) != 3 {
+		t.Fatalf("expected one coalesced record over 3 bricks, got %+v", journal.Records())
+	}
+
+	tx, _ := journal.Begin("noop")
+	tx.SetVoxel(0, 0, 0, 3)
+	if tx.Commit() {
+		t.Fatal("expected rewriting an identical value to record nothing")
+	}
+
+	journal.Undo()
+	if xbm.GetVoxelCount() != 0 {
+		t.Fatalf("expected undo of the stroke to clear every dab, got %d voxels", xbm.GetVoxelCount())
+	}
+}
+
+func TestEditJournalCancelRestoresCapturedBricks(t *testing.T) {
+	xbm := NewXBrickMap()
+	xbm.SetVoxel(1, 1, 1, 4)
+	journal := NewEditJournal(xbm, EditJournalOptions{})
+	tx, _ := journal.Begin("cancelled")
+	tx.SetVoxel(1, 1, 1, 0)
+	tx.SetVoxel(40, 1, 1, 5)
+	if _, err := journal.Begin("nested"); err == nil {
+		t.Fatal("expected a second open transaction to be rejected")
+	}
+	tx.Cancel()
+	if _, value := xbm.GetVoxel(1, 1, 1); value != 4 {
+		t.Fatalf("expected cancelled edit to restore voxel, got %d", value)
+	}
+	if found, _ := xbm.GetVoxel(40, 1, 1); found || len(xbm.Sectors) != 1 {
+		t.Fatal("expected cancelled edit to remove the created sector")
+	}
+	if journal.CanUndo() {
+		t.Fatal("expected cancelled edit to record nothing")
+	}
+}
+
+func TestEditJournalEnforcesMemoryCap(t *testing.T) {
+	xbm := NewXBrickMap()
+	journal := NewEditJournal(xbm, EditJournalOptions{MaxBytes: 3 * brickSnapshotBytes})
+	for i := 0; i < 6; i++ {
+		tx, _ := journal.Begin("dab")
+		tx.SetVoxel(i*BrickSize, 0, 0, 1)
+		tx.Commit()
+	}
+	// Each record holds only an after state, so three fit under the cap.
+	if got := len(journal.Records()); got != 3 {
+		t.Fatalf("expected memory cap to keep 3 records, got %d", got)
+	}
+	if journal.ByteSize() > 3*brickSnapshotBytes {
+		t.Fatalf("expected journal to stay under its cap, got %d bytes", journal.ByteSize())
+	}
+}
+
+func TestReplayEditsFromEncodedStream(t *testing.T) {
+	start := NewXBrickMap()
+	Cube(start, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{20, 4, 4}, 1)
+	startData, _ := EncodeXBrickMap(start, XBrickMapWriteOptions{})
+
+	live, _ := DecodeXBrickMap(startData)
+	journal := NewEditJournal(live, EditJournalOptions{})
+	for i := 0; i < 3; i++ {
+		tx, _ := journal.Begin("explosion")
+		tx.ApplyBrush(BrushEdit{Shape: SDFSphere{Center: mgl32.Vec3{float32(i * 8), 2, 2}, Radius: 3}, Operation: BrushSubtract})
+		tx.Commit()
+	}
+
+	replica, _ := DecodeXBrickMap(startData)
+	for _, record := range journal.Records() {
+		decoded, err := DecodeEditRecord(EncodeEditRecord(record))
+		if err != nil {
+			t.Fatalf("DecodeEditRecord failed: %v", err)
+		}
+		if decoded.Label != record.Label || len(decoded.Deltas) != len(record.Deltas) {
+			t.Fatalf("decoded record mismatch: %+v", decoded)
+		}
+		ReplayEdits(replica, decoded)
+	}
+	assertXBrickMapsEqual(t, live, replica)
+}
+
+func TestEncodeEditRecordKeepsLongLabels(t *testing.T) {
+	record := EditRecord{Label: strings.Repeat("x", 70000), CoalesceKey: "paint"}
+	decoded, err := DecodeEditRecord(EncodeEditRecord(record))
+	if err != nil {
+		t.Fatalf("DecodeEditRecord failed: %v", err)
+	}
+	if decoded.Label != record.Label || decoded.CoalesceKey != record.CoalesceKey {
+		t.Fatalf("expected a 70000-byte label to round-trip, got %d bytes and key %q", len(decoded.Label), decoded.CoalesceKey)
+	}
+
+	truncated := EncodeEditRecord(record)[:len(XBrickMapEditMagic)+2+100]
+	if _, err := DecodeEditRecord(truncated); err == nil {
+		t.Fatal("expected a truncated label to fail to decode")
+	}
+}
@@ -0,0 +1,180 @@
+package volume
+
+import (
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// MeshQuad is one merged face produced by GreedyMesh. Corners are in voxel
+// units and wound counter-clockwise when viewed from the Normal side.
+type MeshQuad struct {
+	Corners      [4]mgl32.Vec3
+	Normal       mgl32.Vec3
+	PaletteIndex uint8
+}
+
+// VoxelMeshGroup collects every quad that shares a palette index.
+type VoxelMeshGroup struct {
+	PaletteIndex uint8
+	Quads        []MeshQuad
+}
+
+// VoxelMesh is the polygon surface of an XBrickMap, grouped by palette index
+// in ascending order.
+type VoxelMesh struct {
+	Groups []VoxelMeshGroup
+}
+
+func (m *VoxelMesh) QuadCount() int {
+	count := 0
+	for _, group := range m.Groups {
+		count += len(group.Quads)
+	}
+	return count
+}
+
+// GreedyMesh extracts the exposed faces of xbm and merges coplanar faces with
+// the same palette index into maximal rectangles.
+func GreedyMesh(xbm *XBrickMap) *VoxelMesh {
+	mesh := &VoxelMesh{}
+	if xbm == nil || xbm.GetVoxelCount() == 0 {
+		return mesh
+	}
+	minB, maxB := xbm.ComputeAABB()
+	origin := [3]int{int(minB.X()), int(minB.Y()), int(minB.Z())}
+	dims := [3]int{
+		int(maxB.X()) - origin[0],
+		int(maxB.Y()) - origin[1],
+		int(maxB.Z()) - origin[2],
+	}
+	dense := make([]uint8, dims[0]*dims[1]*dims[2])
+	denseIndex := func(x, y, z int) int {
+		return x + dims[0]*(y+dims[1]*z)
+	}
+	for sKey, sector := range xbm.Sectors {
+		for i := 0; i < 64; i++ {
+			if sector.BrickMask64&(1<<i) == 0 {
+				continue
+			}
+			bx, by, bz := i%4, (i/4)%4, i/16
+			brick := sector.GetBrick(bx, by, bz)
+			if brick == nil || brick.IsEmpty() {
+				continue
+			}
+			ox := sKey[0]*SectorSize + bx*BrickSize - origin[0]
+			oy := sKey[1]*SectorSize + by*BrickSize - origin[1]
+			oz := sKey[2]*SectorSize + bz*BrickSize - origin[2]
+			for vz := 0; vz < BrickSize; vz++ {
+				for vy := 0; vy < BrickSize; vy++ {
+					for vx := 0; vx < BrickSize; vx++ {
+						if value := brick.Payload[vx][vy][vz]; value != 0 {
+							dense[denseIndex(ox+vx, oy+vy, oz+vz)] = value
+						}
+					}
+				}
+			}
+		}
+	}
+	at := func(p [3]int) uint8 {
+		if p[0] < 0 || p[1] < 0 || p[2] < 0 || p[0] >= dims[0] || p[1] >= dims[1] || p[2] >= dims[2] {
+			return 0
+		}
+		return dense[denseIndex(p[0], p[1], p[2])]
+	}
+
+	groups := make(map[uint8]*VoxelMeshGroup)
+	for axis := 0; axis < 3; axis++ {
+		u, v := (axis+1)%3, (axis+2)%3
+		mask := make([]uint8, dims[u]*dims[v])
+		for _, dir := range [2]int{1, -1} {
+			for slice := 0; slice < dims[axis]; slice++ {
+				for j := 0; j < dims[v]; j++ {
+					for i := 0; i < dims[u]; i++ {
+						var p [3]int
+						p[axis], p[u], p[v] = slice, i, j
+						value := at(p)
+						neighbor := p
+						neighbor[axis] += dir
+						if value != 0 && at(neighbor) == 0 {
+							mask[i+j*dims[u]] = value
+						} else {
+							mask[i+j*dims[u]] = 0
+						}
+					}
+				}
+				for j := 0; j < dims[v]; j++ {
+					for i := 0; i < dims[u]; {
+						value := mask[i+j*dims[u]]
+						if value == 0 {
+							i++
+							continue
+						}
+						width := 1
+						for i+width < dims[u] && mask[i+width+j*dims[u]] == value {
+							width++
+						}
+						height := 1
+					grow:
+						for j+height < dims[v] {
+							for k := 0; k < width; k++ {
+								if mask[i+k+(j+height)*dims[u]] != value {
+									break grow
+								}
+							}
+							height++
+						}
+						for h := 0; h < height; h++ {
+							for k := 0; k < width; k++ {
+								mask[i+k+(j+h)*dims[u]] = 0
+							}
+						}
+						plane := slice
+						if dir > 0 {
+							plane++
+						}
+						quad := greedyQuad(axis, u, v, dir, plane, i, j, width, height, origin)
+						quad.PaletteIndex = value
+						group := groups[value]
+						if group == nil {
+							group = &VoxelMeshGroup{PaletteIndex: value}
+							groups[value] = group
+						}
+						group.Quads = append(group.Quads, quad)
+						i += width
+					}
+				}
+			}
+		}
+	}
+
+	keys := make([]int, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, int(key))
+	}
+	sort.Ints(keys)
+	for _, key := range keys {
+		mesh.Groups = append(mesh.Groups, *groups[uint8(key)])
+	}
+	return mesh
+}
+
+func greedyQuad(axis, u, v, dir, plane, i, j, width, height int, origin [3]int) MeshQuad {
+	corner := func(du, dv int) mgl32.Vec3 {
+		var p mgl32.Vec3
+		p[axis] = float32(plane + origin[axis])
+		p[u] = float32(i + du + origin[u])
+		p[v] = float32(j + dv + origin[v])
+		return p
+	}
+	var normal mgl32.Vec3
+	normal[axis] = float32(dir)
+	quad := MeshQuad{Normal: normal}
+	// (u, v, axis) is a right-handed basis, so u→v winding faces +axis.
+	if dir > 0 {
+		quad.Corners = [4]mgl32.Vec3{corner(0, 0), corner(width, 0), corner(width, height), corner(0, height)}
+	} else {
+		quad.Corners = [4]mgl32.Vec3{corner(0, 0), corner(0, height), corner(width, height), corner(width, 0)}
+	}
+	return quad
+}
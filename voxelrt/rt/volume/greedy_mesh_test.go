@@ -0,0 +1,70 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestGreedyMeshMergesSolidCubeIntoSixQuads(t *testing.T) {
+	xbm := NewXBrickMap()
+	Cube(xbm, mgl32.Vec3{-2, 0, 3}, mgl32.Vec3{1, 3, 6}, 4)
+
+	mesh := GreedyMesh(xbm)
+
+	if len(mesh.Groups) != 1 || mesh.Groups[0].PaletteIndex != 4 {
+		t.Fatalf("expected one palette group for index 4, got %+v", mesh.Groups)
+	}
+	if got := mesh.QuadCount(); got != 6 {
+		t.Fatalf("expected 6 merged quads, got %d", got)
+	}
+	for _, quad := range mesh.Groups[0].Quads {
+		edgeA := quad.Corners[1].Sub(quad.Corners[0])
+		edgeB := quad.Corners[2].Sub(quad.Corners[0])
+		if edgeA.Cross(edgeB).Dot(quad.Normal) <= 0 {
+			t.Fatalf("expected counter-clockwise winding facing %v, got %v", quad.Normal, quad.Corners)
+		}
+		if quad.Normal.X() < 0 && quad.Corners[0].X() != -2 {
+			t.Fatalf("expected -X face on plane x=-2, got %v", quad.Corners)
+		}
+		if quad.Normal.Z() > 0 && quad.Corners[0].Z() != 7 {
+			t.Fatalf("expected +Z face on plane z=7, got %v", quad.Corners)
+		}
+	}
+}
+
+func TestGreedyMeshKeepsPaletteGroupsSeparate(t *testing.T) {
+	xbm := NewXBrickMap()
+	xbm.SetVoxel(0, 0, 0, 1)
+	xbm.SetVoxel(1, 0, 0, 2)
+	xbm.SetVoxel(10, 0, 0, 1)
+
+	mesh := GreedyMesh(xbm)
+
+	if len(mesh.Groups) != 2 {
+		t.Fatalf("expected 2 palette groups, got %d", len(mesh.Groups))
+	}
+	if got := len(mesh.Groups[0].Quads); got != 11 {
+		t.Fatalf("expected 11 quads for palette 1, got %d", got)
+	}
+	if got := len(mesh.Groups[1].Quads); got != 5 {
+		t.Fatalf("expected 5 quads for palette 2, got %d", got)
+	}
+}
+
+func TestGreedyMeshSkipsInternalFaces(t *testing.T) {
+	xbm := NewXBrickMap()
+	Cube(xbm, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{7, 7, 7}, 1)
+	xbm.SetVoxel(3, 3, 3, 2)
+
+	mesh := GreedyMesh(xbm)
+
+	for _, group := range mesh.Groups {
+		if group.PaletteIndex == 2 {
+			t.Fatal("expected buried voxel to produce no faces")
+		}
+	}
+	if got := mesh.QuadCount(); got != 6 {
+		t.Fatalf("expected 6 quads for the outer shell, got %d", got)
+	}
+}
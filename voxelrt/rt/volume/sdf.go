@@ -0,0 +1,383 @@
+package volume
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SDF is a signed distance field evaluated in voxel space. Distance is
+// negative inside the shape; Bounds must enclose every point where it is <= 0.
+type SDF interface {
+	Distance(p mgl32.Vec3) float32
+	Bounds() (mgl32.Vec3, mgl32.Vec3)
+}
+
+type SDFSphere struct {
+	Center mgl32.Vec3
+	Radius float32
+}
+
+func (s SDFSphere) Distance(p mgl32.Vec3) float32 {
+	return p.Sub(s.Center).Len() - s.Radius
+}
+
+func (s SDFSphere) Bounds() (mgl32.Vec3, mgl32.Vec3) {
+	r := mgl32.Vec3{s.Radius, s.Radius, s.Radius}
+	return s.Center.Sub(r), s.Center.Add(r)
+}
+
+// SDFCapsule is a sphere swept along the segment A-B.
+type SDFCapsule struct {
+	A, B   mgl32.Vec3
+	Radius float32
+}
+
+func (s SDFCapsule) Distance(p mgl32.Vec3) float32 {
+	return segmentDistance(p, s.A, s.B) - s.Radius
+}
+
+func (s SDFCapsule) Bounds() (mgl32.Vec3, mgl32.Vec3) {
+	return segmentBounds(s.A, s.B, s.Radius)
+}
+
+// SDFCylinder is a capped cylinder whose axis runs from A to B.
+type SDFCylinder struct {
+	A, B   mgl32.Vec3
+	Radius float32
+}
+
+func (s SDFCylinder) Distance(p mgl32.Vec3) float32 {
+	axis := s.B.Sub(s.A)
+	length := axis.Len()
+	if length == 0 {
+		return p.Sub(s.A).Len() - s.Radius
+	}
+	dir := axis.Mul(1 / length)
+	rel := p.Sub(s.A)
+	along := rel.Dot(dir)
+	radial := rel.Sub(dir.Mul(along)).Len()
+	dr := radial - s.Radius
+	dh := absf(along-length*0.5) - length*0.5
+	outside := mgl32.Vec2{maxf(dr, 0), maxf(dh, 0)}.Len()
+	return minf(maxf(dr, dh), 0) + outside
+}
+
+func (s SDFCylinder) Bounds() (mgl32.Vec3, mgl32.Vec3) {
+	return segmentBounds(s.A, s.B, s.Radius)
+}
+
+// SDFTorus lies in the XZ plane around Center.
+type SDFTorus struct {
+	Center      mgl32.Vec3
+	MajorRadius float32
+	MinorRadius float32
+}
+
+func (s SDFTorus) Distance(p mgl32.Vec3) float32 {
+	rel := p.Sub(s.Center)
+	ring := mgl32.Vec2{rel.X(), rel.Z()}.Len() - s.MajorRadius
+	return mgl32.Vec2{ring, rel.Y()}.Len() - s.MinorRadius
+}
+
+func (s SDFTorus) Bounds() (mgl32.Vec3, mgl32.Vec3) {
+	outer := s.MajorRadius + s.MinorRadius
+	ext := mgl32.Vec3{outer, s.MinorRadius, outer}
+	return s.Center.Sub(ext), s.Center.Add(ext)
+}
+
+// SDFRoundedBox is an axis-aligned box whose edges are rounded by Radius.
+// HalfExtents include the rounding.
+type SDFRoundedBox struct {
+	Center      mgl32.Vec3
+	HalfExtents mgl32.Vec3
+	Radius      float32
+}
+
+func (s SDFRoundedBox) Distance(p mgl32.Vec3) float32 {
+	rel := p.Sub(s.Center)
+	q := mgl32.Vec3{
+		absf(rel.X()) - s.HalfExtents.X() + s.Radius,
+		absf(rel.Y()) - s.HalfExtents.Y() + s.Radius,
+		absf(rel.Z()) - s.HalfExtents.Z() + s.Radius,
+	}
+	outside := mgl32.Vec3{maxf(q.X(), 0), maxf(q.Y(), 0), maxf(q.Z(), 0)}.Len()
+	inside := minf(maxf(q.X(), maxf(q.Y(), q.Z())), 0)
+	return outside + inside - s.Radius
+}
+
+func (s SDFRoundedBox) Bounds() (mgl32.Vec3, mgl32.Vec3) {
+	return s.Center.Sub(s.HalfExtents), s.Center.Add(s.HalfExtents)
+}
+
+// SDFSweptPath sweeps a sphere along a polyline, e.g. a tunnel or trench.
+type SDFSweptPath struct {
+	Points []mgl32.Vec3
+	Radius float32
+}
+
+func (s SDFSweptPath) Distance(p mgl32.Vec3) float32 {
+	if len(s.Points) == 0 {
+		return float32(math.MaxFloat32)
+	}
+	if len(s.Points) == 1 {
+		return p.Sub(s.Points[0]).Len() - s.Radius
+	}
+	best := float32(math.MaxFloat32)
+	for i := 0; i+1 < len(s.Points); i++ {
+		best = minf(best, segmentDistance(p, s.Points[i], s.Points[i+1]))
+	}
+	return best - s.Radius
+}
+
+func (s SDFSweptPath) Bounds() (mgl32.Vec3, mgl32.Vec3) {
+	if len(s.Points) == 0 {
+		return mgl32.Vec3{}, mgl32.Vec3{}
+	}
+	minB, maxB := segmentBounds(s.Points[0], s.Points[0], s.Radius)
+	for _, point := range s.Points[1:] {
+		pMin, pMax := segmentBounds(point, point, s.Radius)
+		minB, maxB = unionBounds(minB, maxB, pMin, pMax)
+	}
+	return minB, maxB
+}
+
+// SDFNoiseDisplaced roughens Shape with deterministic value noise. Amplitude
+// is in voxels; bounds grow by the same amount.
+type SDFNoiseDisplaced struct {
+	Shape     SDF
+	Amplitude float32
+	Frequency float32
+	Seed      uint32
+}
+
+func (s SDFNoiseDisplaced) Distance(p mgl32.Vec3) float32 {
+	base := s.Shape.Distance(p)
+	if s.Amplitude == 0 {
+		return base
+	}
+	freq := s.Frequency
+	if freq <= 0 {
+		freq = 0.25
+	}
+	n := valueNoise3(p.Mul(freq), s.Seed)*2 - 1
+	return base + n*s.Amplitude
+}
+
+func (s SDFNoiseDisplaced) Bounds() (mgl32.Vec3, mgl32.Vec3) {
+	minB, maxB := s.Shape.Bounds()
+	pad := mgl32.Vec3{absf(s.Amplitude), absf(s.Amplitude), absf(s.Amplitude)}
+	return minB.Sub(pad), maxB.Add(pad)
+}
+
+// SDFTransformed places Shape with a rigid or uniformly scaled transform.
+// NewSDFTransformed computes the inverse once; a struct literal works too
+// but inverts LocalToWorld on every Distance call.
+type SDFTransformed struct {
+	Shape        SDF
+	LocalToWorld mgl32.Mat4
+	worldToLocal mgl32.Mat4
+	scale        float32
+}
+
+func NewSDFTransformed(shape SDF, localToWorld mgl32.Mat4) SDFTransformed {
+	s := SDFTransformed{Shape: shape, LocalToWorld: localToWorld}
+	s.worldToLocal, s.scale = s.inverse()
+	return s
+}
+
+// inverse returns the world-to-local matrix and the distance scale. A zero
+// scale means the struct was not built by NewSDFTransformed.
+func (s SDFTransformed) inverse() (mgl32.Mat4, float32) {
+	if s.scale != 0 {
+		return s.worldToLocal, s.scale
+	}
+	scale := s.LocalToWorld.Col(0).Vec3().Len()
+	if scale == 0 {
+		scale = 1
+	}
+	return s.LocalToWorld.Inv(), scale
+}
+
+func (s SDFTransformed) Distance(p mgl32.Vec3) float32 {
+	worldToLocal, scale := s.inverse()
+	local := worldToLocal.Mul4x1(p.Vec4(1)).Vec3()
+	return s.Shape.Distance(local) * scale
+}
+
+func (s SDFTransformed) Bounds() (mgl32.Vec3, mgl32.Vec3) {
+	return transformBounds(s.Shape, s.LocalToWorld)
+}
+
+type SDFUnion struct {
+	A, B SDF
+}
+
+func (s SDFUnion) Distance(p mgl32.Vec3) float32 {
+	return minf(s.A.Distance(p), s.B.Distance(p))
+}
+
+func (s SDFUnion) Bounds() (mgl32.Vec3, mgl32.Vec3) {
+	aMin, aMax := s.A.Bounds()
+	bMin, bMax := s.B.Bounds()
+	return unionBounds(aMin, aMax, bMin, bMax)
+}
+
+// SDFSubtract removes B from A.
+type SDFSubtract struct {
+	A, B SDF
+}
+
+func (s SDFSubtract) Distance(p mgl32.Vec3) float32 {
+	return maxf(s.A.Distance(p), -s.B.Distance(p))
+}
+
+func (s SDFSubtract) Bounds() (mgl32.Vec3, mgl32.Vec3) {
+	return s.A.Bounds()
+}
+
+type SDFIntersect struct {
+	A, B SDF
+}
+
+func (s SDFIntersect) Distance(p mgl32.Vec3) float32 {
+	return maxf(s.A.Distance(p), s.B.Distance(p))
+}
+
+func (s SDFIntersect) Bounds() (mgl32.Vec3, mgl32.Vec3) {
+	aMin, aMax := s.A.Bounds()
+	bMin, bMax := s.B.Bounds()
+	minB := mgl32.Vec3{maxf(aMin.X(), bMin.X()), maxf(aMin.Y(), bMin.Y()), maxf(aMin.Z(), bMin.Z())}
+	maxB := mgl32.Vec3{minf(aMax.X(), bMax.X()), minf(aMax.Y(), bMax.Y()), minf(aMax.Z(), bMax.Z())}
+	return minB, maxB
+}
+
+// SDFSmoothUnion blends A and B with a polynomial smooth minimum of width K.
+type SDFSmoothUnion struct {
+	A, B SDF
+	K    float32
+}
+
+func (s SDFSmoothUnion) Distance(p mgl32.Vec3) float32 {
+	return smoothMin(s.A.Distance(p), s.B.Distance(p), s.K)
+}
+
+func (s SDFSmoothUnion) Bounds() (mgl32.Vec3, mgl32.Vec3) {
+	aMin, aMax := s.A.Bounds()
+	bMin, bMax := s.B.Bounds()
+	minB, maxB := unionBounds(aMin, aMax, bMin, bMax)
+	// The blend can bulge by at most K/4 beyond either input.
+	pad := mgl32.Vec3{s.K * 0.25, s.K * 0.25, s.K * 0.25}
+	return minB.Sub(pad), maxB.Add(pad)
+}
+
+// SDFSmoothSubtract carves B out of A with a blended rim of width K.
+type SDFSmoothSubtract struct {
+	A, B SDF
+	K    float32
+}
+
+func (s SDFSmoothSubtract) Distance(p mgl32.Vec3) float32 {
+	return -smoothMin(-s.A.Distance(p), s.B.Distance(p), s.K)
+}
+
+func (s SDFSmoothSubtract) Bounds() (mgl32.Vec3, mgl32.Vec3) {
+	return s.A.Bounds()
+}
+
+func smoothMin(a, b, k float32) float32 {
+	if k <= 0 {
+		return minf(a, b)
+	}
+	h := maxf(k-absf(a-b), 0) / k
+	return minf(a, b) - h*h*k*0.25
+}
+
+func segmentDistance(p, a, b mgl32.Vec3) float32 {
+	ab := b.Sub(a)
+	denom := ab.Dot(ab)
+	if denom == 0 {
+		return p.Sub(a).Len()
+	}
+	t := p.Sub(a).Dot(ab) / denom
+	t = minf(maxf(t, 0), 1)
+	return p.Sub(a.Add(ab.Mul(t))).Len()
+}
+
+func segmentBounds(a, b mgl32.Vec3, radius float32) (mgl32.Vec3, mgl32.Vec3) {
+	minB := mgl32.Vec3{minf(a.X(), b.X()) - radius, minf(a.Y(), b.Y()) - radius, minf(a.Z(), b.Z()) - radius}
+	maxB := mgl32.Vec3{maxf(a.X(), b.X()) + radius, maxf(a.Y(), b.Y()) + radius, maxf(a.Z(), b.Z()) + radius}
+	return minB, maxB
+}
+
+func unionBounds(aMin, aMax, bMin, bMax mgl32.Vec3) (mgl32.Vec3, mgl32.Vec3) {
+	minB := mgl32.Vec3{minf(aMin.X(), bMin.X()), minf(aMin.Y(), bMin.Y()), minf(aMin.Z(), bMin.Z())}
+	maxB := mgl32.Vec3{maxf(aMax.X(), bMax.X()), maxf(aMax.Y(), bMax.Y()), maxf(aMax.Z(), bMax.Z())}
+	return minB, maxB
+}
+
+func transformBounds(shape SDF, m mgl32.Mat4) (mgl32.Vec3, mgl32.Vec3) {
+	localMin, localMax := shape.Bounds()
+	minB := mgl32.Vec3{float32(math.MaxFloat32), float32(math.MaxFloat32), float32(math.MaxFloat32)}
+	maxB := minB.Mul(-1)
+	for i := 0; i < 8; i++ {
+		corner := localMin
+		if i&1 != 0 {
+			corner[0] = localMax[0]
+		}
+		if i&2 != 0 {
+			corner[1] = localMax[1]
+		}
+		if i&4 != 0 {
+			corner[2] = localMax[2]
+		}
+		world := m.Mul4x1(corner.Vec4(1)).Vec3()
+		minB, maxB = unionBounds(minB, maxB, world, world)
+	}
+	return minB, maxB
+}
+
+func minf(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxf(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func latticeHash(x, y, z int32, seed uint32) float32 {
+	h := uint32(x)*0x8da6b343 ^ uint32(y)*0xd8163841 ^ uint32(z)*0xcb1ab31f ^ seed*0x165667b1
+	h ^= h >> 13
+	h *= 0x5bd1e995
+	h ^= h >> 15
+	return float32(h&0xFFFFFF) / float32(0xFFFFFF)
+}
+
+// valueNoise3 returns trilinearly interpolated lattice noise in [0,1].
+func valueNoise3(p mgl32.Vec3, seed uint32) float32 {
+	fx, fy, fz := math.Floor(float64(p.X())), math.Floor(float64(p.Y())), math.Floor(float64(p.Z()))
+	x0, y0, z0 := int32(fx), int32(fy), int32(fz)
+	tx, ty, tz := p.X()-float32(fx), p.Y()-float32(fy), p.Z()-float32(fz)
+	tx, ty, tz = tx*tx*(3-2*tx), ty*ty*(3-2*ty), tz*tz*(3-2*tz)
+
+	lerp := func(a, b, t float32) float32 { return a + (b-a)*t }
+	c000 := latticeHash(x0, y0, z0, seed)
+	c100 := latticeHash(x0+1, y0, z0, seed)
+	c010 := latticeHash(x0, y0+1, z0, seed)
+	c110 := latticeHash(x0+1, y0+1, z0, seed)
+	c001 := latticeHash(x0, y0, z0+1, seed)
+	c101 := latticeHash(x0+1, y0, z0+1, seed)
+	c011 := latticeHash(x0, y0+1, z0+1, seed)
+	c111 := latticeHash(x0+1, y0+1, z0+1, seed)
+	x00 := lerp(c000, c100, tx)
+	x10 := lerp(c010, c110, tx)
+	x01 := lerp(c001, c101, tx)
+	x11 := lerp(c011, c111, tx)
+	return lerp(lerp(x00, x10, ty), lerp(x01, x11, ty), tz)
+}
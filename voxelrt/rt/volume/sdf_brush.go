@@ -0,0 +1,155 @@
+package volume
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type BrushOperation int
+
+const (
+	// BrushAdd fills every voxel inside the shape with PaletteIdx.
+	BrushAdd BrushOperation = iota
+	// BrushSubtract clears every voxel inside the shape.
+	BrushSubtract
+	// BrushIntersect clears occupied voxels outside the shape.
+	BrushIntersect
+	// BrushPaint recolors occupied voxels inside the shape without changing
+	// occupancy.
+	BrushPaint
+)
+
+// BrushEdit describes one SDF brush stroke against an XBrickMap. Shape is
+// evaluated at voxel centers in the map's voxel space.
+type BrushEdit struct {
+	Shape      SDF
+	Operation  BrushOperation
+	PaletteIdx uint8
+}
+
+// BrushEditResult reports the inclusive voxel region that was touched and
+// how many voxels actually changed.
+type BrushEditResult struct {
+	Min     [3]int
+	Max     [3]int
+	Changed int
+}
+
+// ApplyBrush applies edit to xbm. Only voxels inside the edit's region are
+// visited and only changed voxels are written, so dirty tracking stays
+// bounded to the stroke.
+func ApplyBrush(xbm *XBrickMap, edit BrushEdit) BrushEditResult {
+	result := BrushEditResult{}
+	if xbm == nil || edit.Shape == nil {
+		return result
+	}
+	minI, maxI, ok := brushVoxelRegion(xbm, edit)
+	if !ok {
+		return result
+	}
+	result.Min, result.Max = minI, maxI
+
+	if edit.Operation == BrushIntersect {
+		result.Changed = applyBrushIntersect(xbm, edit.Shape, minI, maxI)
+		return result
+	}
+
+	for x := minI[0]; x <= maxI[0]; x++ {
+		for y := minI[1]; y <= maxI[1]; y++ {
+			for z := minI[2]; z <= maxI[2]; z++ {
+				if edit.Shape.Distance(voxelCenter(x, y, z)) > 0 {
+					continue
+				}
+				occupied, current := xbm.GetVoxel(x, y, z)
+				switch edit.Operation {
+				case BrushAdd:
+					if current == edit.PaletteIdx {
+						continue
+					}
+					xbm.SetVoxel(x, y, z, edit.PaletteIdx)
+				case BrushSubtract:
+					if !occupied {
+						continue
+					}
+					xbm.SetVoxel(x, y, z, 0)
+				case BrushPaint:
+					if !occupied || edit.PaletteIdx == 0 || current == edit.PaletteIdx {
+						continue
+					}
+					xbm.SetVoxel(x, y, z, edit.PaletteIdx)
+				default:
+					continue
+				}
+				result.Changed++
+			}
+		}
+	}
+	return result
+}
+
+// ApplyBrushes applies edits in order and returns the region covering every
+// edit that changed at least one voxel.
+func ApplyBrushes(xbm *XBrickMap, edits ...BrushEdit) BrushEditResult {
+	merged := BrushEditResult{}
+	for _, edit := range edits {
+		result := ApplyBrush(xbm, edit)
+		if result.Changed == 0 {
+			continue
+		}
+		if merged.Changed == 0 {
+			merged.Min, merged.Max = result.Min, result.Max
+		} else {
+			for axis := 0; axis < 3; axis++ {
+				merged.Min[axis] = min(merged.Min[axis], result.Min[axis])
+				merged.Max[axis] = max(merged.Max[axis], result.Max[axis])
+			}
+		}
+		merged.Changed += result.Changed
+	}
+	return merged
+}
+
+func voxelCenter(x, y, z int) mgl32.Vec3 {
+	return mgl32.Vec3{float32(x) + 0.5, float32(y) + 0.5, float32(z) + 0.5}
+}
+
+func brushVoxelRegion(xbm *XBrickMap, edit BrushEdit) ([3]int, [3]int, bool) {
+	var minB, maxB mgl32.Vec3
+	if edit.Operation == BrushIntersect {
+		// Intersect touches everything outside the shape, so the region is the
+		// existing content rather than the brush.
+		if xbm.GetVoxelCount() == 0 {
+			return [3]int{}, [3]int{}, false
+		}
+		minB, maxB = xbm.ComputeAABB()
+	} else {
+		minB, maxB = edit.Shape.Bounds()
+	}
+	var minI, maxI [3]int
+	for axis := 0; axis < 3; axis++ {
+		minI[axis] = int(math.Floor(float64(minB[axis])))
+		maxI[axis] = int(math.Ceil(float64(maxB[axis]))) - 1
+		if maxI[axis] < minI[axis] {
+			return [3]int{}, [3]int{}, false
+		}
+	}
+	return minI, maxI, true
+}
+
+func applyBrushIntersect(xbm *XBrickMap, shape SDF, minI, maxI [3]int) int {
+	changed := 0
+	for x := minI[0]; x <= maxI[0]; x++ {
+		for y := minI[1]; y <= maxI[1]; y++ {
+			for z := minI[2]; z <= maxI[2]; z++ {
+				occupied, _ := xbm.GetVoxel(x, y, z)
+				if !occupied || shape.Distance(voxelCenter(x, y, z)) <= 0 {
+					continue
+				}
+				xbm.SetVoxel(x, y, z, 0)
+				changed++
+			}
+		}
+	}
+	return changed
+}
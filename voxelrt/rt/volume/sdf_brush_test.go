@@ -0,0 +1,160 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestApplyBrushSphereMatchesSpherePrimitive(t *testing.T) {
+	expected := NewXBrickMap()
+	Sphere(expected, mgl32.Vec3{10, 10, 10}, 4, 3)
+
+	got := NewXBrickMap()
+	result := ApplyBrush(got, BrushEdit{Shape: SDFSphere{Center: mgl32.Vec3{10, 10, 10}, Radius: 4}, PaletteIdx: 3})
+
+	if got.GetVoxelCount() != expected.GetVoxelCount() {
+		t.Fatalf("expected %d voxels like volume.Sphere, got %d", expected.GetVoxelCount(), got.GetVoxelCount())
+	}
+	if result.Changed != got.GetVoxelCount() {
+		t.Fatalf("expected changed count %d, got %d", got.GetVoxelCount(), result.Changed)
+	}
+	if result.Min != [3]int{6, 6, 6} || result.Max != [3]int{13, 13, 13} {
+		t.Fatalf("unexpected brush region %v-%v", result.Min, result.Max)
+	}
+}
+
+func TestApplyBrushSubtractKeepsDirtyRegionBounded(t *testing.T) {
+	xbm := NewXBrickMap()
+	Cube(xbm, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{63, 15, 15}, 2)
+	xbm.ClearDirty()
+
+	result := ApplyBrush(xbm, BrushEdit{
+		Shape:     SDFCapsule{A: mgl32.Vec3{2, 8, 8}, B: mgl32.Vec3{6, 8, 8}, Radius: 2},
+		Operation: BrushSubtract,
+	})
+	if result.Changed == 0 {
+		t.Fatal("expected capsule to carve voxels")
+	}
+	if ok, _ := xbm.GetVoxel(4, 8, 8); ok {
+		t.Fatal("expected capsule interior to be cleared")
+	}
+	// Normal halos may dirty the neighbouring brick, but nothing beyond it.
+	for key := range xbm.DirtyBricks {
+		if globalBrickX := key[0]*SectorBricks + key[3]; globalBrickX < -1 || globalBrickX > 1 {
+			t.Fatalf("expected dirty bricks near the stroke only, got %v", key)
+		}
+	}
+}
+
+func TestApplyBrushPaintKeepsOccupancy(t *testing.T) {
+	xbm := NewXBrickMap()
+	Cube(xbm, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{7, 7, 7}, 1)
+	before := xbm.GetVoxelCount()
+
+	result := ApplyBrush(xbm, BrushEdit{
+		Shape:      SDFRoundedBox{Center: mgl32.Vec3{8, 8, 8}, HalfExtents: mgl32.Vec3{6, 6, 6}, Radius: 1},
+		Operation:  BrushPaint,
+		PaletteIdx: 5,
+	})
+	if xbm.GetVoxelCount() != before {
+		t.Fatalf("expected paint to keep %d voxels, got %d", before, xbm.GetVoxelCount())
+	}
+	if result.Changed == 0 {
+		t.Fatal("expected paint to recolor voxels")
+	}
+	if _, value := xbm.GetVoxel(7, 7, 7); value != 5 {
+		t.Fatalf("expected painted voxel value 5, got %d", value)
+	}
+	if _, value := xbm.GetVoxel(0, 0, 0); value != 1 {
+		t.Fatalf("expected voxel outside paint brush to keep value 1, got %d", value)
+	}
+	if ok, _ := xbm.GetVoxel(12, 12, 12); ok {
+		t.Fatal("expected paint not to add voxels")
+	}
+}
+
+func TestApplyBrushIntersectClearsOutsideShape(t *testing.T) {
+	xbm := NewXBrickMap()
+	Cube(xbm, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{15, 15, 15}, 1)
+
+	ApplyBrush(xbm, BrushEdit{Shape: SDFSphere{Center: mgl32.Vec3{8, 8, 8}, Radius: 5}, Operation: BrushIntersect})
+
+	if ok, _ := xbm.GetVoxel(0, 0, 0); ok {
+		t.Fatal("expected corner outside the sphere to be cleared")
+	}
+	if ok, _ := xbm.GetVoxel(8, 8, 8); !ok {
+		t.Fatal("expected sphere center to survive the intersect")
+	}
+}
+
+func TestSDFCombinators(t *testing.T) {
+	a := SDFSphere{Center: mgl32.Vec3{0, 0, 0}, Radius: 2}
+	b := SDFSphere{Center: mgl32.Vec3{3, 0, 0}, Radius: 2}
+	mid := mgl32.Vec3{1.5, 0, 0}
+
+	if (SDFUnion{A: a, B: b}).Distance(mid) > 0 {
+		t.Fatal("expected union to contain the overlap")
+	}
+	if (SDFSubtract{A: a, B: b}).Distance(mid) <= 0 {
+		t.Fatal("expected subtract to remove the overlap")
+	}
+	if (SDFIntersect{A: a, B: b}).Distance(mgl32.Vec3{-1.5, 0, 0}) <= 0 {
+		t.Fatal("expected intersect to exclude points only inside A")
+	}
+	hard := (SDFUnion{A: a, B: b}).Distance(mgl32.Vec3{1.5, 2, 0})
+	smooth := (SDFSmoothUnion{A: a, B: b, K: 2}).Distance(mgl32.Vec3{1.5, 2, 0})
+	if smooth >= hard {
+		t.Fatalf("expected smooth union to bulge past the hard union, got %f >= %f", smooth, hard)
+	}
+}
+
+func TestSDFPrimitivesContainTheirCenters(t *testing.T) {
+	shapes := map[string]SDF{
+		"cylinder": SDFCylinder{A: mgl32.Vec3{0, -3, 0}, B: mgl32.Vec3{0, 3, 0}, Radius: 2},
+		"torus":    SDFTorus{Center: mgl32.Vec3{}, MajorRadius: 4, MinorRadius: 1},
+		"swept":    SDFSweptPath{Points: []mgl32.Vec3{{-4, 0, 0}, {0, 0, 0}, {0, 4, 0}}, Radius: 1},
+		"noise":    SDFNoiseDisplaced{Shape: SDFSphere{Radius: 3}, Amplitude: 0.5, Seed: 7},
+		"transformed": NewSDFTransformed(
+			SDFRoundedBox{HalfExtents: mgl32.Vec3{2, 1, 1}, Radius: 0.5},
+			mgl32.Translate3D(10, 0, 0).Mul4(mgl32.HomogRotate3DY(mgl32.DegToRad(90))),
+		),
+	}
+	probes := map[string]mgl32.Vec3{
+		"cylinder":    {0, 0, 0},
+		"torus":       {4, 0, 0},
+		"swept":       {0, 2, 0},
+		"noise":       {0, 0, 0},
+		"transformed": {10, 0, 1.5},
+	}
+	for name, shape := range shapes {
+		probe := probes[name]
+		if d := shape.Distance(probe); d > 0 {
+			t.Fatalf("%s: expected %v to be inside, distance %f", name, probe, d)
+		}
+		minB, maxB := shape.Bounds()
+		for axis := 0; axis < 3; axis++ {
+			if probe[axis] < minB[axis] || probe[axis] > maxB[axis] {
+				t.Fatalf("%s: probe %v outside bounds %v-%v", name, probe, minB, maxB)
+			}
+		}
+	}
+	if d := (SDFTorus{MajorRadius: 4, MinorRadius: 1}).Distance(mgl32.Vec3{}); d <= 0 {
+		t.Fatal("expected torus hole to be outside the shape")
+	}
+}
+
+func TestSDFTransformedLiteralMatchesConstructor(t *testing.T) {
+	shape := SDFSphere{Radius: 2}
+	localToWorld := mgl32.Translate3D(10, 0, 0).Mul4(mgl32.Scale3D(2, 2, 2))
+	built := NewSDFTransformed(shape, localToWorld)
+	literal := SDFTransformed{Shape: shape, LocalToWorld: localToWorld}
+	for _, p := range []mgl32.Vec3{{10, 0, 0}, {13, 0, 0}, {20, 0, 0}, {0, 0, 0}} {
+		if got, want := literal.Distance(p), built.Distance(p); absf(got-want) > 1e-4 {
+			t.Fatalf("literal distance at %v = %f, want %f", p, got, want)
+		}
+	}
+	if d := literal.Distance(mgl32.Vec3{0, 0, 0}); d <= 0 {
+		t.Fatalf("expected a point outside the scaled sphere to stay outside, got %f", d)
+	}
+}
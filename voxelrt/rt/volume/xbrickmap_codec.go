@@ -0,0 +1,401 @@
+package volume
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math/bits"
+	"sort"
+)
+
+// XBrickMapBinaryMagic prefixes every binary XBrickMap stream.
+var XBrickMapBinaryMagic = []byte{'G', 'K', 'X', 'B', 'M', 'A', 'P', '\n'}
+
+const (
+	XBrickMapBinaryVersion = 1
+
+	// XBrickMapBinaryFlagDeflate marks a body compressed with DEFLATE on top
+	// of the per-brick RLE encoding.
+	XBrickMapBinaryFlagDeflate = 1
+
+	xbrickMapHeaderSize = 8 + 2 + 2 + 4 + 4
+
+	xbrickMapBrickUniform = 0
+	xbrickMapBrickRLE     = 1
+	xbrickMapBrickRaw     = 2
+
+	brickVoxelCount = BrickSize * BrickSize * BrickSize
+)
+
+var errXBrickMapChecksum = errors.New("xbrickmap binary checksum mismatch")
+
+type XBrickMapWriteOptions struct {
+	// DisableCompression writes the RLE body without the DEFLATE layer.
+	DisableCompression bool
+}
+
+type XBrickMapBinaryHeader struct {
+	Version     uint16
+	Flags       uint16
+	SectorCount uint32
+}
+
+// IsXBrickMapBinary reports whether data starts with the binary XBrickMap magic.
+func IsXBrickMapBinary(data []byte) bool {
+	return bytes.HasPrefix(data, XBrickMapBinaryMagic)
+}
+
+// WriteXBrickMap serializes x as a versioned binary stream. Sectors are
+// written in coordinate order so identical maps produce identical bytes.
+func WriteXBrickMap(w io.Writer, x *XBrickMap, opts XBrickMapWriteOptions) error {
+	if x == nil {
+		return fmt.Errorf("xbrickmap is nil")
+	}
+	keys := make([][3]int, 0, len(x.Sectors))
+	for key, sector := range x.Sectors {
+		if sector == nil || sector.IsEmpty() || !sectorHasVoxels(sector) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		if keys[i][1] != keys[j][1] {
+			return keys[i][1] < keys[j][1]
+		}
+		return keys[i][2] < keys[j][2]
+	})
+
+	header := XBrickMapBinaryHeader{
+		Version:     XBrickMapBinaryVersion,
+		SectorCount: uint32(len(keys)),
+	}
+	if !opts.DisableCompression {
+		header.Flags |= XBrickMapBinaryFlagDeflate
+	}
+	var headerBuf [xbrickMapHeaderSize]byte
+	copy(headerBuf[:8], XBrickMapBinaryMagic)
+	binary.LittleEndian.PutUint16(headerBuf[8:10], header.Version)
+	binary.LittleEndian.PutUint16(headerBuf[10:12], header.Flags)
+	binary.LittleEndian.PutUint32(headerBuf[12:16], header.SectorCount)
+	binary.LittleEndian.PutUint32(headerBuf[16:20], crc32.ChecksumIEEE(headerBuf[:16]))
+	if _, err := w.Write(headerBuf[:]); err != nil {
+		return err
+	}
+
+	body := w
+	var deflater *flate.Writer
+	if header.Flags&XBrickMapBinaryFlagDeflate != 0 {
+		var err error
+		deflater, err = flate.NewWriter(w, flate.DefaultCompression)
+		if err != nil {
+			return err
+		}
+		body = deflater
+	}
+
+	var record bytes.Buffer
+	for _, key := range keys {
+		record.Reset()
+		encodeXBrickMapSector(&record, key, x.Sectors[key])
+		var sum [4]byte
+		binary.LittleEndian.PutUint32(sum[:], crc32.ChecksumIEEE(record.Bytes()))
+		record.Write(sum[:])
+		if _, err := body.Write(record.Bytes()); err != nil {
+			return err
+		}
+	}
+	if deflater != nil {
+		return deflater.Close()
+	}
+	return nil
+}
+
+// EncodeXBrickMap returns the binary form of x.
+func EncodeXBrickMap(x *XBrickMap, opts XBrickMapWriteOptions) ([]byte, error) {
+	var out bytes.Buffer
+	if err := WriteXBrickMap(&out, x, opts); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// ReadXBrickMap decodes a complete binary stream into a new XBrickMap with
+// clean dirty tracking.
+func ReadXBrickMap(r io.Reader) (*XBrickMap, error) {
+	reader, err := NewXBrickMapReader(r)
+	if err != nil {
+		return nil, err
+	}
+	xbm := NewXBrickMap()
+	for {
+		sector, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := xbm.Sectors[sector.Coords]; exists {
+			return nil, fmt.Errorf("xbrickmap binary has duplicate sector %v", sector.Coords)
+		}
+		xbm.Sectors[sector.Coords] = sector
+	}
+	xbm.ClearDirty()
+	return xbm, nil
+}
+
+// DecodeXBrickMap decodes the binary form produced by EncodeXBrickMap.
+func DecodeXBrickMap(data []byte) (*XBrickMap, error) {
+	return ReadXBrickMap(bytes.NewReader(data))
+}
+
+// XBrickMapReader streams sectors out of a binary XBrickMap without
+// materializing the whole map.
+type XBrickMapReader struct {
+	Header XBrickMapBinaryHeader
+
+	body      *bufio.Reader
+	remaining uint32
+	record    bytes.Buffer
+}
+
+func NewXBrickMapReader(r io.Reader) (*XBrickMapReader, error) {
+	var headerBuf [xbrickMapHeaderSize]byte
+	if _, err := io.ReadFull(r, headerBuf[:]); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("xbrickmap binary header is truncated")
+		}
+		return nil, err
+	}
+	if !IsXBrickMapBinary(headerBuf[:]) {
+		return nil, fmt.Errorf("xbrickmap binary magic mismatch")
+	}
+	if crc32.ChecksumIEEE(headerBuf[:16]) != binary.LittleEndian.Uint32(headerBuf[16:20]) {
+		return nil, errXBrickMapChecksum
+	}
+	header := XBrickMapBinaryHeader{
+		Version:     binary.LittleEndian.Uint16(headerBuf[8:10]),
+		Flags:       binary.LittleEndian.Uint16(headerBuf[10:12]),
+		SectorCount: binary.LittleEndian.Uint32(headerBuf[12:16]),
+	}
+	if header.Version != XBrickMapBinaryVersion {
+		return nil, fmt.Errorf("unsupported xbrickmap binary version %d", header.Version)
+	}
+	if header.Flags&^XBrickMapBinaryFlagDeflate != 0 {
+		return nil, fmt.Errorf("unsupported xbrickmap binary flags %#x", header.Flags)
+	}
+	body := r
+	if header.Flags&XBrickMapBinaryFlagDeflate != 0 {
+		body = flate.NewReader(r)
+	}
+	return &XBrickMapReader{
+		Header:    header,
+		body:      bufio.NewReader(body),
+		remaining: header.SectorCount,
+	}, nil
+}
+
+// Next returns the next decoded sector, or io.EOF once every sector declared
+// in the header has been read.
+func (r *XBrickMapReader) Next() (*Sector, error) {
+	if r.remaining == 0 {
+		return nil, io.EOF
+	}
+	r.record.Reset()
+	sector, err := decodeXBrickMapSector(io.TeeReader(r.body, &r.record))
+	if err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("xbrickmap binary sector is truncated")
+		}
+		return nil, err
+	}
+	var sum [4]byte
+	if _, err := io.ReadFull(r.body, sum[:]); err != nil {
+		return nil, fmt.Errorf("xbrickmap binary sector checksum is truncated")
+	}
+	if crc32.ChecksumIEEE(r.record.Bytes()) != binary.LittleEndian.Uint32(sum[:]) {
+		return nil, errXBrickMapChecksum
+	}
+	r.remaining--
+	return sector, nil
+}
+
+func sectorHasVoxels(sector *Sector) bool {
+	for _, brick := range sector.PackedBricks {
+		if brick != nil && !brick.IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+func encodeXBrickMapSector(out *bytes.Buffer, key [3]int, sector *Sector) {
+	var mask uint64
+	bricks := make([]*Brick, 0, len(sector.PackedBricks))
+	for i := 0; i < 64; i++ {
+		if sector.BrickMask64&(1<<i) == 0 {
+			continue
+		}
+		brick := sector.PackedBricks[sector.GetPackedIndex(i)]
+		if brick == nil || brick.IsEmpty() {
+			continue
+		}
+		mask |= 1 << i
+		bricks = append(bricks, brick)
+	}
+
+	var buf [8]byte
+	for axis := 0; axis < 3; axis++ {
+		binary.LittleEndian.PutUint32(buf[:4], uint32(int32(key[axis])))
+		out.Write(buf[:4])
+	}
+	binary.LittleEndian.PutUint64(buf[:], mask)
+	out.Write(buf[:])
+	for _, brick := range bricks {
+		encodeXBrickMapBrick(out, brick)
+	}
+}
+
+func encodeXBrickMapBrick(out *bytes.Buffer, brick *Brick) {
+	var linear [brickVoxelCount]uint8
+	for z := 0; z < BrickSize; z++ {
+		for y := 0; y < BrickSize; y++ {
+			for x := 0; x < BrickSize; x++ {
+				linear[denseOccupancyLinearIndex(x, y, z)] = brick.Payload[x][y][z]
+			}
+		}
+	}
+
+	type run struct {
+		value  uint8
+		length uint16
+	}
+	runs := make([]run, 0, 8)
+	for _, value := range linear {
+		if len(runs) > 0 && runs[len(runs)-1].value == value {
+			runs[len(runs)-1].length++
+			continue
+		}
+		runs = append(runs, run{value: value, length: 1})
+	}
+
+	if len(runs) == 1 {
+		out.WriteByte(xbrickMapBrickUniform)
+		out.WriteByte(runs[0].value)
+		return
+	}
+	// Each run costs three bytes; past that point raw storage is smaller.
+	if 2+len(runs)*3 >= brickVoxelCount {
+		out.WriteByte(xbrickMapBrickRaw)
+		out.Write(linear[:])
+		return
+	}
+	var buf [2]byte
+	out.WriteByte(xbrickMapBrickRLE)
+	binary.LittleEndian.PutUint16(buf[:], uint16(len(runs)))
+	out.Write(buf[:])
+	for _, r := range runs {
+		out.WriteByte(r.value)
+		binary.LittleEndian.PutUint16(buf[:], r.length)
+		out.Write(buf[:])
+	}
+}
+
+func decodeXBrickMapSector(r io.Reader) (*Sector, error) {
+	var buf [20]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return nil, err
+	}
+	sector := NewSector(
+		int(int32(binary.LittleEndian.Uint32(buf[0:4]))),
+		int(int32(binary.LittleEndian.Uint32(buf[4:8]))),
+		int(int32(binary.LittleEndian.Uint32(buf[8:12]))),
+	)
+	sector.BrickMask64 = binary.LittleEndian.Uint64(buf[12:20])
+	if sector.BrickMask64 == 0 {
+		return nil, fmt.Errorf("xbrickmap binary sector %v has no bricks", sector.Coords)
+	}
+	sector.PackedBricks = make([]*Brick, 0, bits.OnesCount64(sector.BrickMask64))
+	for i := 0; i < bits.OnesCount64(sector.BrickMask64); i++ {
+		brick, err := decodeXBrickMapBrick(r)
+		if err != nil {
+			return nil, err
+		}
+		if brick.IsEmpty() {
+			return nil, fmt.Errorf("xbrickmap binary sector %v stores an empty brick", sector.Coords)
+		}
+		sector.PackedBricks = append(sector.PackedBricks, brick)
+	}
+	return sector, nil
+}
+
+func decodeXBrickMapBrick(r io.Reader) (*Brick, error) {
+	var kind [1]byte
+	if _, err := io.ReadFull(r, kind[:]); err != nil {
+		return nil, err
+	}
+	var linear [brickVoxelCount]uint8
+	switch kind[0] {
+	case xbrickMapBrickUniform:
+		var value [1]byte
+		if _, err := io.ReadFull(r, value[:]); err != nil {
+			return nil, err
+		}
+		for i := range linear {
+			linear[i] = value[0]
+		}
+	case xbrickMapBrickRLE:
+		var buf [3]byte
+		if _, err := io.ReadFull(r, buf[:2]); err != nil {
+			return nil, err
+		}
+		runCount := int(binary.LittleEndian.Uint16(buf[:2]))
+		cursor := 0
+		for i := 0; i < runCount; i++ {
+			if _, err := io.ReadFull(r, buf[:]); err != nil {
+				return nil, err
+			}
+			length := int(binary.LittleEndian.Uint16(buf[1:3]))
+			if length == 0 || cursor+length > brickVoxelCount {
+				return nil, fmt.Errorf("xbrickmap binary brick run %d is invalid", i)
+			}
+			for n := 0; n < length; n++ {
+				linear[cursor+n] = buf[0]
+			}
+			cursor += length
+		}
+		if cursor != brickVoxelCount {
+			return nil, fmt.Errorf("xbrickmap binary brick runs cover %d voxels, expected %d", cursor, brickVoxelCount)
+		}
+	case xbrickMapBrickRaw:
+		if _, err := io.ReadFull(r, linear[:]); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported xbrickmap binary brick kind %d", kind[0])
+	}
+
+	brick := NewBrick()
+	for z := 0; z < BrickSize; z++ {
+		for y := 0; y < BrickSize; y++ {
+			for x := 0; x < BrickSize; x++ {
+				value := linear[denseOccupancyLinearIndex(x, y, z)]
+				if value == 0 {
+					continue
+				}
+				brick.Payload[x][y][z] = value
+				brick.OccupancyMask64 |= 1 << ((x / MicroSize) + (y/MicroSize)*4 + (z/MicroSize)*16)
+			}
+		}
+	}
+	brick.RefreshMaterialFlags()
+	return brick, nil
+}
@@ -0,0 +1,139 @@
+package volume
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func buildCodecTestMap() *XBrickMap {
+	xbm := NewXBrickMap()
+	Cube(xbm, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{16, 8, 8}, 3)
+	Sphere(xbm, mgl32.Vec3{-20, 40, 5}, 6, 9)
+	for i := 0; i < 40; i++ {
+		xbm.SetVoxel(100+i, -7, i%5, uint8(1+i%200))
+	}
+	return xbm
+}
+
+func assertXBrickMapsEqual(t *testing.T, want, got *XBrickMap) {
+	t.Helper()
+	if got.GetVoxelCount() != want.GetVoxelCount() {
+		t.Fatalf("voxel count mismatch: want %d, got %d", want.GetVoxelCount(), got.GetVoxelCount())
+	}
+	for key, sector := range want.Sectors {
+		for i := 0; i < 64; i++ {
+			if sector.BrickMask64&(1<<i) == 0 {
+				continue
+			}
+			bx, by, bz := i%4, (i/4)%4, i/16
+			brick := sector.GetBrick(bx, by, bz)
+			for z := 0; z < BrickSize; z++ {
+				for y := 0; y < BrickSize; y++ {
+					for x := 0; x < BrickSize; x++ {
+						gx := key[0]*SectorSize + bx*BrickSize + x
+						gy := key[1]*SectorSize + by*BrickSize + y
+						gz := key[2]*SectorSize + bz*BrickSize + z
+						if _, value := got.GetVoxel(gx, gy, gz); value != brick.Payload[x][y][z] {
+							t.Fatalf("voxel (%d,%d,%d) mismatch: want %d, got %d", gx, gy, gz, brick.Payload[x][y][z], value)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestXBrickMapBinaryRoundTrip(t *testing.T) {
+	for _, opts := range []XBrickMapWriteOptions{{}, {DisableCompression: true}} {
+		source := buildCodecTestMap()
+		data, err := EncodeXBrickMap(source, opts)
+		if err != nil {
+			t.Fatalf("EncodeXBrickMap failed: %v", err)
+		}
+		if !IsXBrickMapBinary(data) {
+			t.Fatal("expected encoded data to start with the binary magic")
+		}
+		decoded, err := DecodeXBrickMap(data)
+		if err != nil {
+			t.Fatalf("DecodeXBrickMap failed: %v", err)
+		}
+		assertXBrickMapsEqual(t, source, decoded)
+		if decoded.StructureDirty || len(decoded.DirtyBricks) != 0 {
+			t.Fatal("expected decoded map to start with clean dirty tracking")
+		}
+		again, err := EncodeXBrickMap(decoded, opts)
+		if err != nil {
+			t.Fatalf("re-encode failed: %v", err)
+		}
+		if !bytes.Equal(data, again) {
+			t.Fatal("expected encoding to be deterministic across a round trip")
+		}
+	}
+}
+
+func TestXBrickMapBinaryKeepsUniformBricksCompact(t *testing.T) {
+	xbm := NewXBrickMap()
+	Cube(xbm, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{31, 31, 31}, 5)
+	data, err := EncodeXBrickMap(xbm, XBrickMapWriteOptions{DisableCompression: true})
+	if err != nil {
+		t.Fatalf("EncodeXBrickMap failed: %v", err)
+	}
+	// Header + one sector (coords, mask, 64 two-byte uniform bricks, crc).
+	if want := xbrickMapHeaderSize + 12 + 8 + 64*2 + 4; len(data) != want {
+		t.Fatalf("expected %d bytes for a solid sector, got %d", want, len(data))
+	}
+	decoded, err := DecodeXBrickMap(data)
+	if err != nil {
+		t.Fatalf("DecodeXBrickMap failed: %v", err)
+	}
+	brick := decoded.Sectors[[3]int{0, 0, 0}].GetBrick(1, 2, 3)
+	if brick == nil || brick.Flags&BrickFlagSolid == 0 || brick.AtlasOffset != 5 {
+		t.Fatalf("expected decoded brick to be flagged solid with palette 5, got %+v", brick)
+	}
+}
+
+func TestXBrickMapBinaryRejectsCorruptSector(t *testing.T) {
+	data, err := EncodeXBrickMap(buildCodecTestMap(), XBrickMapWriteOptions{DisableCompression: true})
+	if err != nil {
+		t.Fatalf("EncodeXBrickMap failed: %v", err)
+	}
+	data[xbrickMapHeaderSize+25] ^= 0xFF
+	if _, err := DecodeXBrickMap(data); err == nil {
+		t.Fatal("expected corrupted payload to fail decoding")
+	}
+}
+
+func TestXBrickMapReaderStreamsSectors(t *testing.T) {
+	source := buildCodecTestMap()
+	data, err := EncodeXBrickMap(source, XBrickMapWriteOptions{})
+	if err != nil {
+		t.Fatalf("EncodeXBrickMap failed: %v", err)
+	}
+	reader, err := NewXBrickMapReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewXBrickMapReader failed: %v", err)
+	}
+	if int(reader.Header.SectorCount) != len(source.Sectors) {
+		t.Fatalf("expected %d sectors in header, got %d", len(source.Sectors), reader.Header.SectorCount)
+	}
+	count := 0
+	for {
+		sector, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		if _, ok := source.Sectors[sector.Coords]; !ok {
+			t.Fatalf("unexpected streamed sector %v", sector.Coords)
+		}
+		count++
+	}
+	if count != len(source.Sectors) {
+		t.Fatalf("expected %d streamed sectors, got %d", len(source.Sectors), count)
+	}
+}
@@ -1,6 +1,9 @@
 package gekko
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"sort"
 
 	"github.com/gekko3d/gekko/content"
@@ -73,6 +76,39 @@ func XBrickMapFromVoxelObjectSnapshot(def *content.VoxelObjectSnapshotDef) *volu
 	return xbm
 }
 
+// SaveVoxelObjectSnapshotXBrickMap writes xbm as a binary voxel object
+// snapshot. LoadVoxelObjectSnapshotXBrickMap reads it back.
+func SaveVoxelObjectSnapshotXBrickMap(path string, xbm *volume.XBrickMap) error {
+	if xbm == nil {
+		xbm = volume.NewXBrickMap()
+	}
+	data, err := volume.EncodeXBrickMap(xbm, volume.XBrickMapWriteOptions{})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadVoxelObjectSnapshotXBrickMap loads a voxel object snapshot written in
+// either the binary XBrickMap format or the legacy JSON voxel list.
+func LoadVoxelObjectSnapshotXBrickMap(path string) (*volume.XBrickMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if volume.IsXBrickMapBinary(data) {
+		return volume.ReadXBrickMap(bytes.NewReader(data))
+	}
+	def, err := content.ParseVoxelObjectSnapshot(data)
+	if err != nil {
+		return nil, err
+	}
+	return XBrickMapFromVoxelObjectSnapshot(def), nil
+}
+
 func terrainChunkDefFromXBrickMap(terrainID string, coord content.TerrainChunkCoordDef, chunkSize int, voxelResolution float32, xbm *volume.XBrickMap) *content.TerrainChunkDef {
 	chunk := &content.TerrainChunkDef{
 		SchemaVersion:   content.CurrentTerrainChunkSchemaVersion,
@@ -0,0 +1,57 @@
+package gekko
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gekko3d/gekko/content"
+	"github.com/gekko3d/gekko/voxelrt/rt/volume"
+)
+
+func TestVoxelObjectSnapshotXBrickMapRoundTripUsesBinaryFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "delta_data", "object.gkvoxobj")
+	xbm := volume.NewXBrickMap()
+	for x := 0; x < 24; x++ {
+		for y := 0; y < 10; y++ {
+			xbm.SetVoxel(x, y, 3, uint8(1+x%4))
+		}
+	}
+
+	if err := SaveVoxelObjectSnapshotXBrickMap(path, xbm); err != nil {
+		t.Fatalf("SaveVoxelObjectSnapshotXBrickMap failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !volume.IsXBrickMapBinary(data) {
+		t.Fatal("expected voxel object snapshot to be written in the binary format")
+	}
+	loaded, err := LoadVoxelObjectSnapshotXBrickMap(path)
+	if err != nil {
+		t.Fatalf("LoadVoxelObjectSnapshotXBrickMap failed: %v", err)
+	}
+	if loaded.GetVoxelCount() != xbm.GetVoxelCount() {
+		t.Fatalf("expected %d voxels, got %d", xbm.GetVoxelCount(), loaded.GetVoxelCount())
+	}
+	if _, value := loaded.GetVoxel(7, 4, 3); value != 4 {
+		t.Fatalf("expected voxel value 4 at (7,4,3), got %d", value)
+	}
+}
+
+func TestLoadVoxelObjectSnapshotXBrickMapFallsBackToJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.gkvoxobj")
+	if err := content.SaveVoxelObjectSnapshot(path, &content.VoxelObjectSnapshotDef{
+		Voxels: []content.VoxelObjectVoxelDef{{X: 1, Y: 2, Z: 3, Value: 9}},
+	}); err != nil {
+		t.Fatalf("SaveVoxelObjectSnapshot failed: %v", err)
+	}
+	loaded, err := LoadVoxelObjectSnapshotXBrickMap(path)
+	if err != nil {
+		t.Fatalf("LoadVoxelObjectSnapshotXBrickMap failed: %v", err)
+	}
+	if _, value := loaded.GetVoxel(1, 2, 3); value != 9 || loaded.GetVoxelCount() != 1 {
+		t.Fatalf("expected single legacy voxel with value 9, got value %d count %d", value, loaded.GetVoxelCount())
+	}
+}